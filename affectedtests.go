@@ -0,0 +1,263 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+const (
+	affectedTestsToolName        = "affected_tests"
+	affectedTestsToolDescription = `Lists the tests of a Go module that call a changed function, directly or through other functions of the module, and the go test -run commands running only those tests. Pass the changed Go file as path, and symbol to limit the change to a function, a method as Type.Method or all methods of a type.
+
+Callers are found with a static call graph by Class Hierarchy Analysis, so calls through interfaces reach every implementation and the list may include tests that never reach the function at run time. Calls made by functions outside of the module, e.g. callbacks passed to the standard library, are not followed, except the subtests of t.Run which belong to their test.`
+)
+
+func AddAffectedTestsTool(mcpServer *server.MCPServer) {
+	handleAffectedTests := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		path, err := request.RequireString("path")
+		if err != nil {
+			return nil, err
+		}
+		symbol := request.GetString("symbol", "")
+
+		report, err := AffectedTests(ctx, path, symbol)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding affected tests: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		affectedTestsToolName,
+		mcp.WithDescription(affectedTestsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of the changed Go file"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Name of the changed function, Type.Method for a method or a type name for all of its methods. All functions of the file when empty"),
+		),
+	), handleAffectedTests)
+}
+
+// AffectedTestsReport lists the tests calling changed functions
+type AffectedTestsReport struct {
+	File string `json:"file"`
+	// Functions are the changed functions, methods prefixed by their receiver type name
+	Functions []string       `json:"functions"`
+	Tests     []AffectedTest `json:"tests,omitempty"`
+	// Commands are the go test commands running the tests, one per package directory
+	Commands []string `json:"commands,omitempty"`
+}
+
+// AffectedTest is a test, fuzz test or example calling a changed function
+type AffectedTest struct {
+	Name string `json:"name"`
+	// Dir is the package directory relative to the module root, e.g. ./store
+	Dir  string `json:"dir"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// AffectedTests finds the tests of the module containing the Go file at path that
+// transitively call the functions declared in it, or only those matching symbol
+func AffectedTests(ctx context.Context, path string, symbol string) (*AffectedTestsReport, error) {
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path must be an absolute path, got: %s", path)
+	}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() || !strings.HasSuffix(path, ".go") {
+		return nil, fmt.Errorf("path must be a Go file, got: %s", path)
+	}
+	module, err := readModule(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	initial, err := loadPackages(ctx, &packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Dir:   module.root,
+		Env:   packagesEnv(module.root),
+		Tests: true,
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	for _, pkg := range initial {
+		if len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors))
+		}
+	}
+	report := &AffectedTestsReport{File: module.relPath(path)}
+
+	// The changed functions are tracked by position, as test variants of a package
+	// declare them again
+	typeName, name, isMethod := strings.Cut(strings.TrimPrefix(symbol, "*"), ".")
+	changed := make(map[token.Position]bool)
+	for _, pkg := range initial {
+		for _, file := range pkg.Syntax {
+			if resolveSymlinks(pkg.Fset.Position(file.Package).Filename) != resolveSymlinks(path) {
+				continue
+			}
+			for _, decl := range file.Decls {
+				decl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				receiver := ""
+				if decl.Recv != nil {
+					receiver = receiverTypeName(decl.Recv.List[0].Type)
+				}
+				matches := symbol == "" ||
+					isMethod && receiver == typeName && decl.Name.Name == name ||
+					!isMethod && (decl.Name.Name == symbol || receiver == symbol)
+				obj, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+				if !matches || !ok || changed[pkg.Fset.Position(obj.Pos())] {
+					continue
+				}
+				changed[pkg.Fset.Position(obj.Pos())] = true
+				report.Functions = append(report.Functions, deadCodeName(obj))
+			}
+		}
+	}
+	if len(changed) == 0 {
+		if symbol != "" {
+			return nil, classifyErrorf(ErrSymbolNotFound, "no function or method '%s' found in %s", symbol, path)
+		}
+		return nil, fmt.Errorf("no functions declared in %s", path)
+	}
+
+	prog, _ := ssautil.AllPackages(initial, ssa.InstantiateGenerics)
+	buildProgram(prog)
+	graph := cha.CallGraph(prog)
+
+	// Callers are followed up from the changed functions while they are declared in the
+	// module, or are wrappers the compiler generates for its methods
+	inModule := func(fn *ssa.Function) bool {
+		if fn.Synthetic != "" && fn.Origin() == nil {
+			return true
+		}
+		if origin := fn.Origin(); origin != nil {
+			fn = origin
+		}
+		if fn.Pkg == nil {
+			return false
+		}
+		pkgPath := fn.Pkg.Pkg.Path()
+		return !strings.HasSuffix(pkgPath, ".test") &&
+			(pkgPath == module.path || strings.HasPrefix(pkgPath, module.path+"/"))
+	}
+	var queue []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		origin := fn
+		if fn.Origin() != nil {
+			origin = fn.Origin()
+		}
+		if origin.Pos().IsValid() && changed[prog.Fset.Position(origin.Pos())] {
+			queue = append(queue, fn)
+		}
+	}
+	visited := make(map[*ssa.Function]bool)
+	found := make(map[token.Position]bool)
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		if visited[fn] {
+			continue
+		}
+		visited[fn] = true
+
+		if position := prog.Fset.Position(fn.Pos()); runByGoTest(fn, position) && !found[position] {
+			found[position] = true
+			dir := filepath.ToSlash(filepath.Dir(module.relPath(position.Filename)))
+			if dir != "." {
+				dir = "./" + dir
+			}
+			report.Tests = append(report.Tests, AffectedTest{
+				Name: fn.Name(),
+				Dir:  dir,
+				File: module.relPath(position.Filename),
+				Line: position.Line,
+			})
+		}
+		// Closures are called by their function, e.g. subtests passed to t.Run
+		if fn.Parent() != nil {
+			queue = append(queue, fn.Parent())
+		}
+		if node := graph.Nodes[fn]; node != nil {
+			for _, edge := range node.In {
+				if inModule(edge.Caller.Func) {
+					queue = append(queue, edge.Caller.Func)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(report.Tests, func(a, b AffectedTest) int {
+		if a.Dir != b.Dir {
+			return strings.Compare(a.Dir, b.Dir)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	for i := 0; i < len(report.Tests); {
+		dir := report.Tests[i].Dir
+		var names []string
+		for ; i < len(report.Tests) && report.Tests[i].Dir == dir; i++ {
+			names = append(names, report.Tests[i].Name)
+		}
+		pattern := "^" + names[0] + "$"
+		if len(names) > 1 {
+			pattern = "^(" + strings.Join(names, "|") + ")$"
+		}
+		report.Commands = append(report.Commands, fmt.Sprintf("go test -run '%s' %s", pattern, dir))
+	}
+	return report, nil
+}
+
+// runByGoTest reports whether a function is a test, fuzz test or example run by go test
+func runByGoTest(fn *ssa.Function, position token.Position) bool {
+	if fn.Name() == "TestMain" || fn.Parent() != nil || fn.Signature.Recv() != nil || !strings.HasSuffix(position.Filename, "_test.go") {
+		return false
+	}
+	for prefix, params := range map[string]int{"Test": 1, "Fuzz": 1, "Example": 0} {
+		if fn.Signature.Params().Len() == params && isGoTestName(fn.Name(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (report *AffectedTestsReport) String() string {
+	changed := strings.Join(report.Functions, ", ")
+	if len(report.Tests) == 0 {
+		return fmt.Sprintf("No tests call %s (%s)", changed, report.File)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d tests call %s (%s):\n", len(report.Tests), changed, report.File)
+	for _, test := range report.Tests {
+		fmt.Fprintf(&b, "  %s (%s:%d)\n", test.Name, test.File, test.Line)
+	}
+	b.WriteString("\nRun them with:\n")
+	for _, command := range report.Commands {
+		fmt.Fprintf(&b, "  %s\n", command)
+	}
+	return b.String()
+}
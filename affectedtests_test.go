@@ -0,0 +1,155 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAffectedTests(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/kv", "", "go 1.22", ""},
+		"store/store.go": {
+			"package store", // 1
+			"",
+			"type Store interface{ Get(string) string }", // 3
+			"",
+			"type Memory struct{ values map[string]string }", // 5
+			"",
+			"func (m *Memory) Get(key string) string { return normalize(m.values[key]) }", // 7
+			"",
+			"func (m *Memory) Put(key, value string) { m.values[key] = value }", // 9
+			"",
+			"func normalize(value string) string { return value }", // 11
+			"",
+		},
+		"store/store_test.go": {
+			"package store", // 1
+			"",
+			"import (", // 3
+			"	\"fmt\"",
+			"	\"testing\"",
+			")",
+			"",
+			"func TestGet(t *testing.T) {", // 8
+			"	t.Run(\"missing\", func(t *testing.T) {",
+			"		(&Memory{}).Get(\"a\")",
+			"	})",
+			"}",
+			"",
+			"func TestPut(t *testing.T) { (&Memory{values: map[string]string{}}).Put(\"a\", \"b\") }", // 14
+			"",
+			"func ExampleMemory_Get() { fmt.Println((&Memory{}).Get(\"a\")) }", // 16
+			"",
+			"func Testhelper(t *testing.T) { normalize(\"\") }", // 18
+			"",
+		},
+		"api/api.go": {
+			"package api", // 1
+			"",
+			"import \"example.com/kv/store\"", // 3
+			"",
+			"func Lookup(s store.Store, key string) string { return s.Get(key) }", // 5
+			"",
+		},
+		"api/api_test.go": {
+			"package api_test", // 1
+			"",
+			"import (", // 3
+			"	\"testing\"",
+			"",
+			"	\"example.com/kv/api\"",
+			"	\"example.com/kv/store\"",
+			")",
+			"",
+			"func TestLookup(t *testing.T) { api.Lookup(&store.Memory{}, \"a\") }", // 10
+			"",
+		},
+		"root_test.go": {
+			"package kv", // 1
+			"",
+			"import \"testing\"", // 3
+			"",
+			"func TestNothing(t *testing.T) {}", // 5
+			"",
+		},
+		"kv.go": {"package kv", ""},
+	})
+	storeFile := filepath.Join(workspace, "store", "store.go")
+
+	t.Run("transitive callers", func(t *testing.T) {
+		t.Parallel()
+		report, err := AffectedTests(context.Background(), storeFile, "normalize")
+		if err != nil {
+			t.Fatalf("Failed to find affected tests: %v", err)
+		}
+		// TestLookup calls Get through the Store interface, and Testhelper is no test
+		expected := strings.Join([]string{
+			"3 tests call normalize (store/store.go):",
+			"  TestLookup (api/api_test.go:10)",
+			"  ExampleMemory_Get (store/store_test.go:16)",
+			"  TestGet (store/store_test.go:8)",
+			"",
+			"Run them with:",
+			"  go test -run '^TestLookup$' ./api",
+			"  go test -run '^(ExampleMemory_Get|TestGet)$' ./store",
+			"",
+		}, "\n")
+		if report.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, report)
+		}
+	})
+
+	t.Run("methods of a type", func(t *testing.T) {
+		t.Parallel()
+		report, err := AffectedTests(context.Background(), storeFile, "Memory.Put")
+		if err != nil {
+			t.Fatalf("Failed to find affected tests: %v", err)
+		}
+		if len(report.Tests) != 1 || report.Tests[0].Name != "TestPut" ||
+			len(report.Commands) != 1 || report.Commands[0] != "go test -run '^TestPut$' ./store" {
+			t.Errorf("Expected only TestPut, got:\n%s", report)
+		}
+
+		report, err = AffectedTests(context.Background(), storeFile, "Memory")
+		if err != nil {
+			t.Fatalf("Failed to find affected tests: %v", err)
+		}
+		if strings.Join(report.Functions, ", ") != "Memory.Get, Memory.Put" || len(report.Tests) != 4 {
+			t.Errorf("Expected the tests of both methods, got:\n%s", report)
+		}
+	})
+
+	t.Run("untested file", func(t *testing.T) {
+		t.Parallel()
+		report, err := AffectedTests(context.Background(), filepath.Join(workspace, "root_test.go"), "")
+		if err != nil {
+			t.Fatalf("Failed to find affected tests: %v", err)
+		}
+		// A changed test is affected itself, and run in the root package
+		if len(report.Commands) != 1 || report.Commands[0] != "go test -run '^TestNothing$' ." {
+			t.Errorf("Expected the changed test, got:\n%s", report)
+		}
+
+		if _, err := AffectedTests(context.Background(), filepath.Join(workspace, "kv.go"), ""); err == nil {
+			t.Error("Expected an error for a file without functions")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		if _, err := AffectedTests(context.Background(), storeFile, "Missing"); !errors.Is(err, ErrSymbolNotFound) {
+			t.Errorf("Expected a symbol not found error, got: %v", err)
+		}
+		if _, err := AffectedTests(context.Background(), "store/store.go", ""); err == nil {
+			t.Error("Expected an error for a relative path")
+		}
+		if _, err := AffectedTests(context.Background(), filepath.Join(workspace, "store"), ""); err == nil {
+			t.Error("Expected an error for a directory")
+		}
+	})
+}
@@ -0,0 +1,354 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/appends"
+	"golang.org/x/tools/go/analysis/passes/assign"
+	"golang.org/x/tools/go/analysis/passes/atomic"
+	"golang.org/x/tools/go/analysis/passes/bools"
+	"golang.org/x/tools/go/analysis/passes/composite"
+	"golang.org/x/tools/go/analysis/passes/copylock"
+	"golang.org/x/tools/go/analysis/passes/deepequalerrors"
+	"golang.org/x/tools/go/analysis/passes/defers"
+	"golang.org/x/tools/go/analysis/passes/directive"
+	"golang.org/x/tools/go/analysis/passes/errorsas"
+	"golang.org/x/tools/go/analysis/passes/fieldalignment"
+	"golang.org/x/tools/go/analysis/passes/httpresponse"
+	"golang.org/x/tools/go/analysis/passes/ifaceassert"
+	"golang.org/x/tools/go/analysis/passes/loopclosure"
+	"golang.org/x/tools/go/analysis/passes/lostcancel"
+	"golang.org/x/tools/go/analysis/passes/nilfunc"
+	"golang.org/x/tools/go/analysis/passes/nilness"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/shift"
+	"golang.org/x/tools/go/analysis/passes/sigchanyzer"
+	"golang.org/x/tools/go/analysis/passes/slog"
+	"golang.org/x/tools/go/analysis/passes/sortslice"
+	"golang.org/x/tools/go/analysis/passes/stdmethods"
+	"golang.org/x/tools/go/analysis/passes/stringintconv"
+	"golang.org/x/tools/go/analysis/passes/structtag"
+	"golang.org/x/tools/go/analysis/passes/testinggoroutine"
+	"golang.org/x/tools/go/analysis/passes/tests"
+	"golang.org/x/tools/go/analysis/passes/timeformat"
+	"golang.org/x/tools/go/analysis/passes/unmarshal"
+	"golang.org/x/tools/go/analysis/passes/unreachable"
+	"golang.org/x/tools/go/analysis/passes/unsafeptr"
+	"golang.org/x/tools/go/analysis/passes/unusedresult"
+	"golang.org/x/tools/go/analysis/passes/unusedwrite"
+	"golang.org/x/tools/go/analysis/passes/waitgroup"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	analyzeToolName        = "analyze"
+	analyzeToolDescription = `Runs go/analysis analyzers, the checks of go vet and more, on a Go package and reports their diagnostics with positions and the fixes they suggest, with the edits of each fix.
+
+By default the checks of go vet run together with nilness and unusedwrite. Pass analyzers to choose the analyzers by name instead, "all" selecting every available one. Available analyzers: ` + "appends, assign, atomic, bools, composites, copylocks, deepequalerrors, defers, directive, errorsas, fieldalignment, httpresponse, ifaceassert, loopclosure, lostcancel, nilfunc, nilness, printf, shadow, shift, sigchanyzer, slog, sortslice, stdmethods, stringintconv, structtag, testinggoroutine, tests, timeformat, unmarshal, unreachable, unsafeptr, unusedresult, unusedwrite, waitgroup."
+)
+
+// availableAnalyzers are the analyzers the analyze tool can run, by name
+var availableAnalyzers = []*analysis.Analyzer{
+	appends.Analyzer,
+	assign.Analyzer,
+	atomic.Analyzer,
+	bools.Analyzer,
+	composite.Analyzer,
+	copylock.Analyzer,
+	deepequalerrors.Analyzer,
+	defers.Analyzer,
+	directive.Analyzer,
+	errorsas.Analyzer,
+	fieldalignment.Analyzer,
+	httpresponse.Analyzer,
+	ifaceassert.Analyzer,
+	loopclosure.Analyzer,
+	lostcancel.Analyzer,
+	nilfunc.Analyzer,
+	nilness.Analyzer,
+	printf.Analyzer,
+	shadow.Analyzer,
+	shift.Analyzer,
+	sigchanyzer.Analyzer,
+	slog.Analyzer,
+	sortslice.Analyzer,
+	stdmethods.Analyzer,
+	stringintconv.Analyzer,
+	structtag.Analyzer,
+	testinggoroutine.Analyzer,
+	tests.Analyzer,
+	timeformat.Analyzer,
+	unmarshal.Analyzer,
+	unreachable.Analyzer,
+	unsafeptr.Analyzer,
+	unusedresult.Analyzer,
+	unusedwrite.Analyzer,
+	waitgroup.Analyzer,
+}
+
+// optInAnalyzers only run when requested by name, as they report many false positives or
+// style issues
+var optInAnalyzers = []string{fieldalignment.Analyzer.Name, shadow.Analyzer.Name}
+
+func AddAnalyzeTool(mcpServer *server.MCPServer) {
+	handleAnalyze := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		path, err := request.RequireString("path")
+		if err != nil {
+			return nil, err
+		}
+		analyzers := request.GetStringSlice("analyzers", nil)
+		includeTests := request.GetBool("include_tests", false)
+
+		report, err := Analyze(ctx, path, analyzers, includeTests)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error analyzing package: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		analyzeToolName,
+		mcp.WithDescription(analyzeToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of the package directory or of a Go file of the package"),
+			mcp.Required(),
+		),
+		mcp.WithArray("analyzers",
+			mcp.Description(`Names of the analyzers to run, or "all", instead of the default ones`),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("include_tests",
+			mcp.Description("Also analyze the test files of the package"),
+		),
+	), handleAnalyze)
+}
+
+// AnalysisReport holds the diagnostics of analyzers run on a package
+type AnalysisReport struct {
+	Package     string               `json:"package"`
+	Analyzers   []string             `json:"analyzers"`
+	Diagnostics []AnalysisDiagnostic `json:"diagnostics,omitempty"`
+	// Errors lists the analyzers that failed
+	Errors []string `json:"errors,omitempty"`
+}
+
+// AnalysisDiagnostic is a problem reported by an analyzer
+type AnalysisDiagnostic struct {
+	Analyzer string         `json:"analyzer"`
+	File     string         `json:"file"`
+	Line     int            `json:"line"`
+	Column   int            `json:"column"`
+	Message  string         `json:"message"`
+	Fixes    []SuggestedFix `json:"fixes,omitempty"`
+}
+
+// SuggestedFix is a change an analyzer suggests to fix a diagnostic
+type SuggestedFix struct {
+	Message string    `json:"message"`
+	Edits   []FixEdit `json:"edits"`
+}
+
+// FixEdit replaces the text between two positions, inserting when they are equal
+type FixEdit struct {
+	File        string `json:"file"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+	NewText     string `json:"new_text"`
+	// start and end are the byte offsets of the replaced text, used to apply the edit
+	start, end int
+}
+
+// Analyze runs the analyzers named analyzers, or the default ones when empty, on the
+// package at path, a package directory or a Go file of the package
+func Analyze(ctx context.Context, path string, analyzers []string, includeTests bool) (*AnalysisReport, error) {
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path must be an absolute path, got: %s", path)
+	}
+	selected, err := selectAnalyzers(analyzers)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access %s: %w", path, err)
+	}
+	dir, pattern := path, "."
+	if !info.IsDir() {
+		dir, pattern = filepath.Dir(path), "file="+path
+	}
+	// Analyzers with facts, like printf, run on the dependencies too, which needs their syntax
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Dir:   dir,
+		Env:   packagesEnv(dir),
+		Tests: includeTests,
+	}, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the package of %s: %w", path, err)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no package found for %s", path)
+	}
+	if includeTests {
+		pkgs = testVariants(pkgs)
+	}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+		}
+	}
+
+	graph, err := checker.Analyze(selected, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+	report := &AnalysisReport{Package: pkgs[0].PkgPath}
+	for _, analyzer := range selected {
+		report.Analyzers = append(report.Analyzers, analyzer.Name)
+	}
+	seen := make(map[string]bool)
+	for _, action := range graph.Roots {
+		if action.Err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", action.Analyzer.Name, action.Err))
+			continue
+		}
+		fset := action.Package.Fset
+		for _, diagnostic := range action.Diagnostics {
+			position := fset.Position(diagnostic.Pos)
+			result := AnalysisDiagnostic{
+				Analyzer: action.Analyzer.Name,
+				File:     position.Filename,
+				Line:     position.Line,
+				Column:   position.Column,
+				Message:  diagnostic.Message,
+			}
+			// Test variants report the diagnostics of the package files again
+			key := fmt.Sprintf("%s %s:%d:%d %s", result.Analyzer, result.File, result.Line, result.Column, result.Message)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			for _, fix := range diagnostic.SuggestedFixes {
+				suggested := SuggestedFix{Message: fix.Message}
+				for _, edit := range fix.TextEdits {
+					start, end := fset.Position(edit.Pos), fset.Position(edit.End)
+					if !edit.End.IsValid() {
+						end = start
+					}
+					suggested.Edits = append(suggested.Edits, FixEdit{
+						File:        start.Filename,
+						StartLine:   start.Line,
+						StartColumn: start.Column,
+						EndLine:     end.Line,
+						EndColumn:   end.Column,
+						NewText:     string(edit.NewText),
+						start:       start.Offset,
+						end:         end.Offset,
+					})
+				}
+				result.Fixes = append(result.Fixes, suggested)
+			}
+			report.Diagnostics = append(report.Diagnostics, result)
+		}
+	}
+	sort.SliceStable(report.Diagnostics, func(i, j int) bool {
+		a, b := report.Diagnostics[i], report.Diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return report, nil
+}
+
+// selectAnalyzers returns the analyzers with the names, "all" selecting all of them, or the
+// default ones when names is empty
+func selectAnalyzers(names []string) ([]*analysis.Analyzer, error) {
+	if len(names) == 0 {
+		return slices.DeleteFunc(slices.Clone(availableAnalyzers), func(analyzer *analysis.Analyzer) bool {
+			return slices.Contains(optInAnalyzers, analyzer.Name)
+		}), nil
+	}
+	if slices.Contains(names, "all") {
+		return availableAnalyzers, nil
+	}
+	var selected []*analysis.Analyzer
+	for _, name := range names {
+		index := slices.IndexFunc(availableAnalyzers, func(analyzer *analysis.Analyzer) bool { return analyzer.Name == name })
+		if index < 0 {
+			return nil, fmt.Errorf("unknown analyzer %q", name)
+		}
+		if !slices.Contains(selected, availableAnalyzers[index]) {
+			selected = append(selected, availableAnalyzers[index])
+		}
+	}
+	return selected, nil
+}
+
+// testVariants keeps the packages compiled with their tests, which include all files of the
+// package and its external test package, dropping the variants without tests and test mains
+func testVariants(pkgs []*packages.Package) []*packages.Package {
+	var variants []*packages.Package
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		// Test variants have IDs like "path [path.test]"
+		if strings.Contains(pkg.ID, " [") || !slices.ContainsFunc(pkgs, func(other *packages.Package) bool {
+			return other.PkgPath == pkg.PkgPath && strings.Contains(other.ID, " [")
+		}) {
+			variants = append(variants, pkg)
+		}
+	}
+	return variants
+}
+
+// String formats the report as human and model readable text
+func (report *AnalysisReport) String() string {
+	var b strings.Builder
+	if len(report.Diagnostics) == 0 {
+		fmt.Fprintf(&b, "No diagnostics in %s (analyzers: %s)\n", report.Package, strings.Join(report.Analyzers, ", "))
+	} else {
+		fmt.Fprintf(&b, "Diagnostics in %s (%d):\n", report.Package, len(report.Diagnostics))
+	}
+	for _, diagnostic := range report.Diagnostics {
+		fmt.Fprintf(&b, "  %s:%d:%d [%s] %s\n", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Analyzer, diagnostic.Message)
+		for _, fix := range diagnostic.Fixes {
+			fmt.Fprintf(&b, "    fix: %s\n", fix.Message)
+			for _, edit := range fix.Edits {
+				fmt.Fprintf(
+					&b, "      %s:%d:%d-%d:%d → %s\n",
+					filepath.Base(edit.File), edit.StartLine, edit.StartColumn, edit.EndLine, edit.EndColumn, strconv.Quote(edit.NewText),
+				)
+			}
+		}
+	}
+	if len(report.Errors) > 0 {
+		b.WriteString("\nFailed analyzers:\n")
+		for _, failure := range report.Errors {
+			fmt.Fprintf(&b, "  %s\n", failure)
+		}
+	}
+	return b.String()
+}
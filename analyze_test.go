@@ -0,0 +1,108 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a package with problems found by analyzers
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",   // 1
+				"",               // 2
+				"import \"fmt\"", // 3
+				"",               // 4
+				"func describe(n int, err error) string {", // 5
+				"\tfmt.Printf(\"%d\\n\", \"n\")",           // 6
+				"\tif err == nil {",                        // 7
+				"\t\treturn err.Error()",                   // 8
+				"\t}",                                      // 9
+				"\tif n > 0 {",                             // 10
+				"\t\terr := fmt.Errorf(\"n\")",             // 11
+				"\t\t_ = err",                              // 12
+				"\t}",                                      // 13
+				"\treturn string(n) + err.Error()",         // 14
+				"}",                                        // 15
+				"",                                         // 16
+				"func main() { describe(1, nil) }",         // 17
+				"",                                         // 18
+			},
+		})
+	}
+
+	t.Run("default analyzers", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+		file := filepath.Join(dir, "main.go")
+
+		report, err := Analyze(context.Background(), dir, nil, false)
+		if err != nil {
+			t.Fatalf("Failed to analyze package: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Diagnostics in example.com/app (3):",
+			"  " + file + ":6:14 [printf] fmt.Printf format %d has arg \"n\" of wrong type string",
+			"  " + file + ":8:19 [nilness] nil dereference in dynamic method call",
+			"  " + file + ":14:9 [stringintconv] conversion from int to string yields a string of one rune, not a string of digits",
+			"    fix: Format the number as a decimal",
+			"      main.go:14:9-14:15 → \"fmt.Sprint\"",
+			"    fix: Convert a single rune to a string",
+			"      main.go:14:16-14:16 → \"rune(\"",
+			"      main.go:14:17-14:17 → \")\"",
+			"",
+		}, "\n")
+		if got := report.String(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("selected analyzers", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := Analyze(context.Background(), filepath.Join(dir, "main.go"), []string{"shadow"}, false)
+		if err != nil {
+			t.Fatalf("Failed to analyze package: %v", err)
+		}
+		if len(report.Diagnostics) != 1 || !strings.Contains(report.Diagnostics[0].Message, `declaration of "err" shadows declaration`) {
+			t.Errorf("Expected the shadowed err, got %+v", report.Diagnostics)
+		}
+
+		if _, err := Analyze(context.Background(), dir, []string{"missing"}, false); err == nil || !strings.Contains(err.Error(), `unknown analyzer "missing"`) {
+			t.Errorf("Expected an unknown analyzer error, got %v", err)
+		}
+	})
+
+	t.Run("analyze tool", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      analyzeToolName,
+				"arguments": map[string]any{"path": dir, "analyzers": []string{"printf"}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "[printf] fmt.Printf format %d") {
+			t.Errorf("Expected the printf diagnostic, got: %s", text)
+		}
+	})
+}
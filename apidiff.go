@@ -0,0 +1,417 @@
+package go_mcp_tools
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	apiDiffToolName        = "apidiff"
+	apiDiffToolDescription = `Compares the exported API of Go packages between two versions and classifies every change as breaking or compatible, for release reviews and choosing the next semantic version.
+
+The working tree is compared with a git ref, e.g. "v1.4.0" or "main", or with a published module version given as old_version. Two published versions are compared by giving new_version as well.
+
+Removed and changed functions, methods, types, fields, consts and vars are breaking, as are methods added to interfaces that other packages can implement. Added API is compatible.`
+)
+
+// APIDiffOptions selects the versions of the packages to compare
+type APIDiffOptions struct {
+	// WorkspaceDir is the directory of the working tree, in the module of the packages
+	// or, when comparing published versions, any directory
+	WorkspaceDir string
+	// Package is the import path or relative directory of the packages, patterns like
+	// ./... compare several packages
+	Package string
+	// Ref is a git ref the working tree is compared with
+	Ref string
+	// OldVersion is a published version of the module the working tree, or NewVersion
+	// when set, is compared with. Package must be an import path.
+	OldVersion string
+	NewVersion string
+}
+
+// APIDiff lists the changes of the exported API between two versions
+type APIDiff struct {
+	Old        string      `json:"old"`
+	New        string      `json:"new"`
+	Breaking   []APIChange `json:"breaking,omitempty"`
+	Compatible []APIChange `json:"compatible,omitempty"`
+}
+
+// APIChange is an added, removed or changed feature as listed by ExportedAPI. Old is
+// empty for added and New for removed features.
+type APIChange struct {
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+func AddAPIDiffTool(mcpServer *server.MCPServer) {
+	handleAPIDiff := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		options := APIDiffOptions{}
+		var err error
+		options.WorkspaceDir, err = request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		options.Package, err = request.RequireString("package")
+		if err != nil {
+			return nil, err
+		}
+		options.Ref = request.GetString("ref", "")
+		options.OldVersion = request.GetString("old_version", "")
+		options.NewVersion = request.GetString("new_version", "")
+
+		diff, err := DiffAPIVersions(ctx, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error comparing API: %v", err)), nil
+		}
+		return mcp.NewToolResultText(diff.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		apiDiffToolName,
+		mcp.WithDescription(apiDiffToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of the directory the package is resolved from in the working tree"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Import path or relative directory of the package, e.g. ./pkg/client. Patterns like ./... compare several packages."),
+			mcp.Required(),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Git ref to compare the working tree with, e.g. a release tag"),
+		),
+		mcp.WithString("old_version",
+			mcp.Description("Published module version to compare with instead of a git ref, e.g. v1.4.0. Requires package to be an import path."),
+		),
+		mcp.WithString("new_version",
+			mcp.Description("Published module version compared with old_version instead of the working tree"),
+		),
+	), handleAPIDiff)
+}
+
+// DiffAPIVersions lists the exported API of the packages at both versions selected by
+// the options and compares them
+func DiffAPIVersions(ctx context.Context, options APIDiffOptions) (*APIDiff, error) {
+	if !filepath.IsAbs(options.WorkspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", options.WorkspaceDir)
+	}
+	if (options.Ref == "") == (options.OldVersion == "") {
+		return nil, fmt.Errorf("either ref or old_version must be given")
+	}
+	if options.NewVersion != "" && options.OldVersion == "" {
+		return nil, fmt.Errorf("new_version requires old_version")
+	}
+
+	var oldAPI, newAPI *APISurface
+	var err error
+	oldName, newName := options.Ref, "working tree"
+	if options.Ref != "" {
+		oldAPI, err = exportedAPIAtRef(ctx, options.WorkspaceDir, options.Package, options.Ref)
+	} else {
+		oldName = options.OldVersion
+		oldAPI, err = exportedAPIAtVersion(ctx, options.Package, options.OldVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if options.NewVersion != "" {
+		newName = options.NewVersion
+		newAPI, err = exportedAPIAtVersion(ctx, options.Package, options.NewVersion)
+	} else {
+		newAPI, err = ExportedAPI(ctx, options.WorkspaceDir, options.Package)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := DiffAPI(oldAPI, newAPI)
+	diff.Old, diff.New = oldName, newName
+	return diff, nil
+}
+
+// exportedAPIAtRef lists the exported API of the packages in a copy of the git
+// repository of workspaceDir at ref
+func exportedAPIAtRef(ctx context.Context, workspaceDir string, pattern string, ref string) (*APISurface, error) {
+	var surface *APISurface
+	err := atGitRef(ctx, workspaceDir, ref, func(dir string) error {
+		var err error
+		surface, err = ExportedAPI(ctx, dir, pattern)
+		return err
+	})
+	return surface, err
+}
+
+// atGitRef calls fn with the directory matching workspaceDir in a temporary copy of its
+// git repository at ref
+func atGitRef(ctx context.Context, workspaceDir string, ref string, fn func(dir string) error) error {
+	repoRoot, err := gitRepoRoot(ctx, workspaceDir)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(resolveSymlinks(repoRoot), resolveSymlinks(workspaceDir))
+	if err != nil {
+		return err
+	}
+	archive, err := runGit(ctx, repoRoot, "archive", "--format=tar", ref)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "go-mcp-tools-apidiff-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := extractTar(strings.NewReader(archive), dir); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", ref, err)
+	}
+	if err := fn(filepath.Join(dir, rel)); err != nil {
+		return fmt.Errorf("at %s: %w", ref, err)
+	}
+	return nil
+}
+
+// exportedAPIAtVersion downloads the module providing the packages at version and lists
+// their exported API
+func exportedAPIAtVersion(ctx context.Context, pattern string, version string) (*APISurface, error) {
+	if strings.HasPrefix(pattern, ".") || filepath.IsAbs(pattern) {
+		return nil, fmt.Errorf("package must be an import path to compare module versions, got: %s", pattern)
+	}
+	dir, err := downloadModule(ctx, pattern, version)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	surface, err := ExportedAPI(ctx, dir, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("at %s: %w", version, err)
+	}
+	return surface, nil
+}
+
+// extractTar writes the directories, files and symlinks of a tar archive into dir
+func extractTar(r io.Reader, dir string) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %s is outside the archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0755)
+		case tar.TypeReg:
+			err = writeTarFile(reader, path, header.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+				err = os.Symlink(header.Linkname, path)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeTarFile writes the current entry of a tar archive to path
+func writeTarFile(reader *tar.Reader, path string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// DiffAPI compares two listings of ExportedAPI. Features are matched by their name, so
+// a changed signature is reported as one change instead of a removal and an addition.
+func DiffAPI(oldAPI, newAPI *APISurface) *APIDiff {
+	group := func(features []string) (map[string][]string, map[string]bool) {
+		byKey := make(map[string][]string)
+		sealed := make(map[string]bool)
+		for _, feature := range features {
+			key := apiFeatureKey(feature)
+			if strings.HasSuffix(feature, " interface, unexported methods") {
+				sealed[strings.TrimSuffix(key, ", unexported methods")] = true
+			}
+			byKey[key] = append(byKey[key], feature)
+		}
+		return byKey, sealed
+	}
+	oldFeatures, oldSealed := group(oldAPI.Features)
+	newFeatures, newSealed := group(newAPI.Features)
+
+	var keys []string
+	for key := range oldFeatures {
+		keys = append(keys, key)
+	}
+	for key := range newFeatures {
+		if _, ok := oldFeatures[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	diff := &APIDiff{}
+	for _, key := range keys {
+		oldList, newList := oldFeatures[key], newFeatures[key]
+		if slices.Equal(oldList, newList) {
+			continue
+		}
+		prefix := apiFeaturePackage(key)
+		typeKey, member, isMember := strings.Cut(strings.TrimPrefix(key, prefix), ", ")
+		typeKey = prefix + typeKey
+		isInterface := strings.HasSuffix(typeKey, " interface")
+		switch {
+		case isInterface && !isMember:
+			// The method list of an interface is compared by its methods instead
+		case isInterface && member == "unexported methods":
+			// Sealing an interface breaks its implementations in other packages,
+			// unsealing it does not break anything
+			if len(oldList) == 0 {
+				diff.Breaking = append(diff.Breaking, APIChange{New: newList[0]})
+			} else {
+				diff.Compatible = append(diff.Compatible, APIChange{Old: oldList[0]})
+			}
+		case len(oldList) == 0:
+			change := APIChange{New: strings.Join(newList, "\n")}
+			// Methods added to an existing interface break its implementations in other
+			// packages, unless the interface cannot be implemented there
+			if isInterface && oldFeatures[typeKey] != nil && !oldSealed[typeKey] && !newSealed[typeKey] {
+				diff.Breaking = append(diff.Breaking, change)
+			} else {
+				diff.Compatible = append(diff.Compatible, change)
+			}
+		case len(newList) == 0:
+			diff.Breaking = append(diff.Breaking, APIChange{Old: strings.Join(oldList, "\n")})
+		default:
+			diff.Breaking = append(diff.Breaking, APIChange{
+				Old: strings.Join(oldList, "\n"),
+				New: strings.Join(newList, "\n"),
+			})
+		}
+	}
+	return diff
+}
+
+// apiFeaturePackage returns the "pkg <import path>, " prefix of a feature
+func apiFeaturePackage(feature string) string {
+	if prefix, _, ok := strings.Cut(feature, ", "); ok {
+		return prefix + ", "
+	}
+	return ""
+}
+
+// apiFeatureKey returns the part of a feature naming what it declares, without its type
+// or signature, e.g. "pkg p, method (*DB) Get" or "pkg p, type DB struct, Timeout".
+// Type declarations are keyed by name and kind, "pkg p, type DB struct", so that the
+// members of structs and interfaces are keyed below them.
+func apiFeatureKey(feature string) string {
+	prefix := apiFeaturePackage(feature)
+	rest := strings.TrimPrefix(feature, prefix)
+	kind, rest, _ := strings.Cut(rest, " ")
+	name := func(s string) string {
+		if i := strings.IndexAny(s, " ([="); i >= 0 {
+			return s[:i]
+		}
+		return s
+	}
+
+	switch kind {
+	case "method":
+		receiver, signature, _ := strings.Cut(rest, ") ")
+		return prefix + "method " + receiver + ") " + name(signature)
+	case "type":
+		typeName := name(rest)
+		rest = strings.TrimPrefix(rest, typeName)
+		// Type parameters are part of the declaration, not of the name
+		if strings.HasPrefix(rest, "[") {
+			depth := 0
+			for i, r := range rest {
+				if r == '[' {
+					depth++
+				} else if r == ']' {
+					depth--
+				}
+				if depth == 0 {
+					rest = rest[i+1:]
+					break
+				}
+			}
+		}
+		rest = strings.TrimPrefix(rest, " ")
+		for _, composite := range []string{"struct", "interface"} {
+			if rest == composite || strings.HasPrefix(rest, composite+" {") {
+				return prefix + "type " + typeName + " " + composite
+			}
+			if member, ok := strings.CutPrefix(rest, composite+", "); ok {
+				if !strings.HasPrefix(member, "embedded ") && member != "unexported methods" {
+					member = name(member)
+				}
+				return prefix + "type " + typeName + " " + composite + ", " + member
+			}
+		}
+		return prefix + "type " + typeName
+	}
+	return prefix + kind + " " + name(rest)
+}
+
+func (diff *APIDiff) String() string {
+	if len(diff.Breaking) == 0 && len(diff.Compatible) == 0 {
+		return fmt.Sprintf("No API changes from %s to %s", diff.Old, diff.New)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "API changes from %s to %s: %d breaking, %d compatible\n", diff.Old, diff.New, len(diff.Breaking), len(diff.Compatible))
+	for _, section := range []struct {
+		title   string
+		changes []APIChange
+	}{
+		{"Breaking", diff.Breaking},
+		{"Compatible", diff.Compatible},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		for _, change := range section.changes {
+			switch {
+			case change.Old == "":
+				fmt.Fprintf(&b, "+ %s\n", strings.ReplaceAll(change.New, "\n", "\n+ "))
+			case change.New == "":
+				fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(change.Old, "\n", "\n- "))
+			default:
+				fmt.Fprintf(&b, "- %s\n+ %s\n", strings.ReplaceAll(change.Old, "\n", "\n- "), strings.ReplaceAll(change.New, "\n", "\n+ "))
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,175 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAPIDiff(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to build a listing of features of package p
+	surface := func(features ...string) *APISurface {
+		listing := &APISurface{Packages: []string{"p"}}
+		for _, feature := range features {
+			listing.Features = append(listing.Features, "pkg p, "+feature)
+		}
+		return listing
+	}
+
+	t.Run("classification", func(t *testing.T) {
+		t.Parallel()
+		oldAPI := surface(
+			"const Limit int = 1",
+			"func Open(string) (*DB, error)",
+			"func Close(*DB)",
+			"method (*DB) Get(string) []byte",
+			"type DB struct",
+			"type DB struct, Timeout int",
+			"type Getter interface { Get }",
+			"type Getter interface, Get(string) []byte",
+			"type Sealed interface { Get }",
+			"type Sealed interface, Get(string) []byte",
+			"type Sealed interface, unexported methods",
+			"type Open interface { }",
+			"type Open interface, unexported methods",
+		)
+		newAPI := surface(
+			"const Limit int = 1",
+			"func Open(string, ...Option) (*DB, error)",
+			"method (*DB) Get(string) []byte",
+			"method (*DB) Put(string, []byte)",
+			"type DB struct",
+			"type DB struct, Timeout int",
+			"type DB struct, Retries int",
+			"type Getter interface { Get, Len }",
+			"type Getter interface, Get(string) []byte",
+			"type Getter interface, Len() int",
+			"type Sealed interface { Get, Len }",
+			"type Sealed interface, Get(string) []byte",
+			"type Sealed interface, Len() int",
+			"type Sealed interface, unexported methods",
+			"type Open interface { }",
+			"type Option func(*DB)",
+		)
+
+		diff := DiffAPI(oldAPI, newAPI)
+		format := func(changes []APIChange) string {
+			var lines []string
+			for _, change := range changes {
+				lines = append(lines, change.Old+" -> "+change.New)
+			}
+			return strings.Join(lines, "\n")
+		}
+		expectedBreaking := []string{
+			"pkg p, func Close(*DB) -> ",
+			"pkg p, func Open(string) (*DB, error) -> pkg p, func Open(string, ...Option) (*DB, error)",
+			" -> pkg p, type Getter interface, Len() int",
+		}
+		expectedCompatible := []string{
+			" -> pkg p, method (*DB) Put(string, []byte)",
+			" -> pkg p, type DB struct, Retries int",
+			"pkg p, type Open interface, unexported methods -> ",
+			" -> pkg p, type Option func(*DB)",
+			" -> pkg p, type Sealed interface, Len() int",
+		}
+		if got := format(diff.Breaking); got != strings.Join(expectedBreaking, "\n") {
+			t.Errorf("Expected breaking changes:\n%s\ngot:\n%s", strings.Join(expectedBreaking, "\n"), got)
+		}
+		if got := format(diff.Compatible); got != strings.Join(expectedCompatible, "\n") {
+			t.Errorf("Expected compatible changes:\n%s\ngot:\n%s", strings.Join(expectedCompatible, "\n"), got)
+		}
+
+		if diff := DiffAPI(oldAPI, oldAPI); len(diff.Breaking) != 0 || len(diff.Compatible) != 0 {
+			t.Errorf("Expected no changes between equal listings, got %+v", diff)
+		}
+	})
+
+	t.Run("feature keys", func(t *testing.T) {
+		t.Parallel()
+		for feature, expected := range map[string]string{
+			"pkg p, const Limit int = 1":                                "pkg p, const Limit",
+			"pkg p, func Max[T Number](...T) T":                         "pkg p, func Max",
+			"pkg p, method (Pair[K, V]) Swap() Pair[K, V]":              "pkg p, method (Pair[K, V]) Swap",
+			"pkg p, type Pair[K comparable, V any] struct, Key K":       "pkg p, type Pair struct, Key",
+			"pkg p, type DB struct, embedded io.Reader":                 "pkg p, type DB struct, embedded io.Reader",
+			"pkg p, type Getter interface { Get }":                      "pkg p, type Getter interface",
+			"pkg p, type Number interface{~int | ~float64}":             "pkg p, type Number",
+			"pkg p, type Alias = DB":                                    "pkg p, type Alias",
+			"pkg p, type Sealed interface, unexported methods":          "pkg p, type Sealed interface, unexported methods",
+			"pkg p, type Getter interface, Get(context.Context) []byte": "pkg p, type Getter interface, Get",
+		} {
+			if got := apiFeatureKey(feature); got != expected {
+				t.Errorf("Expected key %q for %q, got %q", expected, feature, got)
+			}
+		}
+	})
+
+	t.Run("working tree against git ref", func(t *testing.T) {
+		t.Parallel()
+		workspace := writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/lib", "", "go 1.22", ""},
+			"lib/lib.go": {
+				"package lib",
+				"",
+				"func Open(path string) error { return nil }",
+				"",
+				"func Close() {}",
+				"",
+			},
+		})
+		runTestGit(t, workspace, "init", "-q")
+		runTestGit(t, workspace, "add", "-A")
+		runTestGit(t, workspace, "commit", "-q", "-m", "v1")
+		runTestGit(t, workspace, "tag", "v1.0.0")
+		writeTestFiles(t, workspace, map[string][]string{
+			"lib/lib.go": {
+				"package lib",
+				"",
+				"func Open(path string, retries int) error { return nil }",
+				"",
+				"func Close() {}",
+				"",
+				"func Flush() {}",
+				"",
+			},
+		})
+
+		diff, err := DiffAPIVersions(context.Background(), APIDiffOptions{
+			WorkspaceDir: filepath.Join(workspace, "lib"),
+			Package:      ".",
+			Ref:          "v1.0.0",
+		})
+		if err != nil {
+			t.Fatalf("Failed to compare API: %v", err)
+		}
+		expected := strings.Join([]string{
+			"API changes from v1.0.0 to working tree: 1 breaking, 1 compatible",
+			"",
+			"Breaking:",
+			"- pkg example.com/lib/lib, func Open(string) error",
+			"+ pkg example.com/lib/lib, func Open(string, int) error",
+			"",
+			"Compatible:",
+			"+ pkg example.com/lib/lib, func Flush()",
+			"",
+		}, "\n")
+		if diff.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, diff)
+		}
+
+		for name, options := range map[string]APIDiffOptions{
+			"no version":              {WorkspaceDir: workspace, Package: "./lib"},
+			"ref and version":         {WorkspaceDir: workspace, Package: "./lib", Ref: "v1.0.0", OldVersion: "v1.0.0"},
+			"unknown ref":             {WorkspaceDir: workspace, Package: "./lib", Ref: "v9.0.0"},
+			"relative version path":   {WorkspaceDir: workspace, Package: "./lib", OldVersion: "v1.0.0"},
+			"new version without old": {WorkspaceDir: workspace, Package: "./lib", Ref: "v1.0.0", NewVersion: "v1.1.0"},
+		} {
+			if _, err := DiffAPIVersions(context.Background(), options); err == nil {
+				t.Errorf("Expected an error for %s", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	apiSurfaceToolName        = "api_surface"
+	apiSurfaceToolDescription = `Lists the complete exported API of Go packages, one feature per line in the sorted format of the api files of the Go distribution, e.g.:
+
+pkg example.com/store, func Open(string) (*DB, error)
+pkg example.com/store, method (*DB) Get(context.Context, string) ([]byte, error)
+pkg example.com/store, type DB struct
+pkg example.com/store, type DB struct, Timeout time.Duration
+
+Types, struct fields, interface methods, methods, funcs, consts and vars are listed. Parameter names are omitted as they are not part of the API, so listings of two versions can be compared line by line for documentation and reviews.`
+)
+
+// APISurface lists the exported API of packages as sorted features, each of the
+// form "pkg <import path>, <feature>"
+type APISurface struct {
+	Packages []string `json:"packages"`
+	Features []string `json:"features"`
+}
+
+func AddAPISurfaceTool(mcpServer *server.MCPServer) {
+	handleAPISurface := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := request.RequireString("package")
+		if err != nil {
+			return nil, err
+		}
+
+		surface, err := ExportedAPI(ctx, workspaceDir, pattern)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error listing API: %v", err)), nil
+		}
+		return mcp.NewToolResultText(surface.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		apiSurfaceToolName,
+		mcp.WithDescription(apiSurfaceToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of the directory the package is resolved from"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Import path or relative directory of the package, e.g. net/http or ./internal/store. Patterns like ./... list several packages."),
+			mcp.Required(),
+		),
+	), handleAPISurface)
+}
+
+// ExportedAPI type checks the packages matching pattern, resolved from workspaceDir,
+// and lists their exported API
+func ExportedAPI(ctx context.Context, workspaceDir string, pattern string) (*APISurface, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes,
+		Dir: workspaceDir,
+		Env: packagesEnv(workspaceDir),
+	}, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pattern, err)
+	}
+	surface := &APISurface{}
+	for _, pkg := range pkgs {
+		// Type errors still leave usable types, only a package without them fails
+		if pkg.Types == nil || len(pkg.GoFiles) == 0 && len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors))
+		}
+		if pkg.Name == "main" {
+			continue
+		}
+		surface.Packages = append(surface.Packages, pkg.PkgPath)
+		surface.Features = append(surface.Features, packageAPI(pkg.Types)...)
+	}
+	if len(surface.Packages) == 0 {
+		return nil, fmt.Errorf("no library packages match %s", pattern)
+	}
+	slices.Sort(surface.Packages)
+	slices.Sort(surface.Features)
+	return surface, nil
+}
+
+// packageAPI lists the exported features of a type checked package
+func packageAPI(pkg *types.Package) []string {
+	qualifier := types.RelativeTo(pkg)
+	typeString := func(t types.Type) string { return types.TypeString(t, qualifier) }
+	var features []string
+	add := func(format string, args ...any) {
+		features = append(features, fmt.Sprintf("pkg %s, ", pkg.Path())+fmt.Sprintf(format, args...))
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		object := scope.Lookup(name)
+		if !object.Exported() {
+			continue
+		}
+		switch object := object.(type) {
+		case *types.Const:
+			add("const %s %s = %s", name, typeString(object.Type()), object.Val().ExactString())
+		case *types.Var:
+			add("var %s %s", name, typeString(object.Type()))
+		case *types.Func:
+			add("func %s%s", name, apiSignature(object.Type().(*types.Signature), qualifier))
+		case *types.TypeName:
+			if object.IsAlias() {
+				add("type %s = %s", name, typeString(types.Unalias(object.Type())))
+				continue
+			}
+			named, ok := object.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			declared := name + apiTypeParams(named.TypeParams(), qualifier)
+			for _, feature := range typeAPI(declared, named.Underlying(), qualifier) {
+				add("%s", feature)
+			}
+			for i := range named.NumMethods() {
+				method := named.Method(i)
+				if !method.Exported() {
+					continue
+				}
+				signature := method.Type().(*types.Signature)
+				add("method (%s) %s%s", typeString(signature.Recv().Type()), method.Name(), apiSignature(signature, qualifier))
+			}
+		}
+	}
+	return features
+}
+
+// typeAPI lists the features of a type declaration: the type with its kind, and the
+// exported fields of structs and the methods of interfaces
+func typeAPI(declared string, underlying types.Type, qualifier types.Qualifier) []string {
+	switch underlying := underlying.(type) {
+	case *types.Struct:
+		features := []string{fmt.Sprintf("type %s struct", declared)}
+		for field := range underlying.Fields() {
+			if !field.Exported() {
+				continue
+			}
+			if field.Embedded() {
+				features = append(features, fmt.Sprintf("type %s struct, embedded %s", declared, types.TypeString(field.Type(), qualifier)))
+			} else {
+				features = append(features, fmt.Sprintf("type %s struct, %s %s", declared, field.Name(), types.TypeString(field.Type(), qualifier)))
+			}
+		}
+		return features
+	case *types.Interface:
+		if !underlying.IsMethodSet() {
+			// Constraints are compared as a whole, their type sets cannot be listed by line
+			return []string{fmt.Sprintf("type %s %s", declared, types.TypeString(underlying, qualifier))}
+		}
+		var names, features []string
+		unexported := false
+		for method := range underlying.Methods() {
+			if !method.Exported() {
+				unexported = true
+				continue
+			}
+			names = append(names, method.Name())
+			features = append(features, fmt.Sprintf("type %s interface, %s%s", declared, method.Name(), apiSignature(method.Type().(*types.Signature), qualifier)))
+		}
+		if unexported {
+			// Other packages cannot implement the interface
+			features = append(features, fmt.Sprintf("type %s interface, unexported methods", declared))
+		}
+		return append(features, fmt.Sprintf("type %s interface { %s }", declared, strings.Join(names, ", ")))
+	case *types.Signature:
+		return []string{fmt.Sprintf("type %s func%s", declared, apiSignature(underlying, qualifier))}
+	}
+	return []string{fmt.Sprintf("type %s %s", declared, types.TypeString(underlying, qualifier))}
+}
+
+// apiSignature formats the type parameters, parameters and results of a signature
+// without parameter names, e.g. [T any](T, ...string) (int, error)
+func apiSignature(signature *types.Signature, qualifier types.Qualifier) string {
+	tuple := func(vars *types.Tuple, variadic bool) []string {
+		var list []string
+		for i := range vars.Len() {
+			t := vars.At(i).Type()
+			if variadic && i == vars.Len()-1 {
+				list = append(list, "..."+types.TypeString(t.(*types.Slice).Elem(), qualifier))
+				continue
+			}
+			list = append(list, types.TypeString(t, qualifier))
+		}
+		return list
+	}
+
+	var b strings.Builder
+	b.WriteString(apiTypeParams(signature.TypeParams(), qualifier))
+	b.WriteString("(" + strings.Join(tuple(signature.Params(), signature.Variadic()), ", ") + ")")
+	switch results := tuple(signature.Results(), false); len(results) {
+	case 0:
+	case 1:
+		b.WriteString(" " + results[0])
+	default:
+		b.WriteString(" (" + strings.Join(results, ", ") + ")")
+	}
+	return b.String()
+}
+
+// apiTypeParams formats type parameters with their constraints, e.g. [K comparable, V any]
+func apiTypeParams(params *types.TypeParamList, qualifier types.Qualifier) string {
+	if params.Len() == 0 {
+		return ""
+	}
+	var list []string
+	for param := range params.TypeParams() {
+		list = append(list, param.Obj().Name()+" "+types.TypeString(param.Constraint(), qualifier))
+	}
+	return "[" + strings.Join(list, ", ") + "]"
+}
+
+func (surface *APISurface) String() string {
+	if len(surface.Features) == 0 {
+		return fmt.Sprintf("No exported API in %s", strings.Join(surface.Packages, ", "))
+	}
+	return strings.Join(surface.Features, "\n") + "\n"
+}
@@ -0,0 +1,153 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAPISurface(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a library package and a command
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/store", "", "go 1.22", ""},
+			"store.go": {
+				"// Package store keeps values",
+				"package store",
+				"",
+				"import (",
+				"\t\"io\"",
+				"\t\"time\"",
+				")",
+				"",
+				"// Version of the store",
+				"const Version = \"v1\"",
+				"",
+				"const MaxSize int64 = 1 << 10",
+				"",
+				"var ErrClosed = io.EOF",
+				"",
+				"type DB struct {",
+				"\tTimeout time.Duration",
+				"\tio.Reader",
+				"\tpath    string",
+				"}",
+				"",
+				"func Open(path string, options ...Option) (*DB, error) { return &DB{path: path}, nil }",
+				"",
+				"func (db *DB) Get(key string) []byte { return nil }",
+				"",
+				"func (db *DB) close() {}",
+				"",
+				"type Option func(*DB)",
+				"",
+				"type Getter interface {",
+				"\tGet(key string) []byte",
+				"}",
+				"",
+				"type sealed interface {",
+				"\tGetter",
+				"\tseal()",
+				"}",
+				"",
+				"type Sealed sealed",
+				"",
+				"type Number interface{ ~int | ~float64 }",
+				"",
+				"type Pair[K comparable, V any] struct{ Key K }",
+				"",
+				"func (p Pair[K, V]) Swap() Pair[K, V] { return p }",
+				"",
+				"func Max[T Number](values ...T) T { return values[0] }",
+				"",
+				"type Alias = DB",
+				"",
+				"type internal struct{ Exported int }",
+				"",
+			},
+			"cmd/store/main.go": {"package main", "", "func Run() {}", "", "func main() {}", ""},
+		})
+	}
+
+	t.Run("features", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		surface, err := ExportedAPI(context.Background(), workspace, ".")
+		if err != nil {
+			t.Fatalf("Failed to list API: %v", err)
+		}
+		expected := []string{
+			"pkg example.com/store, const MaxSize int64 = 1024",
+			`pkg example.com/store, const Version untyped string = "v1"`,
+			"pkg example.com/store, func Max[T Number](...T) T",
+			"pkg example.com/store, func Open(string, ...Option) (*DB, error)",
+			"pkg example.com/store, method (*DB) Get(string) []byte",
+			"pkg example.com/store, method (Pair[K, V]) Swap() Pair[K, V]",
+			"pkg example.com/store, type Alias = DB",
+			"pkg example.com/store, type DB struct",
+			"pkg example.com/store, type DB struct, Timeout time.Duration",
+			"pkg example.com/store, type DB struct, embedded io.Reader",
+			"pkg example.com/store, type Getter interface { Get }",
+			"pkg example.com/store, type Getter interface, Get(string) []byte",
+			"pkg example.com/store, type Number interface{~int | ~float64}",
+			"pkg example.com/store, type Option func(*DB)",
+			"pkg example.com/store, type Pair[K comparable, V any] struct",
+			"pkg example.com/store, type Pair[K comparable, V any] struct, Key K",
+			"pkg example.com/store, type Sealed interface { Get }",
+			"pkg example.com/store, type Sealed interface, Get(string) []byte",
+			"pkg example.com/store, type Sealed interface, unexported methods",
+			"pkg example.com/store, var ErrClosed error",
+		}
+		if got := strings.Join(surface.Features, "\n"); got != strings.Join(expected, "\n") {
+			t.Errorf("Expected features:\n%s\ngot:\n%s", strings.Join(expected, "\n"), got)
+		}
+	})
+
+	t.Run("patterns skip commands", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		surface, err := ExportedAPI(context.Background(), workspace, "./...")
+		if err != nil {
+			t.Fatalf("Failed to list API: %v", err)
+		}
+		if len(surface.Packages) != 1 || surface.Packages[0] != "example.com/store" {
+			t.Errorf("Expected only the library package, got %v", surface.Packages)
+		}
+		if _, err := ExportedAPI(context.Background(), workspace, "./cmd/store"); err == nil {
+			t.Error("Expected an error for a command")
+		}
+		if _, err := ExportedAPI(context.Background(), workspace, "./missing"); err == nil {
+			t.Error("Expected an error for a missing package")
+		}
+	})
+
+	t.Run("tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      apiSurfaceToolName,
+				"arguments": map[string]any{"workspace_dir": workspace, "package": "example.com/store"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := result.Content[0].(mcp.TextContent).Text
+		if result.IsError || !strings.HasPrefix(text, "pkg example.com/store, const MaxSize int64 = 1024\n") {
+			t.Errorf("Expected the sorted API, got: %s", text)
+		}
+	})
+}
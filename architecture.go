@@ -0,0 +1,430 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/doc"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	architectureToolName        = "architecture"
+	architectureToolDescription = `Summarizes the architecture of the workspace top-down: its Go modules, the packages of each module grouped into clusters of packages importing each other, the shared packages imported across clusters, the entry points (main packages) and the external dependencies by category (db, http, rpc, queue, cloud, observability, cli).
+
+Use it to orient yourself in an unfamiliar codebase before inspecting individual packages. Returns markdown, or JSON with format=json.`
+)
+
+// dependencyCategories maps import path prefixes to the category of the dependency.
+// Standard library imports are only categorized when listed here.
+var dependencyCategories = []struct {
+	prefix   string
+	category string
+}{
+	{"database/sql", "db"},
+	{"gorm.io/", "db"},
+	{"github.com/jackc/pgx", "db"},
+	{"github.com/lib/pq", "db"},
+	{"github.com/go-sql-driver/mysql", "db"},
+	{"github.com/mattn/go-sqlite3", "db"},
+	{"modernc.org/sqlite", "db"},
+	{"github.com/jmoiron/sqlx", "db"},
+	{"go.mongodb.org/mongo-driver", "db"},
+	{"github.com/redis/go-redis", "db"},
+	{"github.com/go-redis/redis", "db"},
+	{"go.etcd.io/bbolt", "db"},
+	{"github.com/dgraph-io/badger", "db"},
+	{"entgo.io/ent", "db"},
+	{"net/http", "http"},
+	{"github.com/gin-gonic/gin", "http"},
+	{"github.com/labstack/echo", "http"},
+	{"github.com/go-chi/chi", "http"},
+	{"github.com/gorilla/mux", "http"},
+	{"github.com/gofiber/fiber", "http"},
+	{"github.com/valyala/fasthttp", "http"},
+	{"google.golang.org/grpc", "rpc"},
+	{"google.golang.org/protobuf", "rpc"},
+	{"github.com/golang/protobuf", "rpc"},
+	{"connectrpc.com/connect", "rpc"},
+	{"github.com/twitchtv/twirp", "rpc"},
+	{"github.com/mark3labs/mcp-go", "rpc"},
+	{"github.com/segmentio/kafka-go", "queue"},
+	{"github.com/IBM/sarama", "queue"},
+	{"github.com/Shopify/sarama", "queue"},
+	{"github.com/confluentinc/confluent-kafka-go", "queue"},
+	{"github.com/nats-io/", "queue"},
+	{"github.com/rabbitmq/amqp091-go", "queue"},
+	{"github.com/streadway/amqp", "queue"},
+	{"cloud.google.com/go/pubsub", "queue"},
+	{"github.com/aws/aws-sdk-go-v2/service/sqs", "queue"},
+	{"github.com/aws/aws-sdk-go", "cloud"},
+	{"cloud.google.com/go", "cloud"},
+	{"github.com/Azure/azure-sdk-for-go", "cloud"},
+	{"k8s.io/", "cloud"},
+	{"sigs.k8s.io/", "cloud"},
+	{"github.com/prometheus/", "observability"},
+	{"go.opentelemetry.io/", "observability"},
+	{"go.uber.org/zap", "observability"},
+	{"github.com/sirupsen/logrus", "observability"},
+	{"github.com/rs/zerolog", "observability"},
+	{"log/slog", "observability"},
+	{"github.com/spf13/cobra", "cli"},
+	{"github.com/urfave/cli", "cli"},
+	{"github.com/alecthomas/kong", "cli"},
+	{"flag", "cli"},
+}
+
+// ArchitectureSummary is the top-down structure of a workspace
+type ArchitectureSummary struct {
+	Modules []ArchitectureModule `json:"modules"`
+}
+
+// ArchitectureModule is a Go module of the workspace
+type ArchitectureModule struct {
+	Path      string `json:"path"`
+	Dir       string `json:"dir"`
+	GoVersion string `json:"go_version,omitempty"`
+	// Clusters group packages connected by imports, largest first
+	Clusters []PackageCluster `json:"clusters"`
+	// Shared are the packages imported by many packages, which would otherwise join all clusters
+	Shared       []ArchitecturePackage `json:"shared,omitempty"`
+	EntryPoints  []ArchitecturePackage `json:"entry_points,omitempty"`
+	Dependencies []DependencyCategory  `json:"dependencies,omitempty"`
+}
+
+// PackageCluster is a group of packages importing each other
+type PackageCluster struct {
+	// Name is the common directory of the packages relative to the module root
+	Name     string                `json:"name"`
+	Packages []ArchitecturePackage `json:"packages"`
+}
+
+// ArchitecturePackage is a package of a module
+type ArchitecturePackage struct {
+	ImportPath string `json:"import_path"`
+	Dir        string `json:"dir"`
+	Name       string `json:"name"`
+	Synopsis   string `json:"synopsis,omitempty"`
+	Files      int    `json:"files"`
+	// Imports are the packages of the same module imported by the package
+	Imports    []string `json:"imports,omitempty"`
+	ImportedBy int      `json:"imported_by"`
+}
+
+// DependencyCategory lists the external imports of a category
+type DependencyCategory struct {
+	Category string   `json:"category"`
+	Imports  []string `json:"imports"`
+}
+
+func AddArchitectureTool(mcpServer *server.MCPServer) {
+	handleArchitecture := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		format := request.GetString("format", "")
+		if format != "" && format != "markdown" && format != "json" {
+			return toolErrorResult(fmt.Sprintf("Error: unknown format %q, use markdown or json", format)), nil
+		}
+
+		summary, err := Architecture(workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error summarizing architecture: %v", err)), nil
+		}
+		if format == "json" {
+			encoded, err := json.MarshalIndent(summary, "", "  ")
+			if err != nil {
+				return toolErrorResult(fmt.Sprintf("Error encoding architecture: %v", err)), nil
+			}
+			return mcp.NewToolResultText(string(encoded)), nil
+		}
+		return mcp.NewToolResultText(summary.Markdown()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		architectureToolName,
+		mcp.WithDescription(architectureToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of the workspace, all Go modules in and above it are summarized"),
+			mcp.Required(),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format"),
+			mcp.Enum("markdown", "json"),
+			mcp.DefaultString("markdown"),
+		),
+	), handleArchitecture)
+}
+
+// Architecture summarizes the Go modules in workspaceDir, or the module containing it
+func Architecture(workspaceDir string) (*ArchitectureSummary, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	roots, err := findModuleRoots(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		root, err := findModuleRoot(workspaceDir)
+		if err != nil {
+			return nil, err
+		}
+		roots = []string{root}
+	}
+
+	summary := &ArchitectureSummary{}
+	for _, root := range roots {
+		module, err := loadModuleSources(root, false)
+		if err != nil {
+			return nil, err
+		}
+		summary.Modules = append(summary.Modules, summarizeModule(module))
+	}
+	return summary, nil
+}
+
+// findModuleRoots returns the directories of all go.mod files in dir, skipping
+// hidden directories, testdata and vendor
+func findModuleRoots(dir string) ([]string, error) {
+	var roots []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() == "go.mod" {
+			roots = append(roots, filepath.Dir(path))
+		}
+		return nil
+	})
+	return roots, err
+}
+
+// summarizeModule clusters the packages of the module and categorizes its dependencies
+func summarizeModule(module *moduleSources) ArchitectureModule {
+	summary := ArchitectureModule{Path: module.path, Dir: module.root}
+	if content, err := os.ReadFile(filepath.Join(module.root, "go.mod")); err == nil {
+		if file, err := modfile.ParseLax("go.mod", content, nil); err == nil && file.Go != nil {
+			summary.GoVersion = file.Go.Version
+		}
+	}
+
+	packages := make(map[string]*ArchitecturePackage)
+	var order []string
+	external := make(map[string]map[string]bool)
+	for _, pkg := range module.packages {
+		info := &ArchitecturePackage{
+			ImportPath: pkg.importPath,
+			Dir:        pkg.dir,
+			Name:       pkg.name,
+			Files:      len(pkg.files),
+		}
+		imports := make(map[string]bool)
+		for _, file := range pkg.files {
+			if file.ast.Doc != nil && info.Synopsis == "" {
+				info.Synopsis = (&doc.Package{}).Synopsis(file.ast.Doc.Text())
+			}
+			for _, spec := range file.ast.Imports {
+				importPath := strings.Trim(spec.Path.Value, "\"`")
+				if module.packageByImportPath(importPath) != nil {
+					imports[importPath] = true
+					continue
+				}
+				if category := dependencyCategory(importPath); category != "" {
+					if external[category] == nil {
+						external[category] = make(map[string]bool)
+					}
+					external[category][importPath] = true
+				}
+			}
+		}
+		for importPath := range imports {
+			info.Imports = append(info.Imports, importPath)
+		}
+		slices.Sort(info.Imports)
+		packages[pkg.importPath] = info
+		order = append(order, pkg.importPath)
+	}
+	for _, info := range packages {
+		for _, importPath := range info.Imports {
+			packages[importPath].ImportedBy++
+		}
+	}
+
+	// Packages imported by a third of the module are foundations shared across clusters
+	sharedThreshold := max(3, (len(order)+2)/3)
+	shared := make(map[string]bool)
+	for _, importPath := range order {
+		info := packages[importPath]
+		if info.Name == "main" {
+			summary.EntryPoints = append(summary.EntryPoints, *info)
+		}
+		if info.ImportedBy >= sharedThreshold && info.Name != "main" {
+			shared[importPath] = true
+			summary.Shared = append(summary.Shared, *info)
+		}
+	}
+
+	// Clusters are the connected components of the import graph without the shared packages
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(importPath string) string {
+		if parent[importPath] == importPath {
+			return importPath
+		}
+		parent[importPath] = find(parent[importPath])
+		return parent[importPath]
+	}
+	for _, importPath := range order {
+		parent[importPath] = importPath
+	}
+	for _, importPath := range order {
+		if shared[importPath] {
+			continue
+		}
+		for _, imported := range packages[importPath].Imports {
+			if !shared[imported] {
+				parent[find(importPath)] = find(imported)
+			}
+		}
+	}
+	clusters := make(map[string][]ArchitecturePackage)
+	for _, importPath := range order {
+		if !shared[importPath] {
+			root := find(importPath)
+			clusters[root] = append(clusters[root], *packages[importPath])
+		}
+	}
+	for _, members := range clusters {
+		summary.Clusters = append(summary.Clusters, PackageCluster{
+			Name:     clusterName(module, members),
+			Packages: members,
+		})
+	}
+	slices.SortFunc(summary.Clusters, func(a, b PackageCluster) int {
+		if len(a.Packages) != len(b.Packages) {
+			return len(b.Packages) - len(a.Packages)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for category, imports := range external {
+		dependency := DependencyCategory{Category: category}
+		for importPath := range imports {
+			dependency.Imports = append(dependency.Imports, importPath)
+		}
+		slices.Sort(dependency.Imports)
+		summary.Dependencies = append(summary.Dependencies, dependency)
+	}
+	slices.SortFunc(summary.Dependencies, func(a, b DependencyCategory) int {
+		return strings.Compare(a.Category, b.Category)
+	})
+	return summary
+}
+
+// dependencyCategory returns the category of an import, or an empty string if unknown
+func dependencyCategory(importPath string) string {
+	for _, dependency := range dependencyCategories {
+		prefix := dependency.prefix
+		if importPath == prefix || strings.HasPrefix(importPath, strings.TrimSuffix(prefix, "/")+"/") {
+			return dependency.category
+		}
+	}
+	return ""
+}
+
+// clusterName returns the common directory of the packages relative to the module root
+func clusterName(module *moduleSources, packages []ArchitecturePackage) string {
+	common := strings.Split(filepath.ToSlash(module.relPath(packages[0].Dir)), "/")
+	for _, pkg := range packages[1:] {
+		parts := strings.Split(filepath.ToSlash(module.relPath(pkg.Dir)), "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	name := strings.Join(common, "/")
+	if name == "" || name == "." {
+		return "(module root)"
+	}
+	return name
+}
+
+// Markdown renders the summary for reading
+func (summary *ArchitectureSummary) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Architecture\n")
+	for _, module := range summary.Modules {
+		relPath := func(dir string) string {
+			rel, err := filepath.Rel(module.Dir, dir)
+			if err != nil {
+				return dir
+			}
+			return rel
+		}
+		writePackage := func(pkg ArchitecturePackage, extra string) {
+			fmt.Fprintf(&b, "- `%s` (%s, %d files%s)", pkg.ImportPath, relPath(pkg.Dir), pkg.Files, extra)
+			if pkg.Synopsis != "" {
+				b.WriteString(" - " + pkg.Synopsis)
+			}
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "\n## Module %s\n\n", module.Path)
+		fmt.Fprintf(&b, "Directory: %s\n", module.Dir)
+		if module.GoVersion != "" {
+			fmt.Fprintf(&b, "Go version: %s\n", module.GoVersion)
+		}
+
+		if len(module.EntryPoints) > 0 {
+			b.WriteString("\n### Entry points\n\n")
+			for _, pkg := range module.EntryPoints {
+				writePackage(pkg, "")
+			}
+		}
+
+		b.WriteString("\n### Package clusters\n")
+		for _, cluster := range module.Clusters {
+			fmt.Fprintf(&b, "\n#### %s (%d packages)\n\n", cluster.Name, len(cluster.Packages))
+			for _, pkg := range cluster.Packages {
+				writePackage(pkg, "")
+			}
+		}
+
+		if len(module.Shared) > 0 {
+			b.WriteString("\n### Shared packages\n\n")
+			for _, pkg := range module.Shared {
+				writePackage(pkg, fmt.Sprintf(", imported by %d packages", pkg.ImportedBy))
+			}
+		}
+
+		if len(module.Dependencies) > 0 {
+			b.WriteString("\n### External dependencies\n\n")
+			for _, dependency := range module.Dependencies {
+				fmt.Fprintf(&b, "- %s: %s\n", dependency.Category, strings.Join(dependency.Imports, ", "))
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestArchitecture(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with two clusters sharing a log package
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/shop", "", "go 1.22", ""},
+			"cmd/server/main.go": {
+				"package main",                          // 1
+				"",                                      // 2
+				"import (",                              // 3
+				"    \"net/http\"",                      // 4
+				"    \"example.com/shop/api\"",          // 5
+				"    \"example.com/shop/internal/log\"", // 6
+				")",                                     // 7
+				"",                                      // 8
+				"func main() {",                         // 9
+				"    log.Print(\"start\")",              // 10
+				"    http.ListenAndServe(\":80\", api.Handler())", // 11
+				"}", // 12
+			},
+			"api/api.go": {
+				"// Package api serves the shop over HTTP.", // 1
+				"package api",      // 2
+				"",                 // 3
+				"import (",         // 4
+				"    \"net/http\"", // 5
+				"    \"example.com/shop/api/middleware\"", // 6
+				"    \"example.com/shop/internal/log\"",   // 7
+				")",                                       // 8
+				"",                                        // 9
+				"func Handler() http.Handler { log.Print(\"api\"); return middleware.Wrap(nil) }", // 10
+			},
+			"api/middleware/middleware.go": {
+				"package middleware",  // 1
+				"",                    // 2
+				"import \"net/http\"", // 3
+				"",                    // 4
+				"func Wrap(h http.Handler) http.Handler { return h }", // 5
+			},
+			"store/store.go": {
+				"package store",                         // 1
+				"",                                      // 2
+				"import (",                              // 3
+				"    \"database/sql\"",                  // 4
+				"    _ \"github.com/lib/pq\"",           // 5
+				"    \"example.com/shop/internal/log\"", // 6
+				")",                                     // 7
+				"",                                      // 8
+				"func Open() (*sql.DB, error) { log.Print(\"open\"); return sql.Open(\"postgres\", \"\") }", // 9
+			},
+			"internal/log/log.go": {
+				"// Package log writes structured logs.", // 1
+				"package log",                            // 2
+				"",                                       // 3
+				"func Print(message string) {}",          // 4
+			},
+		})
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		summary, err := Architecture(workspace)
+		if err != nil {
+			t.Fatalf("Failed to summarize architecture: %v", err)
+		}
+		if len(summary.Modules) != 1 {
+			t.Fatalf("Expected one module, got %+v", summary.Modules)
+		}
+		module := summary.Modules[0]
+		if module.Path != "example.com/shop" || module.GoVersion != "1.22" {
+			t.Errorf("Expected the module path and Go version, got %+v", module)
+		}
+		if len(module.EntryPoints) != 1 || module.EntryPoints[0].ImportPath != "example.com/shop/cmd/server" {
+			t.Errorf("Expected cmd/server as entry point, got %+v", module.EntryPoints)
+		}
+		if len(module.Shared) != 1 || module.Shared[0].ImportPath != "example.com/shop/internal/log" || module.Shared[0].ImportedBy != 3 {
+			t.Errorf("Expected internal/log as shared package, got %+v", module.Shared)
+		}
+
+		var clusters []string
+		for _, cluster := range module.Clusters {
+			clusters = append(clusters, fmt.Sprintf("%s:%d", cluster.Name, len(cluster.Packages)))
+		}
+		if strings.Join(clusters, ",") != "(module root):3,store:1" {
+			t.Errorf("Expected the api and store clusters, got %v", clusters)
+		}
+
+		dependencies := make(map[string]string)
+		for _, dependency := range module.Dependencies {
+			dependencies[dependency.Category] = strings.Join(dependency.Imports, ",")
+		}
+		if dependencies["db"] != "database/sql,github.com/lib/pq" || dependencies["http"] != "net/http" || len(dependencies) != 2 {
+			t.Errorf("Expected db and http dependencies, got %v", dependencies)
+		}
+	})
+
+	t.Run("architecture tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		call := func(arguments map[string]any) mcp.CallToolResult {
+			encoded, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      1,
+				"method":  string(mcp.MethodToolsCall),
+				"params":  map[string]any{"name": architectureToolName, "arguments": arguments},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+			return response.Result.(mcp.CallToolResult)
+		}
+
+		result := call(map[string]any{"workspace_dir": workspace})
+		text := toolResultText(&result)
+		for _, expected := range []string{
+			"## Module example.com/shop\n",
+			"- `example.com/shop/api` (api, 1 files) - Package api serves the shop over HTTP.\n",
+			"- `example.com/shop/internal/log` (internal/log, 1 files, imported by 3 packages) - Package log writes structured logs.\n",
+			"- db: database/sql, github.com/lib/pq\n",
+		} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the summary, got: %s", expected, text)
+			}
+		}
+
+		result = call(map[string]any{"workspace_dir": workspace, "format": "json"})
+		var summary ArchitectureSummary
+		if err := json.Unmarshal([]byte(toolResultText(&result)), &summary); err != nil || len(summary.Modules) != 1 {
+			t.Errorf("Expected the summary as JSON, got: %s", toolResultText(&result))
+		}
+	})
+}
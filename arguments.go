@@ -0,0 +1,402 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ArgumentError describes an argument of a tool call that does not match the input schema
+// of the tool, with an example of a valid value so callers can correct the call
+type ArgumentError struct {
+	Argument string
+	// Problem describes what is wrong, e.g. "must be a number, got string \"12\""
+	Problem string
+	// Example is a valid value of the argument in JSON
+	Example string
+}
+
+func (e *ArgumentError) Error() string {
+	if e.Example == "" {
+		return fmt.Sprintf("argument %q %s", e.Argument, e.Problem)
+	}
+	return fmt.Sprintf("argument %q %s, e.g. %q: %s", e.Argument, e.Problem, e.Argument, e.Example)
+}
+
+// ValidateArguments checks the arguments of a tool call against the input schema of the
+// tool: required arguments must be present, values must have the type of their property,
+// enum values must be listed and arguments must be declared. All mismatches are returned,
+// ordered by argument name with missing required arguments first.
+func ValidateArguments(schema mcp.ToolInputSchema, arguments map[string]any) []*ArgumentError {
+	var errs []*ArgumentError
+	for _, name := range schema.Required {
+		property, _ := schema.Properties[name].(map[string]any)
+		value, ok := arguments[name]
+		if !ok || value == nil || value == "" {
+			errs = append(errs, &ArgumentError{
+				Argument: name,
+				Problem:  "is required" + describeProperty(property),
+				Example:  argumentExample(property, nil),
+			})
+		}
+	}
+
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := arguments[name]
+		property, ok := schema.Properties[name].(map[string]any)
+		if !ok {
+			declared := make([]string, 0, len(schema.Properties))
+			for declaredName := range schema.Properties {
+				declared = append(declared, declaredName)
+			}
+			sort.Strings(declared)
+			errs = append(errs, &ArgumentError{
+				Argument: name,
+				Problem:  fmt.Sprintf("is not an argument of the tool, which takes %s", strings.Join(declared, ", ")),
+			})
+			continue
+		}
+		if value == nil || (value == "" && slices.Contains(schema.Required, name)) {
+			// Reported as missing above, or an explicit null for an optional argument
+			continue
+		}
+		if problem := checkArgumentValue(property, value); problem != "" {
+			errs = append(errs, &ArgumentError{
+				Argument: name,
+				Problem:  problem,
+				Example:  argumentExample(property, value),
+			})
+		}
+	}
+	return errs
+}
+
+// checkArgumentValue returns what is wrong with the value of an argument, or an empty string
+func checkArgumentValue(property map[string]any, value any) string {
+	typ, _ := property["type"].(string)
+	if !hasJSONType(value, typ) {
+		return fmt.Sprintf("must be %s, got %s", typeName(typ, property), describeValue(value))
+	}
+	if enum := enumValues(property); len(enum) > 0 {
+		if text, ok := value.(string); ok && !slices.Contains(enum, text) {
+			return fmt.Sprintf("must be one of %s, got %q", quoteAll(enum), text)
+		}
+	}
+	if typ == "array" {
+		items, _ := property["items"].(map[string]any)
+		itemType, _ := items["type"].(string)
+		for i, item := range value.([]any) {
+			if !hasJSONType(item, itemType) {
+				return fmt.Sprintf("must be %s, got %s at index %d", typeName(typ, property), describeValue(item), i)
+			}
+		}
+	}
+	return ""
+}
+
+// hasJSONType reports whether a decoded JSON value has the JSON Schema type, any type
+// matching when typ is empty
+func hasJSONType(value any, typ string) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+// typeName describes a JSON Schema type with an article, e.g. "an array of strings"
+func typeName(typ string, property map[string]any) string {
+	switch typ {
+	case "array":
+		items, _ := property["items"].(map[string]any)
+		if itemType, ok := items["type"].(string); ok {
+			return "an array of " + itemType + "s"
+		}
+		return "an array"
+	case "integer", "object":
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+// describeProperty describes the type and purpose of a property for a missing argument
+func describeProperty(property map[string]any) string {
+	typ, _ := property["type"].(string)
+	if typ == "" {
+		return ""
+	}
+	description := " and must be " + typeName(typ, property)
+	if text, ok := property["description"].(string); ok && text != "" {
+		description += " (" + strings.TrimSuffix(text, ".") + ")"
+	}
+	return description
+}
+
+// describeValue describes a decoded JSON value with its type, e.g. `string "12"`
+func describeValue(value any) string {
+	encoded, _ := json.Marshal(value)
+	switch value.(type) {
+	case string:
+		return "string " + string(encoded)
+	case float64:
+		return "number " + string(encoded)
+	case bool:
+		return "boolean " + string(encoded)
+	case []any:
+		return "array " + string(encoded)
+	case map[string]any:
+		return "object " + string(encoded)
+	}
+	return "null"
+}
+
+// argumentExample returns a valid value of the property in JSON, derived from the invalid
+// value when it can be converted, like the number in the string "12"
+func argumentExample(property map[string]any, value any) string {
+	typ, _ := property["type"].(string)
+	text, isText := value.(string)
+	var example any
+	switch typ {
+	case "string":
+		if enum := enumValues(property); len(enum) > 0 {
+			example = enum[0]
+		} else if number, ok := value.(float64); ok {
+			example = strconv.FormatFloat(number, 'f', -1, 64)
+		} else {
+			example = "..."
+		}
+	case "number", "integer":
+		example = 1
+		if number, err := strconv.ParseFloat(strings.TrimSpace(text), 64); isText && err == nil {
+			example = number
+		}
+	case "boolean":
+		example = true
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(text)); isText && err == nil {
+			example = parsed
+		}
+	case "array":
+		items, _ := property["items"].(map[string]any)
+		itemType, _ := items["type"].(string)
+		switch {
+		case isText && itemType == "string":
+			var list []string
+			for _, item := range strings.Split(text, ",") {
+				list = append(list, strings.TrimSpace(item))
+			}
+			example = list
+		case itemType == "number" || itemType == "integer":
+			example = []int{1}
+		default:
+			example = []string{"..."}
+		}
+	case "object":
+		example = map[string]string{"...": "..."}
+	default:
+		return ""
+	}
+	encoded, err := json.Marshal(example)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// enumValues returns the allowed values of a string property
+func enumValues(property map[string]any) []string {
+	switch enum := property["enum"].(type) {
+	case []string:
+		return enum
+	case []any:
+		var values []string
+		for _, value := range enum {
+			if text, ok := value.(string); ok {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// quoteAll quotes and joins values, e.g. `"a", "b"`
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// toolSchemas looks up the input schema of a called tool, the tools of the session before
+// the tools registered on the server, and runs the tool filters on that tool alone so the
+// arguments added by the filters are validated too. The tool filters of the server are
+// installed through filter so they are not run when the registered tools are listed
+type toolSchemas struct {
+	mcpServer *server.MCPServer
+	filters   []server.ToolFilterFunc
+
+	mu sync.Mutex
+	// tools are the tools registered on the server without filters, listed again when a
+	// tool is missing so tools added after the server is created are found
+	tools map[string]mcp.Tool
+}
+
+// unfilteredListKey marks the context of the tools/list requests of toolSchemas
+type unfilteredListKey struct{}
+
+// filter runs the tool filters in the order they were added, unless the tools are listed
+// to look up their schemas
+func (s *toolSchemas) filter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	if ctx.Value(unfilteredListKey{}) != nil {
+		return tools
+	}
+	for _, filter := range s.filters {
+		tools = filter(ctx, tools)
+	}
+	return tools
+}
+
+// get returns the input schema of the tool as it is listed to the caller, false if the
+// tool does not exist or is filtered out
+func (s *toolSchemas) get(ctx context.Context, name string) (mcp.ToolInputSchema, bool) {
+	tool, ok := s.sessionTool(ctx, name)
+	if !ok {
+		tool, ok = s.registered(ctx, name)
+	}
+	if !ok {
+		return mcp.ToolInputSchema{}, false
+	}
+	for _, filtered := range s.filter(ctx, []mcp.Tool{tool}) {
+		if filtered.Name == name {
+			return filtered.InputSchema, true
+		}
+	}
+	return mcp.ToolInputSchema{}, false
+}
+
+// sessionTool returns the tool added to the session of the call, which is served in place
+// of a registered tool of the same name
+func (s *toolSchemas) sessionTool(ctx context.Context, name string) (mcp.Tool, bool) {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithTools)
+	if !ok {
+		return mcp.Tool{}, false
+	}
+	tool, ok := session.GetSessionTools()[name]
+	return tool.Tool, ok
+}
+
+// registered returns the registered tool, listing the tools of the server again when the
+// tool is not known yet
+func (s *toolSchemas) registered(ctx context.Context, name string) (mcp.Tool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tool, ok := s.tools[name]; ok {
+		return tool, true
+	}
+	if s.mcpServer == nil {
+		return mcp.Tool{}, false
+	}
+	tools, err := s.list(context.WithValue(ctx, unfilteredListKey{}, true))
+	if err != nil {
+		return mcp.Tool{}, false
+	}
+	s.tools = tools
+	tool, ok := s.tools[name]
+	return tool, ok
+}
+
+// list lists the tools of the server, following the cursors of paginated lists
+func (s *toolSchemas) list(ctx context.Context) (map[string]mcp.Tool, error) {
+	tools := map[string]mcp.Tool{}
+	params := map[string]any{}
+	for {
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      0,
+			"method":  string(mcp.MethodToolsList),
+			"params":  params,
+		})
+		if err != nil {
+			return nil, err
+		}
+		response, ok := s.mcpServer.HandleMessage(ctx, encoded).(mcp.JSONRPCResponse)
+		if !ok {
+			return nil, fmt.Errorf("failed to list tools")
+		}
+		result, ok := response.Result.(mcp.ListToolsResult)
+		if !ok {
+			return nil, fmt.Errorf("failed to list tools: unexpected result %T", response.Result)
+		}
+		for _, tool := range result.Tools {
+			tools[tool.Name] = tool
+		}
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		params = map[string]any{"cursor": result.NextCursor}
+	}
+}
+
+// argumentValidationMiddleware rejects tool calls whose arguments do not match the input
+// schema of the tool with an error result naming each invalid argument, so callers can
+// correct the call instead of running into the errors of the handlers
+func argumentValidationMiddleware(schemas *toolSchemas) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			schema, ok := schemas.get(ctx, request.Params.Name)
+			// Tools created with a raw JSON schema have no parsed input schema
+			if !ok || schema.Type == "" {
+				return next(ctx, request)
+			}
+			arguments, ok := request.Params.Arguments.(map[string]any)
+			if !ok && request.Params.Arguments != nil {
+				return toolErrorResult(fmt.Sprintf(
+					"Error: invalid arguments for %s: arguments must be an object, got %s",
+					request.Params.Name,
+					describeValue(request.Params.Arguments),
+				)), nil
+			}
+			errs := ValidateArguments(schema, arguments)
+			if len(errs) == 0 {
+				return next(ctx, request)
+			}
+			var b strings.Builder
+			fmt.Fprintf(&b, "Error: invalid arguments for %s:", request.Params.Name)
+			for _, err := range errs {
+				b.WriteString("\n- ")
+				b.WriteString(err.Error())
+			}
+			return toolErrorResult(b.String()), nil
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolsSession is a client session with tools of its own
+type toolsSession struct {
+	testSession
+	tools map[string]server.ServerTool
+}
+
+func (session *toolsSession) GetSessionTools() map[string]server.ServerTool {
+	return session.tools
+}
+
+func (session *toolsSession) SetSessionTools(tools map[string]server.ServerTool) {
+	session.tools = tools
+}
+
+func TestValidateArguments(t *testing.T) {
+	t.Parallel()
+
+	tool := mcp.NewTool("example",
+		mcp.WithString("file_path", mcp.Description("Absolute path of the Go file"), mcp.Required()),
+		mcp.WithNumber("line_number"),
+		mcp.WithBoolean("apply"),
+		mcp.WithString("format", mcp.Enum("markdown", "json")),
+		mcp.WithArray("analyzers", mcp.Items(map[string]any{"type": "string"})),
+	)
+
+	// Helper function to validate arguments given as JSON, as decoded from a tool call
+	validate := func(t testing.TB, arguments string) []string {
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(arguments), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		var messages []string
+		for _, err := range ValidateArguments(tool.InputSchema, decoded) {
+			messages = append(messages, err.Error())
+		}
+		return messages
+	}
+
+	t.Run("valid arguments", func(t *testing.T) {
+		t.Parallel()
+		if errs := validate(t, `{"file_path": "/a.go", "line_number": 3, "apply": false, "format": "json", "analyzers": ["printf"]}`); len(errs) != 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+		errs := validate(t, `{"line_number": "12", "apply": "true", "format": "yaml", "analyzers": "printf, shadow", "file": "/a.go"}`)
+		expected := []string{
+			`argument "file_path" is required and must be a string (Absolute path of the Go file), e.g. "file_path": "..."`,
+			`argument "analyzers" must be an array of strings, got string "printf, shadow", e.g. "analyzers": ["printf","shadow"]`,
+			`argument "apply" must be a boolean, got string "true", e.g. "apply": true`,
+			`argument "file" is not an argument of the tool, which takes analyzers, apply, file_path, format, line_number`,
+			`argument "format" must be one of "markdown", "json", got "yaml", e.g. "format": "markdown"`,
+			`argument "line_number" must be a number, got string "12", e.g. "line_number": 12`,
+		}
+		if strings.Join(errs, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(errs, "\n"))
+		}
+
+		errs = validate(t, `{"file_path": "", "analyzers": ["printf", 1]}`)
+		if len(errs) != 2 || !strings.Contains(errs[0], `"file_path" is required`) || !strings.Contains(errs[1], "got number 1 at index 1") {
+			t.Errorf("Expected the empty path and the invalid item, got %v", errs)
+		}
+	})
+
+	t.Run("tool calls are validated", func(t *testing.T) {
+		t.Parallel()
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      codefixToolName,
+				"arguments": map[string]any{"file_path": "/app/main.go", "line_number": "12"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		expected := "Error: invalid arguments for codefix:\n- argument \"line_number\" must be a number, got string \"12\", e.g. \"line_number\": 12"
+		if text := toolResultText(&result); !result.IsError || text != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, text)
+		}
+	})
+
+	t.Run("tools added later are validated", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer()
+		mcpServer.AddTool(
+			mcp.NewTool("later", mcp.WithNumber("count", mcp.Required())),
+			func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("called"), nil
+			},
+		)
+		encoded, err := toolCallMessage("later", map[string]any{"count": "3"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		expected := "Error: invalid arguments for later:\n- argument \"count\" must be a number, got string \"3\", e.g. \"count\": 3"
+		if text := toolResultText(&result); !result.IsError || text != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, text)
+		}
+	})
+	t.Run("session tools are validated", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer()
+		session := &toolsSession{testSession: testSession{id: "session-tools"}, tools: map[string]server.ServerTool{
+			codefixToolName: {
+				Tool: mcp.NewTool(codefixToolName, mcp.WithBoolean("line_number", mcp.Required())),
+				Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					return mcp.NewToolResultText("called"), nil
+				},
+			},
+		}}
+		ctx := mcpServer.WithContext(context.Background(), session)
+		encoded, err := toolCallMessage(codefixToolName, map[string]any{"line_number": 12})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := mcpServer.HandleMessage(ctx, encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		expected := "Error: invalid arguments for codefix:\n- argument \"line_number\" must be a boolean, got number 12, e.g. \"line_number\": true"
+		if text := toolResultText(&result); !result.IsError || text != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, text)
+		}
+	})
+}
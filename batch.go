@@ -0,0 +1,186 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	batchInspectToolName        = "batch_inspect"
+	batchInspectToolDescription = `Inspects several packages, files or symbols in one call, returning the result of each path in its own section. Packages are loaded once for all paths of the call, so inspecting several symbols of a package is a lot cheaper than separate inspect calls.
+
+The paths support the formats of the inspect tool. A failing path reports its error in its section without failing the others.`
+)
+
+// maxBatchInspectPaths limits the paths of a batch, keeping the output of a call readable
+const maxBatchInspectPaths = 50
+
+// BatchInspectResult is the outcome of inspecting one path of a batch
+type BatchInspectResult struct {
+	Path   string
+	Result *InspectResult
+	Err    error
+}
+
+func AddBatchInspectTool(mcpServer *server.MCPServer) {
+	addBatchInspectTool(mcpServer, "")
+}
+
+// addBatchInspectTool adds the batch_inspect tool, caching loaded packages in cacheDir unless empty
+func addBatchInspectTool(mcpServer *server.MCPServer, cacheDir string) {
+	handleBatchInspect := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		paths, err := request.RequireStringSlice("paths")
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) > maxBatchInspectPaths {
+			return toolErrorResult(fmt.Sprintf(
+				"Error: at most %d paths can be inspected in one call, got %d",
+				maxBatchInspectPaths,
+				len(paths),
+			)), nil
+		}
+
+		workspaceDir := request.GetString("workspace_dir", "")
+		if workspaceDir == "" {
+			return toolErrorResult("Error: workspace_dir is required."), nil
+		}
+		options, err := inspectOptionsFromRequest(request, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		format, err := inspectFormatFromRequest(request)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		options.MetadataCacheDir = cacheDir
+
+		results := InspectBatch(ctx, paths, options)
+		if format == "json" {
+			return batchInspectJSONResult(results), nil
+		}
+		var b strings.Builder
+		failed := 0
+		for i, result := range results {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "=== %s ===\n", result.Path)
+			if result.Err != nil {
+				failed++
+				fmt.Fprintf(&b, "Error inspecting symbol: %v\n", result.Err)
+				continue
+			}
+			b.WriteString(strings.TrimRight(result.Result.String(), "\n") + "\n")
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.NewTextContent(b.String())},
+			// The call only failed when none of the paths could be inspected
+			IsError: failed == len(results),
+		}, nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(batchInspectToolName, append([]mcp.ToolOption{
+		mcp.WithDescription(batchInspectToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithArray(
+			"paths",
+			mcp.Description(fmt.Sprintf(
+				"Paths to analyze in the formats of the inspect tool, at most %d",
+				maxBatchInspectPaths,
+			)),
+			mcp.Items(map[string]any{"type": "string"}),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"workspace_dir",
+			mcp.Description(
+				"Working directory for package resolution and reference finding. Should always be given.",
+			),
+			mcp.Required(),
+		),
+	}, inspectToolOptions()...)...), handleBatchInspect)
+}
+
+// batchInspectJSONResult encodes the results of a batch as a JSON array with the path,
+// the highlighted result or the error of each path
+func batchInspectJSONResult(results []BatchInspectResult) *mcp.CallToolResult {
+	type jsonResult struct {
+		Path   string         `json:"path"`
+		Result *InspectResult `json:"result,omitempty"`
+		Error  string         `json:"error,omitempty"`
+	}
+	encodable := make([]jsonResult, len(results))
+	failed := 0
+	for i, result := range results {
+		encodable[i] = jsonResult{Path: result.Path, Result: result.Result}
+		if result.Err != nil {
+			failed++
+			encodable[i].Error = result.Err.Error()
+			continue
+		}
+		result.Result.Highlight()
+	}
+	encoded, err := json.MarshalIndent(encodable, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("Error encoding results: %v", err))
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.NewTextContent(string(encoded))},
+		IsError: failed == len(results),
+	}
+}
+
+// InspectBatch inspects each path with the options, loading every package once for all
+// of them. The line number and symbol name of the options are replaced by the ones of
+// each path, and the results are in the order of the paths.
+func InspectBatch(ctx context.Context, paths []string, options InspectOptions) []BatchInspectResult {
+	options.loadedPackages = &packageMemo{}
+	results := make([]BatchInspectResult, len(paths))
+	for i, pathStr := range paths {
+		path, lineNumber, symbolName := parseInspectPath(pathStr)
+		pathOptions := options
+		pathOptions.LineNumber = lineNumber
+		pathOptions.SymbolName = symbolName
+		result, err := InspectStructured(ctx, path, pathOptions)
+		results[i] = BatchInspectResult{Path: pathStr, Result: result, Err: err}
+	}
+	return results
+}
+
+// packageMemo remembers loaded packages by path, a nil memo loads them every time
+type packageMemo struct {
+	mu       sync.Mutex
+	packages map[string]*packages.Package
+}
+
+// load returns the package remembered for key, or loads and remembers it with fn
+func (memo *packageMemo) load(key string, fn func() (*packages.Package, error)) (*packages.Package, error) {
+	if memo == nil {
+		return fn()
+	}
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	if pkg, ok := memo.packages[key]; ok {
+		return pkg, nil
+	}
+	pkg, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	if memo.packages == nil {
+		memo.packages = make(map[string]*packages.Package)
+	}
+	memo.packages[key] = pkg
+	return pkg, nil
+}
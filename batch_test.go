@@ -0,0 +1,129 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBatchInspect(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a workspace with a file of two functions
+	createTestWorkspace := func(t testing.TB) string {
+		tempDir := t.TempDir()
+		lines := []string{
+			"package main",              // 1
+			"",                          // 2
+			"// Add sums two numbers",   // 3
+			"func Add(a, b int) int {",  // 4
+			"    return a + b",          // 5
+			"}",                         // 6
+			"",                          // 7
+			"// Sub subtracts b from a", // 8
+			"func Sub(a, b int) int {",  // 9
+			"    return a - b",          // 10
+			"}",                         // 11
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module example.com/calc\n\ngo 1.21\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, "calc.go"), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return tempDir
+	}
+
+	call := func(t testing.TB, mcpServer interface {
+		HandleMessage(context.Context, json.RawMessage) mcp.JSONRPCMessage
+	}, arguments map[string]any) mcp.CallToolResult {
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params":  map[string]any{"name": batchInspectToolName, "arguments": arguments},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		return response.Result.(mcp.CallToolResult)
+	}
+
+	t.Run("per path results", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "calc.go")
+
+		options := DefaultInspectOptions(workspace)
+		options.IncludeReferences = false
+		options.IncludeCallHierarchy = false
+		results := InspectBatch(context.Background(), []string{file + ":4:Add", file + ":9:Sub", file + ":1:Missing"}, options)
+		if len(results) != 3 {
+			t.Fatalf("Expected three results, got %d", len(results))
+		}
+		for i, name := range []string{"Add", "Sub"} {
+			if results[i].Err != nil || results[i].Result.Symbol == nil || results[i].Result.Symbol.Name != name {
+				t.Errorf("Expected %s in result %d, got %+v", name, i, results[i])
+			}
+		}
+		if results[2].Err == nil {
+			t.Errorf("Expected an error for the missing symbol, got %+v", results[2].Result)
+		}
+	})
+
+	t.Run("batch inspect tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "calc.go")
+
+		result := call(t, NewMCPServer(), map[string]any{
+			"paths":                  []any{file + ":4:Add", "calc.go:9:Sub", file + ":1:Missing"},
+			"workspace_dir":          workspace,
+			"include_references":     false,
+			"include_call_hierarchy": false,
+		})
+		text := toolResultText(&result)
+		if result.IsError {
+			t.Fatalf("Expected partial failures not to fail the call, got: %s", text)
+		}
+		for _, expected := range []string{
+			"=== " + file + ":4:Add ===\n",
+			"=== calc.go:9:Sub ===\n",
+			"Add sums two numbers",
+			"Sub subtracts b from a",
+			"=== " + file + ":1:Missing ===\nError inspecting symbol: symbol 'Missing' not found in file",
+		} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the result, got: %s", expected, text)
+			}
+		}
+
+		result = call(t, NewMCPServer(), map[string]any{
+			"paths":         []any{file + ":1:Missing"},
+			"workspace_dir": workspace,
+		})
+		if !result.IsError {
+			t.Errorf("Expected an error when all paths fail, got: %s", toolResultText(&result))
+		}
+	})
+
+	t.Run("paths outside the allowlist", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		other := createTestWorkspace(t)
+
+		result := call(t, NewMCPServer(WithWorkspaceAllowlist(workspace)), map[string]any{
+			"paths":         []any{filepath.Join(workspace, "calc.go"), filepath.Join(other, "calc.go")},
+			"workspace_dir": workspace,
+		})
+		if text := toolResultText(&result); !result.IsError || !strings.Contains(text, "paths") {
+			t.Errorf("Expected the path outside the allowlist to be rejected, got: %s", text)
+		}
+	})
+}
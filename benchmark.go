@@ -0,0 +1,468 @@
+package go_mcp_tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	benchmarkToolName        = "benchmark"
+	benchmarkToolDescription = `Runs the benchmarks of a Go package count times with go test -bench -benchmem and reports the median of every metric (ns/op, B/op, allocs/op and custom metrics) with its variation across runs.
+
+With baseline_ref the benchmarks also run on a copy of the git repository at that ref, e.g. main or HEAD, and every metric is compared like benchstat does: the change of the median and whether it is significant by a Mann-Whitney U test (p < 0.05). Insignificant changes are shown as ~, so a regression is only reported when the runs clearly differ. More runs detect smaller changes; 5 or more are recommended for comparisons.`
+)
+
+// benchmarkAlpha is the significance level of the comparison, as used by benchstat
+const benchmarkAlpha = 0.05
+
+func AddBenchmarkTool(mcpServer *server.MCPServer) {
+	handleBenchmark := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		var options BenchmarkOptions
+		var err error
+		options.WorkspaceDir, err = request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		options.Package = request.GetString("package", "")
+		options.Bench = request.GetString("bench", "")
+		options.Count = request.GetInt("count", 0)
+		options.Benchtime = request.GetString("benchtime", "")
+		options.BaselineRef = request.GetString("baseline_ref", "")
+
+		report, err := RunBenchmarks(ctx, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error running benchmarks: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		benchmarkToolName,
+		mcp.WithDescription(benchmarkToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module, the package is relative to it"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Package pattern to benchmark, e.g. ./internal/parser or ./..."),
+			mcp.DefaultString("."),
+		),
+		mcp.WithString("bench",
+			mcp.Description("Regular expression selecting the benchmarks, as go test -bench"),
+			mcp.DefaultString("."),
+		),
+		mcp.WithNumber("count",
+			mcp.Description("Number of runs of every benchmark"),
+			mcp.DefaultNumber(5),
+		),
+		mcp.WithString("benchtime",
+			mcp.Description("Run time or iterations of every run, e.g. 100ms or 1000x, as go test -benchtime"),
+		),
+		mcp.WithString("baseline_ref",
+			mcp.Description("Git ref to compare against, e.g. main. Without it the benchmarks are only run on the working tree"),
+		),
+	), handleBenchmark)
+}
+
+// BenchmarkOptions selects the benchmarks to run
+type BenchmarkOptions struct {
+	WorkspaceDir string
+	// Package is the package pattern relative to WorkspaceDir, . by default
+	Package string
+	// Bench is the regular expression selecting benchmarks, . by default
+	Bench string
+	// Count is the number of runs of every benchmark, 5 by default
+	Count     int
+	Benchtime string
+	// BaselineRef is the git ref to compare against, no comparison when empty
+	BaselineRef string
+}
+
+// BenchmarkReport holds the metrics of the benchmarks, compared to a baseline when it was run
+type BenchmarkReport struct {
+	Package     string            `json:"package"`
+	Bench       string            `json:"bench"`
+	Count       int               `json:"count"`
+	BaselineRef string            `json:"baseline_ref,omitempty"`
+	Metrics     []BenchmarkMetric `json:"metrics"`
+}
+
+// BenchmarkMetric is a metric of a benchmark, e.g. the ns/op of BenchmarkParse
+type BenchmarkMetric struct {
+	Package string `json:"package"`
+	// Name is the benchmark name without the GOMAXPROCS suffix, e.g. BenchmarkParse/small
+	Name     string            `json:"name"`
+	Unit     string            `json:"unit"`
+	Current  *BenchmarkSamples `json:"current,omitempty"`
+	Baseline *BenchmarkSamples `json:"baseline,omitempty"`
+	// Delta is the change of the median from the baseline in percent, 0 when the
+	// baseline median is 0
+	Delta float64 `json:"delta,omitempty"`
+	// P is the p-value of the Mann-Whitney U test of the samples
+	P           float64 `json:"p,omitempty"`
+	Significant bool    `json:"significant,omitempty"`
+}
+
+// BenchmarkSamples are the values of a metric in every run
+type BenchmarkSamples struct {
+	Values []float64 `json:"values"`
+	Median float64   `json:"median"`
+	// Variation is the largest distance of a value from the median in percent of it
+	Variation float64 `json:"variation"`
+}
+
+// benchmarkKey identifies a metric across the current and baseline runs
+type benchmarkKey struct {
+	pkg  string
+	name string
+	unit string
+}
+
+// benchmarkProcs matches the GOMAXPROCS suffix of benchmark names, e.g. -8
+var benchmarkProcs = regexp.MustCompile(`-\d+$`)
+
+// RunBenchmarks runs the benchmarks selected by options, and those of the baseline ref
+// when it is set, and compares their metrics
+func RunBenchmarks(ctx context.Context, options BenchmarkOptions) (*BenchmarkReport, error) {
+	if !filepath.IsAbs(options.WorkspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", options.WorkspaceDir)
+	}
+	if options.Package == "" {
+		options.Package = "."
+	}
+	if options.Bench == "" {
+		options.Bench = "."
+	}
+	if options.Count <= 0 {
+		options.Count = 5
+	}
+	report := &BenchmarkReport{
+		Package:     options.Package,
+		Bench:       options.Bench,
+		Count:       options.Count,
+		BaselineRef: options.BaselineRef,
+	}
+
+	metrics := make(map[benchmarkKey]*BenchmarkMetric)
+	var order []benchmarkKey
+	collect := func(dir string, baseline bool) error {
+		samples, err := runBenchmarks(ctx, dir, options)
+		if err != nil {
+			return err
+		}
+		for _, sample := range samples {
+			metric, ok := metrics[sample.key]
+			if !ok {
+				metric = &BenchmarkMetric{Package: sample.key.pkg, Name: sample.key.name, Unit: sample.key.unit}
+				metrics[sample.key] = metric
+				order = append(order, sample.key)
+			}
+			target := &metric.Current
+			if baseline {
+				target = &metric.Baseline
+			}
+			if *target == nil {
+				*target = &BenchmarkSamples{}
+			}
+			(*target).Values = append((*target).Values, sample.value)
+		}
+		return nil
+	}
+
+	if options.BaselineRef != "" {
+		dir, cleanup, err := checkoutRef(ctx, options.WorkspaceDir, options.BaselineRef)
+		if err != nil {
+			return nil, err
+		}
+		err = collect(dir, true)
+		cleanup()
+		if err != nil {
+			return nil, fmt.Errorf("at %s: %w", options.BaselineRef, err)
+		}
+	}
+	if err := collect(options.WorkspaceDir, false); err != nil {
+		return nil, err
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no benchmarks matching %q in %s", options.Bench, options.Package)
+	}
+
+	for _, key := range order {
+		metric := metrics[key]
+		for _, samples := range []*BenchmarkSamples{metric.Current, metric.Baseline} {
+			if samples != nil {
+				samples.summarize()
+			}
+		}
+		if metric.Current != nil && metric.Baseline != nil {
+			if metric.Baseline.Median != 0 {
+				metric.Delta = (metric.Current.Median - metric.Baseline.Median) / metric.Baseline.Median * 100
+			}
+			metric.P = mannWhitneyP(metric.Baseline.Values, metric.Current.Values)
+			metric.Significant = metric.P < benchmarkAlpha
+		}
+		report.Metrics = append(report.Metrics, *metric)
+	}
+	// Metrics are grouped by unit, time first like benchstat
+	unitOrder := func(unit string) int {
+		index := slices.Index([]string{"ns/op", "B/op", "allocs/op"}, unit)
+		if index < 0 {
+			return 3
+		}
+		return index
+	}
+	slices.SortStableFunc(report.Metrics, func(a, b BenchmarkMetric) int {
+		if unitOrder(a.Unit) != unitOrder(b.Unit) {
+			return unitOrder(a.Unit) - unitOrder(b.Unit)
+		}
+		return strings.Compare(a.Unit, b.Unit)
+	})
+	return report, nil
+}
+
+// benchmarkSample is one value of a metric in a run
+type benchmarkSample struct {
+	key   benchmarkKey
+	value float64
+}
+
+// runBenchmarks runs go test -bench in dir and parses the metrics of its output
+func runBenchmarks(ctx context.Context, dir string, options BenchmarkOptions) ([]benchmarkSample, error) {
+	args := []string{"test", "-run", "^$", "-bench", options.Bench, "-benchmem", "-count", strconv.Itoa(options.Count)}
+	if options.Benchtime != "" {
+		args = append(args, "-benchtime", options.Benchtime)
+	}
+	args = append(args, options.Package)
+	output, err := commandOutput(ctx, dir, nil, "go", args...)
+	if err != nil {
+		return nil, fmt.Errorf("go %s failed: %v\n%s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return parseBenchmarkOutput(string(output)), nil
+}
+
+// parseBenchmarkOutput parses the result lines of go test -bench, e.g.
+// BenchmarkParse-8   1000   1234 ns/op   56 B/op   2 allocs/op
+func parseBenchmarkOutput(output string) []benchmarkSample {
+	var samples []benchmarkSample
+	pkg := ""
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(value)
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := benchmarkProcs.ReplaceAllString(fields[0], "")
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			samples = append(samples, benchmarkSample{key: benchmarkKey{pkg: pkg, name: name, unit: fields[i+1]}, value: value})
+		}
+	}
+	return samples
+}
+
+// checkoutRef extracts the git repository of workspaceDir at ref into a temporary
+// directory and returns the directory corresponding to workspaceDir in it
+func checkoutRef(ctx context.Context, workspaceDir string, ref string) (string, func(), error) {
+	repoRoot, err := gitRepoRoot(ctx, workspaceDir)
+	if err != nil {
+		return "", nil, err
+	}
+	rel, err := filepath.Rel(resolveSymlinks(repoRoot), resolveSymlinks(workspaceDir))
+	if err != nil {
+		return "", nil, err
+	}
+	archive, err := runGit(ctx, repoRoot, "archive", "--format=tar", ref)
+	if err != nil {
+		return "", nil, err
+	}
+	dir, err := os.MkdirTemp("", "go-mcp-tools-benchmark-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	if err := extractTar(strings.NewReader(archive), dir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to extract %s: %w", ref, err)
+	}
+	return filepath.Join(dir, rel), cleanup, nil
+}
+
+// summarize sets the median and variation of the values
+func (samples *BenchmarkSamples) summarize() {
+	sorted := slices.Clone(samples.Values)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	samples.Median = sorted[middle]
+	if len(sorted)%2 == 0 {
+		samples.Median = (sorted[middle-1] + sorted[middle]) / 2
+	}
+	if samples.Median != 0 {
+		spread := math.Max(sorted[len(sorted)-1]-samples.Median, samples.Median-sorted[0])
+		samples.Variation = spread / math.Abs(samples.Median) * 100
+	}
+}
+
+// mannWhitneyP returns the two-sided p-value of the Mann-Whitney U test of whether the
+// samples come from the same distribution. Without ties it is exact, with ties the normal
+// approximation with tie correction is used.
+func mannWhitneyP(a, b []float64) float64 {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	// Ranks of the merged samples, ties sharing their mean rank
+	type value struct {
+		value float64
+		first bool
+	}
+	merged := make([]value, 0, n1+n2)
+	for _, v := range a {
+		merged = append(merged, value{v, true})
+	}
+	for _, v := range b {
+		merged = append(merged, value{v, false})
+	}
+	slices.SortFunc(merged, func(x, y value) int {
+		switch {
+		case x.value < y.value:
+			return -1
+		case x.value > y.value:
+			return 1
+		}
+		return 0
+	})
+	rankSum, tieCorrection, ties := 0.0, 0.0, false
+	for i := 0; i < len(merged); {
+		j := i
+		for j < len(merged) && merged[j].value == merged[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if merged[k].first {
+				rankSum += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieCorrection += t*t*t - t
+		}
+		i = j
+	}
+	u := rankSum - float64(n1*(n1+1))/2
+	u = math.Min(u, float64(n1*n2)-u)
+
+	if !ties {
+		// counts[i][j][k] is the number of orderings of i and j values with U = k, built
+		// up in place over i
+		counts := make([][]float64, n2+1)
+		for j := range counts {
+			counts[j] = []float64{1}
+		}
+		for i := 1; i <= n1; i++ {
+			next := make([][]float64, n2+1)
+			next[0] = []float64{1}
+			for j := 1; j <= n2; j++ {
+				next[j] = make([]float64, i*j+1)
+				// The largest value is either the i-th of a, beating all j values of b, or of b
+				for k, count := range counts[j] {
+					next[j][k+j] += count
+				}
+				for k, count := range next[j-1] {
+					next[j][k] += count
+				}
+			}
+			counts = next
+		}
+		below, total := 0.0, 0.0
+		for k, count := range counts[n2] {
+			total += count
+			if float64(k) <= u {
+				below += count
+			}
+		}
+		return math.Min(1, 2*below/total)
+	}
+
+	n := float64(n1 + n2)
+	sigma := math.Sqrt(float64(n1*n2) / 12 * ((n + 1) - tieCorrection/(n*(n-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := (float64(n1*n2)/2 - u - 0.5) / sigma
+	return math.Min(1, math.Erfc(math.Max(z, 0)/math.Sqrt2))
+}
+
+// formatBenchmarkValue formats a metric with 4 significant digits without exponents
+func formatBenchmarkValue(value float64) string {
+	if value == math.Trunc(value) || math.Abs(value) >= 1000 {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(value, 'f', max(0, 3-int(math.Floor(math.Log10(math.Abs(value))))), 64)
+}
+
+func (report *BenchmarkReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Benchmarks %s of %s, %d runs each", report.Bench, report.Package, report.Count)
+	if report.BaselineRef != "" {
+		fmt.Fprintf(&b, ", compared to %s", report.BaselineRef)
+	}
+	b.WriteString(":\n")
+	samples := func(samples *BenchmarkSamples) string {
+		return fmt.Sprintf("%s ± %.0f%%", formatBenchmarkValue(samples.Median), samples.Variation)
+	}
+	unit := ""
+	for _, metric := range report.Metrics {
+		if metric.Unit != unit {
+			unit = metric.Unit
+			fmt.Fprintf(&b, "\n%s:\n", unit)
+		}
+		fmt.Fprintf(&b, "  %s %s: ", metric.Package, metric.Name)
+		switch {
+		case report.BaselineRef == "":
+			fmt.Fprintf(&b, "%s (n=%d)\n", samples(metric.Current), len(metric.Current.Values))
+		case metric.Current == nil:
+			fmt.Fprintf(&b, "%s at %s, missing now\n", samples(metric.Baseline), report.BaselineRef)
+		case metric.Baseline == nil:
+			fmt.Fprintf(&b, "%s, missing at %s\n", samples(metric.Current), report.BaselineRef)
+		default:
+			delta := "~"
+			switch {
+			case metric.Significant && metric.Baseline.Median == 0:
+				delta = fmt.Sprintf("%+.2f%%", math.Copysign(math.Inf(1), metric.Current.Median))
+			case metric.Significant:
+				delta = fmt.Sprintf("%+.2f%%", metric.Delta)
+			}
+			fmt.Fprintf(&b, "%s -> %s  %s (p=%.3f n=%d+%d)\n", samples(metric.Baseline), samples(metric.Current), delta,
+				metric.P, len(metric.Baseline.Values), len(metric.Current.Values))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBenchmark(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/bench", "", "go 1.22", ""},
+		"sum.go": {
+			"package bench",
+			"",
+			"func Sum(values []int) int {",
+			"	total := 0",
+			"	for _, v := range values {",
+			"		total += v",
+			"	}",
+			"	return total",
+			"}",
+			"",
+		},
+		"sum_test.go": {
+			"package bench",
+			"",
+			"import \"testing\"",
+			"",
+			"var values = []int{1, 2, 3}",
+			"",
+			"func BenchmarkSum(b *testing.B) {",
+			"	for i := 0; i < b.N; i++ {",
+			"		Sum(values)",
+			"	}",
+			"}",
+			"",
+			"func TestNotRun(t *testing.T) { t.Fatal(\"tests are not run\") }",
+			"",
+		},
+	})
+	runTestGit(t, workspace, "init", "-q")
+	runTestGit(t, workspace, "add", "-A")
+	runTestGit(t, workspace, "commit", "-q", "-m", "initial")
+
+	// The working tree allocates in every call, a change the runs agree on
+	writeTestFiles(t, workspace, map[string][]string{
+		"sum.go": {
+			"package bench",
+			"",
+			"var sink []int",
+			"",
+			"func Sum(values []int) int {",
+			"	sink = append([]int(nil), values...)",
+			"	total := 0",
+			"	for _, v := range sink {",
+			"		total += v",
+			"	}",
+			"	return total",
+			"}",
+			"",
+		},
+	})
+
+	t.Run("against baseline", func(t *testing.T) {
+		t.Parallel()
+		report, err := RunBenchmarks(context.Background(), BenchmarkOptions{
+			WorkspaceDir: workspace,
+			Count:        5,
+			Benchtime:    "100x",
+			BaselineRef:  "HEAD",
+		})
+		if err != nil {
+			t.Fatalf("Failed to run benchmarks: %v", err)
+		}
+		var units []string
+		for _, metric := range report.Metrics {
+			units = append(units, metric.Unit)
+			if metric.Package != "example.com/bench" || metric.Name != "BenchmarkSum" ||
+				metric.Current == nil || metric.Baseline == nil || len(metric.Current.Values) != 5 {
+				t.Errorf("Unexpected metric %+v", metric)
+			}
+			if metric.Unit == "allocs/op" && (metric.Baseline.Median != 0 || metric.Current.Median != 1 || !metric.Significant) {
+				t.Errorf("Expected a significant change from 0 to 1 allocs/op, got %+v", metric)
+			}
+		}
+		if strings.Join(units, ",") != "ns/op,B/op,allocs/op" {
+			t.Errorf("Expected the ns/op, B/op and allocs/op metrics, got %v", units)
+		}
+		text := report.String()
+		if !strings.HasPrefix(text, "Benchmarks . of ., 5 runs each, compared to HEAD:\n\nns/op:\n  example.com/bench BenchmarkSum: ") ||
+			!strings.Contains(text, "\nallocs/op:\n  example.com/bench BenchmarkSum: 0 ± 0% -> 1 ± 0%  +Inf%") {
+			t.Errorf("Unexpected report:\n%s", text)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		if _, err := RunBenchmarks(context.Background(), BenchmarkOptions{WorkspaceDir: workspace, Bench: "Missing", Count: 1}); err == nil ||
+			!strings.Contains(err.Error(), "no benchmarks matching") {
+			t.Errorf("Expected an error for no matching benchmarks, got: %v", err)
+		}
+		if _, err := RunBenchmarks(context.Background(), BenchmarkOptions{WorkspaceDir: workspace, Count: 1, BaselineRef: "missing-ref"}); err == nil {
+			t.Error("Expected an error for an unknown ref")
+		}
+	})
+
+	t.Run("output parsing", func(t *testing.T) {
+		t.Parallel()
+		samples := parseBenchmarkOutput(strings.Join([]string{
+			"goos: linux",
+			"pkg: example.com/a",
+			"BenchmarkParse/small-8   	 1000	      1234 ns/op	  12.50 MB/s	     56 B/op	       2 allocs/op",
+			"BenchmarkParse/small-8   	--- FAIL: BenchmarkParse",
+			"pkg: example.com/b",
+			"BenchmarkRun   	 10	      99.5 ns/op",
+			"PASS",
+		}, "\n"))
+		var parsed []string
+		for _, sample := range samples {
+			parsed = append(parsed, sample.key.pkg+" "+sample.key.name+" "+formatBenchmarkValue(sample.value)+" "+sample.key.unit)
+		}
+		expected := []string{
+			"example.com/a BenchmarkParse/small 1234 ns/op",
+			"example.com/a BenchmarkParse/small 12.50 MB/s",
+			"example.com/a BenchmarkParse/small 56 B/op",
+			"example.com/a BenchmarkParse/small 2 allocs/op",
+			"example.com/b BenchmarkRun 99.50 ns/op",
+		}
+		if strings.Join(parsed, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(parsed, "\n"))
+		}
+	})
+
+	t.Run("mann-whitney", func(t *testing.T) {
+		t.Parallel()
+		for _, test := range []struct {
+			a, b     []float64
+			expected float64
+		}{
+			// All orderings of 5+5 values but one are less extreme in each direction
+			{[]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 2.0 / 252},
+			{[]float64{6, 7, 8, 9, 10}, []float64{1, 2, 3, 4, 5}, 2.0 / 252},
+			{[]float64{1, 3, 5}, []float64{2, 4, 6}, 0.7},
+			{[]float64{1, 1, 1}, []float64{1, 1, 1}, 1},
+			{nil, []float64{1}, 1},
+		} {
+			if p := mannWhitneyP(test.a, test.b); math.Abs(p-test.expected) > 1e-9 {
+				t.Errorf("Expected p=%f for %v and %v, got %f", test.expected, test.a, test.b, p)
+			}
+		}
+	})
+}
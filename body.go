@@ -0,0 +1,159 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	bodyToolName        = "body"
+	bodyToolDescription = `Returns exactly the source of a Go function or method, from the func keyword to the closing brace, without docs, references or call hierarchies. The cheapest way to read the code of a function whose location is known.
+
+Methods are named Type.Method. A plain name selects a function, or a method when exactly one method has the name.`
+)
+
+func AddBodyTool(mcpServer *server.MCPServer) {
+	handleBody := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		path, err := request.RequireString("path")
+		if err != nil {
+			return nil, err
+		}
+		symbol, err := request.RequireString("symbol")
+		if err != nil {
+			return nil, err
+		}
+		lineNumbers := request.GetBool("line_numbers", false)
+
+		result, err := FunctionBody(path, symbol, lineNumbers)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error reading function: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		bodyToolName,
+		mcp.WithDescription(bodyToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of the Go file or package directory declaring the function"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Name of the function, or Type.Method for a method"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("line_numbers",
+			mcp.Description("Whether to prefix each line with its line number in the file"),
+			mcp.DefaultBool(false),
+		),
+	), handleBody)
+}
+
+// FunctionBody returns the source of the function or method named symbol, as Name or
+// Type.Method, declared in the Go file or package directory at path
+func FunctionBody(path string, symbol string, lineNumbers bool) (string, error) {
+	files, err := bodySourceFiles(path)
+	if err != nil {
+		return "", err
+	}
+	typeName, name, isMethod := strings.Cut(strings.TrimPrefix(symbol, "*"), ".")
+	if !isMethod {
+		name, typeName = typeName, ""
+	}
+
+	var functions, methods []*ast.FuncDecl
+	fset := token.NewFileSet()
+	for _, file := range files {
+		parsed, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", classifyErrorf(ErrSyntaxErrors, "failed to parse %s: %w", file, err)
+		}
+		for _, decl := range parsed.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != name || fn.Body == nil {
+				continue
+			}
+			switch {
+			case fn.Recv == nil:
+				functions = append(functions, fn)
+			case !isMethod || receiverTypeName(fn.Recv.List[0].Type) == typeName:
+				methods = append(methods, fn)
+			}
+		}
+	}
+
+	var fn *ast.FuncDecl
+	switch {
+	case !isMethod && len(functions) > 0:
+		fn = functions[0]
+	case isMethod && len(methods) > 0:
+		fn = methods[0]
+	case !isMethod && len(methods) == 1:
+		fn = methods[0]
+	case !isMethod && len(methods) > 1:
+		var receivers []string
+		for _, method := range methods {
+			receivers = append(receivers, receiverTypeName(method.Recv.List[0].Type)+"."+name)
+		}
+		return "", fmt.Errorf("'%s' is ambiguous, use one of %s", symbol, strings.Join(receivers, ", "))
+	default:
+		return "", classifyErrorf(ErrSymbolNotFound, "no function or method '%s' found in %s", symbol, path)
+	}
+
+	start := fset.Position(fn.Pos())
+	end := fset.Position(fn.End())
+	content, err := os.ReadFile(start.Filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", start.Filename, err)
+	}
+	// The source starts at the beginning of its line to keep the indentation of the lines aligned
+	lineStart := start.Offset - (start.Column - 1)
+	source := string(content[lineStart:end.Offset])
+	if !lineNumbers {
+		return source, nil
+	}
+
+	width := len(strconv.Itoa(end.Line))
+	var b strings.Builder
+	for i, line := range strings.Split(source, "\n") {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%*d\t%s", width, start.Line+i, line)
+	}
+	return b.String(), nil
+}
+
+// bodySourceFiles returns the Go file at path, or the Go files of the package directory at path
+func bodySourceFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	// Glob sorts the files, so the first declaration found is deterministic
+	files, err := filepath.Glob(filepath.Join(path, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", path)
+	}
+	return files, nil
+}
@@ -0,0 +1,115 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFunctionBody(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a package with functions and methods spread over two files
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"a.go": {
+				"package shapes",                       // 1
+				"",                                     // 2
+				"// Area returns the area of a square", // 3
+				"func Area(side int) int {",            // 4
+				"\treturn side * side",                 // 5
+				"}",                                    // 6
+				"",                                     // 7
+				"type Square struct{ side int }",       // 8
+				"",                                     // 9
+				"func (s *Square) Grow() { s.side++ }", // 10
+			},
+			"b.go": {
+				"package shapes",                        // 1
+				"",                                      // 2
+				"type Circle struct{ radius int }",      // 3
+				"",                                      // 4
+				"func (c Circle) Grow() { c.radius++ }", // 5
+				"",                                      // 6
+				"func (c Circle) Radius() int {",        // 7
+				"\treturn c.radius",                     // 8
+				"}",                                     // 9
+			},
+		})
+	}
+
+	t.Run("functions and methods", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		for _, test := range []struct {
+			path, symbol string
+			expected     string
+		}{
+			{workspace, "Area", "func Area(side int) int {\n\treturn side * side\n}"},
+			{filepath.Join(workspace, "a.go"), "Area", "func Area(side int) int {\n\treturn side * side\n}"},
+			{workspace, "Square.Grow", "func (s *Square) Grow() { s.side++ }"},
+			{workspace, "*Square.Grow", "func (s *Square) Grow() { s.side++ }"},
+			{workspace, "Circle.Grow", "func (c Circle) Grow() { c.radius++ }"},
+			{workspace, "Radius", "func (c Circle) Radius() int {\n\treturn c.radius\n}"},
+		} {
+			source, err := FunctionBody(test.path, test.symbol, false)
+			if err != nil || source != test.expected {
+				t.Errorf("Expected the source of %s:\n%s\ngot (%v):\n%s", test.symbol, test.expected, err, source)
+			}
+		}
+	})
+
+	t.Run("line numbers", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		source, err := FunctionBody(workspace, "Circle.Radius", true)
+		if err != nil {
+			t.Fatalf("Failed to read method: %v", err)
+		}
+		if expected := "7\tfunc (c Circle) Radius() int {\n8\t\treturn c.radius\n9\t}"; source != expected {
+			t.Errorf("Expected numbered lines:\n%s\ngot:\n%s", expected, source)
+		}
+	})
+
+	t.Run("missing and ambiguous", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		if _, err := FunctionBody(workspace, "Grow", false); err == nil || !strings.Contains(err.Error(), "use one of Square.Grow, Circle.Grow") {
+			t.Errorf("Expected an ambiguity error, got: %v", err)
+		}
+		if _, err := FunctionBody(workspace, "Square.Radius", false); !errors.Is(err, ErrSymbolNotFound) {
+			t.Errorf("Expected a symbol not found error, got: %v", err)
+		}
+	})
+
+	t.Run("body tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      bodyToolName,
+				"arguments": map[string]any{"path": workspace, "symbol": "Area", "line_numbers": true},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || text != "4\tfunc Area(side int) int {\n5\t\treturn side * side\n6\t}" {
+			t.Errorf("Expected the numbered source of Area, got: %s", text)
+		}
+	})
+}
@@ -1,77 +1,420 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	go_mcp_tools "github.com/adriansahlman/go-mcp-tools"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		printUsage()
+	if err := newRootCommand().Execute(); err != nil {
 		os.Exit(1)
 	}
+}
 
-	command := os.Args[1]
-	switch command {
-	case "server":
-		runServer(os.Args[2:])
-	case "help", "-h", "--help":
-		printUsage()
-	default:
-		fmt.Printf("Unknown command: %s\n\n", command)
-		printUsage()
-		os.Exit(1)
+// newRootCommand returns the go-mcp-tools command with all subcommands. Subcommands
+// parse their flags with cobra, which also provides the completion command emitting
+// bash, zsh, fish and PowerShell completions.
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "go-mcp-tools",
+		Short: "Model Context Protocol tools for Go development",
+		Long: "Go MCP Tools - Model Context Protocol tools for Go development.\n\n" +
+			"May be compiled or run directly with go run cmd/main.go.",
+		Example: `  # Start stdio server
+  go-mcp-tools server
+
+  # Install the bash completions for the current user
+  go-mcp-tools completion bash > ~/.local/share/bash-completion/completions/go-mcp-tools
+
+  # Generate the man pages
+  go-mcp-tools man ./man`,
+		SilenceUsage: true,
+	}
+	root.AddCommand(
+		newServerCommand(),
+		newReplayCommand(),
+		newEvalCommand(),
+		newREPLCommand(),
+		newManCommand(root),
+	)
+	return root
+}
+
+func newServerCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "server [flags]",
+		Short: "Start the MCP server",
+		Long: "Start the MCP server on stdio, HTTP or JSON lines, or the gRPC service of " +
+			"proto/tools.proto.",
+		Example: `  # Start stdio server
+  go-mcp-tools server
+
+  # Start HTTP server
+  go-mcp-tools server --transport http --port 9000
+
+  # Start gRPC server
+  go-mcp-tools server --transport grpc --port 9090
+
+  # Record a session to replay it later
+  go-mcp-tools server --record session.jsonl
+
+  # Call a tool from a shell script
+  echo '{"tool": "inspect", "arguments": {"path": "./pkg", "workspace_dir": "/repo"}}' | \
+    go-mcp-tools server --transport jsonl`,
+		Args: cobra.NoArgs,
+	}
+	fs := cmd.Flags()
+
+	transport := fs.String("transport", "stdio", "Transport type (stdio, http, grpc or jsonl)")
+	host := fs.String("host", "localhost", "Host for HTTP and gRPC transports")
+	port := fs.String("port", "8080", "Port for HTTP and gRPC transports")
+	timeout := fs.Duration("timeout", 0, "Maximum duration of a tool call, 0 means no limit")
+	disabledTools := fs.StringArray("disable-tool", nil, "Disable a tool by name (can be repeated)")
+	workspaces := fs.StringArray("allow-workspace", nil, "Only allow tool calls on paths inside this directory (can be repeated)")
+	maxMutatingCalls := fs.Int("max-mutating-calls", 0, "Maximum calls of tools modifying files per session, 0 means no limit")
+	maxTestRuns := fs.Int("max-test-runs", 0, "Maximum calls of tools running tests per session, 0 means no limit")
+	shadow := fs.Bool("shadow", false, "Run mutating tools on a copy of the module and only keep changes that build and pass tests")
+	shadowSkipTests := fs.Bool("shadow-skip-tests", false, "Only require the shadow copy to build, without running tests")
+	shadowMaxFiles := fs.Int("shadow-max-files", go_mcp_tools.DefaultShadowMaxFiles, "Maximum number of files of a module copied for --shadow, 0 for unlimited")
+	shadowMaxMB := fs.Int64("shadow-max-mb", go_mcp_tools.DefaultShadowMaxBytes>>20, "Maximum size in MB of a module copied for --shadow, 0 for unlimited")
+	commitTool := fs.Bool("commit-tool", false, "Enable the commit_changes tool committing the files modified by tools in a session")
+	commitAuthor := fs.String("commit-author", "", "Author name of commits made by commit_changes (default: git config)")
+	commitEmail := fs.String("commit-email", "", "Author email of commits made by commit_changes (default: git config)")
+	worktrees := fs.Bool("worktrees", false, "Give every session its own git worktree that mutating tools change instead of the repository")
+	worktreeBase := fs.String("worktree-base", "", "Branch or commit the session worktrees are created from (default: HEAD)")
+	conflicts := fs.Bool("conflicts", false, "Refuse mutating tools when files the session read or modified changed on disk since")
+	conflictsWarnOnly := fs.Bool("conflicts-warn-only", false, "Run mutating tools despite files changed on disk, with a warning")
+	packageMetadataCache := fs.String("package-metadata-cache", "", "Directory of a disk cache of the metadata of packages loaded by inspect (default: disabled)")
+	fileCacheFiles := fs.Int("file-cache-files", go_mcp_tools.DefaultFileCacheLimits.MaxFiles, "Maximum number of parsed files kept in memory, 0 for unlimited")
+	fileCacheMB := fs.Int64("file-cache-mb", go_mcp_tools.DefaultFileCacheLimits.MaxBytes>>20, "Maximum source size in MB of the parsed files kept in memory, 0 for unlimited")
+	goplsConcurrency := fs.Int("gopls-concurrency", go_mcp_tools.DefaultGoplsConcurrency, "Maximum number of gopls processes running at the same time, 0 for unlimited")
+	execTimeout := fs.Duration("exec-timeout", go_mcp_tools.DefaultSandboxPolicy.Timeout, "Maximum duration of a go, git, gopls or linter subprocess, 0 means no limit")
+	execCPU := fs.Duration("exec-cpu", go_mcp_tools.DefaultSandboxPolicy.CPUTime, "Maximum CPU time of every subprocess, 0 means no limit")
+	execMemoryMB := fs.Int64("exec-memory-mb", 0, "Maximum virtual memory in MB of every subprocess, 0 means no limit")
+	diagnostics := fs.String("diagnostics", "", "Directory of the module whose build errors the diagnostics://workspace resource reports")
+	symbolResources := fs.String("symbol-resources", "", "Workspace directory of the gosym:// and gopkg:// resource templates inspecting symbols and packages")
+	tabWidth := fs.Int("tab-width", 0, "Expand tabs in the results of read-only tools to this many spaces, 0 keeps tabs")
+	maxLineWidth := fs.Int("max-line-width", 0, "Maximum characters per line in the results of read-only tools, 0 for unlimited")
+	truncateLines := fs.Bool("truncate-lines", false, "Truncate lines longer than --max-line-width instead of wrapping them")
+	minProtocolVersion := fs.String("min-protocol-version", "", "Reject clients negotiating an older MCP protocol version, e.g. 2025-03-26")
+	requireCapabilities := fs.StringArray("require-capability", nil, "Reject clients without this capability: roots, sampling or an experimental capability (can be repeated)")
+	requireProgress := fs.String("require-progress", "", "Reject calls without a progress token of tools of this cost level or higher (low, medium or high)")
+	record := fs.String("record", "", "Record all tool calls and results to this file")
+
+	cmd.RegisterFlagCompletionFunc("transport", cobra.FixedCompletions(
+		[]string{"stdio", "http", "grpc", "jsonl"},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	for _, name := range []string{"allow-workspace", "package-metadata-cache", "diagnostics", "symbol-resources"} {
+		cmd.MarkFlagDirname(name)
+	}
+	cmd.RegisterFlagCompletionFunc("require-progress", cobra.FixedCompletions(
+		[]string{"low", "medium", "high"},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	cmd.MarkFlagFilename("record", "jsonl")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		options := []go_mcp_tools.Option{
+			go_mcp_tools.WithoutTools(*disabledTools...),
+			go_mcp_tools.WithTimeout(*timeout),
+			go_mcp_tools.WithWorkspaceAllowlist(*workspaces...),
+		}
+		if *maxMutatingCalls > 0 || *maxTestRuns > 0 {
+			options = append(options, go_mcp_tools.WithQuotas(go_mcp_tools.Quotas{
+				MaxMutatingCalls: *maxMutatingCalls,
+				MaxTestRuns:      *maxTestRuns,
+			}))
+		}
+		if *shadow {
+			shadowOptions := go_mcp_tools.ShadowOptions{
+				SkipTests: *shadowSkipTests,
+				MaxFiles:  *shadowMaxFiles,
+				MaxBytes:  *shadowMaxMB << 20,
+			}
+			// Zero means the defaults in ShadowOptions but no limit for the flags
+			if shadowOptions.MaxFiles == 0 {
+				shadowOptions.MaxFiles = -1
+			}
+			if shadowOptions.MaxBytes == 0 {
+				shadowOptions.MaxBytes = -1
+			}
+			options = append(options, go_mcp_tools.WithShadowCopy(shadowOptions))
+		}
+		if *commitTool {
+			options = append(options, go_mcp_tools.WithCommitTool(go_mcp_tools.CommitOptions{
+				AuthorName:  *commitAuthor,
+				AuthorEmail: *commitEmail,
+			}))
+		}
+		if *worktrees {
+			options = append(options, go_mcp_tools.WithWorktrees(go_mcp_tools.WorktreeOptions{
+				BaseRef: *worktreeBase,
+			}))
+		}
+		if *conflicts || *conflictsWarnOnly {
+			options = append(options, go_mcp_tools.WithConflictDetection(go_mcp_tools.ConflictOptions{
+				WarnOnly: *conflictsWarnOnly,
+			}))
+		}
+		if *packageMetadataCache != "" {
+			options = append(options, go_mcp_tools.WithPackageMetadataCache(*packageMetadataCache))
+		}
+		options = append(options, go_mcp_tools.WithFileCacheLimits(go_mcp_tools.FileCacheLimits{
+			MaxFiles: *fileCacheFiles,
+			MaxBytes: *fileCacheMB << 20,
+		}))
+		options = append(options, go_mcp_tools.WithGoplsConcurrency(*goplsConcurrency))
+		policy := go_mcp_tools.DefaultSandboxPolicy
+		policy.Timeout = *execTimeout
+		policy.CPUTime = *execCPU
+		policy.Memory = *execMemoryMB << 20
+		options = append(options, go_mcp_tools.WithSandboxPolicy(policy))
+		if *diagnostics != "" {
+			options = append(options, go_mcp_tools.WithDiagnostics(go_mcp_tools.DiagnosticsOptions{
+				WorkspaceDir: *diagnostics,
+			}))
+		}
+		if *symbolResources != "" {
+			options = append(options, go_mcp_tools.WithSymbolResources(*symbolResources))
+		}
+		if *tabWidth > 0 || *maxLineWidth > 0 {
+			format := go_mcp_tools.OutputFormat{
+				TabWidth:     *tabWidth,
+				MaxLineWidth: *maxLineWidth,
+				Overflow:     go_mcp_tools.OverflowWrap,
+			}
+			if *truncateLines {
+				format.Overflow = go_mcp_tools.OverflowTruncate
+			}
+			options = append(options, go_mcp_tools.WithOutputFormat(format))
+		}
+		if *requireProgress != "" && !slices.Contains([]string{"low", "medium", "high"}, *requireProgress) {
+			log.Fatalf("Invalid --require-progress %q, expected low, medium or high", *requireProgress)
+		}
+		if *minProtocolVersion != "" || len(*requireCapabilities) > 0 || *requireProgress != "" {
+			options = append(options, go_mcp_tools.WithNegotiation(go_mcp_tools.NegotiationOptions{
+				MinProtocolVersion:   *minProtocolVersion,
+				RequiredCapabilities: *requireCapabilities,
+				ProgressLevel:        go_mcp_tools.CostLevel(*requireProgress),
+			}))
+		}
+		if *record != "" {
+			recording, err := os.Create(*record)
+			if err != nil {
+				log.Fatalf("Error creating recording: %v", err)
+			}
+			defer recording.Close()
+			options = append(options, go_mcp_tools.WithRecorder(recording))
+		}
+		mcpServer := go_mcp_tools.NewMCPServer(options...)
+
+		// Start serving
+		switch *transport {
+		case "grpc":
+			fmt.Printf("Starting gRPC server on %s:%s\n", *host, *port)
+			if err := go_mcp_tools.ServeGRPC(mcpServer, *host, *port); err != nil {
+				log.Fatalf("gRPC server error: %v", err)
+			}
+		case "http":
+			fmt.Printf("Starting HTTP server on %s:%s/mcp\n", *host, *port)
+			if err := go_mcp_tools.ServeHTTP(mcpServer, *host, *port); err != nil {
+				log.Fatalf("HTTP server error: %v", err)
+			}
+		case "jsonl":
+			// no printing to stdout as every line is a tool result
+			if err := go_mcp_tools.ServeJSONLStdio(mcpServer); err != nil {
+				log.Fatalf("JSONL server error: %v", err)
+			}
+		default:
+			// no printing to stdio as it is used for machine communication
+			if err := go_mcp_tools.ServeStdio(mcpServer); err != nil {
+				log.Fatalf("Stdio server error: %v", err)
+			}
+		}
 	}
+	return cmd
 }
 
-func printUsage() {
-	fmt.Println("Go MCP Tools - Model Context Protocol tools for Go development")
-	fmt.Println()
-	fmt.Println("Usage:")
-	fmt.Println("  go run cmd/main.go server [flags]     Start MCP server")
-	fmt.Println()
-	fmt.Println("Server Commands:")
-	fmt.Println("  server --transport stdio             Start stdio server (default)")
-	fmt.Println("  server --transport http              Start HTTP server")
-	fmt.Println("         --host localhost              HTTP host (default: localhost)")
-	fmt.Println("         --port 8080                   HTTP port (default: 8080)")
-	fmt.Println("         --disable-tool <tool>         Disable specific tool")
-	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Println("  # Start stdio server")
-	fmt.Println("  go run cmd/main.go server")
-	fmt.Println()
-	fmt.Println("  # Start HTTP server")
-	fmt.Println("  go run cmd/main.go server --transport http --port 9000")
+func newReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [flags] <recording>",
+		Short: "Replay a recorded session and compare the results",
+		Example: `  # Replay a session against another checkout
+  go-mcp-tools replay --rewrite /old/repo=/new/repo session.jsonl`,
+		Args: cobra.ExactArgs(1),
+	}
+	fs := cmd.Flags()
+
+	mode := fs.String("mode", string(go_mcp_tools.ReplayExact), "Comparison mode (exact or compatible)")
+	rewriteValues := fs.StringArray("rewrite", nil, "Replace a recorded path with <old>=<new> (can be repeated)")
+
+	cmd.RegisterFlagCompletionFunc("mode", cobra.FixedCompletions(
+		[]string{string(go_mcp_tools.ReplayExact), string(go_mcp_tools.ReplayCompatible)},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	cmd.ValidArgsFunction = completeFiles("jsonl")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		rewrites := make(map[string]string)
+		for _, value := range *rewriteValues {
+			from, to, ok := strings.Cut(value, "=")
+			if !ok || from == "" {
+				log.Fatalf("Error parsing replay flags: expected <old>=<new>, got %q", value)
+			}
+			rewrites[from] = to
+		}
+
+		recording, err := os.Open(args[0])
+		if err != nil {
+			log.Fatalf("Error opening recording: %v", err)
+		}
+		defer recording.Close()
+
+		mismatches, err := go_mcp_tools.Replay(
+			go_mcp_tools.NewMCPServer(),
+			recording,
+			go_mcp_tools.ReplayOptions{
+				Mode:         go_mcp_tools.ReplayMode(*mode),
+				PathRewrites: rewrites,
+			},
+		)
+		if err != nil {
+			log.Fatalf("Replay error: %v", err)
+		}
+		if len(mismatches) > 0 {
+			for _, mismatch := range mismatches {
+				fmt.Printf("%s\n\n", mismatch)
+			}
+			fmt.Printf("%d call(s) did not match the recording\n", len(mismatches))
+			os.Exit(1)
+		}
+		fmt.Println("All calls matched the recording")
+	}
+	return cmd
 }
 
-func runServer(args []string) {
-	fs := flag.NewFlagSet("server", flag.ExitOnError)
+func newEvalCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval [flags] [corpus]",
+		Short: "Run the tools against pinned repositories (default: eval/corpus.json)",
+		Example: `  # Check the tools against real world code
+  go-mcp-tools eval --cache /tmp/eval eval/corpus.json`,
+		Args: cobra.MaximumNArgs(1),
+	}
+	fs := cmd.Flags()
+
+	cacheDir := fs.String("cache", "", "Directory of the repository checkouts (default: user cache directory)")
+	maxLatency := fs.Duration("max-latency", time.Minute, "Maximum duration of calls without a bound in the corpus")
+
+	cmd.MarkFlagDirname("cache")
+	cmd.ValidArgsFunction = completeFiles("json")
 
-	transport := fs.String("transport", "stdio", "Transport type (stdio or http)")
-	host := fs.String("host", "localhost", "Host for HTTP transport")
-	port := fs.String("port", "8080", "Port for HTTP transport")
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		corpusPath := "eval/corpus.json"
+		if len(args) == 1 {
+			corpusPath = args[0]
+		}
+		if *cacheDir == "" {
+			userCacheDir, err := os.UserCacheDir()
+			if err != nil {
+				log.Fatalf("Error finding cache directory, set --cache: %v", err)
+			}
+			*cacheDir = filepath.Join(userCacheDir, "go-mcp-tools", "eval")
+		}
+
+		corpus, err := go_mcp_tools.LoadEvalCorpus(corpusPath)
+		if err != nil {
+			log.Fatalf("Error loading corpus: %v", err)
+		}
+		results, err := go_mcp_tools.Eval(
+			context.Background(),
+			go_mcp_tools.NewMCPServer(),
+			corpus,
+			go_mcp_tools.EvalOptions{
+				CacheDir:   *cacheDir,
+				MaxLatency: *maxLatency,
+			},
+		)
+		if err != nil {
+			log.Fatalf("Eval error: %v", err)
+		}
+		fmt.Print(go_mcp_tools.FormatEvalResults(results))
+		for _, result := range results {
+			if len(result.Failures) > 0 {
+				os.Exit(1)
+			}
+		}
+	}
+	return cmd
+}
 
-	if err := fs.Parse(args); err != nil {
-		log.Fatalf("Error parsing server flags: %v", err)
+func newREPLCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repl [flags]",
+		Short: "Explore code interactively with inspect, refs, impl and callers",
+		Example: `  # Explore the module in the current directory
+  go-mcp-tools repl`,
+		Args: cobra.NoArgs,
 	}
+	fs := cmd.Flags()
 
-	mcpServer := go_mcp_tools.NewMCPServer(nil)
+	workspace := fs.String("workspace", ".", "Workspace directory of the commands")
+	packageMetadataCache := fs.String("package-metadata-cache", "", "Directory of a disk cache of the metadata of loaded packages (default: disabled)")
 
-	// Start serving
-	if *transport == "http" {
-		fmt.Printf("Starting HTTP server on %s:%s/mcp\n", *host, *port)
-		if err := go_mcp_tools.ServeHTTP(mcpServer, *host, *port); err != nil {
-			log.Fatalf("HTTP server error: %v", err)
+	cmd.MarkFlagDirname("workspace")
+	cmd.MarkFlagDirname("package-metadata-cache")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		fmt.Println("Go MCP Tools REPL, type help for the commands")
+		err := go_mcp_tools.RunREPL(context.Background(), os.Stdin, os.Stdout, go_mcp_tools.REPLOptions{
+			WorkspaceDir:     *workspace,
+			MetadataCacheDir: *packageMetadataCache,
+		})
+		if err != nil {
+			log.Fatalf("REPL error: %v", err)
 		}
-	} else {
-		// no printing to stdio as it is used for machine communication
-		if err := go_mcp_tools.ServeStdio(mcpServer); err != nil {
-			log.Fatalf("Stdio server error: %v", err)
+	}
+	return cmd
+}
+
+// newManCommand returns the command writing the man pages of root and its subcommands
+func newManCommand(root *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "man <dir>",
+		Short: "Generate the man pages of the commands into a directory",
+		Example: `  # Install the man pages for the current user
+  go-mcp-tools man ~/.local/share/man/man1`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := os.MkdirAll(args[0], 0755); err != nil {
+				return err
+			}
+			root.DisableAutoGenTag = true
+			return doc.GenManTree(root, &doc.GenManHeader{Title: "GO-MCP-TOOLS", Section: "1"}, args[0])
+		},
+	}
+}
+
+// completeFiles completes the single argument of a command with files of the extensions
+func completeFiles(extensions ...string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
+		return extensions, cobra.ShellCompDirectiveFilterFileExt
 	}
 }
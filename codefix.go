@@ -0,0 +1,205 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	codefixToolName        = "codefix"
+	codefixToolDescription = `Lists the code actions available in a Go file, the fixes suggested by the analyzers of the analyze tool for the diagnostics of the file or of one line, numbered in order of position. Pass the numbers of the actions to apply as apply to write them, which returns the changes as a unified diff. Alternative fixes of a diagnostic and actions whose edits overlap cannot be applied together.`
+)
+
+func AddCodefixTool(mcpServer *server.MCPServer) {
+	handleCodefix := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		lineNumber := request.GetInt("line_number", 0)
+		analyzers := request.GetStringSlice("analyzers", nil)
+		apply := request.GetIntSlice("apply", nil)
+
+		actions, err := CodeActions(ctx, filePath, lineNumber, analyzers)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error listing code actions: %v", err)), nil
+		}
+		if len(apply) == 0 {
+			return mcp.NewToolResultText(formatCodeActions(filePath, actions)), nil
+		}
+		diff, err := ApplyCodeActions(actions, apply)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error applying code actions: %v", err)), nil
+		}
+		return mcp.NewToolResultText(diff), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		codefixToolName,
+		mcp.WithDescription(codefixToolDescription),
+		editingToolAnnotation(true, false),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("line_number",
+			mcp.Description("Only list the code actions of diagnostics on this line"),
+		),
+		mcp.WithArray("analyzers",
+			mcp.Description(`Names of the analyzers to run, or "all", instead of the default ones of the analyze tool`),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("apply",
+			mcp.Description("Numbers of the listed code actions to apply"),
+			mcp.Items(map[string]any{"type": "number"}),
+		),
+	), handleCodefix)
+}
+
+// CodeAction is a fix suggested by an analyzer for a diagnostic
+type CodeAction struct {
+	// Number identifies the action among the actions of the file, starting at 1
+	Number   int    `json:"number"`
+	Analyzer string `json:"analyzer"`
+	Title    string `json:"title"`
+	// Diagnostic is the message of the diagnostic the action fixes
+	Diagnostic string    `json:"diagnostic"`
+	File       string    `json:"file"`
+	Line       int       `json:"line"`
+	Column     int       `json:"column"`
+	Edits      []FixEdit `json:"edits"`
+}
+
+// CodeActions analyzes the package of filePath and returns the fixes suggested for the
+// diagnostics in the file, or on lineNumber when it is positive
+func CodeActions(ctx context.Context, filePath string, lineNumber int, analyzers []string) ([]CodeAction, error) {
+	report, err := Analyze(ctx, filePath, analyzers, strings.HasSuffix(filePath, "_test.go"))
+	if err != nil {
+		return nil, err
+	}
+	var actions []CodeAction
+	for _, diagnostic := range report.Diagnostics {
+		if diagnostic.File != filePath || (lineNumber > 0 && diagnostic.Line != lineNumber) {
+			continue
+		}
+		for _, fix := range diagnostic.Fixes {
+			actions = append(actions, CodeAction{
+				Number:     len(actions) + 1,
+				Analyzer:   diagnostic.Analyzer,
+				Title:      fix.Message,
+				Diagnostic: diagnostic.Message,
+				File:       diagnostic.File,
+				Line:       diagnostic.Line,
+				Column:     diagnostic.Column,
+				Edits:      fix.Edits,
+			})
+		}
+	}
+	return actions, nil
+}
+
+// ApplyCodeActions writes the edits of the actions with the numbers and returns the changes
+// as a unified diff relative to the module root
+func ApplyCodeActions(actions []CodeAction, numbers []int) (string, error) {
+	type fileEdit struct {
+		textEdit
+		action int
+	}
+	edits := make(map[string][]fileEdit)
+	var files []string
+	// Alternative fixes of a diagnostic do not always overlap, but are never meant to be combined
+	fixed := make(map[string]int)
+	for _, number := range slices.Compact(slices.Sorted(slices.Values(numbers))) {
+		index := slices.IndexFunc(actions, func(action CodeAction) bool { return action.Number == number })
+		if index < 0 {
+			return "", fmt.Errorf("no code action %d, there are %d", number, len(actions))
+		}
+		action := actions[index]
+		diagnostic := fmt.Sprintf("%s:%d:%d %s %s", action.File, action.Line, action.Column, action.Analyzer, action.Diagnostic)
+		if other, ok := fixed[diagnostic]; ok {
+			return "", fmt.Errorf("code actions %d and %d are alternative fixes of the same diagnostic", other, number)
+		}
+		fixed[diagnostic] = number
+		for _, edit := range action.Edits {
+			if _, ok := edits[edit.File]; !ok {
+				files = append(files, edit.File)
+			}
+			edits[edit.File] = append(edits[edit.File], fileEdit{textEdit{edit.start, edit.end, edit.NewText}, number})
+		}
+	}
+
+	// All files are checked before any is written, so overlapping actions change nothing
+	contents := make(map[string][]byte)
+	for _, file := range files {
+		fileEdits := edits[file]
+		slices.SortStableFunc(fileEdits, func(a, b fileEdit) int { return a.start - b.start })
+		for i := 1; i < len(fileEdits); i++ {
+			previous, current := fileEdits[i-1], fileEdits[i]
+			if previous.action != current.action && current.start < previous.end {
+				return "", fmt.Errorf("code actions %d and %d overlap, apply them one at a time", previous.action, current.action)
+			}
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		contents[file] = content
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		var textEdits []textEdit
+		for _, edit := range edits[file] {
+			textEdits = append(textEdits, edit.textEdit)
+		}
+		updated := applyTextEdits(contents[file], textEdits)
+		// Keep gofmt-clean files gofmt-clean, e.g. by sorting imports added by a fix
+		if formattedOriginal, err := format.Source(contents[file]); err == nil && bytes.Equal(formattedOriginal, contents[file]) {
+			if formatted, err := format.Source(updated); err == nil {
+				updated = formatted
+			}
+		}
+		stat, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(file, updated, stat.Mode().Perm()); err != nil {
+			return "", fmt.Errorf("failed to write file %s: %w", file, err)
+		}
+		globalFileCache.RemoveFile(file)
+		name := file
+		if root, err := findModuleRoot(filepath.Dir(file)); err == nil {
+			if rel, err := filepath.Rel(root, file); err == nil {
+				name = rel
+			}
+		}
+		writeFilePatch(&b, filepath.ToSlash(name), contents[file], updated, "")
+	}
+	return b.String(), nil
+}
+
+// formatCodeActions lists the actions one per line with their number and position
+func formatCodeActions(filePath string, actions []CodeAction) string {
+	if len(actions) == 0 {
+		return fmt.Sprintf("No code actions available in %s", filePath)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Code actions in %s:\n", filePath)
+	for _, action := range actions {
+		fmt.Fprintf(&b, "  [%d] %d:%d %s: %s\n", action.Number, action.Line, action.Column, action.Analyzer, action.Title)
+		fmt.Fprintf(&b, "      fixes: %s\n", action.Diagnostic)
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCodefix(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a package with diagnostics that have suggested fixes
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",                  // 1
+				"",                              // 2
+				"import \"fmt\"",                // 3
+				"",                              // 4
+				"func describe(n int) string {", // 5
+				"\tfmt.Printf(\"%d\\n\", n)",    // 6
+				"\treturn string(n)",            // 7
+				"}",                             // 8
+				"",                              // 9
+				"func main() { describe(1) }",   // 10
+				"",                              // 11
+			},
+		})
+	}
+
+	t.Run("list code actions", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		actions, err := CodeActions(context.Background(), file, 0, nil)
+		if err != nil {
+			t.Fatalf("Failed to list code actions: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Code actions in " + file + ":",
+			"  [1] 7:9 stringintconv: Format the number as a decimal",
+			"      fixes: conversion from int to string yields a string of one rune, not a string of digits",
+			"  [2] 7:9 stringintconv: Convert a single rune to a string",
+			"      fixes: conversion from int to string yields a string of one rune, not a string of digits",
+			"",
+		}, "\n")
+		if got := formatCodeActions(file, actions); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+
+		actions, err = CodeActions(context.Background(), file, 6, nil)
+		if err != nil {
+			t.Fatalf("Failed to list code actions: %v", err)
+		}
+		if len(actions) != 0 {
+			t.Errorf("Expected no code actions on line 6, got %+v", actions)
+		}
+	})
+
+	t.Run("apply code actions", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		actions, err := CodeActions(context.Background(), file, 7, nil)
+		if err != nil {
+			t.Fatalf("Failed to list code actions: %v", err)
+		}
+		if _, err := ApplyCodeActions(actions, []int{1, 2}); err == nil || !strings.Contains(err.Error(), "code actions 1 and 2 are alternative fixes") {
+			t.Errorf("Expected alternative fixes to be refused, got %v", err)
+		}
+		if _, err := ApplyCodeActions(actions, []int{3}); err == nil {
+			t.Error("Expected an error for an unknown action")
+		}
+
+		diff, err := ApplyCodeActions(actions, []int{2})
+		if err != nil {
+			t.Fatalf("Failed to apply code action: %v", err)
+		}
+		for _, expected := range []string{"--- a/main.go", "-\treturn string(n)", "+\treturn string(rune(n))"} {
+			if !strings.Contains(diff, expected) {
+				t.Errorf("Expected %q in the diff:\n%s", expected, diff)
+			}
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), "return string(rune(n))") {
+			t.Errorf("Expected the fix to be written, got:\n%s", content)
+		}
+	})
+
+	t.Run("codefix tool", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      codefixToolName,
+				"arguments": map[string]any{"file_path": file, "apply": []int{1}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "+\treturn fmt.Sprint(n)") {
+			t.Errorf("Expected the diff of the applied fix, got: %s", text)
+		}
+	})
+}
@@ -0,0 +1,347 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const commitToolName = "commit_changes"
+
+// CommitOptions configures the commit_changes tool
+type CommitOptions struct {
+	// AuthorName and AuthorEmail override the git user of the commits, e.g. to attribute them to an agent
+	AuthorName  string
+	AuthorEmail string
+}
+
+// WithCommitTool enables the commit_changes tool, which stages the files modified by mutating
+// tools earlier in the session and commits them with a given message. Commits are never pushed.
+// Files that were already modified by others are only committed if a tool changed them further.
+func WithCommitTool(options CommitOptions) Option {
+	return func(o *serverOptions) {
+		o.commit = &options
+	}
+}
+
+// changeTracker records the files modified by mutating tools per session and git repository
+type changeTracker struct {
+	mu sync.Mutex
+	// files maps session IDs to repository roots to repository relative paths
+	files map[string]map[string]map[string]bool
+}
+
+func newChangeTracker() *changeTracker {
+	return &changeTracker{files: make(map[string]map[string]map[string]bool)}
+}
+
+func (tracker *changeTracker) add(sessionID string, repoRoot string, paths []string) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	repos, ok := tracker.files[sessionID]
+	if !ok {
+		repos = make(map[string]map[string]bool)
+		tracker.files[sessionID] = repos
+	}
+	if repos[repoRoot] == nil {
+		repos[repoRoot] = make(map[string]bool)
+	}
+	for _, path := range paths {
+		repos[repoRoot][path] = true
+	}
+}
+
+// take removes and returns the sorted paths modified in the repository during the session
+func (tracker *changeTracker) take(sessionID string, repoRoot string) []string {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	paths := make([]string, 0, len(tracker.files[sessionID][repoRoot]))
+	for path := range tracker.files[sessionID][repoRoot] {
+		paths = append(paths, path)
+	}
+	delete(tracker.files[sessionID], repoRoot)
+	slices.Sort(paths)
+	return paths
+}
+
+// sessionIDFromContext returns the ID of the calling session. Calls without a session,
+// e.g. from the jsonl transport, share the empty ID.
+func sessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// changeTrackingMiddleware records the files of the surrounding git repository that
+// mutating tools modify, by comparing the uncommitted changes before and after the call
+func changeTrackingMiddleware(tracker *changeTracker, costs map[string]ToolCost) server.ToolHandlerMiddleware {
+	// Calls are serialized so changes are attributed to the session making them
+	var mu sync.Mutex
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// Committing takes the tracked files itself
+			if !costs[request.Params.Name].Mutating || repositoryToolNames[request.Params.Name] {
+				return next(ctx, request)
+			}
+			repoRoot, err := gitRepoRootOfArguments(ctx, request.GetArguments())
+			if err != nil {
+				// Files outside git repositories cannot be committed, so there is nothing to track
+				return next(ctx, request)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			before, err := gitDirtyFiles(ctx, repoRoot)
+			if err != nil {
+				return next(ctx, request)
+			}
+			result, err := next(ctx, request)
+			after, statusErr := gitDirtyFiles(ctx, repoRoot)
+			if statusErr != nil {
+				return result, err
+			}
+
+			var modified []string
+			for path, hash := range after {
+				if before[path] != hash {
+					modified = append(modified, path)
+				}
+			}
+			// Files without uncommitted changes anymore were reverted by the tool
+			for path := range before {
+				if _, ok := after[path]; !ok {
+					modified = append(modified, path)
+				}
+			}
+			if len(modified) > 0 {
+				tracker.add(sessionIDFromContext(ctx), repoRoot, modified)
+			}
+			return result, err
+		}
+	}
+}
+
+// gitRepoRootOfArguments finds the git repository containing the first existing path argument
+func gitRepoRootOfArguments(ctx context.Context, arguments map[string]any) (string, error) {
+	for _, name := range pathArgumentNames {
+		value, ok := arguments[name].(string)
+		if !ok || value == "" {
+			continue
+		}
+		path, _, _ := parseInspectPath(value)
+		stat, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !stat.IsDir() {
+			path = filepath.Dir(path)
+		}
+		return gitRepoRoot(ctx, path)
+	}
+	return "", fmt.Errorf("the tool call has no existing file_path, path or workspace_dir argument")
+}
+
+// gitRepoRoot returns the top level directory of the git repository containing dir
+func gitRepoRoot(ctx context.Context, dir string) (string, error) {
+	output, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(strings.TrimSpace(output)), nil
+}
+
+// gitDirtyFiles returns a content hash of every file with uncommitted changes, by
+// repository relative path. Deleted files have an empty hash.
+func gitDirtyFiles(ctx context.Context, repoRoot string) (map[string]string, error) {
+	output, err := runGit(ctx, repoRoot, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// Renames and copies are followed by the original path, which is skipped
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+		path := entry[3:]
+		content, err := os.ReadFile(filepath.Join(repoRoot, path))
+		if err != nil {
+			files[path] = ""
+			continue
+		}
+		files[path] = fmt.Sprintf("%x", sha256.Sum256(content))
+	}
+	return files, nil
+}
+
+// runGit runs git in dir and returns its standard output, with the error output in the error
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := runCommand(ctx, dir, nil, &stdout, &stderr, "git", args...); err != nil {
+		// Name the subcommand in the error, skipping configuration passed with -c
+		subcommand := 0
+		for subcommand+1 < len(args) && args[subcommand] == "-c" {
+			subcommand += 2
+		}
+		return "", fmt.Errorf(
+			"git %s failed: %v\n%s",
+			args[subcommand],
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+	return stdout.String(), nil
+}
+
+// commitFiles stages and commits only the given repository relative paths
+func commitFiles(
+	ctx context.Context,
+	repoRoot string,
+	paths []string,
+	message string,
+	options CommitOptions,
+) (string, error) {
+	var config []string
+	if options.AuthorName != "" {
+		config = append(config, "-c", "user.name="+options.AuthorName)
+	}
+	if options.AuthorEmail != "" {
+		config = append(config, "-c", "user.email="+options.AuthorEmail)
+	}
+
+	// Adding with -A stages deletions as well
+	if _, err := runGit(ctx, repoRoot, append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+		return "", err
+	}
+	// Committing the paths leaves changes the user staged in other files out of the commit
+	args := append(config, "commit", "-m", message, "--")
+	if _, err := runGit(ctx, repoRoot, append(args, paths...)...); err != nil {
+		return "", err
+	}
+	hash, err := runGit(ctx, repoRoot, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"Committed %d file(s) as %s in %s:\n%s",
+		len(paths),
+		strings.TrimSpace(hash),
+		repoRoot,
+		strings.Join(paths, "\n"),
+	), nil
+}
+
+// commitPatch creates a git compatible patch of the given repository relative paths
+// against HEAD, the changes a commit of the paths would contain
+func commitPatch(ctx context.Context, repoRoot string, paths []string) (string, error) {
+	var b strings.Builder
+	for _, path := range paths {
+		if _, err := runGit(ctx, repoRoot, "show", "HEAD:"+path); err == nil {
+			diff, err := runGit(ctx, repoRoot, "diff", "--binary", "HEAD", "--", path)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(diff)
+			continue
+		}
+		// Files missing from HEAD are new, unless they were removed again
+		content, err := os.ReadFile(filepath.Join(repoRoot, path))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		stat, err := os.Stat(filepath.Join(repoRoot, path))
+		if err != nil {
+			return "", err
+		}
+		writeFilePatch(&b, path, nil, content, "new file mode "+gitFileMode(stat.Mode()))
+	}
+	return b.String(), nil
+}
+
+// addCommitTool adds the commit_changes tool committing the files recorded by the tracker
+func addCommitTool(mcpServer *server.MCPServer, tracker *changeTracker, options CommitOptions) {
+	handleCommit := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		message, err := request.RequireString("message")
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(message) == "" {
+			return nil, fmt.Errorf("message argument must be a non-empty string")
+		}
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		repoRoot, err := gitRepoRoot(ctx, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %s is not inside a git repository: %v", workspaceDir, err)), nil
+		}
+		sessionID := sessionIDFromContext(ctx)
+		paths := tracker.take(sessionID, repoRoot)
+		if len(paths) == 0 {
+			return toolErrorResult(fmt.Sprintf(
+				"Error: no files in the git repository %s were modified by tools in this session, there is nothing to commit",
+				repoRoot,
+			)), nil
+		}
+
+		if request.GetString(outputArgumentName, "") == outputPatch {
+			// Nothing is committed, so the files stay tracked
+			tracker.add(sessionID, repoRoot, paths)
+			patch, err := commitPatch(ctx, repoRoot, paths)
+			if err != nil {
+				return toolErrorResult(fmt.Sprintf("Error creating patch: %v", err)), nil
+			}
+			if patch == "" {
+				return mcp.NewToolResultText(fmt.Sprintf("%s made no changes, the patch is empty.", commitToolName)), nil
+			}
+			return mcp.NewToolResultText(patch), nil
+		}
+		result, err := commitFiles(ctx, repoRoot, paths, message, options)
+		if err != nil {
+			// Keep the files so the commit can be retried
+			tracker.add(sessionID, repoRoot, paths)
+			return toolErrorResult(fmt.Sprintf("Error committing changes: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(commitToolName,
+		mcp.WithDescription(
+			"Stages the files modified by tools earlier in this session and commits them in the git repository of the workspace. "+
+				"Other uncommitted changes in the repository are left as they are. Commits are never pushed.",
+		),
+		editingToolAnnotation(false, false),
+		mcp.WithString("message",
+			mcp.Description("Commit message, the first line being a short summary of the changes"),
+			mcp.Required(),
+		),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Directory inside the git repository to commit in"),
+			mcp.Required(),
+		),
+	), handleCommit)
+}
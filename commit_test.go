@@ -0,0 +1,135 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCommitChanges(t *testing.T) {
+	t.Parallel()
+
+	commitOptions := CommitOptions{AuthorName: "Agent", AuthorEmail: "agent@example.com"}
+
+	// Helper function to create a git repository with an unsorted map and a committed README
+	createTestWorkspace := func(t testing.TB) string {
+		tempDir := writeTestModule(t, map[string][]string{
+			"go.mod": {"module testmodule", "", "go 1.21", ""},
+			"main.go": {
+				"package testpkg", // 1
+				"",                // 2
+				"var Values = map[string]int{\"b\": 2, \"a\": 1}", // 3
+				"", // 4
+			},
+			"README.md": {"# Test", ""},
+		})
+		runTestGit(t, tempDir, "init", "--quiet")
+		runTestGit(t, tempDir, "add", "-A")
+		runTestGit(t, tempDir, "commit", "--quiet", "-m", "Initial commit")
+		return tempDir
+	}
+
+	t.Run("commits only files modified by tools", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mcpServer := NewMCPServer(WithCommitTool(commitOptions))
+
+		// A change by the user that must stay uncommitted
+		err := os.WriteFile(filepath.Join(workspace, "README.md"), []byte("# Changed\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := callTool(t, mcpServer, sortToolName, map[string]any{"file_path": filepath.Join(workspace, "main.go")})
+		if result.IsError {
+			t.Fatalf("Expected sort to succeed, got: %s", toolResultText(&result))
+		}
+		result = callTool(t, mcpServer, commitToolName, map[string]any{
+			"message":       "Sort values",
+			"workspace_dir": workspace,
+		})
+		text := toolResultText(&result)
+		if result.IsError || !strings.Contains(text, "Committed 1 file(s)") || !strings.HasSuffix(text, "\nmain.go") {
+			t.Fatalf("Expected main.go to be committed, got: %s", text)
+		}
+
+		log := runTestGit(t, workspace, "log", "-1", "--format=%an <%ae> %s", "--name-only")
+		if !strings.Contains(log, "Agent <agent@example.com> Sort values") || !strings.Contains(log, "main.go") ||
+			strings.Contains(log, "README.md") {
+			t.Errorf("Expected commit of main.go by the agent, got:\n%s", log)
+		}
+		if status := runTestGit(t, workspace, "status", "--porcelain"); status != " M README.md\n" {
+			t.Errorf("Expected the README change to stay uncommitted, got:\n%s", status)
+		}
+	})
+
+	t.Run("patch output commits nothing", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mcpServer := NewMCPServer(WithCommitTool(commitOptions))
+
+		result := callTool(t, mcpServer, sortToolName, map[string]any{"file_path": filepath.Join(workspace, "main.go")})
+		if result.IsError {
+			t.Fatalf("Expected sort to succeed, got: %s", toolResultText(&result))
+		}
+		arguments := map[string]any{
+			"message":       "Sort values",
+			"workspace_dir": workspace,
+			"output":        "patch",
+		}
+		result = callTool(t, mcpServer, commitToolName, arguments)
+		patch := toolResultText(&result)
+		if result.IsError || !strings.Contains(patch, "+var Values = map[string]int{\"a\": 1, \"b\": 2}") {
+			t.Fatalf("Expected the sort in the patch, got: %s", patch)
+		}
+		if count := runTestGit(t, workspace, "rev-list", "--count", "HEAD"); count != "1\n" {
+			t.Errorf("Expected no new commit, got %s commit(s)", strings.TrimSpace(count))
+		}
+		cmd := exec.Command("git", "apply", "--check", "--reverse", "-")
+		cmd.Dir = workspace
+		cmd.Stdin = strings.NewReader(patch)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Expected the patch to hold the uncommitted changes: %v\n%s", err, output)
+		}
+
+		// The files stay tracked for a later commit
+		delete(arguments, "output")
+		result = callTool(t, mcpServer, commitToolName, arguments)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "Committed 1 file(s)") {
+			t.Errorf("Expected main.go to be committed after the patch, got: %s", text)
+		}
+	})
+
+	t.Run("nothing to commit", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mcpServer := NewMCPServer(WithCommitTool(commitOptions))
+
+		result := callTool(t, mcpServer, commitToolName, map[string]any{
+			"message":       "Nothing",
+			"workspace_dir": workspace,
+		})
+		if !result.IsError || !strings.Contains(toolResultText(&result), "nothing to commit") {
+			t.Errorf("Expected nothing to commit error, got: %s", toolResultText(&result))
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+		response := NewMCPServer().HandleMessage(
+			context.Background(),
+			json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`),
+		).(mcp.JSONRPCResponse)
+		for _, tool := range response.Result.(mcp.ListToolsResult).Tools {
+			if tool.Name == commitToolName {
+				t.Errorf("Expected %s to require WithCommitTool", commitToolName)
+			}
+		}
+	})
+}
@@ -0,0 +1,340 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	completionToolName        = "completion"
+	completionToolDescription = `Lists completion candidates at a position of a Go file, like an editor would: the fields and methods of a value or the exported members of a package after a dot, otherwise the identifiers in scope. Each candidate has its kind and type or signature. Useful when writing code incrementally to know what members a value has.
+
+The position is the end of the expression being completed on the line, e.g. "server." for the members of server, "strings.Trim" or an identifier prefix like "se". The identifier characters at its end are the prefix the candidates are filtered by. The file may contain the incomplete code being written, or pass it as an overlay without saving it.`
+)
+
+func AddCompletionTool(mcpServer *server.MCPServer) {
+	handleCompletion := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		lineNumber, err := request.RequireInt("line_number")
+		if err != nil {
+			return nil, err
+		}
+		expression, err := request.RequireString("expression")
+		if err != nil {
+			return nil, err
+		}
+		limit := request.GetInt("limit", 50)
+		overlay, err := overlayFromRequest(request, filepath.Dir(filePath))
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+
+		candidates, err := Completion(ctx, filePath, lineNumber, expression, overlay)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error completing: %v", err)), nil
+		}
+		return mcp.NewToolResultText(formatCompletions(candidates, limit)), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		completionToolName,
+		mcp.WithDescription(completionToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("line_number",
+			mcp.Description("Line of the expression being completed"),
+			mcp.Required(),
+		),
+		mcp.WithString("expression",
+			mcp.Description("Text on the line that the candidates complete, e.g. \"servers[0].\" or \"strings.Trim\". The position is the end of its last occurrence on the line."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of candidates, 0 for all"),
+			mcp.DefaultNumber(50),
+		),
+		mcp.WithObject("overlays",
+			mcp.Description("Unsaved file contents by file path (absolute or relative to the directory of file_path), used instead of the files on disk, e.g. the file being written"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+	), handleCompletion)
+}
+
+// CompletionCandidate is an identifier completing the text at a position
+type CompletionCandidate struct {
+	Label string `json:"label"`
+	// Kind is one of variable, constant, field, function, method, type and package
+	Kind string `json:"kind"`
+	// Detail is the type of variables, constants and fields, the signature of functions
+	// and methods, the kind of types and the import path of packages
+	Detail string `json:"detail,omitempty"`
+}
+
+// Completion lists the completion candidates at the end of the last occurrence of expression
+// on lineNumber of filePath: the members of the operand before a dot, fields before methods,
+// or else the identifiers in scope, innermost scope first. The candidates are filtered by the
+// identifier prefix at the end of expression. The files of the overlay replace those on disk.
+func Completion(ctx context.Context, filePath string, lineNumber int, expression string, overlay Overlay) ([]CompletionCandidate, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if lineNumber <= 0 {
+		return nil, fmt.Errorf("line number must be positive, got %d", lineNumber)
+	}
+	line, err := readSourceLines(filePath, lineNumber, lineNumber, overlay)
+	if err != nil {
+		return nil, err
+	}
+	offset := lastExpressionEnd(line, expression)
+	if offset < 0 {
+		return nil, classifyErrorf(ErrSymbolNotFound, "%q not found on line %d: %s", expression, lineNumber, strings.TrimSpace(line))
+	}
+	before := line[:offset]
+	prefix := before[len(strings.TrimRightFunc(before, isIdentifierRune)):]
+	operand := ""
+	if rest := strings.TrimSuffix(before, prefix); strings.HasSuffix(rest, ".") {
+		operand = completionOperand(strings.TrimSuffix(rest, "."))
+		if operand == "" {
+			return nil, fmt.Errorf("no operand before the dot of %q on line %d", expression, lineNumber)
+		}
+	}
+
+	// Packages with type errors, e.g. from the incomplete code, still have usable types
+	pkg, file, _, err := loadOverlaidFilePackage(ctx, filePath, overlay)
+	if err != nil {
+		return nil, err
+	}
+	pos := pkg.Fset.File(file.Pos()).LineStart(lineNumber) + token.Pos(offset)
+	scope := pkg.Types.Scope().Innermost(pos)
+	if scope == nil {
+		scope = pkg.TypesInfo.Scopes[file]
+	}
+	qualifier := types.RelativeTo(pkg.Types)
+
+	var candidates []CompletionCandidate
+	add := func(obj types.Object) {
+		if strings.HasPrefix(obj.Name(), prefix) && obj.Name() != "_" {
+			candidates = append(candidates, completionCandidate(obj, qualifier))
+		}
+	}
+	if operand == "" {
+		seen := make(map[string]bool)
+		for current := scope; current != nil; current = current.Parent() {
+			for _, name := range current.Names() {
+				obj := current.Lookup(name)
+				if _, visible := scope.LookupParent(name, pos); visible == obj && !seen[name] {
+					seen[name] = true
+					add(obj)
+				}
+			}
+		}
+		return candidates, nil
+	}
+
+	// A package qualifier is not an expression, so it is looked up before evaluating the operand
+	if _, obj := scope.LookupParent(operand, pos); obj != nil {
+		if pkgName, ok := obj.(*types.PkgName); ok {
+			imported := pkgName.Imported().Scope()
+			for _, name := range imported.Names() {
+				if token.IsExported(name) {
+					add(imported.Lookup(name))
+				}
+			}
+			return candidates, nil
+		}
+	}
+	typeAndValue, err := types.Eval(pkg.Fset, pkg.Types, pos, operand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate %s: %w", operand, err)
+	}
+	t := typeAndValue.Type
+	if typeAndValue.IsType() {
+		// Method expressions, e.g. Server.Start
+		methodSet := types.NewMethodSet(t)
+		for i := 0; i < methodSet.Len(); i++ {
+			add(methodSet.At(i).Obj())
+		}
+		return candidates, nil
+	}
+	for _, field := range completionFields(t) {
+		if field.Exported() || field.Pkg() == pkg.Types {
+			add(field)
+		}
+	}
+	// Addressable values can call the methods of the pointer too
+	methodsOf := t
+	if _, isPointer := t.Underlying().(*types.Pointer); !isPointer && !types.IsInterface(t) {
+		methodsOf = types.NewPointer(t)
+	}
+	methodSet := types.NewMethodSet(methodsOf)
+	for i := 0; i < methodSet.Len(); i++ {
+		if method := methodSet.At(i).Obj(); method.Exported() || method.Pkg() == pkg.Types {
+			add(method)
+		}
+	}
+	return candidates, nil
+}
+
+// lastExpressionEnd returns the offset in line after the last occurrence of expression not
+// continuing an identifier before it, or -1 when there is none
+func lastExpressionEnd(line string, expression string) int {
+	if expression == "" {
+		return -1
+	}
+	first, _ := utf8.DecodeRuneInString(expression)
+	for end := len(line); ; {
+		start := strings.LastIndex(line[:end], expression)
+		if start < 0 {
+			return -1
+		}
+		if previous, _ := utf8.DecodeLastRuneInString(line[:start]); start == 0 || !isIdentifierRune(first) || !isIdentifierRune(previous) {
+			return start + len(expression)
+		}
+		end = start + len(expression) - 1
+	}
+}
+
+// isIdentifierRune reports whether the rune can be part of a Go identifier
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// completionOperand returns the operand expression at the end of text, e.g. s.items[i] of
+// "for _, x := range s.items[i]", by scanning back over identifiers, selectors and balanced
+// calls and index expressions
+func completionOperand(text string) string {
+	depth := 0
+	start := len(text)
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		switch {
+		case r == ')' || r == ']':
+			depth++
+		case r == '(' || r == '[':
+			if depth == 0 {
+				return strings.TrimSpace(text[start:])
+			}
+			depth--
+		case depth > 0 || isIdentifierRune(r) || r == '.':
+		default:
+			return strings.TrimSpace(text[start:])
+		}
+		start -= size
+	}
+	return strings.TrimSpace(text)
+}
+
+// completionFields returns the fields of a struct or pointer to struct type, including the
+// fields promoted from embedded structs unless shadowed by a shallower field
+func completionFields(t types.Type) []*types.Var {
+	var fields []*types.Var
+	seen := make(map[string]bool)
+	visited := make(map[types.Type]bool)
+	level := []types.Type{t}
+	for len(level) > 0 {
+		var next []types.Type
+		for _, current := range level {
+			if pointer, ok := current.Underlying().(*types.Pointer); ok {
+				current = pointer.Elem()
+			}
+			structType, ok := current.Underlying().(*types.Struct)
+			if !ok || visited[current] {
+				continue
+			}
+			visited[current] = true
+			for i := 0; i < structType.NumFields(); i++ {
+				field := structType.Field(i)
+				if !seen[field.Name()] {
+					seen[field.Name()] = true
+					fields = append(fields, field)
+				}
+				if field.Embedded() {
+					next = append(next, field.Type())
+				}
+			}
+		}
+		level = next
+	}
+	return fields
+}
+
+// completionCandidate describes the object as a completion candidate
+func completionCandidate(obj types.Object, qualifier types.Qualifier) CompletionCandidate {
+	candidate := CompletionCandidate{Label: obj.Name()}
+	switch obj := obj.(type) {
+	case *types.Var:
+		candidate.Kind = "variable"
+		if obj.IsField() {
+			candidate.Kind = "field"
+		}
+		candidate.Detail = types.TypeString(obj.Type(), qualifier)
+	case *types.Const:
+		candidate.Kind = "constant"
+		candidate.Detail = types.TypeString(obj.Type(), qualifier)
+	case *types.Func:
+		candidate.Kind = "function"
+		signature := obj.Type().(*types.Signature)
+		if signature.Recv() != nil {
+			candidate.Kind = "method"
+		}
+		candidate.Detail = strings.TrimPrefix(types.TypeString(signature, qualifier), "func")
+	case *types.TypeName:
+		candidate.Kind = "type"
+		switch underlying := obj.Type().Underlying().(type) {
+		case *types.Struct:
+			candidate.Detail = "struct"
+		case *types.Interface:
+			candidate.Detail = "interface"
+		default:
+			candidate.Detail = types.TypeString(underlying, qualifier)
+		}
+	case *types.PkgName:
+		candidate.Kind = "package"
+		candidate.Detail = obj.Imported().Path()
+	case *types.Builtin:
+		candidate.Kind = "function"
+		candidate.Detail = "builtin"
+	case *types.Nil:
+		candidate.Kind = "constant"
+		candidate.Detail = "untyped nil"
+	}
+	return candidate
+}
+
+// formatCompletions formats up to limit candidates, one per line
+func formatCompletions(candidates []CompletionCandidate, limit int) string {
+	if len(candidates) == 0 {
+		return "No completion candidates"
+	}
+	var b strings.Builder
+	for i, candidate := range candidates {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(&b, "... %d more candidates\n", len(candidates)-limit)
+			break
+		}
+		fmt.Fprintf(&b, "%s %s", candidate.Kind, candidate.Label)
+		if candidate.Detail != "" {
+			fmt.Fprintf(&b, " %s", candidate.Detail)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
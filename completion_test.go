@@ -0,0 +1,151 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with code being written in main
+	createTestWorkspace := func(t testing.TB, line string) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",               // 1
+				"",                           // 2
+				"import \"strings\"",         // 3
+				"",                           // 4
+				"type Base struct{ ID int }", // 5
+				"",                           // 6
+				"type Server struct {",       // 7
+				"\tBase",                     // 8
+				"\tName  string",             // 9
+				"\tports []int",              // 10
+				"}",                          // 11
+				"",                           // 12
+				"func (s *Server) Start() error { return nil }", // 13
+				"", // 14
+				"func (s Server) Port(i int) int { return s.ports[i] }", // 15
+				"",                          // 16
+				"func main() {",             // 17
+				"\tservers := []Server{}",   // 18
+				"\tstrings.TrimSpace(\"\")", // 19
+				line,                        // 20
+				"}",                         // 21
+				"",                          // 22
+			},
+		})
+	}
+
+	// Helper function to format the candidates as kind label detail
+	describe := func(candidates []CompletionCandidate) string {
+		return strings.TrimSpace(formatCompletions(candidates, 0))
+	}
+
+	t.Run("members", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t, "\tservers[0]."), "main.go")
+
+		candidates, err := Completion(context.Background(), file, 20, "servers[0].", nil)
+		if err != nil {
+			t.Fatalf("Failed to complete: %v", err)
+		}
+		expected := strings.Join([]string{
+			"field Base Base",
+			"field Name string",
+			"field ports []int",
+			"field ID int",
+			"method Port (i int) int",
+			"method Start () error",
+		}, "\n")
+		if got := describe(candidates); got != expected {
+			t.Errorf("Expected the fields and methods of Server:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("prefix and package members", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t, "\tstrings.TrimL"), "main.go")
+
+		candidates, err := Completion(context.Background(), file, 20, "strings.TrimL", nil)
+		if err != nil {
+			t.Fatalf("Failed to complete: %v", err)
+		}
+		if got := describe(candidates); got != "function TrimLeft (s string, cutset string) string\nfunction TrimLeftFunc (s string, f func(rune) bool) string" {
+			t.Errorf("Expected the TrimLeft functions, got:\n%s", got)
+		}
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t, "\tse"), "main.go")
+
+		candidates, err := Completion(context.Background(), file, 20, "se", nil)
+		if err != nil {
+			t.Fatalf("Failed to complete: %v", err)
+		}
+		if got := describe(candidates); got != "variable servers []Server" {
+			t.Errorf("Expected the servers variable, got:\n%s", got)
+		}
+	})
+
+	t.Run("completion tool", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t, "\tS"), "main.go")
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      completionToolName,
+				"arguments": map[string]any{"file_path": file, "line_number": 20, "expression": "S"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || text != "type Server struct\n" {
+			t.Errorf("Expected the Server type, got: %s", text)
+		}
+	})
+
+	t.Run("overlays", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t, "\tS")
+		unsaved, err := os.ReadFile(filepath.Join(workspace, "main.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The unsaved line ends in a member prefix, the same line on disk in a type prefix
+		message, err := toolCallMessage(completionToolName, map[string]any{
+			"file_path":   filepath.Join(workspace, "main.go"),
+			"line_number": 20,
+			"expression":  "servers[0].Na",
+			"overlays":    map[string]any{"main.go": strings.Replace(string(unsaved), "\n\tS\n", "\n\tservers[0].Na\n", 1)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || text != "field Name string\n" {
+			t.Errorf("Expected the Name field of the unsaved code, got: %s", text)
+		}
+
+		if _, err := Completion(context.Background(), filepath.Join(workspace, "main.go"), 20, "servers[0].Na", nil); err == nil || !strings.Contains(err.Error(), "not found on line 20") {
+			t.Errorf("Expected the expression to be missing on disk, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ConflictOptions configures the detection of files changed outside of a session
+type ConflictOptions struct {
+	// WarnOnly runs mutating tools despite conflicts and prepends a warning to their result
+	WarnOnly bool
+}
+
+// WithConflictDetection remembers the contents of the files each session has read or
+// modified through tools, and refuses mutating tool calls on a module when any of those
+// files changed on disk since, e.g. by a human editing them concurrently. Reading the
+// files again with a tool resolves the conflict.
+func WithConflictDetection(options ConflictOptions) Option {
+	return func(o *serverOptions) {
+		o.conflicts = &options
+	}
+}
+
+// fileVersions records the content hashes of the files seen per session
+type fileVersions struct {
+	mu sync.Mutex
+	// hashes maps session IDs to absolute file paths to content hashes
+	hashes map[string]map[string]string
+}
+
+func newFileVersions() *fileVersions {
+	return &fileVersions{hashes: make(map[string]map[string]string)}
+}
+
+// record sets the hashes of files as seen by the session, an empty hash forgets the file
+func (versions *fileVersions) record(sessionID string, hashes map[string]string) {
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+	files, ok := versions.hashes[sessionID]
+	if !ok {
+		files = make(map[string]string)
+		versions.hashes[sessionID] = files
+	}
+	for path, hash := range hashes {
+		if hash == "" {
+			delete(files, path)
+		} else {
+			files[path] = hash
+		}
+	}
+}
+
+// conflicts returns the sorted files under dir seen by the session whose content differs
+// from the given current hashes. Files missing from current were deleted.
+func (versions *fileVersions) conflicts(sessionID string, dir string, current map[string]string) []string {
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+	var conflicts []string
+	for path, hash := range versions.hashes[sessionID] {
+		if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			continue
+		}
+		if current[path] != hash {
+			conflicts = append(conflicts, path)
+		}
+	}
+	slices.Sort(conflicts)
+	return conflicts
+}
+
+func (versions *fileVersions) removeSession(sessionID string) {
+	versions.mu.Lock()
+	defer versions.mu.Unlock()
+	delete(versions.hashes, sessionID)
+}
+
+// conflictMiddleware records the files read by tool calls and checks the module for
+// files changed outside of the session before mutating calls
+func conflictMiddleware(
+	versions *fileVersions,
+	options ConflictOptions,
+	costs map[string]ToolCost,
+) server.ToolHandlerMiddleware {
+	// Mutating calls are serialized so their changes are attributed to the session making them
+	var mu sync.Mutex
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			sessionID := sessionIDFromContext(ctx)
+			arguments := request.GetArguments()
+			// The tools changing the repository leave the files of the module as they are
+			if !costs[request.Params.Name].Mutating || repositoryToolNames[request.Params.Name] {
+				result, err := next(ctx, request)
+				if err == nil && result != nil && !result.IsError {
+					versions.record(sessionID, hashFiles(readFilesOfArguments(arguments)))
+				}
+				return result, err
+			}
+			moduleRoot, err := shadowModuleRoot(arguments)
+			if err != nil {
+				// Without a module the changed files cannot be determined
+				return next(ctx, request)
+			}
+			moduleRoot = resolveSymlinks(moduleRoot)
+
+			mu.Lock()
+			defer mu.Unlock()
+			before, err := hashModuleFiles(moduleRoot)
+			if err != nil {
+				return next(ctx, request)
+			}
+			var warning string
+			if conflicts := versions.conflicts(sessionID, moduleRoot, before); len(conflicts) > 0 {
+				message := conflictMessage(conflicts, before)
+				if !options.WarnOnly {
+					return toolErrorResult("Error: " + message), nil
+				}
+				warning = "Warning: " + message
+			}
+
+			result, err := next(ctx, request)
+			after, hashErr := hashModuleFiles(moduleRoot)
+			if hashErr != nil {
+				return result, err
+			}
+			// The session has now seen the files it modified, and the ones read by the call
+			modified := hashFiles(readFilesOfArguments(arguments))
+			for path, hash := range after {
+				if before[path] != hash {
+					modified[path] = hash
+				}
+			}
+			for path := range before {
+				if _, ok := after[path]; !ok {
+					modified[path] = ""
+				}
+			}
+			versions.record(sessionID, modified)
+
+			if warning != "" && result != nil {
+				result.Content = append([]mcp.Content{mcp.NewTextContent(warning)}, result.Content...)
+			}
+			return result, err
+		}
+	}
+}
+
+// conflictMessage describes the files changed outside of the session
+func conflictMessage(conflicts []string, current map[string]string) string {
+	var changed, deleted []string
+	for _, path := range conflicts {
+		if _, ok := current[path]; ok {
+			changed = append(changed, path)
+		} else {
+			deleted = append(deleted, path)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("files were changed outside of this session since it last read them, ")
+	b.WriteString("the tool call could overwrite those changes or be based on outdated code.")
+	if len(changed) > 0 {
+		fmt.Fprintf(&b, " Changed: %s.", strings.Join(changed, ", "))
+	}
+	if len(deleted) > 0 {
+		fmt.Fprintf(&b, " Deleted: %s.", strings.Join(deleted, ", "))
+	}
+	b.WriteString(" Inspect the files again to see their current contents before modifying them.")
+	return b.String()
+}
+
+// readFilesOfArguments returns the existing files referred to by the path arguments
+// other than workspace_dir. Directories stand for the Go files directly inside them.
+func readFilesOfArguments(arguments map[string]any) []string {
+	workspaceDir, _ := arguments["workspace_dir"].(string)
+	var files []string
+	for _, argument := range pathArguments(arguments) {
+		if argument.name == "workspace_dir" {
+			continue
+		}
+		path, _, _ := parseInspectPath(argument.value)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workspaceDir, path)
+		}
+		// Files are tracked by resolved path, matching the walk of the resolved module root
+		path = resolveSymlinks(path)
+		stat, err := os.Stat(path)
+		if err != nil {
+			// Import paths and symbols do not refer to files directly
+			continue
+		}
+		if !stat.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return files
+}
+
+// hashFiles returns the content hashes of the files, with an empty hash for missing files
+func hashFiles(paths []string) map[string]string {
+	hashes := make(map[string]string, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			hashes[path] = ""
+			continue
+		}
+		hashes[path] = fmt.Sprintf("%x", sha256.Sum256(content))
+	}
+	return hashes
+}
+
+// hashModuleFiles returns the content hashes of the Go files and go.mod of the module by
+// absolute path, skipping hidden directories such as version control
+func hashModuleFiles(moduleRoot string) (map[string]string, error) {
+	var paths []string
+	err := filepath.WalkDir(moduleRoot, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != moduleRoot && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() && (strings.HasSuffix(path, ".go") || entry.Name() == "go.mod") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	hashes := hashFiles(paths)
+	for path, hash := range hashes {
+		// Files removed during the walk are not part of the module anymore
+		if hash == "" {
+			delete(hashes, path)
+		}
+	}
+	return hashes, nil
+}
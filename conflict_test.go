@@ -0,0 +1,141 @@
+package go_mcp_tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConflictDetection(t *testing.T) {
+	t.Parallel()
+
+	unsortedLines := []string{
+		"package testpkg", // 1
+		"",                // 2
+		"var Values = map[string]int{\"b\": 2, \"a\": 1}", // 3
+		"", // 4
+	}
+
+	// Helper function to create a test module with an unsorted map
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod":  {"module testmodule", "", "go 1.21", ""},
+			"main.go": unsortedLines,
+		})
+	}
+
+	// Helper function to change a file like a concurrent editor would
+	editFile := func(t testing.TB, path string) {
+		lines := append(append([]string{}, unsortedLines...), "var Other = map[string]int{\"d\": 4, \"c\": 3}", "")
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inspectArguments := func(mainFile string) map[string]any {
+		return map[string]any{
+			"path":               mainFile,
+			"workspace_dir":      filepath.Dir(mainFile),
+			"include_references": false,
+		}
+	}
+
+	t.Run("refuses changes to files changed externally", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		mcpServer := NewMCPServer(WithConflictDetection(ConflictOptions{}))
+
+		if result := callTool(t, mcpServer, inspectToolName, inspectArguments(mainFile)); result.IsError {
+			t.Fatalf("Failed to inspect: %s", toolResultText(&result))
+		}
+		editFile(t, mainFile)
+
+		result := callTool(t, mcpServer, sortToolName, map[string]any{"file_path": mainFile})
+		text := toolResultText(&result)
+		if !result.IsError || !strings.Contains(text, "Changed: "+mainFile) {
+			t.Fatalf("Expected a conflict error naming the file, got: %s", text)
+		}
+		content, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), `{"d": 4, "c": 3}`) {
+			t.Errorf("Expected the external change to be kept, got:\n%s", content)
+		}
+
+		// Reading the file again resolves the conflict
+		if result := callTool(t, mcpServer, inspectToolName, inspectArguments(mainFile)); result.IsError {
+			t.Fatalf("Failed to inspect: %s", toolResultText(&result))
+		}
+		result = callTool(t, mcpServer, sortToolName, map[string]any{"file_path": mainFile})
+		if result.IsError {
+			t.Fatalf("Expected sort to succeed after reading the file again, got: %s", toolResultText(&result))
+		}
+
+		// Changes made by the session itself are no conflicts
+		result = callTool(t, mcpServer, sortToolName, map[string]any{"file_path": mainFile})
+		if result.IsError {
+			t.Errorf("Expected the session's own changes not to conflict, got: %s", toolResultText(&result))
+		}
+	})
+
+	t.Run("symlinked workspace root", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		link := filepath.Join(t.TempDir(), "link")
+		if err := os.Symlink(workspace, link); err != nil {
+			t.Fatal(err)
+		}
+		mcpServer := NewMCPServer(WithConflictDetection(ConflictOptions{}))
+
+		// Reads through the symlink and writes through the resolved path are the same files
+		if result := callTool(t, mcpServer, inspectToolName, inspectArguments(filepath.Join(link, "main.go"))); result.IsError {
+			t.Fatalf("Failed to inspect: %s", toolResultText(&result))
+		}
+		editFile(t, filepath.Join(workspace, "main.go"))
+		result := callTool(t, mcpServer, sortToolName, map[string]any{"file_path": filepath.Join(workspace, "main.go")})
+		if !result.IsError || !strings.Contains(toolResultText(&result), "Changed: "+filepath.Join(workspace, "main.go")) {
+			t.Errorf("Expected a conflict for the file read through the symlink, got: %s", toolResultText(&result))
+		}
+	})
+
+	t.Run("files not read by the session", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		mcpServer := NewMCPServer(WithConflictDetection(ConflictOptions{}))
+
+		editFile(t, mainFile)
+		result := callTool(t, mcpServer, sortToolName, map[string]any{"file_path": mainFile})
+		if result.IsError {
+			t.Errorf("Expected no conflict for unread files, got: %s", toolResultText(&result))
+		}
+	})
+
+	t.Run("warn only", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		mcpServer := NewMCPServer(WithConflictDetection(ConflictOptions{WarnOnly: true}))
+
+		if result := callTool(t, mcpServer, inspectToolName, inspectArguments(mainFile)); result.IsError {
+			t.Fatalf("Failed to inspect: %s", toolResultText(&result))
+		}
+		editFile(t, mainFile)
+
+		result := callTool(t, mcpServer, sortToolName, map[string]any{"file_path": mainFile})
+		text := toolResultText(&result)
+		if result.IsError || !strings.HasPrefix(text, "Warning: files were changed outside of this session") {
+			t.Errorf("Expected the sort to run with a warning, got: %s", text)
+		}
+		content, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), `{"c": 3, "d": 4}`) {
+			t.Errorf("Expected the file to be sorted, got:\n%s", content)
+		}
+	})
+}
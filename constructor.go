@@ -0,0 +1,428 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	generateConstructorToolName        = "generate_constructor"
+	generateConstructorToolDescription = `Generates a NewT constructor, functional options or getters for a Go struct, inserted after the last method of the struct in the file declaring it.
+
+Fields are classified as required or optional. A ctor:"required", ctor:"optional" or ctor:"-" tag decides, then "required" or "omitempty" in validate and binding tags and "omitempty" in json tags. Otherwise fields whose zero value is rarely usable, i.e. pointers, interfaces, functions and channels, are required and other fields optional. Mutexes and other synchronization primitives are never set.
+
+Styles:
+- constructor: NewT taking the required fields as parameters, making the maps
+- options: NewT taking the required fields and functional options, with a WithField option per optional field
+- getters: a method per unexported field returning it, named like the field in upper case
+
+Pass dry_run=true to only return the classification and the code without writing it.`
+)
+
+// ConstructorStyle selects the code GenerateConstructor generates
+type ConstructorStyle string
+
+const (
+	// ConstructorPlain generates NewT with the required fields as parameters
+	ConstructorPlain ConstructorStyle = "constructor"
+	// ConstructorFunctionalOptions generates NewT with the required fields as parameters
+	// and functional options setting the optional fields
+	ConstructorFunctionalOptions ConstructorStyle = "options"
+	// ConstructorGetters generates a getter per unexported field
+	ConstructorGetters ConstructorStyle = "getters"
+)
+
+func AddGenerateConstructorTool(mcpServer *server.MCPServer) {
+	handleGenerateConstructor := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := request.RequireString("type")
+		if err != nil {
+			return nil, err
+		}
+		var options ConstructorOptions
+		style := request.GetString("style", "")
+		options.Style = ConstructorStyle(style)
+		options.DryRun = request.GetBool("dry_run", false)
+
+		result, err := GenerateConstructor(ctx, filePath, typeName, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error generating constructor: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		generateConstructorToolName,
+		mcp.WithDescription(generateConstructorToolDescription),
+		editingToolAnnotation(false, false),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the struct"),
+			mcp.Required(),
+		),
+		mcp.WithString("type",
+			mcp.Description("Name of the struct type"),
+			mcp.Required(),
+		),
+		mcp.WithString("style",
+			mcp.Description("Code to generate: a constructor, a constructor with functional options or getters"),
+			mcp.Enum(string(ConstructorPlain), string(ConstructorFunctionalOptions), string(ConstructorGetters)),
+			mcp.DefaultString(string(ConstructorPlain)),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Whether to only return the generated code instead of writing it"),
+			mcp.DefaultBool(false),
+		),
+	), handleGenerateConstructor)
+}
+
+// ConstructorOptions configures GenerateConstructor
+type ConstructorOptions struct {
+	// Style is the code to generate, ConstructorPlain when empty
+	Style ConstructorStyle
+	// DryRun returns the code instead of writing it
+	DryRun bool
+}
+
+// constructorField is a field of the struct with how the generated code treats it
+type constructorField struct {
+	name     string
+	typeName string
+	// param is the name of the parameter setting the field
+	param    string
+	required bool
+	skipped  bool
+	// isMap fields are made by the constructor
+	isMap  bool
+	reason string
+}
+
+// GenerateConstructor generates a constructor, functional options or getters for the struct
+// named typeName declared in filePath, writing the changed file unless options.DryRun
+func GenerateConstructor(ctx context.Context, filePath string, typeName string, options ConstructorOptions) (string, error) {
+	if !filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if options.Style == "" {
+		options.Style = ConstructorPlain
+	}
+	if !slices.Contains([]ConstructorStyle{ConstructorPlain, ConstructorFunctionalOptions, ConstructorGetters}, options.Style) {
+		return "", fmt.Errorf("unknown style %q, expected constructor, options or getters", options.Style)
+	}
+	pkg, _, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return "", err
+	}
+	if len(pkg.Errors) > 0 {
+		return "", classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok || pkg.Fset.Position(obj.Pos()).Filename != filePath {
+		return "", classifyErrorf(ErrSymbolNotFound, "no type '%s' declared in %s", typeName, filePath)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return "", fmt.Errorf("'%s' is an alias, generate the code for the aliased type", typeName)
+	}
+	structType, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return "", fmt.Errorf("'%s' is not a struct", typeName)
+	}
+	if named.TypeParams().Len() > 0 {
+		return "", fmt.Errorf("'%s' is generic, which is not supported", typeName)
+	}
+
+	file, err := parseMoveFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	receiverName, receiverPointer := stubReceiver(pkg, file.ast, named)
+	var qualifyErr error
+	qualify := func(other *types.Package) string {
+		if other == pkg.Types {
+			return ""
+		}
+		name, err := file.qualifierOf(other.Path(), other.Name())
+		if err != nil && qualifyErr == nil {
+			qualifyErr = err
+		}
+		return name
+	}
+
+	reserved := []string{receiverName, "options", "option"}
+	var fields []constructorField
+	for i := 0; i < structType.NumFields(); i++ {
+		field := classifyConstructorField(structType.Field(i), structType.Tag(i))
+		field.typeName = types.TypeString(structType.Field(i).Type(), qualify)
+		field.param = constructorParamName(field.name, reserved)
+		fields = append(fields, field)
+	}
+
+	var code bytes.Buffer
+	var added []string
+	switch options.Style {
+	case ConstructorPlain, ConstructorFunctionalOptions:
+		added, err = writeConstructor(&code, pkg, named, fields, receiverName, options.Style == ConstructorFunctionalOptions)
+	case ConstructorGetters:
+		added, err = writeGetters(&code, named, fields, receiverName, receiverPointer)
+	}
+	if err != nil {
+		return "", err
+	}
+	if qualifyErr != nil {
+		return "", qualifyErr
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Fields of %s:\n", typeName)
+	for _, field := range fields {
+		status := "optional"
+		if field.skipped {
+			status = "skipped"
+		} else if field.required {
+			status = "required"
+		}
+		fmt.Fprintf(&b, "  %s %s: %s (%s)\n", field.name, field.typeName, status, field.reason)
+	}
+	if len(added) == 0 {
+		fmt.Fprintf(&b, "Nothing to generate for %s", typeName)
+		return b.String(), nil
+	}
+
+	if options.DryRun {
+		fmt.Fprintf(&b, "Code to add after %s in %s (dry run, nothing was written):\n%s", typeName, filePath, strings.TrimPrefix(code.String(), "\n\n"))
+		return b.String(), nil
+	}
+	insertAt := stubInsertionPoint(file, typeName)
+	file.edits = append(file.edits, textEdit{start: insertAt, end: insertAt, replacement: code.String()})
+	if err := file.write(); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "Added %s to %s in %s", strings.Join(added, ", "), typeName, filePath)
+	return b.String(), nil
+}
+
+// classifyConstructorField decides whether the constructor requires the field, from its
+// tags or else from whether its zero value is usable
+func classifyConstructorField(field *types.Var, tag string) constructorField {
+	result := constructorField{name: field.Name()}
+	structTag := reflect.StructTag(tag)
+	_, result.isMap = field.Type().Underlying().(*types.Map)
+
+	if field.Name() == "_" {
+		result.skipped, result.reason = true, "blank field"
+		return result
+	}
+	if value, ok := structTag.Lookup("ctor"); ok {
+		switch value {
+		case "required":
+			result.required = true
+		case "-":
+			result.skipped = true
+		}
+		result.reason = fmt.Sprintf("tag ctor:%q", value)
+		return result
+	}
+	if named, ok := field.Type().(*types.Named); ok && named.Obj().Pkg() != nil {
+		if path := named.Obj().Pkg().Path(); path == "sync" || path == "sync/atomic" {
+			result.skipped, result.reason = true, "synchronization primitive, usable as the zero value"
+			return result
+		}
+	}
+	for _, key := range []string{"validate", "binding"} {
+		value, ok := structTag.Lookup(key)
+		if !ok {
+			continue
+		}
+		rules := strings.Split(value, ",")
+		if slices.Contains(rules, "required") {
+			result.required, result.reason = true, fmt.Sprintf("tag %s:%q", key, value)
+			return result
+		}
+		if slices.Contains(rules, "omitempty") {
+			result.reason = fmt.Sprintf("tag %s:%q", key, value)
+			return result
+		}
+	}
+	if value, ok := structTag.Lookup("json"); ok && slices.Contains(strings.Split(value, ",")[1:], "omitempty") {
+		result.reason = fmt.Sprintf("tag json:%q", value)
+		return result
+	}
+
+	switch field.Type().Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature, *types.Chan:
+		result.required, result.reason = true, "nil is rarely usable"
+	case *types.Map:
+		result.reason = "made by the constructor"
+	default:
+		result.reason = "zero value usable"
+	}
+	return result
+}
+
+// constructorParamName returns the parameter name of a field, e.g. httpClient for
+// HTTPClient, avoiding keywords and the reserved names
+func constructorParamName(fieldName string, reserved []string) string {
+	runes := []rune(fieldName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// The last upper case letter of an initialism starts the next word, as in HTTPClient
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	for i := range upper {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name := string(runes)
+	if token.IsKeyword(name) || slices.Contains(reserved, name) {
+		name += "Value"
+	}
+	return name
+}
+
+// exportedFieldName returns the name of the getter of an unexported field, upper casing
+// common initialisms like id and url as a whole
+func exportedFieldName(fieldName string) string {
+	if slices.Contains([]string{"api", "html", "http", "id", "ip", "json", "sql", "tcp", "udp", "uri", "url", "uuid", "xml"}, fieldName) {
+		return strings.ToUpper(fieldName)
+	}
+	runes := []rune(fieldName)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// checkUndeclared refuses names already declared in the package scope
+func checkUndeclared(pkg *packages.Package, names ...string) error {
+	for _, name := range names {
+		if existing := pkg.Types.Scope().Lookup(name); existing != nil {
+			position := pkg.Fset.Position(existing.Pos())
+			return fmt.Errorf("%s is already declared at %s:%d", name, position.Filename, position.Line)
+		}
+	}
+	return nil
+}
+
+// writeConstructor writes NewT, and with functional options the option type and an option
+// per optional field, returning the names of the declarations
+func writeConstructor(
+	b *bytes.Buffer,
+	pkg *packages.Package,
+	named *types.Named,
+	fields []constructorField,
+	receiverName string,
+	functionalOptions bool,
+) ([]string, error) {
+	typeName := named.Obj().Name()
+	constructorName := "New" + exportedFieldName(typeName)
+	if !named.Obj().Exported() {
+		constructorName = "new" + exportedFieldName(typeName)
+	}
+	optionName := exportedFieldName(typeName) + "Option"
+	if !named.Obj().Exported() {
+		optionName = typeName + "Option"
+	}
+	declared := []string{constructorName}
+	if functionalOptions {
+		declared = append(declared, optionName)
+		for _, field := range fields {
+			if !field.required && !field.skipped {
+				declared = append(declared, "With"+exportedFieldName(field.name))
+			}
+		}
+	}
+	if err := checkUndeclared(pkg, declared...); err != nil {
+		return nil, err
+	}
+
+	var params, values []string
+	for _, field := range fields {
+		switch {
+		case field.skipped:
+		case field.required:
+			params = append(params, field.param+" "+field.typeName)
+			values = append(values, fmt.Sprintf("\t\t%s: %s,\n", field.name, field.param))
+		case field.isMap:
+			values = append(values, fmt.Sprintf("\t\t%s: make(%s),\n", field.name, field.typeName))
+		}
+	}
+	if functionalOptions {
+		params = append(params, "options ..."+optionName)
+		fmt.Fprintf(b, "\n\n// %s configures a %s created by %s\n", optionName, typeName, constructorName)
+		fmt.Fprintf(b, "type %s func(*%s)", optionName, typeName)
+		for _, field := range fields {
+			if field.required || field.skipped {
+				continue
+			}
+			option := "With" + exportedFieldName(field.name)
+			fmt.Fprintf(b, "\n\n// %s sets %s of the %s\n", option, field.name, typeName)
+			fmt.Fprintf(b, "func %s(%s %s) %s {\n", option, field.param, field.typeName, optionName)
+			fmt.Fprintf(b, "\treturn func(%s *%s) {\n\t\t%s.%s = %s\n\t}\n}", receiverName, typeName, receiverName, field.name, field.param)
+		}
+	}
+
+	fmt.Fprintf(b, "\n\n// %s returns a %s with the required fields\n", constructorName, typeName)
+	fmt.Fprintf(b, "func %s(%s) *%s {\n", constructorName, strings.Join(params, ", "), typeName)
+	literal := fmt.Sprintf("&%s{\n%s\t}", typeName, strings.Join(values, ""))
+	if len(values) == 0 {
+		literal = "&" + typeName + "{}"
+	}
+	if !functionalOptions {
+		fmt.Fprintf(b, "\treturn %s\n}", literal)
+		return declared, nil
+	}
+	fmt.Fprintf(b, "\t%s := %s\n", receiverName, literal)
+	fmt.Fprintf(b, "\tfor _, option := range options {\n\t\toption(%s)\n\t}\n\treturn %s\n}", receiverName, receiverName)
+	return declared, nil
+}
+
+// writeGetters writes a getter per unexported field without a method or field of its
+// name, returning the names of the getters
+func writeGetters(
+	b *bytes.Buffer,
+	named *types.Named,
+	fields []constructorField,
+	receiverName string,
+	receiverPointer bool,
+) ([]string, error) {
+	typeName := named.Obj().Name()
+	receiver := typeName
+	if receiverPointer {
+		receiver = "*" + typeName
+	}
+	var added []string
+	for i := range fields {
+		field := &fields[i]
+		if field.skipped || token.IsExported(field.name) {
+			continue
+		}
+		getter := exportedFieldName(field.name)
+		if existing, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), false, named.Obj().Pkg(), getter); existing != nil {
+			field.reason += ", " + getter + " already exists"
+			continue
+		}
+		added = append(added, getter)
+		fmt.Fprintf(b, "\n\n// %s returns %s of the %s\n", getter, field.name, typeName)
+		fmt.Fprintf(b, "func (%s %s) %s() %s {\n\treturn %s.%s\n}", receiverName, receiver, getter, field.typeName, receiverName, field.name)
+	}
+	return added, nil
+}
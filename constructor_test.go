@@ -0,0 +1,194 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateConstructor(t *testing.T) {
+	t.Parallel()
+
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"server/server.go": {
+				"package server", // 1
+				"",
+				"import (", // 3
+				"\t\"log/slog\"",
+				"\t\"sync\"",
+				"\t\"time\"",
+				")",
+				"",
+				"// Server serves requests", // 9
+				"type Server struct {",
+				"\tAddr       string `validate:\"required\"`",
+				"\tHTTPClient Doer",
+				"\tlogger     *slog.Logger `ctor:\"optional\"`",
+				"\ttimeout    time.Duration",
+				"\tid         string `json:\"id,omitempty\"`",
+				"\troutes     map[string]func()",
+				"\tmu         sync.Mutex",
+				"}",
+				"",
+				"// Doer sends requests", // 20
+				"type Doer interface {",
+				"\tDo() error",
+				"}",
+				"",
+				"func (s *Server) Timeout() time.Duration { return s.timeout }", // 25
+				"",
+			},
+		})
+	}
+
+	readFile := func(t testing.TB, path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	build := func(t testing.TB, workspace string) {
+		cmd := exec.Command("go", "build", "./...")
+		cmd.Dir = workspace
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Expected the generated code to build, got: %v\n%s", err, output)
+		}
+	}
+
+	t.Run("constructor", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "server", "server.go")
+
+		result, err := GenerateConstructor(context.Background(), file, "Server", ConstructorOptions{})
+		if err != nil {
+			t.Fatalf("Failed to generate the constructor: %v", err)
+		}
+		for _, expected := range []string{
+			"  Addr string: required (tag validate:\"required\")\n",
+			"  HTTPClient Doer: required (nil is rarely usable)\n",
+			"  logger *slog.Logger: optional (tag ctor:\"optional\")\n",
+			"  timeout time.Duration: optional (zero value usable)\n",
+			"  id string: optional (tag json:\"id,omitempty\")\n",
+			"  mu sync.Mutex: skipped (synchronization primitive, usable as the zero value)\n",
+			"Added NewServer to Server in " + file,
+		} {
+			if !strings.Contains(result, expected) {
+				t.Errorf("Expected the result to contain %q, got:\n%s", expected, result)
+			}
+		}
+		expected := strings.Join([]string{
+			"// NewServer returns a Server with the required fields",
+			"func NewServer(addr string, httpClient Doer) *Server {",
+			"\treturn &Server{",
+			"\t\tAddr:       addr,",
+			"\t\tHTTPClient: httpClient,",
+			"\t\troutes:     make(map[string]func()),",
+			"\t}",
+			"}",
+		}, "\n")
+		if content := readFile(t, file); !strings.Contains(content, "return s.timeout }\n\n"+expected) {
+			t.Errorf("Expected the constructor after the methods:\n%s\ngot:\n%s", expected, content)
+		}
+		build(t, workspace)
+
+		if _, err := GenerateConstructor(context.Background(), file, "Server", ConstructorOptions{}); err == nil || !strings.Contains(err.Error(), "NewServer is already declared at "+file) {
+			t.Errorf("Expected the existing constructor to be refused, got: %v", err)
+		}
+	})
+
+	t.Run("functional options", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "server", "server.go")
+
+		result, err := GenerateConstructor(context.Background(), file, "Server", ConstructorOptions{Style: ConstructorFunctionalOptions})
+		if err != nil {
+			t.Fatalf("Failed to generate the options: %v", err)
+		}
+		if !strings.HasSuffix(result, "Added NewServer, ServerOption, WithLogger, WithTimeout, WithID, WithRoutes to Server in "+file) {
+			t.Errorf("Unexpected result:\n%s", result)
+		}
+		content := readFile(t, file)
+		for _, expected := range []string{
+			"// ServerOption configures a Server created by NewServer\ntype ServerOption func(*Server)\n",
+			"// WithLogger sets logger of the Server\nfunc WithLogger(logger *slog.Logger) ServerOption {\n\treturn func(s *Server) {\n\t\ts.logger = logger\n\t}\n}",
+			"func NewServer(addr string, httpClient Doer, options ...ServerOption) *Server {\n\ts := &Server{\n",
+			"\tfor _, option := range options {\n\t\toption(s)\n\t}\n\treturn s\n}",
+		} {
+			if !strings.Contains(content, expected) {
+				t.Errorf("Expected the file to contain %q, got:\n%s", expected, content)
+			}
+		}
+		build(t, workspace)
+	})
+
+	t.Run("getters dry run", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "server", "server.go")
+		before := readFile(t, file)
+
+		result, err := GenerateConstructor(context.Background(), file, "Server", ConstructorOptions{Style: ConstructorGetters, DryRun: true})
+		if err != nil {
+			t.Fatalf("Failed to generate the getters: %v", err)
+		}
+		for _, expected := range []string{
+			"  timeout time.Duration: optional (zero value usable, Timeout already exists)\n",
+			"(dry run, nothing was written):\n// Logger returns logger of the Server\nfunc (s *Server) Logger() *slog.Logger {\n\treturn s.logger\n}",
+			"func (s *Server) ID() string {",
+		} {
+			if !strings.Contains(result, expected) {
+				t.Errorf("Expected the result to contain %q, got:\n%s", expected, result)
+			}
+		}
+		if strings.Contains(result, "Mu()") || strings.Contains(result, "Addr()") {
+			t.Errorf("Expected no getters of exported and skipped fields, got:\n%s", result)
+		}
+		if readFile(t, file) != before {
+			t.Error("Expected the dry run to leave the file untouched")
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "server", "server.go")
+
+		for _, test := range []struct {
+			typeName string
+			style    ConstructorStyle
+			expected string
+		}{
+			{"Doer", ConstructorPlain, "'Doer' is not a struct"},
+			{"Missing", ConstructorPlain, "no type 'Missing' declared"},
+			{"Server", "builder", "unknown style \"builder\""},
+		} {
+			if _, err := GenerateConstructor(context.Background(), file, test.typeName, ConstructorOptions{Style: test.style}); err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected %q for %s, got: %v", test.expected, test.typeName, err)
+			}
+		}
+	})
+
+	t.Run("parameter names", func(t *testing.T) {
+		t.Parallel()
+		for field, expected := range map[string]string{
+			"HTTPClient": "httpClient",
+			"ID":         "id",
+			"Type":       "typeValue",
+			"s":          "sValue",
+			"name":       "name",
+		} {
+			if got := constructorParamName(field, []string{"s"}); got != expected {
+				t.Errorf("Expected %s for %s, got %s", expected, field, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	contextAtToolName        = "context_at"
+	contextAtToolDescription = `Describes the surroundings of a line in a Go file: the source of the enclosing top-level declaration, the hierarchy of enclosing scopes and the identifiers visible at the line with their types, innermost scope first. Use it before editing at a location to know what is in scope.
+
+Identifiers of the package scope are only listed with include_package_scope, as packages declare many. Imports are always listed.`
+)
+
+func AddContextAtTool(mcpServer *server.MCPServer) {
+	handleContextAt := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		lineNumber, err := request.RequireInt("line_number")
+		if err != nil {
+			return nil, err
+		}
+		includePackageScope := request.GetBool("include_package_scope", false)
+
+		result, err := ContextAt(ctx, filePath, lineNumber, includePackageScope)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error describing context: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		contextAtToolName,
+		mcp.WithDescription(contextAtToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("line_number",
+			mcp.Description("Line number to describe the context of"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("include_package_scope",
+			mcp.Description("Whether to list the declarations of the package scope among the visible identifiers"),
+			mcp.DefaultBool(false),
+		),
+	), handleContextAt)
+}
+
+// LineContext describes the surroundings of a line in a Go file
+type LineContext struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Declaration is the source of the enclosing top-level declaration including its doc
+	// comment, empty when the line is not part of one
+	Declaration      string     `json:"declaration,omitempty"`
+	DeclarationStart int        `json:"declaration_start,omitempty"`
+	DeclarationEnd   int        `json:"declaration_end,omitempty"`
+	Scope            *ScopeInfo `json:"scope,omitempty"`
+	// Identifiers are the identifiers visible at the line, innermost scope first.
+	// IdentifiersError is set when the package could not be type checked.
+	Identifiers      []VisibleIdentifier `json:"identifiers,omitempty"`
+	IdentifiersError string              `json:"identifiers_error,omitempty"`
+}
+
+// VisibleIdentifier is an identifier in scope at a line
+type VisibleIdentifier struct {
+	Name string `json:"name"`
+	// Object describes the identifier with its kind and type, e.g. var count int
+	Object string `json:"object"`
+	// Scope is the scope declaring the identifier: local, file or package
+	Scope string `json:"scope"`
+	// Line is the line of the declaration, zero for identifiers declared in other files
+	Line int `json:"line,omitempty"`
+}
+
+// ContextAt describes the enclosing declaration, scopes and visible identifiers at lineNumber
+// of filePath. The identifiers of the package scope are only listed with includePackageScope.
+func ContextAt(ctx context.Context, filePath string, lineNumber int, includePackageScope bool) (*LineContext, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if lineNumber <= 0 {
+		return nil, fmt.Errorf("line number must be positive, got %d", lineNumber)
+	}
+	cached, err := globalFileCache.GetOrParseFile(filePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if lineNumber > cached.fset.File(cached.ast.Pos()).LineCount() {
+		return nil, fmt.Errorf("line number %d exceeds the length of %s", lineNumber, filePath)
+	}
+
+	result := &LineContext{
+		File:  filePath,
+		Line:  lineNumber,
+		Scope: findScope(filePath, lineNumber, nil),
+	}
+	for _, decl := range cached.ast.Decls {
+		start := decl.Pos()
+		if doc := declDoc(decl); doc != nil {
+			start = doc.Pos()
+		}
+		startLine := cached.fset.Position(start).Line
+		endLine := cached.fset.Position(decl.End()).Line
+		if lineNumber >= startLine && lineNumber <= endLine {
+			result.DeclarationStart = startLine
+			result.DeclarationEnd = endLine
+			result.Declaration = readDeclarationSource(filePath, startLine, endLine, nil)
+			break
+		}
+	}
+
+	identifiers, err := visibleIdentifiers(ctx, filePath, lineNumber, includePackageScope)
+	if err != nil {
+		result.IdentifiersError = err.Error()
+	}
+	result.Identifiers = identifiers
+	return result, nil
+}
+
+// declDoc returns the doc comment of a top-level declaration
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		return decl.Doc
+	case *ast.GenDecl:
+		return decl.Doc
+	}
+	return nil
+}
+
+// loadFilePackage type checks the package of the Go file with its dependencies, together with
+// the packages matching extraPatterns in the same universe, and returns the package with the
+// syntax of the file and all loaded packages
+func loadFilePackage(ctx context.Context, filePath string, extraPatterns ...string) (*packages.Package, *ast.File, []*packages.Package, error) {
+	return loadOverlaidFilePackage(ctx, filePath, nil, extraPatterns...)
+}
+
+// loadOverlaidFilePackage is loadFilePackage with the files of the overlay replacing those on disk
+func loadOverlaidFilePackage(ctx context.Context, filePath string, overlay Overlay, extraPatterns ...string) (*packages.Package, *ast.File, []*packages.Package, error) {
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:     filepath.Dir(filePath),
+		Env:     packagesEnv(filepath.Dir(filePath)),
+		Overlay: overlay,
+	}, append([]string{"file=" + filePath}, extraPatterns...)...)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load the package of %s: %w", filePath, err)
+	}
+	for _, pkg := range pkgs {
+		if !slices.Contains(pkg.GoFiles, filePath) {
+			continue
+		}
+		// Type errors still leave usable types, only a package without them fails
+		if pkg.Types == nil || pkg.TypesInfo == nil {
+			return nil, nil, nil, classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+		}
+		for _, file := range pkg.Syntax {
+			if pkg.Fset.Position(file.Pos()).Filename == filePath {
+				return pkg, file, pkgs, nil
+			}
+		}
+		return nil, nil, nil, fmt.Errorf("%s was not type checked", filePath)
+	}
+	return nil, nil, nil, fmt.Errorf("no package found for %s", filePath)
+}
+
+// lineScopePos returns the position of the first non-blank character of the line in the
+// type checked file, which places it inside the blocks starting on the line
+func lineScopePos(pkg *packages.Package, file *ast.File, lineNumber int) token.Pos {
+	tokenFile := pkg.Fset.File(file.Pos())
+	pos := tokenFile.LineStart(lineNumber)
+	if line, err := readSourceLines(tokenFile.Name(), lineNumber, lineNumber, nil); err == nil {
+		pos += token.Pos(len(line) - len(strings.TrimLeft(line, " \t")))
+	}
+	return pos
+}
+
+// visibleIdentifiers type checks the package of filePath and lists the identifiers in scope
+// at the first non-blank character of lineNumber, innermost scope first
+func visibleIdentifiers(ctx context.Context, filePath string, lineNumber int, includePackageScope bool) ([]VisibleIdentifier, error) {
+	pkg, file, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	pos := lineScopePos(pkg, file, lineNumber)
+
+	scope := pkg.Types.Scope().Innermost(pos)
+	if scope == nil {
+		scope = pkg.TypesInfo.Scopes[file]
+	}
+	qualifier := types.RelativeTo(pkg.Types)
+	var identifiers []VisibleIdentifier
+	for current := scope; current != nil && current != types.Universe; current = current.Parent() {
+		scopeName := "local"
+		switch {
+		case current == pkg.Types.Scope():
+			if !includePackageScope {
+				continue
+			}
+			scopeName = "package"
+		case current.Parent() == pkg.Types.Scope():
+			scopeName = "file"
+		}
+		for _, name := range current.Names() {
+			obj := current.Lookup(name)
+			// Shadowed identifiers and locals declared after the line are not visible
+			if _, visible := scope.LookupParent(name, pos); visible != obj || name == "_" {
+				continue
+			}
+			identifier := VisibleIdentifier{
+				Name:   name,
+				Object: types.ObjectString(obj, qualifier),
+				Scope:  scopeName,
+			}
+			if position := pkg.Fset.Position(obj.Pos()); position.Filename == filePath {
+				identifier.Line = position.Line
+			}
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	return identifiers, nil
+}
+
+// String formats the context as human and model readable text
+func (result *LineContext) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "File: %s:%d\n", result.File, result.Line)
+
+	if result.Declaration != "" {
+		fmt.Fprintf(&b, "\nDeclaration (lines %d-%d):\n%s\n", result.DeclarationStart, result.DeclarationEnd, result.Declaration)
+	}
+	if result.Scope != nil {
+		b.WriteString("\n")
+		writeScope(&b, result.Scope)
+	}
+
+	b.WriteString("\nVisible Identifiers:\n")
+	if result.IdentifiersError != "" {
+		b.WriteString(result.IdentifiersError)
+		b.WriteString("\n")
+	}
+	for _, identifier := range result.Identifiers {
+		fmt.Fprintf(&b, "%s (%s", identifier.Object, identifier.Scope)
+		if identifier.Line > 0 {
+			fmt.Fprintf(&b, ", line %d", identifier.Line)
+		}
+		b.WriteString(")\n")
+	}
+	return b.String()
+}
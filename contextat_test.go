@@ -0,0 +1,128 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestContextAt(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with nested scopes
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",   // 1
+				"",               // 2
+				"import \"fmt\"", // 3
+				"",               // 4
+				"var limit = 3",  // 5
+				"",               // 6
+				"// count prints the values below the limit", // 7
+				"func count(values []int) int {",             // 8
+				"\ttotal := 0",                               // 9
+				"\tfor i, value := range values {",           // 10
+				"\t\tif value < limit {",                     // 11
+				"\t\t\tfmt.Println(i)",                       // 12
+				"\t\t}",                                      // 13
+				"\t\ttotal := value",                         // 14
+				"\t\t_ = total",                              // 15
+				"\t}",                                        // 16
+				"\treturn total",                             // 17
+				"}",                                          // 18
+				"",                                           // 19
+				"func main() { count(nil) }",                 // 20
+				"",                                           // 21
+			},
+		})
+	}
+
+	// Helper function to format the identifiers as object (scope)
+	describe := func(identifiers []VisibleIdentifier) []string {
+		var described []string
+		for _, identifier := range identifiers {
+			described = append(described, identifier.Object+" ("+identifier.Scope+")")
+		}
+		return described
+	}
+
+	t.Run("nested scopes", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		result, err := ContextAt(context.Background(), file, 12, false)
+		if err != nil {
+			t.Fatalf("Failed to describe context: %v", err)
+		}
+		if result.DeclarationStart != 7 || result.DeclarationEnd != 18 || !strings.HasPrefix(result.Declaration, "// count prints") {
+			t.Errorf("Expected the count function with its doc, got lines %d-%d:\n%s", result.DeclarationStart, result.DeclarationEnd, result.Declaration)
+		}
+		if result.Scope == nil || len(result.Scope.Hierarchy) < 2 || result.Scope.Hierarchy[1] != "function count (lines 8-18)" {
+			t.Errorf("Expected the scope hierarchy to include count, got %+v", result.Scope)
+		}
+		expected := []string{
+			"var i int (local)",
+			"var value int (local)",
+			"var total int (local)",
+			"var values []int (local)",
+			"package fmt (file)",
+		}
+		if got := describe(result.Identifiers); result.IdentifiersError != "" || strings.Join(got, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected identifiers %v, got %v (%s)", expected, got, result.IdentifiersError)
+		}
+		if result.Identifiers[2].Line != 9 {
+			t.Errorf("Expected the outer total declared at line 9 before the inner one, got %+v", result.Identifiers[2])
+		}
+	})
+
+	t.Run("package scope", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		result, err := ContextAt(context.Background(), file, 5, true)
+		if err != nil {
+			t.Fatalf("Failed to describe context: %v", err)
+		}
+		if result.Declaration != "var limit = 3" {
+			t.Errorf("Expected the limit declaration, got %q", result.Declaration)
+		}
+		got := strings.Join(describe(result.Identifiers), "\n")
+		for _, expected := range []string{"func count(values []int) int (package)", "var limit int (package)"} {
+			if !strings.Contains(got, expected) {
+				t.Errorf("Expected %q among the identifiers, got:\n%s", expected, got)
+			}
+		}
+	})
+
+	t.Run("context at tool", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      contextAtToolName,
+				"arguments": map[string]any{"file_path": file, "line_number": 15},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		for _, expected := range []string{"Declaration (lines 7-18):\n// count prints", "Visible Identifiers:\nvar total int (local, line 14)\n"} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the result, got: %s", expected, text)
+			}
+		}
+	})
+}
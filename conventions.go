@@ -0,0 +1,410 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	conventionsToolName        = "conventions"
+	conventionsToolDescription = `Infers the coding conventions of a Go module from its code: the casing, punctuation and wrapping of error messages, the naming and return kind of constructors, the style and kind of method receivers, the logging library and whether context.Context comes first in parameter lists.
+
+Returns a JSON profile where each convention has the value most of the code follows, the share of the code following it (confidence) and the number of samples. Receiver names are listed per type. Code generated or written by hand should follow the profile.`
+)
+
+// loggingLibraries are the import paths of the recognized logging libraries
+var loggingLibraries = []string{
+	"log",
+	"log/slog",
+	"go.uber.org/zap",
+	"github.com/rs/zerolog",
+	"github.com/sirupsen/logrus",
+	"github.com/go-kit/log",
+	"github.com/golang/glog",
+	"k8s.io/klog",
+	"github.com/charmbracelet/log",
+}
+
+// Convention is the value most samples of a convention follow
+type Convention struct {
+	// Value is empty when the code has no samples of the convention
+	Value string `json:"value"`
+	// Confidence is the share of samples following the value
+	Confidence float64 `json:"confidence"`
+	Samples    int     `json:"samples"`
+}
+
+// ConventionProfile holds the coding conventions of a module
+type ConventionProfile struct {
+	ModulePath string `json:"module_path"`
+
+	// ErrorCasing is lowercase or capitalized, by the first letter of error messages
+	ErrorCasing Convention `json:"error_casing"`
+	// ErrorPunctuation is none or the punctuation ending error messages
+	ErrorPunctuation Convention `json:"error_punctuation"`
+	// ErrorWrapping is how errors are wrapped: fmt.Errorf %w, fmt.Errorf %v or errors.Wrap
+	ErrorWrapping Convention `json:"error_wrapping"`
+
+	// ConstructorPrefix is the prefix of functions returning a type of their package, e.g. New
+	ConstructorPrefix Convention `json:"constructor_prefix"`
+	// ConstructorReturns is pointer or value
+	ConstructorReturns Convention `json:"constructor_returns"`
+
+	// ReceiverStyle is short (one or two letters), word, self or this
+	ReceiverStyle Convention `json:"receiver_style"`
+	// ReceiverKind is pointer or value
+	ReceiverKind Convention `json:"receiver_kind"`
+	// ReceiverNames maps types, as import path and type name, to their receiver name
+	ReceiverNames map[string]string `json:"receiver_names,omitempty"`
+
+	// Logging is the import path of the logging library most files use
+	Logging Convention `json:"logging"`
+	// ContextFirst is first or not first, by the position of context.Context parameters
+	ContextFirst Convention `json:"context_first"`
+}
+
+func AddConventionsTool(mcpServer *server.MCPServer) {
+	handleConventions := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		profile, err := Conventions(workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error inferring conventions: %v", err)), nil
+		}
+		encoded, err := json.MarshalIndent(profile, "", "  ")
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error encoding conventions: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(encoded)), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		conventionsToolName,
+		mcp.WithDescription(conventionsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to profile"),
+			mcp.Required(),
+		),
+	), handleConventions)
+}
+
+// conventionCounts counts the samples of a convention by value
+type conventionCounts map[string]int
+
+// convention returns the most common value, preferring the first value by name on ties
+func (counts conventionCounts) convention() Convention {
+	var convention Convention
+	best := 0
+	for value, count := range counts {
+		convention.Samples += count
+		if count > best || (count == best && value < convention.Value) {
+			convention.Value = value
+			best = count
+		}
+	}
+	if convention.Samples > 0 {
+		// Truncated to two decimals to keep the profile readable
+		convention.Confidence = float64(best*100/convention.Samples) / 100
+	}
+	return convention
+}
+
+// Conventions infers the coding conventions of the module containing workspaceDir from
+// its non-test files
+func Conventions(workspaceDir string) (*ConventionProfile, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := loadModuleSources(workspaceDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]conventionCounts{}
+	count := func(name string, value string) {
+		if counts[name] == nil {
+			counts[name] = conventionCounts{}
+		}
+		counts[name][value]++
+	}
+	// receivers counts the receiver names of each type
+	receivers := make(map[string]conventionCounts)
+
+	for _, pkg := range module.packages {
+		types := make(map[string]bool)
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.TYPE {
+					for _, spec := range decl.Specs {
+						types[spec.(*ast.TypeSpec).Name.Name] = true
+					}
+				}
+			}
+		}
+
+		for _, file := range pkg.files {
+			imports := fileImportNames(file.ast)
+			for _, importPath := range imports {
+				// The longest match keeps log/slog from counting as log
+				match := ""
+				for _, library := range loggingLibraries {
+					if (importPath == library || strings.HasPrefix(importPath, library+"/")) && len(library) > len(match) {
+						match = library
+					}
+				}
+				if match != "" {
+					count("logging", match)
+				}
+			}
+
+			ast.Inspect(file.ast, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.CallExpr:
+					countErrorConventions(node, imports, count)
+				case *ast.FuncType:
+					countContextPosition(node, imports, count)
+				}
+				return true
+			})
+
+			for _, decl := range file.ast.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				if fn.Recv == nil {
+					if kind, prefix := constructorOf(fn, types); prefix != "" {
+						count("constructor_prefix", prefix)
+						count("constructor_returns", kind)
+					}
+					continue
+				}
+				if len(fn.Recv.List) == 0 {
+					continue
+				}
+				receiver := fn.Recv.List[0]
+				if _, ok := receiver.Type.(*ast.StarExpr); ok {
+					count("receiver_kind", "pointer")
+				} else {
+					count("receiver_kind", "value")
+				}
+				if len(receiver.Names) == 0 || receiver.Names[0].Name == "_" {
+					continue
+				}
+				name := receiver.Names[0].Name
+				count("receiver_style", receiverStyle(name))
+				typeName := pkg.importPath + "." + receiverTypeName(receiver.Type)
+				if receivers[typeName] == nil {
+					receivers[typeName] = conventionCounts{}
+				}
+				receivers[typeName][name]++
+			}
+		}
+	}
+
+	profile := &ConventionProfile{
+		ModulePath:         module.path,
+		ErrorCasing:        counts["error_casing"].convention(),
+		ErrorPunctuation:   counts["error_punctuation"].convention(),
+		ErrorWrapping:      counts["error_wrapping"].convention(),
+		ConstructorPrefix:  counts["constructor_prefix"].convention(),
+		ConstructorReturns: counts["constructor_returns"].convention(),
+		ReceiverStyle:      counts["receiver_style"].convention(),
+		ReceiverKind:       counts["receiver_kind"].convention(),
+		Logging:            counts["logging"].convention(),
+		ContextFirst:       counts["context_first"].convention(),
+	}
+	if len(receivers) > 0 {
+		profile.ReceiverNames = make(map[string]string, len(receivers))
+		for typeName, names := range receivers {
+			profile.ReceiverNames[typeName] = names.convention().Value
+		}
+	}
+	return profile, nil
+}
+
+// ReceiverName returns the receiver name to use for a new method of the type, following
+// the existing methods of the type or else the receiver style of the module
+func (profile *ConventionProfile) ReceiverName(importPath string, typeName string) string {
+	if name, ok := profile.ReceiverNames[importPath+"."+typeName]; ok {
+		return name
+	}
+	switch profile.ReceiverStyle.Value {
+	case "self", "this":
+		return profile.ReceiverStyle.Value
+	case "word":
+		first, size := utf8.DecodeRuneInString(typeName)
+		return string(unicode.ToLower(first)) + typeName[size:]
+	}
+	first, _ := utf8.DecodeRuneInString(typeName)
+	return string(unicode.ToLower(first))
+}
+
+// fileImportNames maps the names under which the file refers to its imports to their
+// import paths, guessing the package name from the last path element
+func fileImportNames(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else if strings.HasPrefix(name, "v") && strings.Trim(name[1:], "0123456789") == "" && name != "v" {
+			// Major version suffixes are not part of the package name
+			name = path.Base(path.Dir(importPath))
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+// calledFunction returns the import path and name of a package level function call
+func calledFunction(call *ast.CallExpr, imports map[string]string) (string, string) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok || ident.Obj != nil {
+		return "", ""
+	}
+	return imports[ident.Name], selector.Sel.Name
+}
+
+// countErrorConventions counts the casing, punctuation and wrapping of an error creating call
+func countErrorConventions(call *ast.CallExpr, imports map[string]string, count func(string, string)) {
+	importPath, name := calledFunction(call, imports)
+	messageArg := -1
+	switch {
+	case importPath == "errors" && name == "New",
+		importPath == "fmt" && name == "Errorf",
+		strings.HasSuffix(importPath, "/errors") && (name == "New" || name == "Errorf"):
+		messageArg = 0
+	case strings.HasSuffix(importPath, "/errors") && (name == "Wrap" || name == "Wrapf"):
+		messageArg = 1
+		count("error_wrapping", "errors.Wrap")
+	}
+	if messageArg < 0 || len(call.Args) <= messageArg {
+		return
+	}
+	literal, ok := call.Args[messageArg].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return
+	}
+	message, err := strconv.Unquote(literal.Value)
+	if err != nil || message == "" {
+		return
+	}
+
+	if importPath == "fmt" {
+		switch {
+		case strings.Contains(message, "%w"):
+			count("error_wrapping", "fmt.Errorf %w")
+		case strings.HasSuffix(message, "%v") && isErrorArgument(call.Args[len(call.Args)-1]):
+			count("error_wrapping", "fmt.Errorf %v")
+		}
+	}
+
+	// Messages starting with a verb, an acronym or an identifier say nothing about casing
+	firstWord, _, _ := strings.Cut(message, " ")
+	first, _ := utf8.DecodeRuneInString(message)
+	switch {
+	case !unicode.IsLetter(first), strings.ContainsAny(firstWord, "_.()%"):
+	case unicode.IsLower(first):
+		count("error_casing", "lowercase")
+	case firstWord == strings.ToUpper(firstWord) && len(firstWord) > 1:
+	default:
+		count("error_casing", "capitalized")
+	}
+	switch last, _ := utf8.DecodeLastRuneInString(message); last {
+	case '.', '!':
+		count("error_punctuation", string(last))
+	default:
+		count("error_punctuation", "none")
+	}
+}
+
+// isErrorArgument reports whether the expression looks like an error, e.g. err or parseErr
+func isErrorArgument(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && (ident.Name == "err" || strings.HasSuffix(ident.Name, "Err"))
+}
+
+// countContextPosition counts whether a context.Context parameter comes first
+func countContextPosition(fn *ast.FuncType, imports map[string]string, count func(string, string)) {
+	if fn.Params == nil {
+		return
+	}
+	index := 0
+	for _, field := range fn.Params.List {
+		if selector, ok := field.Type.(*ast.SelectorExpr); ok && selector.Sel.Name == "Context" {
+			if ident, ok := selector.X.(*ast.Ident); ok && imports[ident.Name] == "context" {
+				if index == 0 {
+					count("context_first", "first")
+				} else {
+					count("context_first", "not first")
+				}
+				return
+			}
+		}
+		index += max(1, len(field.Names))
+	}
+}
+
+// constructorOf returns whether the function returns a pointer or value of a type of its
+// package, and the prefix of its name before the type name, e.g. New for NewServer
+func constructorOf(fn *ast.FuncDecl, types map[string]bool) (string, string) {
+	if fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+		return "", ""
+	}
+	result := fn.Type.Results.List[0].Type
+	kind := "value"
+	if star, ok := result.(*ast.StarExpr); ok {
+		kind = "pointer"
+		result = star.X
+	}
+	ident, ok := result.(*ast.Ident)
+	if !ok || !types[ident.Name] {
+		return "", ""
+	}
+	name := fn.Name.Name
+	for _, prefix := range []string{"New", "Make", "Create", "Build", "new", "make"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && (rest == "" || strings.EqualFold(rest, ident.Name)) {
+			return kind, prefix
+		}
+	}
+	return "", ""
+}
+
+// receiverStyle classifies a receiver name
+func receiverStyle(name string) string {
+	switch {
+	case name == "self" || name == "this":
+		return name
+	case utf8.RuneCountInString(name) <= 2:
+		return "short"
+	}
+	return "word"
+}
@@ -0,0 +1,115 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestConventionProfile(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module following mostly consistent conventions
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"server/server.go": {
+				"package server",                    // 1
+				"",                                  // 2
+				"import (",                          // 3
+				"    \"context\"",                   // 4
+				"    \"errors\"",                    // 5
+				"    \"fmt\"",                       // 6
+				"    \"log/slog\"",                  // 7
+				")",                                 // 8
+				"",                                  // 9
+				"type Server struct{ name string }", // 10
+				"",                                  // 11
+				"func NewServer(name string) *Server { return &Server{name: name} }", // 12
+				"", // 13
+				"func (s *Server) Start(ctx context.Context) error {", // 14
+				"    if s.name == \"\" {",                             // 15
+				"        return errors.New(\"missing name\")",         // 16
+				"    }",                    // 17
+				"    slog.Info(\"start\")", // 18
+				"    return nil",           // 19
+				"}",                        // 20
+				"",                         // 21
+				"func (s *Server) Stop(ctx context.Context, force bool) error {",    // 22
+				"    if err := s.Start(ctx); err != nil {",                          // 23
+				"        return fmt.Errorf(\"failed to stop %s: %w\", s.name, err)", // 24
+				"    }", // 25
+				"    return fmt.Errorf(\"Stopping is not supported.\")", // 26
+				"}", // 27
+				"",  // 28
+				"func (srv Server) Name(prefix string, ctx context.Context) string {", // 29
+				"    return errors.New(\"unnamed server\").Error()",                   // 30
+				"}", // 31
+			},
+		})
+	}
+
+	t.Run("profile", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		profile, err := Conventions(workspace)
+		if err != nil {
+			t.Fatalf("Failed to infer conventions: %v", err)
+		}
+		for name, test := range map[string]struct {
+			convention Convention
+			expected   Convention
+		}{
+			"error casing":        {profile.ErrorCasing, Convention{Value: "lowercase", Confidence: 0.75, Samples: 4}},
+			"error punctuation":   {profile.ErrorPunctuation, Convention{Value: "none", Confidence: 0.75, Samples: 4}},
+			"error wrapping":      {profile.ErrorWrapping, Convention{Value: "fmt.Errorf %w", Confidence: 1, Samples: 1}},
+			"constructor prefix":  {profile.ConstructorPrefix, Convention{Value: "New", Confidence: 1, Samples: 1}},
+			"constructor returns": {profile.ConstructorReturns, Convention{Value: "pointer", Confidence: 1, Samples: 1}},
+			"receiver style":      {profile.ReceiverStyle, Convention{Value: "short", Confidence: 0.66, Samples: 3}},
+			"receiver kind":       {profile.ReceiverKind, Convention{Value: "pointer", Confidence: 0.66, Samples: 3}},
+			"logging":             {profile.Logging, Convention{Value: "log/slog", Confidence: 1, Samples: 1}},
+			"context first":       {profile.ContextFirst, Convention{Value: "first", Confidence: 0.66, Samples: 3}},
+		} {
+			if test.convention != test.expected {
+				t.Errorf("Expected %s %+v, got %+v", name, test.expected, test.convention)
+			}
+		}
+
+		if name := profile.ReceiverName("example.com/app/server", "Server"); name != "s" {
+			t.Errorf("Expected the receiver name of Server to be s, got %q", name)
+		}
+		if name := profile.ReceiverName("example.com/app/server", "Client"); name != "c" {
+			t.Errorf("Expected a short receiver name for new types, got %q", name)
+		}
+	})
+
+	t.Run("conventions tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      conventionsToolName,
+				"arguments": map[string]any{"workspace_dir": workspace},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		var profile ConventionProfile
+		if err := json.Unmarshal([]byte(toolResultText(&result)), &profile); err != nil || result.IsError {
+			t.Fatalf("Expected a JSON profile, got: %s", toolResultText(&result))
+		}
+		if profile.ModulePath != "example.com/app" || profile.ReceiverNames["example.com/app/server.Server"] != "s" {
+			t.Errorf("Expected the module path and receiver names, got %+v", profile)
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+const (
+	deadcodeToolName        = "deadcode"
+	deadcodeToolDescription = `Lists the functions and methods of a Go module that are unreachable from its entry points, the main and init functions of its main packages, grouped by package. Reachability follows calls, including dynamic calls through interfaces and function values, by Rapid Type Analysis like golang.org/x/tools/cmd/deadcode, so a method is live when its type is converted to an interface with that method. Functions in generated files are not listed.
+
+Pass prefix to only list the functions of packages whose import path starts with it. With include_tests the tests of the module are entry points too, so functions only used by tests are not reported.`
+)
+
+func AddDeadcodeTool(mcpServer *server.MCPServer) {
+	handleDeadcode := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		prefix := request.GetString("prefix", "")
+		includeTests := request.GetBool("include_tests", false)
+
+		report, err := DeadCode(ctx, workspaceDir, prefix, includeTests)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding dead code: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		deadcodeToolName,
+		mcp.WithDescription(deadcodeToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to check"),
+			mcp.Required(),
+		),
+		mcp.WithString("prefix",
+			mcp.Description("Only list functions of packages with this import path prefix, e.g. example.com/app/internal"),
+		),
+		mcp.WithBoolean("include_tests",
+			mcp.Description("Also use the tests of the module as entry points"),
+		),
+	), handleDeadcode)
+}
+
+// DeadCodeReport lists the unreachable functions of a module
+type DeadCodeReport struct {
+	Module string `json:"module"`
+	// Prefix is the import path prefix the packages were limited to
+	Prefix   string            `json:"prefix,omitempty"`
+	Packages []DeadCodePackage `json:"packages,omitempty"`
+}
+
+// DeadCodePackage holds the unreachable functions of a package
+type DeadCodePackage struct {
+	Path      string             `json:"path"`
+	Functions []DeadCodeFunction `json:"functions"`
+}
+
+// DeadCodeFunction is an unreachable function or method
+type DeadCodeFunction struct {
+	// Name is the function name, prefixed by the receiver type name for methods, e.g. Store.Close
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// DeadCode reports the functions of the module containing workspaceDir that are unreachable
+// from the main and init functions of its main packages, and from its tests when includeTests
+// is set. Only packages whose import path starts with prefix are reported when it is not empty.
+func DeadCode(ctx context.Context, workspaceDir string, prefix string, includeTests bool) (*DeadCodeReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	root, err := findModuleRoot(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	initial, err := loadPackages(ctx, &packages.Config{
+		Mode:  packages.LoadAllSyntax | packages.NeedModule,
+		Dir:   root,
+		Tests: includeTests,
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	if len(initial) == 0 {
+		return nil, fmt.Errorf("no packages found in %s", root)
+	}
+	for _, pkg := range initial {
+		if len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors))
+		}
+	}
+	report := &DeadCodeReport{Prefix: prefix}
+	if initial[0].Module != nil {
+		report.Module = initial[0].Module.Path
+	}
+
+	prog, pkgs := ssautil.AllPackages(initial, ssa.InstantiateGenerics)
+	buildProgram(prog)
+	mains := ssautil.MainPackages(pkgs)
+	if len(mains) == 0 {
+		return nil, fmt.Errorf("no main packages in %s, dead code is found from their entry points", root)
+	}
+	var roots []*ssa.Function
+	for _, main := range mains {
+		roots = append(roots, main.Func("init"), main.Func("main"))
+	}
+	result := rta.Analyze(roots, false)
+
+	// Test variants hold distinct functions for the same declarations, so reachability is
+	// tracked by position: a declaration is live when any of its variants is
+	reachable := make(map[token.Position]bool)
+	for fn := range result.Reachable {
+		if fn.Pos().IsValid() || fn.Name() == "init" {
+			reachable[prog.Fset.Position(fn.Pos())] = true
+		}
+	}
+
+	byPackage := make(map[string][]DeadCodeFunction)
+	for _, pkg := range initial {
+		if prefix != "" && pkg.PkgPath != prefix && !strings.HasPrefix(pkg.PkgPath, prefix+"/") {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			if ast.IsGenerated(file) {
+				continue
+			}
+			for _, decl := range file.Decls {
+				decl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				obj, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+				if !ok {
+					continue
+				}
+				position := prog.Fset.Position(obj.Pos())
+				if reachable[position] {
+					continue
+				}
+				// Reported once, though test variants declare it again
+				reachable[position] = true
+				byPackage[pkg.PkgPath] = append(byPackage[pkg.PkgPath], DeadCodeFunction{
+					Name: deadCodeName(obj),
+					File: position.Filename,
+					Line: position.Line,
+				})
+			}
+		}
+	}
+
+	for path, functions := range byPackage {
+		// Declaration order keeps related methods together
+		sort.Slice(functions, func(i, j int) bool {
+			if functions[i].File != functions[j].File {
+				return functions[i].File < functions[j].File
+			}
+			return functions[i].Line < functions[j].Line
+		})
+		report.Packages = append(report.Packages, DeadCodePackage{Path: path, Functions: functions})
+	}
+	sort.Slice(report.Packages, func(i, j int) bool { return report.Packages[i].Path < report.Packages[j].Path })
+	return report, nil
+}
+
+// buildProgram builds the SSA form of all packages of prog. The functions of packages the
+// builder fails on, e.g. using syntax of a newer Go release than it supports, are left
+// without bodies, which analyses treat as external functions.
+func buildProgram(prog *ssa.Program) {
+	failed := make(map[*ssa.Package]bool)
+	for _, pkg := range prog.AllPackages() {
+		func() {
+			defer func() {
+				if recover() != nil {
+					failed[pkg] = true
+				}
+			}()
+			pkg.Build()
+		}()
+	}
+	if len(failed) == 0 {
+		return
+	}
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Pkg != nil && failed[fn.Pkg] {
+			fn.Blocks = nil
+		}
+	}
+}
+
+// deadCodeName returns the name of a function, prefixed by its receiver type name for methods
+func deadCodeName(fn *types.Func) string {
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return fn.Name()
+	}
+	typ := recv.Type()
+	if pointer, ok := typ.(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+	switch named := typ.(type) {
+	case *types.Named:
+		return named.Obj().Name() + "." + fn.Name()
+	case *types.Alias:
+		return named.Obj().Name() + "." + fn.Name()
+	}
+	return fn.Name()
+}
+
+func (report *DeadCodeReport) String() string {
+	scope := report.Module
+	if report.Prefix != "" {
+		scope = report.Prefix
+	}
+	if len(report.Packages) == 0 {
+		return fmt.Sprintf("No unreachable functions in %s", scope)
+	}
+	count := 0
+	for _, pkg := range report.Packages {
+		count += len(pkg.Functions)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Unreachable functions in %s (%d):\n", scope, count)
+	for _, pkg := range report.Packages {
+		fmt.Fprintf(&b, "\n%s:\n", pkg.Path)
+		for _, fn := range pkg.Functions {
+			fmt.Fprintf(&b, "  %s %s:%d\n", fn.Name, fn.File, fn.Line)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDeadcode(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with unreachable functions
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",                // 1
+				"",                            // 2
+				"import (",                    // 3
+				"\t\"fmt\"",                   // 4
+				"",                            // 5
+				"\t\"example.com/app/store\"", // 6
+				")",                           // 7
+				"",                            // 8
+				"func main() {",               // 9
+				"\ts := store.Open()",         // 10
+				"\tfmt.Println(s.String())",   // 11
+				"}",                           // 12
+				"",                            // 13
+				"func unused() {}",            // 14
+				"",                            // 15
+			},
+			"store/store.go": {
+				"package store",                          // 1
+				"",                                       // 2
+				"type Store struct{}",                    // 3
+				"",                                       // 4
+				"func Open() *Store { return &Store{} }", // 5
+				"",                                       // 6
+				"func (s *Store) String() string { return \"store\" }", // 7
+				"",                           // 8
+				"func (s *Store) Close() {}", // 9
+				"",                           // 10
+				"func Reset() {}",            // 11
+				"",                           // 12
+			},
+			"store/store_test.go": {
+				"package store",      // 1
+				"",                   // 2
+				"import \"testing\"", // 3
+				"",                   // 4
+				"func TestReset(t *testing.T) { Reset() }", // 5
+				"", // 6
+			},
+		})
+	}
+
+	t.Run("unreachable functions", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := DeadCode(context.Background(), dir, "", false)
+		if err != nil {
+			t.Fatalf("Failed to find dead code: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Unreachable functions in example.com/app (3):",
+			"",
+			"example.com/app:",
+			"  unused " + filepath.Join(dir, "main.go") + ":14",
+			"",
+			"example.com/app/store:",
+			"  Store.Close " + filepath.Join(dir, "store", "store.go") + ":9",
+			"  Reset " + filepath.Join(dir, "store", "store.go") + ":11",
+			"",
+		}, "\n")
+		if got := report.String(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("prefix and tests", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := DeadCode(context.Background(), dir, "example.com/app/store", true)
+		if err != nil {
+			t.Fatalf("Failed to find dead code: %v", err)
+		}
+		if len(report.Packages) != 1 || len(report.Packages[0].Functions) != 1 || report.Packages[0].Functions[0].Name != "Store.Close" {
+			t.Errorf("Expected only Store.Close to be unreachable, got %+v", report.Packages)
+		}
+	})
+
+	t.Run("deadcode tool", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      deadcodeToolName,
+				"arguments": map[string]any{"workspace_dir": dir, "prefix": "example.com/app/store"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "Unreachable functions in example.com/app/store (2):") {
+			t.Errorf("Expected the unreachable functions of the store package, got: %s", text)
+		}
+	})
+}
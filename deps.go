@@ -0,0 +1,272 @@
+package go_mcp_tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	depsToolName        = "deps"
+	depsToolDescription = `Describes the module dependencies of the Go module containing a directory.
+
+Without module, lists the direct and indirect requirements of the module with their versions and replacements (go list -m all).
+With module, explains why the module is needed: the shortest import chain from the main module to a package of it (go mod why -m) and the requirement paths of the module graph leading to it (go mod graph, filtered to the paths reaching the module).`
+)
+
+func AddDepsTool(mcpServer *server.MCPServer) {
+	handleDeps := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		module := request.GetString("module", "")
+		format := request.GetString("format", "")
+		if format != "" && format != "markdown" && format != "json" {
+			return toolErrorResult(fmt.Sprintf("Error: unknown format %q, use markdown or json", format)), nil
+		}
+
+		report, err := Dependencies(ctx, workspaceDir, module)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error listing dependencies: %v", err)), nil
+		}
+		if format == "json" {
+			encoded, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return toolErrorResult(fmt.Sprintf("Error encoding dependencies: %v", err)), nil
+			}
+			return mcp.NewToolResultText(string(encoded)), nil
+		}
+		return mcp.NewToolResultText(report.Markdown()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		depsToolName,
+		mcp.WithDescription(depsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+		mcp.WithString("module",
+			mcp.Description("Module path to explain the requirement of, e.g. golang.org/x/sys. Lists all dependencies when empty."),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format"),
+			mcp.Enum("markdown", "json"),
+			mcp.DefaultString("markdown"),
+		),
+	), handleDeps)
+}
+
+// DependencyReport describes the dependencies of a module, or why it needs one of them
+type DependencyReport struct {
+	ModuleRoot string `json:"module_root"`
+	Main       string `json:"main"`
+	// Direct and Indirect list the requirements when no module is explained
+	Direct   []Dependency `json:"direct,omitempty"`
+	Indirect []Dependency `json:"indirect,omitempty"`
+
+	// Module is the explained module
+	Module string `json:"module,omitempty"`
+	// Why is the shortest import chain of packages from the main module to the module
+	Why []string `json:"why,omitempty"`
+	// NotNeeded is the explanation of go mod why when the packages of the main module do
+	// not import the module
+	NotNeeded string `json:"not_needed,omitempty"`
+	// Graph holds the requirements on the paths of the module graph reaching the module
+	Graph []DependencyEdge `json:"graph,omitempty"`
+}
+
+// Dependency is a module required by the main module
+type Dependency struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// Replace is the replacement of the module, as path or path@version
+	Replace string `json:"replace,omitempty"`
+}
+
+// DependencyEdge is a requirement of the module graph, From requiring To, as path@version
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Dependencies lists the direct and indirect requirements of the module containing
+// workspaceDir, or explains why it needs module when module is not empty
+func Dependencies(ctx context.Context, workspaceDir string, module string) (*DependencyReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	root, err := findModuleRoot(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	listing, err := runGo(ctx, root, "list", "-m", "-json", "all")
+	if err != nil {
+		return nil, err
+	}
+	report := &DependencyReport{ModuleRoot: root}
+	decoder := json.NewDecoder(strings.NewReader(listing))
+	for {
+		var listed struct {
+			Path     string
+			Version  string
+			Main     bool
+			Indirect bool
+			Replace  *struct {
+				Path    string
+				Version string
+			}
+		}
+		if err := decoder.Decode(&listed); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode go list output: %w", err)
+		}
+		if listed.Main {
+			report.Main = listed.Path
+			continue
+		}
+		dependency := Dependency{Path: listed.Path, Version: listed.Version}
+		if listed.Replace != nil {
+			dependency.Replace = listed.Replace.Path
+			if listed.Replace.Version != "" {
+				dependency.Replace += "@" + listed.Replace.Version
+			}
+		}
+		if listed.Indirect {
+			report.Indirect = append(report.Indirect, dependency)
+		} else {
+			report.Direct = append(report.Direct, dependency)
+		}
+	}
+	if module == "" {
+		return report, nil
+	}
+
+	report.Module = module
+	report.Direct, report.Indirect = nil, nil
+	why, err := runGo(ctx, root, "mod", "why", "-m", module)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(why), "\n") {
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "("):
+			report.NotNeeded = strings.Trim(line, "()")
+		case line != "":
+			report.Why = append(report.Why, line)
+		}
+	}
+
+	graph, err := runGo(ctx, root, "mod", "graph")
+	if err != nil {
+		return nil, err
+	}
+	report.Graph = edgesReaching(graph, module)
+	return report, nil
+}
+
+// edgesReaching returns the edges of the go mod graph output on paths reaching a version of
+// module, leaving out the go and toolchain requirements
+func edgesReaching(graph string, module string) []DependencyEdge {
+	var edges []DependencyEdge
+	requiredBy := make(map[string][]string)
+	reaching := make(map[string]bool)
+	var queue []string
+	scanner := bufio.NewScanner(strings.NewReader(graph))
+	for scanner.Scan() {
+		from, to, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || strings.HasPrefix(to, "go@") || strings.HasPrefix(to, "toolchain@") {
+			continue
+		}
+		edges = append(edges, DependencyEdge{From: from, To: to})
+		requiredBy[to] = append(requiredBy[to], from)
+		if path, _, _ := strings.Cut(to, "@"); path == module && !reaching[to] {
+			reaching[to] = true
+			queue = append(queue, to)
+		}
+	}
+	// Walk the requirements backwards from the versions of the module
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, from := range requiredBy[node] {
+			if !reaching[from] {
+				reaching[from] = true
+				queue = append(queue, from)
+			}
+		}
+	}
+	var filtered []DependencyEdge
+	for _, edge := range edges {
+		if reaching[edge.From] && reaching[edge.To] {
+			filtered = append(filtered, edge)
+		}
+	}
+	return filtered
+}
+
+// runGo runs the go command in dir and returns its standard output, with the error output in the error
+func runGo(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := runCommand(ctx, dir, nil, &stdout, &stderr, "go", args...); err != nil {
+		return "", fmt.Errorf("go %s failed: %v\n%s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Markdown formats the report as markdown
+func (report *DependencyReport) Markdown() string {
+	var b strings.Builder
+	if report.Module == "" {
+		fmt.Fprintf(&b, "# Dependencies of %s\n", report.Main)
+		for _, section := range []struct {
+			title        string
+			dependencies []Dependency
+		}{
+			{"Direct", report.Direct},
+			{"Indirect", report.Indirect},
+		} {
+			fmt.Fprintf(&b, "\n## %s (%d)\n", section.title, len(section.dependencies))
+			for _, dependency := range section.dependencies {
+				fmt.Fprintf(&b, "- %s %s", dependency.Path, dependency.Version)
+				if dependency.Replace != "" {
+					fmt.Fprintf(&b, " => %s", dependency.Replace)
+				}
+				b.WriteString("\n")
+			}
+		}
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "# Why %s needs %s\n\n## Import chain\n", report.Main, report.Module)
+	if report.NotNeeded != "" {
+		fmt.Fprintf(&b, "%s\n", report.NotNeeded)
+	}
+	for i, pkg := range report.Why {
+		fmt.Fprintf(&b, "%s%s\n", strings.Repeat("  ", i), pkg)
+	}
+	b.WriteString("\n## Requirement paths\n")
+	if len(report.Graph) == 0 {
+		fmt.Fprintf(&b, "%s is not in the module graph\n", report.Module)
+	}
+	for _, edge := range report.Graph {
+		fmt.Fprintf(&b, "- %s requires %s\n", edge.From, edge.To)
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module requiring a library which requires a leaf module.
+	// The replacements keep the go command offline.
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {
+				"module example.com/app",
+				"",
+				"go 1.22",
+				"",
+				"require example.com/lib v1.0.0",
+				"",
+				"require example.com/leaf v1.2.0 // indirect",
+				"",
+				"replace example.com/lib v1.0.0 => ./lib",
+				"",
+				"replace example.com/leaf v1.2.0 => ./leaf",
+				"",
+			},
+			"main.go":      {"package main", "", "import \"example.com/lib\"", "", "func main() { lib.Run() }", ""},
+			"lib/go.mod":   {"module example.com/lib", "", "go 1.22", "", "require example.com/leaf v1.2.0", ""},
+			"lib/lib.go":   {"package lib", "", "import \"example.com/leaf\"", "", "func Run() { leaf.Run() }", ""},
+			"leaf/go.mod":  {"module example.com/leaf", "", "go 1.22", ""},
+			"leaf/leaf.go": {"package leaf", "", "func Run() {}", ""},
+		})
+	}
+
+	t.Run("direct and indirect", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		report, err := Dependencies(context.Background(), workspace, "")
+		if err != nil {
+			t.Fatalf("Failed to list dependencies: %v", err)
+		}
+		expected := strings.Join([]string{
+			"# Dependencies of example.com/app",
+			"",
+			"## Direct (1)",
+			"- example.com/lib v1.0.0 => ./lib",
+			"",
+			"## Indirect (1)",
+			"- example.com/leaf v1.2.0 => ./leaf",
+			"",
+		}, "\n")
+		if got := report.Markdown(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("why", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		report, err := Dependencies(context.Background(), workspace, "example.com/leaf")
+		if err != nil {
+			t.Fatalf("Failed to explain dependency: %v", err)
+		}
+		if strings.Join(report.Why, " ") != "example.com/app example.com/lib example.com/leaf" {
+			t.Errorf("Expected the import chain through lib, got %v", report.Why)
+		}
+		expected := []DependencyEdge{
+			{From: "example.com/app", To: "example.com/leaf@v1.2.0"},
+			{From: "example.com/app", To: "example.com/lib@v1.0.0"},
+			{From: "example.com/lib@v1.0.0", To: "example.com/leaf@v1.2.0"},
+		}
+		if len(report.Graph) != len(expected) {
+			t.Fatalf("Expected %v, got %v", expected, report.Graph)
+		}
+		for i, edge := range report.Graph {
+			if edge != expected[i] {
+				t.Errorf("Expected %v, got %v", expected[i], edge)
+			}
+		}
+
+		report, err = Dependencies(context.Background(), workspace, "example.com/other")
+		if err != nil {
+			t.Fatalf("Failed to explain dependency: %v", err)
+		}
+		if report.NotNeeded != "main module does not need module example.com/other" || len(report.Graph) != 0 {
+			t.Errorf("Expected the module not to be needed, got %+v", report)
+		}
+	})
+
+	t.Run("deps tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      depsToolName,
+				"arguments": map[string]any{"workspace_dir": workspace, "module": "example.com/lib", "format": "json"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		var report DependencyReport
+		if err := json.Unmarshal([]byte(toolResultText(&result)), &report); err != nil {
+			t.Fatalf("Expected a JSON report, got: %s", toolResultText(&result))
+		}
+		if report.Main != "example.com/app" || strings.Join(report.Why, " ") != "example.com/app example.com/lib" || len(report.Graph) != 1 {
+			t.Errorf("Expected lib to be imported by the main module, got %+v", report)
+		}
+	})
+}
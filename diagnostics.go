@@ -0,0 +1,333 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+// diagnosticsResourceURI is the URI of the resource summarizing the build errors of the workspace
+const diagnosticsResourceURI = "diagnostics://workspace"
+
+// DefaultDiagnosticsInterval is how often the workspace is checked for changed files
+// unless set with DiagnosticsOptions.Interval
+const DefaultDiagnosticsInterval = 2 * time.Second
+
+// DiagnosticsOptions configures the diagnostics://workspace resource
+type DiagnosticsOptions struct {
+	// WorkspaceDir is a directory of the module whose build errors are reported
+	WorkspaceDir string
+	// Interval is how often the Go files of the module are checked for changes
+	Interval time.Duration
+}
+
+// WithDiagnostics exposes the diagnostics://workspace resource summarizing the build
+// errors of the module, including its tests. While clients are connected the module is
+// checked again in the background when its Go files, go.mod or go.sum change, so reading
+// the resource returns current errors without waiting for the type checker. Sessions of the
+// stdio and HTTP transports can subscribe to the resource to be notified after each check of
+// changed files.
+func WithDiagnostics(options DiagnosticsOptions) Option {
+	return func(o *serverOptions) {
+		o.diagnostics = &options
+	}
+}
+
+// DiagnosticsSummary lists the build errors of a module
+type DiagnosticsSummary struct {
+	ModuleRoot string    `json:"module_root"`
+	CheckedAt  time.Time `json:"checked_at"`
+	// Packages is the number of packages checked, counting test packages separately
+	Packages int          `json:"packages"`
+	Errors   []Diagnostic `json:"errors,omitempty"`
+}
+
+// Diagnostic is a build error of a package. File is empty for errors without position,
+// e.g. a missing dependency.
+type Diagnostic struct {
+	Package string `json:"package"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// WorkspaceDiagnostics type checks the packages of the module containing workspaceDir and
+// their tests and returns their errors
+func WorkspaceDiagnostics(ctx context.Context, workspaceDir string) (*DiagnosticsSummary, error) {
+	root, err := findModuleRoot(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	config := &packages.Config{
+		Context: ctx,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:   root,
+		Tests: true,
+	}
+	pkgs, err := loadPackages(ctx, config, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the packages of %s: %w", root, err)
+	}
+
+	summary := &DiagnosticsSummary{ModuleRoot: root, CheckedAt: time.Now(), Packages: len(pkgs)}
+	// Test variants of a package report the errors of its files again
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, pkgError := range pkg.Errors {
+			diagnostic := Diagnostic{Package: pkg.PkgPath, Message: pkgError.Msg}
+			if pkgError.Pos != "" && pkgError.Pos != "-" {
+				diagnostic.File, diagnostic.Line, diagnostic.Column = parseErrorPosition(pkgError.Pos)
+			}
+			key := fmt.Sprintf("%s:%d:%d %s", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Message)
+			if diagnostic.File == "" {
+				key = diagnostic.Package + " " + key
+			}
+			if !seen[key] {
+				seen[key] = true
+				summary.Errors = append(summary.Errors, diagnostic)
+			}
+		}
+	}
+	slices.SortStableFunc(summary.Errors, func(a, b Diagnostic) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return summary, nil
+}
+
+// parseErrorPosition splits a file:line:column position, the line and column being optional
+func parseErrorPosition(position string) (file string, line int, column int) {
+	file = position
+	for _, number := range []*int{&column, &line} {
+		index := strings.LastIndex(file, ":")
+		if index < 0 {
+			break
+		}
+		value, err := strconv.Atoi(file[index+1:])
+		if err != nil {
+			break
+		}
+		*number = value
+		file = file[:index]
+	}
+	if line == 0 {
+		// A single number is the line
+		line, column = column, 0
+	}
+	return file, line, column
+}
+
+// Green reports whether the module builds without errors
+func (summary *DiagnosticsSummary) Green() bool {
+	return len(summary.Errors) == 0
+}
+
+// String formats the summary as human and model readable text
+func (summary *DiagnosticsSummary) String() string {
+	checkedAt := summary.CheckedAt.Format(time.RFC3339)
+	if summary.Green() {
+		return fmt.Sprintf(
+			"Build of %s is green: %d packages without errors (checked at %s)\n",
+			summary.ModuleRoot, summary.Packages, checkedAt,
+		)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Build of %s is red: %d errors (checked at %s)\n", summary.ModuleRoot, len(summary.Errors), checkedAt)
+	for _, diagnostic := range summary.Errors {
+		switch {
+		case diagnostic.File == "":
+			fmt.Fprintf(&b, "  %s: %s\n", diagnostic.Package, diagnostic.Message)
+		case diagnostic.Column > 0:
+			fmt.Fprintf(&b, "  %s:%d:%d: %s\n", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Message)
+		default:
+			fmt.Fprintf(&b, "  %s:%d: %s\n", diagnostic.File, diagnostic.Line, diagnostic.Message)
+		}
+	}
+	return b.String()
+}
+
+// diagnosticsWatcher keeps the diagnostics of a module current by checking it again
+// whenever its files change
+type diagnosticsWatcher struct {
+	workspaceDir string
+	// updated is called after the module was checked again because its files changed
+	updated func()
+
+	mu          sync.Mutex
+	fingerprint string
+	summary     *DiagnosticsSummary
+	err         error
+
+	// sessions is the number of connected sessions, the watcher running while positive
+	sessionsMu sync.Mutex
+	sessions   int
+	stop       context.CancelFunc
+}
+
+func newDiagnosticsWatcher(workspaceDir string, updated func()) *diagnosticsWatcher {
+	return &diagnosticsWatcher{workspaceDir: workspaceDir, updated: updated}
+}
+
+// addSession starts running the watcher every interval when the first session connects
+func (watcher *diagnosticsWatcher) addSession(interval time.Duration) {
+	watcher.sessionsMu.Lock()
+	defer watcher.sessionsMu.Unlock()
+	watcher.sessions++
+	if watcher.sessions == 1 {
+		var ctx context.Context
+		ctx, watcher.stop = context.WithCancel(context.Background())
+		go watcher.run(ctx, interval)
+	}
+}
+
+// removeSession stops the watcher when the last session ends
+func (watcher *diagnosticsWatcher) removeSession() {
+	watcher.sessionsMu.Lock()
+	defer watcher.sessionsMu.Unlock()
+	watcher.sessions--
+	if watcher.sessions == 0 && watcher.stop != nil {
+		watcher.stop()
+		watcher.stop = nil
+	}
+}
+
+// run checks the module every interval until the context is cancelled
+func (watcher *diagnosticsWatcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		watcher.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check type checks the module again if its files changed since the previous check
+func (watcher *diagnosticsWatcher) check(ctx context.Context) {
+	watcher.mu.Lock()
+	fingerprint, err := moduleFingerprint(watcher.workspaceDir)
+	if err == nil && fingerprint == watcher.fingerprint && (watcher.summary != nil || watcher.err != nil) {
+		watcher.mu.Unlock()
+		return
+	}
+	var summary *DiagnosticsSummary
+	if err == nil {
+		summary, err = WorkspaceDiagnostics(ctx, watcher.workspaceDir)
+	}
+	// The first check has no earlier result to have changed
+	updated := watcher.summary != nil || watcher.err != nil
+	if ctx.Err() != nil {
+		updated = false
+	} else {
+		watcher.fingerprint, watcher.summary, watcher.err = fingerprint, summary, err
+	}
+	watcher.mu.Unlock()
+	if updated && watcher.updated != nil {
+		watcher.updated()
+	}
+}
+
+// current returns the diagnostics of the last check, checking the module first if it changed
+func (watcher *diagnosticsWatcher) current(ctx context.Context) (*DiagnosticsSummary, error) {
+	watcher.check(ctx)
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	return watcher.summary, watcher.err
+}
+
+// moduleFingerprint hashes the paths, sizes and modification times of the Go files of
+// the module containing workspaceDir and its go.mod and go.sum
+func moduleFingerprint(workspaceDir string) (string, error) {
+	root, err := findModuleRoot(workspaceDir)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	err = filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if filePath != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") && name != "go.mod" && name != "go.sum" {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %d %d\n", filePath, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// addDiagnosticsResource registers the diagnostics://workspace resource, running its
+// watcher while sessions are connected and notifying the subscribed sessions of its checks
+func addDiagnosticsResource(mcpServer *server.MCPServer, hooks *server.Hooks, options DiagnosticsOptions) {
+	watcher := newDiagnosticsWatcher(options.WorkspaceDir, func() {
+		notifyResourceUpdated(mcpServer, diagnosticsResourceURI)
+	})
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultDiagnosticsInterval
+	}
+	resourceSubscriptions.allow(mcpServer, diagnosticsResourceURI)
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		watcher.addSession(interval)
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		watcher.removeSession()
+		resourceSubscriptions.removeSession(mcpServer, session.SessionID())
+	})
+
+	handleDiagnostics := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		summary, err := watcher.current(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the workspace: %w", err)
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      diagnosticsResourceURI,
+				MIMEType: "text/plain",
+				Text:     summary.String(),
+			},
+		}, nil
+	}
+
+	mcpServer.AddResource(mcp.NewResource(
+		diagnosticsResourceURI,
+		"Workspace diagnostics",
+		mcp.WithResourceDescription("Build errors of the packages and tests of the workspace module, kept current as files change"),
+		mcp.WithMIMEType("text/plain"),
+	), handleDiagnostics)
+}
@@ -0,0 +1,143 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a type error and a broken test
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",          // 1
+				"",                      // 2
+				"func main() {",         // 3
+				"\tvar n int = \"one\"", // 4
+				"\t_ = n",               // 5
+				"}",                     // 6
+				"",                      // 7
+			},
+			"main_test.go": {
+				"package main",                 // 1
+				"",                             // 2
+				"import \"testing\"",           // 3
+				"",                             // 4
+				"func TestRun(t *testing.T) {", // 5
+				"\tundefined()",                // 6
+				"}",                            // 7
+				"",                             // 8
+			},
+		})
+	}
+
+	t.Run("workspace diagnostics", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		summary, err := WorkspaceDiagnostics(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("Failed to check workspace: %v", err)
+		}
+		if summary.Green() || len(summary.Errors) != 2 {
+			t.Fatalf("Expected 2 errors, got %+v", summary.Errors)
+		}
+		text := summary.String()
+		for _, expected := range []string{
+			"Build of " + dir + " is red: 2 errors",
+			filepath.Join(dir, "main.go") + ":4:14: cannot use \"one\"",
+			filepath.Join(dir, "main_test.go") + ":6:2: undefined: undefined",
+		} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in:\n%s", expected, text)
+			}
+		}
+	})
+
+	t.Run("watcher", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		var updates atomic.Int32
+		watcher := newDiagnosticsWatcher(dir, func() { updates.Add(1) })
+		summary, err := watcher.current(context.Background())
+		if err != nil || summary.Green() {
+			t.Fatalf("Expected errors, got %v %v", summary, err)
+		}
+		if again, _ := watcher.current(context.Background()); again != summary {
+			t.Errorf("Expected the module not to be checked again before editing files")
+		}
+
+		content := "package main\n\nfunc main() {}\n"
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(filepath.Join(dir, "main_test.go")); err != nil {
+			t.Fatal(err)
+		}
+		summary, err = watcher.current(context.Background())
+		if err != nil || !summary.Green() {
+			t.Fatalf("Expected a green build, got %v %v", summary, err)
+		}
+		if count := updates.Load(); count != 1 {
+			t.Errorf("Expected one update after editing files, got %d", count)
+		}
+	})
+
+	t.Run("watcher runs while sessions are connected", func(t *testing.T) {
+		t.Parallel()
+		watcher := newDiagnosticsWatcher(createTestWorkspace(t), nil)
+		watcher.addSession(time.Hour)
+		watcher.addSession(time.Hour)
+		watcher.removeSession()
+		watcher.sessionsMu.Lock()
+		running := watcher.stop != nil
+		watcher.sessionsMu.Unlock()
+		if !running {
+			t.Fatal("Expected the watcher to run while a session is connected")
+		}
+		watcher.removeSession()
+		if watcher.stop != nil {
+			t.Error("Expected the watcher to stop after the last session ended")
+		}
+	})
+
+	t.Run("diagnostics resource", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodResourcesRead),
+			"params":  map[string]any{"uri": diagnosticsResourceURI},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mcpServer := NewMCPServer(WithDiagnostics(DiagnosticsOptions{WorkspaceDir: dir, Interval: time.Hour}))
+		response, ok := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatal("Expected a response reading the diagnostics resource")
+		}
+		result := response.Result.(mcp.ReadResourceResult)
+		if len(result.Contents) != 1 {
+			t.Fatalf("Expected 1 content, got %d", len(result.Contents))
+		}
+		text := result.Contents[0].(mcp.TextResourceContents).Text
+		if !strings.Contains(text, "is red: 2 errors") {
+			t.Errorf("Expected the errors of the workspace, got: %s", text)
+		}
+	})
+}
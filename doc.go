@@ -0,0 +1,170 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	docToolName        = "doc"
+	docToolDescription = `Shows the documentation of a Go package or symbol by import path, like go doc, without needing the path of its source files. E.g. package "net/http" with symbol "Client.Do" shows the signature and doc comment of the Do method of http.Client, and without a symbol the package documentation and its exported API is shown.
+
+Packages are resolved in the standard library and the requirements of the module containing workspace_dir. Packages of other modules are downloaded into the module cache, at the latest version or at version when given, e.g. "v1.6.0", so any published package can be looked up.`
+)
+
+// DocOptions configures the documentation lookup
+type DocOptions struct {
+	// WorkspaceDir is a directory in the module whose requirements resolve the package,
+	// optional for packages of the standard library or downloaded modules
+	WorkspaceDir string
+	// Package is the import path of the package
+	Package string
+	// Symbol is a package level symbol, Type.Method or Type.Field, empty for the package
+	Symbol string
+	// Version of the module providing the package to download, the requirement of the
+	// workspace or the latest version when empty
+	Version string
+	// All shows the documentation of all exported symbols instead of a summary
+	All bool
+}
+
+func AddDocTool(mcpServer *server.MCPServer) {
+	handleDoc := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		pkg, err := request.RequireString("package")
+		if err != nil {
+			return nil, err
+		}
+		options := DocOptions{Package: pkg}
+		options.WorkspaceDir = request.GetString("workspace_dir", "")
+		options.Symbol = request.GetString("symbol", "")
+		options.Version = request.GetString("version", "")
+		options.All = request.GetBool("all", false)
+
+		doc, err := Doc(ctx, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error looking up documentation: %v", err)), nil
+		}
+		return mcp.NewToolResultText(doc), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		docToolName,
+		mcp.WithDescription(docToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("package",
+			mcp.Description("Import path of the package, e.g. net/http or github.com/google/uuid"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Symbol to document, e.g. Client or Client.Do, omit for the package"),
+		),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module whose requirements resolve the package"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Version of the module providing the package, e.g. v1.6.0, to download instead of using the requirement of the workspace"),
+		),
+		mcp.WithBoolean("all",
+			mcp.Description("Whether to show the documentation of all exported symbols of the package"),
+			mcp.DefaultBool(false),
+		),
+	), handleDoc)
+}
+
+// Doc returns the documentation of a package or symbol as printed by go doc. Packages
+// neither in the standard library nor required by the workspace module are downloaded
+// into the module cache by a temporary module requiring them.
+func Doc(ctx context.Context, options DocOptions) (string, error) {
+	if options.WorkspaceDir != "" && !filepath.IsAbs(options.WorkspaceDir) {
+		return "", fmt.Errorf("workspace_dir must be an absolute path, got: %s", options.WorkspaceDir)
+	}
+	if strings.HasPrefix(options.Package, ".") || filepath.IsAbs(options.Package) {
+		return "", fmt.Errorf("package must be an import path, got: %s", options.Package)
+	}
+	args := []string{"doc"}
+	if options.All {
+		args = append(args, "-all")
+	}
+	args = append(args, options.Package)
+	if options.Symbol != "" {
+		args = append(args, options.Symbol)
+	}
+
+	if options.Version == "" {
+		dir := options.WorkspaceDir
+		if dir == "" {
+			dir = os.TempDir()
+		}
+		doc, err := runGo(ctx, dir, args...)
+		// Only paths starting with a domain can be downloaded, standard library
+		// packages and symbols that do not exist fail here
+		if err == nil || !isModulePath(options.Package) || !isMissingPackageError(err) {
+			return doc, err
+		}
+	}
+
+	version := options.Version
+	if version == "" {
+		version = "latest"
+	}
+	dir, err := downloadModule(ctx, options.Package, version)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	return runGo(ctx, dir, args...)
+}
+
+// downloadModule creates a temporary module requiring the module providing the package
+// at version, which downloads it into the module cache. The caller removes the directory
+// of the returned module.
+func downloadModule(ctx context.Context, pkg string, version string) (string, error) {
+	dir, err := os.MkdirTemp("", "go-mcp-tools-module-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := runGo(ctx, dir, "mod", "init", "query.local"); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	if _, err := runGo(ctx, dir, "get", pkg+"@"+version); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to download %s@%s: %w", pkg, version, err)
+	}
+	return dir, nil
+}
+
+// isModulePath reports whether an import path starts with a domain, like the paths of
+// modules that can be downloaded
+func isModulePath(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return strings.Contains(first, ".")
+}
+
+// isMissingPackageError reports whether go doc failed because no module of the build
+// list provides the package
+func isMissingPackageError(err error) bool {
+	message := err.Error()
+	for _, text := range []string{
+		"no required module provides package",
+		"go.mod file not found",
+		"cannot find module providing package",
+		"is not in std",
+		"no such package",
+	} {
+		if strings.Contains(message, text) {
+			return true
+		}
+	}
+	return false
+}
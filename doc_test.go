@@ -0,0 +1,138 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDoc(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module requiring a replaced library, so lookups stay offline
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {
+				"module example.com/app",
+				"",
+				"go 1.22",
+				"",
+				"require example.com/lib v1.0.0",
+				"",
+				"replace example.com/lib => ./lib",
+				"",
+			},
+			"main.go": {
+				"package main",
+				"",
+				"import \"example.com/lib\"",
+				"",
+				"func main() { lib.NewClient().Do() }",
+				"",
+			},
+			"lib/go.mod": {"module example.com/lib", "", "go 1.22", ""},
+			"lib/lib.go": {
+				"// Package lib talks to the server",
+				"package lib",
+				"",
+				"// Client talks to the server",
+				"type Client struct{}",
+				"",
+				"// NewClient creates a client",
+				"func NewClient() *Client { return &Client{} }",
+				"",
+				"// Do sends a request and reports whether it succeeded",
+				"func (c *Client) Do() bool { return true }",
+				"",
+			},
+		})
+	}
+
+	t.Run("standard library symbol", func(t *testing.T) {
+		t.Parallel()
+		doc, err := Doc(context.Background(), DocOptions{Package: "strings", Symbol: "Builder.WriteString"})
+		if err != nil {
+			t.Fatalf("Failed to look up documentation: %v", err)
+		}
+		if !strings.Contains(doc, "func (b *Builder) WriteString(s string) (int, error)") {
+			t.Errorf("Expected the method signature, got:\n%s", doc)
+		}
+	})
+
+	t.Run("required package", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		doc, err := Doc(context.Background(), DocOptions{WorkspaceDir: workspace, Package: "example.com/lib", Symbol: "Client.Do"})
+		if err != nil {
+			t.Fatalf("Failed to look up documentation: %v", err)
+		}
+		for _, expected := range []string{"func (c *Client) Do() bool", "Do sends a request and reports whether it succeeded"} {
+			if !strings.Contains(doc, expected) {
+				t.Errorf("Expected documentation to contain %q, got:\n%s", expected, doc)
+			}
+		}
+
+		doc, err = Doc(context.Background(), DocOptions{WorkspaceDir: workspace, Package: "example.com/lib", All: true})
+		if err != nil {
+			t.Fatalf("Failed to look up documentation: %v", err)
+		}
+		for _, expected := range []string{"Package lib talks to the server", "func NewClient() *Client", "NewClient creates a client"} {
+			if !strings.Contains(doc, expected) {
+				t.Errorf("Expected documentation to contain %q, got:\n%s", expected, doc)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		for name, options := range map[string]DocOptions{
+			"unknown symbol":     {WorkspaceDir: workspace, Package: "example.com/lib", Symbol: "Server"},
+			"unknown std symbol": {Package: "strings", Symbol: "Missing"},
+			"relative path":      {WorkspaceDir: workspace, Package: "./lib"},
+			"relative workspace": {WorkspaceDir: "app", Package: "strings"},
+		} {
+			if _, err := Doc(context.Background(), options); err == nil {
+				t.Errorf("Expected an error for %s", name)
+			}
+		}
+	})
+
+	t.Run("tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mcpServer := NewMCPServer()
+
+		callTool := func(arguments map[string]any) mcp.CallToolResult {
+			encoded, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      1,
+				"method":  string(mcp.MethodToolsCall),
+				"params":  map[string]any{"name": docToolName, "arguments": arguments},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, ok := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+			if !ok {
+				t.Fatal("Expected a tool result")
+			}
+			return response.Result.(mcp.CallToolResult)
+		}
+
+		result := callTool(map[string]any{"workspace_dir": workspace, "package": "example.com/lib", "symbol": "NewClient"})
+		if result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "func NewClient() *Client") {
+			t.Errorf("Expected the function documentation, got: %+v", result)
+		}
+
+		result = callTool(map[string]any{"workspace_dir": workspace, "package": "example.com/lib", "symbol": "Missing"})
+		if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "Error looking up documentation") {
+			t.Errorf("Expected an error result, got: %+v", result)
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	duplicatesToolName        = "duplicate_dependencies"
+	duplicatesToolDescription = `Finds third-party dependencies of a Go module providing overlapping functionality, such as two YAML parsers, two UUID libraries or two assertion libraries, and lists the files using each of them.
+
+Use it to plan consolidating on one library per concern. Only well known libraries are recognized.`
+)
+
+// duplicateUsageFiles limits the files listed per library
+const duplicateUsageFiles = 10
+
+// functionalityLibraries maps a functionality to the import path prefixes of the libraries
+// providing it. Major versions of a library are distinct libraries, as migrating between
+// them is consolidation work as well.
+var functionalityLibraries = []struct {
+	functionality string
+	prefixes      []string
+}{
+	{"yaml", []string{
+		"gopkg.in/yaml.v2", "gopkg.in/yaml.v3", "sigs.k8s.io/yaml", "github.com/ghodss/yaml",
+		"github.com/goccy/go-yaml", "go.yaml.in/yaml",
+	}},
+	{"toml", []string{"github.com/BurntSushi/toml", "github.com/pelletier/go-toml"}},
+	{"json", []string{
+		"github.com/json-iterator/go", "github.com/goccy/go-json", "github.com/bytedance/sonic",
+		"github.com/segmentio/encoding", "github.com/mailru/easyjson",
+	}},
+	{"uuid", []string{
+		"github.com/google/uuid", "github.com/gofrs/uuid", "github.com/satori/go.uuid",
+		"github.com/pborman/uuid", "github.com/rs/xid", "github.com/oklog/ulid",
+	}},
+	{"assertions", []string{
+		"github.com/stretchr/testify", "github.com/matryer/is", "gotest.tools",
+		"github.com/onsi/gomega", "github.com/frankban/quicktest", "github.com/smartystreets/goconvey",
+		"github.com/alecthomas/assert",
+	}},
+	{"mocking", []string{"github.com/golang/mock", "go.uber.org/mock", "github.com/gojuno/minimock"}},
+	{"logging", []string{
+		"github.com/sirupsen/logrus", "go.uber.org/zap", "github.com/rs/zerolog",
+		"github.com/apex/log", "github.com/go-kit/log", "github.com/inconshreveable/log15",
+	}},
+	{"errors", []string{
+		"github.com/pkg/errors", "github.com/cockroachdb/errors", "github.com/go-errors/errors",
+		"emperror.dev/errors",
+	}},
+	{"http router", []string{
+		"github.com/gin-gonic/gin", "github.com/labstack/echo", "github.com/go-chi/chi",
+		"github.com/gorilla/mux", "github.com/gofiber/fiber", "github.com/julienschmidt/httprouter",
+	}},
+	{"http client", []string{"github.com/go-resty/resty", "github.com/hashicorp/go-retryablehttp", "github.com/parnurzeal/gorequest"}},
+	{"cli", []string{"github.com/spf13/cobra", "github.com/urfave/cli", "github.com/alecthomas/kong", "github.com/jessevdk/go-flags"}},
+	{"config", []string{
+		"github.com/spf13/viper", "github.com/kelseyhightower/envconfig", "github.com/caarlos0/env",
+		"github.com/knadh/koanf", "github.com/ilyakaznacheev/cleanenv",
+	}},
+	{"redis", []string{"github.com/go-redis/redis", "github.com/redis/go-redis", "github.com/gomodule/redigo"}},
+	{"postgres driver", []string{"github.com/lib/pq", "github.com/jackc/pgx"}},
+	{"decimal", []string{"github.com/shopspring/decimal", "github.com/cockroachdb/apd", "github.com/ericlagergren/decimal"}},
+	{"validation", []string{"github.com/go-playground/validator", "github.com/go-ozzo/ozzo-validation", "github.com/asaskevich/govalidator"}},
+}
+
+// DuplicatedFunctionality is a functionality provided by several dependencies of a module
+type DuplicatedFunctionality struct {
+	Functionality string
+	Libraries     []DependencyUsage
+}
+
+// DependencyUsage is a dependency of a module and where it is used
+type DependencyUsage struct {
+	// Library is the required module, or the import path prefix when go.mod does not require it
+	Library string
+	Imports []string
+	// Files are relative to the module root
+	Files []string
+}
+
+func AddDuplicatesTool(mcpServer *server.MCPServer) {
+	handleDuplicates := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		includeTests := true
+		includeTests = request.GetBool("include_tests", includeTests)
+
+		duplicates, err := DuplicateDependencies(workspaceDir, includeTests)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding duplicate dependencies: %v", err)), nil
+		}
+		return mcp.NewToolResultText(formatDuplicates(duplicates)), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		duplicatesToolName,
+		mcp.WithDescription(duplicatesToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to check"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("include_tests",
+			mcp.Description("Whether to include the imports of test files, e.g. of assertion libraries"),
+			mcp.DefaultBool(true),
+		),
+	), handleDuplicates)
+}
+
+// DuplicateDependencies returns the functionalities provided by more than one dependency
+// imported by the module containing workspaceDir, sorted by functionality
+func DuplicateDependencies(workspaceDir string, includeTests bool) ([]DuplicatedFunctionality, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := loadModuleSources(workspaceDir, includeTests)
+	if err != nil {
+		return nil, err
+	}
+
+	// usages maps functionalities to libraries to their usage
+	usages := make(map[string]map[string]*DependencyUsage)
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			for _, spec := range file.ast.Imports {
+				importPath := strings.Trim(spec.Path.Value, "\"`")
+				functionality, prefix := libraryFunctionality(importPath)
+				if functionality == "" {
+					continue
+				}
+				library := module.requiredModule(importPath)
+				if library == "" {
+					library = prefix
+				}
+				if usages[functionality] == nil {
+					usages[functionality] = make(map[string]*DependencyUsage)
+				}
+				usage, ok := usages[functionality][library]
+				if !ok {
+					usage = &DependencyUsage{Library: library}
+					usages[functionality][library] = usage
+				}
+				if !slices.Contains(usage.Imports, importPath) {
+					usage.Imports = append(usage.Imports, importPath)
+				}
+				if relPath := module.relPath(file.path); !slices.Contains(usage.Files, relPath) {
+					usage.Files = append(usage.Files, relPath)
+				}
+			}
+		}
+	}
+
+	var duplicates []DuplicatedFunctionality
+	for functionality, libraries := range usages {
+		if len(libraries) < 2 {
+			continue
+		}
+		duplicate := DuplicatedFunctionality{Functionality: functionality}
+		for _, usage := range libraries {
+			slices.Sort(usage.Imports)
+			slices.Sort(usage.Files)
+			duplicate.Libraries = append(duplicate.Libraries, *usage)
+		}
+		// The most used library first, as it is the natural one to consolidate on
+		slices.SortFunc(duplicate.Libraries, func(a, b DependencyUsage) int {
+			if len(a.Files) != len(b.Files) {
+				return len(b.Files) - len(a.Files)
+			}
+			return strings.Compare(a.Library, b.Library)
+		})
+		duplicates = append(duplicates, duplicate)
+	}
+	slices.SortFunc(duplicates, func(a, b DuplicatedFunctionality) int {
+		return strings.Compare(a.Functionality, b.Functionality)
+	})
+	return duplicates, nil
+}
+
+// libraryFunctionality returns the functionality of the imported library and its prefix in
+// functionalityLibraries, or empty strings for unknown libraries
+func libraryFunctionality(importPath string) (string, string) {
+	for _, group := range functionalityLibraries {
+		for _, prefix := range group.prefixes {
+			if importPath == prefix || strings.HasPrefix(importPath, prefix+"/") {
+				return group.functionality, prefix
+			}
+		}
+	}
+	return "", ""
+}
+
+// formatDuplicates renders the duplicated functionalities for reading
+func formatDuplicates(duplicates []DuplicatedFunctionality) string {
+	if len(duplicates) == 0 {
+		return "No dependencies with overlapping functionality found."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d functionalities provided by several dependencies:\n", len(duplicates))
+	for _, duplicate := range duplicates {
+		fmt.Fprintf(&b, "\n## %s (%d libraries)\n", duplicate.Functionality, len(duplicate.Libraries))
+		for _, library := range duplicate.Libraries {
+			fmt.Fprintf(&b, "- %s, used by %d files\n", library.Library, len(library.Files))
+			if len(library.Imports) > 1 || library.Imports[0] != library.Library {
+				fmt.Fprintf(&b, "  imports: %s\n", strings.Join(library.Imports, ", "))
+			}
+			for _, file := range library.Files[:min(duplicateUsageFiles, len(library.Files))] {
+				fmt.Fprintf(&b, "  - %s\n", file)
+			}
+			if len(library.Files) > duplicateUsageFiles {
+				fmt.Fprintf(&b, "  - ... and %d more\n", len(library.Files)-duplicateUsageFiles)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDuplicateDependencies(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module using two YAML and two assertion libraries
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {
+				"module example.com/app",                 // 1
+				"",                                       // 2
+				"go 1.22",                                // 3
+				"",                                       // 4
+				"require (",                              // 5
+				"    gopkg.in/yaml.v3 v3.0.1",            // 6
+				"    sigs.k8s.io/yaml v1.4.0",            // 7
+				"    github.com/stretchr/testify v1.9.0", // 8
+				"    github.com/google/uuid v1.6.0",      // 9
+				")",                                      // 10
+			},
+			"config/config.go": {
+				"package config",              // 1
+				"",                            // 2
+				"import \"gopkg.in/yaml.v3\"", // 3
+				"",                            // 4
+				"var _ = yaml.Marshal",        // 5
+			},
+			"config/k8s.go": {
+				"package config",              // 1
+				"",                            // 2
+				"import \"sigs.k8s.io/yaml\"", // 3
+				"",                            // 4
+				"var _ = yaml.YAMLToJSON",     // 5
+			},
+			"config/load.go": {
+				"package config",                       // 1
+				"",                                     // 2
+				"import (",                             // 3
+				"    \"github.com/google/uuid\"",       // 4
+				"    yaml3 \"gopkg.in/yaml.v3\"",       // 5
+				")",                                    // 6
+				"",                                     // 7
+				"var _, _ = uuid.New, yaml3.Unmarshal", // 8
+			},
+			"config/config_test.go": {
+				"package config",  // 1
+				"",                // 2
+				"import (",        // 3
+				"    \"testing\"", // 4
+				"    \"github.com/stretchr/testify/assert\"",  // 5
+				"    \"github.com/stretchr/testify/require\"", // 6
+				"    \"github.com/matryer/is\"",               // 7
+				")",                                           // 8
+				"",                                            // 9
+				"func TestLoad(t *testing.T) { _, _, _ = assert.True, require.True, is.New }", // 10
+			},
+		})
+	}
+
+	t.Run("duplicates", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		duplicates, err := DuplicateDependencies(workspace, true)
+		if err != nil {
+			t.Fatalf("Failed to find duplicates: %v", err)
+		}
+		if len(duplicates) != 2 || duplicates[0].Functionality != "assertions" || duplicates[1].Functionality != "yaml" {
+			t.Fatalf("Expected assertions and yaml duplicates, got %+v", duplicates)
+		}
+
+		assertions := duplicates[0].Libraries
+		if assertions[0].Library != "github.com/matryer/is" || assertions[1].Library != "github.com/stretchr/testify" {
+			t.Errorf("Expected is and testify, got %+v", assertions)
+		}
+		if strings.Join(assertions[1].Imports, ",") != "github.com/stretchr/testify/assert,github.com/stretchr/testify/require" {
+			t.Errorf("Expected the testify packages as imports, got %v", assertions[1].Imports)
+		}
+
+		yaml := duplicates[1].Libraries
+		if yaml[0].Library != "gopkg.in/yaml.v3" || strings.Join(yaml[0].Files, ",") != filepath.Join("config", "config.go")+","+filepath.Join("config", "load.go") {
+			t.Errorf("Expected yaml.v3 used by two files first, got %+v", yaml[0])
+		}
+		if yaml[1].Library != "sigs.k8s.io/yaml" {
+			t.Errorf("Expected sigs.k8s.io/yaml second, got %+v", yaml[1])
+		}
+	})
+
+	t.Run("without tests", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		duplicates, err := DuplicateDependencies(workspace, false)
+		if err != nil {
+			t.Fatalf("Failed to find duplicates: %v", err)
+		}
+		if len(duplicates) != 1 || duplicates[0].Functionality != "yaml" {
+			t.Errorf("Expected only the yaml duplicate, got %+v", duplicates)
+		}
+	})
+
+	t.Run("duplicates tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      duplicatesToolName,
+				"arguments": map[string]any{"workspace_dir": filepath.Join(workspace, "config")},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		for _, expected := range []string{
+			"## yaml (2 libraries)\n- gopkg.in/yaml.v3, used by 2 files\n",
+			"- github.com/stretchr/testify, used by 1 files\n  imports: github.com/stretchr/testify/assert, github.com/stretchr/testify/require\n",
+		} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the result, got: %s", expected, text)
+			}
+		}
+		if strings.Contains(text, "uuid") {
+			t.Errorf("Expected a single uuid library not to be reported, got: %s", text)
+		}
+	})
+}
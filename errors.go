@@ -0,0 +1,52 @@
+package go_mcp_tools
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Sentinel errors classifying the errors returned by the tools and library functions.
+// Use errors.Is to check for them, the messages of the returned errors are more specific.
+var (
+	// ErrSymbolNotFound is returned when a symbol is not declared in, or not at, the given location
+	ErrSymbolNotFound = errors.New("symbol not found")
+	// ErrOutsideWorkspace is returned when a path is outside the workspace the call is restricted to
+	ErrOutsideWorkspace = errors.New("path outside workspace")
+	// ErrGoplsUnavailable is returned when the gopls binary is not installed or not in PATH
+	ErrGoplsUnavailable = errors.New("gopls unavailable")
+	// ErrSyntaxErrors is returned when a file cannot be processed because it does not parse
+	ErrSyntaxErrors = errors.New("syntax errors")
+)
+
+// classifiedError is an error belonging to one of the sentinel error classes. Its message
+// is the message of the underlying error, which may wrap further errors itself.
+type classifiedError struct {
+	class error
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// classifyErrorf formats an error like fmt.Errorf and marks it as belonging to class
+func classifyErrorf(class error, format string, args ...any) error {
+	return &classifiedError{class: class, err: fmt.Errorf(format, args...)}
+}
+
+// classifyPackageErrors marks err, reporting the errors of pkg, as ErrSyntaxErrors when
+// any of them is a parse error
+func classifyPackageErrors(pkg *packages.Package, err error) error {
+	for _, pkgError := range pkg.Errors {
+		if pkgError.Kind == packages.ParseError {
+			return &classifiedError{class: ErrSyntaxErrors, err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to write a Go file into a fresh temp directory
+	writeTestFile := func(t testing.TB, lines []string) string {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "main.go")
+		err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filePath
+	}
+
+	validLines := []string{
+		"package testpkg",            // 1
+		"",                           // 2
+		"func Hello() string {",      // 3
+		"    return \"hello\"",       // 4
+		"}",                          // 5
+		"",                           // 6
+		"var Values = map[int]int{}", // 7
+	}
+
+	t.Run("symbol not found", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, validLines)
+
+		_, err := Inspect(filePath, 0, "Missing", true, filepath.Dir(filePath))
+		if !errors.Is(err, ErrSymbolNotFound) || err.Error() != "symbol 'Missing' not found in file" {
+			t.Errorf("Expected ErrSymbolNotFound with the original message, got: %v", err)
+		}
+
+		_, err = Sort(context.Background(), filePath, "Missing", SortOptions{MapKeys: true})
+		if !errors.Is(err, ErrSymbolNotFound) {
+			t.Errorf("Expected ErrSymbolNotFound from Sort, got: %v", err)
+		}
+
+		// The position lookup fails before gopls is called
+		_, err = Rename(context.Background(), filePath, 4, "Hello", "Greet")
+		if !errors.Is(err, ErrSymbolNotFound) || errors.Is(err, ErrGoplsUnavailable) {
+			t.Errorf("Expected ErrSymbolNotFound from Rename, got: %v", err)
+		}
+
+		_, err = Completion(context.Background(), filePath, 4, "missing.", nil)
+		if !errors.Is(err, ErrSymbolNotFound) {
+			t.Errorf("Expected ErrSymbolNotFound from Completion, got: %v", err)
+		}
+		_, err = SignatureHelp(context.Background(), filePath, 4, "missing", "")
+		if !errors.Is(err, ErrSymbolNotFound) {
+			t.Errorf("Expected ErrSymbolNotFound from SignatureHelp, got: %v", err)
+		}
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{
+			"package testpkg", // 1
+			"",                // 2
+			"func Bad( {",     // 3
+		})
+
+		_, err := Sort(context.Background(), filePath, "", SortOptions{MapKeys: true})
+		if !errors.Is(err, ErrSyntaxErrors) || !strings.Contains(err.Error(), "requires a file without syntax errors") {
+			t.Errorf("Expected ErrSyntaxErrors from Sort, got: %v", err)
+		}
+		_, err = StructLayout(context.Background(), filePath, "Bad", "amd64")
+		if !errors.Is(err, ErrSyntaxErrors) {
+			t.Errorf("Expected ErrSyntaxErrors from StructLayout, got: %v", err)
+		}
+	})
+
+	t.Run("outside workspace", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+
+		if err := CheckWorkspacePath(filepath.Join(workspace, "main.go"), workspace); err != nil {
+			t.Errorf("Expected path inside the workspace to be allowed, got: %v", err)
+		}
+		err := CheckWorkspacePath("/etc/passwd", workspace)
+		if !errors.Is(err, ErrOutsideWorkspace) || !strings.Contains(err.Error(), "/etc/passwd") {
+			t.Errorf("Expected ErrOutsideWorkspace naming the path, got: %v", err)
+		}
+	})
+
+	t.Run("gopls unavailable", func(t *testing.T) {
+		t.Parallel()
+		if _, err := exec.LookPath("gopls"); err == nil {
+			t.Skip("gopls is installed")
+		}
+		filePath := writeTestFile(t, validLines)
+
+		_, err := Rename(context.Background(), filePath, 3, "Hello", "Greet")
+		if !errors.Is(err, ErrGoplsUnavailable) || !strings.Contains(err.Error(), "go install golang.org/x/tools/gopls") {
+			t.Errorf("Expected ErrGoplsUnavailable with install hint, got: %v", err)
+		}
+	})
+}
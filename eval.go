@@ -0,0 +1,222 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// evalRepoPlaceholder is replaced by the checkout directory in the string arguments of queries
+const evalRepoPlaceholder = "{repo}"
+
+// EvalCorpus is a set of pinned repositories and the tool calls Eval runs against them,
+// stored as JSON, see eval/corpus.json
+type EvalCorpus struct {
+	// MaxLatency bounds the duration of every call not setting its own, e.g. "30s"
+	MaxLatency string     `json:"max_latency,omitempty"`
+	Repos      []EvalRepo `json:"repos"`
+}
+
+// EvalRepo is a git repository pinned to a tag or commit
+type EvalRepo struct {
+	// Name identifies the repository in reports and in the cache directory
+	Name    string      `json:"name"`
+	URL     string      `json:"url"`
+	Ref     string      `json:"ref"`
+	Queries []EvalQuery `json:"queries"`
+}
+
+// EvalQuery is a tool call run against a repository. Every call must finish without a panic
+// within the latency bound and return a non-empty result that is not an error.
+type EvalQuery struct {
+	Tool string `json:"tool"`
+	// Arguments of the call, "{repo}" in strings being replaced by the checkout directory
+	Arguments map[string]any `json:"arguments"`
+	// Expect lists texts the result must contain
+	Expect     []string `json:"expect,omitempty"`
+	MaxLatency string   `json:"max_latency,omitempty"`
+}
+
+// EvalOptions configures Eval
+type EvalOptions struct {
+	// CacheDir holds the checkouts of the repositories, which are reused by later runs
+	CacheDir string
+	// MaxLatency bounds calls when neither the corpus nor the query sets a bound, one
+	// minute when zero
+	MaxLatency time.Duration
+}
+
+// EvalResult is the outcome of a query
+type EvalResult struct {
+	Repo     string
+	Query    EvalQuery
+	Duration time.Duration
+	// Failures lists the violated invariants, empty when the query passed
+	Failures []string
+}
+
+// LoadEvalCorpus reads a corpus from a JSON file
+func LoadEvalCorpus(path string) (*EvalCorpus, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var corpus EvalCorpus
+	if err := json.Unmarshal(content, &corpus); err != nil {
+		return nil, fmt.Errorf("invalid corpus %s: %w", path, err)
+	}
+	for _, repo := range corpus.Repos {
+		if repo.Name == "" || repo.URL == "" || repo.Ref == "" {
+			return nil, fmt.Errorf("invalid corpus %s: repos need a name, url and ref", path)
+		}
+	}
+	return &corpus, nil
+}
+
+// Eval checks out the repositories of the corpus into the cache directory and runs their
+// queries against the MCP server, returning one result per query. Checkouts that fail
+// are reported as failures of their queries, so one unavailable repository does not hide
+// the results of the others.
+func Eval(ctx context.Context, mcpServer *server.MCPServer, corpus *EvalCorpus, options EvalOptions) ([]EvalResult, error) {
+	if options.CacheDir == "" {
+		return nil, fmt.Errorf("a cache directory is required")
+	}
+	if err := os.MkdirAll(options.CacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	defaultLatency := options.MaxLatency
+	if defaultLatency == 0 {
+		defaultLatency = time.Minute
+	}
+	if corpus.MaxLatency != "" {
+		latency, err := time.ParseDuration(corpus.MaxLatency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_latency of the corpus: %w", err)
+		}
+		defaultLatency = latency
+	}
+
+	var results []EvalResult
+	for _, repo := range corpus.Repos {
+		dir, checkoutErr := checkoutEvalRepo(ctx, options.CacheDir, repo)
+		rewriter := newPathRewriter(map[string]string{evalRepoPlaceholder: dir})
+		for _, query := range repo.Queries {
+			result := EvalResult{Repo: repo.Name, Query: query}
+			if checkoutErr != nil {
+				result.Failures = append(result.Failures, fmt.Sprintf("checkout failed: %v", checkoutErr))
+				results = append(results, result)
+				continue
+			}
+			maxLatency := defaultLatency
+			if query.MaxLatency != "" {
+				latency, err := time.ParseDuration(query.MaxLatency)
+				if err != nil {
+					return results, fmt.Errorf("invalid max_latency of %s query %s: %w", repo.Name, query.Tool, err)
+				}
+				maxLatency = latency
+			}
+
+			start := time.Now()
+			response := evalCall(mcpServer, JSONLRequest{
+				Tool:      query.Tool,
+				Arguments: rewriteArguments(rewriter, query.Arguments),
+			})
+			result.Duration = time.Since(start)
+			result.Failures = evalFailures(response, query, result.Duration, maxLatency)
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// evalCall calls the tool, reporting panics of the handler as errors
+func evalCall(mcpServer *server.MCPServer, request JSONLRequest) (response JSONLResponse) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			response = JSONLResponse{Tool: request.Tool, Error: fmt.Sprintf("panic: %v", recovered)}
+		}
+	}()
+	return executeJSONLRequest(mcpServer, request)
+}
+
+// evalFailures returns the invariants a query result violates
+func evalFailures(response JSONLResponse, query EvalQuery, duration time.Duration, maxLatency time.Duration) []string {
+	var failures []string
+	switch {
+	case response.Error != "":
+		failures = append(failures, "call failed: "+response.Error)
+	case response.IsError:
+		failures = append(failures, "tool returned an error: "+response.Text)
+	case strings.TrimSpace(response.Text) == "":
+		failures = append(failures, "empty result")
+	}
+	if duration > maxLatency {
+		failures = append(failures, fmt.Sprintf("took %s, more than %s", duration.Round(time.Millisecond), maxLatency))
+	}
+	for _, expected := range query.Expect {
+		if response.Error == "" && !strings.Contains(response.Text, expected) {
+			failures = append(failures, fmt.Sprintf("result does not contain %q", expected))
+		}
+	}
+	return failures
+}
+
+// checkoutEvalRepo returns the checkout of the pinned repository in the cache directory,
+// fetching it first when missing. Only the pinned commit is fetched.
+func checkoutEvalRepo(ctx context.Context, cacheDir string, repo EvalRepo) (string, error) {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(repo.Name + "@" + repo.Ref)
+	dir := filepath.Join(cacheDir, name)
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		// Pinned refs never change, so an existing checkout is current
+		return dir, nil
+	}
+
+	// Checkouts are prepared next to their final directory, so interrupted fetches are never reused
+	partial := dir + ".partial"
+	if err := os.RemoveAll(partial); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(partial, 0755); err != nil {
+		return "", err
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", repo.URL, repo.Ref},
+		{"-c", "advice.detachedHead=false", "checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		if _, err := runGit(ctx, partial, args...); err != nil {
+			os.RemoveAll(partial)
+			return "", fmt.Errorf("failed to check out %s at %s: %w", repo.URL, repo.Ref, err)
+		}
+	}
+	if err := os.Rename(partial, dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// FormatEvalResults lists the results one per line, followed by the failures of each failed
+// query, and a summary
+func FormatEvalResults(results []EvalResult) string {
+	var b strings.Builder
+	failed := 0
+	for _, result := range results {
+		status := "PASS"
+		if len(result.Failures) > 0 {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Fprintf(&b, "%s %s %s (%s)\n", status, result.Repo, result.Query.Tool, result.Duration.Round(time.Millisecond))
+		for _, failure := range result.Failures {
+			fmt.Fprintf(&b, "    %s\n", strings.ReplaceAll(strings.TrimSpace(failure), "\n", "\n    "))
+		}
+	}
+	fmt.Fprintf(&b, "%d of %d queries passed\n", len(results)-failed, len(results))
+	return b.String()
+}
@@ -0,0 +1,191 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to run git in a directory
+	git := func(t testing.TB, dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	// Helper function to create a git repository of a module tagged v1.0.0
+	createTestRepo := func(t testing.TB) string {
+		tempDir := writeTestModule(t, map[string][]string{
+			"go.mod": {"module testmodule", "", "go 1.21", ""},
+			"greet.go": {
+				"package testpkg",                  // 1
+				"",                                 // 2
+				"// Greet returns a greeting",      // 3
+				"func Greet(name string) string {", // 4
+				"	return \"Hello, \" + name",       // 5
+				"}",                                // 6
+				"",                                 // 7
+			},
+		})
+		git(t, tempDir, "init", "--quiet")
+		git(t, tempDir, "add", "-A")
+		git(t, tempDir, "commit", "--quiet", "-m", "Initial commit")
+		git(t, tempDir, "tag", "v1.0.0")
+		return tempDir
+	}
+
+	t.Run("passing and failing queries", func(t *testing.T) {
+		t.Parallel()
+		repo := createTestRepo(t)
+		corpus := &EvalCorpus{Repos: []EvalRepo{{
+			Name: "test/repo",
+			URL:  repo,
+			Ref:  "v1.0.0",
+			Queries: []EvalQuery{
+				{
+					Tool:      bodyToolName,
+					Arguments: map[string]any{"path": "{repo}", "symbol": "Greet"},
+					Expect:    []string{"func Greet(name string) string"},
+				},
+				{
+					Tool:      bodyToolName,
+					Arguments: map[string]any{"path": "{repo}", "symbol": "Greet"},
+					Expect:    []string{"func Farewell"},
+				},
+				{
+					Tool:      bodyToolName,
+					Arguments: map[string]any{"path": "{repo}", "symbol": "Missing"},
+				},
+			},
+		}}}
+
+		results, err := Eval(context.Background(), NewMCPServer(), corpus, EvalOptions{CacheDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("Eval failed: %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results, got %d", len(results))
+		}
+		if len(results[0].Failures) != 0 {
+			t.Errorf("Expected the first query to pass, got failures: %v", results[0].Failures)
+		}
+		if len(results[1].Failures) != 1 || !strings.Contains(results[1].Failures[0], `does not contain "func Farewell"`) {
+			t.Errorf("Expected a missing expected text failure, got: %v", results[1].Failures)
+		}
+		if len(results[2].Failures) != 1 || !strings.Contains(results[2].Failures[0], "tool returned an error") {
+			t.Errorf("Expected a tool error failure, got: %v", results[2].Failures)
+		}
+
+		report := FormatEvalResults(results)
+		for _, expected := range []string{"PASS test/repo body", "FAIL test/repo body", "1 of 3 queries passed"} {
+			if !strings.Contains(report, expected) {
+				t.Errorf("Expected report to contain %q, got:\n%s", expected, report)
+			}
+		}
+	})
+
+	t.Run("latency bound", func(t *testing.T) {
+		t.Parallel()
+		repo := createTestRepo(t)
+		corpus := &EvalCorpus{Repos: []EvalRepo{{
+			Name: "test/repo",
+			URL:  repo,
+			Ref:  "v1.0.0",
+			Queries: []EvalQuery{{
+				Tool:       bodyToolName,
+				Arguments:  map[string]any{"path": "{repo}", "symbol": "Greet"},
+				MaxLatency: "1ns",
+			}},
+		}}}
+
+		results, err := Eval(context.Background(), NewMCPServer(), corpus, EvalOptions{CacheDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("Eval failed: %v", err)
+		}
+		if len(results[0].Failures) != 1 || !strings.Contains(results[0].Failures[0], "more than 1ns") {
+			t.Errorf("Expected a latency failure, got: %v", results[0].Failures)
+		}
+	})
+
+	t.Run("checkouts are cached", func(t *testing.T) {
+		t.Parallel()
+		repo := createTestRepo(t)
+		cacheDir := t.TempDir()
+		corpus := &EvalCorpus{Repos: []EvalRepo{{
+			Name: "test/repo",
+			URL:  repo,
+			Ref:  "v1.0.0",
+			Queries: []EvalQuery{{
+				Tool:      bodyToolName,
+				Arguments: map[string]any{"path": "{repo}", "symbol": "Greet"},
+			}},
+		}}}
+
+		if _, err := Eval(context.Background(), NewMCPServer(), corpus, EvalOptions{CacheDir: cacheDir}); err != nil {
+			t.Fatalf("Eval failed: %v", err)
+		}
+		// The source repository is gone, so the second run can only use the cache
+		if err := os.RemoveAll(repo); err != nil {
+			t.Fatal(err)
+		}
+		results, err := Eval(context.Background(), NewMCPServer(), corpus, EvalOptions{CacheDir: cacheDir})
+		if err != nil {
+			t.Fatalf("Eval failed: %v", err)
+		}
+		if len(results[0].Failures) != 0 {
+			t.Errorf("Expected the cached checkout to be used, got failures: %v", results[0].Failures)
+		}
+	})
+
+	t.Run("failed checkouts fail their queries", func(t *testing.T) {
+		t.Parallel()
+		corpus := &EvalCorpus{Repos: []EvalRepo{{
+			Name: "test/missing",
+			URL:  filepath.Join(t.TempDir(), "missing"),
+			Ref:  "v1.0.0",
+			Queries: []EvalQuery{{
+				Tool:      bodyToolName,
+				Arguments: map[string]any{"path": "{repo}", "symbol": "Greet"},
+			}},
+		}}}
+
+		results, err := Eval(context.Background(), NewMCPServer(), corpus, EvalOptions{CacheDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("Eval failed: %v", err)
+		}
+		if len(results) != 1 || len(results[0].Failures) != 1 || !strings.Contains(results[0].Failures[0], "checkout failed") {
+			t.Errorf("Expected a checkout failure, got: %v", results)
+		}
+	})
+
+	t.Run("default corpus loads", func(t *testing.T) {
+		t.Parallel()
+		corpus, err := LoadEvalCorpus(filepath.Join("eval", "corpus.json"))
+		if err != nil {
+			t.Fatalf("LoadEvalCorpus failed: %v", err)
+		}
+		schemas := &toolSchemas{mcpServer: NewMCPServer()}
+		for _, repo := range corpus.Repos {
+			for _, query := range repo.Queries {
+				schema, ok := schemas.get(context.Background(), query.Tool)
+				if !ok {
+					t.Errorf("Unknown tool %s in query of %s", query.Tool, repo.Name)
+					continue
+				}
+				if errs := ValidateArguments(schema, query.Arguments); len(errs) > 0 {
+					t.Errorf("Invalid arguments of %s query of %s: %v", query.Tool, repo.Name, errs)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Example is an ExampleXxx function of a _test.go file documenting a symbol
+type Example struct {
+	// Name is the name of the function, e.g. ExampleBuffer_Write_second
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Code string `json:"code"`
+	// Output is the output expected by the // Output: comment and HasOutput whether the
+	// example has one. go test only runs examples with an output comment.
+	Output    string `json:"output,omitempty"`
+	HasOutput bool   `json:"has_output"`
+	// Run is the result of running the example, only set when requested
+	Run *ExampleRun `json:"run,omitempty"`
+}
+
+// ExampleRun is the result of running an example with go test. Error is set when the
+// example was not run, e.g. because its package fails to build.
+type ExampleRun struct {
+	// Passed reports whether the output of the example matched its output comment
+	Passed bool `json:"passed"`
+	// Got is the output of an example that did not match
+	Got   string `json:"got,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// exampleTarget returns the name examples of the symbol are named after, e.g. T_M
+// for ExampleT_M of method M of T, or empty when the symbol cannot have examples
+func exampleTarget(symbol *SymbolInfo) string {
+	switch symbol.Kind {
+	case SymbolFunction, SymbolType, SymbolInterface:
+		return symbol.Name
+	case SymbolMethod:
+		receiver := strings.TrimPrefix(symbol.Receiver, "*")
+		if receiver == "unknown" {
+			return ""
+		}
+		return receiver + "_" + symbol.Name
+	}
+	return ""
+}
+
+// documentsTarget reports whether the example named name, without the Example prefix,
+// documents target, optionally with a suffix starting with a lower case letter as in
+// ExampleT_M_second
+func documentsTarget(name, target string) bool {
+	suffix, ok := strings.CutPrefix(name, target)
+	if !ok {
+		return false
+	}
+	if suffix == "" {
+		return true
+	}
+	suffix, ok = strings.CutPrefix(suffix, "_")
+	return ok && suffix != "" && !strings.Contains(suffix, "_") && unicode.IsLower(rune(suffix[0]))
+}
+
+// findExamples returns the examples of the symbol in the _test.go files of its directory,
+// both of the package and of its external test package
+func findExamples(symbol *SymbolInfo) ([]Example, error) {
+	target := exampleTarget(symbol)
+	if target == "" {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(filepath.Dir(symbol.File), "*_test.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	declarations := make(map[string]*ast.FuncDecl)
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			// A test file that does not parse has no examples go test could run
+			continue
+		}
+		files = append(files, file)
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+				declarations[funcDecl.Name.Name] = funcDecl
+			}
+		}
+	}
+
+	var examples []Example
+	for _, example := range doc.Examples(files...) {
+		if !documentsTarget(example.Name, target) {
+			continue
+		}
+		name := "Example" + example.Name
+		funcDecl, ok := declarations[name]
+		if !ok {
+			continue
+		}
+		start := fset.Position(funcDecl.Pos())
+		code, err := readSourceLines(start.Filename, start.Line, fset.Position(funcDecl.End()).Line, nil)
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, Example{
+			Name:      name,
+			File:      start.Filename,
+			Line:      start.Line,
+			Code:      code,
+			Output:    strings.TrimSuffix(example.Output, "\n"),
+			HasOutput: example.Output != "" || example.EmptyOutput,
+		})
+	}
+	slices.SortFunc(examples, func(a, b Example) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return examples, nil
+}
+
+// runExamples runs the examples with an output comment with go test in dir, the
+// directory of their package, and records whether their output matched
+func runExamples(ctx context.Context, dir string, examples []*Example) {
+	var names []string
+	for _, example := range examples {
+		if !example.HasOutput {
+			example.Run = &ExampleRun{Error: "not run, go test only runs examples with an // Output: comment"}
+			continue
+		}
+		names = append(names, example.Name)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	output, err := commandOutput(ctx, dir, nil, "go", "test", "-count=1", "-v", "-run", "^("+strings.Join(names, "|")+")$", ".")
+	runs := parseExampleOutput(string(output))
+	for _, example := range examples {
+		if example.Run != nil {
+			continue
+		}
+		if run, ok := runs[example.Name]; ok {
+			example.Run = run
+		} else if err != nil {
+			example.Run = &ExampleRun{Error: fmt.Sprintf("go test failed: %v\n%s", err, strings.TrimSpace(string(output)))}
+		} else {
+			example.Run = &ExampleRun{Error: "go test did not run the example"}
+		}
+	}
+}
+
+// parseExampleOutput returns the results of the examples in the output of go test -v,
+// where a failed example is followed by its output and the expected one:
+//
+//	--- FAIL: ExampleParse (0.00s)
+//	got:
+//	1
+//	want:
+//	2
+func parseExampleOutput(output string) map[string]*ExampleRun {
+	runs := make(map[string]*ExampleRun)
+	var failed *ExampleRun
+	var got []string
+	inGot := false
+	for line := range strings.Lines(output) {
+		line = strings.TrimSuffix(line, "\n")
+		if rest, ok := strings.CutPrefix(line, "--- PASS: "); ok {
+			name, _, _ := strings.Cut(rest, " ")
+			runs[name] = &ExampleRun{Passed: true}
+			failed = nil
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "--- FAIL: "); ok {
+			name, _, _ := strings.Cut(rest, " ")
+			failed = &ExampleRun{}
+			runs[name] = failed
+			got = nil
+			continue
+		}
+		if failed == nil {
+			continue
+		}
+		switch {
+		case line == "got:":
+			inGot = true
+		case line == "want:":
+			failed.Got = strings.Join(got, "\n")
+			inGot = false
+			failed = nil
+		case inGot:
+			got = append(got, line)
+		}
+	}
+	return runs
+}
+
+// addExamples adds the examples of the symbol, and of its methods when it is a type,
+// running them when run is set
+func addExamples(ctx context.Context, symbol *SymbolInfo, run bool) error {
+	symbols := []*SymbolInfo{symbol}
+	for i := range symbol.Methods {
+		symbols = append(symbols, &symbol.Methods[i])
+	}
+	var examples []*Example
+	for _, s := range symbols {
+		found, err := findExamples(s)
+		if err != nil {
+			return err
+		}
+		s.Examples = found
+		for i := range s.Examples {
+			examples = append(examples, &s.Examples[i])
+		}
+	}
+	if run && len(examples) > 0 {
+		runExamples(ctx, filepath.Dir(symbol.File), examples)
+	}
+	return nil
+}
+
+func writeExample(b *strings.Builder, example *Example) {
+	fmt.Fprintf(b, "Example: %s (%s:%d)\n", example.Name, example.File, example.Line)
+	b.WriteString(example.Code)
+	if run := example.Run; run != nil {
+		switch {
+		case run.Error != "":
+			fmt.Fprintf(b, "\nVerified: %s", run.Error)
+		case run.Passed:
+			b.WriteString("\nVerified: the output matches the output comment")
+		default:
+			fmt.Fprintf(b, "\nVerified: FAILED, the output differs from the output comment, got:\n%s", run.Got)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// SandboxPolicy restricts the subprocesses the tools run, i.e. go, git, gopls and
+// linters such as govulncheck
+type SandboxPolicy struct {
+	// Commands maps the programs that may run to their allowed subcommands, any
+	// subcommand when the list is empty. Other programs are refused.
+	Commands map[string][]string
+	// Env are the names of the environment variables of the server passed on to
+	// subprocesses, a trailing * matching a prefix. Other variables are scrubbed.
+	Env []string
+	// Roots are the directories subprocesses may run in, besides the temporary
+	// directory tools copy modules to. Any directory when empty.
+	Roots []string
+	// Timeout limits how long a subprocess may run. Zero means unlimited.
+	Timeout time.Duration
+	// CPUTime limits the CPU time of every process started (RLIMIT_CPU), including
+	// the compilers and test binaries the go command runs. Zero means unlimited.
+	CPUTime time.Duration
+	// Memory limits the virtual memory in bytes of every process started (RLIMIT_AS).
+	// Go programs reserve far more address space than they use and the race detector
+	// reserves terabytes, so only set it generously. Zero means unlimited.
+	Memory int64
+}
+
+// DefaultSandboxPolicy is the policy of subprocesses unless changed with SetSandboxPolicy
+var DefaultSandboxPolicy = SandboxPolicy{
+	Commands: map[string][]string{
+		"go":          {"build", "doc", "env", "get", "list", "mod", "test", "vet", "version"},
+		"git":         {"add", "archive", "checkout", "commit", "diff", "fetch", "init", "log", "rev-parse", "show", "status", "worktree"},
+		"gopls":       nil,
+		"govulncheck": nil,
+	},
+	Env: []string{
+		"PATH", "HOME", "USER", "LOGNAME", "TMPDIR", "LANG", "LC_*", "TZ",
+		"XDG_CACHE_HOME", "XDG_CONFIG_HOME", "SSL_CERT_FILE", "SSL_CERT_DIR",
+		"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+		"GO*", "CGO_*", "CC", "CXX", "PKG_CONFIG*", "GIT_*",
+		// Windows needs these to start processes and find the user directories
+		"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "USERPROFILE", "LOCALAPPDATA", "APPDATA", "TEMP", "TMP",
+	},
+	Timeout: 10 * time.Minute,
+	CPUTime: 10 * time.Minute,
+}
+
+var sandbox = struct {
+	mu     sync.RWMutex
+	policy SandboxPolicy
+}{policy: DefaultSandboxPolicy}
+
+// SetSandboxPolicy changes the policy of the subprocesses run by all tools and servers
+// in the process. Subprocesses already running are not affected.
+func SetSandboxPolicy(policy SandboxPolicy) {
+	sandbox.mu.Lock()
+	defer sandbox.mu.Unlock()
+	sandbox.policy = policy
+}
+
+// WithSandboxPolicy sets the subprocess policy when the server is created. Without
+// roots, subprocesses are pinned to the directories of WithWorkspaceAllowlist.
+// The policy is shared by all servers in the process.
+func WithSandboxPolicy(policy SandboxPolicy) Option {
+	return func(o *serverOptions) {
+		o.sandboxPolicy = &policy
+	}
+}
+
+func currentSandboxPolicy() SandboxPolicy {
+	sandbox.mu.RLock()
+	defer sandbox.mu.RUnlock()
+	return sandbox.policy
+}
+
+// runCommand runs name with args in dir under the sandbox policy, with the extra
+// environment variables added to the scrubbed environment of the server
+func runCommand(ctx context.Context, dir string, env []string, stdout, stderr io.Writer, name string, args ...string) error {
+	policy := currentSandboxPolicy()
+	return policy.run(ctx, dir, env, stdout, stderr, name, args...)
+}
+
+// loadPackages loads packages like packages.Load under the sandbox policy. The go list
+// processes it runs get the scrubbed environment of the server with the extra variables
+// of config.Env, run in a directory inside the roots, and are killed when ctx is done or
+// the timeout of the policy passes.
+func loadPackages(ctx context.Context, config *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	policy := currentSandboxPolicy()
+	return policy.load(ctx, config, patterns...)
+}
+
+func (policy *SandboxPolicy) load(ctx context.Context, config *packages.Config, patterns ...string) ([]*packages.Package, error) {
+	if err := policy.allow("go", []string{"list"}); err != nil {
+		return nil, err
+	}
+	if err := policy.pin(config.Dir); err != nil {
+		return nil, err
+	}
+	loadConfig := *config
+	loadConfig.Context = ctx
+	if policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		loadConfig.Context, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+	loadConfig.Env = append(policy.environ(os.Environ()), config.Env...)
+	pkgs, err := packages.Load(&loadConfig, patterns...)
+	if err != nil && ctx.Err() == nil && errors.Is(loadConfig.Context.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: go list exceeded the sandbox timeout of %s", err, policy.Timeout)
+	}
+	return pkgs, err
+}
+
+// commandOutput runs the command like runCommand and returns its standard and error output
+func commandOutput(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
+	var output bytes.Buffer
+	err := runCommand(ctx, dir, env, &output, &output, name, args...)
+	return output.Bytes(), err
+}
+
+func (policy *SandboxPolicy) run(
+	ctx context.Context,
+	dir string,
+	env []string,
+	stdout, stderr io.Writer,
+	name string,
+	args ...string,
+) error {
+	if err := policy.allow(name, args); err != nil {
+		return err
+	}
+	if err := policy.pin(dir); err != nil {
+		return err
+	}
+	// Resolving the program first keeps exec.ErrNotFound when it runs through the shell
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return err
+	}
+
+	runCtx := ctx
+	if policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(runCtx, path, args...)
+	if script := policy.limitScript(); script != "" {
+		// The shell sets the limits and replaces itself with the program, so they
+		// apply from its first instruction and are inherited by its children
+		cmd = exec.CommandContext(runCtx, "/bin/sh", append([]string{"-c", script, path}, args...)...)
+	}
+	cmd.Dir = dir
+	cmd.Env = append(policy.environ(os.Environ()), env...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Cancelling kills the process group, and the wait delay stops waiting for output
+	// held open by grandchildren that escaped it
+	setProcessGroup(cmd)
+	cmd.WaitDelay = subprocessWaitDelay
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	subprocesses.add(cmd, sessionIDFromContext(ctx))
+	err = cmd.Wait()
+	subprocesses.remove(cmd)
+	if err != nil && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s exceeded the sandbox timeout of %s", err, name, policy.Timeout)
+	}
+	return err
+}
+
+// subprocessWaitDelay is how long a killed subprocess may keep its output open
+const subprocessWaitDelay = 5 * time.Second
+
+// Subprocess is a running subprocess of a tool call
+type Subprocess struct {
+	PID     int
+	Command string
+	// Session is the ID of the session whose tool call started the subprocess, empty
+	// for calls without a session and work outside of tool calls such as gopls queries
+	Session string
+	Started time.Time
+}
+
+// processRegistry tracks the running subprocesses, so they can be killed when the
+// session that started them ends or the server shuts down
+type processRegistry struct {
+	mu        sync.Mutex
+	processes map[*exec.Cmd]Subprocess
+}
+
+var subprocesses = &processRegistry{processes: make(map[*exec.Cmd]Subprocess)}
+
+func (registry *processRegistry) add(cmd *exec.Cmd, session string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.processes[cmd] = Subprocess{
+		PID:     cmd.Process.Pid,
+		Command: strings.Join(cmd.Args, " "),
+		Session: session,
+		Started: time.Now(),
+	}
+}
+
+func (registry *processRegistry) remove(cmd *exec.Cmd) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.processes, cmd)
+}
+
+// kill kills the process groups of the subprocesses accepted by match and returns how
+// many were killed. Their tool calls fail with the error of the killed process.
+func (registry *processRegistry) kill(match func(Subprocess) bool) int {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	killed := 0
+	for cmd, subprocess := range registry.processes {
+		if match(subprocess) && killProcessGroup(cmd.Process) == nil {
+			killed++
+		}
+	}
+	return killed
+}
+
+// Subprocesses returns the running subprocesses of all tools and servers in the process,
+// oldest first
+func Subprocesses() []Subprocess {
+	subprocesses.mu.Lock()
+	defer subprocesses.mu.Unlock()
+	running := make([]Subprocess, 0, len(subprocesses.processes))
+	for _, subprocess := range subprocesses.processes {
+		running = append(running, subprocess)
+	}
+	slices.SortFunc(running, func(a, b Subprocess) int {
+		return a.Started.Compare(b.Started)
+	})
+	return running
+}
+
+// KillSessionSubprocesses kills the subprocesses started by the tool calls of a session
+// with their process groups and returns how many were killed. Servers call it when a
+// session ends.
+func KillSessionSubprocesses(session string) int {
+	return subprocesses.kill(func(subprocess Subprocess) bool {
+		return subprocess.Session == session
+	})
+}
+
+// KillSubprocesses kills all running subprocesses with their process groups and returns
+// how many were killed, e.g. when shutting down
+func KillSubprocesses() int {
+	return subprocesses.kill(func(Subprocess) bool { return true })
+}
+
+// allow refuses programs and subcommands outside the allowlist
+func (policy *SandboxPolicy) allow(name string, args []string) error {
+	subcommands, ok := policy.Commands[name]
+	if !ok {
+		return fmt.Errorf("the sandbox policy does not allow running %s", name)
+	}
+	if len(subcommands) == 0 {
+		return nil
+	}
+	subcommand := ""
+	for i := 0; i < len(args); i++ {
+		// Global options of git and go taking a value, e.g. git -c key=value or go -C dir
+		if args[i] == "-c" || args[i] == "-C" {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			subcommand = args[i]
+			break
+		}
+	}
+	if !slices.Contains(subcommands, subcommand) {
+		return fmt.Errorf("the sandbox policy does not allow running %s %s", name, subcommand)
+	}
+	return nil
+}
+
+// pin refuses directories outside the roots and relative directories, which would
+// resolve against the working directory of the server
+func (policy *SandboxPolicy) pin(dir string) error {
+	if !filepath.IsAbs(dir) {
+		return fmt.Errorf("subprocesses must run in an absolute directory, got: %q", dir)
+	}
+	if len(policy.Roots) == 0 {
+		return nil
+	}
+	for _, root := range append(slices.Clone(policy.Roots), os.TempDir()) {
+		if isFileInWorkspace(dir, root) {
+			return nil
+		}
+	}
+	return classifyErrorf(ErrOutsideWorkspace, "the sandbox policy does not allow running subprocesses in %s", dir)
+}
+
+// environ returns the variables of environment the policy passes on
+func (policy *SandboxPolicy) environ(environment []string) []string {
+	var kept []string
+	for _, variable := range environment {
+		name, _, _ := strings.Cut(variable, "=")
+		for _, pattern := range policy.Env {
+			prefix, wildcard := strings.CutSuffix(pattern, "*")
+			if name == pattern || wildcard && strings.HasPrefix(name, prefix) {
+				kept = append(kept, variable)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// limitScript returns the shell script setting the resource limits of the policy
+// before running the program given as its arguments, empty without limits or where
+// the limits cannot be set
+func (policy *SandboxPolicy) limitScript() string {
+	if policy.CPUTime <= 0 && policy.Memory <= 0 || runtime.GOOS == "windows" {
+		return ""
+	}
+	var script strings.Builder
+	if policy.CPUTime > 0 {
+		fmt.Fprintf(&script, "ulimit -t %d || exit 126; ", max(1, int64(policy.CPUTime/time.Second)))
+	}
+	if policy.Memory > 0 {
+		fmt.Fprintf(&script, "ulimit -v %d || exit 126; ", max(1, policy.Memory>>10))
+	}
+	script.WriteString(`exec "$0" "$@"`)
+	return script.String()
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package go_mcp_tools
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup keeps the default cancellation, which only kills the process itself,
+// as process groups are a unix concept
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills process, its children are not tracked without process groups
+func killProcessGroup(process *os.Process) error {
+	return process.Kill()
+}
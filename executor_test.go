@@ -0,0 +1,244 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestSandboxPolicy(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test runs shell commands")
+	}
+	policy := SandboxPolicy{
+		Commands: map[string][]string{
+			"go":    {"env", "version"},
+			"git":   {"status"},
+			"sh":    nil,
+			"sleep": nil,
+		},
+		Env: []string{"PATH", "HOME", "GO*"},
+	}
+
+	t.Run("allowlist", func(t *testing.T) {
+		t.Parallel()
+		for _, args := range [][]string{
+			{"go", "version"},
+			{"git", "-c", "core.quotepath=off", "status"},
+			{"sh", "-c", "exit 0"},
+		} {
+			if err := policy.allow(args[0], args[1:]); err != nil {
+				t.Errorf("Expected %v to be allowed, got: %v", args, err)
+			}
+		}
+		for _, args := range [][]string{
+			{"curl", "example.com"},
+			{"go", "run", "main.go"},
+			{"go", "-C", "version", "run", "."},
+			{"git", "push"},
+			{"git"},
+		} {
+			if err := policy.allow(args[0], args[1:]); err == nil {
+				t.Errorf("Expected %v to be refused", args)
+			}
+		}
+		if err := policy.run(context.Background(), t.TempDir(), nil, nil, nil, "go", "run", "."); err == nil || !strings.Contains(err.Error(), "does not allow running go run") {
+			t.Errorf("Expected go run to be refused, got: %v", err)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Parallel()
+		kept := policy.environ([]string{"PATH=/bin", "GOFLAGS=-mod=mod", "AWS_SECRET_ACCESS_KEY=secret", "GOPHER", "HOMEDIR=/home"})
+		if strings.Join(kept, " ") != "PATH=/bin GOFLAGS=-mod=mod GOPHER" {
+			t.Errorf("Unexpected environment %v", kept)
+		}
+
+		pathOnly := policy
+		pathOnly.Env = []string{"PATH"}
+		var output bytes.Buffer
+		if err := pathOnly.run(context.Background(), t.TempDir(), []string{"GOOS=js"}, &output, &output, "sh", "-c", "env"); err != nil {
+			t.Fatalf("Failed to run env: %v", err)
+		}
+		if strings.Contains(output.String(), "\nHOME=") || !strings.Contains(output.String(), "GOOS=js\n") {
+			t.Errorf("Expected HOME to be scrubbed and GOOS to be set, got:\n%s", output.String())
+		}
+	})
+
+	t.Run("working directory", func(t *testing.T) {
+		t.Parallel()
+		if err := policy.pin("relative/dir"); err == nil {
+			t.Error("Expected a relative directory to be refused")
+		}
+		if err := policy.pin(""); err == nil {
+			t.Error("Expected an empty directory to be refused")
+		}
+		pinned := policy
+		pinned.Roots = []string{"/srv/workspace"}
+		if err := pinned.pin("/srv/workspace/module"); err != nil {
+			t.Errorf("Expected a directory inside the root to be allowed, got: %v", err)
+		}
+		if err := pinned.pin(t.TempDir()); err != nil {
+			t.Errorf("Expected the temporary directory to be allowed, got: %v", err)
+		}
+		if err := pinned.pin("/srv/workspace-other"); err == nil {
+			t.Error("Expected a directory outside the roots to be refused")
+		}
+
+		dir := t.TempDir()
+		var output bytes.Buffer
+		if err := policy.run(context.Background(), dir, nil, &output, nil, "sh", "-c", "pwd -P"); err != nil {
+			t.Fatalf("Failed to run pwd: %v", err)
+		}
+		if strings.TrimSpace(output.String()) != resolveSymlinks(dir) {
+			t.Errorf("Expected to run in %s, got %s", dir, output.String())
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		t.Parallel()
+		limited := policy
+		limited.CPUTime = 90 * time.Second
+		limited.Memory = 8 << 30
+		var output bytes.Buffer
+		if err := limited.run(context.Background(), t.TempDir(), nil, &output, nil, "sh", "-c", "ulimit -t; ulimit -v"); err != nil {
+			t.Fatalf("Failed to run ulimit: %v", err)
+		}
+		if output.String() != "90\n8388608\n" {
+			t.Errorf("Expected 90 seconds of CPU time and 8 GB of memory, got:\n%s", output.String())
+		}
+
+		limited.Timeout = 100 * time.Millisecond
+		start := time.Now()
+		err := limited.run(context.Background(), t.TempDir(), nil, nil, nil, "sleep", "10")
+		if err == nil || !strings.Contains(err.Error(), "exceeded the sandbox timeout of 100ms") {
+			t.Errorf("Expected the timeout to kill sleep, got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Expected sleep to be killed after the timeout, took %s", elapsed)
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+		// The shell prints the PID of a grandchild that must die with it
+		script := "sleep 30 & echo $!; wait"
+		stillRunning := func(output string) bool {
+			pid, err := strconv.Atoi(strings.TrimSpace(output))
+			if err != nil {
+				t.Fatalf("Expected a PID, got %q", output)
+			}
+			process, err := os.FindProcess(pid)
+			if err != nil {
+				return false
+			}
+			for range 50 {
+				if process.Signal(syscall.Signal(0)) != nil {
+					return false
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+			return true
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		var output bytes.Buffer
+		start := time.Now()
+		if err := policy.run(ctx, t.TempDir(), nil, &output, nil, "sh", "-c", script); err == nil {
+			t.Error("Expected an error for a cancelled command")
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf("Expected the command to stop when cancelled, took %s", elapsed)
+		}
+		if stillRunning(output.String()) {
+			t.Error("Expected the process group to be killed on cancellation")
+		}
+
+		session := &testSession{id: "executor-test-session"}
+		sessionCtx := server.NewMCPServer("test", "1.0").WithContext(context.Background(), session)
+		output.Reset()
+		done := make(chan error, 1)
+		go func() {
+			done <- policy.run(sessionCtx, t.TempDir(), nil, &output, nil, "sh", "-c", script)
+		}()
+		for !slices.ContainsFunc(Subprocesses(), func(subprocess Subprocess) bool { return subprocess.Session == session.id }) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if KillSessionSubprocesses("other-session") != 0 {
+			t.Error("Expected no subprocesses of another session to be killed")
+		}
+		if killed := KillSessionSubprocesses(session.id); killed != 1 {
+			t.Errorf("Expected the subprocess of the session to be killed, killed %d", killed)
+		}
+		if err := <-done; err == nil {
+			t.Error("Expected an error for a killed command")
+		}
+		if stillRunning(output.String()) {
+			t.Error("Expected the process group to be killed with the session")
+		}
+		if slices.ContainsFunc(Subprocesses(), func(subprocess Subprocess) bool { return subprocess.Session == session.id }) {
+			t.Error("Expected the killed subprocess to be untracked")
+		}
+	})
+
+	t.Run("package loading", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		for name, content := range map[string]string{
+			"go.mod":  "module example.com/app\n\ngo 1.21\n",
+			"main.go": "package main\n\nfunc main() {}\n",
+		} {
+			if err := os.WriteFile(filepath.Join(workspace, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		config := &packages.Config{Mode: packages.NeedName, Dir: workspace, Env: []string{"GOFLAGS=-mod=mod"}}
+
+		listing := policy
+		listing.Commands = map[string][]string{"go": {"list"}}
+		pkgs, err := listing.load(context.Background(), config, "./...")
+		if err != nil || len(pkgs) != 1 || pkgs[0].PkgPath != "example.com/app" {
+			t.Errorf("Expected the package of the module, got %v: %v", pkgs, err)
+		}
+
+		if _, err := policy.load(context.Background(), config, "./..."); err == nil || !strings.Contains(err.Error(), "does not allow running go list") {
+			t.Errorf("Expected go list to be refused, got: %v", err)
+		}
+		pinned := listing
+		pinned.Roots = []string{"/srv/workspace"}
+		outside := *config
+		outside.Dir = "/srv/other"
+		if _, err := pinned.load(context.Background(), &outside, "./..."); err == nil || !strings.Contains(err.Error(), "does not allow running subprocesses in /srv/other") {
+			t.Errorf("Expected a directory outside the roots to be refused, got: %v", err)
+		}
+		limited := listing
+		limited.Timeout = time.Nanosecond
+		if _, err := limited.load(context.Background(), config, "./..."); err == nil || !strings.Contains(err.Error(), "exceeded the sandbox timeout") {
+			t.Errorf("Expected the timeout to stop go list, got: %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		missing := SandboxPolicy{Commands: map[string][]string{"go-mcp-tools-missing": nil}, CPUTime: time.Minute}
+		if err := missing.run(context.Background(), t.TempDir(), nil, nil, nil, "go-mcp-tools-missing"); !errors.Is(err, exec.ErrNotFound) {
+			t.Errorf("Expected exec.ErrNotFound, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package go_mcp_tools
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in a process group of its own and kills the whole group
+// when it is cancelled, so the compilers, test binaries and other children it started
+// do not outlive it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd.Process)
+	}
+}
+
+// killProcessGroup kills the process group led by process
+func killProcessGroup(process *os.Process) error {
+	return syscall.Kill(-process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,318 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	extractInterfaceToolName        = "extract_interface"
+	extractInterfaceToolDescription = `Generates an interface from the exported methods of a concrete Go type, declared right after the type with the method signatures and docs. A subset of the methods can be chosen, all exported methods are used otherwise.
+
+Optionally rewrites call sites to use the interface: each call site is a file:line where the concrete type (T or *T) is used as the type of a parameter, result, field or variable, and is replaced by the interface, qualified in other packages. Pass output=patch to preview the changes as a unified diff without writing them.`
+)
+
+func AddExtractInterfaceTool(mcpServer *server.MCPServer) {
+	handleExtractInterface := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := request.RequireString("type")
+		if err != nil {
+			return nil, err
+		}
+		interfaceName, err := request.RequireString("interface_name")
+		if err != nil {
+			return nil, err
+		}
+		methods := request.GetStringSlice("methods", nil)
+		callSites := request.GetStringSlice("call_sites", nil)
+
+		result, err := ExtractInterface(ctx, filePath, typeName, interfaceName, methods, callSites)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error extracting interface: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		extractInterfaceToolName,
+		mcp.WithDescription(extractInterfaceToolDescription),
+		editingToolAnnotation(false, true),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the concrete type"),
+			mcp.Required(),
+		),
+		mcp.WithString("type",
+			mcp.Description("Name of the concrete type"),
+			mcp.Required(),
+		),
+		mcp.WithString("interface_name",
+			mcp.Description("Name of the interface to declare"),
+			mcp.Required(),
+		),
+		mcp.WithArray("methods",
+			mcp.Description("Exported methods of the type to include, all when empty"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("call_sites",
+			mcp.Description("Locations as /path/to/file.go:line where uses of the type as a type should be replaced by the interface"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	), handleExtractInterface)
+}
+
+// ExtractInterface declares an interface named interfaceName with the methods of the type
+// named typeName declared in filePath, all exported methods when methods is empty, and
+// replaces the type by the interface at the callSites given as file:line
+func ExtractInterface(
+	ctx context.Context,
+	filePath string,
+	typeName string,
+	interfaceName string,
+	methods []string,
+	callSites []string,
+) (string, error) {
+	if !filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if !token.IsIdentifier(interfaceName) {
+		return "", fmt.Errorf("'%s' is not a valid interface name", interfaceName)
+	}
+	pkg, _, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return "", err
+	}
+	if len(pkg.Errors) > 0 {
+		return "", classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+	concrete, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok || pkg.Fset.Position(concrete.Pos()).Filename != filePath {
+		return "", classifyErrorf(ErrSymbolNotFound, "no type '%s' declared in %s", typeName, filePath)
+	}
+	if existing := pkg.Types.Scope().Lookup(interfaceName); existing != nil {
+		return "", fmt.Errorf("%s is already declared in package %s", interfaceName, pkg.PkgPath)
+	}
+
+	// The method set of the pointer holds all methods declared on the type
+	methodSet := types.NewMethodSet(types.NewPointer(concrete.Type()))
+	var selected []*types.Func
+	pointerReceiver := false
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj().(*types.Func)
+		if !method.Exported() || (len(methods) > 0 && !slices.Contains(methods, method.Name())) {
+			continue
+		}
+		if _, ok := method.Type().(*types.Signature).Recv().Type().(*types.Pointer); ok {
+			pointerReceiver = true
+		}
+		selected = append(selected, method)
+	}
+	for _, name := range methods {
+		if !slices.ContainsFunc(selected, func(method *types.Func) bool { return method.Name() == name }) {
+			return "", classifyErrorf(ErrSymbolNotFound, "%s has no exported method '%s'", typeName, name)
+		}
+	}
+	if len(selected) == 0 {
+		return "", fmt.Errorf("%s has no exported methods", typeName)
+	}
+	// The interface lists the methods in declaration order rather than alphabetically
+	sort.SliceStable(selected, func(i, j int) bool {
+		first, second := pkg.Fset.Position(selected[i].Pos()), pkg.Fset.Position(selected[j].Pos())
+		if first.Filename != second.Filename {
+			return first.Filename < second.Filename
+		}
+		return first.Offset < second.Offset
+	})
+
+	files := map[string]*moveFile{}
+	fileOf := func(path string) (*moveFile, error) {
+		if file, ok := files[path]; ok {
+			return file, nil
+		}
+		file, err := parseMoveFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files[path] = file
+		return file, nil
+	}
+	declFile, err := fileOf(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	var qualifyErr error
+	qualify := func(other *types.Package) string {
+		if other == pkg.Types {
+			return ""
+		}
+		name, err := declFile.qualifierOf(other.Path(), other.Name())
+		if err != nil && qualifyErr == nil {
+			qualifyErr = err
+		}
+		return name
+	}
+	var decl bytes.Buffer
+	implementer := typeName
+	if pointerReceiver {
+		implementer = "*" + typeName
+	}
+	fmt.Fprintf(&decl, "\n\n// %s is implemented by %s\ntype %s interface {", interfaceName, implementer, interfaceName)
+	docs := methodDocs(pkg.Syntax, typeName)
+	for _, method := range selected {
+		if doc := docs[method.Name()]; doc != "" {
+			for _, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+				fmt.Fprintf(&decl, "\n\t// %s", line)
+			}
+		}
+		fmt.Fprintf(&decl, "\n\t%s", method.Name())
+		types.WriteSignature(&decl, method.Type().(*types.Signature), qualify)
+	}
+	decl.WriteString("\n}")
+	if qualifyErr != nil {
+		return "", qualifyErr
+	}
+	insertAt := declFile.offset(typeDeclEnd(declFile.ast, typeName))
+	declFile.edits = append(declFile.edits, textEdit{start: insertAt, end: insertAt, replacement: decl.String()})
+
+	for _, site := range callSites {
+		path, lineNumber, _ := parseInspectPath(site)
+		if lineNumber <= 0 || !filepath.IsAbs(path) {
+			return "", fmt.Errorf("call site %s must be an absolute /path/to/file.go:line", site)
+		}
+		file, err := fileOf(path)
+		if err != nil {
+			return "", err
+		}
+		if err := rewriteTypeUses(file, lineNumber, pkg.PkgPath, filepath.Dir(path) == filepath.Dir(filePath), typeName, interfaceName, pkg.Name); err != nil {
+			return "", fmt.Errorf("call site %s: %w", site, err)
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := files[path].write(); err != nil {
+			return "", err
+		}
+	}
+	var names []string
+	for _, method := range selected {
+		names = append(names, method.Name())
+	}
+	result := fmt.Sprintf("Declared %s with the methods %s of %s", interfaceName, strings.Join(names, ", "), typeName)
+	if len(callSites) > 0 {
+		result += fmt.Sprintf(" and rewrote %d call sites", len(callSites))
+	}
+	return result, nil
+}
+
+// methodDocs returns the doc comments of the methods of the type by method name
+func methodDocs(files []*ast.File, typeName string) map[string]string {
+	docs := make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv != nil && fn.Doc != nil && receiverTypeName(fn.Recv.List[0].Type) == typeName {
+				docs[fn.Name.Name] = fn.Doc.Text()
+			}
+		}
+	}
+	return docs
+}
+
+// typeDeclEnd returns the end of the declaration of the type in the file
+func typeDeclEnd(file *ast.File, typeName string) token.Pos {
+	for _, decl := range file.Decls {
+		if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.TYPE {
+			for _, spec := range decl.Specs {
+				if spec.(*ast.TypeSpec).Name.Name == typeName {
+					return decl.End()
+				}
+			}
+		}
+	}
+	return file.End()
+}
+
+// rewriteTypeUses replaces the uses of the type as the type of a parameter, result, field or
+// variable on the line of the file by the interface, qualified when the file is in another
+// package than the type
+func rewriteTypeUses(
+	file *moveFile,
+	lineNumber int,
+	importPath string,
+	samePackage bool,
+	typeName string,
+	interfaceName string,
+	packageName string,
+) error {
+	qualifier := ""
+	if !samePackage {
+		var err error
+		if qualifier, err = file.qualifierOf(importPath, packageName); err != nil {
+			return err
+		}
+	}
+
+	isConcrete := func(expr ast.Expr) bool {
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		switch expr := expr.(type) {
+		case *ast.Ident:
+			return qualifier == "" && expr.Name == typeName
+		case *ast.SelectorExpr:
+			x, ok := expr.X.(*ast.Ident)
+			return ok && qualifier != "" && x.Name == qualifier && expr.Sel.Name == typeName
+		}
+		return false
+	}
+	replacement := interfaceName
+	if qualifier != "" {
+		replacement = qualifier + "." + interfaceName
+	}
+
+	rewritten := 0
+	ast.Inspect(file.ast, func(node ast.Node) bool {
+		var typeExpr ast.Expr
+		switch node := node.(type) {
+		case *ast.Field:
+			typeExpr = node.Type
+		case *ast.ValueSpec:
+			typeExpr = node.Type
+		}
+		if typeExpr != nil && file.fset.Position(typeExpr.Pos()).Line == lineNumber && isConcrete(typeExpr) {
+			file.edits = append(file.edits, textEdit{
+				start:       file.offset(typeExpr.Pos()),
+				end:         file.offset(typeExpr.End()),
+				replacement: replacement,
+			})
+			rewritten++
+		}
+		return true
+	})
+	if rewritten == 0 {
+		return fmt.Errorf("no use of %s as a type on line %d of %s", typeName, lineNumber, file.path)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestExtractInterface(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a concrete store used by a service
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"store/store.go": {
+				"package store",              // 1
+				"",                           // 2
+				"import \"time\"",            // 3
+				"",                           // 4
+				"// Memory stores values",    // 5
+				"type Memory struct {",       // 6
+				"\tvalues map[string]string", // 7
+				"}",                          // 8
+				"",                           // 9
+				"// Get returns the value",   // 10
+				"// of the key",              // 11
+				"func (m *Memory) Get(key string) (string, bool) {", // 12
+				"\tvalue, ok := m.values[key]",                      // 13
+				"\treturn value, ok",                                // 14
+				"}",                                                 // 15
+				"",                                                  // 16
+				"func (m *Memory) Expire(ttl time.Duration) {}", // 17
+				"",                            // 18
+				"func (m Memory) Len() int {", // 19
+				"\treturn len(m.values)",      // 20
+				"}",                           // 21
+				"",                            // 22
+				"func (m *Memory) reset() {}", // 23
+				"",                            // 24
+				"type Reader struct{}",        // 25
+				"",                            // 26
+			},
+			"service/service.go": {
+				"package service",                      // 1
+				"",                                     // 2
+				"import \"example.com/app/store\"",     // 3
+				"",                                     // 4
+				"type Service struct {",                // 5
+				"\tstore *store.Memory",                // 6
+				"}",                                    // 7
+				"",                                     // 8
+				"func New(s *store.Memory) *Service {", // 9
+				"\treturn &Service{store: s}",          // 10
+				"}",                                    // 11
+				"",                                     // 12
+			},
+		})
+	}
+
+	readFile := func(t testing.TB, path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	t.Run("all exported methods", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "store", "store.go")
+
+		result, err := ExtractInterface(context.Background(), file, "Memory", "Store", nil, nil)
+		if err != nil {
+			t.Fatalf("Failed to extract interface: %v", err)
+		}
+		if result != "Declared Store with the methods Get, Expire, Len of Memory" {
+			t.Errorf("Expected the methods in declaration order, got: %s", result)
+		}
+		expected := strings.Join([]string{
+			"type Memory struct {",
+			"\tvalues map[string]string",
+			"}",
+			"",
+			"// Store is implemented by *Memory",
+			"type Store interface {",
+			"\t// Get returns the value",
+			"\t// of the key",
+			"\tGet(key string) (string, bool)",
+			"\tExpire(ttl time.Duration)",
+			"\tLen() int",
+			"}",
+			"",
+			"// Get returns the value",
+		}, "\n")
+		if content := readFile(t, file); !strings.Contains(content, expected) {
+			t.Errorf("Expected the interface after the type:\n%s\ngot:\n%s", expected, content)
+		}
+	})
+
+	t.Run("subset and call sites", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "store", "store.go")
+		service := filepath.Join(workspace, "service", "service.go")
+
+		_, err := ExtractInterface(context.Background(), file, "Memory", "Getter", []string{"Get"}, []string{service + ":6", service + ":9"})
+		if err != nil {
+			t.Fatalf("Failed to extract interface: %v", err)
+		}
+		if content := readFile(t, file); !strings.Contains(content, "type Getter interface {\n\t// Get returns the value\n\t// of the key\n\tGet(key string) (string, bool)\n}") {
+			t.Errorf("Expected only Get in the interface, got:\n%s", content)
+		}
+		content := readFile(t, service)
+		for _, expected := range []string{"\tstore store.Getter\n", "func New(s store.Getter) *Service {"} {
+			if !strings.Contains(content, expected) {
+				t.Errorf("Expected %q in the call sites, got:\n%s", expected, content)
+			}
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "store", "store.go")
+		service := filepath.Join(workspace, "service", "service.go")
+		before := readFile(t, file)
+
+		for _, test := range []struct {
+			interfaceName string
+			methods       []string
+			callSites     []string
+			expected      string
+		}{
+			{"Reader", nil, nil, "Reader is already declared in package example.com/app/store"},
+			{"Store", []string{"reset"}, nil, "Memory has no exported method 'reset'"},
+			{"Store", nil, []string{service + ":10"}, "no use of Memory as a type on line 10"},
+		} {
+			_, err := ExtractInterface(context.Background(), file, "Memory", test.interfaceName, test.methods, test.callSites)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected %q, got: %v", test.expected, err)
+			}
+		}
+		if readFile(t, file) != before {
+			t.Errorf("Expected refused extractions to leave the file untouched")
+		}
+	})
+
+	t.Run("extract interface tool patch", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		service := filepath.Join(workspace, "service", "service.go")
+		before := readFile(t, service)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name": extractInterfaceToolName,
+				"arguments": map[string]any{
+					"file_path":      filepath.Join(workspace, "store", "store.go"),
+					"type":           "Memory",
+					"interface_name": "Store",
+					"call_sites":     []string{service + ":9"},
+					"output":         "patch",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		for _, expected := range []string{"+++ b/store/store.go", "+type Store interface {", "+func New(s store.Store) *Service {"} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the patch, got: %s", expected, text)
+			}
+		}
+		if readFile(t, service) != before {
+			t.Errorf("Expected the patch output to leave the files untouched")
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package go_mcp_tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileCache(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create Go files of 20 bytes each
+	createTestFiles := func(t testing.TB, count int) []string {
+		tempDir := t.TempDir()
+		var paths []string
+		for i := range count {
+			path := filepath.Join(tempDir, fmt.Sprintf("file%d.go", i))
+			if err := os.WriteFile(path, []byte("package testpkg\n\n//\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			paths = append(paths, path)
+		}
+		return paths
+	}
+
+	t.Run("evicts least recently used files", func(t *testing.T) {
+		t.Parallel()
+		paths := createTestFiles(t, 3)
+		cache := newFileCache(FileCacheLimits{MaxFiles: 2})
+
+		for _, path := range paths[:2] {
+			if _, err := cache.GetOrParseFile(path, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Using the first file makes the second one the least recently used
+		if _, err := cache.GetOrParseFile(paths[0], nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cache.GetOrParseFile(paths[2], nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := cache.files[paths[1]]; ok {
+			t.Errorf("Expected the least recently used file to be evicted")
+		}
+		if _, ok := cache.files[paths[0]]; !ok {
+			t.Errorf("Expected the recently used file to be kept")
+		}
+		metrics := cache.Metrics()
+		expected := FileCacheMetrics{Files: 2, Bytes: 40, Hits: 1, Misses: 3, Evictions: 1}
+		if metrics != expected {
+			t.Errorf("Expected metrics %+v, got %+v", expected, metrics)
+		}
+	})
+
+	t.Run("byte limit", func(t *testing.T) {
+		t.Parallel()
+		paths := createTestFiles(t, 3)
+		cache := newFileCache(FileCacheLimits{MaxBytes: 50})
+
+		for _, path := range paths {
+			if _, err := cache.GetOrParseFile(path, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if metrics := cache.Metrics(); metrics.Files != 2 || metrics.Bytes != 40 || metrics.Evictions != 1 {
+			t.Errorf("Expected the cache to stay within 50 bytes, got %+v", metrics)
+		}
+
+		// Lowering the limits evicts immediately, but keeps the most recently used file
+		cache.SetLimits(FileCacheLimits{MaxBytes: 1})
+		if _, ok := cache.files[paths[2]]; !ok || len(cache.files) != 1 {
+			t.Errorf("Expected only the most recently used file to be kept, got %d files", len(cache.files))
+		}
+	})
+
+	t.Run("symlinked paths share an entry", func(t *testing.T) {
+		t.Parallel()
+		paths := createTestFiles(t, 1)
+		link := filepath.Join(t.TempDir(), "link")
+		if err := os.Symlink(filepath.Dir(paths[0]), link); err != nil {
+			t.Fatal(err)
+		}
+		linkedPath := filepath.Join(link, filepath.Base(paths[0]))
+		cache := newFileCache(FileCacheLimits{})
+
+		first, err := cache.GetOrParseFile(linkedPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := cache.GetOrParseFile(paths[0], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != second {
+			t.Errorf("Expected both paths to share the cached file")
+		}
+		if filename := first.fset.Position(first.ast.Pos()).Filename; filename != paths[0] {
+			t.Errorf("Expected positions to use the resolved path %s, got %s", paths[0], filename)
+		}
+
+		cache.RemoveFile(linkedPath)
+		if metrics := cache.Metrics(); metrics.Files != 0 {
+			t.Errorf("Expected removing the symlinked path to remove the file, got %+v", metrics)
+		}
+	})
+
+	t.Run("source lines", func(t *testing.T) {
+		t.Parallel()
+		// Windows line endings, a line longer than the buffer of a line scanner and no final newline
+		longLine := "var s = \"" + strings.Repeat("x", 100000) + "\""
+		source := "package testpkg\r\n\r\n" + longLine + "\r\n\r\nfunc F() {\n\treturn\n}"
+		path := filepath.Join(t.TempDir(), "source.go")
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cache := newFileCache(FileCacheLimits{})
+		cached, err := cache.GetOrParseFile(path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, test := range []struct {
+			start, end int
+			expected   string
+		}{
+			{1, 1, "package testpkg"},
+			{3, 3, longLine},
+			{5, 7, "func F() {\n\treturn\n}"},
+			{1, 2, "package testpkg\n"},
+			{6, 100, "\treturn\n}"},
+			{0, 1, "package testpkg"},
+			{8, 9, ""},
+			{3, 2, ""},
+		} {
+			if lines := cached.sourceLines(test.start, test.end); lines != test.expected {
+				t.Errorf("Expected lines %d-%d to be %.40q, got %.40q", test.start, test.end, test.expected, lines)
+			}
+		}
+
+		// Snippets are sliced from the cached source instead of reading the file again
+		if _, err := cache.GetOrParseFile(path, nil); err != nil {
+			t.Fatal(err)
+		}
+		if metrics := cache.Metrics(); metrics.Misses != 1 || metrics.Hits != 1 {
+			t.Errorf("Expected the file to be read once, got %+v", metrics)
+		}
+	})
+
+	t.Run("removing files", func(t *testing.T) {
+		t.Parallel()
+		paths := createTestFiles(t, 2)
+		cache := newFileCache(FileCacheLimits{})
+
+		for _, path := range paths {
+			if _, err := cache.GetOrParseFile(path, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cache.RemoveFile(paths[0])
+		if metrics := cache.Metrics(); metrics.Files != 1 || metrics.Bytes != 20 || metrics.Evictions != 0 {
+			t.Errorf("Expected one file left without evictions, got %+v", metrics)
+		}
+		cache.ClearCache()
+		if metrics := cache.Metrics(); metrics.Files != 0 || metrics.Bytes != 0 || cache.lru.Len() != 0 {
+			t.Errorf("Expected an empty cache, got %+v", metrics)
+		}
+	})
+}
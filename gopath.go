@@ -0,0 +1,78 @@
+package go_mcp_tools
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+)
+
+// gopathProject is a legacy project without go.mod in the src directory of a GOPATH
+// entry, whose packages are resolved by their directory below src
+type gopathProject struct {
+	// root is the version control root of the project, or the directory it was found
+	// from when it is not under version control
+	root string
+	// importPath is the import path of root
+	importPath string
+}
+
+// gopathEntries returns the directories of GOPATH, or the default GOPATH when unset
+func gopathEntries() []string {
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		gopath = build.Default.GOPATH
+	}
+	return filepath.SplitList(gopath)
+}
+
+// findGOPATHProject returns the GOPATH project containing dir, when dir is not in a
+// module but below the src directory of a GOPATH entry
+func findGOPATHProject(dir string) (*gopathProject, bool) {
+	if _, err := findModuleRoot(dir); err == nil {
+		return nil, false
+	}
+	resolved := resolveSymlinks(dir)
+	for _, entry := range gopathEntries() {
+		if entry == "" {
+			continue
+		}
+		src := resolveSymlinks(filepath.Join(entry, "src"))
+		rel, ok := workspaceRelPath(resolved, src)
+		if !ok || rel == "." {
+			continue
+		}
+		project := &gopathProject{root: resolved}
+		// The closest version control root is the project, packages of other projects
+		// in the same GOPATH are its dependencies
+		for current := resolved; current != src; current = filepath.Dir(current) {
+			if isVersionControlRoot(current) {
+				project.root = current
+				break
+			}
+		}
+		rel, _ = workspaceRelPath(project.root, src)
+		project.importPath = filepath.ToSlash(rel)
+		return project, true
+	}
+	return nil, false
+}
+
+// isVersionControlRoot reports whether dir is the root of a repository
+func isVersionControlRoot(dir string) bool {
+	for _, name := range []string{".git", ".hg", ".svn", ".bzr"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// packagesEnv returns the variables added to the environment the go command resolves
+// the packages of dir with by loadPackages. GOPATH projects are loaded with modules
+// disabled, as the go command otherwise fails to find a main module for them.
+func packagesEnv(dir string) []string {
+	if _, ok := findGOPATHProject(dir); !ok {
+		return nil
+	}
+	return []string{"GO111MODULE=off"}
+}
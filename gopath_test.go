@@ -0,0 +1,82 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGOPATH is not parallel, as it points GOPATH of the process to a temporary directory
+func TestGOPATH(t *testing.T) {
+	gopath := t.TempDir()
+	t.Setenv("GOPATH", gopath)
+	project := filepath.Join(gopath, "src", "example.com", "legacy")
+	files := map[string][]string{
+		"main.go": {
+			"package main",
+			"",
+			"import \"example.com/legacy/util\"",
+			"",
+			"func main() { util.Hello() }",
+			"",
+		},
+		"util/util.go": {
+			"package util",
+			"",
+			"// Hello greets",
+			"func Hello() string { return \"hi\" }",
+			"",
+		},
+	}
+	writeTestFiles(t, project, files)
+	if err := os.Mkdir(filepath.Join(project, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("project detection", func(t *testing.T) {
+		found, ok := findGOPATHProject(filepath.Join(project, "util"))
+		if !ok {
+			t.Fatal("Expected the GOPATH project to be found")
+		}
+		if found.root != resolveSymlinks(project) || found.importPath != "example.com/legacy" {
+			t.Errorf("Expected project example.com/legacy at %s, got %+v", project, found)
+		}
+		if _, ok := findGOPATHProject(t.TempDir()); ok {
+			t.Error("Expected no project outside of GOPATH")
+		}
+		if packagesEnv(project) == nil || packagesEnv(t.TempDir()) != nil {
+			t.Error("Expected modules to be disabled only for the GOPATH project")
+		}
+	})
+
+	t.Run("module sources", func(t *testing.T) {
+		module, err := loadModuleSources(filepath.Join(project, "util"), false)
+		if err != nil {
+			t.Fatalf("Failed to read project: %v", err)
+		}
+		var importPaths []string
+		for _, pkg := range module.packages {
+			importPaths = append(importPaths, pkg.importPath)
+		}
+		if module.path != "example.com/legacy" || strings.Join(importPaths, ",") != "example.com/legacy,example.com/legacy/util" {
+			t.Errorf("Expected the packages of example.com/legacy, got %s with %v", module.path, importPaths)
+		}
+
+		_, err = loadModuleSources(t.TempDir(), false)
+		if err == nil || !strings.Contains(err.Error(), "GOPATH") {
+			t.Errorf("Expected an error mentioning GOPATH outside of modules, got %v", err)
+		}
+	})
+
+	t.Run("packages are loaded", func(t *testing.T) {
+		surface, err := ExportedAPI(context.Background(), project, "./...")
+		if err != nil {
+			t.Fatalf("Failed to load packages: %v", err)
+		}
+		if strings.Join(surface.Features, "\n") != "pkg example.com/legacy/util, func Hello() string" {
+			t.Errorf("Expected the API of the util package, got %v", surface.Features)
+		}
+	})
+}
@@ -1,41 +1,143 @@
 package go_mcp_tools
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
+// DefaultGoplsConcurrency is the number of gopls processes run at the same time
+// unless changed with SetGoplsConcurrency
+var DefaultGoplsConcurrency = max(2, runtime.NumCPU()/2)
+
+// goplsLimiter queues gopls invocations beyond the concurrency limit, as every
+// invocation loads the workspace and takes a lot of memory on big repositories
+var goplsLimiter = newSemaphore(DefaultGoplsConcurrency)
+
+// SetGoplsConcurrency limits how many gopls processes run at the same time in the
+// process, further invocations wait for a running one to finish. Zero means unlimited.
+func SetGoplsConcurrency(limit int) {
+	goplsLimiter.setLimit(limit)
+}
+
+// WithGoplsConcurrency sets the gopls concurrency limit when the server is created.
+// The limit is shared by all servers in the process.
+func WithGoplsConcurrency(limit int) Option {
+	return func(o *serverOptions) {
+		o.goplsConcurrency = &limit
+	}
+}
+
+// semaphore limits concurrent work to a limit that can be changed while in use
+type semaphore struct {
+	mu     sync.Mutex
+	limit  int
+	active int
+	// changed is closed and replaced whenever work is released or the limit changes,
+	// waking the waiting work to check the limit again
+	changed chan struct{}
+}
+
+func newSemaphore(limit int) *semaphore {
+	return &semaphore{limit: limit, changed: make(chan struct{})}
+}
+
+// acquire waits until the work can start within the limit, or until ctx is done
+func (s *semaphore) acquire(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		if s.limit <= 0 || s.active < s.limit {
+			s.active++
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release marks work as done, letting waiting work start
+func (s *semaphore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active--
+	s.notifyLocked()
+}
+
+// setLimit changes the limit, work already running is not interrupted
+func (s *semaphore) setLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.notifyLocked()
+}
+
+// notifyLocked wakes the waiting work
+func (s *semaphore) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
 // executeGoplsCommand executes a gopls command with the given arguments
-// Returns the trimmed output string or an error with helpful context
-func executeGoplsCommand(args ...string) (string, error) {
+// Returns the trimmed output string or an error with helpful context.
+// The process is killed when ctx is done, and belongs to the session of ctx.
+func executeGoplsCommand(ctx context.Context, args ...string) (string, error) {
 	if len(args) == 0 {
 		return "", fmt.Errorf("no arguments provided to gopls command")
 	}
 
-	// Create the command
-	cmd := exec.Command("gopls", args...)
-
 	// Set working directory to the directory of the first file argument if it exists
 	// Look for file path in arguments (typically contains .go)
+	dir := ""
 	for _, arg := range args {
 		if strings.Contains(arg, ".go:") {
 			// Extract file path from position string (file:line:column)
 			parts := strings.Split(arg, ":")
 			if len(parts) >= 1 {
-				cmd.Dir = filepath.Dir(parts[0])
+				dir = filepath.Dir(parts[0])
 				break
 			}
 		} else if strings.HasSuffix(arg, ".go") {
-			cmd.Dir = filepath.Dir(arg)
+			dir = filepath.Dir(arg)
 			break
 		}
 	}
+	// Without a file argument gopls runs in the working directory of the server
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
 
-	// Execute the command
-	output, err := cmd.CombinedOutput()
+	var env []string
+	if _, ok := findGOPATHProject(dir); ok {
+		env = []string{"GO111MODULE=off"}
+	}
+
+	// Execute the command, waiting for a slot if too many gopls processes are running
+	if err := goplsLimiter.acquire(ctx); err != nil {
+		return "", fmt.Errorf("waiting for a gopls slot: %w", err)
+	}
+	output, err := commandOutput(ctx, dir, env, "gopls", args...)
+	goplsLimiter.release()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", classifyErrorf(
+			ErrGoplsUnavailable,
+			"gopls is not installed or not in PATH, install it with `go install golang.org/x/tools/gopls@latest`: %w",
+			err,
+		)
+	}
 	if err != nil {
 		// Try to provide a more helpful error message
 		outputStr := strings.TrimSpace(string(output))
@@ -133,7 +235,8 @@ func createGoplsPosition(
 		}
 
 		if symbolIndex == -1 {
-			return 0, fmt.Errorf(
+			return 0, classifyErrorf(
+				ErrSymbolNotFound,
 				"symbol '%s' not found at a word boundary at line %d",
 				symbolName,
 				lineNumber,
@@ -149,11 +252,8 @@ func createGoplsPosition(
 		return "", fmt.Errorf("file does not exist: %s", filePath)
 	}
 
-	// Convert to absolute path
-	absPath, err := filepath.Abs(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path for %s: %w", filePath, err)
-	}
+	// gopls reports locations by their resolved paths, so positions use them as well
+	absPath := resolveSymlinks(filePath)
 
 	// Find the column position of the symbol at the given line
 	columnPos, err := findSymbolColumnPosition(absPath, lineNumber, symbolName)
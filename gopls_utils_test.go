@@ -1,11 +1,15 @@
 package go_mcp_tools
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestCreateGoplsPosition(t *testing.T) {
@@ -276,3 +280,95 @@ func TestCreateGoplsPositionEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestGoplsLimiter(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to run work concurrently and return the most concurrent work seen
+	runConcurrently := func(t testing.TB, s *semaphore, count int) int {
+		var mu sync.Mutex
+		var active, maxActive int
+		var wg sync.WaitGroup
+		for range count {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := s.acquire(context.Background()); err != nil {
+					t.Error(err)
+					return
+				}
+				defer s.release()
+				mu.Lock()
+				active++
+				maxActive = max(maxActive, active)
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return maxActive
+	}
+
+	t.Run("excess work is queued", func(t *testing.T) {
+		t.Parallel()
+		if maxActive := runConcurrently(t, newSemaphore(2), 8); maxActive != 2 {
+			t.Errorf("Expected at most 2 concurrent invocations, got %d", maxActive)
+		}
+	})
+
+	t.Run("zero is unlimited", func(t *testing.T) {
+		t.Parallel()
+		if maxActive := runConcurrently(t, newSemaphore(0), 4); maxActive != 4 {
+			t.Errorf("Expected 4 concurrent invocations, got %d", maxActive)
+		}
+	})
+
+	t.Run("raising the limit releases waiting work", func(t *testing.T) {
+		t.Parallel()
+		s := newSemaphore(1)
+		_ = s.acquire(context.Background())
+		done := make(chan struct{})
+		go func() {
+			_ = s.acquire(context.Background())
+			close(done)
+		}()
+		select {
+		case <-done:
+			t.Fatal("Expected the second invocation to wait")
+		case <-time.After(20 * time.Millisecond):
+		}
+		s.setLimit(2)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the second invocation to start after raising the limit")
+		}
+	})
+
+	t.Run("waiting work can be cancelled", func(t *testing.T) {
+		t.Parallel()
+		s := newSemaphore(1)
+		_ = s.acquire(context.Background())
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+		go func() {
+			errs <- s.acquire(ctx)
+		}()
+		cancel()
+		select {
+		case err := <-errs:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected the cancellation error, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected the waiting invocation to return when cancelled")
+		}
+		s.release()
+		if err := s.acquire(context.Background()); err != nil {
+			t.Errorf("Expected the cancelled invocation to not hold a slot, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,517 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// grpcServiceName is the fully qualified name of the Tools service in proto/tools.proto
+const grpcServiceName = "gomcptools.v1.Tools"
+
+// grpcMaxMessageSize is the largest request message accepted (matches the grpc-go default)
+const grpcMaxMessageSize = 4 << 20
+
+// gRPC status codes used by the server, see https://grpc.io/docs/guides/status-codes/
+const (
+	grpcCodeOK              = 0
+	grpcCodeUnknown         = 2
+	grpcCodeInvalidArgument = 3
+	grpcCodeUnimplemented   = 12
+	grpcCodeInternal        = 13
+)
+
+// InspectRequest is the Go representation of the InspectRequest message in proto/tools.proto
+type InspectRequest struct {
+	Path           string
+	LineNumber     int32
+	SymbolName     string
+	IncludePrivate bool
+	WorkspaceDir   string
+}
+
+// RenameRequest is the Go representation of the RenameRequest message in proto/tools.proto
+type RenameRequest struct {
+	FilePath   string
+	LineNumber int32
+	OldName    string
+	NewName    string
+}
+
+// SortRequest is the Go representation of the SortRequest message in proto/tools.proto.
+// Nil toggles are treated as true.
+type SortRequest struct {
+	FilePath    string
+	SymbolName  string
+	SwitchCases *bool
+	MapKeys     *bool
+	Imports     *bool
+}
+
+// DiagnosticsRequest is the Go representation of the DiagnosticsRequest message in
+// proto/tools.proto
+type DiagnosticsRequest struct {
+	Path         string
+	Analyzers    []string
+	IncludeTests bool
+}
+
+// ToolResponse is the Go representation of the ToolResponse message in proto/tools.proto
+type ToolResponse struct {
+	Text string
+}
+
+// MarshalProto encodes the request in protobuf wire format
+func (m *InspectRequest) MarshalProto() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, m.Path)
+	b = appendProtoVarint(b, 2, uint64(int64(m.LineNumber)))
+	b = appendProtoString(b, 3, m.SymbolName)
+	b = appendProtoBool(b, 4, m.IncludePrivate)
+	b = appendProtoString(b, 5, m.WorkspaceDir)
+	return b
+}
+
+// UnmarshalProto decodes the request from protobuf wire format
+func (m *InspectRequest) UnmarshalProto(data []byte) error {
+	fields, err := decodeProtoFields(data)
+	if err != nil {
+		return err
+	}
+	m.Path = fields.string(1)
+	m.LineNumber = fields.int32(2)
+	m.SymbolName = fields.string(3)
+	m.IncludePrivate = fields.bool(4)
+	m.WorkspaceDir = fields.string(5)
+	return nil
+}
+
+// MarshalProto encodes the request in protobuf wire format
+func (m *RenameRequest) MarshalProto() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, m.FilePath)
+	b = appendProtoVarint(b, 2, uint64(int64(m.LineNumber)))
+	b = appendProtoString(b, 3, m.OldName)
+	b = appendProtoString(b, 4, m.NewName)
+	return b
+}
+
+// UnmarshalProto decodes the request from protobuf wire format
+func (m *RenameRequest) UnmarshalProto(data []byte) error {
+	fields, err := decodeProtoFields(data)
+	if err != nil {
+		return err
+	}
+	m.FilePath = fields.string(1)
+	m.LineNumber = fields.int32(2)
+	m.OldName = fields.string(3)
+	m.NewName = fields.string(4)
+	return nil
+}
+
+// MarshalProto encodes the request in protobuf wire format
+func (m *SortRequest) MarshalProto() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, m.FilePath)
+	b = appendProtoString(b, 2, m.SymbolName)
+	b = appendProtoOptionalBool(b, 3, m.SwitchCases)
+	b = appendProtoOptionalBool(b, 4, m.MapKeys)
+	b = appendProtoOptionalBool(b, 5, m.Imports)
+	return b
+}
+
+// UnmarshalProto decodes the request from protobuf wire format
+func (m *SortRequest) UnmarshalProto(data []byte) error {
+	fields, err := decodeProtoFields(data)
+	if err != nil {
+		return err
+	}
+	m.FilePath = fields.string(1)
+	m.SymbolName = fields.string(2)
+	m.SwitchCases = fields.optionalBool(3)
+	m.MapKeys = fields.optionalBool(4)
+	m.Imports = fields.optionalBool(5)
+	return nil
+}
+
+// MarshalProto encodes the request in protobuf wire format
+func (m *DiagnosticsRequest) MarshalProto() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, m.Path)
+	for _, analyzer := range m.Analyzers {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, analyzer)
+	}
+	b = appendProtoBool(b, 3, m.IncludeTests)
+	return b
+}
+
+// UnmarshalProto decodes the request from protobuf wire format
+func (m *DiagnosticsRequest) UnmarshalProto(data []byte) error {
+	fields, err := decodeProtoFields(data)
+	if err != nil {
+		return err
+	}
+	m.Path = fields.string(1)
+	m.Analyzers = fields.strings(2)
+	m.IncludeTests = fields.bool(3)
+	return nil
+}
+
+// MarshalProto encodes the response in protobuf wire format
+func (m *ToolResponse) MarshalProto() []byte {
+	return appendProtoString(nil, 1, m.Text)
+}
+
+// UnmarshalProto decodes the response from protobuf wire format
+func (m *ToolResponse) UnmarshalProto(data []byte) error {
+	fields, err := decodeProtoFields(data)
+	if err != nil {
+		return err
+	}
+	m.Text = fields.string(1)
+	return nil
+}
+
+// appendProtoString appends a string field, omitting the proto3 default value
+func appendProtoString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// appendProtoVarint appends a varint field, omitting the proto3 default value
+func appendProtoVarint(b []byte, num protowire.Number, value uint64) []byte {
+	if value == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// appendProtoBool appends a bool field, omitting the proto3 default value
+func appendProtoBool(b []byte, num protowire.Number, value bool) []byte {
+	return appendProtoVarint(b, num, protowire.EncodeBool(value))
+}
+
+// appendProtoOptionalBool appends an optional bool field whenever it is set, even when false
+func appendProtoOptionalBool(b []byte, num protowire.Number, value *bool) []byte {
+	if value == nil {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, protowire.EncodeBool(*value))
+}
+
+// protoFields holds the values of each scalar field of a decoded message in the order
+// they were seen. Strings are stored as []byte and varints as uint64.
+type protoFields map[protowire.Number][]any
+
+// decodeProtoFields decodes the scalar fields of a protobuf message, skipping unknown field types
+func decodeProtoFields(data []byte) (protoFields, error) {
+	fields := make(protoFields)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid protobuf tag: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			value, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid varint for field %d: %w", num, protowire.ParseError(n))
+			}
+			fields[num] = append(fields[num], value)
+			data = data[n:]
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid bytes for field %d: %w", num, protowire.ParseError(n))
+			}
+			fields[num] = append(fields[num], value)
+			data = data[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid value for field %d: %w", num, protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+	}
+	return fields, nil
+}
+
+// last returns the last value of a field, which wins for scalar fields
+func (f protoFields) last(num protowire.Number) any {
+	values := f[num]
+	if len(values) == 0 {
+		return nil
+	}
+	return values[len(values)-1]
+}
+
+func (f protoFields) string(num protowire.Number) string {
+	value, _ := f.last(num).([]byte)
+	return string(value)
+}
+
+func (f protoFields) strings(num protowire.Number) []string {
+	var values []string
+	for _, value := range f[num] {
+		if value, ok := value.([]byte); ok {
+			values = append(values, string(value))
+		}
+	}
+	return values
+}
+
+func (f protoFields) int32(num protowire.Number) int32 {
+	value, _ := f.last(num).(uint64)
+	return int32(value)
+}
+
+func (f protoFields) bool(num protowire.Number) bool {
+	value, _ := f.last(num).(uint64)
+	return protowire.DecodeBool(value)
+}
+
+func (f protoFields) optionalBool(num protowire.Number) *bool {
+	if _, ok := f.last(num).(uint64); !ok {
+		return nil
+	}
+	value := f.bool(num)
+	return &value
+}
+
+// grpcStatusError is an error carrying a gRPC status code
+type grpcStatusError struct {
+	code    int
+	message string
+}
+
+func (e *grpcStatusError) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", e.code, e.message)
+}
+
+// grpcMethod handles a single unary RPC given the raw request message
+type grpcMethod func(ctx context.Context, request []byte) (*ToolResponse, error)
+
+// GRPCServer exposes the tool API as the gRPC service defined in proto/tools.proto.
+// It is an http.Handler and must be served over HTTP/2, see ServeGRPC.
+type GRPCServer struct {
+	mcpServer *server.MCPServer
+	methods   map[string]grpcMethod
+}
+
+// NewGRPCServer creates a gRPC server whose RPCs call the tools of mcpServer. Calls go
+// through the regular MCP request handling, so the middleware and hooks of the server
+// options (workspace allowlist, timeouts, quotas, disabled tools, ...) apply to them.
+func NewGRPCServer(mcpServer *server.MCPServer) *GRPCServer {
+	s := &GRPCServer{mcpServer: mcpServer, methods: make(map[string]grpcMethod)}
+
+	s.methods["Inspect"] = func(ctx context.Context, data []byte) (*ToolResponse, error) {
+		var request InspectRequest
+		if err := request.UnmarshalProto(data); err != nil {
+			return nil, &grpcStatusError{grpcCodeInvalidArgument, err.Error()}
+		}
+		// The inspect tool takes the line number and symbol name as part of the path
+		path := request.Path
+		if request.LineNumber > 0 {
+			path += ":" + strconv.Itoa(int(request.LineNumber))
+		}
+		if request.SymbolName != "" {
+			path += ":" + request.SymbolName
+		}
+		return s.callTool(ctx, inspectToolName, map[string]any{
+			"path":          path,
+			"workspace_dir": request.WorkspaceDir,
+			"only_exported": !request.IncludePrivate,
+		})
+	}
+
+	s.methods["Rename"] = func(ctx context.Context, data []byte) (*ToolResponse, error) {
+		var request RenameRequest
+		if err := request.UnmarshalProto(data); err != nil {
+			return nil, &grpcStatusError{grpcCodeInvalidArgument, err.Error()}
+		}
+		return s.callTool(ctx, renameToolName, map[string]any{
+			"file_path":   request.FilePath,
+			"line_number": request.LineNumber,
+			"old_name":    request.OldName,
+			"new_name":    request.NewName,
+		})
+	}
+
+	s.methods["Sort"] = func(ctx context.Context, data []byte) (*ToolResponse, error) {
+		var request SortRequest
+		if err := request.UnmarshalProto(data); err != nil {
+			return nil, &grpcStatusError{grpcCodeInvalidArgument, err.Error()}
+		}
+		arguments := map[string]any{"file_path": request.FilePath}
+		if request.SymbolName != "" {
+			arguments["symbol_name"] = request.SymbolName
+		}
+		for name, value := range map[string]*bool{
+			"switch_cases": request.SwitchCases,
+			"map_keys":     request.MapKeys,
+			"imports":      request.Imports,
+		} {
+			if value != nil {
+				arguments[name] = *value
+			}
+		}
+		return s.callTool(ctx, sortToolName, arguments)
+	}
+
+	s.methods["Diagnostics"] = func(ctx context.Context, data []byte) (*ToolResponse, error) {
+		var request DiagnosticsRequest
+		if err := request.UnmarshalProto(data); err != nil {
+			return nil, &grpcStatusError{grpcCodeInvalidArgument, err.Error()}
+		}
+		arguments := map[string]any{
+			"path":          request.Path,
+			"include_tests": request.IncludeTests,
+		}
+		if len(request.Analyzers) > 0 {
+			arguments["analyzers"] = request.Analyzers
+		}
+		return s.callTool(ctx, analyzeToolName, arguments)
+	}
+
+	return s
+}
+
+// callTool calls a tool of the MCP server and converts its result to a response, or to
+// a status error when the call or the tool failed
+func (s *GRPCServer) callTool(ctx context.Context, name string, arguments map[string]any) (*ToolResponse, error) {
+	message, err := toolCallMessage(name, arguments)
+	if err != nil {
+		return nil, &grpcStatusError{grpcCodeInternal, err.Error()}
+	}
+	switch result := s.mcpServer.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCResponse:
+		callResult, ok := result.Result.(mcp.CallToolResult)
+		if !ok {
+			return nil, &grpcStatusError{grpcCodeInternal, fmt.Sprintf("unexpected tool result type %T", result.Result)}
+		}
+		if callResult.IsError {
+			return nil, &grpcStatusError{grpcCodeUnknown, toolResultText(&callResult)}
+		}
+		return &ToolResponse{Text: toolResultText(&callResult)}, nil
+	case mcp.JSONRPCError:
+		code := grpcCodeUnknown
+		switch {
+		case strings.Contains(result.Error.Message, server.ErrToolNotFound.Error()):
+			// Tools disabled by the server options
+			code = grpcCodeUnimplemented
+		case result.Error.Code == mcp.INVALID_PARAMS:
+			code = grpcCodeInvalidArgument
+		}
+		return nil, &grpcStatusError{code, result.Error.Message}
+	default:
+		return nil, &grpcStatusError{grpcCodeInternal, fmt.Sprintf("unexpected response type %T", result)}
+	}
+}
+
+// ServeHTTP implements the unary gRPC protocol over HTTP/2
+func (s *GRPCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires POST over HTTP/2", http.StatusMethodNotAllowed)
+		return
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/grpc" && contentType != "application/grpc+proto" {
+		http.Error(
+			w,
+			fmt.Sprintf("unsupported content type %q", contentType),
+			http.StatusUnsupportedMediaType,
+		)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	writeStatus := func(code int, message string) {
+		w.Header().Set("Grpc-Status", strconv.Itoa(code))
+		if message != "" {
+			w.Header().Set("Grpc-Message", url.PathEscape(message))
+		}
+	}
+
+	methodName, found := strings.CutPrefix(r.URL.Path, "/"+grpcServiceName+"/")
+	method, exists := s.methods[methodName]
+	if !found || !exists {
+		w.WriteHeader(http.StatusOK)
+		writeStatus(grpcCodeUnimplemented, fmt.Sprintf("unknown method %s", r.URL.Path))
+		return
+	}
+
+	request, err := readGRPCMessage(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusOK)
+		writeStatus(grpcCodeInvalidArgument, err.Error())
+		return
+	}
+
+	response, err := method(r.Context(), request)
+	w.WriteHeader(http.StatusOK)
+	if err != nil {
+		if statusErr, ok := err.(*grpcStatusError); ok {
+			writeStatus(statusErr.code, statusErr.message)
+		} else {
+			writeStatus(grpcCodeUnknown, err.Error())
+		}
+		return
+	}
+
+	if _, err := w.Write(encodeGRPCMessage(response.MarshalProto())); err != nil {
+		writeStatus(grpcCodeInternal, fmt.Sprintf("failed to write response: %v", err))
+		return
+	}
+	writeStatus(grpcCodeOK, "")
+}
+
+// readGRPCMessage reads a single length-prefixed gRPC message
+func readGRPCMessage(body io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		return nil, fmt.Errorf("failed to read gRPC message header: %w", err)
+	}
+	if header[0] != 0 {
+		return nil, fmt.Errorf("compressed gRPC messages are not supported")
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > grpcMaxMessageSize {
+		return nil, fmt.Errorf(
+			"gRPC message of %d bytes exceeds the limit of %d bytes",
+			length,
+			grpcMaxMessageSize,
+		)
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(body, message); err != nil {
+		return nil, fmt.Errorf("failed to read gRPC message: %w", err)
+	}
+	return message, nil
+}
+
+// encodeGRPCMessage prefixes an uncompressed message with the gRPC length header
+func encodeGRPCMessage(message []byte) []byte {
+	framed := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(framed[1:], uint32(len(message)))
+	return append(framed, message...)
+}
@@ -0,0 +1,246 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGRPCServer(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to start the gRPC server of an MCP server with options on a random port
+	startServer := func(t testing.TB, options ...Option) string {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		httpServer := &http.Server{Handler: NewGRPCServer(NewMCPServer(options...)), Protocols: protocols}
+		go func() {
+			_ = httpServer.Serve(listener)
+		}()
+		t.Cleanup(func() {
+			_ = httpServer.Close()
+		})
+		return "http://" + listener.Addr().String()
+	}
+
+	// Helper function to perform a unary gRPC call, returning the response message, status and status message
+	call := func(t testing.TB, baseURL string, method string, request []byte) ([]byte, string, string) {
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+		httpRequest, err := http.NewRequest(
+			http.MethodPost,
+			baseURL+"/"+grpcServiceName+"/"+method,
+			bytes.NewReader(encodeGRPCMessage(request)),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		httpRequest.Header.Set("Content-Type", "application/grpc")
+		httpRequest.Header.Set("TE", "trailers")
+
+		response, err := client.Do(httpRequest)
+		if err != nil {
+			t.Fatalf("gRPC call failed: %v", err)
+		}
+		defer func() {
+			_ = response.Body.Close()
+		}()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var message []byte
+		if len(body) > 0 {
+			message, err = readGRPCMessage(bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("Failed to read response message: %v", err)
+			}
+		}
+		statusMessage, _ := url.PathUnescape(response.Trailer.Get("Grpc-Message"))
+		return message, response.Trailer.Get("Grpc-Status"), statusMessage
+	}
+
+	t.Run("message round trip", func(t *testing.T) {
+		t.Parallel()
+		disabled := false
+		original := SortRequest{
+			FilePath:   "/tmp/main.go",
+			SymbolName: "Handler",
+			MapKeys:    &disabled,
+		}
+
+		var decoded SortRequest
+		if err := decoded.UnmarshalProto(original.MarshalProto()); err != nil {
+			t.Fatalf("Failed to decode sort request: %v", err)
+		}
+		if decoded.FilePath != original.FilePath || decoded.SymbolName != original.SymbolName {
+			t.Errorf("Expected string fields to round trip, got %+v", decoded)
+		}
+		if decoded.SwitchCases != nil || decoded.Imports != nil {
+			t.Error("Expected unset optional fields to stay unset")
+		}
+		if decoded.MapKeys == nil || *decoded.MapKeys {
+			t.Error("Expected map_keys to be explicitly false")
+		}
+
+		diagnostics := DiagnosticsRequest{Path: "/tmp/pkg", Analyzers: []string{"printf", "nilness"}, IncludeTests: true}
+		var decodedDiagnostics DiagnosticsRequest
+		if err := decodedDiagnostics.UnmarshalProto(diagnostics.MarshalProto()); err != nil {
+			t.Fatalf("Failed to decode diagnostics request: %v", err)
+		}
+		if decodedDiagnostics.Path != diagnostics.Path || !decodedDiagnostics.IncludeTests ||
+			strings.Join(decodedDiagnostics.Analyzers, ",") != "printf,nilness" {
+			t.Errorf("Expected %+v, got %+v", diagnostics, decodedDiagnostics)
+		}
+
+		inspect := InspectRequest{Path: "fmt", LineNumber: 42, IncludePrivate: true}
+		var decodedInspect InspectRequest
+		if err := decodedInspect.UnmarshalProto(inspect.MarshalProto()); err != nil {
+			t.Fatalf("Failed to decode inspect request: %v", err)
+		}
+		if decodedInspect != inspect {
+			t.Errorf("Expected %+v, got %+v", inspect, decodedInspect)
+		}
+	})
+
+	t.Run("sort over grpc", func(t *testing.T) {
+		t.Parallel()
+		baseURL := startServer(t)
+
+		lines := []string{
+			"package testpkg", // 1
+			"",                // 2
+			"var M = map[string]int{\"b\": 2, \"a\": 1}", // 3
+			"", // 4
+		}
+		filePath := filepath.Join(t.TempDir(), "main.go")
+		if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		request := SortRequest{FilePath: filePath}
+		message, status, statusMessage := call(t, baseURL, "Sort", request.MarshalProto())
+		if status != "0" {
+			t.Fatalf("Expected OK status, got %s: %s", status, statusMessage)
+		}
+
+		var response ToolResponse
+		if err := response.UnmarshalProto(message); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if !strings.Contains(response.Text, "1 map literal(s)") {
+			t.Errorf("Expected sort summary in response, got: %s", response.Text)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), "{\"a\": 1, \"b\": 2}") {
+			t.Errorf("Expected map literal to be sorted, got:\n%s", content)
+		}
+	})
+
+	t.Run("tool error is reported as status", func(t *testing.T) {
+		t.Parallel()
+		baseURL := startServer(t)
+
+		request := RenameRequest{FilePath: "", LineNumber: 1, OldName: "a", NewName: "b"}
+		_, status, statusMessage := call(t, baseURL, "Rename", request.MarshalProto())
+		if status != "2" {
+			t.Errorf("Expected UNKNOWN status, got %s", status)
+		}
+		if !strings.Contains(statusMessage, "file_path") {
+			t.Errorf("Expected informative status message, got: %s", statusMessage)
+		}
+	})
+
+	t.Run("diagnostics over grpc", func(t *testing.T) {
+		t.Parallel()
+		baseURL := startServer(t)
+
+		workspace := writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.21"},
+			"main.go": {
+				"package main", // 1
+				"",
+				"import \"fmt\"",
+				"",
+				"func main() {", // 5
+				"	fmt.Printf(\"%d\\n\", \"text\")",
+				"}",
+			},
+		})
+
+		request := DiagnosticsRequest{Path: workspace, Analyzers: []string{"printf"}}
+		message, status, statusMessage := call(t, baseURL, "Diagnostics", request.MarshalProto())
+		if status != "0" {
+			t.Fatalf("Expected OK status, got %s: %s", status, statusMessage)
+		}
+		var response ToolResponse
+		if err := response.UnmarshalProto(message); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if !strings.Contains(response.Text, "main.go:6") || !strings.Contains(response.Text, "printf") {
+			t.Errorf("Expected the printf diagnostic in response, got: %s", response.Text)
+		}
+	})
+
+	t.Run("server options apply", func(t *testing.T) {
+		t.Parallel()
+		allowed := t.TempDir()
+		baseURL := startServer(t, WithWorkspaceAllowlist(allowed), WithoutTools(renameToolName))
+
+		filePath := filepath.Join(t.TempDir(), "main.go")
+		if err := os.WriteFile(filePath, []byte("package testpkg\n\nvar M = map[string]int{\"b\": 2, \"a\": 1}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		request := SortRequest{FilePath: filePath}
+		_, status, statusMessage := call(t, baseURL, "Sort", request.MarshalProto())
+		if status == "0" || !strings.Contains(statusMessage, "outside") {
+			t.Errorf("Expected the call outside the allowed workspace to be refused, got %s: %s", status, statusMessage)
+		}
+		if content, err := os.ReadFile(filePath); err != nil || !strings.Contains(string(content), "{\"b\": 2, \"a\": 1}") {
+			t.Errorf("Expected the file to be unchanged, got:\n%s", content)
+		}
+
+		rename := RenameRequest{FilePath: filepath.Join(allowed, "main.go"), LineNumber: 1, OldName: "a", NewName: "b"}
+		_, status, _ = call(t, baseURL, "Rename", rename.MarshalProto())
+		if status != "12" {
+			t.Errorf("Expected UNIMPLEMENTED status for a disabled tool, got %s", status)
+		}
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		t.Parallel()
+		baseURL := startServer(t)
+
+		_, status, _ := call(t, baseURL, "Missing", nil)
+		if status != "12" {
+			t.Errorf("Expected UNIMPLEMENTED status, got %s", status)
+		}
+	})
+
+	t.Run("malformed message", func(t *testing.T) {
+		t.Parallel()
+		baseURL := startServer(t)
+
+		_, status, _ := call(t, baseURL, "Inspect", []byte{0xff})
+		if status != "3" {
+			t.Errorf("Expected INVALID_ARGUMENT status, got %s", status)
+		}
+	})
+}
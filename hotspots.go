@@ -0,0 +1,435 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	hotspotsToolName        = "hotspots"
+	hotspotsToolDescription = `Ranks the top-level symbols of the Go module by how load-bearing they are: incoming references from the rest of the module, churn (commits touching the file in the git history) and size in lines. A good first map of a codebase before inspecting it in detail.
+
+References are counted syntactically by name without type checking, so method references are attributed to all methods of the module with the same name.`
+)
+
+// Hotspot ranking orders
+const (
+	HotspotsByScore      = "score"
+	HotspotsByReferences = "references"
+	HotspotsByChurn      = "churn"
+	HotspotsBySize       = "size"
+)
+
+// hotspotsChurnCommits limits how far back the git history is read for churn
+const hotspotsChurnCommits = 1000
+
+// HotspotOptions configures the hotspots report
+type HotspotOptions struct {
+	// Limit is the number of symbols reported, all when zero
+	Limit int
+	// SortBy is one of HotspotsByScore (default), HotspotsByReferences, HotspotsByChurn and HotspotsBySize
+	SortBy string
+	// IncludeTests counts declarations in and references from test files
+	IncludeTests bool
+}
+
+// Hotspot is a top-level symbol with its usage statistics
+type Hotspot struct {
+	// Name is the symbol name, Receiver.Name for methods
+	Name       string
+	Kind       string
+	ImportPath string
+	FilePath   string
+	Line       int
+	Lines      int
+	References int
+	// Churn is the number of commits that touched the file of the symbol
+	Churn int
+	// Score weighs references, churn and size equally, each scaled to the maximum in the module
+	Score float64
+}
+
+func AddHotspotsTool(mcpServer *server.MCPServer) {
+	handleHotspots := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		options := HotspotOptions{Limit: 20, SortBy: HotspotsByScore}
+		options.Limit = request.GetInt("limit", options.Limit)
+		if sortBy := request.GetString("sort_by", ""); sortBy != "" {
+			options.SortBy = sortBy
+		}
+		options.IncludeTests = request.GetBool("include_tests", false)
+
+		hotspots, err := Hotspots(ctx, workspaceDir, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding hotspots: %v", err)), nil
+		}
+		return mcp.NewToolResultText(formatHotspots(hotspots, options)), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		hotspotsToolName,
+		mcp.WithDescription(hotspotsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory inside the Go module to report on"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Number of symbols to report, 0 for all"),
+			mcp.DefaultNumber(20),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Ranking of the symbols, score combines references, churn and size"),
+			mcp.Enum(HotspotsByScore, HotspotsByReferences, HotspotsByChurn, HotspotsBySize),
+			mcp.DefaultString(HotspotsByScore),
+		),
+		mcp.WithBoolean("include_tests",
+			mcp.Description("Whether to include declarations in and references from test files"),
+			mcp.DefaultBool(false),
+		),
+	), handleHotspots)
+}
+
+// Hotspots ranks the top-level symbols of the module containing workspaceDir by their
+// incoming references, churn and size
+func Hotspots(ctx context.Context, workspaceDir string, options HotspotOptions) ([]Hotspot, error) {
+	less, ok := map[string]func(a, b *Hotspot) bool{
+		"":                   func(a, b *Hotspot) bool { return a.Score > b.Score },
+		HotspotsByScore:      func(a, b *Hotspot) bool { return a.Score > b.Score },
+		HotspotsByReferences: func(a, b *Hotspot) bool { return a.References > b.References },
+		HotspotsByChurn:      func(a, b *Hotspot) bool { return a.Churn > b.Churn },
+		HotspotsBySize:       func(a, b *Hotspot) bool { return a.Lines > b.Lines },
+	}[options.SortBy]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown sort_by %q, use one of %s, %s, %s or %s",
+			options.SortBy,
+			HotspotsByScore,
+			HotspotsByReferences,
+			HotspotsByChurn,
+			HotspotsBySize,
+		)
+	}
+
+	module, err := loadModuleSources(workspaceDir, options.IncludeTests)
+	if err != nil {
+		return nil, err
+	}
+	hotspots := collectHotspots(module)
+	countHotspotReferences(module, hotspots)
+
+	// Churn is optional, modules outside git repositories are ranked without it
+	if churn, err := fileChurn(ctx, module.root); err == nil {
+		for _, hotspot := range hotspots {
+			hotspot.Churn = churn[hotspot.FilePath]
+		}
+	}
+
+	var maxReferences, maxChurn, maxLines int
+	for _, hotspot := range hotspots {
+		maxReferences = max(maxReferences, hotspot.References)
+		maxChurn = max(maxChurn, hotspot.Churn)
+		maxLines = max(maxLines, hotspot.Lines)
+	}
+	scale := func(value int, maximum int) float64 {
+		if maximum == 0 {
+			return 0
+		}
+		return float64(value) / float64(maximum)
+	}
+	for _, hotspot := range hotspots {
+		hotspot.Score = scale(hotspot.References, maxReferences) +
+			scale(hotspot.Churn, maxChurn) +
+			scale(hotspot.Lines, maxLines)
+	}
+
+	slices.SortStableFunc(hotspots, func(a, b *Hotspot) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return strings.Compare(a.ImportPath+"."+a.Name, b.ImportPath+"."+b.Name)
+	})
+	if options.Limit > 0 && len(hotspots) > options.Limit {
+		hotspots = hotspots[:options.Limit]
+	}
+	result := make([]Hotspot, len(hotspots))
+	for i, hotspot := range hotspots {
+		result[i] = *hotspot
+	}
+	return result, nil
+}
+
+// hotspotDeclarations indexes the hotspots of a module for counting references
+type hotspotDeclarations struct {
+	// byPackage maps import paths to names to functions, types, variables and constants
+	byPackage map[string]map[string]*Hotspot
+	// methods maps method names to all methods of the module with the name
+	methods map[string][]*Hotspot
+	// objects are the declarations, so local identifiers shadowing them are not counted
+	objects map[any]bool
+}
+
+// collectHotspots returns a hotspot for each top-level symbol of the module
+func collectHotspots(module *moduleSources) []*Hotspot {
+	var hotspots []*Hotspot
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				hotspots = append(hotspots, declarationHotspots(pkg, file, decl)...)
+			}
+		}
+	}
+	return hotspots
+}
+
+// declarationHotspots returns the hotspots of the symbols declared by a top-level declaration
+func declarationHotspots(pkg *packageSources, file *sourceFile, decl ast.Decl) []*Hotspot {
+	newHotspot := func(name string, kind string, node ast.Node) *Hotspot {
+		start := file.fset.Position(node.Pos())
+		end := file.fset.Position(node.End())
+		return &Hotspot{
+			Name:       name,
+			Kind:       kind,
+			ImportPath: pkg.importPath,
+			FilePath:   file.path,
+			Line:       start.Line,
+			Lines:      end.Line - start.Line + 1,
+		}
+	}
+
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv != nil && len(decl.Recv.List) > 0 {
+			receiver := receiverTypeName(decl.Recv.List[0].Type)
+			return []*Hotspot{newHotspot(receiver+"."+decl.Name.Name, "method", decl)}
+		}
+		if decl.Name.Name == "init" || decl.Name.Name == "main" {
+			// Called by the runtime, never referenced
+			return nil
+		}
+		return []*Hotspot{newHotspot(decl.Name.Name, "func", decl)}
+	case *ast.GenDecl:
+		var hotspots []*Hotspot
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				hotspots = append(hotspots, newHotspot(spec.Name.Name, "type", spec))
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if name.Name == "_" {
+						continue
+					}
+					var node ast.Node = spec
+					if len(decl.Specs) == 1 {
+						// Include the keyword of single line declarations
+						node = decl
+					}
+					hotspots = append(hotspots, newHotspot(name.Name, decl.Tok.String(), node))
+				}
+			}
+		}
+		return hotspots
+	}
+	return nil
+}
+
+// receiverTypeName returns the name of the receiver type without pointer and type parameters
+func receiverTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// countHotspotReferences counts the references to the hotspots from the files of the module
+func countHotspotReferences(module *moduleSources, hotspots []*Hotspot) {
+	declarations := hotspotDeclarations{
+		byPackage: make(map[string]map[string]*Hotspot),
+		methods:   make(map[string][]*Hotspot),
+		objects:   make(map[any]bool),
+	}
+	for _, hotspot := range hotspots {
+		if hotspot.Kind == "method" {
+			name := hotspot.Name[strings.Index(hotspot.Name, ".")+1:]
+			declarations.methods[name] = append(declarations.methods[name], hotspot)
+			continue
+		}
+		if declarations.byPackage[hotspot.ImportPath] == nil {
+			declarations.byPackage[hotspot.ImportPath] = make(map[string]*Hotspot)
+		}
+		declarations.byPackage[hotspot.ImportPath][hotspot.Name] = hotspot
+	}
+
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			// Only resolved objects of top-level declarations refer to package level symbols
+			for _, decl := range file.ast.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					declarations.objects[decl] = true
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						declarations.objects[spec] = true
+					}
+				}
+			}
+		}
+	}
+
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			countFileReferences(module, pkg, file, declarations)
+		}
+	}
+}
+
+// countFileReferences counts the references of a file to the hotspots
+func countFileReferences(
+	module *moduleSources,
+	pkg *packageSources,
+	file *sourceFile,
+	declarations hotspotDeclarations,
+) {
+	imports := module.fileImports(file.ast)
+	ownPackage := declarations.byPackage[pkg.importPath]
+	if strings.HasSuffix(file.ast.Name.Name, "_test") {
+		// External test packages refer to the package under test through its import
+		ownPackage = nil
+	}
+
+	// Declaring identifiers and selected names are not references by themselves
+	skip := make(map[*ast.Ident]bool)
+	for _, decl := range file.ast.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			skip[decl.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					skip[spec.Name] = true
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						skip[name] = true
+					}
+				}
+			}
+		}
+	}
+
+	ast.Inspect(file.ast, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.SelectorExpr:
+			skip[node.Sel] = true
+			if ident, ok := node.X.(*ast.Ident); ok && ident.Obj == nil {
+				if importPath, ok := imports[ident.Name]; ok {
+					skip[ident] = true
+					if hotspot, ok := declarations.byPackage[importPath][node.Sel.Name]; ok {
+						hotspot.References++
+					}
+					return false
+				}
+			}
+			for _, method := range declarations.methods[node.Sel.Name] {
+				method.References++
+			}
+		case *ast.Ident:
+			if skip[node] || ownPackage == nil {
+				return true
+			}
+			if node.Obj != nil && !declarations.objects[node.Obj.Decl] {
+				// Shadowed by a local declaration
+				return true
+			}
+			if hotspot, ok := ownPackage[node.Name]; ok {
+				hotspot.References++
+			}
+		}
+		return true
+	})
+}
+
+// fileChurn counts the commits touching each file below dir, by absolute path
+func fileChurn(ctx context.Context, dir string) (map[string]int, error) {
+	repoRoot, err := gitRepoRoot(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	output, err := runGit(
+		ctx,
+		dir,
+		"log",
+		fmt.Sprintf("--max-count=%d", hotspotsChurnCommits),
+		"--format=",
+		"--name-only",
+		"--no-renames",
+		"--",
+		".",
+	)
+	if err != nil {
+		return nil, err
+	}
+	churn := make(map[string]int)
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		churn[filepath.Join(repoRoot, filepath.FromSlash(line))]++
+	}
+	return churn, nil
+}
+
+// formatHotspots renders the hotspots as a ranked list
+func formatHotspots(hotspots []Hotspot, options HotspotOptions) string {
+	sortBy := options.SortBy
+	if sortBy == "" {
+		sortBy = HotspotsByScore
+	}
+	if len(hotspots) == 0 {
+		return "No top-level symbols found in the module."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Top %d symbols ranked by %s:\n", len(hotspots), sortBy)
+	for i, hotspot := range hotspots {
+		fmt.Fprintf(
+			&b,
+			"%d. %s %s.%s (%s:%d) - %d references, %d commits, %d lines, score %.2f\n",
+			i+1,
+			hotspot.Kind,
+			hotspot.ImportPath,
+			hotspot.Name,
+			hotspot.FilePath,
+			hotspot.Line,
+			hotspot.References,
+			hotspot.Churn,
+			hotspot.Lines,
+			hotspot.Score,
+		)
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHotspots(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a test module with a library and a command using it
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module testmodule", "", "go 1.21", ""},
+			"lib/lib.go": {
+				"package lib",                      // 1
+				"",                                 // 2
+				"type Store struct{}",              // 3
+				"",                                 // 4
+				"func (s *Store) Get() string {",   // 5
+				"    return prefix + \"value\"",    // 6
+				"}",                                // 7
+				"",                                 // 8
+				"const prefix = \"p:\"",            // 9
+				"",                                 // 10
+				"func New() *Store {",              // 11
+				"    return &Store{}",              // 12
+				"}",                                // 13
+				"",                                 // 14
+				"func Unused(prefix string) int {", // 15
+				"    return len(prefix)",           // 16
+				"}",                                // 17
+			},
+			"cmd/main.go": {
+				"package main",                          // 1
+				"",                                      // 2
+				"import \"testmodule/lib\"",             // 3
+				"",                                      // 4
+				"func main() {",                         // 5
+				"    store := lib.New()",                // 6
+				"    println(store.Get(), store.Get())", // 7
+				"    var _ *lib.Store = lib.New()",      // 8
+				"}",                                     // 9
+			},
+			"lib/lib_test.go": {
+				"package lib",                           // 1
+				"",                                      // 2
+				"func helper() *Store { return New() }", // 3
+			},
+		})
+	}
+
+	// Helper function to find a hotspot by name
+	findHotspot := func(hotspots []Hotspot, name string) *Hotspot {
+		for i := range hotspots {
+			if hotspots[i].Name == name {
+				return &hotspots[i]
+			}
+		}
+		return nil
+	}
+
+	t.Run("references", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		hotspots, err := Hotspots(context.Background(), workspace, HotspotOptions{SortBy: HotspotsByReferences})
+		if err != nil {
+			t.Fatalf("Failed to find hotspots: %v", err)
+		}
+		expected := map[string]int{
+			"New":       2,
+			"Store.Get": 2,
+			"Store":     4, // including the method receiver
+			"prefix":    1,
+			"Unused":    0,
+		}
+		for name, references := range expected {
+			hotspot := findHotspot(hotspots, name)
+			if hotspot == nil {
+				t.Errorf("Expected a hotspot for %s, got %+v", name, hotspots)
+				continue
+			}
+			if hotspot.References != references {
+				t.Errorf("Expected %d references to %s, got %d", references, name, hotspot.References)
+			}
+		}
+		if hotspots[0].Name != "Store" || hotspots[len(hotspots)-1].Name != "Unused" {
+			t.Errorf("Expected Store first and Unused last, got %+v", hotspots)
+		}
+		if findHotspot(hotspots, "main") != nil || findHotspot(hotspots, "helper") != nil {
+			t.Errorf("Expected no hotspots for main and test helpers, got %+v", hotspots)
+		}
+
+		// Test files add their declarations and references
+		hotspots, err = Hotspots(context.Background(), workspace, HotspotOptions{IncludeTests: true})
+		if err != nil {
+			t.Fatalf("Failed to find hotspots: %v", err)
+		}
+		if hotspot := findHotspot(hotspots, "New"); hotspot == nil || hotspot.References != 3 {
+			t.Errorf("Expected 3 references to New including tests, got %+v", hotspot)
+		}
+	})
+
+	t.Run("churn", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		git := func(args ...string) {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+			cmd.Dir = workspace
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, output)
+			}
+		}
+		git("init", "--quiet")
+		git("add", "-A")
+		git("commit", "--quiet", "-m", "Initial commit")
+		mainFile := filepath.Join(workspace, "cmd", "main.go")
+		if err := os.WriteFile(mainFile, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("commit", "--quiet", "-am", "Simplify main")
+
+		hotspots, err := Hotspots(context.Background(), workspace, HotspotOptions{SortBy: HotspotsByChurn, Limit: 1})
+		if err != nil {
+			t.Fatalf("Failed to find hotspots: %v", err)
+		}
+		if len(hotspots) != 1 || hotspots[0].Name != "New" || hotspots[0].Churn != 1 {
+			t.Errorf("Expected one hotspot with churn 1 ordered by name on ties, got %+v", hotspots)
+		}
+	})
+
+	t.Run("hotspots tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		call := func(arguments map[string]any) mcp.CallToolResult {
+			encoded, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      1,
+				"method":  string(mcp.MethodToolsCall),
+				"params":  map[string]any{"name": hotspotsToolName, "arguments": arguments},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+			return response.Result.(mcp.CallToolResult)
+		}
+
+		result := call(map[string]any{"workspace_dir": workspace, "sort_by": "size", "limit": 2})
+		text := toolResultText(&result)
+		if result.IsError || !strings.HasPrefix(text, "Top 2 symbols ranked by size:\n1. func testmodule/lib.New") {
+			t.Errorf("Expected the largest symbols, got: %s", text)
+		}
+
+		result = call(map[string]any{"workspace_dir": workspace, "sort_by": "age"})
+		if text := toolResultText(&result); !result.IsError || !strings.Contains(text, `argument "sort_by" must be one of "score", "references", "churn", "size", got "age"`) {
+			t.Errorf("Expected an unknown sort_by error, got: %s", text)
+		}
+	})
+}
@@ -0,0 +1,567 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	changeImpactToolName        = "change_impact"
+	changeImpactToolDescription = `Shows the blast radius of the uncommitted and committed changes of the working tree since a git ref, e.g. the base branch of a pull request. The Go files changed since the ref are diffed to find the top-level declarations that were changed or removed, and every reference to them from elsewhere in the module is listed with the function containing it, so reviewers can check the callers that may be affected.
+
+References are found by type checking the module: calls through interfaces are listed for the interface method, not for the changed methods implementing it. References to removed declarations are the places that no longer compile.`
+)
+
+// ChangeImpact lists the declarations changed since a git ref with their references
+type ChangeImpact struct {
+	Ref          string               `json:"ref"`
+	Declarations []ChangedDeclaration `json:"declarations"`
+}
+
+// ChangedDeclaration is a top-level declaration changed or removed since the ref
+type ChangedDeclaration struct {
+	// Name is the declared name, Receiver.Name for methods
+	Name string `json:"name"`
+	// Kind is one of func, method, type, const and var
+	Kind string `json:"kind"`
+	// File is relative to the module root
+	File string `json:"file"`
+	// Line is the line of the declaration in the working tree, or at the ref when removed
+	Line       int               `json:"line"`
+	Removed    bool              `json:"removed,omitempty"`
+	References []ImpactReference `json:"references,omitempty"`
+}
+
+// ImpactReference is a use of a changed declaration
+type ImpactReference struct {
+	// File is relative to the module root
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Function is the top-level function containing the reference, empty at package level
+	Function string `json:"function,omitempty"`
+}
+
+func AddChangeImpactTool(mcpServer *server.MCPServer) {
+	handleChangeImpact := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		ref := request.GetString("ref", "")
+		if ref == "" {
+			ref = "HEAD"
+		}
+		includeTests := true
+		includeTests = request.GetBool("include_tests", includeTests)
+
+		impact, err := AnalyzeChangeImpact(ctx, workspaceDir, ref, includeTests)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error analyzing change impact: %v", err)), nil
+		}
+		return mcp.NewToolResultText(impact.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		changeImpactToolName,
+		mcp.WithDescription(changeImpactToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to analyze"),
+			mcp.Required(),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Git ref the working tree is compared with, e.g. main or origin/main"),
+			mcp.DefaultString("HEAD"),
+		),
+		mcp.WithBoolean("include_tests",
+			mcp.Description("Whether changes to test files and references from them are included"),
+			mcp.DefaultBool(true),
+		),
+	), handleChangeImpact)
+}
+
+// changedLines are the lines of a file changed since the ref
+type changedLines struct {
+	// current are the ranges of added or modified lines in the working tree
+	current [][2]int
+	// deletedAfter are the lines of the working tree after which lines were deleted
+	deletedAfter []int
+	// old are the ranges of removed or modified lines at the ref
+	old [][2]int
+	// removed is set when the file no longer exists
+	removed bool
+}
+
+// overlapsCurrent reports whether lines in start to end were changed in the working tree
+func (lines *changedLines) overlapsCurrent(start, end int) bool {
+	for _, r := range lines.current {
+		if start <= r[1] && end >= r[0] {
+			return true
+		}
+	}
+	for _, line := range lines.deletedAfter {
+		if start <= line && end > line {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapsOld reports whether lines in start to end at the ref were removed or modified
+func (lines *changedLines) overlapsOld(start, end int) bool {
+	for _, r := range lines.old {
+		if start <= r[1] && end >= r[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// hunkHeader matches the line ranges of a hunk of a unified diff
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// changedGoFiles diffs the Go files below dir against ref, by repository relative path
+func changedGoFiles(ctx context.Context, dir string, ref string) (map[string]*changedLines, error) {
+	output, err := runGit(ctx, dir, "diff", "--unified=0", "--no-renames", "--no-color", "--no-ext-diff", ref, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	return parseUnifiedDiff(output), nil
+}
+
+// parseUnifiedDiff returns the changed lines of the Go files of a unified diff without
+// context lines, by the paths of the diff
+func parseUnifiedDiff(output string) map[string]*changedLines {
+	files := make(map[string]*changedLines)
+	var current *changedLines
+	var oldPath string
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			oldPath = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			current = nil
+			path := strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			removed := path == "/dev/null"
+			if removed {
+				path = oldPath
+			}
+			if strings.HasSuffix(path, ".go") {
+				current = &changedLines{removed: removed}
+				files[path] = current
+			}
+		case strings.HasPrefix(line, "@@ ") && current != nil:
+			match := hunkHeader.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			count := func(text string) int {
+				if text == "" {
+					return 1
+				}
+				n, _ := strconv.Atoi(text)
+				return n
+			}
+			oldStart, oldCount := count(match[1]), count(match[2])
+			newStart, newCount := count(match[3]), count(match[4])
+			if oldCount > 0 {
+				current.old = append(current.old, [2]int{oldStart, oldStart + oldCount - 1})
+			}
+			if newCount > 0 {
+				current.current = append(current.current, [2]int{newStart, newStart + newCount - 1})
+			} else {
+				current.deletedAfter = append(current.deletedAfter, newStart)
+			}
+		}
+	}
+	return files
+}
+
+// AnalyzeChangeImpact finds the top-level declarations of the module containing
+// workspaceDir changed or removed since ref and lists their references in the module
+func AnalyzeChangeImpact(ctx context.Context, workspaceDir string, ref string, includeTests bool) (*ChangeImpact, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	repoRoot, err := gitRepoRoot(ctx, module.root)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := changedGoFiles(ctx, module.root, ref)
+	if err != nil {
+		return nil, err
+	}
+	isIncluded := func(path string) bool {
+		return includeTests || !strings.HasSuffix(path, "_test.go")
+	}
+	repoPath := func(filename string) string {
+		rel, _ := workspaceRelPath(filename, repoRoot)
+		return filepath.ToSlash(rel)
+	}
+
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Context: ctx,
+		Dir:     module.root,
+		Env:     packagesEnv(module.root),
+		Tests:   includeTests,
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	// Test variants type check the files of a package again, so every file is visited
+	// once and declarations and references are identified by position instead of object
+	var files []*ast.File
+	filePackages := make(map[*ast.File]*packages.Package)
+	filesByName := make(map[string]*ast.File)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			name := pkg.Fset.Position(file.Pos()).Filename
+			if _, ok := filesByName[name]; !ok {
+				filesByName[name] = file
+				filePackages[file] = pkg
+				files = append(files, file)
+			}
+		}
+	}
+
+	var changedDeclarations []*ChangedDeclaration
+	byPosition := make(map[token.Position]*ChangedDeclaration)
+	// spans are the lines of the changed declarations, whose own references are skipped
+	type span struct {
+		file       string
+		start, end int
+	}
+	var spans []span
+	// declared holds the names of the current top-level declarations by directory
+	declared := make(map[string]map[string]bool)
+	for _, file := range files {
+		pkg := filePackages[file]
+		filename := pkg.Fset.Position(file.Pos()).Filename
+		dir := resolveSymlinks(filepath.Dir(filename))
+		if declared[dir] == nil {
+			declared[dir] = make(map[string]bool)
+		}
+		lines := changed[repoPath(filename)]
+		for _, decl := range file.Decls {
+			for _, ident := range declarationIdents(decl) {
+				name, kind := topLevelName(decl, ident)
+				declared[dir][name] = true
+				if lines == nil || !isIncluded(filename) {
+					continue
+				}
+				node := declarationNode(decl, ident)
+				start, end := pkg.Fset.Position(node.Pos()).Line, pkg.Fset.Position(node.End()).Line
+				object := pkg.TypesInfo.Defs[ident]
+				if object == nil || !lines.overlapsCurrent(start, end) {
+					continue
+				}
+				declaration := &ChangedDeclaration{
+					Name: name,
+					Kind: kind,
+					File: module.relPath(filename),
+					Line: pkg.Fset.Position(ident.Pos()).Line,
+				}
+				changedDeclarations = append(changedDeclarations, declaration)
+				byPosition[pkg.Fset.Position(object.Pos())] = declaration
+				spans = append(spans, span{file: filename, start: start, end: end})
+			}
+		}
+	}
+
+	seenReferences := make(map[token.Position]bool)
+	for _, file := range files {
+		pkg := filePackages[file]
+		filename := pkg.Fset.Position(file.Pos()).Filename
+		if !isIncluded(filename) {
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			ident, ok := node.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			object := pkg.TypesInfo.Uses[ident]
+			if object == nil {
+				return true
+			}
+			declaration, ok := byPosition[pkg.Fset.Position(object.Pos())]
+			position := pkg.Fset.Position(ident.Pos())
+			if !ok || seenReferences[position] {
+				return true
+			}
+			seenReferences[position] = true
+			for _, s := range spans {
+				if s.file == filename && position.Line >= s.start && position.Line <= s.end {
+					return true
+				}
+			}
+			declaration.References = append(declaration.References, ImpactReference{
+				File:     module.relPath(filename),
+				Line:     position.Line,
+				Function: enclosingFunctionName(file, ident.Pos()),
+			})
+			return true
+		})
+	}
+
+	removed, err := removedDeclarations(ctx, repoRoot, ref, changed, declared, isIncluded)
+	if err != nil {
+		return nil, err
+	}
+	// Uses of removed declarations no longer type check, the type errors locate them
+	seenErrors := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			if pkgErr.Kind != packages.TypeError || seenErrors[pkgErr.Pos+pkgErr.Msg] {
+				continue
+			}
+			seenErrors[pkgErr.Pos+pkgErr.Msg] = true
+			filename, line := splitErrorPosition(pkgErr.Pos)
+			if !isIncluded(filename) {
+				continue
+			}
+			for _, declaration := range removed {
+				if !isUndefinedError(pkgErr.Msg, declaration.Name) {
+					continue
+				}
+				reference := ImpactReference{File: module.relPath(filename), Line: line}
+				if file, ok := filesByName[filename]; ok {
+					reference.Function = enclosingFunctionName(file, file.Pos()+token.Pos(lineOffset(pkg.Fset, file, line)))
+				}
+				declaration.References = append(declaration.References, reference)
+			}
+		}
+	}
+	for _, declaration := range removed {
+		declaration.File = module.relPath(filepath.Join(repoRoot, filepath.FromSlash(declaration.File)))
+	}
+
+	impact := &ChangeImpact{Ref: ref}
+	for _, declaration := range append(changedDeclarations, removed...) {
+		slices.SortFunc(declaration.References, func(a, b ImpactReference) int {
+			if a.File != b.File {
+				return strings.Compare(a.File, b.File)
+			}
+			return a.Line - b.Line
+		})
+		impact.Declarations = append(impact.Declarations, *declaration)
+	}
+	slices.SortStableFunc(impact.Declarations, func(a, b ChangedDeclaration) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+		return a.Line - b.Line
+	})
+	return impact, nil
+}
+
+// removedDeclarations returns the top-level declarations in the changed lines of the
+// files at ref that are no longer declared in their directory. Their files are relative
+// to the repository root.
+func removedDeclarations(
+	ctx context.Context,
+	repoRoot string,
+	ref string,
+	changed map[string]*changedLines,
+	declared map[string]map[string]bool,
+	isIncluded func(path string) bool,
+) ([]*ChangedDeclaration, error) {
+	var removed []*ChangedDeclaration
+	for path, lines := range changed {
+		if len(lines.old) == 0 || !isIncluded(path) {
+			continue
+		}
+		source, err := runGit(ctx, repoRoot, "show", ref+":"+path)
+		if err != nil {
+			// Files added since the ref have no old declarations
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, source, parser.SkipObjectResolution)
+		if err != nil && file == nil {
+			continue
+		}
+		current := declared[resolveSymlinks(filepath.Join(repoRoot, filepath.Dir(filepath.FromSlash(path))))]
+		for _, decl := range file.Decls {
+			for _, ident := range declarationIdents(decl) {
+				name, kind := topLevelName(decl, ident)
+				node := declarationNode(decl, ident)
+				if current[name] || !lines.overlapsOld(fset.Position(node.Pos()).Line, fset.Position(node.End()).Line) {
+					continue
+				}
+				removed = append(removed, &ChangedDeclaration{
+					Name:    name,
+					Kind:    kind,
+					File:    path,
+					Line:    fset.Position(ident.Pos()).Line,
+					Removed: true,
+				})
+			}
+		}
+	}
+	return removed, nil
+}
+
+// declarationIdents returns the identifiers declared by a top-level declaration
+func declarationIdents(decl ast.Decl) []*ast.Ident {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv == nil && (decl.Name.Name == "init" || decl.Name.Name == "main") {
+			return nil
+		}
+		return []*ast.Ident{decl.Name}
+	case *ast.GenDecl:
+		var idents []*ast.Ident
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				idents = append(idents, spec.Name)
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if name.Name != "_" {
+						idents = append(idents, name)
+					}
+				}
+			}
+		}
+		return idents
+	}
+	return nil
+}
+
+// topLevelName returns the name and kind of an identifier declared by a top-level
+// declaration, Receiver.Name for methods
+func topLevelName(decl ast.Decl, ident *ast.Ident) (string, string) {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv != nil && len(decl.Recv.List) > 0 {
+			return receiverTypeName(decl.Recv.List[0].Type) + "." + ident.Name, "method"
+		}
+		return ident.Name, "func"
+	case *ast.GenDecl:
+		if decl.Tok == token.TYPE {
+			return ident.Name, "type"
+		}
+		return ident.Name, decl.Tok.String()
+	}
+	return ident.Name, ""
+}
+
+// declarationNode returns the node spanning the declaration of an identifier: the
+// function, or the spec of grouped declarations and the whole declaration otherwise
+func declarationNode(decl ast.Decl, ident *ast.Ident) ast.Node {
+	genDecl, ok := decl.(*ast.GenDecl)
+	if !ok || len(genDecl.Specs) == 1 {
+		return decl
+	}
+	for _, spec := range genDecl.Specs {
+		if spec.Pos() <= ident.Pos() && ident.End() <= spec.End() {
+			return spec
+		}
+	}
+	return decl
+}
+
+// enclosingFunctionName returns the name of the top-level function containing pos,
+// Receiver.Name for methods, or an empty name at package level
+func enclosingFunctionName(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || pos < funcDecl.Pos() || pos >= funcDecl.End() {
+			continue
+		}
+		name, _ := topLevelName(funcDecl, funcDecl.Name)
+		return name
+	}
+	return ""
+}
+
+// lineOffset returns the offset of the first character of a line of a file, relative to
+// the start of the file
+func lineOffset(fset *token.FileSet, file *ast.File, line int) int {
+	tokenFile := fset.File(file.Pos())
+	if tokenFile == nil || line < 1 || line > tokenFile.LineCount() {
+		return 0
+	}
+	return int(tokenFile.LineStart(line) - file.Pos())
+}
+
+// splitErrorPosition splits a file:line:column error position
+func splitErrorPosition(position string) (string, int) {
+	parts := strings.Split(position, ":")
+	if len(parts) < 3 {
+		return position, 0
+	}
+	line, _ := strconv.Atoi(parts[len(parts)-2])
+	return strings.Join(parts[:len(parts)-2], ":"), line
+}
+
+// isUndefinedError reports whether a type error is a use of the removed declaration name
+func isUndefinedError(message string, name string) bool {
+	if _, method, ok := strings.Cut(name, "."); ok {
+		return strings.Contains(message, "has no field or method "+method+")") ||
+			strings.Contains(message, "has no field or method "+method+",")
+	}
+	// Qualified uses from other packages are reported as undefined: pkg.Name
+	_, undefined, ok := strings.Cut(message, "undefined: ")
+	return ok && (undefined == name || strings.HasSuffix(undefined, "."+name) && !strings.Contains(undefined, " "))
+}
+
+func (impact *ChangeImpact) String() string {
+	if len(impact.Declarations) == 0 {
+		return fmt.Sprintf("No top-level declarations changed since %s", impact.Ref)
+	}
+	references := 0
+	for _, declaration := range impact.Declarations {
+		references += len(declaration.References)
+	}
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		"%d declarations changed since %s, referenced in %d places:\n",
+		len(impact.Declarations),
+		impact.Ref,
+		references,
+	)
+	for _, declaration := range impact.Declarations {
+		state := "changed"
+		if declaration.Removed {
+			state = "removed"
+		}
+		fmt.Fprintf(&b, "\n%s:%d: %s %s (%s", declaration.File, declaration.Line, declaration.Kind, declaration.Name, state)
+		if len(declaration.References) == 0 {
+			b.WriteString(", no references)\n")
+			continue
+		}
+		fmt.Fprintf(&b, ", %d references)\n", len(declaration.References))
+		for _, reference := range declaration.References {
+			fmt.Fprintf(&b, "  %s:%d", reference.File, reference.Line)
+			if reference.Function != "" {
+				fmt.Fprintf(&b, " in %s", reference.Function)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
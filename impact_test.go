@@ -0,0 +1,133 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChangeImpact(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/app", "", "go 1.22", ""},
+		"store/store.go": {
+			"package store",
+			"",
+			"type DB struct{}",
+			"",
+			"func Open(path string) *DB { return &DB{} }",
+			"",
+			"func (db *DB) Get(key string) string { return key }",
+			"",
+			"func (db *DB) Delete(key string) {}",
+			"",
+			"const Limit = 10",
+			"",
+		},
+		"app/app.go": {
+			"package app",
+			"",
+			"import \"example.com/app/store\"",
+			"",
+			"var limit = store.Limit",
+			"",
+			"func Run() string {",
+			"	db := store.Open(\"data\")",
+			"	db.Delete(\"old\")",
+			"	return db.Get(\"key\")",
+			"}",
+			"",
+		},
+		"app/app_test.go": {
+			"package app",
+			"",
+			"import (",
+			"	\"testing\"",
+			"",
+			"	\"example.com/app/store\"",
+			")",
+			"",
+			"func TestOpen(t *testing.T) {",
+			"	_ = store.Open(\"test\")",
+			"}",
+			"",
+		},
+	})
+	runTestGit(t, workspace, "init", "-q")
+	runTestGit(t, workspace, "add", "-A")
+	runTestGit(t, workspace, "commit", "-q", "-m", "initial")
+	writeTestFiles(t, workspace, map[string][]string{
+		"store/store.go": {
+			"package store",
+			"",
+			"type DB struct{}",
+			"",
+			"func Open(path string, readOnly bool) *DB { return &DB{} }",
+			"",
+			"func (db *DB) Get(key string) string { return key }",
+			"",
+			"const Limit = 10",
+			"",
+		},
+	})
+
+	t.Run("references of changed and removed declarations", func(t *testing.T) {
+		t.Parallel()
+		impact, err := AnalyzeChangeImpact(context.Background(), filepath.Join(workspace, "app"), "HEAD", true)
+		if err != nil {
+			t.Fatalf("Failed to analyze change impact: %v", err)
+		}
+		expected := strings.Join([]string{
+			"2 declarations changed since HEAD, referenced in 3 places:",
+			"",
+			"store/store.go:5: func Open (changed, 2 references)",
+			"  app/app.go:8 in Run",
+			"  app/app_test.go:10 in TestOpen",
+			"",
+			"store/store.go:9: method DB.Delete (removed, 1 references)",
+			"  app/app.go:9 in Run",
+			"",
+		}, "\n")
+		if impact.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, impact)
+		}
+	})
+
+	t.Run("excluding tests", func(t *testing.T) {
+		t.Parallel()
+		impact, err := AnalyzeChangeImpact(context.Background(), workspace, "HEAD", false)
+		if err != nil {
+			t.Fatalf("Failed to analyze change impact: %v", err)
+		}
+		for _, declaration := range impact.Declarations {
+			for _, reference := range declaration.References {
+				if strings.HasSuffix(reference.File, "_test.go") {
+					t.Errorf("Expected no references from tests, got %+v", reference)
+				}
+			}
+		}
+	})
+
+	t.Run("other refs and errors", func(t *testing.T) {
+		t.Parallel()
+		impact, err := AnalyzeChangeImpact(context.Background(), workspace, "HEAD~0", true)
+		if err != nil {
+			t.Fatalf("Failed to analyze change impact: %v", err)
+		}
+		if len(impact.Declarations) != 2 {
+			t.Errorf("Expected the working tree changes for HEAD~0, got %+v", impact.Declarations)
+		}
+		if _, err := AnalyzeChangeImpact(context.Background(), workspace, "unknown-ref", true); err == nil {
+			t.Error("Expected an error for an unknown ref")
+		}
+		if _, err := AnalyzeChangeImpact(context.Background(), "relative", "HEAD", true); err == nil {
+			t.Error("Expected an error for a relative workspace_dir")
+		}
+		empty := &ChangeImpact{Ref: "main"}
+		if empty.String() != "No top-level declarations changed since main" {
+			t.Errorf("Unexpected empty report: %s", empty)
+		}
+	})
+}
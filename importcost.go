@@ -0,0 +1,271 @@
+package go_mcp_tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	importCostToolName        = "import_cost"
+	importCostToolDescription = `Reports what each import of a Go package costs: the packages it depends on transitively, the packages and modules only reached through it, which removing the import would drop, and the size of their Go source as an estimate of the build time they add, to decide whether a convenience dependency is worth it.
+
+The size of the Go source is a proxy for compile time on a cold build cache: packages of the standard library are usually cached, and packages using cgo also run the C compiler, which is flagged. Test files and test imports are not counted.`
+)
+
+func AddImportCostTool(mcpServer *server.MCPServer) {
+	handleImportCost := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := request.RequireString("package")
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := ImportCosts(ctx, workspaceDir, pattern)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error computing import costs: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		importCostToolName,
+		mcp.WithDescription(importCostToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of the directory the package is resolved from"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Import path or relative directory of a single package, e.g. ./cmd/server"),
+			mcp.Required(),
+		),
+	), handleImportCost)
+}
+
+// ImportCostReport lists the cost of the imports of a package
+type ImportCostReport struct {
+	Package string `json:"package"`
+	// Dependencies is the number of packages the package depends on transitively, of
+	// which Standard are in the standard library
+	Dependencies int `json:"dependencies"`
+	Standard     int `json:"standard"`
+	// Bytes is the size of the Go source of the dependencies
+	Bytes int64 `json:"bytes"`
+	// Imports are ordered by the size of the source only reached through them
+	Imports []ImportCost `json:"imports"`
+}
+
+// ImportCost is what an import adds to the build of the importing package
+type ImportCost struct {
+	Import   string `json:"import"`
+	Standard bool   `json:"standard,omitempty"`
+	// Transitive is the number of packages the import depends on, including itself
+	Transitive int `json:"transitive"`
+	// Exclusive is the number of those packages not reached through other imports, of
+	// which ExclusiveStandard are in the standard library
+	Exclusive         int `json:"exclusive"`
+	ExclusiveStandard int `json:"exclusive_standard"`
+	// Modules are the modules whose packages are only reached through the import
+	Modules []string `json:"modules,omitempty"`
+	// Bytes is the size of the Go source of the exclusive packages
+	Bytes int64 `json:"bytes"`
+	// Cgo is set when exclusive packages use cgo
+	Cgo bool `json:"cgo,omitempty"`
+}
+
+// listedPackage is a package listed by go list -json
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	Standard   bool
+	DepOnly    bool
+	GoFiles    []string
+	CgoFiles   []string
+	Imports    []string
+	Module     *struct {
+		Path string
+		Main bool
+	}
+}
+
+// ImportCosts lists the dependencies of the package matching pattern, resolved from
+// workspaceDir, and the packages, modules and source each of its imports adds to them
+func ImportCosts(ctx context.Context, workspaceDir string, pattern string) (*ImportCostReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	listing, err := runGo(ctx, workspaceDir, "list", "-deps", "-json", "--", pattern)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]*listedPackage)
+	var roots []*listedPackage
+	decoder := json.NewDecoder(strings.NewReader(listing))
+	for {
+		pkg := &listedPackage{}
+		if err := decoder.Decode(pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode go list output: %w", err)
+		}
+		listed[pkg.ImportPath] = pkg
+		if !pkg.DepOnly {
+			roots = append(roots, pkg)
+		}
+	}
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("%s matches %d packages, the import costs are reported for a single package", pattern, len(roots))
+	}
+	root := roots[0]
+
+	// Imports missing from the listing, like the pseudo package C of cgo, are skipped
+	dependencies := func(importPath string) map[string]bool {
+		reached := make(map[string]bool)
+		for queue := []string{importPath}; len(queue) > 0; queue = queue[1:] {
+			pkg := listed[queue[0]]
+			if pkg == nil || reached[pkg.ImportPath] {
+				continue
+			}
+			reached[pkg.ImportPath] = true
+			queue = append(queue, pkg.Imports...)
+		}
+		return reached
+	}
+	sizes := make(map[string]int64)
+	sourceBytes := func(importPath string) int64 {
+		if size, ok := sizes[importPath]; ok {
+			return size
+		}
+		pkg := listed[importPath]
+		var size int64
+		for _, name := range append(slices.Clone(pkg.GoFiles), pkg.CgoFiles...) {
+			if info, err := os.Stat(filepath.Join(pkg.Dir, name)); err == nil {
+				size += info.Size()
+			}
+		}
+		sizes[importPath] = size
+		return size
+	}
+
+	report := &ImportCostReport{Package: root.ImportPath}
+	for importPath := range dependencies(root.ImportPath) {
+		if importPath == root.ImportPath {
+			continue
+		}
+		report.Dependencies++
+		if listed[importPath].Standard {
+			report.Standard++
+		}
+		report.Bytes += sourceBytes(importPath)
+	}
+
+	reached := make(map[string]map[string]bool)
+	for _, importPath := range root.Imports {
+		if listed[importPath] != nil {
+			reached[importPath] = dependencies(importPath)
+		}
+	}
+	for importPath, transitive := range reached {
+		cost := ImportCost{
+			Import:     importPath,
+			Standard:   listed[importPath].Standard,
+			Transitive: len(transitive),
+		}
+		modules := make(map[string]bool)
+		others := make(map[string]bool)
+		for dependency := range transitive {
+			exclusive := true
+			for other, otherTransitive := range reached {
+				if other != importPath && otherTransitive[dependency] {
+					exclusive = false
+					break
+				}
+			}
+			pkg := listed[dependency]
+			if !exclusive {
+				if pkg.Module != nil {
+					others[pkg.Module.Path] = true
+				}
+				continue
+			}
+			cost.Exclusive++
+			cost.Bytes += sourceBytes(dependency)
+			if pkg.Standard {
+				cost.ExclusiveStandard++
+			}
+			if len(pkg.CgoFiles) > 0 {
+				cost.Cgo = true
+			}
+			if pkg.Module != nil && !pkg.Module.Main {
+				modules[pkg.Module.Path] = true
+			}
+		}
+		for module := range modules {
+			if !others[module] {
+				cost.Modules = append(cost.Modules, module)
+			}
+		}
+		slices.Sort(cost.Modules)
+		report.Imports = append(report.Imports, cost)
+	}
+	slices.SortFunc(report.Imports, func(a, b ImportCost) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), strings.Compare(a.Import, b.Import))
+	})
+	return report, nil
+}
+
+// sourceSize formats a size of source in bytes, KB or MB
+func sourceSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", bytes)
+}
+
+func (report *ImportCostReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		"%s depends on %d packages (%d standard) with %s of Go source through %d imports, most costly first:\n",
+		report.Package, report.Dependencies, report.Standard, sourceSize(report.Bytes), len(report.Imports),
+	)
+	for _, cost := range report.Imports {
+		fmt.Fprintf(&b, "  %s", cost.Import)
+		if cost.Standard {
+			b.WriteString(" (standard)")
+		}
+		fmt.Fprintf(
+			&b,
+			": %d packages, %d only through it (%d standard), %s",
+			cost.Transitive, cost.Exclusive, cost.ExclusiveStandard, sourceSize(cost.Bytes),
+		)
+		if cost.Cgo {
+			b.WriteString(", cgo")
+		}
+		if len(cost.Modules) > 0 {
+			fmt.Fprintf(&b, ", modules only through it: %s", strings.Join(cost.Modules, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
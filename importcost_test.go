@@ -0,0 +1,87 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImportCosts(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	files := map[string][]string{
+		"go.mod": {
+			"module example.com/app",
+			"",
+			"go 1.22",
+			"",
+			"require example.com/lib v0.0.0",
+			"",
+			"replace example.com/lib => ./lib",
+			"",
+		},
+		"app.go": {
+			"package app",
+			"",
+			"import (",
+			"\t_ \"example.com/app/heavy\"",
+			"\t_ \"example.com/app/light\"",
+			")",
+			"",
+		},
+		"heavy/heavy.go": {
+			"package heavy",
+			"",
+			"import (",
+			"\t_ \"example.com/app/shared\"",
+			"\t_ \"example.com/lib\"",
+			")",
+			"",
+			"// " + strings.Repeat("heavy ", 400),
+			"",
+		},
+		"light/light.go":   {"package light", "", "import _ \"example.com/app/shared\"", ""},
+		"shared/shared.go": {"package shared", ""},
+		"lib/go.mod":       {"module example.com/lib", "", "go 1.22", ""},
+		"lib/lib.go":       {"package lib", "", "// " + strings.Repeat("lib ", 300), ""},
+	}
+	writeTestFiles(t, workspace, files)
+	size := func(names ...string) int64 {
+		var total int64
+		for _, name := range names {
+			total += int64(len(strings.Join(files[name], "\n")))
+		}
+		return total
+	}
+
+	t.Run("costs", func(t *testing.T) {
+		t.Parallel()
+		report, err := ImportCosts(context.Background(), workspace, ".")
+		if err != nil {
+			t.Fatalf("Failed to compute the import costs: %v", err)
+		}
+		if report.Dependencies != 4 || report.Standard != 0 || report.Bytes != size("heavy/heavy.go", "light/light.go", "shared/shared.go", "lib/lib.go") {
+			t.Errorf("Unexpected totals: %+v", report)
+		}
+		expected := strings.Join([]string{
+			"example.com/app depends on 4 packages (0 standard) with 3.7 KB of Go source through 2 imports, most costly first:",
+			"  example.com/app/heavy: 3 packages, 2 only through it (0 standard), 3.6 KB, modules only through it: example.com/lib",
+			"  example.com/app/light: 2 packages, 1 only through it (0 standard), 49 bytes",
+			"",
+		}, "\n")
+		if report.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, report.String())
+		}
+		if heavy := report.Imports[0]; heavy.Bytes != size("heavy/heavy.go", "lib/lib.go") {
+			t.Errorf("Expected the source of heavy and lib, got %d bytes", heavy.Bytes)
+		}
+	})
+
+	t.Run("several packages", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ImportCosts(context.Background(), workspace, "./..."); err == nil || !strings.Contains(err.Error(), "matches 4 packages") {
+			t.Errorf("Expected a pattern of several packages to be refused, got: %v", err)
+		}
+	})
+}
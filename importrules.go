@@ -0,0 +1,237 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	importRulesToolName        = "import_rules"
+	importRulesToolDescription = `Checks the imports of the packages of a Go module against architectural rules and reports every import violating them with its file and line. Rules are sentences of two forms:
+
+- "A must not import B": packages in A may not import packages in B
+- "only A, C may import B": packages outside A, C and B itself may not import packages in B
+
+Packages are given as directories relative to the module root, which include their subdirectories, e.g. pkg/domain, or as import paths for packages outside the module, e.g. net/http or github.com/lib/pq. A trailing /... is accepted and ignored.`
+)
+
+// ImportRule restricts the imports between packages
+type ImportRule struct {
+	// Text is the rule as written
+	Text string `json:"text"`
+	// Importers are the packages the rule applies to, or the only packages allowed to
+	// import the imported packages when Only is set
+	Importers []string `json:"importers"`
+	Imported  []string `json:"imported"`
+	Only      bool     `json:"only,omitempty"`
+}
+
+// ImportViolation is an import breaking a rule
+type ImportViolation struct {
+	Rule string `json:"rule"`
+	// Importer is the import path of the importing package
+	Importer string `json:"importer"`
+	Import   string `json:"import"`
+	// File is relative to the module root
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// ImportRulesReport lists the imports of a module violating the rules
+type ImportRulesReport struct {
+	Module     string            `json:"module"`
+	Rules      []ImportRule      `json:"rules"`
+	Violations []ImportViolation `json:"violations,omitempty"`
+}
+
+func AddImportRulesTool(mcpServer *server.MCPServer) {
+	handleImportRules := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		rules, err := request.RequireStringSlice("rules")
+		if err != nil {
+			return nil, err
+		}
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("rules argument must list at least one rule")
+		}
+		includeTests := request.GetBool("include_tests", false)
+
+		report, err := CheckImportRules(workspaceDir, rules, includeTests)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error checking import rules: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		importRulesToolName,
+		mcp.WithDescription(importRulesToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to check"),
+			mcp.Required(),
+		),
+		mcp.WithArray("rules",
+			mcp.Description(`Rules like "pkg/domain must not import pkg/http" or "only cmd may import internal/boot"`),
+			mcp.Items(map[string]any{"type": "string"}),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("include_tests",
+			mcp.Description("Whether the imports of test files must follow the rules as well"),
+			mcp.DefaultBool(false),
+		),
+	), handleImportRules)
+}
+
+// ParseImportRule parses a rule of the form "A must not import B" or "only A, C may import B",
+// where "may not" and "cannot" can be used instead of "must not". Keywords are matched
+// case insensitively, package paths keep their case.
+func ParseImportRule(text string) (ImportRule, error) {
+	rule := ImportRule{Text: strings.Join(strings.Fields(text), " ")}
+	// Lowering keeps the byte offsets of the keywords valid for the ASCII text of rules
+	lower := strings.ToLower(rule.Text)
+	cut := func(start int, separators ...string) (string, string, bool) {
+		for _, separator := range separators {
+			if i := strings.Index(lower[start:], separator); i >= 0 {
+				return rule.Text[start : start+i], rule.Text[start+i+len(separator):], true
+			}
+		}
+		return "", "", false
+	}
+
+	var importers, imported string
+	var found bool
+	if strings.HasPrefix(lower, "only ") {
+		rule.Only = true
+		importers, imported, found = cut(len("only "), " may import ", " can import ")
+	} else {
+		importers, imported, found = cut(0, " must not import ", " may not import ", " cannot import ")
+	}
+	if !found || len(lower) != len(rule.Text) {
+		return rule, fmt.Errorf(`invalid rule %q, expected "A must not import B" or "only A may import B"`, text)
+	}
+	rule.Importers = splitRulePackages(importers)
+	rule.Imported = splitRulePackages(imported)
+	if len(rule.Importers) == 0 || len(rule.Imported) == 0 {
+		return rule, fmt.Errorf("invalid rule %q, packages are missing", text)
+	}
+	return rule, nil
+}
+
+// splitRulePackages splits a comma separated list of packages of a rule
+func splitRulePackages(list string) []string {
+	var packages []string
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		part = strings.TrimSuffix(strings.TrimSuffix(part, "..."), "/")
+		part = strings.TrimPrefix(part, "./")
+		if part == "" {
+			continue
+		}
+		packages = append(packages, part)
+	}
+	return packages
+}
+
+// CheckImportRules reports the imports of the packages of the module containing
+// workspaceDir that violate the rules
+func CheckImportRules(workspaceDir string, rules []string, includeTests bool) (*ImportRulesReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	report := &ImportRulesReport{}
+	for _, text := range rules {
+		rule, err := ParseImportRule(text)
+		if err != nil {
+			return nil, err
+		}
+		report.Rules = append(report.Rules, rule)
+	}
+	module, err := loadModuleSources(workspaceDir, includeTests)
+	if err != nil {
+		return nil, err
+	}
+	report.Module = module.path
+
+	// matches reports whether an import path is one of the packages of a rule, given as
+	// directories of the module or as import paths
+	matches := func(importPath string, packages []string) bool {
+		candidates := []string{importPath}
+		if importPath == module.path {
+			candidates = append(candidates, ".")
+		} else if rel, ok := strings.CutPrefix(importPath, module.path+"/"); ok {
+			candidates = append(candidates, rel)
+		}
+		for _, candidate := range candidates {
+			for _, pkg := range packages {
+				if candidate == pkg || strings.HasPrefix(candidate, pkg+"/") {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			for _, spec := range file.ast.Imports {
+				importPath := strings.Trim(spec.Path.Value, "\"`")
+				for _, rule := range report.Rules {
+					if !matches(importPath, rule.Imported) {
+						continue
+					}
+					violated := matches(pkg.importPath, rule.Importers)
+					if rule.Only {
+						// Packages within the imported packages may import each other
+						violated = !violated && !matches(pkg.importPath, rule.Imported)
+					}
+					if violated {
+						report.Violations = append(report.Violations, ImportViolation{
+							Rule:     rule.Text,
+							Importer: pkg.importPath,
+							Import:   importPath,
+							File:     module.relPath(file.path),
+							Line:     file.fset.Position(spec.Pos()).Line,
+						})
+					}
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+func (report *ImportRulesReport) String() string {
+	if len(report.Violations) == 0 {
+		return fmt.Sprintf("No imports of %s violate the rules", report.Module)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d imports of %s violate the rules:\n", len(report.Violations), report.Module)
+	for _, rule := range report.Rules {
+		var violations []ImportViolation
+		for _, violation := range report.Violations {
+			if violation.Rule == rule.Text {
+				violations = append(violations, violation)
+			}
+		}
+		if len(violations) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", rule.Text, len(violations))
+		for _, violation := range violations {
+			fmt.Fprintf(&b, "  %s:%d: %s imports %s\n", violation.File, violation.Line, violation.Importer, violation.Import)
+		}
+	}
+	return b.String()
+}
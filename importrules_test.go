@@ -0,0 +1,166 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestImportRules(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module whose domain package imports the HTTP layer and
+	// whose API package imports the boot package reserved for commands
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"cmd/app/main.go": {
+				"package main",
+				"",
+				"import \"example.com/app/internal/boot\"",
+				"",
+				"func main() { boot.Run() }",
+				"",
+			},
+			"internal/boot/boot.go": {
+				"package boot",
+				"",
+				"import \"example.com/app/internal/boot/config\"",
+				"",
+				"func Run() { config.Load() }",
+				"",
+			},
+			"internal/boot/config/config.go": {"package config", "", "func Load() {}", ""},
+			"pkg/domain/user.go": {
+				"package domain",                         // 1
+				"",                                       // 2
+				"import (",                               // 3
+				"	\"net/http\"",                          // 4
+				"",                                       // 5
+				"	\"example.com/app/pkg/http/handlers\"", // 6
+				")",                                      // 7
+				"",                                       // 8
+				"var _ = http.StatusOK",                  // 9
+				"var _ = handlers.Handle",                // 10
+				"",                                       // 11
+			},
+			"pkg/domain/user_test.go": {
+				"package domain",
+				"",
+				"import \"example.com/app/internal/boot\"",
+				"",
+				"var _ = boot.Run",
+				"",
+			},
+			"pkg/http/handlers/handlers.go": {
+				"package handlers",
+				"",
+				"import \"example.com/app/internal/boot\"",
+				"",
+				"func Handle() { boot.Run() }",
+				"",
+			},
+		})
+	}
+
+	t.Run("parsing rules", func(t *testing.T) {
+		t.Parallel()
+		for text, expected := range map[string]ImportRule{
+			"pkg/domain must not import pkg/http": {
+				Text: "pkg/domain must not import pkg/http", Importers: []string{"pkg/domain"}, Imported: []string{"pkg/http"},
+			},
+			"Only  cmd/..., ./tools MAY import internal/Boot/": {
+				Text: "Only cmd/..., ./tools MAY import internal/Boot/", Importers: []string{"cmd", "tools"}, Imported: []string{"internal/Boot"}, Only: true,
+			},
+			"pkg/domain cannot import net/http, database/sql": {
+				Text: "pkg/domain cannot import net/http, database/sql", Importers: []string{"pkg/domain"}, Imported: []string{"net/http", "database/sql"},
+			},
+		} {
+			rule, err := ParseImportRule(text)
+			if err != nil {
+				t.Errorf("Failed to parse %q: %v", text, err)
+				continue
+			}
+			if rule.Text != expected.Text || rule.Only != expected.Only ||
+				strings.Join(rule.Importers, "|") != strings.Join(expected.Importers, "|") ||
+				strings.Join(rule.Imported, "|") != strings.Join(expected.Imported, "|") {
+				t.Errorf("Expected %q to parse to %+v, got %+v", text, expected, rule)
+			}
+		}
+
+		for _, text := range []string{"pkg/domain imports pkg/http", "only may import internal/boot", "pkg/domain must not import"} {
+			if _, err := ParseImportRule(text); err == nil {
+				t.Errorf("Expected an error for %q", text)
+			}
+		}
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		report, err := CheckImportRules(workspace, []string{
+			"pkg/domain must not import pkg/http, net/http",
+			"only cmd may import internal/boot",
+			"cmd must not import pkg",
+		}, false)
+		if err != nil {
+			t.Fatalf("Failed to check import rules: %v", err)
+		}
+		expected := strings.Join([]string{
+			"3 imports of example.com/app violate the rules:",
+			"",
+			"pkg/domain must not import pkg/http, net/http (2):",
+			"  pkg/domain/user.go:4: example.com/app/pkg/domain imports net/http",
+			"  pkg/domain/user.go:6: example.com/app/pkg/domain imports example.com/app/pkg/http/handlers",
+			"",
+			"only cmd may import internal/boot (1):",
+			"  pkg/http/handlers/handlers.go:3: example.com/app/pkg/http/handlers imports example.com/app/internal/boot",
+			"",
+		}, "\n")
+		if report.String() != expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", expected, report.String())
+		}
+
+		// Test files only follow the rules when included
+		report, err = CheckImportRules(workspace, []string{"only cmd may import internal/boot"}, true)
+		if err != nil {
+			t.Fatalf("Failed to check import rules: %v", err)
+		}
+		if len(report.Violations) != 2 || report.Violations[0].File != filepath.Join("pkg", "domain", "user_test.go") {
+			t.Errorf("Expected the test import to violate the rule, got %+v", report.Violations)
+		}
+	})
+
+	t.Run("import rules tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		call := func(arguments map[string]any) mcp.CallToolResult {
+			encoded, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      1,
+				"method":  string(mcp.MethodToolsCall),
+				"params":  map[string]any{"name": importRulesToolName, "arguments": arguments},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+			return response.Result.(mcp.CallToolResult)
+		}
+
+		result := call(map[string]any{"workspace_dir": workspace, "rules": []any{"internal must not import pkg"}})
+		if text := toolResultText(&result); result.IsError || text != "No imports of example.com/app violate the rules" {
+			t.Errorf("Expected no violations, got: %s", text)
+		}
+
+		result = call(map[string]any{"workspace_dir": workspace, "rules": []any{"internal imports pkg"}})
+		if text := toolResultText(&result); !result.IsError || !strings.Contains(text, `invalid rule "internal imports pkg"`) {
+			t.Errorf("Expected an invalid rule error, got: %s", text)
+		}
+	})
+}
@@ -0,0 +1,332 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	initOrderToolName        = "init_order"
+	initOrderToolDescription = `Explains the initialization order of a Go package: the package level variables in the dependency order the compiler initializes them with the variables each depends on, directly or through the functions its initializer calls, followed by the init functions in execution order and the variables they assign. Imported packages are initialized before.
+
+Warns when a variable is initialized from a variable that is only assigned in an init function, which is still the zero value at that time. Pass variable to focus on one variable and the variables it depends on, to debug nil-at-init and ordering bugs.`
+)
+
+func AddInitOrderTool(mcpServer *server.MCPServer) {
+	handleInitOrder := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		path, err := request.RequireString("path")
+		if err != nil {
+			return nil, err
+		}
+		variable := request.GetString("variable", "")
+
+		report, err := InitOrder(ctx, path, variable)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error explaining initialization order: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		initOrderToolName,
+		mcp.WithDescription(initOrderToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of the package directory or of a Go file of the package"),
+			mcp.Required(),
+		),
+		mcp.WithString("variable",
+			mcp.Description("Package level variable to explain the initialization of"),
+		),
+	), handleInitOrder)
+}
+
+// InitOrderReport describes the initialization of a package
+type InitOrderReport struct {
+	Package string `json:"package"`
+	// Imports are the imported packages, initialized before the package
+	Imports []string `json:"imports,omitempty"`
+	// Variables lists the variables with initializers in initialization order
+	Variables []InitStep `json:"variables,omitempty"`
+	// Uninitialized lists the variables without initializer, zero until assigned
+	Uninitialized []string   `json:"uninitialized,omitempty"`
+	InitFuncs     []InitFunc `json:"init_funcs,omitempty"`
+	Warnings      []string   `json:"warnings,omitempty"`
+	// Variable is the explained variable, the other fields only hold what it depends on
+	Variable string `json:"variable,omitempty"`
+}
+
+// InitStep is the initialization of package level variables by one initializer
+type InitStep struct {
+	// Order is the 1-based position of the step in the initialization of the package
+	Order      int      `json:"order"`
+	Names      []string `json:"names"`
+	Position   string   `json:"position"`
+	Expression string   `json:"expression"`
+	// DependsOn lists the package level variables the initializer uses, directly or
+	// through the functions it calls
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// InitFunc is an init function of the package
+type InitFunc struct {
+	Position string `json:"position"`
+	// Assigns lists the package level variables the function assigns
+	Assigns []string `json:"assigns,omitempty"`
+}
+
+// initExpressionMaxLength is the length initializer expressions are truncated to
+const initExpressionMaxLength = 80
+
+// InitOrder explains the initialization order of the package at path, a package directory
+// or a Go file of the package, or of the variable named variable when it is not empty
+func InitOrder(ctx context.Context, path string, variable string) (*InitOrderReport, error) {
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path must be an absolute path, got: %s", path)
+	}
+	filePath := path
+	if info, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to access %s: %w", path, err)
+	} else if info.IsDir() {
+		files, err := bodySourceFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		files = slices.DeleteFunc(files, func(file string) bool { return strings.HasSuffix(file, "_test.go") })
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no Go files in %s", path)
+		}
+		filePath = files[0]
+	}
+	pkg, _, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.Errors) > 0 {
+		return nil, classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+	info := pkg.TypesInfo
+	scope := pkg.Types.Scope()
+	if variable != "" {
+		if _, ok := scope.Lookup(variable).(*types.Var); !ok {
+			return nil, classifyErrorf(ErrSymbolNotFound, "no package level variable '%s' in %s", variable, pkg.PkgPath)
+		}
+	}
+	position := func(pos token.Pos) string {
+		position := pkg.Fset.Position(pos)
+		return fmt.Sprintf("%s:%d", filepath.Base(position.Filename), position.Line)
+	}
+
+	funcDecls := make(map[*types.Func]*ast.FuncDecl)
+	var initDecls []*ast.FuncDecl
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				if fn.Recv == nil && fn.Name.Name == "init" {
+					initDecls = append(initDecls, fn)
+				} else if obj, ok := info.Defs[fn.Name].(*types.Func); ok {
+					funcDecls[obj] = fn
+				}
+			}
+		}
+	}
+	isPackageVar := func(obj types.Object) bool {
+		v, ok := obj.(*types.Var)
+		return ok && !v.IsField() && v.Parent() == scope
+	}
+	// dependencies collects the package level variables used by the node, following the
+	// functions and methods of the package it refers to
+	visited := make(map[*types.Func]bool)
+	var dependencies func(node ast.Node, found map[string]bool)
+	dependencies = func(node ast.Node, found map[string]bool) {
+		ast.Inspect(node, func(node ast.Node) bool {
+			ident, ok := node.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			obj := info.Uses[ident]
+			if isPackageVar(obj) {
+				found[obj.Name()] = true
+			} else if fn, ok := obj.(*types.Func); ok && funcDecls[fn] != nil && !visited[fn] {
+				visited[fn] = true
+				dependencies(funcDecls[fn].Body, found)
+			}
+			return true
+		})
+	}
+
+	report := &InitOrderReport{Package: pkg.PkgPath, Variable: variable}
+	for _, imported := range pkg.Types.Imports() {
+		report.Imports = append(report.Imports, imported.Path())
+	}
+	sort.Strings(report.Imports)
+	initialized := make(map[string]bool)
+	for i, initializer := range info.InitOrder {
+		step := InitStep{
+			Order:      i + 1,
+			Position:   position(initializer.Lhs[0].Pos()),
+			Expression: types.ExprString(initializer.Rhs),
+		}
+		if len(step.Expression) > initExpressionMaxLength {
+			step.Expression = step.Expression[:initExpressionMaxLength] + "..."
+		}
+		for _, lhs := range initializer.Lhs {
+			step.Names = append(step.Names, lhs.Name())
+			initialized[lhs.Name()] = true
+		}
+		found := make(map[string]bool)
+		clear(visited)
+		dependencies(initializer.Rhs, found)
+		for name := range found {
+			step.DependsOn = append(step.DependsOn, name)
+		}
+		sort.Strings(step.DependsOn)
+		report.Variables = append(report.Variables, step)
+	}
+	for _, name := range scope.Names() {
+		if obj := scope.Lookup(name); isPackageVar(obj) && !initialized[name] && name != "_" {
+			report.Uninitialized = append(report.Uninitialized, name)
+		}
+	}
+
+	assignedInInit := make(map[string]string)
+	for _, decl := range initDecls {
+		initFunc := InitFunc{Position: position(decl.Pos())}
+		assigned := make(map[string]bool)
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			var targets []ast.Expr
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				targets = node.Lhs
+			case *ast.IncDecStmt:
+				targets = []ast.Expr{node.X}
+			}
+			for _, target := range targets {
+				if ident := rootIdent(target); ident != nil && isPackageVar(info.Uses[ident]) && !assigned[ident.Name] {
+					assigned[ident.Name] = true
+					initFunc.Assigns = append(initFunc.Assigns, ident.Name)
+					if _, ok := assignedInInit[ident.Name]; !ok {
+						assignedInInit[ident.Name] = initFunc.Position
+					}
+				}
+			}
+			return true
+		})
+		report.InitFuncs = append(report.InitFuncs, initFunc)
+	}
+	for _, step := range report.Variables {
+		for _, dependency := range step.DependsOn {
+			if at, ok := assignedInInit[dependency]; ok && !initialized[dependency] {
+				report.Warnings = append(report.Warnings, fmt.Sprintf(
+					"%s uses %s, which is only assigned in init() at %s and still the zero value when %s is initialized",
+					strings.Join(step.Names, ", "), dependency, at, strings.Join(step.Names, ", "),
+				))
+			}
+		}
+	}
+
+	if variable != "" {
+		report.focus(variable)
+	}
+	return report, nil
+}
+
+// focus keeps only the initialization of the variable and of the variables it depends on
+func (report *InitOrderReport) focus(variable string) {
+	relevant := map[string]bool{variable: true}
+	for i := len(report.Variables) - 1; i >= 0; i-- {
+		step := report.Variables[i]
+		if slices.ContainsFunc(step.Names, func(name string) bool { return relevant[name] }) {
+			for _, dependency := range step.DependsOn {
+				relevant[dependency] = true
+			}
+		}
+	}
+	report.Variables = slices.DeleteFunc(report.Variables, func(step InitStep) bool {
+		return !slices.ContainsFunc(step.Names, func(name string) bool { return relevant[name] })
+	})
+	report.Uninitialized = slices.DeleteFunc(report.Uninitialized, func(name string) bool { return !relevant[name] })
+	report.InitFuncs = slices.DeleteFunc(report.InitFuncs, func(initFunc InitFunc) bool {
+		return !slices.ContainsFunc(initFunc.Assigns, func(name string) bool { return relevant[name] })
+	})
+	report.Warnings = slices.DeleteFunc(report.Warnings, func(warning string) bool {
+		name, _, _ := strings.Cut(warning, " uses ")
+		return !slices.ContainsFunc(strings.Split(name, ", "), func(name string) bool { return relevant[name] })
+	})
+}
+
+// rootIdent returns the variable an assignment target is rooted at, e.g. m of m[k].f
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch current := expr.(type) {
+		case *ast.Ident:
+			return current
+		case *ast.SelectorExpr:
+			expr = current.X
+		case *ast.IndexExpr:
+			expr = current.X
+		case *ast.StarExpr:
+			expr = current.X
+		case *ast.ParenExpr:
+			expr = current.X
+		default:
+			return nil
+		}
+	}
+}
+
+// String formats the report as human and model readable text
+func (report *InitOrderReport) String() string {
+	var b strings.Builder
+	if report.Variable != "" {
+		fmt.Fprintf(&b, "Initialization of %s in package %s\n", report.Variable, report.Package)
+	} else {
+		fmt.Fprintf(&b, "Initialization of package %s\n", report.Package)
+	}
+	if len(report.Imports) > 0 {
+		fmt.Fprintf(&b, "Imported packages are initialized first: %s\n", strings.Join(report.Imports, ", "))
+	}
+	if len(report.Variables) > 0 {
+		b.WriteString("\nVariables, in initialization order:\n")
+		for _, step := range report.Variables {
+			fmt.Fprintf(&b, "  %d. %s (%s) = %s\n", step.Order, strings.Join(step.Names, ", "), step.Position, step.Expression)
+			if len(step.DependsOn) > 0 {
+				fmt.Fprintf(&b, "     depends on: %s\n", strings.Join(step.DependsOn, ", "))
+			}
+		}
+	}
+	if len(report.Uninitialized) > 0 {
+		fmt.Fprintf(&b, "\nVariables without initializer, zero until assigned: %s\n", strings.Join(report.Uninitialized, ", "))
+	}
+	if len(report.InitFuncs) > 0 {
+		b.WriteString("\ninit functions, in execution order after the variables:\n")
+		for i, initFunc := range report.InitFuncs {
+			fmt.Fprintf(&b, "  %d. %s", i+1, initFunc.Position)
+			if len(initFunc.Assigns) > 0 {
+				fmt.Fprintf(&b, " assigns %s", strings.Join(initFunc.Assigns, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(report.Warnings) > 0 {
+		b.WriteString("\nWarnings:\n")
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(&b, "  - %s\n", warning)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestInitOrder(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a package whose variables depend on each other and on init
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"a.go": {
+				"package main",       // 1
+				"",                   // 2
+				"import \"strings\"", // 3
+				"",                   // 4
+				"var greeting = strings.ToUpper(name) + suffix()", // 5
+				"",                            // 6
+				"var name = \"gopher\"",       // 7
+				"",                            // 8
+				"var registry map[string]int", // 9
+				"",                            // 10
+				"var count = len(registry)",   // 11
+				"",                            // 12
+				"func suffix() string { return punctuation }", // 13
+				"",                              // 14
+				"func init() {",                 // 15
+				"\tregistry = map[string]int{}", // 16
+				"}",                             // 17
+				"",                              // 18
+			},
+			"b.go": {
+				"package main",            // 1
+				"",                        // 2
+				"var punctuation = \"!\"", // 3
+				"",                        // 4
+				"func init() {",           // 5
+				"\tregistry[name]++",      // 6
+				"}",                       // 7
+				"",                        // 8
+				"func main() {}",          // 9
+				"",                        // 10
+			},
+		})
+	}
+
+	t.Run("package", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := InitOrder(context.Background(), dir, "")
+		if err != nil {
+			t.Fatalf("Failed to explain initialization order: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Initialization of package example.com/app",
+			"Imported packages are initialized first: strings",
+			"",
+			"Variables, in initialization order:",
+			"  1. name (a.go:7) = \"gopher\"",
+			"  2. count (a.go:11) = len(registry)",
+			"     depends on: registry",
+			"  3. punctuation (b.go:3) = \"!\"",
+			"  4. greeting (a.go:5) = strings.ToUpper(name) + suffix()",
+			"     depends on: name, punctuation",
+			"",
+			"Variables without initializer, zero until assigned: registry",
+			"",
+			"init functions, in execution order after the variables:",
+			"  1. a.go:15 assigns registry",
+			"  2. b.go:5 assigns registry",
+			"",
+			"Warnings:",
+			"  - count uses registry, which is only assigned in init() at a.go:15 and still the zero value when count is initialized",
+			"",
+		}, "\n")
+		if got := report.String(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("variable", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := InitOrder(context.Background(), filepath.Join(dir, "a.go"), "greeting")
+		if err != nil {
+			t.Fatalf("Failed to explain initialization order: %v", err)
+		}
+		var names []string
+		for _, step := range report.Variables {
+			names = append(names, step.Names...)
+		}
+		if got := strings.Join(names, ","); got != "name,punctuation,greeting" {
+			t.Errorf("Expected the variables greeting depends on, got %s", got)
+		}
+		if len(report.InitFuncs) != 0 || len(report.Warnings) != 0 {
+			t.Errorf("Expected no init functions or warnings, got %v %v", report.InitFuncs, report.Warnings)
+		}
+
+		if _, err := InitOrder(context.Background(), dir, "missing"); err == nil {
+			t.Error("Expected an error for an unknown variable")
+		}
+	})
+
+	t.Run("init order tool", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      initOrderToolName,
+				"arguments": map[string]any{"path": dir, "variable": "count"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "count uses registry") {
+			t.Errorf("Expected the warning about registry, got: %s", text)
+		}
+	})
+}
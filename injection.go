@@ -0,0 +1,827 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	diGraphToolName        = "di_graph"
+	diGraphToolDescription = `Renders the dependency injection graphs of google/wire injectors and uber/fx applications, which are otherwise only checked by wire generation or when the application starts.
+
+For every wire.Build injector and fx.New call, the providers reachable through provider sets, fx.Options and fx.Module are listed with the types they require and provide, followed by the types that no provider provides. wire.Struct, wire.Bind, wire.Value, wire.InterfaceValue, wire.FieldsOf, fx.Supply, fx.Annotate and fx.In/fx.Out parameter structs are understood; fx.Invoke functions are listed as consumers.
+
+When the packages have no injector or application, the package-level provider sets and fx options are rendered on their own.`
+)
+
+// Import paths of the supported dependency injection frameworks
+const (
+	wireImportPath = "github.com/google/wire"
+	fxImportPath   = "go.uber.org/fx"
+)
+
+// InjectionGraphs are the dependency injection graphs found in packages
+type InjectionGraphs struct {
+	Graphs []InjectionGraph `json:"graphs"`
+}
+
+// InjectionGraph is a wire injector or fx application with its providers
+type InjectionGraph struct {
+	// Framework is wire or fx
+	Framework string `json:"framework"`
+	// Name is the injector function, or the function or variable creating the graph
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Inputs are the parameters of a wire injector
+	Inputs []string `json:"inputs,omitempty"`
+	// Outputs are the types built by a wire injector
+	Outputs   []string            `json:"outputs,omitempty"`
+	Providers []InjectionProvider `json:"providers"`
+	Missing   []MissingDependency `json:"missing,omitempty"`
+	// Set is set for a package-level provider set or fx option on its own, whose missing
+	// types are left to the injectors and applications using it
+	Set bool `json:"set,omitempty"`
+}
+
+// InjectionProvider is a provider of a graph or a consumer invoked by fx
+type InjectionProvider struct {
+	Name     string   `json:"name"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Requires []string `json:"requires,omitempty"`
+	Provides []string `json:"provides,omitempty"`
+	// Invoked is set for fx.Invoke functions, which only consume dependencies
+	Invoked bool `json:"invoked,omitempty"`
+}
+
+// MissingDependency is a required type no provider of the graph provides
+type MissingDependency struct {
+	Type       string   `json:"type"`
+	RequiredBy []string `json:"required_by"`
+}
+
+func AddDIGraphTool(mcpServer *server.MCPServer) {
+	handleDIGraph := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		pattern := request.GetString("package", "")
+		if pattern == "" {
+			pattern = "./..."
+		}
+
+		graphs, err := FindInjectionGraphs(ctx, workspaceDir, pattern)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding dependency injection graphs: %v", err)), nil
+		}
+		return mcp.NewToolResultText(graphs.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		diGraphToolName,
+		mcp.WithDescription(diGraphToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of the directory the packages are resolved from"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Package pattern to search for injectors and applications, e.g. ./cmd/server"),
+			mcp.DefaultString("./..."),
+		),
+	), handleDIGraph)
+}
+
+// injectionScanner resolves the arguments of wire and fx calls to providers, following
+// provider sets and options declared in the loaded packages
+type injectionScanner struct {
+	fset   *token.FileSet
+	module *moduleSources
+	// variables and functions are the package-level declarations of the loaded packages,
+	// with the package declaring them
+	variables map[*types.Var]declaredExpr
+	functions map[*types.Func]declaredFunc
+}
+
+type declaredExpr struct {
+	expr ast.Expr
+	pkg  *packages.Package
+}
+
+type declaredFunc struct {
+	decl *ast.FuncDecl
+	pkg  *packages.Package
+}
+
+// injectionGraph is a graph while it is being built
+type injectionGraph struct {
+	graph InjectionGraph
+	// provided and required hold the types behind the strings of the providers
+	provided []types.Type
+	required []requiredType
+	visited  map[types.Object]bool
+	// object is the variable of a package-level set or option
+	object types.Object
+}
+
+type requiredType struct {
+	t  types.Type
+	by string
+}
+
+// FindInjectionGraphs type checks the packages matching pattern and renders the wire
+// injectors and fx applications found in them
+func FindInjectionGraphs(ctx context.Context, workspaceDir string, pattern string) (*InjectionGraphs, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Context: ctx,
+		Dir:     workspaceDir,
+		Env:     packagesEnv(workspaceDir),
+		// wire injectors are only compiled with the wireinject build tag
+		BuildFlags: []string{"-tags=wireinject"},
+	}, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pattern, err)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages match %s", pattern)
+	}
+
+	scanner := &injectionScanner{
+		fset:      pkgs[0].Fset,
+		module:    module,
+		variables: make(map[*types.Var]declaredExpr),
+		functions: make(map[*types.Func]declaredFunc),
+	}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.TypesInfo == nil {
+			return
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					if function, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func); ok {
+						scanner.functions[function] = declaredFunc{decl: decl, pkg: pkg}
+					}
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						valueSpec, ok := spec.(*ast.ValueSpec)
+						if !ok || len(valueSpec.Values) != len(valueSpec.Names) {
+							continue
+						}
+						for i, name := range valueSpec.Names {
+							if variable, ok := pkg.TypesInfo.Defs[name].(*types.Var); ok {
+								scanner.variables[variable] = declaredExpr{expr: valueSpec.Values[i], pkg: pkg}
+							}
+						}
+					}
+				}
+			}
+		}
+	})
+
+	result := &InjectionGraphs{}
+	// Package-level sets and options are only rendered without injectors and applications
+	var sets []*injectionGraph
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					if decl.Body == nil {
+						continue
+					}
+					ast.Inspect(decl.Body, func(node ast.Node) bool {
+						call, ok := node.(*ast.CallExpr)
+						if !ok {
+							return true
+						}
+						switch frameworkFunc(pkg, call) {
+						case "wire.Build":
+							graph := scanner.wireInjector(pkg, decl, call)
+							result.Graphs = append(result.Graphs, graph.finish())
+							return false
+						case "fx.New":
+							graph := scanner.newGraph("fx", funcDeclName(decl), call.Pos())
+							scanner.addFxOptions(graph, pkg, call.Args)
+							result.Graphs = append(result.Graphs, graph.finish())
+							return false
+						}
+						return true
+					})
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						valueSpec, ok := spec.(*ast.ValueSpec)
+						if !ok || len(valueSpec.Values) != len(valueSpec.Names) {
+							continue
+						}
+						for i, name := range valueSpec.Names {
+							call, ok := ast.Unparen(valueSpec.Values[i]).(*ast.CallExpr)
+							if !ok {
+								continue
+							}
+							object := pkg.TypesInfo.Defs[name]
+							switch framework := frameworkFunc(pkg, call); framework {
+							case "wire.NewSet":
+								graph := scanner.newGraph("wire", name.Name, name.Pos())
+								graph.graph.Set = true
+								graph.object = object
+								graph.visited[object] = true
+								scanner.addWireProviders(graph, pkg, call.Args)
+								sets = append(sets, graph)
+							case "fx.Options", "fx.Module":
+								graph := scanner.newGraph("fx", name.Name, name.Pos())
+								graph.graph.Set = true
+								graph.object = object
+								graph.visited[object] = true
+								scanner.addFxOptions(graph, pkg, call.Args)
+								sets = append(sets, graph)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(result.Graphs) == 0 {
+		// Sets included by other sets are part of their graph
+		for _, set := range sets {
+			included := slices.ContainsFunc(sets, func(other *injectionGraph) bool {
+				return other != set && other.visited[set.object]
+			})
+			if !included {
+				result.Graphs = append(result.Graphs, set.finish())
+			}
+		}
+	}
+	return result, nil
+}
+
+// frameworkFunc returns the qualified name of a wire or fx function called by call,
+// e.g. wire.Build, or an empty string for other calls
+func frameworkFunc(pkg *packages.Package, call *ast.CallExpr) string {
+	function, ok := typeutil.Callee(pkg.TypesInfo, call).(*types.Func)
+	if !ok || function.Pkg() == nil {
+		return ""
+	}
+	switch function.Pkg().Path() {
+	case wireImportPath:
+		return "wire." + function.Name()
+	case fxImportPath:
+		return "fx." + function.Name()
+	}
+	return ""
+}
+
+// funcDeclName returns the name of a function, Receiver.Name for methods
+func funcDeclName(decl *ast.FuncDecl) string {
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		return receiverTypeName(decl.Recv.List[0].Type) + "." + decl.Name.Name
+	}
+	return decl.Name.Name
+}
+
+func (scanner *injectionScanner) newGraph(framework string, name string, pos token.Pos) *injectionGraph {
+	position := scanner.fset.Position(pos)
+	return &injectionGraph{
+		graph: InjectionGraph{
+			Framework: framework,
+			Name:      name,
+			File:      scanner.module.relPath(position.Filename),
+			Line:      position.Line,
+		},
+		visited: make(map[types.Object]bool),
+	}
+}
+
+// wireInjector builds the graph of an injector function calling wire.Build
+func (scanner *injectionScanner) wireInjector(pkg *packages.Package, decl *ast.FuncDecl, call *ast.CallExpr) *injectionGraph {
+	graph := scanner.newGraph("wire", funcDeclName(decl), decl.Name.Pos())
+	if function, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func); ok {
+		signature := function.Type().(*types.Signature)
+		for param := range signature.Params().Variables() {
+			graph.graph.Inputs = append(graph.graph.Inputs, graph.typeString(param.Type()))
+			graph.provided = append(graph.provided, param.Type())
+		}
+		for _, result := range providedResults(signature) {
+			graph.graph.Outputs = append(graph.graph.Outputs, graph.typeString(result))
+			graph.required = append(graph.required, requiredType{t: result, by: graph.graph.Name})
+		}
+	}
+	scanner.addWireProviders(graph, pkg, call.Args)
+	return graph
+}
+
+// addWireProviders adds the providers passed to wire.Build or wire.NewSet
+func (scanner *injectionScanner) addWireProviders(graph *injectionGraph, pkg *packages.Package, args []ast.Expr) {
+	for _, arg := range args {
+		arg = ast.Unparen(arg)
+		call, isCall := arg.(*ast.CallExpr)
+		if !isCall {
+			scanner.addReference(graph, pkg, arg, scanner.addWireProviders, false)
+			continue
+		}
+		newType := func(i int) types.Type {
+			if i >= len(call.Args) {
+				return nil
+			}
+			// The type arguments are given as new(T)
+			if pointer, ok := pkg.TypesInfo.TypeOf(call.Args[i]).(*types.Pointer); ok {
+				return pointer.Elem()
+			}
+			return nil
+		}
+		position := scanner.fset.Position(call.Pos())
+		provider := InjectionProvider{
+			Name: types.ExprString(call),
+			File: scanner.module.relPath(position.Filename),
+			Line: position.Line,
+		}
+		var provides, requires []types.Type
+		switch frameworkFunc(pkg, call) {
+		case "wire.NewSet":
+			scanner.addWireProviders(graph, pkg, call.Args)
+			continue
+		case "wire.Struct":
+			structType := newType(0)
+			if structType == nil {
+				continue
+			}
+			provides = append(provides, structType)
+			if _, ok := structType.(*types.Pointer); !ok {
+				provides = append(provides, types.NewPointer(structType))
+			}
+			requires = structFieldTypes(structType, call.Args[1:], pkg.TypesInfo)
+		case "wire.Bind":
+			if iface, impl := newType(0), newType(1); iface != nil && impl != nil {
+				provides, requires = []types.Type{iface}, []types.Type{impl}
+			}
+		case "wire.Value":
+			if len(call.Args) == 1 {
+				provides = append(provides, pkg.TypesInfo.TypeOf(call.Args[0]))
+			}
+		case "wire.InterfaceValue":
+			if iface := newType(0); iface != nil {
+				provides = append(provides, iface)
+			}
+		case "wire.FieldsOf":
+			structType := newType(0)
+			if structType == nil {
+				continue
+			}
+			requires = append(requires, structType)
+			for _, field := range structFieldTypes(structType, call.Args[1:], pkg.TypesInfo) {
+				provides = append(provides, field, types.NewPointer(field))
+			}
+		default:
+			// A call returning a provider set or a provider function
+			if !scanner.addReturned(graph, pkg, call, scanner.addWireProviders) {
+				scanner.addProviderType(graph, pkg, call, false)
+			}
+			continue
+		}
+		graph.add(provider, provides, requires)
+	}
+}
+
+// addFxOptions adds the providers of the options passed to fx.New, fx.Options and fx.Module
+func (scanner *injectionScanner) addFxOptions(graph *injectionGraph, pkg *packages.Package, args []ast.Expr) {
+	for _, arg := range args {
+		arg = ast.Unparen(arg)
+		call, isCall := arg.(*ast.CallExpr)
+		if !isCall {
+			scanner.addReference(graph, pkg, arg, scanner.addFxOptions, false)
+			continue
+		}
+		switch frameworkFunc(pkg, call) {
+		case "fx.Options":
+			scanner.addFxOptions(graph, pkg, call.Args)
+		case "fx.Module":
+			if len(call.Args) > 0 {
+				scanner.addFxOptions(graph, pkg, call.Args[1:])
+			}
+		case "fx.Provide":
+			for _, constructor := range call.Args {
+				scanner.addFxConstructor(graph, pkg, constructor, false)
+			}
+		case "fx.Invoke":
+			for _, function := range call.Args {
+				scanner.addFxConstructor(graph, pkg, function, true)
+			}
+		case "fx.Supply":
+			for _, value := range call.Args {
+				position := scanner.fset.Position(value.Pos())
+				graph.add(InjectionProvider{
+					Name: "fx.Supply(" + types.ExprString(value) + ")",
+					File: scanner.module.relPath(position.Filename),
+					Line: position.Line,
+				}, []types.Type{pkg.TypesInfo.TypeOf(value)}, nil)
+			}
+		case "":
+			scanner.addReturned(graph, pkg, call, scanner.addFxOptions)
+		}
+	}
+}
+
+// addFxConstructor adds a function passed to fx.Provide or fx.Invoke
+func (scanner *injectionScanner) addFxConstructor(graph *injectionGraph, pkg *packages.Package, expr ast.Expr, invoked bool) {
+	expr = ast.Unparen(expr)
+	if call, ok := expr.(*ast.CallExpr); ok {
+		switch frameworkFunc(pkg, call) {
+		case "fx.Annotate":
+			// The annotations name and group the types, the function still provides them
+			if len(call.Args) > 0 {
+				scanner.addFxConstructor(graph, pkg, call.Args[0], invoked)
+			}
+			return
+		case "fx.Annotated":
+			return
+		}
+	}
+	if _, isCall := expr.(*ast.CallExpr); !isCall {
+		if scanner.addReference(graph, pkg, expr, nil, invoked) {
+			return
+		}
+	}
+	scanner.addProviderType(graph, pkg, expr, invoked)
+}
+
+// addReference adds a provider function or follows a provider set or option variable
+// referenced by expr. It reports whether expr was a reference.
+func (scanner *injectionScanner) addReference(
+	graph *injectionGraph,
+	pkg *packages.Package,
+	expr ast.Expr,
+	addSet func(*injectionGraph, *packages.Package, []ast.Expr),
+	invoked bool,
+) bool {
+	var ident *ast.Ident
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident = expr.Sel
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// Instantiated generic provider
+		scanner.addProviderType(graph, pkg, expr, invoked)
+		return true
+	default:
+		return false
+	}
+	switch object := pkg.TypesInfo.Uses[ident].(type) {
+	case *types.Var:
+		declared, ok := scanner.variables[object]
+		if !ok || addSet == nil {
+			return false
+		}
+		if !graph.visited[object] {
+			graph.visited[object] = true
+			addSet(graph, declared.pkg, []ast.Expr{declared.expr})
+		}
+		return true
+	case *types.Func:
+		scanner.addProviderType(graph, pkg, expr, invoked)
+		return true
+	}
+	return false
+}
+
+// addReturned follows a call to a function of the loaded packages returning a provider
+// set or options, adding what its return statements return. It reports whether the
+// function was found.
+func (scanner *injectionScanner) addReturned(
+	graph *injectionGraph,
+	pkg *packages.Package,
+	call *ast.CallExpr,
+	addSet func(*injectionGraph, *packages.Package, []ast.Expr),
+) bool {
+	function, ok := typeutil.Callee(pkg.TypesInfo, call).(*types.Func)
+	if !ok {
+		return false
+	}
+	declared, ok := scanner.functions[function.Origin()]
+	if !ok || declared.decl.Body == nil {
+		return false
+	}
+	if graph.visited[function] {
+		return true
+	}
+	graph.visited[function] = true
+	ast.Inspect(declared.decl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			addSet(graph, declared.pkg, node.Results)
+		}
+		return true
+	})
+	return true
+}
+
+// addProviderType adds a provider function by the signature of expr
+func (scanner *injectionScanner) addProviderType(graph *injectionGraph, pkg *packages.Package, expr ast.Expr, invoked bool) {
+	signature, ok := pkg.TypesInfo.TypeOf(expr).Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+	position := scanner.fset.Position(expr.Pos())
+	name := types.ExprString(expr)
+	if _, ok := expr.(*ast.FuncLit); ok {
+		name = "func literal"
+	}
+	// Functions are located at their declaration instead of the reference
+	var ident *ast.Ident
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident = expr.Sel
+	}
+	if ident != nil {
+		if function, ok := pkg.TypesInfo.Uses[ident].(*types.Func); ok && function.Pos().IsValid() {
+			position = scanner.fset.Position(function.Pos())
+		}
+	}
+	provider := InjectionProvider{
+		Name:    name,
+		File:    scanner.module.relPath(position.Filename),
+		Line:    position.Line,
+		Invoked: invoked,
+	}
+
+	var requires []types.Type
+	var optional []string
+	for param := range signature.Params().Variables() {
+		if fields, ok := fxParameterFields(param.Type()); ok {
+			for _, field := range fields {
+				if field.optional {
+					optional = append(optional, graph.typeString(field.t))
+				} else {
+					requires = append(requires, field.t)
+				}
+			}
+			continue
+		}
+		requires = append(requires, param.Type())
+	}
+	var provides []types.Type
+	if !invoked {
+		for _, result := range providedResults(signature) {
+			if fields, ok := fxResultFields(result); ok {
+				provides = append(provides, fields...)
+				continue
+			}
+			provides = append(provides, result)
+		}
+	}
+	graph.add(provider, provides, requires)
+	for _, t := range optional {
+		last := &graph.graph.Providers[len(graph.graph.Providers)-1]
+		last.Requires = append(last.Requires, t+" (optional)")
+	}
+}
+
+// providedResults returns the results of a provider without errors and cleanup functions
+func providedResults(signature *types.Signature) []types.Type {
+	var results []types.Type
+	for result := range signature.Results().Variables() {
+		t := result.Type()
+		if types.Identical(t, types.Universe.Lookup("error").Type()) {
+			continue
+		}
+		if cleanup, ok := t.(*types.Signature); ok && cleanup.Params().Len() == 0 && cleanup.Results().Len() == 0 {
+			continue
+		}
+		results = append(results, t)
+	}
+	return results
+}
+
+// fxField is a field of an fx.In parameter struct
+type fxField struct {
+	t        types.Type
+	optional bool
+}
+
+// fxParameterFields returns the fields of a parameter struct embedding fx.In
+func fxParameterFields(t types.Type) ([]fxField, bool) {
+	structType, ok := embedsFxStruct(t, "In")
+	if !ok {
+		return nil, false
+	}
+	var fields []fxField
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		if field.Embedded() || !field.Exported() {
+			continue
+		}
+		tag := reflect.StructTag(structType.Tag(i))
+		// Value groups are filled by any number of providers, including none
+		if _, ok := tag.Lookup("group"); ok {
+			continue
+		}
+		optional, _ := strconv.ParseBool(tag.Get("optional"))
+		fields = append(fields, fxField{t: field.Type(), optional: optional})
+	}
+	return fields, true
+}
+
+// fxResultFields returns the types provided by a result struct embedding fx.Out
+func fxResultFields(t types.Type) ([]types.Type, bool) {
+	structType, ok := embedsFxStruct(t, "Out")
+	if !ok {
+		return nil, false
+	}
+	var fields []types.Type
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		if !field.Embedded() && field.Exported() {
+			fields = append(fields, field.Type())
+		}
+	}
+	return fields, true
+}
+
+// embedsFxStruct returns the struct of t when it embeds fx.In or fx.Out
+func embedsFxStruct(t types.Type, name string) (*types.Struct, bool) {
+	structType, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil, false
+	}
+	for field := range structType.Fields() {
+		named, ok := types.Unalias(field.Type()).(*types.Named)
+		if field.Embedded() && ok && named.Obj().Pkg() != nil &&
+			named.Obj().Pkg().Path() == fxImportPath && named.Obj().Name() == name {
+			return structType, true
+		}
+	}
+	return nil, false
+}
+
+// structFieldTypes returns the types of the fields of a struct named by the string
+// literals of wire.Struct and wire.FieldsOf, "*" selecting all fields
+func structFieldTypes(t types.Type, names []ast.Expr, info *types.Info) []types.Type {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	structType, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var selected []string
+	for _, name := range names {
+		if value := info.Types[name].Value; value != nil {
+			unquoted, _ := strconv.Unquote(value.ExactString())
+			selected = append(selected, unquoted)
+		}
+	}
+	var fields []types.Type
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		if slices.Contains(selected, field.Name()) ||
+			slices.Contains(selected, "*") && reflect.StructTag(structType.Tag(i)).Get("wire") != "-" {
+			fields = append(fields, field.Type())
+		}
+	}
+	return fields
+}
+
+// typeString formats a type qualified by package names
+func (graph *injectionGraph) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })
+}
+
+// add adds a provider with the types it provides and requires
+func (graph *injectionGraph) add(provider InjectionProvider, provides []types.Type, requires []types.Type) {
+	for _, t := range provides {
+		if t == nil {
+			continue
+		}
+		provider.Provides = append(provider.Provides, graph.typeString(t))
+		graph.provided = append(graph.provided, t)
+	}
+	for _, t := range requires {
+		if t == nil {
+			continue
+		}
+		provider.Requires = append(provider.Requires, graph.typeString(t))
+		graph.required = append(graph.required, requiredType{t: t, by: provider.Name})
+	}
+	graph.graph.Providers = append(graph.graph.Providers, provider)
+}
+
+// finish determines the missing dependencies of the graph
+func (graph *injectionGraph) finish() InjectionGraph {
+	for _, required := range graph.required {
+		if graph.provides(required.t) {
+			continue
+		}
+		name := graph.typeString(required.t)
+		index := slices.IndexFunc(graph.graph.Missing, func(missing MissingDependency) bool { return missing.Type == name })
+		if index < 0 {
+			graph.graph.Missing = append(graph.graph.Missing, MissingDependency{Type: name})
+			index = len(graph.graph.Missing) - 1
+		}
+		if !slices.Contains(graph.graph.Missing[index].RequiredBy, required.by) {
+			graph.graph.Missing[index].RequiredBy = append(graph.graph.Missing[index].RequiredBy, required.by)
+		}
+	}
+	return graph.graph
+}
+
+// provides reports whether a provider of the graph provides t. fx provides its own
+// types, such as fx.Lifecycle, to every application.
+func (graph *injectionGraph) provides(t types.Type) bool {
+	if graph.graph.Framework == "fx" {
+		if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == fxImportPath {
+			return true
+		}
+	}
+	for _, provided := range graph.provided {
+		if types.Identical(provided, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (graphs *InjectionGraphs) String() string {
+	if len(graphs.Graphs) == 0 {
+		return "No wire injectors or provider sets, and no fx applications or options found"
+	}
+	var b strings.Builder
+	for i, graph := range graphs.Graphs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		kind := "wire injector"
+		switch {
+		case graph.Framework == "fx" && graph.Set:
+			kind = "fx option"
+		case graph.Framework == "fx":
+			kind = "fx application"
+		case graph.Set:
+			kind = "wire provider set"
+		}
+		fmt.Fprintf(&b, "%s %s (%s:%d)\n", kind, graph.Name, graph.File, graph.Line)
+		if len(graph.Inputs) > 0 {
+			fmt.Fprintf(&b, "  inputs: %s\n", strings.Join(graph.Inputs, ", "))
+		}
+		if len(graph.Outputs) > 0 {
+			fmt.Fprintf(&b, "  builds: %s\n", strings.Join(graph.Outputs, ", "))
+		}
+		b.WriteString("  providers:\n")
+		for _, provider := range graph.Providers {
+			name := provider.Name
+			if provider.Invoked {
+				name = "invoke " + name
+			}
+			fmt.Fprintf(&b, "    %s", name)
+			if provider.File != "" {
+				fmt.Fprintf(&b, " (%s:%d)", provider.File, provider.Line)
+			}
+			fmt.Fprintf(&b, ": %s -> %s\n", strings.Join(provider.Requires, ", "), strings.Join(provider.Provides, ", "))
+		}
+		switch {
+		case len(graph.Missing) == 0:
+			b.WriteString("  no missing providers\n")
+			continue
+		case graph.Set:
+			b.WriteString("  required from outside the set:\n")
+		default:
+			b.WriteString("  missing providers:\n")
+		}
+		for _, missing := range graph.Missing {
+			fmt.Fprintf(&b, "    %s, required by %s\n", missing.Type, strings.Join(missing.RequiredBy, ", "))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,218 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDIGraph(t *testing.T) {
+	t.Parallel()
+
+	// The frameworks are replaced by stubs with the same API, so no download is needed
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {
+			"module example.com/di",
+			"",
+			"go 1.22",
+			"",
+			"require (",
+			"	github.com/google/wire v0.6.0",
+			"	go.uber.org/fx v1.23.0",
+			")",
+			"",
+			"replace github.com/google/wire => ./stubs/wire",
+			"",
+			"replace go.uber.org/fx => ./stubs/fx",
+			"",
+		},
+		"stubs/wire/go.mod": {"module github.com/google/wire", "", "go 1.22", ""},
+		"stubs/wire/wire.go": {
+			"package wire",
+			"",
+			"type ProviderSet struct{}",
+			"",
+			"func NewSet(...interface{}) ProviderSet { return ProviderSet{} }",
+			"",
+			"func Build(...interface{}) string { return \"\" }",
+			"",
+			"func Bind(iface, to interface{}) struct{} { return struct{}{} }",
+			"",
+			"func Value(interface{}) struct{} { return struct{}{} }",
+			"",
+			"func Struct(structType interface{}, fieldNames ...string) struct{} { return struct{}{} }",
+			"",
+		},
+		"stubs/fx/go.mod": {"module go.uber.org/fx", "", "go 1.22", ""},
+		"stubs/fx/fx.go": {
+			"package fx",
+			"",
+			"type Option interface{}",
+			"",
+			"type App struct{}",
+			"",
+			"func (*App) Run() {}",
+			"",
+			"func New(...Option) *App { return nil }",
+			"",
+			"func Provide(...interface{}) Option { return nil }",
+			"",
+			"func Invoke(...interface{}) Option { return nil }",
+			"",
+			"func Supply(...interface{}) Option { return nil }",
+			"",
+			"func Module(string, ...Option) Option { return nil }",
+			"",
+			"func Annotate(f interface{}, annotations ...interface{}) interface{} { return f }",
+			"",
+			"type In struct{}",
+			"",
+			"type Out struct{}",
+			"",
+			"type Lifecycle interface{}",
+			"",
+		},
+		"app/app.go": {
+			"package app", // 1
+			"",
+			"import \"github.com/google/wire\"", // 3
+			"",
+			"type Config struct{ DSN string }", // 5
+			"",
+			"type DB struct{}", // 7
+			"",
+			"func NewDB(cfg Config) (*DB, func(), error) { return &DB{}, func() {}, nil }", // 9
+			"",
+			"type Logger interface{ Log(string) }", // 11
+			"",
+			"type stdLogger struct{}", // 13
+			"",
+			"func (*stdLogger) Log(string) {}", // 15
+			"",
+			"func newStdLogger() *stdLogger { return &stdLogger{} }", // 17
+			"",
+			"type Cache struct{}", // 19
+			"",
+			"type App struct {", // 21
+			"	DB     *DB",
+			"	Logger Logger",
+			"	Cache  *Cache",
+			"}",
+			"",
+			"var StoreSet = wire.NewSet(NewDB, wire.Bind(new(Logger), new(*stdLogger)), newStdLogger)", // 27
+			"",
+		},
+		"app/wire.go": {
+			"//go:build wireinject", // 1
+			"",
+			"package app", // 3
+			"",
+			"import \"github.com/google/wire\"", // 5
+			"",
+			"func InitializeApp(cfg Config) (*App, error) {",      // 7
+			"	wire.Build(StoreSet, wire.Struct(new(App), \"*\"))", // 8
+			"	return nil, nil",
+			"}",
+			"",
+		},
+		"cmd/server/main.go": {
+			"package main", // 1
+			"",
+			"import (", // 3
+			"	\"example.com/di/app\"",
+			"	\"go.uber.org/fx\"",
+			")",
+			"",
+			"type Metrics struct{}", // 8
+			"",
+			"type Server struct{}", // 10
+			"",
+			"type Params struct {", // 12
+			"	fx.In",
+			"	DB      *app.DB",
+			"	Metrics *Metrics `optional:\"true\"`",
+			"}",
+			"",
+			"func NewServer(p Params, lc fx.Lifecycle) *Server { return &Server{} }", // 18
+			"",
+			"type Handler interface{}", // 20
+			"",
+			"type Result struct {", // 22
+			"	fx.Out",
+			"	Handler Handler",
+			"}",
+			"",
+			"func NewHandler(s *Server) Result { return Result{} }", // 27
+			"",
+			"var Module = fx.Module(\"server\", fx.Provide(NewServer, fx.Annotate(NewHandler)))", // 29
+			"",
+			"func main() {", // 31
+			"	fx.New(Module, fx.Supply(app.Config{}), fx.Invoke(func(h Handler, c *app.Cache) {})).Run()", // 32
+			"}",
+			"",
+		},
+	})
+
+	// The subtests run in order, as the second one removes the injector
+	t.Run("wire and fx graphs", func(t *testing.T) {
+		graphs, err := FindInjectionGraphs(context.Background(), workspace, "./...")
+		if err != nil {
+			t.Fatalf("Failed to find graphs: %v", err)
+		}
+		expected := strings.Join([]string{
+			"wire injector InitializeApp (app/wire.go:7)",
+			"  inputs: app.Config",
+			"  builds: *app.App",
+			"  providers:",
+			"    NewDB (app/app.go:9): app.Config -> *app.DB",
+			"    wire.Bind(new(Logger), new(*stdLogger)) (app/app.go:27): *app.stdLogger -> app.Logger",
+			"    newStdLogger (app/app.go:17):  -> *app.stdLogger",
+			"    wire.Struct(new(App), \"*\") (app/wire.go:8): *app.DB, app.Logger, *app.Cache -> app.App, *app.App",
+			"  missing providers:",
+			"    *app.Cache, required by wire.Struct(new(App), \"*\")",
+			"",
+			"fx application main (cmd/server/main.go:32)",
+			"  providers:",
+			"    NewServer (cmd/server/main.go:18): *app.DB, fx.Lifecycle, *main.Metrics (optional) -> *main.Server",
+			"    NewHandler (cmd/server/main.go:27): *main.Server -> main.Handler",
+			"    fx.Supply(app.Config{}) (cmd/server/main.go:32):  -> app.Config",
+			"    invoke func literal (cmd/server/main.go:32): main.Handler, *app.Cache -> ",
+			"  missing providers:",
+			"    *app.DB, required by NewServer",
+			"    *app.Cache, required by func literal",
+			"",
+		}, "\n")
+		if graphs.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, graphs)
+		}
+	})
+
+	t.Run("provider sets without injectors", func(t *testing.T) {
+		graphs, err := FindInjectionGraphs(context.Background(), workspace, "./app")
+		if err != nil {
+			t.Fatalf("Failed to find graphs: %v", err)
+		}
+		// The injector in app/wire.go is found too, as the wireinject tag is set
+		if len(graphs.Graphs) != 1 || graphs.Graphs[0].Name != "InitializeApp" {
+			t.Errorf("Expected the injector of the app package, got %+v", graphs.Graphs)
+		}
+
+		if err := os.Remove(filepath.Join(workspace, "app", "wire.go")); err != nil {
+			t.Fatal(err)
+		}
+		graphs, err = FindInjectionGraphs(context.Background(), workspace, "./app")
+		if err != nil {
+			t.Fatalf("Failed to find graphs: %v", err)
+		}
+		if len(graphs.Graphs) != 1 || graphs.Graphs[0].Name != "StoreSet" || !graphs.Graphs[0].Set {
+			t.Fatalf("Expected the StoreSet provider set, got %+v", graphs.Graphs)
+		}
+		text := graphs.String()
+		if !strings.HasPrefix(text, "wire provider set StoreSet (app/app.go:27)\n") ||
+			!strings.HasSuffix(text, "  required from outside the set:\n    app.Config, required by NewDB\n") {
+			t.Errorf("Unexpected text:\n%s", text)
+		}
+	})
+}
@@ -0,0 +1,278 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	inlayHintsToolName        = "inlay_hints"
+	inlayHintsToolDescription = `Reports what the compiler knows about a Go function that is invisible in its source text, like the inlay hints of an editor:
+- type: the inferred types of variables declared without a type (x := f(), var x = 1, range variables and type switch variables)
+- conversion: implicit conversions of concrete values to interfaces and of untyped constants to named types, in arguments, assignments and returns
+- variadic: the arguments packed into the variadic parameter of a call, or the slice expanded with ...
+
+Methods are named Type.Method.`
+)
+
+func AddInlayHintsTool(mcpServer *server.MCPServer) {
+	handleInlayHints := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		symbol, err := request.RequireString("symbol")
+		if err != nil {
+			return nil, err
+		}
+
+		hints, err := InlayHints(ctx, filePath, symbol)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error computing inlay hints: %v", err)), nil
+		}
+		return mcp.NewToolResultText(formatInlayHints(symbol, hints)), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		inlayHintsToolName,
+		mcp.WithDescription(inlayHintsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the function"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Name of the function, or Type.Method for a method"),
+			mcp.Required(),
+		),
+	), handleInlayHints)
+}
+
+// InlayHint is implicit information about the code at a position
+type InlayHint struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Kind is type, conversion or variadic
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+}
+
+// InlayHints type checks the package of filePath and reports the inferred types, implicit
+// conversions and variadic calls in the function or method named symbol, as Name or
+// Type.Method, ordered by position
+func InlayHints(ctx context.Context, filePath string, symbol string) ([]InlayHint, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	pkg, file, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	fn := findFuncDecl(file, symbol)
+	if fn == nil {
+		return nil, classifyErrorf(ErrSymbolNotFound, "no function or method '%s' found in %s", symbol, filePath)
+	}
+
+	info := pkg.TypesInfo
+	qualifier := types.RelativeTo(pkg.Types)
+	typeString := func(t types.Type) string { return types.TypeString(t, qualifier) }
+	var hints []InlayHint
+	add := func(pos token.Pos, kind string, label string) {
+		position := pkg.Fset.Position(pos)
+		hints = append(hints, InlayHint{Line: position.Line, Column: position.Column, Kind: kind, Label: label})
+	}
+	typeHint := func(expr ast.Expr) {
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+			if obj := info.Defs[ident]; obj != nil {
+				add(ident.Pos(), "type", ident.Name+" "+typeString(obj.Type()))
+			}
+		}
+	}
+	conversionHint := func(expr ast.Expr, target types.Type) {
+		value, ok := info.Types[expr]
+		if !ok || target == nil || value.Type == nil || value.IsNil() {
+			return
+		}
+		if _, isTuple := value.Type.(*types.Tuple); isTuple {
+			return
+		}
+		if types.IsInterface(target) && !types.IsInterface(value.Type) {
+			add(expr.Pos(), "conversion", fmt.Sprintf("%s %s → %s", types.ExprString(expr), typeString(value.Type), typeString(target)))
+			return
+		}
+		if _, named := types.Unalias(target).(*types.Named); named && untypedConstant(info, expr) {
+			add(expr.Pos(), "conversion", fmt.Sprintf("%s untyped constant → %s", types.ExprString(expr), typeString(target)))
+		}
+	}
+
+	// The results of return statements are those of the innermost function literal
+	var walk func(body *ast.BlockStmt, signature *types.Signature)
+	walk = func(body *ast.BlockStmt, signature *types.Signature) {
+		ast.Inspect(body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncLit:
+				if literal, ok := info.TypeOf(node).(*types.Signature); ok {
+					walk(node.Body, literal)
+				}
+				return false
+			case *ast.AssignStmt:
+				if node.Tok == token.DEFINE {
+					for _, lhs := range node.Lhs {
+						typeHint(lhs)
+					}
+				} else if node.Tok == token.ASSIGN && len(node.Lhs) == len(node.Rhs) {
+					for i, rhs := range node.Rhs {
+						conversionHint(rhs, info.TypeOf(node.Lhs[i]))
+					}
+				}
+			case *ast.RangeStmt:
+				if node.Tok == token.DEFINE {
+					for _, expr := range []ast.Expr{node.Key, node.Value} {
+						typeHint(expr)
+					}
+				}
+			case *ast.ValueSpec:
+				for i, ident := range node.Names {
+					if node.Type == nil {
+						typeHint(ident)
+					} else if len(node.Values) == len(node.Names) {
+						conversionHint(node.Values[i], info.TypeOf(node.Type))
+					}
+				}
+			case *ast.CaseClause:
+				// Type switch variables are declared implicitly in each clause
+				if obj, ok := info.Implicits[node]; ok && obj.Name() != "_" {
+					add(node.Colon, "type", obj.Name()+" "+typeString(obj.Type()))
+				}
+			case *ast.ReturnStmt:
+				if signature != nil && len(node.Results) == signature.Results().Len() {
+					for i, result := range node.Results {
+						conversionHint(result, signature.Results().At(i).Type())
+					}
+				}
+			case *ast.CallExpr:
+				callHints(info, node, typeString, add, conversionHint)
+			}
+			return true
+		})
+	}
+	signature, _ := info.Defs[fn.Name].Type().(*types.Signature)
+	walk(fn.Body, signature)
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		if hints[i].Line != hints[j].Line {
+			return hints[i].Line < hints[j].Line
+		}
+		return hints[i].Column < hints[j].Column
+	})
+	return hints, nil
+}
+
+// findFuncDecl returns the function with a body named symbol, as Name or Type.Method, declared in the file
+func findFuncDecl(file *ast.File, symbol string) *ast.FuncDecl {
+	typeName, name, isMethod := strings.Cut(strings.TrimPrefix(symbol, "*"), ".")
+	if !isMethod {
+		name, typeName = typeName, ""
+	}
+	for _, decl := range file.Decls {
+		if decl, ok := decl.(*ast.FuncDecl); ok && decl.Name.Name == name && decl.Body != nil &&
+			(decl.Recv == nil) != isMethod && (!isMethod || receiverTypeName(decl.Recv.List[0].Type) == typeName) {
+			return decl
+		}
+	}
+	return nil
+}
+
+// callHints reports the conversions of the arguments of the call and what is passed as its
+// variadic parameter
+func callHints(
+	info *types.Info,
+	call *ast.CallExpr,
+	typeString func(types.Type) string,
+	add func(token.Pos, string, string),
+	conversionHint func(ast.Expr, types.Type),
+) {
+	fun, ok := info.Types[call.Fun]
+	if !ok || fun.IsType() {
+		return
+	}
+	signature, ok := fun.Type.Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+	params := signature.Params()
+	fixed := params.Len()
+	if signature.Variadic() {
+		fixed--
+	}
+	for i, arg := range call.Args {
+		switch {
+		case i < fixed:
+			conversionHint(arg, params.At(i).Type())
+		case signature.Variadic() && !call.Ellipsis.IsValid():
+			conversionHint(arg, params.At(fixed).Type().(*types.Slice).Elem())
+		}
+	}
+	if !signature.Variadic() {
+		return
+	}
+	variadic := params.At(fixed)
+	name := variadic.Name()
+	if name == "" || name == "_" {
+		name = "variadic parameter"
+	}
+	switch extra := len(call.Args) - fixed; {
+	case call.Ellipsis.IsValid():
+		add(call.Ellipsis, "variadic", fmt.Sprintf("slice expanded as %s %s", name, typeString(variadic.Type())))
+	case extra <= 0:
+		add(call.Rparen, "variadic", fmt.Sprintf("%s is a nil %s", name, typeString(variadic.Type())))
+	default:
+		add(call.Args[fixed].Pos(), "variadic", fmt.Sprintf("%d arguments packed into %s %s", extra, name, typeString(variadic.Type())))
+	}
+}
+
+// untypedConstant reports whether the expression is a constant made only of untyped literals
+// and constants, which takes the type it is used as
+func untypedConstant(info *types.Info, expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		if constant, ok := info.Uses[expr].(*types.Const); ok {
+			basic, ok := constant.Type().(*types.Basic)
+			return ok && basic.Info()&types.IsUntyped != 0
+		}
+	case *ast.ParenExpr:
+		return untypedConstant(info, expr.X)
+	case *ast.UnaryExpr:
+		return untypedConstant(info, expr.X)
+	case *ast.BinaryExpr:
+		return untypedConstant(info, expr.X) && untypedConstant(info, expr.Y)
+	}
+	return false
+}
+
+// formatInlayHints formats the hints one per line as line:column kind: label
+func formatInlayHints(symbol string, hints []InlayHint) string {
+	if len(hints) == 0 {
+		return fmt.Sprintf("No implicit information in %s", symbol)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Implicit information in %s:\n", symbol)
+	for _, hint := range hints {
+		fmt.Fprintf(&b, "  %d:%d %s: %s\n", hint.Line, hint.Column, hint.Kind, hint.Label)
+	}
+	return b.String()
+}
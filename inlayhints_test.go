@@ -0,0 +1,117 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestInlayHints(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a function relying on implicit information
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",      // 1
+				"",                  // 2
+				"import (",          // 3
+				"\t\"fmt\"",         // 4
+				"\t\"time\"",        // 5
+				")",                 // 6
+				"",                  // 7
+				"type Job struct{}", // 8
+				"",                  // 9
+				"func wait(d time.Duration, names ...string) {}", // 10
+				"",                                     // 11
+				"func (j *Job) Run(items []int) any {", // 12
+				"\tcount := len(items)",                // 13
+				"\tfor i, item := range items {",       // 14
+				"\t\tfmt.Println(i, item)",             // 15
+				"\t}",                                  // 16
+				"\twait(5)",                            // 17
+				"\twait(time.Second, \"a\", \"b\")",    // 18
+				"\tnames := []string{\"c\"}",           // 19
+				"\twait(0, names...)",                  // 20
+				"\tvar value any = count",              // 21
+				"\tswitch v := value.(type) {",         // 22
+				"\tcase int:",                          // 23
+				"\t\treturn v",                         // 24
+				"\t}",                                  // 25
+				"\treturn j",                           // 26
+				"}",                                    // 27
+				"",                                     // 28
+			},
+		})
+	}
+
+	t.Run("method hints", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		hints, err := InlayHints(context.Background(), file, "Job.Run")
+		if err != nil {
+			t.Fatalf("Failed to compute inlay hints: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Implicit information in Job.Run:",
+			"  13:2 type: count int",
+			"  14:6 type: i int",
+			"  14:9 type: item int",
+			"  15:15 conversion: i int → any",
+			"  15:15 variadic: 2 arguments packed into a []any",
+			"  15:18 conversion: item int → any",
+			"  17:7 conversion: 5 untyped constant → time.Duration",
+			"  17:8 variadic: names is a nil []string",
+			"  18:20 variadic: 2 arguments packed into names []string",
+			"  19:2 type: names []string",
+			"  20:7 conversion: 0 untyped constant → time.Duration",
+			"  20:15 variadic: slice expanded as names []string",
+			"  21:18 conversion: count int → any",
+			"  23:10 type: v int",
+			"  24:10 conversion: v int → any",
+			"  26:9 conversion: j *Job → any",
+			"",
+		}, "\n")
+		if got := formatInlayHints("Job.Run", hints); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("missing function", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		if _, err := InlayHints(context.Background(), file, "Run"); err == nil || !strings.Contains(err.Error(), "no function or method 'Run'") {
+			t.Errorf("Expected a plain name to only match functions, got: %v", err)
+		}
+	})
+
+	t.Run("inlay hints tool", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      inlayHintsToolName,
+				"arguments": map[string]any{"file_path": file, "symbol": "wait"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || text != "No implicit information in wait" {
+			t.Errorf("Expected no hints in the empty function, got: %s", text)
+		}
+	})
+}
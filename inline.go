@@ -0,0 +1,128 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const (
+	inlineToolName        = "inline"
+	inlineToolDescription = `Inlines a function call or a local variable at a position using the gopls inline code actions. When the symbol at the position is called, the call is replaced by the body of the function with its parameters substituted. Otherwise the use of the variable is replaced by the expression it was initialized with.
+
+Pass output=patch to preview the changes as a unified diff without writing them.`
+)
+
+// gopls code action kinds of the inline refactorings
+const (
+	inlineCallKind     = "refactor.inline.call"
+	inlineVariableKind = "refactor.inline.variable"
+)
+
+func AddInlineTool(mcpServer *server.MCPServer) {
+	handleInline := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		lineNumber, err := request.RequireInt("line_number")
+		if err != nil {
+			return nil, err
+		}
+		symbolName, err := request.RequireString("symbol")
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := Inline(ctx, filePath, lineNumber, symbolName)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error inlining: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		inlineToolName,
+		mcp.WithDescription(inlineToolDescription),
+		editingToolAnnotation(true, false),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file containing the call or variable use"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("line_number",
+			mcp.Description("Line number of the call or variable use"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Name of the called function or of the variable to inline, the first occurrence on the line is used"),
+			mcp.Required(),
+		),
+	), handleInline)
+}
+
+// Inline inlines the call of the function or the use of the variable named symbolName at
+// lineNumber of filePath, writing the changed files. gopls is killed when ctx is done.
+func Inline(ctx context.Context, filePath string, lineNumber int, symbolName string) (string, error) {
+	position, err := createGoplsPosition(filePath, lineNumber, symbolName)
+	if err != nil {
+		return "", err
+	}
+	column, err := strconv.Atoi(position[strings.LastIndex(position, ":")+1:])
+	if err != nil {
+		return "", fmt.Errorf("invalid gopls position %s: %w", position, err)
+	}
+	kind, err := inlineKind(filePath, lineNumber, column)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := executeGoplsCommand(ctx, "codeaction", "-kind="+kind, "-exec", "-write", position); err != nil {
+		return "", fmt.Errorf("failed to inline '%s' at %s: %w", symbolName, position, err)
+	}
+	if kind == inlineCallKind {
+		return fmt.Sprintf("Inlined the call of '%s' at %s", symbolName, position), nil
+	}
+	return fmt.Sprintf("Inlined the variable '%s' at %s", symbolName, position), nil
+}
+
+// inlineKind returns the code action kind inlining the identifier at the 1-based line and byte
+// column of filePath: a call when the identifier is the called function, otherwise a variable
+func inlineKind(filePath string, lineNumber int, column int) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return "", classifyErrorf(ErrSyntaxErrors, "failed to parse %s: %w", filePath, err)
+	}
+	pos := fset.File(file.Pos()).LineStart(lineNumber) + token.Pos(column-1)
+
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	ident, ok := path[0].(*ast.Ident)
+	if !ok {
+		return "", fmt.Errorf("no identifier at %s:%d:%d", filePath, lineNumber, column)
+	}
+	var callee ast.Node = ident
+	parents := path[1:]
+	if len(parents) > 0 {
+		if selector, ok := parents[0].(*ast.SelectorExpr); ok && selector.Sel == ident {
+			callee = selector
+			parents = parents[1:]
+		}
+	}
+	if len(parents) > 0 {
+		if call, ok := parents[0].(*ast.CallExpr); ok && call.Fun == callee {
+			return inlineCallKind, nil
+		}
+	}
+	return inlineVariableKind, nil
+}
@@ -0,0 +1,80 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInline(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a call and a variable to inline
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",       // 1
+				"",                   // 2
+				"import \"strings\"", // 3
+				"",                   // 4
+				"func double(x int) int { return x * 2 }", // 5
+				"",                                      // 6
+				"func main() {",                         // 7
+				"\tgreeting := strings.ToUpper(\"hi\")", // 8
+				"\tprintln(double(21), greeting)",       // 9
+				"}",                                     // 10
+				"",                                      // 11
+			},
+		})
+	}
+
+	t.Run("kind", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		for _, test := range []struct {
+			line, column int
+			expected     string
+		}{
+			{9, 10, inlineCallKind},
+			{9, 22, inlineVariableKind},
+			{8, 22, inlineCallKind},
+			{8, 14, inlineVariableKind},
+		} {
+			kind, err := inlineKind(file, test.line, test.column)
+			if err != nil || kind != test.expected {
+				t.Errorf("Expected %s at %d:%d, got %s (%v)", test.expected, test.line, test.column, kind, err)
+			}
+		}
+		if _, err := inlineKind(file, 9, 9); err == nil || !strings.Contains(err.Error(), "no identifier") {
+			t.Errorf("Expected an error for a position without identifier, got: %v", err)
+		}
+	})
+
+	t.Run("inline call", func(t *testing.T) {
+		t.Parallel()
+		if _, err := exec.LookPath("gopls"); err != nil {
+			t.Skip("gopls is not installed")
+		}
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		result, err := Inline(context.Background(), file, 9, "double")
+		if err != nil {
+			t.Fatalf("Failed to inline call: %v", err)
+		}
+		if !strings.Contains(result, "Inlined the call of 'double'") {
+			t.Errorf("Expected the inlined call to be reported, got: %s", result)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), "println(21*2, greeting)") {
+			t.Errorf("Expected the call to be inlined, got:\n%s", content)
+		}
+	})
+}
@@ -1,16 +1,22 @@
 package go_mcp_tools
 
 import (
-	"bufio"
+	"cmp"
+	"container/list"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/scanner"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -34,80 +40,36 @@ Supported path formats:
 • Import path with symbol: github.com/user/repo/package:symbolName`
 )
 
+// Detail levels of function bodies
+const (
+	DetailSignature = "signature"
+	DetailBody      = "body"
+	DetailAuto      = "auto"
+)
+
+// autoDetailMaxLines is the longest function whose body the auto detail level shows in full
+const autoDetailMaxLines = 40
+
 func AddInspectTool(mcpServer *server.MCPServer) {
+	addInspectTool(mcpServer, "")
+}
+
+// addInspectTool adds the inspect tool, caching loaded packages in cacheDir unless empty
+func addInspectTool(mcpServer *server.MCPServer, cacheDir string) {
 	handleInspect := func(
 		ctx context.Context,
 		request mcp.CallToolRequest,
 	) (*mcp.CallToolResult, error) {
-		arguments := request.GetArguments()
-
-		pathStr, ok := arguments["path"].(string)
-		if !ok || pathStr == "" {
-			return nil, fmt.Errorf("path argument is required and must be a string")
+		pathStr, err := request.RequireString("path")
+		if err != nil {
+			return nil, err
 		}
 
 		// Parse the path to extract base path, line number, and symbol name
-		var path, symbolName string
-		var lineNumber int
-		// Check if it's a file path (contains .go or starts with /)
-		isFilePath := strings.Contains(pathStr, ".go") ||
-			strings.HasPrefix(pathStr, "/") ||
-			strings.HasPrefix(pathStr, "./") ||
-			strings.HasPrefix(pathStr, "../")
-
-		if isFilePath {
-			// Parse file path patterns: /path/file.go[:line[:symbol]]
-
-			// Split by colons to extract line and symbol
-			parts := strings.Split(pathStr, ":")
-			path = parts[0]
-
-			if len(parts) > 1 {
-				// Try to parse line number
-				if ln, err := strconv.Atoi(parts[1]); err == nil {
-					lineNumber = ln
-
-					// If there's a third part, it's the symbol name
-					if len(parts) > 2 {
-						symbolName = parts[2]
-					}
-				} else {
-					// Not a line number, might be a symbol name
-					symbolName = parts[1]
-				}
-			}
-		} else {
-			// Parse import path patterns: github.com/user/repo/package[:symbol]
-
-			// Find the last colon that's not part of a port number
-			// Look for pattern like :symbol_name (not :digit)
-			colonIndex := -1
-			for i := len(pathStr) - 1; i >= 0; i-- {
-				if pathStr[i] == ':' {
-					// Check if what follows looks like a symbol name
-					afterColon := pathStr[i+1:]
-					if afterColon != "" && !regexp.MustCompile(`^\d+(/|$)`).MatchString(afterColon) {
-						// Make sure it's a valid Go identifier pattern
-						if regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString(afterColon) {
-							colonIndex = i
-							break
-						}
-					}
-				}
-			}
-
-			if colonIndex > 0 {
-				path = pathStr[:colonIndex]
-				symbolName = pathStr[colonIndex+1:]
-			} else {
-				path = pathStr
-			}
-		}
+		path, lineNumber, symbolName := parseInspectPath(pathStr)
 
-		// Get required and optional arguments
-		onlyExported, _ := arguments["only_exported"].(bool)
-		workspaceDir, ok := arguments["workspace_dir"].(string)
-		if !ok || workspaceDir == "" {
+		workspaceDir := request.GetString("workspace_dir", "")
+		if workspaceDir == "" {
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
 					mcp.TextContent{
@@ -119,14 +81,24 @@ func AddInspectTool(mcpServer *server.MCPServer) {
 			}, nil
 		}
 
+		options, err := inspectOptionsFromRequest(request, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		format, err := inspectFormatFromRequest(request)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		options.LineNumber = lineNumber
+		options.SymbolName = symbolName
+		options.MetadataCacheDir = cacheDir
+
+		if stream := request.GetBool("stream", false); stream && format != "json" {
+			return streamInspectResult(ctx, mcpServer, request, path, options), nil
+		}
+
 		// Call the inspect function with parsed parameters
-		summary, err := Inspect(
-			path,
-			lineNumber,
-			symbolName,
-			!onlyExported, // InspectSymbol uses includePrivate, so we invert onlyExported
-			workspaceDir,
-		)
+		result, err := InspectStructured(ctx, path, options)
 		if err != nil {
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
@@ -139,18 +111,27 @@ func AddInspectTool(mcpServer *server.MCPServer) {
 			}, nil
 		}
 
+		if format == "json" {
+			result.Highlight()
+			encoded, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return toolErrorResult(fmt.Sprintf("Error encoding result: %v", err)), nil
+			}
+			return mcp.NewToolResultText(string(encoded)), nil
+		}
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.TextContent{
 					Type: "text",
-					Text: summary,
+					Text: result.String(),
 				},
 			},
 		}, nil
 	}
-	mcpServer.AddTool(mcp.NewTool(
-		inspectToolName,
+	mcpServer.AddTool(mcp.NewTool(inspectToolName, append([]mcp.ToolOption{
 		mcp.WithDescription(inspectToolDescription),
+		readOnlyToolAnnotation(),
 		mcp.WithString(
 			"path",
 			mcp.Description(
@@ -165,6 +146,116 @@ func AddInspectTool(mcpServer *server.MCPServer) {
 			),
 			mcp.Required(),
 		),
+		mcp.WithBoolean(
+			"stream",
+			mcp.Description(fmt.Sprintf(
+				"Whether to return the text in content blocks of about %d KB split between files and symbols, written as the files are inspected, for huge packages. "+
+					"With a progress token, a progress notification reports every written chunk, the last one with the total.",
+				inspectStreamChunkSize>>10,
+			)),
+			mcp.DefaultBool(false),
+		),
+	}, inspectToolOptions()...)...), handleInspect)
+}
+
+// inspectStreamChunkSize is the size of the chunks of streamed inspect results
+const inspectStreamChunkSize = 64 << 10
+
+// streamInspectResult inspects the path, splitting its text into chunks as its files are
+// inspected and returning one content block per chunk. When the call has a progress token,
+// a progress notification reports every chunk once the next one is written, the
+// notification of the last chunk setting the total. The notifications only carry the
+// status, the text is always in the result.
+func streamInspectResult(
+	ctx context.Context,
+	mcpServer *server.MCPServer,
+	request mcp.CallToolRequest,
+	path string,
+	options InspectOptions,
+) *mcp.CallToolResult {
+	var progressToken mcp.ProgressToken
+	if request.Params.Meta != nil {
+		progressToken = request.Params.Meta.ProgressToken
+	}
+	var content []mcp.Content
+	written := 0
+	add := func(chunk string, last bool) {
+		content = append(content, mcp.NewTextContent(chunk))
+		written += len(chunk)
+		if progressToken == nil {
+			return
+		}
+		params := map[string]any{
+			"progressToken": progressToken,
+			"progress":      len(content),
+			"message":       fmt.Sprintf("Inspected %d chunk(s), %d KB", len(content), written>>10),
+		}
+		if last {
+			params["total"] = len(content)
+		}
+		// Calls without a session cannot be notified and still get the whole result
+		_ = mcpServer.SendNotificationToClient(ctx, "notifications/progress", params)
+	}
+
+	pending := ""
+	chunks := 0
+	err := inspectChunks(ctx, path, options, inspectStreamChunkSize, func(chunk string) {
+		if chunks > 0 {
+			add(pending, false)
+		}
+		chunks++
+		pending = chunk
+	})
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("Error inspecting symbol: %v", err))
+	}
+	add(pending, true)
+	return &mcp.CallToolResult{Content: content}
+}
+
+// inspectChunks inspects the path like InspectStructured and passes its text to yield
+// in the chunks of InspectResult.Chunks, writing every file as soon as it is inspected
+// instead of once the whole result is built
+func inspectChunks(ctx context.Context, path string, options InspectOptions, size int, yield func(string)) error {
+	var b strings.Builder
+	boundary := func() {
+		if b.Len() == 0 || b.Len() < size {
+			return
+		}
+		yield(b.String())
+		b.Reset()
+	}
+	files := 0
+	options.fileInspected = func(result *InspectResult, file *FileInfo) {
+		if result.Package == nil {
+			result.writeHeader(&b, len(file.Symbols) == 0)
+			writeFile(&b, file, boundary)
+			return
+		}
+		// Package files without changed declarations are dropped before getting here
+		if files == 0 {
+			result.writeHeader(&b, false)
+			writePackageHeader(&b, result.Package)
+		}
+		writePackageFile(&b, file, files, boundary)
+		files++
+	}
+	result, err := InspectStructured(ctx, path, options)
+	if err != nil {
+		return err
+	}
+	if result.Symbol != nil || result.Package != nil && files == 0 {
+		result.write(&b, boundary)
+	}
+	if b.Len() > 0 {
+		yield(b.String())
+	}
+	return nil
+}
+
+// inspectToolOptions returns the optional arguments shared by the inspect tools
+func inspectToolOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
 		mcp.WithBoolean(
 			"only_exported",
 			mcp.Description(
@@ -172,7 +263,200 @@ func AddInspectTool(mcpServer *server.MCPServer) {
 			),
 			mcp.DefaultBool(false),
 		),
-	), handleInspect)
+		mcp.WithBoolean(
+			"include_references",
+			mcp.Description("Whether to list the references of the symbol. Disable to save time and output when only the declaration is needed."),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean(
+			"include_call_hierarchy",
+			mcp.Description("Whether to show the incoming and outgoing calls of a function"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean(
+			"include_implementers",
+			mcp.Description("Whether to list the types implementing an interface, with whether values or only pointers of each type satisfy it"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean(
+			"include_methods",
+			mcp.Description("Whether to list the methods of a type, declared in any file of its package or promoted from embedded fields"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean(
+			"expand_embedded",
+			mcp.Description("Whether to list the fields a struct gets from its embedded structs, with the embedded types declaring them, and its promoted methods even when include_methods is false"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean(
+			"include_imports",
+			mcp.Description("Whether to list the imports of a file"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean(
+			"include_scope",
+			mcp.Description("Whether to show the enclosing scopes of a variable or constant"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean(
+			"include_body",
+			mcp.Description("Whether to show the full source of functions instead of only their signature"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean(
+			"include_blame",
+			mcp.Description("Whether to show the last commit changing each symbol, with its author and date. Runs git log once per symbol."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean(
+			"include_examples",
+			mcp.Description("Whether to show the ExampleXxx functions of the symbol, and of its methods for a type, from the _test.go files of its package"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean(
+			"run_examples",
+			mcp.Description("Whether to run the examples with go test and show whether their output matches their // Output: comment. Implies include_examples."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString(
+			"detail",
+			mcp.Description(fmt.Sprintf(
+				"How much of function bodies to show, overriding include_body. auto shows the body of functions up to %d lines and summarizes the branches, loops and calls of longer ones.",
+				autoDetailMaxLines,
+			)),
+			mcp.Enum(DetailSignature, DetailBody, DetailAuto),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Output format. json returns the structured result, with the language and token classes of every code snippet for syntax highlighting."),
+			mcp.Enum("text", "json"),
+			mcp.DefaultString("text"),
+		),
+		mcp.WithString(
+			"changed_since",
+			mcp.Description("Git ref, e.g. main, limiting file and package inspections to the declarations changed since it in the working tree. Useful to review a branch without reading unchanged code."),
+		),
+		mcp.WithObject(
+			"overlays",
+			mcp.Description(
+				"Unsaved file contents by file path (absolute or relative to workspace_dir), used instead of the files on disk. References, implementers and call hierarchies are unavailable for overlaid files.",
+			),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+	}
+}
+
+// inspectOptionsFromRequest returns the inspect options set by the optional arguments
+// of an inspect tool call
+func inspectOptionsFromRequest(request mcp.CallToolRequest, workspaceDir string) (InspectOptions, error) {
+	options := DefaultInspectOptions(workspaceDir)
+	onlyExported := request.GetBool("only_exported", false)
+	options.IncludePrivate = !onlyExported
+	for name, include := range map[string]*bool{
+		"include_references":     &options.IncludeReferences,
+		"include_call_hierarchy": &options.IncludeCallHierarchy,
+		"include_implementers":   &options.IncludeImplementers,
+		"include_methods":        &options.IncludeMethods,
+		"expand_embedded":        &options.ExpandEmbedded,
+		"include_imports":        &options.IncludeImports,
+		"include_scope":          &options.IncludeScope,
+		"include_body":           &options.IncludeBody,
+		"include_blame":          &options.IncludeBlame,
+		"include_examples":       &options.IncludeExamples,
+		"run_examples":           &options.RunExamples,
+	} {
+		*include = request.GetBool(name, *include)
+	}
+	if detail := request.GetString("detail", ""); detail != "" {
+		switch detail {
+		case DetailSignature, DetailBody, DetailAuto:
+			options.Detail = detail
+		default:
+			return options, fmt.Errorf(
+				"detail must be one of %s, %s and %s, got: %s",
+				DetailSignature, DetailBody, DetailAuto, detail,
+			)
+		}
+	}
+	options.ChangedSince = request.GetString("changed_since", "")
+	overlay, err := overlayFromRequest(request, workspaceDir)
+	if err != nil {
+		return options, err
+	}
+	options.Overlay = overlay
+	return options, nil
+}
+
+// inspectFormatFromRequest returns the output format of an inspect tool call, text or json
+func inspectFormatFromRequest(request mcp.CallToolRequest) (string, error) {
+	format := request.GetString("format", "")
+	switch format {
+	case "", "text":
+		return "text", nil
+	case "json":
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q, use text or json", format)
+}
+
+// parseInspectPath splits a path in one of the formats supported by the inspect tool
+// into the base path, an optional line number and an optional symbol name
+func parseInspectPath(pathStr string) (path string, lineNumber int, symbolName string) {
+	// Check if it's a file path (contains .go or starts with /)
+	isFilePath := strings.Contains(pathStr, ".go") ||
+		strings.HasPrefix(pathStr, "/") ||
+		strings.HasPrefix(pathStr, "./") ||
+		strings.HasPrefix(pathStr, "../")
+
+	if isFilePath {
+		// Parse file path patterns: /path/file.go[:line[:symbol]]
+
+		// Split by colons to extract line and symbol
+		parts := strings.Split(pathStr, ":")
+		path = parts[0]
+
+		if len(parts) > 1 {
+			// Try to parse line number
+			if ln, err := strconv.Atoi(parts[1]); err == nil {
+				lineNumber = ln
+
+				// If there's a third part, it's the symbol name
+				if len(parts) > 2 {
+					symbolName = parts[2]
+				}
+			} else {
+				// Not a line number, might be a symbol name
+				symbolName = parts[1]
+			}
+		}
+	} else {
+		// Parse import path patterns: github.com/user/repo/package[:symbol]
+
+		// Find the last colon that's not part of a port number
+		// Look for pattern like :symbol_name (not :digit)
+		colonIndex := -1
+		for i := len(pathStr) - 1; i >= 0; i-- {
+			if pathStr[i] == ':' {
+				// Check if what follows looks like a symbol name
+				afterColon := pathStr[i+1:]
+				if afterColon != "" && !regexp.MustCompile(`^\d+(/|$)`).MatchString(afterColon) {
+					// Make sure it's a valid Go identifier pattern
+					if regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString(afterColon) {
+						colonIndex = i
+						break
+					}
+				}
+			}
+		}
+
+		if colonIndex > 0 {
+			path = pathStr[:colonIndex]
+			symbolName = pathStr[colonIndex+1:]
+		} else {
+			path = pathStr
+		}
+	}
+	return path, lineNumber, symbolName
 }
 
 // Inspect analyzes a Go symbol (package, file, function, type, etc.)
@@ -187,20 +471,227 @@ func Inspect(
 	includePrivate bool,
 	workspaceDir string,
 ) (string, error) {
+	options := DefaultInspectOptions(workspaceDir)
+	options.LineNumber = lineNumber
+	options.SymbolName = symbolName
+	options.IncludePrivate = includePrivate
+
+	result, err := InspectStructured(context.Background(), path, options)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// InspectOptions selects what to inspect and which sections to include in the result.
+// The sections only apply where they are meaningful, e.g. implementers for interfaces.
+type InspectOptions struct {
+	// LineNumber and SymbolName optionally select a symbol in a file or package
+	LineNumber int
+	SymbolName string
+	// WorkspaceDir is the absolute working directory for package resolution and reference finding
+	WorkspaceDir string
+
+	IncludePrivate       bool
+	IncludeReferences    bool
+	IncludeCallHierarchy bool
+	IncludeImplementers  bool
+	IncludeMethods       bool
+	IncludeImports       bool
+	IncludeScope         bool
+	// ExpandEmbedded adds the fields a struct type gets from its embedded structs, and its
+	// promoted methods even without IncludeMethods
+	ExpandEmbedded bool
+	// IncludeBody shows the full source of functions instead of only their signature
+	IncludeBody bool
+	// Detail is one of DetailSignature, DetailBody and DetailAuto and overrides IncludeBody when set
+	Detail string
+
+	// Overlay replaces the content of files on disk, e.g. with unsaved editor buffers.
+	// References, implementers and call hierarchies are not available for overlaid files.
+	Overlay Overlay
+
+	// IncludeBlame adds the last commit changing each symbol, found with git log -L
+	IncludeBlame bool
+	// IncludeExamples adds the ExampleXxx functions of an inspected symbol, and of its
+	// methods when it is a type, from the _test.go files of its package
+	IncludeExamples bool
+	// RunExamples runs the examples with go test and adds whether their output matched
+	// their output comment. It implies IncludeExamples.
+	RunExamples bool
+	// ChangedSince is a git ref limiting file and package inspections to the declarations
+	// changed since it in the working tree. Symbol inspections are not limited.
+	ChangedSince string
+
+	// MetadataCacheDir is the directory of a disk cache of the metadata of loaded packages,
+	// shared between processes. Package metadata is not cached when empty.
+	MetadataCacheDir string
+
+	// loadedPackages shares the packages loaded by the inspections of a batch
+	loadedPackages *packageMemo
+	// fileInspected gets the files of file and package inspections as they are
+	// inspected, which are then left out of the result
+	fileInspected func(result *InspectResult, file *FileInfo)
+}
+
+// DefaultInspectOptions returns the options used by Inspect and the inspect tool:
+// all sections except function bodies
+func DefaultInspectOptions(workspaceDir string) InspectOptions {
+	return InspectOptions{
+		WorkspaceDir:         workspaceDir,
+		IncludePrivate:       true,
+		IncludeReferences:    true,
+		IncludeCallHierarchy: true,
+		IncludeImplementers:  true,
+		IncludeMethods:       true,
+		IncludeImports:       true,
+		IncludeScope:         true,
+	}
+}
+
+// bodyDetail returns the detail level of function bodies selected by the options
+func (options InspectOptions) bodyDetail() string {
+	if options.Detail != "" {
+		return options.Detail
+	}
+	if options.IncludeBody {
+		return DetailBody
+	}
+	return DetailSignature
+}
+
+// InspectStructured analyzes a Go symbol like Inspect, but returns the result as
+// structs instead of text for library users. Start from DefaultInspectOptions and
+// disable the sections that are not needed, as references and call hierarchies
+// require gopls and are the expensive part of an inspection. The gopls, git and go test
+// processes of the inspection are killed when ctx is done.
+func InspectStructured(ctx context.Context, path string, options InspectOptions) (*InspectResult, error) {
+	result, err := inspectStructured(ctx, path, options)
+	if err != nil {
+		return nil, err
+	}
+	if result.Symbol == nil {
+		return result, nil
+	}
+	if options.IncludeBlame {
+		for _, symbol := range result.Symbol.withMethods() {
+			symbol.LastChange = findLastChange(ctx, symbol.File, symbol.StartLine, symbol.EndLine)
+		}
+	}
+	if options.IncludeExamples || options.RunExamples {
+		if err := addExamples(ctx, result.Symbol, options.RunExamples); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// withMethods returns the symbol followed by its methods
+func (symbol *SymbolInfo) withMethods() []*SymbolInfo {
+	symbols := []*SymbolInfo{symbol}
+	for i := range symbol.Methods {
+		symbols = append(symbols, &symbol.Methods[i])
+	}
+	return symbols
+}
+
+// findLastChange returns the last commit changing the lines of a declaration, as
+// committed, using git log -L
+func findLastChange(ctx context.Context, filePath string, startLine, endLine int) *LastChange {
+	output, err := runGit(
+		ctx,
+		filepath.Dir(filePath),
+		"log", "-1", "--no-patch", "--format=%H%x00%an%x00%aI%x00%s",
+		fmt.Sprintf("-L%d,%d:%s", startLine, endLine, filepath.Base(filePath)),
+	)
+	if err != nil {
+		return &LastChange{Error: err.Error()}
+	}
+	fields := strings.Split(strings.TrimSpace(output), "\x00")
+	if len(fields) != 4 {
+		return &LastChange{Error: "no commit found, the declaration may not be committed yet"}
+	}
+	return &LastChange{Commit: fields[0], Author: fields[1], Date: fields[2], Summary: fields[3]}
+}
+
+// changedDeclarationFilter returns whether the lines of a declaration were changed in
+// the working tree since ref, in the repository containing workspaceDir
+func changedDeclarationFilter(ctx context.Context, workspaceDir string, ref string) (func(SymbolInfo) bool, error) {
+	repoRoot, err := gitRepoRoot(ctx, workspaceDir)
+	if err != nil {
+		return nil, fmt.Errorf("changed_since requires a git repository: %w", err)
+	}
+	changed, err := changedGoFiles(ctx, repoRoot, ref)
+	if err != nil {
+		return nil, err
+	}
+	repoRoot = resolveSymlinks(repoRoot)
+	return func(symbol SymbolInfo) bool {
+		rel, ok := workspaceRelPath(resolveSymlinks(symbol.File), repoRoot)
+		if !ok {
+			return false
+		}
+		lines, ok := changed[filepath.ToSlash(rel)]
+		return ok && lines.overlapsCurrent(symbol.StartLine, symbol.EndLine)
+	}, nil
+}
+
+// inspectStructured inspects a path, leaving the blame and examples of symbol results
+// to InspectStructured
+func inspectStructured(ctx context.Context, path string, options InspectOptions) (*InspectResult, error) {
+	lineNumber := options.LineNumber
+	symbolName := options.SymbolName
+	workspaceDir := options.WorkspaceDir
+	overlay := options.Overlay
+
 	if workspaceDir == "" {
-		return "", fmt.Errorf("workspace_dir is required for file analysis")
+		return nil, fmt.Errorf("workspace_dir is required for file analysis")
 	}
 
 	if !filepath.IsAbs(workspaceDir) {
-		return "", fmt.Errorf(
+		return nil, fmt.Errorf(
 			"workspace_dir must be an absolute path, got: %s",
 			workspaceDir,
 		)
 	}
 
-	var result strings.Builder
+	// Helper to limit an inspected file to the changed declarations, add their last
+	// changes and add it to the result, or pass it to options.fileInspected. Files of
+	// packages left without changed declarations are dropped.
+	var changed func(SymbolInfo) bool
+	addFile := func(result *InspectResult, file FileInfo) error {
+		if options.ChangedSince != "" {
+			if changed == nil {
+				var err error
+				changed, err = changedDeclarationFilter(ctx, workspaceDir, options.ChangedSince)
+				if err != nil {
+					return err
+				}
+			}
+			result.ChangedSince = options.ChangedSince
+			file.Symbols = slices.DeleteFunc(file.Symbols, func(symbol SymbolInfo) bool { return !changed(symbol) })
+			if result.Package != nil && len(file.Symbols) == 0 {
+				return nil
+			}
+		}
+		if options.IncludeBlame {
+			for i := range file.Symbols {
+				symbol := &file.Symbols[i]
+				symbol.LastChange = findLastChange(ctx, symbol.File, symbol.StartLine, symbol.EndLine)
+			}
+		}
+		switch {
+		case options.fileInspected != nil:
+			options.fileInspected(result, &file)
+		case result.Package != nil:
+			result.Package.Files = append(result.Package.Files, file)
+		default:
+			result.File = &file
+		}
+		return nil
+	}
 
-	// Helper to find and format symbol in declarations
+	// Helper to find symbol in declarations
 	findSymbol := func(decls []ast.Decl, fset *token.FileSet, symbolName string, lineNumber int) (ast.Node, bool) {
 		for _, decl := range decls {
 			switch d := decl.(type) {
@@ -231,151 +722,230 @@ func Inspect(
 		return nil, false
 	}
 
-	// Helper to format any symbol node
-	formatSymbolWithContext := func(node ast.Node, fset *token.FileSet, file *ast.File) {
+	// Helper to describe any symbol node with all of its context, looking up its methods
+	// within the type checked package, or nil to load it when needed
+	describeSymbolWithContext := func(node ast.Node, fset *token.FileSet, file *ast.File, pkg *packages.Package) *SymbolInfo {
+		var info SymbolInfo
 		switch n := node.(type) {
 		case *ast.FuncDecl:
-			formatFunction(&result, n, fset, true, true, workspaceDir)
+			info = newFunctionInfo(
+				ctx,
+				n,
+				fset,
+				options.IncludeReferences,
+				options.IncludeCallHierarchy,
+				options.bodyDetail(),
+				workspaceDir,
+				overlay,
+			)
 		case *ast.TypeSpec:
-			// Find the parent GenDecl for this TypeSpec
-			var parentGenDecl *ast.GenDecl
-			for _, decl := range file.Decls {
-				if genDecl, ok := decl.(*ast.GenDecl); ok {
-					for _, spec := range genDecl.Specs {
-						if spec == n {
-							parentGenDecl = genDecl
-							break
-						}
-					}
-					if parentGenDecl != nil {
-						break
-					}
-				}
-			}
-			formatType(&result, n, fset, true, true, true, parentGenDecl, workspaceDir)
+			info = newTypeInfo(
+				ctx,
+				n,
+				fset,
+				pkg,
+				options.IncludeReferences,
+				options.IncludeImplementers,
+				options.IncludeMethods,
+				options.ExpandEmbedded,
+				options.bodyDetail(),
+				findParentGenDecl(file, n),
+				workspaceDir,
+				overlay,
+			)
 		case *ast.ValueSpec:
-			// Find the parent GenDecl for this ValueSpec
-			var parentGenDecl *ast.GenDecl
-			for _, decl := range file.Decls {
-				if genDecl, ok := decl.(*ast.GenDecl); ok {
-					for _, spec := range genDecl.Specs {
-						if spec == n {
-							parentGenDecl = genDecl
-							break
-						}
-					}
-					if parentGenDecl != nil {
-						break
-					}
-				}
-			}
-			formatVariable(&result, n, fset, true, true, parentGenDecl, workspaceDir)
+			info = newVariableInfo(
+				ctx,
+				n,
+				fset,
+				options.IncludeReferences,
+				options.IncludeScope,
+				findParentGenDecl(file, n),
+				workspaceDir,
+				overlay,
+			)
 		}
+		return &info
 	}
 
 	// Handle file paths
 	if strings.HasSuffix(path, ".go") {
-		resolvedPath, err := resolveFilePath(path, workspaceDir)
+		resolvedPath, err := overlay.resolveFilePath(path, workspaceDir)
 		if err != nil {
-			return "", fmt.Errorf("failed to resolve file path: %w", err)
+			return nil, fmt.Errorf("failed to resolve file path: %w", err)
 		}
 
+		// Parsing the content from the overlay if there is one, otherwise from disk
+		var src any
+		if content, ok := overlay[resolvedPath]; ok {
+			src = content
+		}
 		fset := token.NewFileSet()
-		file, err := parser.ParseFile(fset, resolvedPath, nil, parser.ParseComments)
+		file, err := parser.ParseFile(fset, resolvedPath, src, parser.ParseComments)
 
 		// Handle syntax errors - we can still work with partial AST
-		var syntaxErrorMsg string
+		result := &InspectResult{}
 		if err != nil {
 			if errList, ok := err.(scanner.ErrorList); ok {
 				// Syntax errors - we have a partial AST, continue with warning
-				syntaxErrorMsg = fmt.Sprintf(
-					"WARNING: Syntax errors found, analysis may be incomplete:\n%s\n\n",
-					errList.Error(),
-				)
+				result.SyntaxErrors = errList.Error()
 			} else {
 				// Other parsing errors - cannot proceed
-				return "", fmt.Errorf("failed to parse file %s: %w", resolvedPath, err)
+				return nil, fmt.Errorf("failed to parse file %s: %w", resolvedPath, err)
 			}
 		}
 
 		// If we have no AST at all, we can't proceed
 		if file == nil {
-			return "", fmt.Errorf(
+			return nil, fmt.Errorf(
 				"failed to parse file %s: no AST generated",
 				resolvedPath,
 			)
 		}
 
-		// Case 1: Format entire file
+		// Case 1: Describe entire file
 		if lineNumber == 0 && symbolName == "" {
-			formatFile(&result, file, fset, includePrivate, true, workspaceDir)
-			return syntaxErrorMsg + result.String(), nil
+			fileInfo := newFileInfo(
+				ctx,
+				file,
+				fset,
+				options.IncludePrivate,
+				options.IncludeImports,
+				options.bodyDetail(),
+				workspaceDir,
+				overlay,
+			)
+			if err := addFile(result, fileInfo); err != nil {
+				return nil, err
+			}
+			return result, nil
 		}
 
 		// Case 2 & 3: Find specific symbol
 		if symbol, found := findSymbol(file.Decls, fset, symbolName, lineNumber); found {
-			formatSymbolWithContext(symbol, fset, file)
-			return syntaxErrorMsg + result.String(), nil
+			result.Symbol = describeSymbolWithContext(symbol, fset, file, nil)
+			return result, nil
 		}
 
 		if symbolName != "" {
-			return "", fmt.Errorf("symbol '%s' not found in file", symbolName)
+			return nil, classifyErrorf(ErrSymbolNotFound, "symbol '%s' not found in file", symbolName)
 		}
-		return "", fmt.Errorf("no symbol found at line %d", lineNumber)
+		return nil, classifyErrorf(ErrSymbolNotFound, "no symbol found at line %d", lineNumber)
 	}
 
 	// Handle package paths
 	resolvedPkgPath, err := resolvePackagePath(path, workspaceDir)
 	if err != nil {
-		return "", fmt.Errorf("failed to resolve package path: %w", err)
+		return nil, fmt.Errorf("failed to resolve package path: %w", err)
+	}
+
+	pkg, err := options.loadedPackages.load(resolvedPkgPath, func() (*packages.Package, error) {
+		return loadPackage(ctx, resolvedPkgPath, workspaceDir, overlay, options.MetadataCacheDir)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	// Case 1: Describe entire package
+	if symbolName == "" {
+		pkgInfo := newPackageInfo(pkg)
+		result := &InspectResult{Package: &pkgInfo, ChangedSince: options.ChangedSince}
+		for _, file := range pkg.Syntax {
+			fileInfo := newFileInfo(ctx, file, pkg.Fset, options.IncludePrivate, false, options.bodyDetail(), workspaceDir, overlay)
+			if err := addFile(result, fileInfo); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	// Case 2: Find specific symbol in package
+	for _, file := range pkg.Syntax {
+		if symbol, found := findSymbol(file.Decls, pkg.Fset, symbolName, 0); found {
+			return &InspectResult{Symbol: describeSymbolWithContext(symbol, pkg.Fset, file, pkg)}, nil
+		}
+	}
+
+	return nil, classifyErrorf(ErrSymbolNotFound, "symbol '%s' not found in package", symbolName)
+}
+
+// loadPackage loads a package with its syntax, from the metadata cache in cacheDir if
+// possible, in which case it has no types
+func loadPackage(ctx context.Context, pkgPath string, workspaceDir string, overlay Overlay, cacheDir string) (*packages.Package, error) {
+	// Overlays are not part of the cache key, so packages are only cached without them
+	cache := packageMetadataCache{dir: cacheDir}
+	useCache := cacheDir != "" && len(overlay) == 0
+	if useCache {
+		if pkg, ok := cache.load(pkgPath, workspaceDir); ok {
+			return pkg, nil
+		}
+	}
+
+	// Dependencies are type checked from source as by the other loads, instead of from the
+	// export data of the toolchain, so the types of the package also serve the lookup of
+	// methods promoted from other packages
 	cfg := &packages.Config{
 		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
-			packages.NeedImports | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax |
 			packages.NeedTypesInfo | packages.NeedModule,
-		Dir: workspaceDir,
+		Dir:     workspaceDir,
+		Env:     packagesEnv(workspaceDir),
+		Overlay: overlay,
 	}
 
-	pkgs, err := packages.Load(cfg, resolvedPkgPath)
+	pkgs, err := loadPackages(ctx, cfg, pkgPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to load package %s: %w", resolvedPkgPath, err)
+		return nil, fmt.Errorf("failed to load package %s: %w", pkgPath, err)
 	}
 
 	if len(pkgs) == 0 {
-		return "", fmt.Errorf("no packages found for path: %s", resolvedPkgPath)
+		return nil, fmt.Errorf("no packages found for path: %s", pkgPath)
 	}
 
 	pkg := pkgs[0]
 	if len(pkg.Errors) > 0 {
-		return "", fmt.Errorf("package has errors: %v", pkg.Errors)
+		return nil, classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
 	}
-
-	// Case 1: Format entire package
-	if symbolName == "" {
-		formatPackage(&result, pkg, includePrivate, workspaceDir)
-		return result.String(), nil
+	if useCache {
+		// The cache only saves time, failing to write it does not fail the inspection
+		_ = cache.store(pkgPath, workspaceDir, pkg)
 	}
+	return pkg, nil
+}
 
-	// Case 2: Find specific symbol in package
-	for _, file := range pkg.Syntax {
-		if symbol, found := findSymbol(file.Decls, pkg.Fset, symbolName, 0); found {
-			formatSymbolWithContext(symbol, pkg.Fset, file)
-			return result.String(), nil
+// findParentGenDecl finds the declaration group (type, var or const block) containing the spec
+func findParentGenDecl(file *ast.File, spec ast.Spec) *ast.GenDecl {
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok {
+			for _, s := range genDecl.Specs {
+				if s == spec {
+					return genDecl
+				}
+			}
 		}
 	}
+	return nil
+}
 
-	return "", fmt.Errorf("symbol '%s' not found in package", symbolName)
+// readDeclarationSource reads the source lines of a declaration, or describes why they could not be read
+func readDeclarationSource(filename string, startLine, endLine int, overlay Overlay) string {
+	rawSource, err := readSourceLines(filename, startLine, endLine, overlay)
+	if err != nil {
+		return fmt.Sprintf("// Error reading source: %v", err)
+	}
+	return rawSource
 }
 
-func formatFunction(
-	b *strings.Builder,
+func newFunctionInfo(
+	ctx context.Context,
 	fn *ast.FuncDecl,
 	fset *token.FileSet,
 	includeReferences bool,
 	includeCallHierarchy bool,
+	detail string,
 	workspaceDir string,
-) {
+	overlay Overlay,
+) SymbolInfo {
 	// Get signature start position
 	sigStart := fset.Position(fn.Pos())
 
@@ -388,42 +958,54 @@ func formatFunction(
 		bodyEnd = fset.Position(fn.End())
 	}
 
-	// Lines section
-	if bodyEnd.Line > sigStart.Line {
-		fmt.Fprintf(b, "Lines: %d-%d\n", sigStart.Line, bodyEnd.Line)
-	} else {
-		fmt.Fprintf(b, "Lines: %d\n", sigStart.Line)
+	info := SymbolInfo{
+		Name:      fn.Name.Name,
+		Kind:      SymbolFunction,
+		File:      sigStart.Filename,
+		StartLine: sigStart.Line,
+		EndLine:   bodyEnd.Line,
+	}
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		info.Kind = SymbolMethod
+		info.Receiver = extractReceiverTypeSimple(fn.Recv.List[0].Type)
 	}
 
-	// Docstring section
 	if fn.Doc != nil {
-		b.WriteString("Docstring: ")
-		b.WriteString(strings.TrimSpace(fn.Doc.Text()))
-		b.WriteString("\n")
+		info.Doc = strings.TrimSpace(fn.Doc.Text())
 	}
 
-	// Code section
-	b.WriteString("Code:\n")
+	includeBody := detail == DetailBody
+	if detail == DetailAuto && fn.Body != nil {
+		if bodyEnd.Line-sigStart.Line < autoDetailMaxLines {
+			includeBody = true
+		} else {
+			info.BodySummary = summarizeBody(fn.Body, fset)
+		}
+	}
 
 	var endLine int
-	// Just signature - end before opening brace or at function end
-	if fn.Body != nil {
+	if includeBody {
+		endLine = bodyEnd.Line
+	} else if fn.Body != nil {
+		// Just signature - end before opening brace or at function end
 		endLine = fset.Position(fn.Body.Pos() - 1).Line
 	} else {
 		endLine = fset.Position(fn.End()).Line
 	}
 
 	// Read the raw source code from the file
-	rawSource, err := readSourceLines(sigStart.Filename, sigStart.Line, endLine)
-	if err == nil {
+	rawSource, err := readSourceLines(sigStart.Filename, sigStart.Line, endLine, overlay)
+	if includeBody && err == nil {
+		info.Code = rawSource
+	} else if err == nil {
 		// Remove opening bracket if present at the end
 		trimmed := strings.TrimSpace(rawSource)
 		if strings.HasSuffix(trimmed, "{") {
 			trimmed = strings.TrimSpace(trimmed[:len(trimmed)-1])
 		}
-		b.WriteString(trimmed)
+		info.Code = trimmed
 	} else {
-		fmt.Fprintf(b, "// Error reading source: %v", err)
+		info.Code = fmt.Sprintf("// Error reading source: %v", err)
 	}
 
 	// Only include references/call hierarchy if the file is within the workspace
@@ -431,207 +1013,360 @@ func formatFunction(
 
 	// Include references if requested and file is in workspace
 	if includeReferences && isInWorkspace {
-		b.WriteString("\n\n")
-		formatReferences(b, sigStart.Filename, sigStart.Line, fn.Name.Name)
+		info.References = append(
+			info.References,
+			findReferences(ctx, sigStart.Filename, sigStart.Line, fn.Name.Name, overlay),
+		)
 	}
 
 	// Include call hierarchy if requested and file is in workspace
 	if includeCallHierarchy && isInWorkspace {
-		b.WriteString("\n\n")
-		formatCallHierarchy(b, sigStart.Filename, sigStart.Line, fn.Name.Name)
+		info.CallHierarchy = findCallHierarchy(ctx, sigStart.Filename, sigStart.Line, fn.Name.Name, overlay)
 	}
+	return info
 }
 
-func formatType(
-	b *strings.Builder,
+// summarizeBody counts the lines, branches and loops of a function body and lists the
+// functions it calls in order of their first call
+func summarizeBody(body *ast.BlockStmt, fset *token.FileSet) *BodySummary {
+	summary := &BodySummary{
+		Lines: fset.Position(body.End()).Line - fset.Position(body.Pos()).Line + 1,
+	}
+	called := make(map[string]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.IfStmt, *ast.CaseClause, *ast.CommClause:
+			summary.Branches++
+		case *ast.ForStmt, *ast.RangeStmt:
+			summary.Loops++
+		case *ast.CallExpr:
+			// Calls of function literals and conversions to composite types have no name worth listing
+			switch fun := ast.Unparen(node.Fun).(type) {
+			case *ast.Ident, *ast.SelectorExpr:
+				name := types.ExprString(fun)
+				if !called[name] {
+					called[name] = true
+					summary.Calls = append(summary.Calls, name)
+				}
+			}
+		}
+		return true
+	})
+	return summary
+}
+
+// newTypeInfo describes a type declaration. Its methods and promoted fields are looked up
+// in pkg, the type checked package of the declaration, or nil to load it.
+func newTypeInfo(
+	ctx context.Context,
 	typeSpec *ast.TypeSpec,
 	fset *token.FileSet,
+	pkg *packages.Package,
 	includeReferences bool,
 	includeImplementers bool,
 	includeMethods bool,
+	expandEmbedded bool,
+	detail string,
 	parentGenDecl *ast.GenDecl,
 	workspaceDir string,
-) {
+	overlay Overlay,
+) SymbolInfo {
 	// Get type start and end positions
 	start := fset.Position(typeSpec.Pos())
 	end := fset.Position(typeSpec.End())
 
-	// Lines section
-	if end.Line > start.Line {
-		fmt.Fprintf(b, "Lines: %d-%d\n", start.Line, end.Line)
-	} else {
-		fmt.Fprintf(b, "Lines: %d\n", start.Line)
+	info := SymbolInfo{
+		Name:      typeSpec.Name.Name,
+		Kind:      SymbolType,
+		File:      start.Filename,
+		StartLine: start.Line,
+		EndLine:   end.Line,
+		Code:      readDeclarationSource(start.Filename, start.Line, end.Line, overlay),
 	}
 
-	// Docstring section - check TypeSpec first, then parentGenDecl if provided
-	var docText string
+	// Docstring - check TypeSpec first, then parentGenDecl if provided
 	if typeSpec.Doc != nil {
-		docText = typeSpec.Doc.Text()
+		info.Doc = strings.TrimSpace(typeSpec.Doc.Text())
 	} else if parentGenDecl != nil && parentGenDecl.Doc != nil {
-		docText = parentGenDecl.Doc.Text()
-	}
-
-	if docText != "" {
-		b.WriteString("Docstring: ")
-		b.WriteString(strings.TrimSpace(docText))
-		b.WriteString("\n")
+		info.Doc = strings.TrimSpace(parentGenDecl.Doc.Text())
 	}
 
-	// Code section
-	b.WriteString("Code:\n")
-
-	// Read the raw source code from the file
-	rawSource, err := readSourceLines(start.Filename, start.Line, end.Line)
-	if err == nil {
-		b.WriteString(rawSource)
-	} else {
-		fmt.Fprintf(b, "// Error reading source: %v", err)
-	}
+	isInWorkspace := isFileInWorkspace(start.Filename, workspaceDir)
 
 	// Include implementers if requested and type is an interface and file is in workspace
-	if interfaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok &&
-		interfaceType.Methods != nil {
-		isInWorkspace := isFileInWorkspace(start.Filename, workspaceDir)
-		if includeImplementers && isInWorkspace {
-			b.WriteString("\n\n")
-			formatImplementers(b, start.Filename, start.Line, typeSpec.Name.Name)
+	if interfaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+		info.Kind = SymbolInterface
+		if interfaceType.Methods != nil && includeImplementers && isInWorkspace {
+			info.Implementers = findImplementers(ctx, start.Filename, start.Line, typeSpec.Name.Name, overlay)
 		}
 	}
 
-	// Include methods if requested
-	if includeMethods {
-		// Find all methods for this type by parsing the file and looking for method receivers
-		cachedFile, err := globalFileCache.GetOrParseFile(start.Filename)
+	// Include methods and promoted fields of concrete types if requested. The method set
+	// of the type checked package has the methods declared in all files of the package and
+	// those promoted from embedded fields. The methods of the file are used when the
+	// package cannot be type checked.
+	if (includeMethods || expandEmbedded) && info.Kind != SymbolInterface {
+		pkg, obj, err := lookupTypeName(ctx, pkg, start.Filename, typeSpec.Name.Name, overlay)
 		if err == nil {
-			file := cachedFile.ast
-			fileFset := cachedFile.fset
-
-			// Find methods with this type as receiver
-			var methods []*ast.FuncDecl
-			for _, decl := range file.Decls {
+			info.Methods = typeMethods(ctx, pkg, obj, detail, workspaceDir, overlay)
+			if !includeMethods {
+				info.Methods = slices.DeleteFunc(info.Methods, func(method SymbolInfo) bool {
+					return method.PromotedFrom == ""
+				})
+			}
+			if expandEmbedded {
+				info.PromotedFields = promotedFields(pkg, obj)
+			}
+		} else if cachedFile, err := globalFileCache.GetOrParseFile(start.Filename, overlay); err == nil && includeMethods {
+			for _, decl := range cachedFile.ast.Decls {
 				if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv != nil {
 					// Check if this method has our type as receiver
-					if len(funcDecl.Recv.List) > 0 {
-						receiverType := extractReceiverTypeName(
-							funcDecl.Recv.List[0].Type,
+					if len(funcDecl.Recv.List) > 0 &&
+						extractReceiverTypeName(funcDecl.Recv.List[0].Type) == typeSpec.Name.Name {
+						info.Methods = append(
+							info.Methods,
+							newFunctionInfo(ctx, funcDecl, cachedFile.fset, false, false, detail, workspaceDir, overlay),
 						)
-						if receiverType == typeSpec.Name.Name {
-							methods = append(methods, funcDecl)
-						}
 					}
 				}
 			}
+		}
+	}
+
+	// Include references if requested and file is in workspace
+	if includeReferences && isInWorkspace {
+		info.References = append(
+			info.References,
+			findReferences(ctx, start.Filename, start.Line, typeSpec.Name.Name, overlay),
+		)
+	}
+	return info
+}
+
+// lookupTypeName looks up the concrete type typeName in pkg, first loading and type
+// checking the package of filePath when pkg is nil or has no types, e.g. from the cache
+func lookupTypeName(ctx context.Context, pkg *packages.Package, filePath string, typeName string, overlay Overlay) (*packages.Package, *types.TypeName, error) {
+	if pkg == nil || pkg.Types == nil {
+		var err error
+		if pkg, err = loadTypeCheckedPackage(ctx, filePath, overlay); err != nil {
+			return nil, nil, err
+		}
+	}
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok || types.IsInterface(obj.Type()) {
+		return nil, nil, fmt.Errorf("no concrete type '%s' in the package of %s", typeName, filePath)
+	}
+	return pkg, obj, nil
+}
+
+// loadTypeCheckedPackage loads and type checks the package of filePath
+func loadTypeCheckedPackage(ctx context.Context, filePath string, overlay Overlay) (*packages.Package, error) {
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:     filepath.Dir(filePath),
+		Env:     packagesEnv(filepath.Dir(filePath)),
+		Overlay: overlay,
+	}, "file="+filePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		if slices.Contains(pkg.GoFiles, filePath) && pkg.Types != nil && len(pkg.Errors) == 0 {
+			return pkg, nil
+		}
+	}
+	return nil, fmt.Errorf("the package of %s could not be type checked", filePath)
+}
+
+// typeMethods describes the methods of the type obj and of pointers to it, declared
+// methods in source order then promoted methods
+func typeMethods(ctx context.Context, pkg *packages.Package, obj *types.TypeName, detail string, workspaceDir string, overlay Overlay) []SymbolInfo {
+	var declared, promoted []SymbolInfo
+	for selection := range types.NewMethodSet(types.NewPointer(obj.Type())).Methods() {
+		method := selection.Obj().(*types.Func)
+		if !method.Exported() && method.Pkg() != pkg.Types {
+			continue
+		}
+		position := pkg.Fset.Position(method.Pos())
+		cachedFile, err := globalFileCache.GetOrParseFile(position.Filename, overlay)
+		if err != nil {
+			continue
+		}
+		for _, decl := range cachedFile.ast.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || funcDecl.Name.Name != method.Name() ||
+				cachedFile.fset.Position(funcDecl.Name.Pos()).Line != position.Line {
+				continue
+			}
+			info := newFunctionInfo(ctx, funcDecl, cachedFile.fset, false, false, detail, workspaceDir, overlay)
+			if len(selection.Index()) == 1 {
+				declared = append(declared, info)
+				break
+			}
+			info.PromotedFrom, _ = embeddedFieldPath(obj.Type(), selection.Index())
+			if recv := method.Signature().Recv(); recv != nil {
+				info.Origin = types.TypeString(recv.Type(), types.RelativeTo(pkg.Types))
+			}
+			promoted = append(promoted, info)
+			break
+		}
+	}
+	slices.SortStableFunc(declared, func(a, b SymbolInfo) int {
+		return cmp.Or(strings.Compare(a.File, b.File), cmp.Compare(a.StartLine, b.StartLine))
+	})
+	return append(declared, promoted...)
+}
 
-			// Format each method
-			for _, method := range methods {
-				b.WriteString("\n\n")
-				formatFunction(b, method, fileFset, false, false, workspaceDir)
+// promotedFields lists the fields of the structs embedded in the struct type obj that
+// are promoted to it, shallowest first. Fields shadowed by shallower ones, ambiguous
+// at their depth or unexported in another package are left out.
+func promotedFields(pkg *packages.Package, obj *types.TypeName) []PromotedField {
+	var fields []PromotedField
+	seen := make(map[string]bool)
+	visited := make(map[types.Type]bool)
+	level := []types.Type{obj.Type()}
+	for depth := 0; len(level) > 0; depth++ {
+		var next []types.Type
+		for _, t := range level {
+			if pointer, ok := t.Underlying().(*types.Pointer); ok {
+				t = pointer.Elem()
+			}
+			if visited[t] {
+				continue
+			}
+			visited[t] = true
+			structType, ok := t.Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for field := range structType.Fields() {
+				if field.Embedded() {
+					next = append(next, field.Type())
+				}
+				if depth == 0 || seen[field.Name()] || (!field.Exported() && field.Pkg() != pkg.Types) {
+					continue
+				}
+				seen[field.Name()] = true
+				// The selector rules of the spec decide which of the fields of a name is
+				// promoted, if any
+				selected, index, _ := types.LookupFieldOrMethod(obj.Type(), true, field.Pkg(), field.Name())
+				if selected != field {
+					continue
+				}
+				path, origin := embeddedFieldPath(obj.Type(), index)
+				fields = append(fields, PromotedField{
+					Name:   field.Name(),
+					Type:   types.TypeString(field.Type(), types.RelativeTo(pkg.Types)),
+					Origin: types.TypeString(origin, types.RelativeTo(pkg.Types)),
+					Path:   path,
+				})
 			}
 		}
+		level = next
 	}
+	return fields
+}
 
-	// Include references if requested and file is in workspace
-	isInWorkspace := isFileInWorkspace(start.Filename, workspaceDir)
-	if includeReferences && isInWorkspace {
-		b.WriteString("\n\n")
-		formatReferences(b, start.Filename, start.Line, typeSpec.Name.Name)
+// embeddedFieldPath names the embedded fields a promoted field or method is selected
+// through by the index of its selection, e.g. Base.Logger, and returns the type of the
+// last of them
+func embeddedFieldPath(t types.Type, index []int) (string, types.Type) {
+	var names []string
+	for _, i := range index[:len(index)-1] {
+		if pointer, ok := t.Underlying().(*types.Pointer); ok {
+			t = pointer.Elem()
+		}
+		structType, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		field := structType.Field(i)
+		names = append(names, field.Name())
+		t = field.Type()
 	}
+	return strings.Join(names, "."), t
 }
 
-func formatVariable(
-	b *strings.Builder,
+func newVariableInfo(
+	ctx context.Context,
 	valueSpec *ast.ValueSpec,
 	fset *token.FileSet,
 	includeReferences bool,
 	includeScope bool,
 	parentGenDecl *ast.GenDecl,
 	workspaceDir string,
-) {
+	overlay Overlay,
+) SymbolInfo {
 	// Get variable start and end positions
 	start := fset.Position(valueSpec.Pos())
 	end := fset.Position(valueSpec.End())
 
-	// Lines section
-	if end.Line > start.Line {
-		fmt.Fprintf(b, "Lines: %d-%d\n", start.Line, end.Line)
-	} else {
-		fmt.Fprintf(b, "Lines: %d\n", start.Line)
+	info := SymbolInfo{
+		Kind:      SymbolVariable,
+		File:      start.Filename,
+		StartLine: start.Line,
+		EndLine:   end.Line,
+		Code:      readDeclarationSource(start.Filename, start.Line, end.Line, overlay),
 	}
-
-	// Docstring section - check ValueSpec first, then parentGenDecl if provided
-	var docText string
-	if valueSpec.Doc != nil {
-		docText = valueSpec.Doc.Text()
-	} else if parentGenDecl != nil && parentGenDecl.Doc != nil {
-		docText = parentGenDecl.Doc.Text()
+	if parentGenDecl != nil && parentGenDecl.Tok == token.CONST {
+		info.Kind = SymbolConstant
 	}
-
-	if docText != "" {
-		b.WriteString("Docstring: ")
-		b.WriteString(strings.TrimSpace(docText))
-		b.WriteString("\n")
+	for _, name := range valueSpec.Names {
+		info.Names = append(info.Names, name.Name)
+	}
+	if len(info.Names) > 0 {
+		info.Name = info.Names[0]
 	}
 
-	// Code section
-	b.WriteString("Code:\n")
-
-	// Read the raw source code from the file
-	rawSource, err := readSourceLines(start.Filename, start.Line, end.Line)
-	if err == nil {
-		b.WriteString(rawSource)
-	} else {
-		fmt.Fprintf(b, "// Error reading source: %v", err)
+	// Docstring - check ValueSpec first, then parentGenDecl if provided
+	if valueSpec.Doc != nil {
+		info.Doc = strings.TrimSpace(valueSpec.Doc.Text())
+	} else if parentGenDecl != nil && parentGenDecl.Doc != nil {
+		info.Doc = strings.TrimSpace(parentGenDecl.Doc.Text())
 	}
 
 	if includeScope {
-		b.WriteString("\n")
-		formatScope(b, start.Filename, start.Line)
+		info.Scope = findScope(start.Filename, start.Line, overlay)
 	}
 
 	// Include references if requested and file is in workspace
-	isInWorkspace := isFileInWorkspace(start.Filename, workspaceDir)
-	if includeReferences && isInWorkspace {
+	if includeReferences && isFileInWorkspace(start.Filename, workspaceDir) {
 		// Handle multiple variable names in a single declaration
 		for _, name := range valueSpec.Names {
-			b.WriteString("\n\n")
-			formatReferences(b, start.Filename, start.Line, name.Name)
+			info.References = append(
+				info.References,
+				findReferences(ctx, start.Filename, start.Line, name.Name, overlay),
+			)
 		}
 	}
+	return info
 }
 
-func formatFile(
-	b *strings.Builder,
+func newFileInfo(
+	ctx context.Context,
 	file *ast.File,
 	fset *token.FileSet,
 	includePrivate bool,
 	includeImports bool,
+	detail string,
 	workspaceDir string,
-) {
-	lineWritten := false
-	addSeparator := func() {
-		if lineWritten {
-			b.WriteString("\n\n")
-		}
-		lineWritten = true
-	}
+	overlay Overlay,
+) FileInfo {
+	var info FileInfo
 
 	// Include absolute file path at the top
 	if file.Pos().IsValid() {
-		addSeparator()
-		pos := fset.Position(file.Pos())
-		fmt.Fprintf(b, "File: %s", pos.Filename)
+		info.Path = fset.Position(file.Pos()).Filename
 	}
 
 	// Include file docstring if present
 	if file.Doc != nil {
-		addSeparator()
-		b.WriteString("File Docstring:\n")
-		b.WriteString(strings.TrimSpace(file.Doc.Text()))
+		info.Doc = strings.TrimSpace(file.Doc.Text())
 	}
 
 	// First pass: handle imports if requested
 	if includeImports {
-		var imports []string
 		for _, decl := range file.Decls {
 			if genDecl, ok := decl.(*ast.GenDecl); ok {
 				for _, spec := range genDecl.Specs {
@@ -641,30 +1376,13 @@ func formatFile(
 						end := fset.Position(importSpec.End())
 
 						// Read the raw source code from the file
-						rawSource, err := readSourceLines(
-							start.Filename,
-							start.Line,
-							end.Line,
-						)
-						if err == nil {
-							imports = append(imports, strings.TrimSpace(rawSource))
-						} else {
-							imports = append(imports, fmt.Sprintf("// Error reading source: %v", err))
-						}
+						info.Imports = append(info.Imports, strings.TrimSpace(
+							readDeclarationSource(start.Filename, start.Line, end.Line, overlay),
+						))
 					}
 				}
 			}
 		}
-
-		// Write all imports under a single header if any were found
-		if len(imports) > 0 {
-			addSeparator()
-			b.WriteString("Imports:\n")
-			for _, imp := range imports {
-				b.WriteString(imp)
-				b.WriteString("\n")
-			}
-		}
 	}
 
 	// Second pass: handle all other declarations
@@ -673,8 +1391,7 @@ func formatFile(
 		case *ast.FuncDecl:
 			// Only include exported functions/methods or if includePrivate is true
 			if includePrivate || ast.IsExported(d.Name.Name) {
-				addSeparator()
-				formatFunction(b, d, fset, false, false, workspaceDir)
+				info.Symbols = append(info.Symbols, newFunctionInfo(ctx, d, fset, false, false, detail, workspaceDir, overlay))
 			}
 
 		case *ast.GenDecl:
@@ -688,8 +1405,10 @@ func formatFile(
 				case *ast.TypeSpec:
 					// Only include exported types or if includePrivate is true
 					if includePrivate || ast.IsExported(s.Name.Name) {
-						addSeparator()
-						formatType(b, s, fset, false, false, false, d, workspaceDir)
+						info.Symbols = append(
+							info.Symbols,
+							newTypeInfo(ctx, s, fset, nil, false, false, false, false, DetailSignature, d, workspaceDir, overlay),
+						)
 					}
 
 				case *ast.ValueSpec:
@@ -706,101 +1425,98 @@ func formatFile(
 					}
 
 					if shouldInclude {
-						addSeparator()
-						formatVariable(b, s, fset, false, false, d, workspaceDir)
+						info.Symbols = append(
+							info.Symbols,
+							newVariableInfo(ctx, s, fset, false, false, d, workspaceDir, overlay),
+						)
 					}
 				}
 			}
 		}
 	}
+	return info
 }
 
-func formatPackage(
-	b *strings.Builder,
-	pkg *packages.Package,
-	includePrivate bool,
-	workspaceDir string,
-) {
+// newPackageInfo describes the directory and import path of a package, leaving its files
+// to the caller
+func newPackageInfo(pkg *packages.Package) PackageInfo {
+	var info PackageInfo
+
 	// Add absolute directory path
 	if pkg.Module != nil && pkg.Module.Dir != "" {
-		fmt.Fprintf(b, "Directory: %s", pkg.Module.Dir)
+		info.Directory = pkg.Module.Dir
 		if pkg.PkgPath != pkg.Module.Path {
 			// For subpackages, append the relative path
 			relPath := strings.TrimPrefix(pkg.PkgPath, pkg.Module.Path)
 			if relPath != "" && relPath != "/" {
 				relPath = strings.TrimPrefix(relPath, "/")
-				fmt.Fprintf(b, "/%s", relPath)
+				info.Directory += "/" + relPath
 			}
 		}
-		b.WriteString("\n")
 	} else if len(pkg.GoFiles) > 0 {
 		// Fallback: use directory of first Go file
-		dir := filepath.Dir(pkg.GoFiles[0])
-		fmt.Fprintf(b, "Directory: %s\n", dir)
+		info.Directory = filepath.Dir(pkg.GoFiles[0])
 	}
 
 	// Add Go import path
-	if pkg.PkgPath != "" {
-		fmt.Fprintf(b, "Import Path: %s\n", pkg.PkgPath)
-	}
+	info.ImportPath = pkg.PkgPath
 
-	// Triple line break before file contents
-	b.WriteString("\n\n")
+	return info
+}
 
-	fileWritten := false
+// parseGoplsLocation parses a location printed by gopls: /path/to/file.go:line:startCol-endCol
+func parseGoplsLocation(location string) (filePath string, lineNumber int, ok bool) {
+	parts := strings.Split(location, ":")
+	if len(parts) < 3 {
+		return "", 0, false
+	}
 
-	for _, file := range pkg.Syntax {
-		if fileWritten {
-			b.WriteString("\n---\n")
-		}
-		fileWritten = true
+	// File path is everything except the last two parts
+	filePath = strings.Join(parts[:len(parts)-2], ":")
 
-		formatFile(
-			b,
-			file,
-			pkg.Fset,
-			includePrivate,
-			false,
-			workspaceDir,
-		)
+	lineNumber, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil {
+		return "", 0, false
 	}
+	return filePath, lineNumber, true
 }
 
-// formatReferences finds and formats references to a symbol using gopls
-func formatReferences(
-	b *strings.Builder,
+// findReferences finds references to a symbol using gopls
+func findReferences(
+	ctx context.Context,
 	filePath string,
 	lineNumber int,
 	symbolName string,
-) {
-	b.WriteString("References:\n")
+	overlay Overlay,
+) ReferenceList {
+	references := ReferenceList{Symbol: symbolName}
+
+	if reason := overlay.goplsUnavailableReason(filePath, "References"); reason != "" {
+		references.Error = reason
+		return references
+	}
 
 	if filePath == "" || lineNumber <= 0 || symbolName == "" {
-		b.WriteString("Invalid parameters for finding references\n")
-		return
+		references.Error = "Invalid parameters for finding references"
+		return references
 	}
 
 	// Create gopls position using utility function
 	position, err := createGoplsPosition(filePath, lineNumber, symbolName)
 	if err != nil {
-		fmt.Fprintf(b, "Failed to find references: %s\n", err.Error())
-		return
+		references.Error = fmt.Sprintf("Failed to find references: %s", err.Error())
+		return references
 	}
 
 	// Execute gopls references command using utility function
-	outputStr, err := executeGoplsCommand("references", position)
+	outputStr, err := executeGoplsCommand(ctx, "references", position)
 	if err != nil {
-		fmt.Fprintf(b, "gopls references failed: %s\n", err.Error())
+		references.Error = fmt.Sprintf("gopls references failed: %s", err.Error())
+		return references
 	}
 
-	if outputStr == "" {
-		b.WriteString("No references found\n")
-		return
-	}
-
-	// Parse gopls output and group references
-	functionScopes := make(map[string]bool) // Track functions we've already formatted
-	packageFiles := make(map[string]bool)   // Track package-level files
+	// Functions are described once even if they contain several references
+	functions := make(map[string]*SymbolInfo)
 
 	for line := range strings.SplitSeq(outputStr, "\n") {
 		line = strings.TrimSpace(line)
@@ -808,285 +1524,233 @@ func formatReferences(
 			continue
 		}
 
-		// Parse location: /path/to/file.go:line:startCol-endCol
-		parts := strings.Split(line, ":")
-		if len(parts) < 3 {
-			continue
-		}
-
-		// File path is everything except the last two parts
-		fp := strings.Join(parts[:len(parts)-2], ":")
-
-		// Parse line number
-		ln, err := strconv.Atoi(parts[len(parts)-2])
-		if err != nil {
+		fp, ln, ok := parseGoplsLocation(line)
+		if !ok {
 			continue
 		}
+		reference := Reference{File: fp, Line: ln}
 
-		// Determine the scope for this reference
-		scope, err := determineScope(fp, ln)
-		if err != nil {
-			// Fallback to file-level grouping (package scope)
-			packageFiles[fp] = true
-		} else if scope != fp {
-			// This is a function scope
-			functionScopes[scope] = true
-		} else {
-			// This is package scope
-			packageFiles[fp] = true
-		}
-	}
-
-	if len(functionScopes) == 0 && len(packageFiles) == 0 {
-		b.WriteString("No references found\n")
-		return
-	}
-
-	lineWritten := false
-	addSeparator := func() {
-		if lineWritten {
-			b.WriteString("\n\n")
+		// Determine the function containing the reference, if any
+		if funcDecl, fset := findFunctionAtLine(fp, ln, overlay); funcDecl != nil {
+			key := fmt.Sprintf("%s:%d", fp, fset.Position(funcDecl.Pos()).Line)
+			function, ok := functions[key]
+			if !ok {
+				info := newFunctionInfo(ctx, funcDecl, fset, false, false, DetailSignature, "", overlay)
+				function = &info
+				functions[key] = function
+			}
+			reference.Function = function
 		}
-		lineWritten = true
+		references.References = append(references.References, reference)
 	}
 
-	// Format function-level references
-	for scope := range functionScopes {
-		// Parse scope format: /path/to/file.go:line:functionName
-		parts := strings.Split(scope, ":")
-		if len(parts) < 3 {
-			continue
-		}
-
-		fp := strings.Join(parts[:len(parts)-2], ":")
-		ln, err := strconv.Atoi(parts[len(parts)-2])
-		if err != nil {
-			continue
-		}
+	sortReferences(references.References)
+	return references
+}
 
-		// Parse the file to find the function using AST cache
-		cachedFile, err := globalFileCache.GetOrParseFile(fp)
-		if err != nil {
-			fmt.Fprintf(b, "  Error parsing file %s: %v\n", fp, err)
-			continue
+// sortReferences orders references by file and line so results are deterministic
+func sortReferences(references []Reference) {
+	sort.SliceStable(references, func(i, j int) bool {
+		if references[i].File != references[j].File {
+			return references[i].File < references[j].File
 		}
+		return references[i].Line < references[j].Line
+	})
+}
 
-		file := cachedFile.ast
-		fset := cachedFile.fset
-
-		// Find the function at the specified line
-		for _, decl := range file.Decls {
-			funcDecl, ok := decl.(*ast.FuncDecl)
-			if !ok {
-				continue
-			}
-			funcStart := fset.Position(funcDecl.Pos()).Line
-			funcEnd := fset.Position(funcDecl.End()).Line
-
-			if ln < funcStart || ln > funcEnd {
-				continue // Not in this function
-			}
-			// Format the function using a temporary builder
-			var tempBuilder strings.Builder
-			formatFunction(&tempBuilder, funcDecl, fset, false, false, "")
-
-			// Indent each line of the function output
-			functionOutput := tempBuilder.String()
-			for line := range strings.SplitSeq(functionOutput, "\n") {
-				if line == "" {
-					continue
-				}
-				addSeparator()
-				fmt.Fprintf(b, "  %s\n", line)
-			}
-			break
-		}
+// findFunctionAtLine finds the function declaration containing the line, if any
+func findFunctionAtLine(filePath string, lineNumber int, overlay Overlay) (*ast.FuncDecl, *token.FileSet) {
+	// Parse the file to find the containing function using AST cache
+	cachedFile, err := globalFileCache.GetOrParseFile(filePath, overlay)
+	if err != nil {
+		return nil, nil
 	}
 
-	// Format package-level references
-	for fp := range packageFiles {
-		addSeparator()
-		fmt.Fprintf(b, "  Package scope: %s\n", fp)
+	for _, decl := range cachedFile.ast.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok &&
+			containsLine(cachedFile.fset, funcDecl, lineNumber) {
+			return funcDecl, cachedFile.fset
+		}
 	}
+	return nil, nil
 }
 
-// formatImplementers finds and formats implementers of an interface using gopls
-func formatImplementers(
-	b *strings.Builder,
+// findImplementers finds implementers of an interface using gopls
+func findImplementers(
+	ctx context.Context,
 	filePath string,
 	lineNumber int,
 	symbolName string,
-) {
-	b.WriteString("Implementers:\n")
+	overlay Overlay,
+) *ImplementerList {
+	implementers := &ImplementerList{}
+
+	if reason := overlay.goplsUnavailableReason(filePath, "Implementers"); reason != "" {
+		implementers.Error = reason
+		return implementers
+	}
 
 	if filePath == "" || lineNumber <= 0 || symbolName == "" {
-		b.WriteString("Invalid parameters for finding implementers\n")
-		return
+		implementers.Error = "Invalid parameters for finding implementers"
+		return implementers
 	}
 
 	// Create gopls position using utility function
 	position, err := createGoplsPosition(filePath, lineNumber, symbolName)
 	if err != nil {
-		fmt.Fprintf(b, "Failed to find implementers: %s\n", err.Error())
-		return
+		implementers.Error = fmt.Sprintf("Failed to find implementers: %s", err.Error())
+		return implementers
 	}
 
 	// Execute gopls implementation command using utility function
-	outputStr, err := executeGoplsCommand("implementation", position)
+	outputStr, err := executeGoplsCommand(ctx, "implementation", position)
 	if err != nil {
-		fmt.Fprintf(b, "gopls implementation failed: %s\n", err.Error())
-		return
+		implementers.Error = fmt.Sprintf("gopls implementation failed: %s", err.Error())
+		return implementers
 	}
 
-	if outputStr == "" {
-		b.WriteString("No implementers found\n")
-		return
-	}
-
-	// Parse gopls output to get implementer locations
-	lines := strings.Split(outputStr, "\n")
-	implementers := make(map[string][]int)
-
-	for _, line := range lines {
+	for line := range strings.SplitSeq(outputStr, "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
 
-		// Parse location: /path/to/file.go:line:startCol-endCol
-		parts := strings.Split(line, ":")
-		if len(parts) < 3 {
+		fp, ln, ok := parseGoplsLocation(line)
+		if !ok {
 			continue
 		}
+		implementer := Implementer{File: fp, Line: ln}
 
-		// File path is everything except the last two parts
-		fp := strings.Join(parts[:len(parts)-2], ":")
-
-		// Parse line number
-		ln, err := strconv.Atoi(parts[len(parts)-2])
+		// Parse the file to find the type at the implementer location using AST cache
+		cachedFile, err := globalFileCache.GetOrParseFile(fp, overlay)
 		if err != nil {
-			continue
+			implementer.Error = fmt.Sprintf("Error parsing file %s: %v", fp, err)
+		} else if typeSpec := findTypeAtLine(cachedFile.ast, cachedFile.fset, ln); typeSpec == nil {
+			implementer.Error = fmt.Sprintf("No type found at %s:%d", fp, ln)
+		} else {
+			info := newTypeInfo(ctx, typeSpec, cachedFile.fset, nil, false, false, false, false, DetailSignature, nil, "", overlay)
+			implementer.Type = &info
 		}
-
-		implementers[fp] = append(implementers[fp], ln)
+		implementers.Implementers = append(implementers.Implementers, implementer)
 	}
 
-	if len(implementers) == 0 {
-		b.WriteString("No implementers found\n")
-		return
-	}
+	annotateImplementerReceivers(ctx, filePath, symbolName, implementers.Implementers, overlay)
+
+	// Group implementers by file, keeping the gopls order within each file
+	sort.SliceStable(implementers.Implementers, func(i, j int) bool {
+		return implementers.Implementers[i].File < implementers.Implementers[j].File
+	})
+	return implementers
+}
 
-	lineWritten := false
-	addSeparator := func() {
-		if lineWritten {
-			b.WriteString("\n\n")
+// annotateImplementerReceivers type checks the interface with its implementers and records
+// whether values of each implementing type satisfy it, or only pointers as some methods have
+// pointer receivers. Implementers that cannot be type checked are left as they are.
+func annotateImplementerReceivers(ctx context.Context, filePath string, interfaceName string, implementers []Implementer, overlay Overlay) {
+	patterns := []string{"file=" + filePath}
+	for _, implementer := range implementers {
+		if implementer.Type != nil && !slices.Contains(patterns, "file="+implementer.File) {
+			patterns = append(patterns, "file="+implementer.File)
 		}
-		lineWritten = true
 	}
-
-	// Format each implementer using formatType
-	for fp, lns := range implementers {
-		for _, ln := range lns {
-			// Parse the file to find the type at the implementer location using AST cache
-			cachedFile, err := globalFileCache.GetOrParseFile(fp)
-			if err != nil {
-				addSeparator()
-				fmt.Fprintf(b, "  Error parsing file %s: %v\n", fp, err)
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:     filepath.Dir(filePath),
+		Env:     packagesEnv(filepath.Dir(filePath)),
+		Overlay: overlay,
+	}, patterns...)
+	if err != nil {
+		return
+	}
+	// packageOf returns the type checked package and syntax of a file
+	packageOf := func(path string) (*packages.Package, *ast.File) {
+		for _, pkg := range pkgs {
+			if pkg.Types == nil || pkg.TypesInfo == nil || !slices.Contains(pkg.GoFiles, path) {
 				continue
 			}
-
-			file := cachedFile.ast
-			fset := cachedFile.fset
-
-			// Find the type declaration at the specified line
-			typeSpec := findTypeAtLine(file, fset, ln)
-			if typeSpec == nil {
-				addSeparator()
-				fmt.Fprintf(b, "  No type found at %s:%d\n", fp, ln)
-				continue
+			for _, file := range pkg.Syntax {
+				if pkg.Fset.Position(file.Pos()).Filename == path {
+					return pkg, file
+				}
 			}
-			// Format the type using a temporary builder
-			var tempBuilder strings.Builder
-			formatType(&tempBuilder, typeSpec, fset, false, false, false, nil, "")
-
-			// Indent each line of the type output
-			typeOutput := tempBuilder.String()
-			for line := range strings.SplitSeq(typeOutput, "\n") {
-				if line == "" {
-					continue
+		}
+		return nil, nil
+	}
+
+	pkg, _ := packageOf(filePath)
+	if pkg == nil {
+		return
+	}
+	obj, ok := pkg.Types.Scope().Lookup(interfaceName).(*types.TypeName)
+	if !ok {
+		return
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return
+	}
+	for i := range implementers {
+		implementer := &implementers[i]
+		if implementer.Type == nil {
+			continue
+		}
+		pkg, file := packageOf(implementer.File)
+		if pkg == nil {
+			continue
+		}
+		typeSpec := findTypeAtLine(file, pkg.Fset, implementer.Line)
+		if typeSpec == nil {
+			continue
+		}
+		typeName, ok := pkg.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
+		// Interfaces implement interfaces by their method lists, and generic types only
+		// once instantiated
+		if !ok || types.IsInterface(typeName.Type()) {
+			continue
+		}
+		if named, ok := typeName.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			continue
+		}
+		t := typeName.Type()
+		name := typeName.Name()
+		switch {
+		case types.Implements(t, iface):
+			implementer.Satisfies = []string{name, "*" + name}
+		case types.Implements(types.NewPointer(t), iface):
+			implementer.Satisfies = []string{"*" + name}
+			values := types.NewMethodSet(t)
+			for method := range iface.Methods() {
+				if values.Lookup(method.Pkg(), method.Name()) == nil {
+					implementer.PointerMethods = append(implementer.PointerMethods, method.Name())
 				}
-				addSeparator()
-				fmt.Fprintf(b, "  %s\n", line)
 			}
 		}
 	}
 }
 
-// formatScope formats scope hierarchy information for a given file position
-func formatScope(
-	b *strings.Builder,
+// findScope determines the scope hierarchy for a given file position
+func findScope(
 	filePath string,
 	lineNumber int,
-) {
-	b.WriteString("Scope:\n")
-
+	overlay Overlay,
+) *ScopeInfo {
 	if filePath == "" || lineNumber <= 0 {
-		b.WriteString("Invalid parameters for determining scope\n")
-		return
+		return &ScopeInfo{Error: "Invalid parameters for determining scope"}
 	}
 
 	// Parse the file to find scope information using AST cache
-	cachedFile, err := globalFileCache.GetOrParseFile(filePath)
+	cachedFile, err := globalFileCache.GetOrParseFile(filePath, overlay)
 	if err != nil {
-		fmt.Fprintf(b, "Error parsing file: %v\n", err)
-		return
+		return &ScopeInfo{Error: fmt.Sprintf("Error parsing file: %v", err)}
 	}
 
-	file := cachedFile.ast
-	fset := cachedFile.fset
-
 	// Build scope hierarchy from package to current position
-	hierarchy := buildScopeHierarchyAtLine(file, fset, lineNumber)
-
-	for i, scope := range hierarchy {
-		indent := strings.Repeat("  ", i)
-		fmt.Fprintf(b, "%s%s\n", indent, scope)
+	return &ScopeInfo{
+		Hierarchy: buildScopeHierarchyAtLine(cachedFile.ast, cachedFile.fset, lineNumber),
 	}
 }
 
-// determineScope determines the scope (file or function) for a reference
-func determineScope(filePath string, lineNumber int) (string, error) {
-	// Parse the file to find the containing function using AST cache
-	cachedFile, err := globalFileCache.GetOrParseFile(filePath)
-	if err != nil {
-		return filePath, err // fallback to file scope
-	}
-
-	file := cachedFile.ast
-	fset := cachedFile.fset
-
-	// Find if the line is within a function
-	for _, decl := range file.Decls {
-		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-			funcStart := fset.Position(funcDecl.Pos()).Line
-			funcEnd := fset.Position(funcDecl.End()).Line
-
-			if lineNumber >= funcStart && lineNumber <= funcEnd {
-				// Reference is within this function
-				return fmt.Sprintf(
-					"%s:%d:%s",
-					filePath,
-					funcStart,
-					funcDecl.Name.Name,
-				), nil
-			}
-		}
-	}
-
-	// Reference is at package level
-	return filePath, nil
-}
-
 // findTypeAtLine finds a type declaration at or near the specified line
 func findTypeAtLine(file *ast.File, fset *token.FileSet, targetLine int) *ast.TypeSpec {
 	for _, decl := range file.Decls {
@@ -1324,35 +1988,48 @@ func isPartOfConstruct(block *ast.BlockStmt, parent ast.Node) bool {
 	return isPartOfConstruct
 }
 
-// readSourceLines reads the specified lines from a source file and returns the raw content
-func readSourceLines(filename string, startLine, endLine int) (string, error) {
-	file, err := os.Open(filename)
+// readSourceLines reads the specified lines from a source file and returns the raw content.
+// The source and line offsets of the file cache are reused, so rendering many snippets of
+// a file reads and scans it once.
+func readSourceLines(filename string, startLine, endLine int, overlay Overlay) (string, error) {
+	cached, err := globalFileCache.GetOrParseFile(filename, overlay)
 	if err != nil {
-		return "", err
-	}
-	defer func() {
-		_ = file.Close()
-	}()
-
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	lineNum := 1
-
-	for scanner.Scan() {
-		if lineNum >= startLine && lineNum <= endLine {
-			lines = append(lines, scanner.Text())
+		// Files the parser rejects entirely are read without caching
+		content, readErr := overlay.readFile(filename)
+		if readErr != nil {
+			return "", readErr
 		}
-		if lineNum > endLine {
-			break
-		}
-		lineNum++
+		cached = &cachedFile{src: content, lines: lineOffsets(content)}
 	}
+	return cached.sourceLines(startLine, endLine), nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return "", err
+// lineOffsets returns the byte offset of the start of each line of src
+func lineOffsets(src []byte) []int {
+	offsets := []int{0}
+	for i, c := range src {
+		if c == '\n' && i+1 < len(src) {
+			offsets = append(offsets, i+1)
+		}
 	}
+	return offsets
+}
 
-	return strings.Join(lines, "\n"), nil
+// sourceLines returns the lines from startLine to endLine, 1-based and inclusive, joined
+// by newlines without line terminators
+func (cached *cachedFile) sourceLines(startLine, endLine int) string {
+	startLine = max(startLine, 1)
+	endLine = min(endLine, len(cached.lines))
+	if len(cached.src) == 0 || startLine > endLine {
+		return ""
+	}
+	end := len(cached.src)
+	if endLine < len(cached.lines) {
+		end = cached.lines[endLine]
+	}
+	text := string(cached.src[cached.lines[startLine-1]:end])
+	text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+	return strings.ReplaceAll(text, "\r\n", "\n")
 }
 
 // resolveFilePath resolves a file path relative to the workspace directory
@@ -1417,29 +2094,41 @@ func resolvePackagePath(pkgPath string, workspaceDir string) (string, error) {
 
 // isFileInWorkspace checks if a file path is within the workspace directory
 func isFileInWorkspace(filePath, workspaceDir string) bool {
+	_, ok := workspaceRelPath(filePath, workspaceDir)
+	return ok
+}
+
+// workspaceRelPath returns the path of a file relative to the workspace directory and
+// whether the file is within it. Symlinks are resolved first, so a workspace reached
+// through a symlink, like /tmp being /private/tmp on macOS, contains the files reported
+// by the go command and gopls under the resolved path and vice versa.
+func workspaceRelPath(filePath, workspaceDir string) (string, bool) {
 	if workspaceDir == "" {
-		return false
+		return "", false
 	}
-
-	// Convert both paths to absolute paths for reliable comparison
-	absFilePath, err := filepath.Abs(filePath)
-	if err != nil {
-		return false
+	rel, err := filepath.Rel(resolveSymlinks(workspaceDir), resolveSymlinks(filePath))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
 	}
+	return rel, true
+}
 
-	absWorkspaceDir, err := filepath.Abs(workspaceDir)
+// resolveSymlinks returns the absolute path with all symlinks resolved. Paths that do not
+// exist yet, like files about to be created, are resolved through their closest existing
+// parent directory.
+func resolveSymlinks(path string) string {
+	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return false
+		return filepath.Clean(path)
 	}
-
-	// Check if the file path starts with the workspace directory
-	rel, err := filepath.Rel(absWorkspaceDir, absFilePath)
-	if err != nil {
-		return false
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		return resolved
 	}
-
-	// If the relative path starts with "..", the file is outside the workspace
-	return !strings.HasPrefix(rel, "..")
+	parent := filepath.Dir(absPath)
+	if parent == absPath {
+		return absPath
+	}
+	return filepath.Join(resolveSymlinks(parent), filepath.Base(absPath))
 }
 
 // containsLine checks if a node contains the specified line number
@@ -1462,10 +2151,19 @@ func extractReceiverTypeName(expr ast.Expr) string {
 	return ""
 }
 
-// fileCache provides a thread-safe cache for parsed Go files
+// fileCache provides a thread-safe cache for parsed Go files, evicting the least
+// recently used files when it exceeds its limits
 type fileCache struct {
-	mu    sync.RWMutex
-	files map[string]*cachedFile
+	mu     sync.Mutex
+	files  map[string]*cachedFile
+	limits FileCacheLimits
+	// lru orders the cached files from most to least recently used
+	lru   *list.List
+	bytes int64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
 }
 
 // cachedFile represents a cached parsed Go file
@@ -1474,35 +2172,116 @@ type cachedFile struct {
 	fset     *token.FileSet
 	modTime  time.Time
 	filePath string
+	// overlayHash is the hash of the overlay content the file was parsed from, if any
+	overlayHash *[sha256.Size]byte
+	// size is the size of the source, used as an estimate of the memory held
+	size int64
+	// src is the source the file was parsed from and lines the byte offset of the start
+	// of each of its lines, so snippets are sliced without reading the file again
+	src     []byte
+	lines   []int
+	element *list.Element
+}
+
+// FileCacheLimits bounds the cache of parsed files shared by all tools. Zero means unlimited.
+type FileCacheLimits struct {
+	MaxFiles int
+	// MaxBytes limits the total size of the sources of the cached files. The parsed
+	// syntax trees take a multiple of that in memory.
+	MaxBytes int64
+}
+
+// DefaultFileCacheLimits are the limits of the file cache unless changed with SetFileCacheLimits
+var DefaultFileCacheLimits = FileCacheLimits{
+	MaxFiles: 5000,
+	MaxBytes: 128 << 20,
+}
+
+// FileCacheMetrics describes the usage of the cache of parsed files
+type FileCacheMetrics struct {
+	Files     int
+	Bytes     int64
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
 }
 
 // Global file cache instance
-var globalFileCache = &fileCache{
-	files: make(map[string]*cachedFile),
+var globalFileCache = newFileCache(DefaultFileCacheLimits)
+
+func newFileCache(limits FileCacheLimits) *fileCache {
+	return &fileCache{
+		files:  make(map[string]*cachedFile),
+		limits: limits,
+		lru:    list.New(),
+	}
+}
+
+// SetFileCacheLimits changes the limits of the cache of parsed files shared by all
+// tools and servers in the process, evicting files exceeding the new limits
+func SetFileCacheLimits(limits FileCacheLimits) {
+	globalFileCache.SetLimits(limits)
+}
+
+// GetFileCacheMetrics returns the usage of the cache of parsed files shared by all tools
+func GetFileCacheMetrics() FileCacheMetrics {
+	return globalFileCache.Metrics()
 }
 
-// GetOrParseFile retrieves a cached file or parses it if not cached/outdated
-func (cache *fileCache) GetOrParseFile(filePath string) (*cachedFile, error) {
-	cache.mu.RLock()
-	cached, exists := cache.files[filePath]
-	cache.mu.RUnlock()
+// WithFileCacheLimits sets the limits of the cache of parsed files when the server is
+// created. The cache is shared by all servers in the process.
+func WithFileCacheLimits(limits FileCacheLimits) Option {
+	return func(o *serverOptions) {
+		o.fileCacheLimits = &limits
+	}
+}
+
+// GetOrParseFile retrieves a cached file or parses it if not cached/outdated.
+// Files in the overlay are parsed from their overlay content instead of from disk.
+// Files are cached and positioned by their path with symlinks resolved, so paths through
+// a symlinked workspace share the entry of the file they point to.
+func (cache *fileCache) GetOrParseFile(filePath string, overlay Overlay) (*cachedFile, error) {
+	var overlayHash *[sha256.Size]byte
+	if content, ok := overlay[filePath]; ok {
+		hash := sha256.Sum256(content)
+		overlayHash = &hash
+	}
+	key := resolveSymlinks(filePath)
+
+	cache.mu.Lock()
+	cached, exists := cache.files[key]
+	cache.mu.Unlock()
 
 	// Check if we have a valid cached version
 	if exists {
-		stat, err := os.Stat(filePath)
-		if err == nil && !stat.ModTime().After(cached.modTime) {
+		valid := false
+		if overlayHash != nil {
+			valid = cached.overlayHash != nil && *cached.overlayHash == *overlayHash
+		} else if cached.overlayHash == nil {
+			stat, err := os.Stat(filePath)
+			valid = err == nil && !stat.ModTime().After(cached.modTime)
+		}
+		if valid {
+			cache.mu.Lock()
+			cache.hits++
+			// The file may have been evicted or replaced since the lookup
+			if cache.files[key] == cached {
+				cache.lru.MoveToFront(cached.element)
+			}
+			cache.mu.Unlock()
 			return cached, nil
 		}
 	}
 
 	// Need to parse the file
 	fset := token.NewFileSet()
-	src, err := os.ReadFile(filePath)
+	src, err := overlay.readFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
-	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	// Positions use the resolved path, whichever path the file was first requested by
+	file, err := parser.ParseFile(fset, key, src, parser.ParseComments)
 	if err != nil {
 		// Check if it's a syntax error (scanner.ErrorList) - we can still work with partial AST
 		if _, ok := err.(scanner.ErrorList); !ok {
@@ -1517,47 +2296,110 @@ func (cache *fileCache) GetOrParseFile(filePath string) (*cachedFile, error) {
 		return nil, fmt.Errorf("failed to parse file %s: no AST generated", filePath)
 	}
 
-	stat, err := os.Stat(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to stat file %s: %w", filePath, err)
-	}
-
 	cached = &cachedFile{
-		ast:      file,
-		fset:     fset,
-		modTime:  stat.ModTime(),
-		filePath: filePath,
+		ast:         file,
+		fset:        fset,
+		filePath:    key,
+		overlayHash: overlayHash,
+		size:        int64(len(src)),
+		src:         src,
+		lines:       lineOffsets(src),
+	}
+	if overlayHash == nil {
+		stat, err := os.Stat(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file %s: %w", filePath, err)
+		}
+		cached.modTime = stat.ModTime()
 	}
 
 	// Cache the parsed file
 	cache.mu.Lock()
-	cache.files[filePath] = cached
-	cache.mu.Unlock()
+	defer cache.mu.Unlock()
+	cache.misses++
+	cache.removeLocked(key)
+	cached.element = cache.lru.PushFront(cached)
+	cache.files[key] = cached
+	cache.bytes += cached.size
+	cache.evictLocked()
 
 	return cached, nil
 }
 
+// removeLocked removes a file from the cache, the caller must hold the lock
+func (cache *fileCache) removeLocked(filePath string) {
+	cached, ok := cache.files[filePath]
+	if !ok {
+		return
+	}
+	cache.lru.Remove(cached.element)
+	cache.bytes -= cached.size
+	delete(cache.files, filePath)
+}
+
+// evictLocked removes the least recently used files until the cache is within its
+// limits, the caller must hold the lock. The most recently used file is always kept.
+func (cache *fileCache) evictLocked() {
+	for cache.lru.Len() > 1 {
+		overFiles := cache.limits.MaxFiles > 0 && cache.lru.Len() > cache.limits.MaxFiles
+		overBytes := cache.limits.MaxBytes > 0 && cache.bytes > cache.limits.MaxBytes
+		if !overFiles && !overBytes {
+			return
+		}
+		cache.removeLocked(cache.lru.Back().Value.(*cachedFile).filePath)
+		cache.evictions++
+	}
+}
+
+// SetLimits changes the limits of the cache, evicting files exceeding them
+func (cache *fileCache) SetLimits(limits FileCacheLimits) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.limits = limits
+	cache.evictLocked()
+}
+
+// Metrics returns the usage of the cache
+func (cache *fileCache) Metrics() FileCacheMetrics {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return FileCacheMetrics{
+		Files:     len(cache.files),
+		Bytes:     cache.bytes,
+		Hits:      cache.hits,
+		Misses:    cache.misses,
+		Evictions: cache.evictions,
+	}
+}
+
 // ClearCache removes all cached files (useful for testing or memory management)
 func (cache *fileCache) ClearCache() {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
 	cache.files = make(map[string]*cachedFile)
+	cache.lru.Init()
+	cache.bytes = 0
 }
 
 // RemoveFile removes a specific file from the cache
 func (cache *fileCache) RemoveFile(filePath string) {
+	key := resolveSymlinks(filePath)
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
-	delete(cache.files, filePath)
+	cache.removeLocked(key)
 }
 
 // GetCacheStats returns information about the cache state
 func (cache *fileCache) GetCacheStats() map[string]any {
-	cache.mu.RLock()
-	defer cache.mu.RUnlock()
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
 
 	return map[string]any{
 		"cached_files": len(cache.files),
+		"cached_bytes": cache.bytes,
+		"hits":         cache.hits,
+		"misses":       cache.misses,
+		"evictions":    cache.evictions,
 		"files": func() []string {
 			files := make([]string, 0, len(cache.files))
 			for path := range cache.files {
@@ -1568,40 +2410,38 @@ func (cache *fileCache) GetCacheStats() map[string]any {
 	}
 }
 
-// formatCallHierarchy finds and formats call hierarchy for a symbol using gopls
-func formatCallHierarchy(
-	b *strings.Builder,
+// findCallHierarchy finds the call hierarchy for a symbol using gopls
+func findCallHierarchy(
+	ctx context.Context,
 	filePath string,
 	lineNumber int,
 	symbolName string,
-) {
-	b.WriteString("Call Hierarchy:\n")
+	overlay Overlay,
+) *CallHierarchy {
+	if reason := overlay.goplsUnavailableReason(filePath, "Call hierarchies"); reason != "" {
+		return &CallHierarchy{Error: reason}
+	}
 
 	if filePath == "" || lineNumber <= 0 || symbolName == "" {
-		b.WriteString("Invalid parameters for finding call hierarchy\n")
-		return
+		return &CallHierarchy{Error: "Invalid parameters for finding call hierarchy"}
 	}
 
 	// Create gopls position using utility function
 	position, err := createGoplsPosition(filePath, lineNumber, symbolName)
 	if err != nil {
-		fmt.Fprintf(b, "Failed to find call hierarchy: %s\n", err.Error())
-		return
+		return &CallHierarchy{
+			Error: fmt.Sprintf("Failed to find call hierarchy: %s", err.Error()),
+		}
 	}
 
 	// Execute gopls call_hierarchy command using utility function
-	outputStr, err := executeGoplsCommand("call_hierarchy", position)
+	outputStr, err := executeGoplsCommand(ctx, "call_hierarchy", position)
 	if err != nil {
-		fmt.Fprintf(b, "gopls call_hierarchy failed: %s\n", err.Error())
-		return
-	}
-
-	if outputStr == "" {
-		b.WriteString("No call hierarchy found\n")
-		return
+		return &CallHierarchy{
+			Error: fmt.Sprintf("gopls call_hierarchy failed: %s", err.Error()),
+		}
 	}
 
-	// Output the raw gopls call hierarchy result
-	b.WriteString(outputStr)
-	b.WriteString("\n")
+	// Keep the raw gopls call hierarchy result
+	return &CallHierarchy{Text: outputStr}
 }
@@ -0,0 +1,663 @@
+package go_mcp_tools
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"iter"
+	"strings"
+)
+
+// SymbolKind is the kind of declaration described by a SymbolInfo
+type SymbolKind string
+
+const (
+	SymbolFunction  SymbolKind = "function"
+	SymbolMethod    SymbolKind = "method"
+	SymbolType      SymbolKind = "type"
+	SymbolInterface SymbolKind = "interface"
+	SymbolVariable  SymbolKind = "variable"
+	SymbolConstant  SymbolKind = "constant"
+)
+
+// InspectResult is the structured result of InspectStructured. Exactly one of
+// Package, File and Symbol is set, depending on what the inspected path refers to.
+// String formats the result as returned by Inspect and the inspect tool.
+type InspectResult struct {
+	// SyntaxErrors is set when the inspected file has syntax errors and the result is based on a partial AST
+	SyntaxErrors string `json:"syntax_errors,omitempty"`
+	// ChangedSince is the git ref the declarations of a file or package were limited to
+	// the changes since
+	ChangedSince string       `json:"changed_since,omitempty"`
+	Package      *PackageInfo `json:"package,omitempty"`
+	File         *FileInfo    `json:"file,omitempty"`
+	Symbol       *SymbolInfo  `json:"symbol,omitempty"`
+}
+
+// PackageInfo describes a package and the declarations of its files
+type PackageInfo struct {
+	Directory  string     `json:"directory,omitempty"`
+	ImportPath string     `json:"import_path,omitempty"`
+	Files      []FileInfo `json:"files"`
+}
+
+// FileInfo describes a file and its top level declarations
+type FileInfo struct {
+	Path    string       `json:"path,omitempty"`
+	Doc     string       `json:"doc,omitempty"`
+	Imports []string     `json:"imports,omitempty"`
+	Symbols []SymbolInfo `json:"symbols"`
+}
+
+// SymbolInfo describes a declaration. The optional sections (methods, references,
+// implementers, scope and call hierarchy) are only set when they were requested.
+type SymbolInfo struct {
+	Name string     `json:"name"`
+	Kind SymbolKind `json:"kind"`
+	// Names lists all names of a variable or constant declaration declaring several at once
+	Names []string `json:"names,omitempty"`
+	// Receiver is the receiver type of a method, e.g. *MyStruct
+	Receiver  string `json:"receiver,omitempty"`
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Doc       string `json:"doc,omitempty"`
+	// PromotedFrom is the path of embedded fields a method of a type is promoted through,
+	// e.g. Base.Logger
+	PromotedFrom string `json:"promoted_from,omitempty"`
+	// Origin is the receiver type declaring a promoted method, e.g. *log.Logger
+	Origin string `json:"origin,omitempty"`
+	// LastChange is the last commit changing the declaration, only set when requested
+	LastChange *LastChange `json:"last_change,omitempty"`
+	// Code is the source of the declaration. Functions only include the signature unless
+	// InspectOptions.IncludeBody is set.
+	Code string `json:"code"`
+	// Language is the language of Code and Tokens classify its tokens for syntax
+	// highlighting. Both are only set by InspectResult.Highlight.
+	Language string      `json:"language,omitempty"`
+	Tokens   []CodeToken `json:"tokens,omitempty"`
+	// BodySummary summarizes the body of functions too long to show with DetailAuto
+	BodySummary   *BodySummary     `json:"body_summary,omitempty"`
+	Methods       []SymbolInfo     `json:"methods,omitempty"`
+	References    []ReferenceList  `json:"references,omitempty"`
+	Implementers  *ImplementerList `json:"implementers,omitempty"`
+	Scope         *ScopeInfo       `json:"scope,omitempty"`
+	CallHierarchy *CallHierarchy   `json:"call_hierarchy,omitempty"`
+	// PromotedFields are the fields a struct type gets from its embedded structs, only
+	// set when requested
+	PromotedFields []PromotedField `json:"promoted_fields,omitempty"`
+	// Examples are the ExampleXxx functions of the symbol, only set when requested
+	Examples []Example `json:"examples,omitempty"`
+}
+
+// PromotedField is a field of an embedded struct promoted to the inspected struct
+type PromotedField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Origin is the embedded type declaring the field, e.g. *log.Logger
+	Origin string `json:"origin"`
+	// Path is the path of embedded fields the field is promoted through, e.g. Base.Logger
+	Path string `json:"path"`
+}
+
+// LastChange is the last commit changing the lines of a declaration. Error is set when
+// it could not be determined, e.g. outside of a git repository.
+type LastChange struct {
+	Commit string `json:"commit,omitempty"`
+	Author string `json:"author,omitempty"`
+	// Date is the author date in RFC 3339 format
+	Date    string `json:"date,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BodySummary describes a function body without its source
+type BodySummary struct {
+	Lines    int `json:"lines"`
+	Branches int `json:"branches"`
+	Loops    int `json:"loops"`
+	// Calls lists the called functions in order of their first call, e.g. fmt.Println
+	Calls []string `json:"calls,omitempty"`
+}
+
+// ReferenceList holds the references to a symbol. Error is set when they could not be determined.
+type ReferenceList struct {
+	Symbol     string      `json:"symbol"`
+	References []Reference `json:"references,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Reference is a single use of a symbol
+type Reference struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Function is the function containing the reference, nil for references at package scope
+	Function *SymbolInfo `json:"function,omitempty"`
+}
+
+// ImplementerList holds the implementers of an interface. Error is set when they could not be determined.
+type ImplementerList struct {
+	Implementers []Implementer `json:"implementers,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// Implementer is a type implementing an interface. Error is set when the type could not be read.
+type Implementer struct {
+	File string      `json:"file"`
+	Line int         `json:"line"`
+	Type *SymbolInfo `json:"type,omitempty"`
+	// Satisfies are the forms of the type satisfying the interface, T and *T, or only *T
+	// when methods have pointer receivers. It is empty for interfaces, generic types and
+	// types that could not be type checked.
+	Satisfies []string `json:"satisfies,omitempty"`
+	// PointerMethods are the methods of the interface with pointer receivers, which values
+	// of the type lack
+	PointerMethods []string `json:"pointer_methods,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// ScopeInfo holds the enclosing scopes of a declaration from the package inwards
+type ScopeInfo struct {
+	Hierarchy []string `json:"hierarchy,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// CallHierarchy holds the incoming and outgoing calls of a function as reported by gopls
+type CallHierarchy struct {
+	Text  string `json:"text,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// TokenClass is the syntax highlighting class of a token of a snippet
+type TokenClass string
+
+const (
+	TokenKeyword    TokenClass = "keyword"
+	TokenIdentifier TokenClass = "ident"
+	// TokenBuiltin is a predeclared identifier such as string, len or nil
+	TokenBuiltin  TokenClass = "builtin"
+	TokenString   TokenClass = "string"
+	TokenNumber   TokenClass = "number"
+	TokenComment  TokenClass = "comment"
+	TokenOperator TokenClass = "operator"
+)
+
+// CodeToken is a token of a snippet. Offset and Length are in bytes of the snippet.
+type CodeToken struct {
+	Offset int        `json:"offset"`
+	Length int        `json:"length"`
+	Class  TokenClass `json:"class"`
+}
+
+// Highlight sets the language and tokens of the code of every symbol of the result,
+// so rich clients can highlight snippets without lexing them again
+func (result *InspectResult) Highlight() {
+	var highlight func(symbol *SymbolInfo)
+	highlight = func(symbol *SymbolInfo) {
+		if symbol == nil {
+			return
+		}
+		if symbol.Code != "" {
+			symbol.Language = "go"
+			symbol.Tokens = HighlightCode(symbol.Code)
+		}
+		for i := range symbol.Methods {
+			highlight(&symbol.Methods[i])
+		}
+		for _, list := range symbol.References {
+			for _, reference := range list.References {
+				highlight(reference.Function)
+			}
+		}
+		if symbol.Implementers != nil {
+			for _, implementer := range symbol.Implementers.Implementers {
+				highlight(implementer.Type)
+			}
+		}
+	}
+	highlightFile := func(file *FileInfo) {
+		for i := range file.Symbols {
+			highlight(&file.Symbols[i])
+		}
+	}
+
+	highlight(result.Symbol)
+	if result.File != nil {
+		highlightFile(result.File)
+	}
+	if result.Package != nil {
+		for i := range result.Package.Files {
+			highlightFile(&result.Package.Files[i])
+		}
+	}
+}
+
+// HighlightCode classifies the tokens of a Go snippet. Snippets do not need to be
+// complete files, and invalid code is classified as far as it can be scanned.
+func HighlightCode(code string) []CodeToken {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(code))
+	var s scanner.Scanner
+	// Errors are ignored, the scanner continues after them
+	s.Init(file, []byte(code), nil, scanner.ScanComments)
+
+	var tokens []CodeToken
+	for {
+		pos, tok, literal := s.Scan()
+		if tok == token.EOF {
+			return tokens
+		}
+		offset := file.Offset(pos)
+		length := len(tok.String())
+		var class TokenClass
+		switch {
+		case tok == token.SEMICOLON && literal == "\n":
+			// Semicolons inserted at line ends are not part of the code
+			continue
+		case tok == token.COMMENT:
+			class, length = TokenComment, len(literal)
+		case tok == token.IDENT:
+			class, length = TokenIdentifier, len(literal)
+			if types.Universe.Lookup(literal) != nil {
+				class = TokenBuiltin
+			}
+		case tok == token.STRING || tok == token.CHAR:
+			class, length = TokenString, len(literal)
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			class, length = TokenNumber, len(literal)
+		case tok.IsKeyword():
+			class = TokenKeyword
+		case tok.IsOperator():
+			class = TokenOperator
+		default:
+			continue
+		}
+		tokens = append(tokens, CodeToken{Offset: offset, Length: length, Class: class})
+	}
+}
+
+// String formats the result as human and model readable text
+func (result *InspectResult) String() string {
+	var b strings.Builder
+	result.write(&b, func() {})
+	return b.String()
+}
+
+// Chunks returns the text of String in chunks of at least size bytes, split before files
+// and symbols, so the text of huge packages can be passed on while it is written instead
+// of as one string. Joined, the chunks are the text of String.
+func (result *InspectResult) Chunks(size int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var b strings.Builder
+		stopped := false
+		// The text of the remaining symbols is still written, but no longer passed on
+		boundary := func() {
+			if b.Len() == 0 || b.Len() < size {
+				return
+			}
+			if !stopped {
+				stopped = !yield(b.String())
+			}
+			b.Reset()
+		}
+		result.write(&b, boundary)
+		if b.Len() > 0 && !stopped {
+			yield(b.String())
+		}
+	}
+}
+
+// write writes the text of the result, calling boundary before every file and symbol
+func (result *InspectResult) write(b *strings.Builder, boundary func()) {
+	result.writeHeader(b, result.Package != nil && len(result.Package.Files) == 0 || result.File != nil && len(result.File.Symbols) == 0)
+	switch {
+	case result.Package != nil:
+		writePackage(b, result.Package, boundary)
+	case result.File != nil:
+		writeFile(b, result.File, boundary)
+	case result.Symbol != nil:
+		writeSymbol(b, result.Symbol)
+	}
+}
+
+// writeHeader writes the warnings and notes before the files or symbol of the result,
+// where empty tells whether no declarations are left to show
+func (result *InspectResult) writeHeader(b *strings.Builder, empty bool) {
+	if result.SyntaxErrors != "" {
+		fmt.Fprintf(
+			b,
+			"WARNING: Syntax errors found, analysis may be incomplete:\n%s\n\n",
+			result.SyntaxErrors,
+		)
+	}
+	if result.ChangedSince != "" {
+		if empty {
+			fmt.Fprintf(b, "No declarations changed since %s\n\n", result.ChangedSince)
+		} else {
+			fmt.Fprintf(b, "Showing only declarations changed since %s\n\n", result.ChangedSince)
+		}
+	}
+}
+
+func writeSymbol(b *strings.Builder, symbol *SymbolInfo) {
+	switch symbol.Kind {
+	case SymbolFunction, SymbolMethod:
+		writeFunction(b, symbol)
+	case SymbolType, SymbolInterface:
+		writeType(b, symbol)
+	default:
+		writeVariable(b, symbol)
+	}
+}
+
+// writeDeclaration writes the lines, docstring and code shared by all symbols
+func writeDeclaration(b *strings.Builder, symbol *SymbolInfo) {
+	if symbol.EndLine > symbol.StartLine {
+		fmt.Fprintf(b, "Lines: %d-%d\n", symbol.StartLine, symbol.EndLine)
+	} else {
+		fmt.Fprintf(b, "Lines: %d\n", symbol.StartLine)
+	}
+
+	if change := symbol.LastChange; change != nil {
+		if change.Error != "" {
+			fmt.Fprintf(b, "Last Change: %s\n", change.Error)
+		} else {
+			date, _, _ := strings.Cut(change.Date, "T")
+			fmt.Fprintf(b, "Last Change: %.12s by %s on %s: %s\n", change.Commit, change.Author, date, change.Summary)
+		}
+	}
+
+	if symbol.Doc != "" {
+		b.WriteString("Docstring: ")
+		b.WriteString(symbol.Doc)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("Code:\n")
+	b.WriteString(symbol.Code)
+
+	if summary := symbol.BodySummary; summary != nil {
+		fmt.Fprintf(
+			b,
+			"\nBody Summary: %d lines, %d branches, %d loops",
+			summary.Lines, summary.Branches, summary.Loops,
+		)
+		if len(summary.Calls) > 0 {
+			b.WriteString("\nCalls: ")
+			b.WriteString(strings.Join(summary.Calls, ", "))
+		}
+	}
+
+	for i := range symbol.Examples {
+		b.WriteString("\n\n")
+		writeExample(b, &symbol.Examples[i])
+	}
+}
+
+func writeFunction(b *strings.Builder, symbol *SymbolInfo) {
+	writeDeclaration(b, symbol)
+
+	for i := range symbol.References {
+		b.WriteString("\n\n")
+		writeReferences(b, &symbol.References[i])
+	}
+
+	if symbol.CallHierarchy != nil {
+		b.WriteString("\n\n")
+		writeCallHierarchy(b, symbol.CallHierarchy)
+	}
+}
+
+func writeType(b *strings.Builder, symbol *SymbolInfo) {
+	writeDeclaration(b, symbol)
+
+	if symbol.Implementers != nil {
+		b.WriteString("\n\n")
+		writeImplementers(b, symbol.Implementers)
+	}
+
+	if len(symbol.PromotedFields) > 0 {
+		b.WriteString("\n\nPromoted Fields:\n")
+		for _, field := range symbol.PromotedFields {
+			fmt.Fprintf(b, "  %s %s (from %s via %s)\n", field.Name, field.Type, field.Origin, field.Path)
+		}
+	}
+
+	for i := range symbol.Methods {
+		method := &symbol.Methods[i]
+		b.WriteString("\n\n")
+		switch {
+		case method.Origin != "":
+			fmt.Fprintf(b, "Promoted From: %s (%s)\n", method.PromotedFrom, method.Origin)
+		case method.PromotedFrom != "":
+			fmt.Fprintf(b, "Promoted From: %s\n", method.PromotedFrom)
+		}
+		if method.File != symbol.File {
+			fmt.Fprintf(b, "File: %s\n", method.File)
+		}
+		writeFunction(b, method)
+	}
+
+	for i := range symbol.References {
+		b.WriteString("\n\n")
+		writeReferences(b, &symbol.References[i])
+	}
+}
+
+func writeVariable(b *strings.Builder, symbol *SymbolInfo) {
+	writeDeclaration(b, symbol)
+
+	if symbol.Scope != nil {
+		b.WriteString("\n")
+		writeScope(b, symbol.Scope)
+	}
+
+	// Declarations with multiple names have one reference list per name
+	for i := range symbol.References {
+		b.WriteString("\n\n")
+		writeReferences(b, &symbol.References[i])
+	}
+}
+
+func writeFile(b *strings.Builder, file *FileInfo, boundary func()) {
+	lineWritten := false
+	addSeparator := func() {
+		if lineWritten {
+			b.WriteString("\n\n")
+		}
+		lineWritten = true
+	}
+
+	// Include absolute file path at the top
+	if file.Path != "" {
+		addSeparator()
+		fmt.Fprintf(b, "File: %s", file.Path)
+	}
+
+	if file.Doc != "" {
+		addSeparator()
+		b.WriteString("File Docstring:\n")
+		b.WriteString(file.Doc)
+	}
+
+	// Write all imports under a single header if any were found
+	if len(file.Imports) > 0 {
+		addSeparator()
+		b.WriteString("Imports:\n")
+		for _, imp := range file.Imports {
+			b.WriteString(imp)
+			b.WriteString("\n")
+		}
+	}
+
+	for i := range file.Symbols {
+		boundary()
+		addSeparator()
+		writeSymbol(b, &file.Symbols[i])
+	}
+}
+
+func writePackage(b *strings.Builder, pkg *PackageInfo, boundary func()) {
+	writePackageHeader(b, pkg)
+	for i := range pkg.Files {
+		writePackageFile(b, &pkg.Files[i], i, boundary)
+	}
+}
+
+func writePackageHeader(b *strings.Builder, pkg *PackageInfo) {
+	if pkg.Directory != "" {
+		fmt.Fprintf(b, "Directory: %s\n", pkg.Directory)
+	}
+	if pkg.ImportPath != "" {
+		fmt.Fprintf(b, "Import Path: %s\n", pkg.ImportPath)
+	}
+
+	// Triple line break before file contents
+	b.WriteString("\n\n")
+}
+
+// writePackageFile writes the file with the given index in its package
+func writePackageFile(b *strings.Builder, file *FileInfo, index int, boundary func()) {
+	boundary()
+	if index > 0 {
+		b.WriteString("\n---\n")
+	}
+	writeFile(b, file, boundary)
+}
+
+// writeIndented writes the text with each non-empty line indented, as nested in a section
+func writeIndented(b *strings.Builder, text string, addSeparator func()) {
+	for line := range strings.SplitSeq(text, "\n") {
+		if line == "" {
+			continue
+		}
+		addSeparator()
+		fmt.Fprintf(b, "  %s\n", line)
+	}
+}
+
+func writeReferences(b *strings.Builder, references *ReferenceList) {
+	b.WriteString("References:\n")
+
+	if references.Error != "" {
+		b.WriteString(references.Error)
+		b.WriteString("\n")
+		return
+	}
+	if len(references.References) == 0 {
+		b.WriteString("No references found\n")
+		return
+	}
+
+	lineWritten := false
+	addSeparator := func() {
+		if lineWritten {
+			b.WriteString("\n\n")
+		}
+		lineWritten = true
+	}
+
+	// Each function containing references is shown once, followed by the files
+	// with references at package scope
+	writtenFunctions := make(map[string]bool)
+	var packageFiles []string
+	writtenFiles := make(map[string]bool)
+	for _, reference := range references.References {
+		if reference.Function == nil {
+			if !writtenFiles[reference.File] {
+				writtenFiles[reference.File] = true
+				packageFiles = append(packageFiles, reference.File)
+			}
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%d", reference.Function.File, reference.Function.StartLine)
+		if writtenFunctions[key] {
+			continue
+		}
+		writtenFunctions[key] = true
+
+		var function strings.Builder
+		writeFunction(&function, reference.Function)
+		writeIndented(b, function.String(), addSeparator)
+	}
+
+	for _, file := range packageFiles {
+		addSeparator()
+		fmt.Fprintf(b, "  Package scope: %s\n", file)
+	}
+}
+
+func writeImplementers(b *strings.Builder, implementers *ImplementerList) {
+	b.WriteString("Implementers:\n")
+
+	if implementers.Error != "" {
+		b.WriteString(implementers.Error)
+		b.WriteString("\n")
+		return
+	}
+	if len(implementers.Implementers) == 0 {
+		b.WriteString("No implementers found\n")
+		return
+	}
+
+	lineWritten := false
+	addSeparator := func() {
+		if lineWritten {
+			b.WriteString("\n\n")
+		}
+		lineWritten = true
+	}
+
+	for _, implementer := range implementers.Implementers {
+		if implementer.Type == nil {
+			addSeparator()
+			fmt.Fprintf(b, "  %s\n", implementer.Error)
+			continue
+		}
+
+		var typeText strings.Builder
+		switch {
+		case len(implementer.PointerMethods) > 0:
+			fmt.Fprintf(
+				&typeText,
+				"Satisfied by %s only, values of %s lack the pointer receiver methods %s\n",
+				implementer.Satisfies[0], implementer.Type.Name, strings.Join(implementer.PointerMethods, ", "),
+			)
+		case len(implementer.Satisfies) > 0:
+			fmt.Fprintf(&typeText, "Satisfied by %s\n", strings.Join(implementer.Satisfies, " and "))
+		}
+		writeType(&typeText, implementer.Type)
+		writeIndented(b, typeText.String(), addSeparator)
+	}
+}
+
+func writeScope(b *strings.Builder, scope *ScopeInfo) {
+	b.WriteString("Scope:\n")
+
+	if scope.Error != "" {
+		b.WriteString(scope.Error)
+		b.WriteString("\n")
+		return
+	}
+
+	for i, name := range scope.Hierarchy {
+		indent := strings.Repeat("  ", i)
+		fmt.Fprintf(b, "%s%s\n", indent, name)
+	}
+}
+
+func writeCallHierarchy(b *strings.Builder, callHierarchy *CallHierarchy) {
+	b.WriteString("Call Hierarchy:\n")
+
+	switch {
+	case callHierarchy.Error != "":
+		b.WriteString(callHierarchy.Error)
+	case callHierarchy.Text == "":
+		b.WriteString("No call hierarchy found")
+	default:
+		b.WriteString(callHierarchy.Text)
+	}
+	b.WriteString("\n")
+}
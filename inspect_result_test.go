@@ -0,0 +1,951 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// notifyingSession is a session keeping the notifications sent to it
+type notifyingSession struct {
+	testSession
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (session *notifyingSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return session.notifications
+}
+
+func TestInspectStructured(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a test workspace with a single file
+	createTestWorkspace := func(t testing.TB) string {
+		tempDir := t.TempDir()
+
+		goModContent := "module testmodule\n\ngo 1.21\n"
+		err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte(goModContent), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mainLines := []string{
+			"package testpkg",               // 1
+			"",                              // 2
+			"import \"fmt\"",                // 3
+			"",                              // 4
+			"// Greeter greets people",      // 5
+			"type Greeter interface {",      // 6
+			"    Greet(name string) string", // 7
+			"}",                             // 8
+			"",                              // 9
+			"// English greets in English",  // 10
+			"type English struct {",         // 11
+			"    Prefix string",             // 12
+			"}",                             // 13
+			"",                              // 14
+			"// Greet implements Greeter",   // 15
+			"func (e *English) Greet(name string) string {", // 16
+			"    return fmt.Sprint(e.Prefix, name)",         // 17
+			"}",                                             // 18
+			"",                                              // 19
+			"// Limits for names",                           // 20
+			"const MinLen, MaxLen = 1, 64",                  // 21
+		}
+		err = os.WriteFile(
+			filepath.Join(tempDir, "main.go"),
+			[]byte(strings.Join(mainLines, "\n")),
+			0644,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tempDir
+	}
+
+	// Helper function to build options selecting a symbol with all default sections
+	inspectOptions := func(workspace string, lineNumber int, symbolName string) InspectOptions {
+		options := DefaultInspectOptions(workspace)
+		options.LineNumber = lineNumber
+		options.SymbolName = symbolName
+		return options
+	}
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		result, err := InspectStructured(context.Background(), mainFile, inspectOptions(workspace, 0, ""))
+		if err != nil {
+			t.Fatalf("Failed to inspect file: %v", err)
+		}
+		if result.File == nil || result.Symbol != nil || result.Package != nil {
+			t.Fatalf("Expected only a file result, got %+v", result)
+		}
+		if result.File.Path != mainFile {
+			t.Errorf("Expected path %s, got %s", mainFile, result.File.Path)
+		}
+		if len(result.File.Imports) != 1 || result.File.Imports[0] != `import "fmt"` {
+			t.Errorf("Expected fmt import, got %v", result.File.Imports)
+		}
+
+		expected := []struct {
+			name      string
+			kind      SymbolKind
+			startLine int
+			endLine   int
+		}{
+			{"Greeter", SymbolInterface, 6, 8},
+			{"English", SymbolType, 11, 13},
+			{"Greet", SymbolMethod, 16, 18},
+			{"MinLen", SymbolConstant, 21, 21},
+		}
+		if len(result.File.Symbols) != len(expected) {
+			t.Fatalf("Expected %d symbols, got %+v", len(expected), result.File.Symbols)
+		}
+		for i, want := range expected {
+			got := result.File.Symbols[i]
+			if got.Name != want.name || got.Kind != want.kind ||
+				got.StartLine != want.startLine || got.EndLine != want.endLine {
+				t.Errorf("Expected symbol %+v, got %+v", want, got)
+			}
+		}
+
+		method := result.File.Symbols[2]
+		if method.Receiver != "*English" || method.Code != "func (e *English) Greet(name string) string" {
+			t.Errorf("Expected method receiver and signature, got %+v", method)
+		}
+		constant := result.File.Symbols[3]
+		if strings.Join(constant.Names, ",") != "MinLen,MaxLen" || constant.Doc != "Limits for names" {
+			t.Errorf("Expected all constant names and docstring, got %+v", constant)
+		}
+	})
+
+	t.Run("symbol with context", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		result, err := InspectStructured(context.Background(), mainFile, inspectOptions(workspace, 0, "English"))
+		if err != nil {
+			t.Fatalf("Failed to inspect symbol: %v", err)
+		}
+		symbol := result.Symbol
+		if symbol == nil || symbol.Name != "English" || symbol.Doc != "English greets in English" {
+			t.Fatalf("Expected English type, got %+v", symbol)
+		}
+		if len(symbol.Methods) != 1 || symbol.Methods[0].Name != "Greet" {
+			t.Errorf("Expected Greet method, got %+v", symbol.Methods)
+		}
+		if len(symbol.References) != 1 || symbol.References[0].Symbol != "English" {
+			t.Errorf("Expected a reference list for English, got %+v", symbol.References)
+		}
+
+		result, err = InspectStructured(context.Background(), mainFile, inspectOptions(workspace, 21, "MaxLen"))
+		if err != nil {
+			t.Fatalf("Failed to inspect constant: %v", err)
+		}
+		if result.Symbol.Scope == nil || len(result.Symbol.Scope.Hierarchy) != 1 ||
+			result.Symbol.Scope.Hierarchy[0] != "package testpkg" {
+			t.Errorf("Expected package scope, got %+v", result.Symbol.Scope)
+		}
+		if len(result.Symbol.References) != 2 {
+			t.Errorf("Expected one reference list per constant name, got %+v", result.Symbol.References)
+		}
+	})
+
+	t.Run("text output is formatted from the result", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		for _, symbolName := range []string{"", "Greeter", "Greet", "MinLen"} {
+			result, err := InspectStructured(context.Background(), mainFile, inspectOptions(workspace, 0, symbolName))
+			if err != nil {
+				t.Fatalf("Failed to inspect %q: %v", symbolName, err)
+			}
+			text, err := Inspect(mainFile, 0, symbolName, true, workspace)
+			if err != nil {
+				t.Fatalf("Failed to inspect %q: %v", symbolName, err)
+			}
+			if result.String() != text {
+				t.Errorf("Expected String to match Inspect for %q:\n%s\n---\n%s", symbolName, result, text)
+			}
+		}
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		brokenFile := filepath.Join(workspace, "broken.go")
+		lines := []string{
+			"package testpkg", // 1
+			"",                // 2
+			"func Good() {}",  // 3
+			"",                // 4
+			"func Bad( {",     // 5
+		}
+		if err := os.WriteFile(brokenFile, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := InspectStructured(context.Background(), brokenFile, inspectOptions(workspace, 0, "Good"))
+		if err != nil {
+			t.Fatalf("Expected partial result despite syntax errors: %v", err)
+		}
+		if result.SyntaxErrors == "" || result.Symbol == nil || result.Symbol.Name != "Good" {
+			t.Errorf("Expected syntax errors and the Good function, got %+v", result)
+		}
+		if !strings.HasPrefix(result.String(), "WARNING: Syntax errors found") {
+			t.Errorf("Expected warning in text output, got:\n%s", result)
+		}
+	})
+
+	t.Run("body", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		options := inspectOptions(workspace, 0, "Greet")
+		options.IncludeBody = true
+		result, err := InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect method: %v", err)
+		}
+		expected := strings.Join([]string{
+			"func (e *English) Greet(name string) string {",
+			"    return fmt.Sprint(e.Prefix, name)",
+			"}",
+		}, "\n")
+		if result.Symbol.Code != expected {
+			t.Errorf("Expected full method source:\n%s\ngot:\n%s", expected, result.Symbol.Code)
+		}
+
+		options = inspectOptions(workspace, 0, "English")
+		options.IncludeBody = true
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		if len(result.Symbol.Methods) != 1 || result.Symbol.Methods[0].Code != expected {
+			t.Errorf("Expected full source of methods, got %+v", result.Symbol.Methods)
+		}
+	})
+
+	t.Run("auto detail", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		longLines := []string{
+			"package testpkg",
+			"",
+			"import \"fmt\"",
+			"",
+			"func Long(values []int) {",
+			"    for _, value := range values {",
+			"        if value > 0 {",
+			"            fmt.Println(value)",
+			"        }",
+			"    }",
+		}
+		for i := 0; i < autoDetailMaxLines; i++ {
+			longLines = append(longLines, "    fmt.Println(len(values))")
+		}
+		longLines = append(longLines, "}")
+		longFile := filepath.Join(workspace, "long.go")
+		if err := os.WriteFile(longFile, []byte(strings.Join(longLines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		options := inspectOptions(workspace, 0, "Long")
+		options.Detail = DetailAuto
+		result, err := InspectStructured(context.Background(), longFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect function: %v", err)
+		}
+		expected := BodySummary{Lines: autoDetailMaxLines + 7, Branches: 1, Loops: 1, Calls: []string{"fmt.Println", "len"}}
+		summary := result.Symbol.BodySummary
+		if result.Symbol.Code != "func Long(values []int)" || summary == nil ||
+			summary.Lines != expected.Lines || summary.Branches != expected.Branches || summary.Loops != expected.Loops ||
+			strings.Join(summary.Calls, ",") != strings.Join(expected.Calls, ",") {
+			t.Errorf("Expected the signature and summary %+v, got %+v", expected, result.Symbol)
+		}
+		if text := result.String(); !strings.Contains(text, "Body Summary: 47 lines, 1 branches, 1 loops\nCalls: fmt.Println, len") {
+			t.Errorf("Expected the body summary in text output, got:\n%s", text)
+		}
+
+		options = inspectOptions(workspace, 0, "Greet")
+		options.Detail = DetailAuto
+		result, err = InspectStructured(context.Background(), filepath.Join(workspace, "main.go"), options)
+		if err != nil {
+			t.Fatalf("Failed to inspect method: %v", err)
+		}
+		if !strings.HasSuffix(result.Symbol.Code, "}") || result.Symbol.BodySummary != nil {
+			t.Errorf("Expected the full body of a short method, got %+v", result.Symbol)
+		}
+	})
+
+	t.Run("sections can be disabled", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		options := inspectOptions(workspace, 0, "English")
+		options.IncludeReferences = false
+		options.IncludeMethods = false
+		result, err := InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		if len(result.Symbol.References) != 0 || len(result.Symbol.Methods) != 0 {
+			t.Errorf("Expected no references or methods, got %+v", result.Symbol)
+		}
+
+		options = inspectOptions(workspace, 21, "MaxLen")
+		options.IncludeReferences = false
+		options.IncludeScope = false
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect constant: %v", err)
+		}
+		if result.Symbol.Scope != nil || len(result.Symbol.References) != 0 {
+			t.Errorf("Expected no scope or references, got %+v", result.Symbol)
+		}
+		if text := result.String(); strings.Contains(text, "Scope:") || strings.Contains(text, "References:") {
+			t.Errorf("Expected disabled sections to be omitted from the text, got:\n%s", text)
+		}
+
+		options = inspectOptions(workspace, 0, "")
+		options.IncludeImports = false
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect file: %v", err)
+		}
+		if len(result.File.Imports) != 0 || len(result.File.Symbols) != 4 {
+			t.Errorf("Expected symbols without imports, got %+v", result.File)
+		}
+	})
+
+	t.Run("changed since", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "initial"}} {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+			cmd.Dir = workspace
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, output)
+			}
+		}
+
+		options := inspectOptions(workspace, 0, "")
+		options.ChangedSince = "HEAD"
+		result, err := InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect file: %v", err)
+		}
+		if len(result.File.Symbols) != 0 || !strings.HasPrefix(result.String(), "No declarations changed since HEAD") {
+			t.Errorf("Expected no changed declarations, got:\n%s", result)
+		}
+
+		content, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content = []byte(strings.Replace(string(content), "e.Prefix, name", "e.Prefix, \" \", name", 1))
+		if err := os.WriteFile(mainFile, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect file: %v", err)
+		}
+		if len(result.File.Symbols) != 1 || result.File.Symbols[0].Name != "Greet" {
+			t.Errorf("Expected only the changed method, got %+v", result.File.Symbols)
+		}
+		if !strings.HasPrefix(result.String(), "Showing only declarations changed since HEAD") {
+			t.Errorf("Expected the ref in the text, got:\n%s", result)
+		}
+
+		options.ChangedSince = "unknown-ref"
+		if _, err := InspectStructured(context.Background(), mainFile, options); err == nil {
+			t.Error("Expected an error for an unknown ref")
+		}
+	})
+
+	t.Run("blame", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		git := func(args ...string) {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+			cmd.Dir = workspace
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, output)
+			}
+		}
+
+		options := inspectOptions(workspace, 0, "")
+		options.IncludeBlame = true
+		result, err := InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect file: %v", err)
+		}
+		if change := result.File.Symbols[0].LastChange; change == nil || change.Error == "" {
+			t.Errorf("Expected an error outside of a git repository, got %+v", change)
+		}
+
+		git("init", "-q")
+		git("add", "-A")
+		git("commit", "-q", "-m", "initial")
+		content, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content = []byte(strings.Replace(string(content), "e.Prefix, name", "e.Prefix, \" \", name", 1))
+		if err := os.WriteFile(mainFile, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("commit", "-q", "-a", "-m", "Separate prefix and name")
+
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect file: %v", err)
+		}
+		summaries := make(map[string]string)
+		for _, symbol := range result.File.Symbols {
+			if symbol.LastChange == nil || symbol.LastChange.Author != "Test" || len(symbol.LastChange.Commit) != 40 {
+				t.Fatalf("Expected the last change of %s, got %+v", symbol.Name, symbol.LastChange)
+			}
+			summaries[symbol.Name] = symbol.LastChange.Summary
+		}
+		if summaries["Greet"] != "Separate prefix and name" || summaries["Greeter"] != "initial" {
+			t.Errorf("Expected the last commit changing each symbol, got %v", summaries)
+		}
+		if text := result.String(); !strings.Contains(text, "by Test on ") || !strings.Contains(text, ": Separate prefix and name\n") {
+			t.Errorf("Expected the last change in the text, got:\n%s", text)
+		}
+
+		options = inspectOptions(workspace, 0, "English")
+		options.IncludeReferences = false
+		options.IncludeImplementers = false
+		options.IncludeBlame = true
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		if result.Symbol.LastChange == nil || len(result.Symbol.Methods) != 1 || result.Symbol.Methods[0].LastChange == nil ||
+			result.Symbol.Methods[0].LastChange.Summary != "Separate prefix and name" {
+			t.Errorf("Expected the last change of the type and its methods, got %+v", result.Symbol)
+		}
+	})
+
+	t.Run("methods across files", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		extraLines := []string{
+			"package testpkg", // 1
+			"",
+			"import \"strings\"",
+			"",
+			"// Shout greets loudly",
+			"func (e *English) Shout(name string) string { return strings.ToUpper(e.Greet(name)) }", // 6
+			"",
+			"// Formal greets formally",
+			"type Formal struct {", // 9
+			"\t*English",
+			"\tstrings.Builder",
+			"}",
+			"",
+			"func (f Formal) Title() string { return \"Sir\" }", // 14
+			"",
+		}
+		extraFile := filepath.Join(workspace, "extra.go")
+		if err := os.WriteFile(extraFile, []byte(strings.Join(extraLines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mainFile := filepath.Join(workspace, "main.go")
+
+		options := inspectOptions(workspace, 0, "English")
+		options.IncludeReferences = false
+		result, err := InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		var methods []string
+		for _, method := range result.Symbol.Methods {
+			methods = append(methods, fmt.Sprintf("%s %s:%d", method.Name, filepath.Base(method.File), method.StartLine))
+		}
+		if fmt.Sprint(methods) != "[Shout extra.go:6 Greet main.go:16]" {
+			t.Errorf("Expected the methods of every file, got %v", methods)
+		}
+		if text := result.String(); !strings.Contains(text, "File: "+extraFile+"\nLines: 6\n") {
+			t.Errorf("Expected the file of the method declared in another file, got:\n%s", text)
+		}
+
+		options = inspectOptions(workspace, 0, "Formal")
+		options.IncludeReferences = false
+		result, err = InspectStructured(context.Background(), extraFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		methods = nil
+		for _, method := range result.Symbol.Methods {
+			methods = append(methods, method.Name+" "+method.PromotedFrom)
+		}
+		if len(methods) < 4 || methods[0] != "Title " || !slices.Contains(methods, "Greet English") ||
+			!slices.Contains(methods, "Shout English") || !slices.Contains(methods, "WriteString Builder") {
+			t.Errorf("Expected the declared method then the promoted methods, got %v", methods)
+		}
+		if text := result.String(); !strings.Contains(text, "Promoted From: English (*English)\nFile: "+mainFile+"\n") {
+			t.Errorf("Expected the promotion of the methods in the text, got:\n%s", text)
+		}
+
+		// Inspected in its package, the methods are looked up in the loaded package
+		packageResult, err := InspectStructured(context.Background(), workspace, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type in its package: %v", err)
+		}
+		if packageResult.String() != result.String() {
+			t.Errorf("Expected the same methods in the package, got:\n%s", packageResult.String())
+		}
+	})
+
+	t.Run("embedded fields", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		serviceLines := []string{
+			"package testpkg", // 1
+			"",
+			"type Meta struct{ Version int }",
+			"",
+			"type Base struct {", // 5
+			"\tMeta",
+			"\tID   int",
+			"\tname string",
+			"}",
+			"",
+			"type Logger struct {", // 11
+			"\tID    string",
+			"\tLevel string",
+			"}",
+			"",
+			"func (l *Logger) Log(message string) {}", // 16
+			"",
+			"type Service struct {", // 18
+			"\tBase",
+			"\t*Logger",
+			"\tName string",
+			"}",
+			"",
+			"func (s Service) Run() {}",
+			"",
+		}
+		serviceFile := filepath.Join(workspace, "service.go")
+		if err := os.WriteFile(serviceFile, []byte(strings.Join(serviceLines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		options := inspectOptions(workspace, 0, "Service")
+		options.IncludeReferences = false
+		options.IncludeMethods = false
+		options.ExpandEmbedded = true
+		result, err := InspectStructured(context.Background(), serviceFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		var fields []string
+		for _, field := range result.Symbol.PromotedFields {
+			fields = append(fields, fmt.Sprintf("%s %s %s %s", field.Name, field.Type, field.Origin, field.Path))
+		}
+		// ID is ambiguous between Base and Logger, so it is not promoted
+		expected := "[Meta Meta Base Base name string Base Base Level string *Logger Logger Version int Meta Base.Meta]"
+		if fmt.Sprint(fields) != expected {
+			t.Errorf("Expected the promoted fields %s, got %v", expected, fields)
+		}
+		if len(result.Symbol.Methods) != 1 || result.Symbol.Methods[0].Name != "Log" ||
+			result.Symbol.Methods[0].Origin != "*Logger" {
+			t.Errorf("Expected only the promoted method Log of *Logger, got %+v", result.Symbol.Methods)
+		}
+		text := result.String()
+		for _, expected := range []string{
+			"Promoted Fields:\n  Meta Meta (from Base via Base)\n",
+			"  Version int (from Meta via Base.Meta)\n",
+			"Promoted From: Logger (*Logger)\n",
+		} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Expected the text to contain %q, got:\n%s", expected, text)
+			}
+		}
+
+		options.ExpandEmbedded = false
+		result, err = InspectStructured(context.Background(), serviceFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		if len(result.Symbol.PromotedFields) != 0 || len(result.Symbol.Methods) != 0 {
+			t.Errorf("Expected no promoted members unless requested, got %+v", result.Symbol)
+		}
+	})
+
+	t.Run("implementer receivers", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		frenchLines := []string{
+			"package testpkg", // 1
+			"",
+			"type French struct{}", // 3
+			"",
+			"func (French) Greet(name string) string { return \"Bonjour \" + name }",
+			"",
+			"type Polite interface{ Greeter }", // 7
+			"",
+		}
+		frenchFile := filepath.Join(workspace, "french.go")
+		if err := os.WriteFile(frenchFile, []byte(strings.Join(frenchLines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mainFile := filepath.Join(workspace, "main.go")
+
+		implementers := &ImplementerList{Implementers: []Implementer{
+			{File: mainFile, Line: 11, Type: &SymbolInfo{Name: "English", Kind: SymbolType}},
+			{File: frenchFile, Line: 3, Type: &SymbolInfo{Name: "French", Kind: SymbolType}},
+			{File: frenchFile, Line: 7, Type: &SymbolInfo{Name: "Polite", Kind: SymbolInterface}},
+		}}
+		annotateImplementerReceivers(context.Background(), mainFile, "Greeter", implementers.Implementers, nil)
+		english, french, polite := implementers.Implementers[0], implementers.Implementers[1], implementers.Implementers[2]
+		if fmt.Sprint(english.Satisfies) != "[*English]" || fmt.Sprint(english.PointerMethods) != "[Greet]" {
+			t.Errorf("Expected English to only satisfy Greeter through a pointer, got %+v", english)
+		}
+		if fmt.Sprint(french.Satisfies) != "[French *French]" || len(french.PointerMethods) != 0 {
+			t.Errorf("Expected French and *French to satisfy Greeter, got %+v", french)
+		}
+		if len(polite.Satisfies) != 0 {
+			t.Errorf("Expected no receiver annotation for an interface, got %+v", polite)
+		}
+
+		var b strings.Builder
+		writeImplementers(&b, implementers)
+		for _, expected := range []string{
+			"Satisfied by *English only, values of English lack the pointer receiver methods Greet",
+			"Satisfied by French and *French",
+		} {
+			if !strings.Contains(b.String(), expected) {
+				t.Errorf("Expected the implementers to contain %q, got:\n%s", expected, b.String())
+			}
+		}
+	})
+
+	t.Run("examples", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		exampleLines := []string{
+			"package testpkg_test", // 1
+			"",
+			"import (", // 3
+			"	\"fmt\"",
+			"",
+			"	\"testmodule\"", // 6
+			")",
+			"",
+			"func ExampleEnglish() {", // 9
+			"	_ = testpkg.English{Prefix: \"Hi \"}",
+			"}",
+			"",
+			"func ExampleEnglish_Greet() {", // 13
+			"	fmt.Println((&testpkg.English{Prefix: \"Hi \"}).Greet(\"Bob\"))",
+			"	// Output: Hi Bob",
+			"}",
+			"",
+			"func ExampleEnglish_Greet_outdated() {", // 18
+			"	fmt.Println((&testpkg.English{Prefix: \"Hi \"}).Greet(\"Bob\"))",
+			"	// Output: Hello Bob",
+			"}",
+			"",
+		}
+		if err := os.WriteFile(filepath.Join(workspace, "example_test.go"), []byte(strings.Join(exampleLines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mainFile := filepath.Join(workspace, "main.go")
+
+		options := inspectOptions(workspace, 0, "English")
+		options.IncludeReferences = false
+		options.IncludeImplementers = false
+		options.IncludeExamples = true
+		result, err := InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		if len(result.Symbol.Examples) != 1 || result.Symbol.Examples[0].Name != "ExampleEnglish" || result.Symbol.Examples[0].HasOutput {
+			t.Errorf("Expected the example of the type, got %+v", result.Symbol.Examples)
+		}
+		methodExamples := result.Symbol.Methods[0].Examples
+		if len(methodExamples) != 2 || methodExamples[0].Name != "ExampleEnglish_Greet" || methodExamples[0].Line != 13 ||
+			methodExamples[0].Output != "Hi Bob" || methodExamples[0].Run != nil {
+			t.Errorf("Expected the examples of the method without running them, got %+v", methodExamples)
+		}
+
+		options.RunExamples = true
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect type: %v", err)
+		}
+		text := result.String()
+		for _, expected := range []string{
+			"Example: ExampleEnglish (" + filepath.Join(workspace, "example_test.go") + ":9)\nfunc ExampleEnglish() {\n",
+			"}\nVerified: not run, go test only runs examples with an // Output: comment",
+			"// Output: Hi Bob\n}\nVerified: the output matches the output comment",
+			"// Output: Hello Bob\n}\nVerified: FAILED, the output differs from the output comment, got:\nHi Bob",
+		} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Expected the text to contain %q, got:\n%s", expected, text)
+			}
+		}
+
+		options = inspectOptions(workspace, 0, "MaxLen")
+		options.IncludeReferences = false
+		options.RunExamples = true
+		result, err = InspectStructured(context.Background(), mainFile, options)
+		if err != nil || len(result.Symbol.Examples) != 0 {
+			t.Errorf("Expected no examples for a constant, got %v, %v", result, err)
+		}
+	})
+
+	t.Run("highlight", func(t *testing.T) {
+		t.Parallel()
+		code := "func (e *English) Greet(name string) string { // greets\n\treturn fmt.Sprint(\"Hi \", 42)\n}"
+		var got []string
+		for _, token := range HighlightCode(code) {
+			got = append(got, fmt.Sprintf("%s:%s", token.Class, code[token.Offset:token.Offset+token.Length]))
+		}
+		expected := []string{
+			"keyword:func", "operator:(", "ident:e", "operator:*", "ident:English", "operator:)",
+			"ident:Greet", "operator:(", "ident:name", "builtin:string", "operator:)", "builtin:string",
+			"operator:{", "comment:// greets", "keyword:return", "ident:fmt", "operator:.", "ident:Sprint",
+			"operator:(", `string:"Hi "`, "operator:,", "number:42", "operator:)", "operator:}",
+		}
+		if strings.Join(got, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected tokens\n%v\ngot\n%v", expected, got)
+		}
+
+		workspace := createTestWorkspace(t)
+		options := inspectOptions(workspace, 0, "")
+		options.IncludeReferences = false
+		result, err := InspectStructured(context.Background(), filepath.Join(workspace, "main.go"), options)
+		if err != nil {
+			t.Fatalf("Failed to inspect file: %v", err)
+		}
+		result.Highlight()
+		for _, symbol := range result.File.Symbols {
+			if symbol.Language != "go" || len(symbol.Tokens) == 0 {
+				t.Errorf("Expected %s to be highlighted, got %+v", symbol.Name, symbol)
+			}
+		}
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		for name, content := range map[string]string{
+			"go.mod": "module example.com/chunks\n\ngo 1.22\n",
+			"a.go":   "package chunks\n\nfunc Alpha() {}\n\nfunc Beta() {}\n",
+			"b.go":   "package chunks\n\ntype Gamma int\n",
+		} {
+			if err := os.WriteFile(filepath.Join(workspace, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		options := DefaultInspectOptions(workspace)
+		options.IncludeReferences = false
+
+		result, err := InspectStructured(context.Background(), workspace, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect the package: %v", err)
+		}
+		var chunks []string
+		for chunk := range result.Chunks(1) {
+			chunks = append(chunks, chunk)
+		}
+		// The package header, then a chunk per file header and per symbol starting with the
+		// separator before it
+		if len(chunks) != 6 || !strings.HasPrefix(chunks[0], "Directory: ") || !strings.HasPrefix(chunks[1], "File: ") ||
+			!strings.HasPrefix(chunks[2], "\n\nLines: 3\nCode:\nfunc Alpha") || !strings.HasPrefix(chunks[4], "\n---\nFile: ") {
+			t.Errorf("Expected a chunk before every file and symbol, got %q", chunks)
+		}
+		if strings.Join(chunks, "") != result.String() {
+			t.Errorf("Expected the chunks to join to the text of the result, got %q", chunks)
+		}
+		for chunk := range result.Chunks(1 << 20) {
+			if chunk != result.String() {
+				t.Errorf("Expected one chunk for a small result, got %q", chunk)
+			}
+		}
+
+		// Written while the files are inspected, the chunks are the same
+		for _, path := range []string{workspace, filepath.Join(workspace, "a.go"), filepath.Join(workspace, "a.go") + ":Beta"} {
+			options := options
+			path, options.LineNumber, options.SymbolName = parseInspectPath(path)
+			result, err := InspectStructured(context.Background(), path, options)
+			if err != nil {
+				t.Fatalf("Failed to inspect %s: %v", path, err)
+			}
+			var streamed []string
+			if err := inspectChunks(context.Background(), path, options, 1, func(chunk string) { streamed = append(streamed, chunk) }); err != nil {
+				t.Fatalf("Failed to stream %s: %v", path, err)
+			}
+			if expected := slices.Collect(result.Chunks(1)); !slices.Equal(streamed, expected) {
+				t.Errorf("Expected the chunks of %s to be %q, got %q", path, expected, streamed)
+			}
+		}
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		lines := []string{"package big", ""}
+		for i := range 1000 {
+			lines = append(lines,
+				fmt.Sprintf("// Function%d is one of many functions making the package too big for one chunk", i),
+				fmt.Sprintf("func Function%d(value int) int { return value + %d }", i, i),
+				"",
+			)
+		}
+		for name, content := range map[string]string{
+			"go.mod": "module example.com/big\n\ngo 1.22\n",
+			"big.go": strings.Join(lines, "\n"),
+		} {
+			if err := os.WriteFile(filepath.Join(workspace, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		options := DefaultInspectOptions(workspace)
+		options.IncludeReferences = false
+		result, err := InspectStructured(context.Background(), workspace, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect the package: %v", err)
+		}
+		expected := result.String()
+
+		mcpServer := NewMCPServer()
+		message, err := toolCallMessage(inspectToolName, map[string]any{
+			"path":               workspace,
+			"workspace_dir":      workspace,
+			"include_references": false,
+			"stream":             true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var call map[string]any
+		if err := json.Unmarshal(message, &call); err != nil {
+			t.Fatal(err)
+		}
+		call["params"].(map[string]any)["_meta"] = map[string]any{"progressToken": "inspect-1"}
+		encoded, err := json.Marshal(call)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Without a session the chunks are returned as content blocks
+		response := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		blocks := response.Result.(mcp.CallToolResult)
+		if blocks.IsError || len(blocks.Content) < 2 {
+			t.Fatalf("Expected the result in several content blocks, got %d: %v", len(blocks.Content), blocks.Content)
+		}
+		var text strings.Builder
+		for _, content := range blocks.Content {
+			text.WriteString(content.(mcp.TextContent).Text)
+		}
+		if text.String() != expected {
+			t.Errorf("Expected the blocks to join to the whole result, got:\n%s", text.String())
+		}
+
+		// With one, progress notifications report the chunks and the result still holds them
+		session := &notifyingSession{testSession{id: "stream-test-session"}, make(chan mcp.JSONRPCNotification, 100)}
+		response = mcpServer.HandleMessage(mcpServer.WithContext(context.Background(), session), encoded).(mcp.JSONRPCResponse)
+		notified := response.Result.(mcp.CallToolResult)
+		close(session.notifications)
+		progress := 0
+		for notification := range session.notifications {
+			fields := notification.Params.AdditionalFields
+			if notification.Method != "notifications/progress" || fields["progressToken"] != "inspect-1" || fields["progress"] != progress+1 {
+				t.Fatalf("Expected progress notification %d, got %v", progress+1, notification)
+			}
+			progress++
+			if message := fields["message"].(string); !strings.HasPrefix(message, fmt.Sprintf("Inspected %d chunk(s), ", progress)) {
+				t.Errorf("Expected a status message, got %q", message)
+			}
+			if total, ok := fields["total"]; ok != (progress == len(blocks.Content)) || ok && total != progress {
+				t.Errorf("Expected only the last notification to set the total, got %v", fields)
+			}
+		}
+		if progress != len(blocks.Content) {
+			t.Errorf("Expected a notification per chunk, got %d of %d", progress, len(blocks.Content))
+		}
+		if !slices.Equal(notified.Content, blocks.Content) {
+			t.Errorf("Expected the result to hold the chunks, got %d content blocks", len(notified.Content))
+		}
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mcpServer := NewMCPServer()
+
+		for _, tool := range []string{inspectToolName, batchInspectToolName} {
+			arguments := map[string]any{
+				"workspace_dir":      workspace,
+				"include_references": false,
+				"format":             "json",
+			}
+			if tool == inspectToolName {
+				arguments["path"] = filepath.Join(workspace, "main.go") + ":Greet"
+			} else {
+				arguments["paths"] = []string{filepath.Join(workspace, "main.go") + ":Greet"}
+			}
+			encoded, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      1,
+				"method":  string(mcp.MethodToolsCall),
+				"params":  map[string]any{"name": tool, "arguments": arguments},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+			result := response.Result.(mcp.CallToolResult)
+			text := result.Content[0].(mcp.TextContent).Text
+			if result.IsError {
+				t.Fatalf("Expected %s to succeed, got: %s", tool, text)
+			}
+			var symbol *SymbolInfo
+			if tool == inspectToolName {
+				var decoded InspectResult
+				if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+					t.Fatalf("Expected a JSON result, got %v: %s", err, text)
+				}
+				symbol = decoded.Symbol
+			} else {
+				var decoded []struct {
+					Result *InspectResult `json:"result"`
+				}
+				if err := json.Unmarshal([]byte(text), &decoded); err != nil || len(decoded) != 1 || decoded[0].Result == nil {
+					t.Fatalf("Expected a JSON array of results, got %v: %s", err, text)
+				}
+				symbol = decoded[0].Result.Symbol
+			}
+			if symbol == nil || symbol.Language != "go" || len(symbol.Tokens) == 0 || symbol.Tokens[0] != (CodeToken{Offset: 0, Length: 4, Class: TokenKeyword}) {
+				t.Errorf("Expected a highlighted symbol from %s, got: %s", tool, text)
+			}
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestIntegration(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a function to read through the tools
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"greet/greet.go": {
+				"package greet",                    // 1
+				"",                                 // 2
+				"// Hello greets name",             // 3
+				"func Hello(name string) string {", // 4
+				"\treturn \"Hello, \" + name",      // 5
+				"}",                                // 6
+				"",                                 // 7
+			},
+		})
+	}
+
+	// Helper function to start a client, closing it when the test ends
+	start := func(t testing.TB, mcpClient *client.Client) *client.Client {
+		if err := mcpClient.Start(context.Background()); err != nil {
+			t.Fatalf("Failed to start client: %v", err)
+		}
+		t.Cleanup(func() { mcpClient.Close() })
+		return mcpClient
+	}
+
+	// Helper function to perform the initialization handshake of a client
+	initialize := func(t testing.TB, mcpClient *client.Client) {
+		_, err := mcpClient.Initialize(context.Background(), mcp.InitializeRequest{
+			Params: mcp.InitializeParams{ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION},
+		})
+		if err != nil {
+			t.Fatalf("Failed to initialize: %v", err)
+		}
+	}
+
+	// Transports connect an initialized mcp-go client to the server in-process, so tests
+	// cover the protocol layer the way MCP clients use it
+	transports := map[string]func(t testing.TB, mcpServer *server.MCPServer) *client.Client{
+		// pipe serves the stdio transport of ServeStdio over in-memory pipes
+		"pipe": func(t testing.TB, mcpServer *server.MCPServer) *client.Client {
+			clientReader, serverWriter := io.Pipe()
+			serverReader, clientWriter := io.Pipe()
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_ = serveStdio(ctx, mcpServer, serverReader, serverWriter)
+			}()
+			t.Cleanup(func() {
+				cancel()
+				clientWriter.Close()
+				serverWriter.Close()
+				<-done
+			})
+			return start(t, client.NewClient(transport.NewIO(clientReader, clientWriter, io.NopCloser(strings.NewReader("")))))
+		},
+		// http serves the streamable HTTP transport of ServeHTTP on a local test server
+		"http": func(t testing.TB, mcpServer *server.MCPServer) *client.Client {
+			httpServer := httptest.NewServer(resourceSubscriptionHandler(mcpServer, server.NewStreamableHTTPServer(mcpServer)))
+			t.Cleanup(httpServer.Close)
+			mcpClient, err := client.NewStreamableHttpClient(httpServer.URL + "/mcp")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return start(t, mcpClient)
+		},
+	}
+
+	for name, connect := range transports {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("initialize", func(t *testing.T) {
+				t.Parallel()
+				mcpClient := connect(t, NewMCPServer(WithConfig(&ServerConfig{Name: "integration", Version: "0.0.1"})))
+				result, err := mcpClient.Initialize(context.Background(), mcp.InitializeRequest{
+					Params: mcp.InitializeParams{ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION},
+				})
+				if err != nil {
+					t.Fatalf("Failed to initialize: %v", err)
+				}
+				if result.ServerInfo.Name != "integration" || result.ServerInfo.Version != "0.0.1" {
+					t.Errorf("Expected the configured server info, got %+v", result.ServerInfo)
+				}
+				if result.Capabilities.Tools == nil || result.Capabilities.Resources != nil {
+					t.Errorf("Expected only the tools capability, got %+v", result.Capabilities)
+				}
+			})
+
+			t.Run("list tools", func(t *testing.T) {
+				t.Parallel()
+				mcpClient := connect(t, NewMCPServer())
+				initialize(t, mcpClient)
+				result, err := mcpClient.ListTools(context.Background(), mcp.ListToolsRequest{})
+				if err != nil {
+					t.Fatalf("Failed to list tools: %v", err)
+				}
+				tools := make(map[string]mcp.Tool)
+				for _, tool := range result.Tools {
+					tools[tool.Name] = tool
+				}
+				body, ok := tools[bodyToolName]
+				if !ok {
+					t.Fatalf("Expected the body tool to be listed, got %d tools", len(result.Tools))
+				}
+				if !strings.Contains(body.Description, "Cost: low.") || body.Annotations.ReadOnlyHint == nil || !*body.Annotations.ReadOnlyHint {
+					t.Errorf("Expected the cost hint and read-only annotation, got %q and %+v", body.Description, body.Annotations)
+				}
+				if _, ok := tools[sortToolName].InputSchema.Properties[outputArgumentName]; !ok {
+					t.Errorf("Expected the output argument of mutating tools, got %+v", tools[sortToolName].InputSchema)
+				}
+			})
+
+			t.Run("call tool", func(t *testing.T) {
+				t.Parallel()
+				dir := createTestWorkspace(t)
+				mcpClient := connect(t, NewMCPServer())
+				initialize(t, mcpClient)
+
+				request := mcp.CallToolRequest{}
+				request.Params.Name = bodyToolName
+				request.Params.Arguments = map[string]any{"path": filepath.Join(dir, "greet"), "symbol": "Hello"}
+				result, err := mcpClient.CallTool(context.Background(), request)
+				if err != nil {
+					t.Fatalf("Failed to call tool: %v", err)
+				}
+				expected := "func Hello(name string) string {\n\treturn \"Hello, \" + name\n}"
+				if text := toolResultText(result); result.IsError || !strings.Contains(text, expected) {
+					t.Errorf("Expected the function body, got: %s", text)
+				}
+
+				request.Params.Arguments = map[string]any{"path": filepath.Join(dir, "greet"), "symbol": "Hello", "line_numbers": "yes"}
+				result, err = mcpClient.CallTool(context.Background(), request)
+				if err != nil {
+					t.Fatalf("Failed to call tool: %v", err)
+				}
+				if text := toolResultText(result); !result.IsError || !strings.Contains(text, `argument "line_numbers" must be a boolean`) {
+					t.Errorf("Expected an argument error, got: %s", text)
+				}
+			})
+
+			t.Run("read resource", func(t *testing.T) {
+				t.Parallel()
+				dir := createTestWorkspace(t)
+				mcpClient := connect(t, NewMCPServer(WithSymbolResources(dir)))
+				initialize(t, mcpClient)
+
+				templates, err := mcpClient.ListResourceTemplates(context.Background(), mcp.ListResourceTemplatesRequest{})
+				if err != nil {
+					t.Fatalf("Failed to list resource templates: %v", err)
+				}
+				if len(templates.ResourceTemplates) != 2 {
+					t.Errorf("Expected the gosym and gopkg templates, got %+v", templates.ResourceTemplates)
+				}
+
+				request := mcp.ReadResourceRequest{}
+				request.Params.URI = "gosym://example.com/app/greet/Hello"
+				result, err := mcpClient.ReadResource(context.Background(), request)
+				if err != nil {
+					t.Fatalf("Failed to read resource: %v", err)
+				}
+				if len(result.Contents) != 1 {
+					t.Fatalf("Expected 1 content, got %d", len(result.Contents))
+				}
+				if text := result.Contents[0].(mcp.TextResourceContents).Text; !strings.Contains(text, "func Hello(name string) string") {
+					t.Errorf("Expected the inspected function, got: %s", text)
+				}
+			})
+
+			t.Run("subscribe to resource", func(t *testing.T) {
+				t.Parallel()
+				dir := createTestWorkspace(t)
+				mcpServer := NewMCPServer(WithDiagnostics(DiagnosticsOptions{WorkspaceDir: dir, Interval: 10 * time.Millisecond}))
+				mcpClient := connect(t, mcpServer)
+				updates := make(chan any, 100)
+				mcpClient.OnNotification(func(notification mcp.JSONRPCNotification) {
+					if notification.Method == mcp.MethodNotificationResourceUpdated {
+						updates <- notification.Params.AdditionalFields["uri"]
+					}
+				})
+				result, err := mcpClient.Initialize(context.Background(), mcp.InitializeRequest{
+					Params: mcp.InitializeParams{ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION},
+				})
+				if err != nil {
+					t.Fatalf("Failed to initialize: %v", err)
+				}
+				if result.Capabilities.Resources == nil || !result.Capabilities.Resources.Subscribe {
+					t.Errorf("Expected subscribable resources, got %+v", result.Capabilities)
+				}
+
+				subscribe := mcp.SubscribeRequest{}
+				subscribe.Params.URI = "gosym://example.com/app/greet/Hello"
+				if err := mcpClient.Subscribe(context.Background(), subscribe); err == nil || !strings.Contains(err.Error(), "cannot be subscribed to") {
+					t.Errorf("Expected other resources not to be subscribable, got: %v", err)
+				}
+				subscribe.Params.URI = diagnosticsResourceURI
+				if err := mcpClient.Subscribe(context.Background(), subscribe); err != nil {
+					t.Fatalf("Failed to subscribe: %v", err)
+				}
+				if subscribers := resourceSubscriptions.subscribers(mcpServer, diagnosticsResourceURI); len(subscribers) != 1 {
+					t.Fatalf("Expected one subscribed session, got %v", subscribers)
+				}
+
+				// The HTTP client of mcp-go opens no stream to receive notifications outside of requests
+				if name == "pipe" {
+					err := os.WriteFile(filepath.Join(dir, "greet", "greet.go"), []byte("package greet\n\nvar Broken int = \"\"\n"), 0644)
+					if err != nil {
+						t.Fatal(err)
+					}
+					select {
+					case uri := <-updates:
+						if uri != diagnosticsResourceURI {
+							t.Errorf("Expected an update of %s, got %v", diagnosticsResourceURI, uri)
+						}
+					case <-time.After(time.Minute):
+						t.Fatal("Expected an update after changing the module")
+					}
+				}
+
+				unsubscribe := mcp.UnsubscribeRequest{}
+				unsubscribe.Params.URI = diagnosticsResourceURI
+				if err := mcpClient.Unsubscribe(context.Background(), unsubscribe); err != nil {
+					t.Fatalf("Failed to unsubscribe: %v", err)
+				}
+				if subscribers := resourceSubscriptions.subscribers(mcpServer, diagnosticsResourceURI); len(subscribers) != 0 {
+					t.Errorf("Expected no subscribed sessions, got %v", subscribers)
+				}
+			})
+		})
+	}
+}
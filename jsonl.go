@@ -0,0 +1,134 @@
+package go_mcp_tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// jsonlMaxLineSize is the largest accepted request line
+const jsonlMaxLineSize = 64 << 20
+
+// JSONLRequest is a single line of input for the jsonl transport, e.g.
+// {"id": 1, "tool": "inspect", "arguments": {"path": "./pkg", "workspace_dir": "/repo"}}
+type JSONLRequest struct {
+	// ID is an optional caller chosen identifier echoed in the response
+	ID        any            `json:"id,omitempty"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// JSONLResponse is a single line of output for the jsonl transport.
+// Text holds the concatenated text content of the tool result. Error is set when
+// the request could not be executed at all, IsError when the tool reported a failure.
+type JSONLResponse struct {
+	ID      any    `json:"id,omitempty"`
+	Tool    string `json:"tool,omitempty"`
+	Text    string `json:"text,omitempty"`
+	IsError bool   `json:"is_error,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ServeJSONL reads one JSONLRequest per line from in and writes one JSONLResponse per line to out.
+// Requests are executed in order against the tools registered on the MCP server without an MCP
+// session, so shell scripts can drive the tools without an MCP client library.
+// Returns when in is exhausted.
+func ServeJSONL(mcpServer *server.MCPServer, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), jsonlMaxLineSize)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		response := handleJSONLRequest(mcpServer, []byte(line))
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("failed to write jsonl response: %w", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read jsonl request: %w", err)
+	}
+	return nil
+}
+
+// handleJSONLRequest executes a single jsonl request line
+func handleJSONLRequest(mcpServer *server.MCPServer, line []byte) JSONLResponse {
+	var request JSONLRequest
+	if err := json.Unmarshal(line, &request); err != nil {
+		return JSONLResponse{
+			Error: fmt.Sprintf(
+				`invalid request, expected {"tool": "<name>", "arguments": {...}}: %v`,
+				err,
+			),
+		}
+	}
+
+	return executeJSONLRequest(mcpServer, request)
+}
+
+// executeJSONLRequest calls the requested tool on the MCP server
+func executeJSONLRequest(mcpServer *server.MCPServer, request JSONLRequest) JSONLResponse {
+	response := JSONLResponse{ID: request.ID, Tool: request.Tool}
+	if request.Tool == "" {
+		response.Error = "tool is required"
+		return response
+	}
+
+	// Dispatch through the regular MCP request handling so middleware and hooks apply
+	message, err := toolCallMessage(request.Tool, request.Arguments)
+	if err != nil {
+		response.Error = fmt.Sprintf("failed to encode tool call: %v", err)
+		return response
+	}
+
+	switch result := mcpServer.HandleMessage(context.Background(), message).(type) {
+	case mcp.JSONRPCResponse:
+		callResult, ok := result.Result.(mcp.CallToolResult)
+		if !ok {
+			response.Error = fmt.Sprintf("unexpected tool result type %T", result.Result)
+			return response
+		}
+		response.Text = toolResultText(&callResult)
+		response.IsError = callResult.IsError
+	case mcp.JSONRPCError:
+		response.Error = result.Error.Message
+	default:
+		response.Error = fmt.Sprintf("unexpected response type %T", result)
+	}
+	return response
+}
+
+// toolCallMessage encodes the JSON-RPC message calling a tool
+func toolCallMessage(name string, arguments map[string]any) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  string(mcp.MethodToolsCall),
+		"params": map[string]any{
+			"name":      name,
+			"arguments": arguments,
+		},
+	})
+}
+
+// toolResultText concatenates the text content of a tool result
+func toolResultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
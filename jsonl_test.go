@@ -0,0 +1,109 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeJSONL(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to run a jsonl session and decode all response lines
+	runSession := func(t testing.TB, input string) []JSONLResponse {
+		var out bytes.Buffer
+		if err := ServeJSONL(NewMCPServer(), strings.NewReader(input), &out); err != nil {
+			t.Fatalf("Failed to serve jsonl: %v", err)
+		}
+
+		var responses []JSONLResponse
+		for line := range strings.SplitSeq(strings.TrimSpace(out.String()), "\n") {
+			var response JSONLResponse
+			if err := json.Unmarshal([]byte(line), &response); err != nil {
+				t.Fatalf("Failed to decode response line %q: %v", line, err)
+			}
+			responses = append(responses, response)
+		}
+		return responses
+	}
+
+	t.Run("tool calls produce one result per line", func(t *testing.T) {
+		t.Parallel()
+		lines := []string{
+			"package testpkg", // 1
+			"",                // 2
+			"var M = map[string]int{\"b\": 2, \"a\": 1}", // 3
+			"", // 4
+		}
+		filePath := filepath.Join(t.TempDir(), "main.go")
+		if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		request, err := json.Marshal(JSONLRequest{
+			ID:        "sort-1",
+			Tool:      sortToolName,
+			Arguments: map[string]any{"file_path": filePath},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := strings.Join([]string{
+			string(request), // 1
+			"",              // 2 - blank lines are skipped
+			string(request), // 3 - already sorted now
+		}, "\n")
+
+		responses := runSession(t, input)
+		if len(responses) != 2 {
+			t.Fatalf("Expected 2 responses, got %d", len(responses))
+		}
+		if responses[0].ID != "sort-1" || responses[0].Tool != sortToolName {
+			t.Errorf("Expected id and tool to be echoed, got %+v", responses[0])
+		}
+		if !strings.Contains(responses[0].Text, "1 map literal(s)") {
+			t.Errorf("Expected sort summary, got %+v", responses[0])
+		}
+		if !strings.Contains(responses[1].Text, "already sorted") {
+			t.Errorf("Expected second call to report already sorted, got %+v", responses[1])
+		}
+	})
+
+	t.Run("tool failures set is_error", func(t *testing.T) {
+		t.Parallel()
+		input := `{"tool": "sort", "arguments": {"file_path": "/nonexistent/main.go"}}`
+
+		responses := runSession(t, input)
+		if len(responses) != 1 || !responses[0].IsError {
+			t.Fatalf("Expected a single failed tool result, got %+v", responses)
+		}
+		if !strings.Contains(responses[0].Text, "Error sorting file") {
+			t.Errorf("Expected error text, got %+v", responses[0])
+		}
+	})
+
+	t.Run("malformed requests set error", func(t *testing.T) {
+		t.Parallel()
+		input := strings.Join([]string{
+			`not json`,                             // 1
+			`{"arguments": {}}`,                    // 2 - missing tool
+			`{"tool": "missing", "arguments": {}}`, // 3 - unknown tool
+		}, "\n")
+
+		responses := runSession(t, input)
+		if len(responses) != 3 {
+			t.Fatalf("Expected 3 responses, got %d", len(responses))
+		}
+		for i, response := range responses {
+			if response.Error == "" {
+				t.Errorf("Expected error for request %d, got %+v", i+1, response)
+			}
+		}
+		if !strings.Contains(responses[1].Error, "tool is required") {
+			t.Errorf("Expected missing tool error, got %q", responses[1].Error)
+		}
+	})
+}
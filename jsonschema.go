@@ -0,0 +1,545 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	jsonSchemaToolName        = "json_schema"
+	jsonSchemaToolDescription = `Converts a Go struct type to a JSON Schema (draft 2020-12) of its encoding/json encoding, or to an example JSON document, to document or validate API payloads defined in Go.
+
+The fields follow the rules of encoding/json: exported fields named by their json tags, fields tagged "-" left out, and the fields of embedded structs without a tag name promoted, the shallowest winning conflicts. Fields without omitempty or omitzero are required, and the string option encodes numbers and booleans as strings. Doc comments become descriptions.
+
+time.Time is a date-time string, []byte a base64 string, types with a MarshalText method strings and other types with a MarshalJSON method accept any value. Named structs other than the root are declared in $defs and referenced, which allows recursive types.`
+)
+
+// jsonSchemaURI is the dialect of the generated schemas
+const jsonSchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+func AddJSONSchemaTool(mcpServer *server.MCPServer) {
+	handleJSONSchema := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := request.RequireString("type")
+		if err != nil {
+			return nil, err
+		}
+		example := request.GetBool("example", false)
+
+		result, err := JSONSchema(ctx, filePath, typeName, example)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error generating JSON Schema: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		jsonSchemaToolName,
+		mcp.WithDescription(jsonSchemaToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the struct"),
+			mcp.Required(),
+		),
+		mcp.WithString("type",
+			mcp.Description("Name of the struct type"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("example",
+			mcp.Description("Whether to generate an example document with placeholder values instead of the JSON Schema"),
+			mcp.DefaultBool(false),
+		),
+	), handleJSONSchema)
+}
+
+// JSONSchema returns the indented JSON Schema of the encoding/json encoding of the struct
+// typeName declared in filePath, or an example document of it when example is set
+func JSONSchema(ctx context.Context, filePath string, typeName string, example bool) (string, error) {
+	if !filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	pkg, _, pkgs, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return "", err
+	}
+	if len(pkg.Errors) > 0 {
+		return "", classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return "", classifyErrorf(ErrSymbolNotFound, "no type '%s' declared in package %s", typeName, pkg.PkgPath)
+	}
+	named, ok := types.Unalias(obj.Type()).(*types.Named)
+	if !ok {
+		return "", fmt.Errorf("'%s' is not a named type", typeName)
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return "", fmt.Errorf("'%s' is not a struct", typeName)
+	}
+	if named.TypeParams().Len() > 0 {
+		return "", fmt.Errorf("'%s' is generic, its encoding depends on the type arguments", typeName)
+	}
+
+	generator := &jsonSchemaGenerator{
+		docs: make(map[token.Pos]string),
+		defs: make(map[*types.Named]string),
+	}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, file := range pkg.Syntax {
+			generator.collectDocs(file)
+		}
+	})
+
+	var value any
+	if example {
+		value, err = generator.example(named, nil)
+	} else {
+		// Recursive references to the root refer to the whole document
+		generator.defs[named] = "#"
+		var schema jsonObject
+		schema, err = generator.objectSchema(named.Underlying().(*types.Struct))
+		schema = append(jsonObject{{"$schema", jsonSchemaURI}, {"title", typeName}}, generator.describe(obj.Pos(), schema)...)
+		if len(generator.definitions) > 0 {
+			schema = append(schema, jsonMember{"$defs", generator.definitions})
+		}
+		value = schema
+	}
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// jsonMember is a member of a jsonObject
+type jsonMember struct {
+	key   string
+	value any
+}
+
+// jsonObject is a JSON object encoded with its members in order
+type jsonObject []jsonMember
+
+func (object jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	b.WriteString("{")
+	for i, member := range object {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		if err := encoder.Encode(member.key); err != nil {
+			return nil, err
+		}
+		b.WriteString(":")
+		if err := encoder.Encode(member.value); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+// jsonField is a field of the encoding of a struct
+type jsonField struct {
+	name string
+	// index is the path of field indices through embedded structs, whose length is the depth
+	index     []int
+	field     *types.Var
+	tagged    bool
+	omitEmpty bool
+	asString  bool
+}
+
+// jsonSchemaGenerator generates the schema or example of a type, declaring the named
+// structs it refers to in $defs
+type jsonSchemaGenerator struct {
+	// docs maps the positions of the names of types and fields to their doc comments
+	docs map[token.Pos]string
+	// defs maps the named structs to their references
+	defs        map[*types.Named]string
+	definitions jsonObject
+}
+
+// collectDocs records the doc comments, or line comments, of the types and fields of the file
+func (generator *jsonSchemaGenerator) collectDocs(file *ast.File) {
+	text := func(groups ...*ast.CommentGroup) string {
+		for _, group := range groups {
+			if group != nil {
+				return strings.Join(strings.Fields(group.Text()), " ")
+			}
+		}
+		return ""
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.GenDecl:
+			if node.Tok == token.TYPE && len(node.Specs) == 1 && node.Doc != nil {
+				generator.docs[node.Specs[0].(*ast.TypeSpec).Name.Pos()] = text(node.Doc)
+			}
+		case *ast.TypeSpec:
+			if doc := text(node.Doc, node.Comment); doc != "" {
+				generator.docs[node.Name.Pos()] = doc
+			}
+		case *ast.Field:
+			doc := text(node.Doc, node.Comment)
+			if doc == "" {
+				return true
+			}
+			for _, name := range node.Names {
+				generator.docs[name.Pos()] = doc
+			}
+			if len(node.Names) == 0 {
+				generator.docs[embeddedFieldPos(node.Type)] = doc
+			}
+		}
+		return true
+	})
+}
+
+// embeddedFieldPos returns the position of the type name of an embedded field, which is
+// the position of its types.Var
+func embeddedFieldPos(expr ast.Expr) token.Pos {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldPos(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Pos()
+	case *ast.IndexExpr:
+		return embeddedFieldPos(expr.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldPos(expr.X)
+	}
+	return expr.Pos()
+}
+
+// jsonFields returns the fields of the encoding of the struct in the order encoding/json
+// writes them, promoting the fields of embedded structs without a tag name
+func jsonFields(structType *types.Struct) []jsonField {
+	type queued struct {
+		structType *types.Struct
+		index      []int
+	}
+	var candidates []jsonField
+	visited := make(map[*types.Struct]bool)
+	for queue := []queued{{structType, nil}}; len(queue) > 0; {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current.structType] {
+			continue
+		}
+		visited[current.structType] = true
+
+		for i := range current.structType.NumFields() {
+			field := current.structType.Field(i)
+			index := append(slices.Clone(current.index), i)
+			tag := reflect.StructTag(current.structType.Tag(i)).Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			fieldType := types.Unalias(field.Type())
+			if pointer, ok := fieldType.(*types.Pointer); ok && field.Embedded() {
+				fieldType = pointer.Elem()
+			}
+			embedded, isStruct := fieldType.Underlying().(*types.Struct)
+			if field.Embedded() && name == "" && isStruct {
+				queue = append(queue, queued{embedded, index})
+				continue
+			}
+			if !field.Exported() {
+				continue
+			}
+			optionList := strings.Split(options, ",")
+			jsonField := jsonField{
+				name:      name,
+				index:     index,
+				field:     field,
+				tagged:    name != "",
+				omitEmpty: slices.Contains(optionList, "omitempty") || slices.Contains(optionList, "omitzero"),
+			}
+			if jsonField.name == "" {
+				jsonField.name = field.Name()
+			}
+			if basic, ok := field.Type().Underlying().(*types.Basic); ok && slices.Contains(optionList, "string") {
+				jsonField.asString = basic.Info()&(types.IsNumeric|types.IsBoolean|types.IsString) != 0
+			}
+			candidates = append(candidates, jsonField)
+		}
+	}
+
+	// Of the fields with the same name, the shallowest wins, then the only tagged one.
+	// Other conflicts leave all of them out.
+	var fields []jsonField
+	for _, candidate := range candidates {
+		depth := len(candidate.index)
+		var dominant []jsonField
+		for _, other := range candidates {
+			if other.name != candidate.name {
+				continue
+			}
+			if len(other.index) < depth {
+				dominant = nil
+				break
+			}
+			if len(other.index) == depth {
+				dominant = append(dominant, other)
+			}
+		}
+		if len(dominant) > 1 {
+			var tagged []jsonField
+			for _, field := range dominant {
+				if field.tagged {
+					tagged = append(tagged, field)
+				}
+			}
+			dominant = tagged
+		}
+		if len(dominant) == 1 && slices.Equal(dominant[0].index, candidate.index) {
+			fields = append(fields, candidate)
+		}
+	}
+	slices.SortFunc(fields, func(a, b jsonField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return fields
+}
+
+// customJSON returns how the type encodes itself, as a date-time, text or any value, and
+// whether it does
+func customJSON(t types.Type) (string, bool) {
+	if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time" {
+		return "date-time", true
+	}
+	methods := types.NewMethodSet(types.NewPointer(t))
+	if _, ok := t.Underlying().(*types.Pointer); ok {
+		methods = types.NewMethodSet(t)
+	}
+	if methods.Lookup(nil, "MarshalJSON") != nil {
+		return "any", true
+	}
+	if methods.Lookup(nil, "MarshalText") != nil {
+		return "text", true
+	}
+	return "", false
+}
+
+// schema returns the schema of the type, a reference for named structs
+func (generator *jsonSchemaGenerator) schema(t types.Type) (jsonObject, error) {
+	t = types.Unalias(t)
+	if encoding, ok := customJSON(t); ok {
+		switch encoding {
+		case "date-time":
+			return jsonObject{{"type", "string"}, {"format", "date-time"}}, nil
+		case "text":
+			return jsonObject{{"type", "string"}}, nil
+		}
+		return jsonObject{{"description", fmt.Sprintf("Encoded by the MarshalJSON method of %s", types.TypeString(t, nil))}}, nil
+	}
+
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		switch info := underlying.Info(); {
+		case info&types.IsBoolean != 0:
+			return jsonObject{{"type", "boolean"}}, nil
+		case info&types.IsInteger != 0:
+			return jsonObject{{"type", "integer"}}, nil
+		case info&types.IsFloat != 0:
+			return jsonObject{{"type", "number"}}, nil
+		case info&types.IsString != 0:
+			return jsonObject{{"type", "string"}}, nil
+		}
+	case *types.Pointer:
+		return generator.schema(underlying.Elem())
+	case *types.Interface:
+		return jsonObject{}, nil
+	case *types.Slice:
+		if basic, ok := underlying.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return jsonObject{{"type", "string"}, {"contentEncoding", "base64"}}, nil
+		}
+		items, err := generator.schema(underlying.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return jsonObject{{"type", "array"}, {"items", items}}, nil
+	case *types.Array:
+		items, err := generator.schema(underlying.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return jsonObject{{"type", "array"}, {"items", items}, {"minItems", underlying.Len()}, {"maxItems", underlying.Len()}}, nil
+	case *types.Map:
+		values, err := generator.schema(underlying.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return jsonObject{{"type", "object"}, {"additionalProperties", values}}, nil
+	case *types.Struct:
+		if named, ok := t.(*types.Named); ok {
+			return generator.reference(named)
+		}
+		return generator.objectSchema(underlying)
+	}
+	return nil, fmt.Errorf("%s cannot be encoded by encoding/json", types.TypeString(t, nil))
+}
+
+// reference declares the named struct in $defs, once, and returns a reference to it
+func (generator *jsonSchemaGenerator) reference(named *types.Named) (jsonObject, error) {
+	if ref, ok := generator.defs[named]; ok {
+		return jsonObject{{"$ref", ref}}, nil
+	}
+	name := named.Obj().Name()
+	for taken := true; taken; {
+		taken = slices.ContainsFunc(generator.definitions, func(member jsonMember) bool { return member.key == name })
+		if taken {
+			name = named.Obj().Pkg().Name() + "." + name
+		}
+	}
+	ref := "#/$defs/" + name
+	generator.defs[named] = ref
+	index := len(generator.definitions)
+	generator.definitions = append(generator.definitions, jsonMember{name, nil})
+	schema, err := generator.objectSchema(named.Underlying().(*types.Struct))
+	if err != nil {
+		return nil, err
+	}
+	generator.definitions[index].value = generator.describe(named.Obj().Pos(), schema)
+	return jsonObject{{"$ref", ref}}, nil
+}
+
+// objectSchema returns the schema of the encoding of the fields of a struct
+func (generator *jsonSchemaGenerator) objectSchema(structType *types.Struct) (jsonObject, error) {
+	var properties jsonObject
+	var required []string
+	for _, field := range jsonFields(structType) {
+		schema, err := generator.schema(field.field.Type())
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.field.Name(), err)
+		}
+		if field.asString {
+			schema = jsonObject{{"type", "string"}}
+		}
+		properties = append(properties, jsonMember{field.name, generator.describe(field.field.Pos(), schema)})
+		if !field.omitEmpty {
+			required = append(required, field.name)
+		}
+	}
+	schema := jsonObject{{"type", "object"}, {"properties", properties}}
+	if len(required) > 0 {
+		schema = append(schema, jsonMember{"required", required})
+	}
+	return schema, nil
+}
+
+// describe adds the doc comment at pos as the description of a schema that is not a reference
+func (generator *jsonSchemaGenerator) describe(pos token.Pos, schema jsonObject) jsonObject {
+	doc := generator.docs[pos]
+	if doc == "" || len(schema) > 0 && schema[0].key == "$ref" {
+		return schema
+	}
+	return append(jsonObject{{"description", doc}}, schema...)
+}
+
+// example returns an example value of the type with placeholder values, null for
+// recursive structs listed in stack
+func (generator *jsonSchemaGenerator) example(t types.Type, stack []*types.Named) (any, error) {
+	t = types.Unalias(t)
+	if encoding, ok := customJSON(t); ok {
+		switch encoding {
+		case "date-time":
+			return "2006-01-02T15:04:05Z", nil
+		case "text":
+			return "string", nil
+		}
+		return nil, nil
+	}
+
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		switch info := underlying.Info(); {
+		case info&types.IsBoolean != 0:
+			return false, nil
+		case info&types.IsNumeric != 0 && info&types.IsComplex == 0:
+			return 0, nil
+		case info&types.IsString != 0:
+			return "string", nil
+		}
+	case *types.Pointer:
+		return generator.example(underlying.Elem(), stack)
+	case *types.Interface:
+		return nil, nil
+	case *types.Slice:
+		if basic, ok := underlying.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return "", nil
+		}
+		value, err := generator.example(underlying.Elem(), stack)
+		if err != nil {
+			return nil, err
+		}
+		return []any{value}, nil
+	case *types.Array:
+		value, err := generator.example(underlying.Elem(), stack)
+		if err != nil {
+			return nil, err
+		}
+		return slices.Repeat([]any{value}, int(underlying.Len())), nil
+	case *types.Map:
+		key := "key"
+		if basic, ok := underlying.Key().Underlying().(*types.Basic); ok && basic.Info()&types.IsInteger != 0 {
+			key = "0"
+		}
+		value, err := generator.example(underlying.Elem(), stack)
+		if err != nil {
+			return nil, err
+		}
+		return jsonObject{{key, value}}, nil
+	case *types.Struct:
+		if named, ok := t.(*types.Named); ok {
+			if slices.Contains(stack, named) {
+				return nil, nil
+			}
+			stack = append(stack, named)
+		}
+		object := jsonObject{}
+		for _, field := range jsonFields(underlying) {
+			value, err := generator.example(field.field.Type(), stack)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.field.Name(), err)
+			}
+			if field.asString {
+				encoded, _ := json.Marshal(value)
+				value = string(encoded)
+			}
+			object = append(object, jsonMember{field.name, value})
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("%s cannot be encoded by encoding/json", types.TypeString(t, nil))
+}
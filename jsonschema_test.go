@@ -0,0 +1,173 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/app", "", "go 1.22", ""},
+		"api/api.go": {
+			"package api",
+			"",
+			"import \"time\"",
+			"",
+			"// Order is a placed order",
+			"type Order struct {",
+			"\t// ID identifies the order",
+			"\tID      string            `json:\"id\"`",
+			"\tItems   []Item            `json:\"items,omitempty\"`",
+			"\tTotal   int64             `json:\"total,string\"`",
+			"\tMeta",
+			"\tCreated time.Time         `json:\"created\"`",
+			"\tParent  *Order            `json:\"parent,omitempty\"`",
+			"\tLabels  map[string]string `json:\"-\"`",
+			"\tsecret  string",
+			"\tData    []byte            `json:\"data,omitempty\"`",
+			"}",
+			"",
+			"// Item is a line of an order",
+			"type Item struct {",
+			"\tSKU      string  // stock keeping unit",
+			"\tQuantity int     `json:\"quantity\"`",
+			"\tPrice    float64 `json:\"price,omitzero\"`",
+			"}",
+			"",
+			"type Meta struct {",
+			"\tSource string `json:\"source\"`",
+			"\t// ID is hidden by the shallower ID of Order",
+			"\tID string `json:\"id\"`",
+			"}",
+			"",
+			"type Handler func()",
+			"",
+			"type Invalid struct {",
+			"\tDone chan bool",
+			"}",
+			"",
+		},
+	})
+	file := filepath.Join(workspace, "api", "api.go")
+
+	t.Run("schema", func(t *testing.T) {
+		t.Parallel()
+		schema, err := JSONSchema(context.Background(), file, "Order", false)
+		if err != nil {
+			t.Fatalf("Failed to generate the schema: %v", err)
+		}
+		expected := strings.Join([]string{
+			`{`,
+			`  "$schema": "https://json-schema.org/draft/2020-12/schema",`,
+			`  "title": "Order",`,
+			`  "description": "Order is a placed order",`,
+			`  "type": "object",`,
+			`  "properties": {`,
+			`    "id": {`,
+			`      "description": "ID identifies the order",`,
+			`      "type": "string"`,
+			`    },`,
+			`    "items": {`,
+			`      "type": "array",`,
+			`      "items": {`,
+			`        "$ref": "#/$defs/Item"`,
+			`      }`,
+			`    },`,
+			`    "total": {`,
+			`      "type": "string"`,
+			`    },`,
+			`    "source": {`,
+			`      "type": "string"`,
+			`    },`,
+			`    "created": {`,
+			`      "type": "string",`,
+			`      "format": "date-time"`,
+			`    },`,
+			`    "parent": {`,
+			`      "$ref": "#"`,
+			`    },`,
+			`    "data": {`,
+			`      "type": "string",`,
+			`      "contentEncoding": "base64"`,
+			`    }`,
+			`  },`,
+			`  "required": [`,
+			`    "id",`,
+			`    "total",`,
+			`    "source",`,
+			`    "created"`,
+			`  ],`,
+			`  "$defs": {`,
+			`    "Item": {`,
+			`      "description": "Item is a line of an order",`,
+			`      "type": "object",`,
+			`      "properties": {`,
+			`        "SKU": {`,
+			`          "description": "stock keeping unit",`,
+			`          "type": "string"`,
+			`        },`,
+			`        "quantity": {`,
+			`          "type": "integer"`,
+			`        },`,
+			`        "price": {`,
+			`          "type": "number"`,
+			`        }`,
+			`      },`,
+			`      "required": [`,
+			`        "SKU",`,
+			`        "quantity"`,
+			`      ]`,
+			`    }`,
+			`  }`,
+			`}`,
+			``,
+		}, "\n")
+		if schema != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, schema)
+		}
+	})
+
+	t.Run("example", func(t *testing.T) {
+		t.Parallel()
+		example, err := JSONSchema(context.Background(), file, "Order", true)
+		if err != nil {
+			t.Fatalf("Failed to generate the example: %v", err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(example), &decoded); err != nil {
+			t.Fatalf("Expected valid JSON, got %v:\n%s", err, example)
+		}
+		for _, expected := range []string{
+			`  "id": "string",` + "\n",
+			`  "items": [` + "\n" + `    {` + "\n" + `      "SKU": "string",` + "\n" + `      "quantity": 0,` + "\n" + `      "price": 0` + "\n" + `    }` + "\n" + `  ],`,
+			`  "total": "0",` + "\n",
+			`  "created": "2006-01-02T15:04:05Z",` + "\n",
+			`  "parent": null,` + "\n",
+		} {
+			if !strings.Contains(example, expected) {
+				t.Errorf("Expected the example to contain %q, got:\n%s", expected, example)
+			}
+		}
+		if strings.Contains(example, "Labels") || strings.Contains(example, "secret") {
+			t.Errorf("Expected left out fields to be missing, got:\n%s", example)
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		for typeName, expected := range map[string]string{
+			"Handler": "'Handler' is not a struct",
+			"Missing": "no type 'Missing' declared",
+			"Invalid": "field Done: chan bool cannot be encoded by encoding/json",
+		} {
+			if _, err := JSONSchema(context.Background(), file, typeName, false); err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected %q for %s, got: %v", expected, typeName, err)
+			}
+		}
+	})
+}
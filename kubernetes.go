@@ -0,0 +1,564 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	kubernetesToolName        = "kubernetes_inventory"
+	kubernetesToolDescription = `Lists the Kubernetes API types, controllers and webhooks of a kubebuilder or controller-runtime project, the places an agent has to touch together when changing an API:
+
+• CRD types: structs with the +kubebuilder:object:root marker, with the group and version of their package (+groupName marker or GroupVersion variable), scope, short names, subresources and storage version.
+• Reconcilers: types with a Reconcile(ctx, req) method, with the types their SetupWithManager builder reconciles (For), owns and watches, and the +kubebuilder:rbac markers of their file.
+• Webhooks: the +kubebuilder:webhook markers with their path, resources and operations, and the NewWebhookManagedBy registrations with their defaulters and validators.
+
+The source is parsed without type checking, so types are shown as written, e.g. batchv1.CronJob.`
+)
+
+func AddKubernetesTool(mcpServer *server.MCPServer) {
+	handleKubernetes := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		inventory, err := FindKubernetesInventory(workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error listing Kubernetes types: %v", err)), nil
+		}
+		return mcp.NewToolResultText(inventory.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		kubernetesToolName,
+		mcp.WithDescription(kubernetesToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+	), handleKubernetes)
+}
+
+// KubernetesInventory lists the API types, controllers and webhooks of a module
+type KubernetesInventory struct {
+	Module        string                `json:"module"`
+	CRDs          []CRDType             `json:"crds,omitempty"`
+	Reconcilers   []Reconciler          `json:"reconcilers,omitempty"`
+	Webhooks      []WebhookMarker       `json:"webhooks,omitempty"`
+	Registrations []WebhookRegistration `json:"registrations,omitempty"`
+}
+
+// CRDType is a root object type of a custom resource
+type CRDType struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	// Scope is Namespaced or Cluster
+	Scope          string   `json:"scope"`
+	ShortNames     []string `json:"short_names,omitempty"`
+	Subresources   []string `json:"subresources,omitempty"`
+	StorageVersion bool     `json:"storage_version,omitempty"`
+}
+
+// Reconciler is a type with a Reconcile method of controller-runtime
+type Reconciler struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// For, Owns and Watches are the object types of the controller builder
+	For     []string `json:"for,omitempty"`
+	Owns    []string `json:"owns,omitempty"`
+	Watches []string `json:"watches,omitempty"`
+	// RBAC are the arguments of the +kubebuilder:rbac markers of the file
+	RBAC []string `json:"rbac,omitempty"`
+}
+
+// WebhookMarker is a +kubebuilder:webhook marker
+type WebhookMarker struct {
+	Mutating  bool   `json:"mutating"`
+	Path      string `json:"path"`
+	Name      string `json:"name,omitempty"`
+	Groups    string `json:"groups,omitempty"`
+	Versions  string `json:"versions,omitempty"`
+	Resources string `json:"resources,omitempty"`
+	Verbs     string `json:"verbs,omitempty"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+}
+
+// WebhookRegistration is a webhook registered with NewWebhookManagedBy
+type WebhookRegistration struct {
+	For       string `json:"for"`
+	Defaulter string `json:"defaulter,omitempty"`
+	Validator string `json:"validator,omitempty"`
+	// Function is the function registering the webhook, e.g. CronJob.SetupWebhookWithManager
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// kubernetesVersion matches the API versions Kubernetes packages are named after
+var kubernetesVersion = regexp.MustCompile(`^v\d+((alpha|beta)\d+)?$`)
+
+// FindKubernetesInventory lists the CRD types, reconcilers and webhooks of the module
+// containing workspaceDir
+func FindKubernetesInventory(workspaceDir string) (*KubernetesInventory, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := loadModuleSources(workspaceDir, false)
+	if err != nil {
+		return nil, err
+	}
+	inventory := &KubernetesInventory{Module: module.path}
+	for _, pkg := range module.packages {
+		group, version := packageGroupVersion(pkg)
+		typeNames := make(map[string]bool)
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+					for _, spec := range genDecl.Specs {
+						typeNames[spec.(*ast.TypeSpec).Name.Name] = true
+					}
+				}
+			}
+		}
+
+		for _, file := range pkg.files {
+			rel := module.relPath(file.path)
+			var rbac []string
+			for _, comments := range file.ast.Comments {
+				for _, comment := range comments.List {
+					marker, ok := kubebuilderMarker(comment.Text)
+					if !ok {
+						continue
+					}
+					if args, ok := strings.CutPrefix(marker, "kubebuilder:rbac:"); ok {
+						rbac = append(rbac, args)
+					}
+					if args, ok := strings.CutPrefix(marker, "kubebuilder:webhook:"); ok {
+						values := markerArguments(args)
+						inventory.Webhooks = append(inventory.Webhooks, WebhookMarker{
+							Mutating:  values["mutating"] == "true",
+							Path:      values["path"],
+							Name:      values["name"],
+							Groups:    values["groups"],
+							Versions:  values["versions"],
+							Resources: values["resources"],
+							Verbs:     values["verbs"],
+							File:      rel,
+							Line:      file.fset.Position(comment.Pos()).Line,
+						})
+					}
+				}
+			}
+
+			for _, decl := range file.ast.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					if decl.Tok != token.TYPE {
+						continue
+					}
+					for _, spec := range decl.Specs {
+						spec := spec.(*ast.TypeSpec)
+						if _, ok := spec.Type.(*ast.StructType); !ok {
+							continue
+						}
+						markers := declarationMarkers(file, decl, spec)
+						name := spec.Name.Name
+						// The List types of kinds are root objects too
+						if !markers["kubebuilder:object:root"] && !markers["kubebuilder:object:root=true"] ||
+							strings.HasSuffix(name, "List") && typeNames[strings.TrimSuffix(name, "List")] {
+							continue
+						}
+						crd := CRDType{
+							Group:          group,
+							Version:        version,
+							Kind:           name,
+							File:           rel,
+							Line:           file.fset.Position(spec.Pos()).Line,
+							Scope:          "Namespaced",
+							StorageVersion: markers["kubebuilder:storageversion"],
+						}
+						for marker := range markers {
+							if args, ok := strings.CutPrefix(marker, "kubebuilder:resource:"); ok {
+								values := markerArguments(args)
+								if values["scope"] == "Cluster" {
+									crd.Scope = "Cluster"
+								}
+								if shortNames := values["shortName"]; shortNames != "" {
+									crd.ShortNames = strings.Split(strings.Trim(shortNames, "{}"), ";")
+								}
+							}
+							if subresource, ok := strings.CutPrefix(marker, "kubebuilder:subresource:"); ok {
+								subresource, _, _ = strings.Cut(subresource, ":")
+								crd.Subresources = append(crd.Subresources, subresource)
+							}
+						}
+						slices.Sort(crd.Subresources)
+						inventory.CRDs = append(inventory.CRDs, crd)
+					}
+				case *ast.FuncDecl:
+					if decl.Recv == nil || decl.Name.Name != "Reconcile" || decl.Type.Params.NumFields() != 2 {
+						continue
+					}
+					reconciler := Reconciler{
+						Name: receiverTypeName(decl.Recv.List[0].Type),
+						File: rel,
+						Line: file.fset.Position(decl.Pos()).Line,
+						RBAC: rbac,
+					}
+					reconciler.For, reconciler.Owns, reconciler.Watches = controllerObjects(pkg, reconciler.Name)
+					inventory.Reconcilers = append(inventory.Reconcilers, reconciler)
+				}
+			}
+			inventory.Registrations = append(inventory.Registrations, webhookRegistrations(module, file)...)
+		}
+	}
+	return inventory, nil
+}
+
+// kubebuilderMarker returns the marker of a comment line like // +kubebuilder:object:root=true
+func kubebuilderMarker(comment string) (string, bool) {
+	text := strings.TrimSpace(strings.TrimPrefix(comment, "//"))
+	marker, ok := strings.CutPrefix(text, "+")
+	return marker, ok && marker != ""
+}
+
+// declarationMarkers returns the markers of a type: those in its doc comment, and in the
+// comment group separated from it by one blank line as controller-tools reads them
+func declarationMarkers(file *sourceFile, decl *ast.GenDecl, spec *ast.TypeSpec) map[string]bool {
+	doc := spec.Doc
+	if doc == nil && len(decl.Specs) == 1 {
+		doc = decl.Doc
+	}
+	start := decl.Pos()
+	if doc != nil {
+		start = doc.Pos()
+	}
+	startLine := file.fset.Position(start).Line
+	markers := make(map[string]bool)
+	for _, group := range file.ast.Comments {
+		if group != doc && file.fset.Position(group.End()).Line != startLine-2 {
+			continue
+		}
+		for _, comment := range group.List {
+			if marker, ok := kubebuilderMarker(comment.Text); ok {
+				markers[marker] = true
+			}
+		}
+	}
+	return markers
+}
+
+// markerArguments parses the comma separated key=value arguments of a marker. Commas
+// within quotes belong to the value, and commas within braces, as in shortName={cj,cron},
+// separate list items which are returned separated by semicolons like verbs=get;list.
+func markerArguments(args string) map[string]string {
+	values := make(map[string]string)
+	depth, quoted, start := 0, false, 0
+	for i := 0; i <= len(args); i++ {
+		if i < len(args) {
+			switch args[i] {
+			case '"':
+				quoted = !quoted
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+			if args[i] != ',' || depth > 0 || quoted {
+				continue
+			}
+		}
+		key, value, _ := strings.Cut(args[start:i], "=")
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if strings.HasPrefix(value, "{") {
+			value = strings.ReplaceAll(value, ",", ";")
+		}
+		values[strings.TrimSpace(key)] = value
+		start = i + 1
+	}
+	return values
+}
+
+// packageGroupVersion returns the API group and version of a package from its
+// +groupName and +versionName markers or its schema.GroupVersion variable, with the
+// version defaulting to package names like v1 or v1beta1
+func packageGroupVersion(pkg *packageSources) (string, string) {
+	var group, version string
+	for _, file := range pkg.files {
+		for _, comments := range file.ast.Comments {
+			for _, comment := range comments.List {
+				marker, _ := kubebuilderMarker(comment.Text)
+				if value, ok := strings.CutPrefix(marker, "groupName="); ok {
+					group = value
+				}
+				if value, ok := strings.CutPrefix(marker, "versionName="); ok {
+					version = value
+				}
+			}
+		}
+		ast.Inspect(file.ast, func(node ast.Node) bool {
+			lit, ok := node.(*ast.CompositeLit)
+			if !ok || lit.Type == nil || !strings.HasSuffix(types.ExprString(lit.Type), "GroupVersion") {
+				return true
+			}
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, _ := kv.Key.(*ast.Ident)
+				value, ok := kv.Value.(*ast.BasicLit)
+				if key == nil || !ok || value.Kind != token.STRING {
+					continue
+				}
+				unquoted, _ := strconv.Unquote(value.Value)
+				if key.Name == "Group" && group == "" {
+					group = unquoted
+				}
+				if key.Name == "Version" && version == "" {
+					version = unquoted
+				}
+			}
+			return false
+		})
+	}
+	if version == "" && kubernetesVersion.MatchString(pkg.name) {
+		version = pkg.name
+	}
+	return group, version
+}
+
+// controllerObjects returns the object types passed to For, Owns and Watches of the
+// controller builders in the SetupWithManager method of a reconciler
+func controllerObjects(pkg *packageSources, reconciler string) (forTypes, owns, watches []string) {
+	for _, file := range pkg.files {
+		for _, decl := range file.ast.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || funcDecl.Body == nil || funcDecl.Name.Name != "SetupWithManager" ||
+				receiverTypeName(funcDecl.Recv.List[0].Type) != reconciler {
+				continue
+			}
+			ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+				selector, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || !builderChain(selector.X, "NewControllerManagedBy") {
+					return true
+				}
+				switch selector.Sel.Name {
+				case "For":
+					forTypes = append(forTypes, objectType(call.Args[0]))
+				case "Owns":
+					owns = append(owns, objectType(call.Args[0]))
+				case "Watches":
+					watches = append(watches, objectType(call.Args[0]))
+				}
+				return true
+			})
+		}
+	}
+	return forTypes, owns, watches
+}
+
+// webhookRegistrations returns the webhooks a file registers with NewWebhookManagedBy
+func webhookRegistrations(module *moduleSources, file *sourceFile) []WebhookRegistration {
+	var registrations []WebhookRegistration
+	for _, decl := range file.ast.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		// The receiver is registered by For(r) in methods of the API type
+		receiver, receiverType := "", ""
+		if funcDecl.Recv != nil && len(funcDecl.Recv.List[0].Names) > 0 {
+			receiver = funcDecl.Recv.List[0].Names[0].Name
+			receiverType = receiverTypeName(funcDecl.Recv.List[0].Type)
+		}
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || selector.Sel.Name != "Complete" || !builderChain(selector.X, "NewWebhookManagedBy") {
+				return true
+			}
+			registration := WebhookRegistration{
+				Function: funcDeclName(funcDecl),
+				File:     module.relPath(file.path),
+				Line:     file.fset.Position(call.Pos()).Line,
+			}
+			for expr := selector.X; ; {
+				call, ok := expr.(*ast.CallExpr)
+				if !ok {
+					break
+				}
+				selector, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					break
+				}
+				if len(call.Args) == 1 {
+					object := objectType(call.Args[0])
+					if ident, ok := call.Args[0].(*ast.Ident); ok && ident.Name == receiver {
+						object = receiverType
+					}
+					switch selector.Sel.Name {
+					case "For":
+						registration.For = object
+					case "WithDefaulter":
+						registration.Defaulter = object
+					case "WithValidator":
+						registration.Validator = object
+					}
+				}
+				expr = selector.X
+			}
+			registrations = append(registrations, registration)
+			return false
+		})
+	}
+	return registrations
+}
+
+// builderChain reports whether a chain of method calls starts with a call of the named
+// function, e.g. ctrl.NewControllerManagedBy(mgr).For(&v1.CronJob{})
+func builderChain(expr ast.Expr, name string) bool {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if fun.Sel.Name == name {
+				return true
+			}
+			expr = fun.X
+		case *ast.Ident:
+			return fun.Name == name
+		default:
+			return false
+		}
+	}
+}
+
+// objectType returns the type of an object argument like &batchv1.CronJob{}, or the
+// argument as written
+func objectType(expr ast.Expr) string {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	if lit, ok := expr.(*ast.CompositeLit); ok && lit.Type != nil {
+		return types.ExprString(lit.Type)
+	}
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		// new(batchv1.CronJob)
+		if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "new" {
+			return types.ExprString(call.Args[0])
+		}
+	}
+	return types.ExprString(expr)
+}
+
+func (inventory *KubernetesInventory) String() string {
+	if len(inventory.CRDs) == 0 && len(inventory.Reconcilers) == 0 && len(inventory.Webhooks) == 0 &&
+		len(inventory.Registrations) == 0 {
+		return fmt.Sprintf("No CRD types, reconcilers or webhooks found in %s", inventory.Module)
+	}
+	var sections []string
+	if len(inventory.CRDs) > 0 {
+		var b strings.Builder
+		b.WriteString("CRD types:\n")
+		for _, crd := range inventory.CRDs {
+			// The API version is written like apiVersion fields, without a group for the core group
+			apiVersion := crd.Version
+			if crd.Group != "" {
+				apiVersion = crd.Group + "/" + crd.Version
+			}
+			fmt.Fprintf(&b, "  %s %s (%s:%d), %s", apiVersion, crd.Kind, crd.File, crd.Line, strings.ToLower(crd.Scope))
+			if len(crd.ShortNames) > 0 {
+				fmt.Fprintf(&b, ", short names: %s", strings.Join(crd.ShortNames, ", "))
+			}
+			if len(crd.Subresources) > 0 {
+				fmt.Fprintf(&b, ", subresources: %s", strings.Join(crd.Subresources, ", "))
+			}
+			if crd.StorageVersion {
+				b.WriteString(", storage version")
+			}
+			b.WriteString("\n")
+		}
+		sections = append(sections, b.String())
+	}
+	if len(inventory.Reconcilers) > 0 {
+		var b strings.Builder
+		b.WriteString("Reconcilers:\n")
+		for _, reconciler := range inventory.Reconcilers {
+			fmt.Fprintf(&b, "  %s.Reconcile (%s:%d)\n", reconciler.Name, reconciler.File, reconciler.Line)
+			for _, objects := range []struct {
+				label string
+				types []string
+			}{{"for", reconciler.For}, {"owns", reconciler.Owns}, {"watches", reconciler.Watches}} {
+				if len(objects.types) > 0 {
+					fmt.Fprintf(&b, "    %s: %s\n", objects.label, strings.Join(objects.types, ", "))
+				}
+			}
+			for _, rbac := range reconciler.RBAC {
+				fmt.Fprintf(&b, "    rbac: %s\n", rbac)
+			}
+		}
+		sections = append(sections, b.String())
+	}
+	if len(inventory.Webhooks) > 0 || len(inventory.Registrations) > 0 {
+		var b strings.Builder
+		b.WriteString("Webhooks:\n")
+		for _, webhook := range inventory.Webhooks {
+			kind := "validating"
+			if webhook.Mutating {
+				kind = "mutating"
+			}
+			fmt.Fprintf(&b, "  %s %s", kind, webhook.Path)
+			if webhook.Name != "" {
+				fmt.Fprintf(&b, " (%s)", webhook.Name)
+			}
+			fmt.Fprintf(&b, " for %s/%s %s on %s (%s:%d)\n", webhook.Groups, webhook.Versions, webhook.Resources,
+				webhook.Verbs, webhook.File, webhook.Line)
+		}
+		for _, registration := range inventory.Registrations {
+			fmt.Fprintf(&b, "  registered for %s in %s (%s:%d)", registration.For, registration.Function,
+				registration.File, registration.Line)
+			if registration.Defaulter != "" {
+				fmt.Fprintf(&b, ", defaulter %s", registration.Defaulter)
+			}
+			if registration.Validator != "" {
+				fmt.Fprintf(&b, ", validator %s", registration.Validator)
+			}
+			b.WriteString("\n")
+		}
+		sections = append(sections, b.String())
+	}
+	return strings.Join(sections, "\n")
+}
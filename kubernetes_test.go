@@ -0,0 +1,167 @@
+package go_mcp_tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKubernetesInventory(t *testing.T) {
+	t.Parallel()
+
+	// Parsed without type checking, so the controller-runtime imports need not resolve
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/operator", "", "go 1.22", ""},
+		"api/v1/groupversion_info.go": {
+			"// +kubebuilder:object:generate=true", // 1
+			"// +groupName=batch.example.com",
+			"package v1", // 3
+			"",
+			"import \"k8s.io/apimachinery/pkg/runtime/schema\"", // 5
+			"",
+			"var GroupVersion = schema.GroupVersion{Group: \"ignored.example.com\", Version: \"v1\"}", // 7
+			"",
+		},
+		"api/v1/cronjob_types.go": {
+			"package v1", // 1
+			"",
+			"type CronJobSpec struct{ Schedule string }", // 3
+			"",
+			"// +kubebuilder:object:root=true", // 5
+			"// +kubebuilder:subresource:status",
+			"// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas",
+			"// +kubebuilder:resource:scope=Cluster,shortName={cj,cron}",
+			"",
+			"// CronJob is the Schema for the cronjobs API", // 10
+			"// +kubebuilder:storageversion",
+			"type CronJob struct {", // 12
+			"	Spec CronJobSpec",
+			"}",
+			"",
+			"// +kubebuilder:object:root=true", // 16
+			"",
+			"// CronJobList contains a list of CronJob",
+			"type CronJobList struct {", // 19
+			"	Items []CronJob",
+			"}",
+			"",
+		},
+		"api/v1/cronjob_webhook.go": {
+			"package v1", // 1
+			"",
+			"import ctrl \"sigs.k8s.io/controller-runtime\"", // 3
+			"",
+			"func (r *CronJob) SetupWebhookWithManager(mgr ctrl.Manager) error {", // 5
+			"	return ctrl.NewWebhookManagedBy(mgr).For(r).Complete()",             // 6
+			"}",
+			"",
+			"// +kubebuilder:webhook:path=/mutate-batch-example-com-v1-cronjob,mutating=true,failurePolicy=fail,groups=batch.example.com,resources=cronjobs,verbs=create;update,versions=v1,name=mcronjob.kb.io", // 9
+			"",
+			"func (r *CronJob) Default() {}", // 11
+			"",
+		},
+		"api/v2beta1/widget_types.go": {
+			"package v2beta1", // 1
+			"",
+			"// +kubebuilder:object:root=true", // 3
+			"type Widget struct{}",             // 4
+			"",
+		},
+		"internal/controller/cronjob_controller.go": {
+			"package controller", // 1
+			"",
+			"import (", // 3
+			"	\"context\"",
+			"",
+			"	batchv1 \"example.com/operator/api/v1\"",
+			"	kbatch \"k8s.io/api/batch/v1\"",
+			"	corev1 \"k8s.io/api/core/v1\"",
+			"	ctrl \"sigs.k8s.io/controller-runtime\"",
+			"	\"sigs.k8s.io/controller-runtime/pkg/handler\"",
+			")",
+			"",
+			"type CronJobReconciler struct{}", // 13
+			"",
+			"// +kubebuilder:rbac:groups=batch.example.com,resources=cronjobs,verbs=get;list;watch", // 15
+			"// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get",
+			"",
+			"func (r *CronJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {", // 18
+			"	return ctrl.Result{}, nil",
+			"}",
+			"",
+			"func (r *CronJobReconciler) SetupWithManager(mgr ctrl.Manager) error {", // 22
+			"	return ctrl.NewControllerManagedBy(mgr).",
+			"		For(&batchv1.CronJob{}).",
+			"		Owns(&kbatch.Job{}).",
+			"		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(nil)).",
+			"		Complete(r)",
+			"}",
+			"",
+			"func SetupWebhooks(mgr ctrl.Manager) error {",                                                       // 30
+			"	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).WithDefaulter(&podDefaulter{}).Complete()", // 31
+			"}",
+			"",
+			"type podDefaulter struct{}", // 34
+			"",
+		},
+	})
+
+	t.Run("inventory", func(t *testing.T) {
+		t.Parallel()
+		inventory, err := FindKubernetesInventory(filepath.Join(workspace, "internal"))
+		if err != nil {
+			t.Fatalf("Failed to list the inventory: %v", err)
+		}
+		// The group marker takes precedence over GroupVersion, and the version of the
+		// v2beta1 package comes from its name
+		expected := strings.Join([]string{
+			"CRD types:",
+			"  batch.example.com/v1 CronJob (api/v1/cronjob_types.go:12), cluster, short names: cj, cron, subresources: scale, status, storage version",
+			"  v2beta1 Widget (api/v2beta1/widget_types.go:4), namespaced",
+			"",
+			"Reconcilers:",
+			"  CronJobReconciler.Reconcile (internal/controller/cronjob_controller.go:18)",
+			"    for: batchv1.CronJob",
+			"    owns: kbatch.Job",
+			"    watches: corev1.ConfigMap",
+			"    rbac: groups=batch.example.com,resources=cronjobs,verbs=get;list;watch",
+			"    rbac: groups=batch,resources=jobs,verbs=get",
+			"",
+			"Webhooks:",
+			"  mutating /mutate-batch-example-com-v1-cronjob (mcronjob.kb.io) for batch.example.com/v1 cronjobs on create;update (api/v1/cronjob_webhook.go:9)",
+			"  registered for CronJob in CronJob.SetupWebhookWithManager (api/v1/cronjob_webhook.go:6)",
+			"  registered for corev1.Pod in SetupWebhooks (internal/controller/cronjob_controller.go:31), defaulter podDefaulter",
+			"",
+		}, "\n")
+		if inventory.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, inventory)
+		}
+	})
+
+	t.Run("marker arguments", func(t *testing.T) {
+		t.Parallel()
+		values := markerArguments(`scope=Cluster,shortName={cj,cron},name="a,b"`)
+		if values["scope"] != "Cluster" || values["shortName"] != "{cj;cron}" || values["name"] != "a,b" {
+			t.Errorf("Unexpected arguments: %v", values)
+		}
+	})
+
+	t.Run("no kubernetes code", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/plain\n\ngo 1.22\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "plain.go"), []byte("package plain\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		inventory, err := FindKubernetesInventory(dir)
+		if err != nil {
+			t.Fatalf("Failed to list the inventory: %v", err)
+		}
+		if inventory.String() != "No CRD types, reconcilers or webhooks found in example.com/plain" {
+			t.Errorf("Unexpected inventory:\n%s", inventory)
+		}
+	})
+}
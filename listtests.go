@@ -0,0 +1,277 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	listTestsToolName        = "list_tests"
+	listTestsToolDescription = `Lists the tests, benchmarks, fuzz targets and examples of a Go module per package, with their file and line, as go test finds them. Subtests started with t.Run or b.Run are listed below their test under the name go test gives them, e.g. TestParse/empty_input for t.Run("empty input", ...), so a single subtest can be run with go test -run. Subtests named by an expression, as in table-driven tests, are listed with the expression.
+
+Pass prefix to only list the packages whose import path starts with it.`
+)
+
+// goTestKinds are the prefixes of the functions go test runs, with their kind and the
+// number of parameters they take
+var goTestKinds = []struct {
+	prefix string
+	kind   string
+	params int
+}{
+	{"Test", "test", 1},
+	{"Benchmark", "benchmark", 1},
+	{"Fuzz", "fuzz", 1},
+	{"Example", "example", 0},
+}
+
+func AddListTestsTool(mcpServer *server.MCPServer) {
+	handleListTests := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		prefix := request.GetString("prefix", "")
+
+		listing, err := ListTests(workspaceDir, prefix)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error listing tests: %v", err)), nil
+		}
+		return mcp.NewToolResultText(listing.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		listTestsToolName,
+		mcp.WithDescription(listTestsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+		mcp.WithString("prefix",
+			mcp.Description("Only list tests of packages with this import path prefix, e.g. example.com/app/internal"),
+		),
+	), handleListTests)
+}
+
+// TestListing lists the test functions of the packages of a module
+type TestListing struct {
+	Module string `json:"module"`
+	// Prefix is the import path prefix the packages were limited to
+	Prefix   string        `json:"prefix,omitempty"`
+	Packages []TestPackage `json:"packages,omitempty"`
+}
+
+// TestPackage holds the test functions of a package
+type TestPackage struct {
+	ImportPath string         `json:"import_path"`
+	Tests      []TestFunction `json:"tests"`
+}
+
+// TestFunction is a test, benchmark, fuzz target or example
+type TestFunction struct {
+	Name string `json:"name"`
+	// Kind is one of test, benchmark, fuzz and example
+	Kind     string    `json:"kind"`
+	File     string    `json:"file"`
+	Line     int       `json:"line"`
+	Subtests []Subtest `json:"subtests,omitempty"`
+}
+
+// Subtest is a subtest or sub-benchmark started with Run
+type Subtest struct {
+	// Name is the full name as go test reports it, e.g. TestParse/empty_input
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	// Dynamic is set when the name is an expression, which is part of Name as written
+	Dynamic bool `json:"dynamic,omitempty"`
+}
+
+// ListTests lists the test functions of the packages of the module containing workspaceDir
+// whose import path starts with prefix, or of all packages when prefix is empty
+func ListTests(workspaceDir string, prefix string) (*TestListing, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := loadModuleSources(workspaceDir, true)
+	if err != nil {
+		return nil, err
+	}
+	listing := &TestListing{Module: module.path, Prefix: prefix}
+	for _, pkg := range module.packages {
+		if prefix != "" && pkg.importPath != prefix && !strings.HasPrefix(pkg.importPath, prefix+"/") {
+			continue
+		}
+		var tests []TestFunction
+		for _, file := range pkg.files {
+			if !file.test {
+				continue
+			}
+			for _, decl := range file.ast.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				kind := goTestKind(funcDecl)
+				if kind == "" {
+					continue
+				}
+				test := TestFunction{
+					Name: funcDecl.Name.Name,
+					Kind: kind,
+					File: module.relPath(file.path),
+					Line: file.fset.Position(funcDecl.Pos()).Line,
+				}
+				if params := funcDecl.Type.Params.List; kind != "example" && len(params[0].Names) == 1 && funcDecl.Body != nil {
+					test.Subtests = findSubtests(file.fset, funcDecl.Body, params[0].Names[0].Name, test.Name)
+				}
+				tests = append(tests, test)
+			}
+		}
+		if len(tests) == 0 {
+			continue
+		}
+		slices.SortFunc(tests, func(a, b TestFunction) int {
+			if a.File != b.File {
+				return strings.Compare(a.File, b.File)
+			}
+			return a.Line - b.Line
+		})
+		listing.Packages = append(listing.Packages, TestPackage{ImportPath: pkg.importPath, Tests: tests})
+	}
+	return listing, nil
+}
+
+// goTestKind returns the kind of function go test runs a declaration as, or an empty
+// string for other functions such as TestMain and helpers
+func goTestKind(decl *ast.FuncDecl) string {
+	if decl.Recv != nil || decl.Type.TypeParams != nil || decl.Name.Name == "TestMain" {
+		return ""
+	}
+	for _, kind := range goTestKinds {
+		if decl.Type.Params.NumFields() == kind.params && isGoTestName(decl.Name.Name, kind.prefix) {
+			return kind.kind
+		}
+	}
+	return ""
+}
+
+// isGoTestName reports whether name has the prefix followed by nothing or a character
+// other than a lower case letter, as go test requires: TestFoo is a test, Testfoo is not
+func isGoTestName(name string, prefix string) bool {
+	suffix, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(suffix)
+	return suffix == "" || !unicode.IsLower(first)
+}
+
+// findSubtests returns the subtests started by calling Run on the named *testing.T or
+// *testing.B in body, and those of their subtests
+func findSubtests(fset *token.FileSet, body *ast.BlockStmt, param string, parent string) []Subtest {
+	var subtests []Subtest
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "Run" {
+			return true
+		}
+		if receiver, ok := selector.X.(*ast.Ident); !ok || receiver.Name != param {
+			return true
+		}
+		subtest := Subtest{Line: fset.Position(call.Pos()).Line}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			name, _ := strconv.Unquote(lit.Value)
+			subtest.Name = parent + "/" + subtestName(name)
+		} else {
+			subtest.Name = parent + "/" + types.ExprString(call.Args[0])
+			subtest.Dynamic = true
+		}
+		subtests = append(subtests, subtest)
+		if fn, ok := call.Args[1].(*ast.FuncLit); ok {
+			if params := fn.Type.Params.List; len(params) == 1 && len(params[0].Names) == 1 {
+				subtests = append(subtests, findSubtests(fset, fn.Body, params[0].Names[0].Name, subtest.Name)...)
+			}
+		}
+		return false
+	})
+	return subtests
+}
+
+// subtestName rewrites a subtest name as go test does, replacing spaces by underscores
+// and quoting unprintable characters
+func subtestName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteByte('_')
+		case !strconv.IsPrint(r):
+			quoted := strconv.QuoteRune(r)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (listing *TestListing) String() string {
+	scope := listing.Module
+	if listing.Prefix != "" {
+		scope = listing.Prefix
+	}
+	if len(listing.Packages) == 0 {
+		return fmt.Sprintf("No tests found in %s", scope)
+	}
+	counts := make(map[string]int)
+	for _, pkg := range listing.Packages {
+		for _, test := range pkg.Tests {
+			counts[test.Kind]++
+		}
+	}
+	var totals []string
+	for _, kind := range []struct{ kind, plural string }{
+		{"test", "tests"}, {"benchmark", "benchmarks"}, {"fuzz", "fuzz targets"}, {"example", "examples"},
+	} {
+		if counts[kind.kind] > 0 {
+			totals = append(totals, fmt.Sprintf("%d %s", counts[kind.kind], kind.plural))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s in %s:\n", strings.Join(totals, ", "), scope)
+	for _, pkg := range listing.Packages {
+		fmt.Fprintf(&b, "\n%s:\n", pkg.ImportPath)
+		for _, test := range pkg.Tests {
+			fmt.Fprintf(&b, "  %s (%s:%d)\n", test.Name, test.File, test.Line)
+			for _, subtest := range test.Subtests {
+				fmt.Fprintf(&b, "    %s (line %d", subtest.Name, subtest.Line)
+				if subtest.Dynamic {
+					b.WriteString(", dynamic name")
+				}
+				b.WriteString(")\n")
+			}
+		}
+	}
+	return b.String()
+}
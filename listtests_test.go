@@ -0,0 +1,119 @@
+package go_mcp_tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListTests(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod":     {"module example.com/calc", "", "go 1.22", ""},
+		"calc.go":    {"package calc", "", "func Add(a, b int) int { return a + b }", ""},
+		"parse/p.go": {"package parse", ""},
+		"other/o.go": {"package other", ""},
+		"calc_test.go": {
+			"package calc", // 1
+			"",
+			"import \"testing\"", // 3
+			"",
+			"func TestAdd(t *testing.T) {", // 5
+			"	t.Run(\"small numbers\", func(t *testing.T) {", // 6
+			"		t.Run(\"zero\", func(t *testing.T) {})",       // 7
+			"	})",
+			"	for _, tt := range []struct{ name string }{{\"a\"}} {", // 9
+			"		t.Run(tt.name, func(st *testing.T) {",                 // 10
+			"			st.Run(\"inner\", func(*testing.T) {})",              // 11
+			"		})",
+			"	}",
+			"}",
+			"",
+			"func BenchmarkAdd(b *testing.B) {", // 16
+			"	b.Run(\"tab\\tname\", func(b *testing.B) {})", // 17
+			"}",
+			"",
+			"func FuzzAdd(f *testing.F) {}", // 20
+			"",
+			"func Testhelper(t *testing.T) {}", // 22
+			"",
+			"func TestMain(m *testing.M) {}", // 24
+			"",
+			"func helper(t *testing.T) { t.Run(\"ignored\", nil) }", // 26
+			"",
+		},
+		"example_test.go": {
+			"package calc_test", // 1
+			"",
+			"func ExampleAdd() {}", // 3
+			"",
+			"func Example() {}", // 5
+			"",
+		},
+		"parse/p_test.go": {
+			"package parse", // 1
+			"",
+			"import \"testing\"", // 3
+			"",
+			"func Test(t *testing.T) {}", // 5
+			"",
+		},
+	})
+
+	t.Run("module", func(t *testing.T) {
+		t.Parallel()
+		listing, err := ListTests(filepath.Join(workspace, "other"), "")
+		if err != nil {
+			t.Fatalf("Failed to list tests: %v", err)
+		}
+		expected := strings.Join([]string{
+			"2 tests, 1 benchmarks, 1 fuzz targets, 2 examples in example.com/calc:",
+			"",
+			"example.com/calc:",
+			"  TestAdd (calc_test.go:5)",
+			"    TestAdd/small_numbers (line 6)",
+			"    TestAdd/small_numbers/zero (line 7)",
+			"    TestAdd/tt.name (line 10, dynamic name)",
+			"    TestAdd/tt.name/inner (line 11)",
+			"  BenchmarkAdd (calc_test.go:16)",
+			"    BenchmarkAdd/tab_name (line 17)",
+			"  FuzzAdd (calc_test.go:20)",
+			"  ExampleAdd (example_test.go:3)",
+			"  Example (example_test.go:5)",
+			"",
+			"example.com/calc/parse:",
+			"  Test (parse/p_test.go:5)",
+			"",
+		}, "\n")
+		if listing.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, listing)
+		}
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		t.Parallel()
+		listing, err := ListTests(workspace, "example.com/calc/parse")
+		if err != nil {
+			t.Fatalf("Failed to list tests: %v", err)
+		}
+		if len(listing.Packages) != 1 || listing.Packages[0].Tests[0].Name != "Test" {
+			t.Errorf("Expected only the parse package, got:\n%s", listing)
+		}
+
+		listing, err = ListTests(workspace, "example.com/calc/other")
+		if err != nil {
+			t.Fatalf("Failed to list tests: %v", err)
+		}
+		if listing.String() != "No tests found in example.com/calc/other" {
+			t.Errorf("Unexpected listing:\n%s", listing)
+		}
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ListTests("calc", ""); err == nil {
+			t.Error("Expected an error for a relative path")
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	metricsToolName        = "metrics"
+	metricsToolDescription = `Reports code metrics of the functions of a Go module: cyclomatic complexity, lines and parameter counts per function, and per package totals of files, functions, lines and complexity. The functions are listed from the most complex down, filtered by min_complexity and min_lines, so the worst offenders can be targeted for refactoring.
+
+Cyclomatic complexity is 1 plus the number of if, for and range statements, non-default case and select clauses, and && and || operators of the function, including its function literals.`
+)
+
+// MetricsOptions configures the metrics report
+type MetricsOptions struct {
+	// MinComplexity only lists functions with at least this cyclomatic complexity
+	MinComplexity int
+	// MinLines only lists functions with at least this many lines
+	MinLines int
+	// Limit is the number of functions listed, all when zero
+	Limit int
+	// IncludeTests includes the functions of test files
+	IncludeTests bool
+}
+
+// FunctionMetrics are the metrics of a function or method
+type FunctionMetrics struct {
+	// Name is the function name, Receiver.Name for methods
+	Name       string `json:"name"`
+	ImportPath string `json:"import_path"`
+	FilePath   string `json:"file_path"`
+	Line       int    `json:"line"`
+	Complexity int    `json:"complexity"`
+	Lines      int    `json:"lines"`
+	Parameters int    `json:"parameters"`
+}
+
+// PackageMetrics are the totals of the functions of a package
+type PackageMetrics struct {
+	ImportPath string `json:"import_path"`
+	Files      int    `json:"files"`
+	Functions  int    `json:"functions"`
+	// Lines are the lines of the files of the package
+	Lines           int `json:"lines"`
+	TotalComplexity int `json:"total_complexity"`
+	MaxComplexity   int `json:"max_complexity"`
+}
+
+// MetricsReport holds the metrics of a module
+type MetricsReport struct {
+	Module   string           `json:"module"`
+	Packages []PackageMetrics `json:"packages"`
+	// Functions are the functions passing the thresholds, the most complex first
+	Functions []FunctionMetrics `json:"functions"`
+	// Matched is the number of functions passing the thresholds before the limit
+	Matched int            `json:"matched"`
+	Options MetricsOptions `json:"-"`
+}
+
+func AddMetricsTool(mcpServer *server.MCPServer) {
+	handleMetrics := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		options := MetricsOptions{Limit: 20}
+		options.MinComplexity = request.GetInt("min_complexity", options.MinComplexity)
+		options.MinLines = request.GetInt("min_lines", options.MinLines)
+		options.Limit = request.GetInt("limit", options.Limit)
+		options.IncludeTests = request.GetBool("include_tests", false)
+
+		report, err := Metrics(workspaceDir, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error computing metrics: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		metricsToolName,
+		mcp.WithDescription(metricsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory inside the Go module to report on"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("min_complexity",
+			mcp.Description("Only list functions with at least this cyclomatic complexity"),
+		),
+		mcp.WithNumber("min_lines",
+			mcp.Description("Only list functions with at least this many lines"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Number of functions to list, 0 for all"),
+			mcp.DefaultNumber(20),
+		),
+		mcp.WithBoolean("include_tests",
+			mcp.Description("Whether to include the functions of test files"),
+			mcp.DefaultBool(false),
+		),
+	), handleMetrics)
+}
+
+// Metrics computes the metrics of the functions and packages of the module containing
+// workspaceDir. Package totals include all functions, the thresholds only filter the list.
+func Metrics(workspaceDir string, options MetricsOptions) (*MetricsReport, error) {
+	module, err := loadModuleSources(workspaceDir, options.IncludeTests)
+	if err != nil {
+		return nil, err
+	}
+	report := &MetricsReport{Module: module.path, Options: options}
+	var functions []FunctionMetrics
+	for _, pkg := range module.packages {
+		totals := PackageMetrics{ImportPath: pkg.importPath, Files: len(pkg.files)}
+		for _, file := range pkg.files {
+			totals.Lines += file.fset.File(file.ast.Pos()).LineCount()
+			for _, decl := range file.ast.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil {
+					continue
+				}
+				start := file.fset.Position(fn.Pos())
+				metrics := FunctionMetrics{
+					Name:       fn.Name.Name,
+					ImportPath: pkg.importPath,
+					FilePath:   module.relPath(file.path),
+					Line:       start.Line,
+					Complexity: cyclomaticComplexity(fn.Body),
+					Lines:      file.fset.Position(fn.End()).Line - start.Line + 1,
+					Parameters: fn.Type.Params.NumFields(),
+				}
+				if fn.Recv != nil && len(fn.Recv.List) > 0 {
+					metrics.Name = receiverTypeName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+				}
+				totals.Functions++
+				totals.TotalComplexity += metrics.Complexity
+				totals.MaxComplexity = max(totals.MaxComplexity, metrics.Complexity)
+				if metrics.Complexity >= options.MinComplexity && metrics.Lines >= options.MinLines {
+					functions = append(functions, metrics)
+				}
+			}
+		}
+		report.Packages = append(report.Packages, totals)
+	}
+
+	slices.SortStableFunc(functions, func(a, b FunctionMetrics) int {
+		if a.Complexity != b.Complexity {
+			return b.Complexity - a.Complexity
+		}
+		if a.Lines != b.Lines {
+			return b.Lines - a.Lines
+		}
+		return strings.Compare(a.ImportPath+"."+a.Name, b.ImportPath+"."+b.Name)
+	})
+	report.Matched = len(functions)
+	if options.Limit > 0 && len(functions) > options.Limit {
+		functions = functions[:options.Limit]
+	}
+	report.Functions = functions
+	return report, nil
+}
+
+// cyclomaticComplexity returns 1 plus the number of decision points of a function body
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if node.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+func (report *MetricsReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Packages of %s:\n", report.Module)
+	for _, pkg := range report.Packages {
+		average := 0.0
+		if pkg.Functions > 0 {
+			average = float64(pkg.TotalComplexity) / float64(pkg.Functions)
+		}
+		fmt.Fprintf(
+			&b,
+			"  %s - %d files, %d lines, %d functions, complexity %d total, %.1f average, %d max\n",
+			pkg.ImportPath,
+			pkg.Files,
+			pkg.Lines,
+			pkg.Functions,
+			pkg.TotalComplexity,
+			average,
+			pkg.MaxComplexity,
+		)
+	}
+
+	var filters []string
+	if report.Options.MinComplexity > 0 {
+		filters = append(filters, fmt.Sprintf("complexity >= %d", report.Options.MinComplexity))
+	}
+	if report.Options.MinLines > 0 {
+		filters = append(filters, fmt.Sprintf("lines >= %d", report.Options.MinLines))
+	}
+	b.WriteString("\n")
+	if len(report.Functions) == 0 {
+		b.WriteString("No functions")
+		if len(filters) > 0 {
+			fmt.Fprintf(&b, " with %s", strings.Join(filters, " and "))
+		}
+		b.WriteString("\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Functions by complexity (%d of %d", len(report.Functions), report.Matched)
+	if len(filters) > 0 {
+		fmt.Fprintf(&b, " with %s", strings.Join(filters, " and "))
+	}
+	b.WriteString("):\n")
+	for i, fn := range report.Functions {
+		fmt.Fprintf(
+			&b,
+			"%d. %s.%s (%s:%d) - complexity %d, %d lines, %d parameters\n",
+			i+1,
+			fn.ImportPath,
+			fn.Name,
+			fn.FilePath,
+			fn.Line,
+			fn.Complexity,
+			fn.Lines,
+			fn.Parameters,
+		)
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with functions of varying complexity
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"calc/calc.go": {
+				"package calc",                     // 1
+				"",                                 // 2
+				"type Calc struct{}",               // 3
+				"",                                 // 4
+				"func (c *Calc) Sign(n int) int {", // 5
+				"\tif n > 0 && n < 100 {",          // 6
+				"\t\treturn 1",                     // 7
+				"\t}",                              // 8
+				"\tswitch {",                       // 9
+				"\tcase n < 0:",                    // 10
+				"\t\treturn -1",                    // 11
+				"\tdefault:",                       // 12
+				"\t\treturn 0",                     // 13
+				"\t}",                              // 14
+				"}",                                // 15
+				"",                                 // 16
+				"func Sum(values []int, offset int) int {", // 17
+				"\ttotal := offset",                        // 18
+				"\tfor _, value := range values {",         // 19
+				"\t\ttotal += value",                       // 20
+				"\t}",                                      // 21
+				"\treturn total",                           // 22
+				"}",                                        // 23
+				"",                                         // 24
+				"func Zero() int { return 0 }",             // 25
+				"",                                         // 26
+			},
+			"calc/calc_test.go": {
+				"package calc",                  // 1
+				"",                              // 2
+				"import \"testing\"",            // 3
+				"",                              // 4
+				"func TestZero(t *testing.T) {", // 5
+				"\tif Zero() != 0 {",            // 6
+				"\t\tt.Fail()",                  // 7
+				"\t}",                           // 8
+				"}",                             // 9
+				"",                              // 10
+			},
+		})
+	}
+
+	t.Run("function and package metrics", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := Metrics(dir, MetricsOptions{})
+		if err != nil {
+			t.Fatalf("Failed to compute metrics: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Packages of example.com/app:",
+			"  example.com/app/calc - 1 files, 25 lines, 3 functions, complexity 7 total, 2.3 average, 4 max",
+			"",
+			"Functions by complexity (3 of 3):",
+			"1. example.com/app/calc.Calc.Sign (calc/calc.go:5) - complexity 4, 11 lines, 1 parameters",
+			"2. example.com/app/calc.Sum (calc/calc.go:17) - complexity 2, 7 lines, 2 parameters",
+			"3. example.com/app/calc.Zero (calc/calc.go:25) - complexity 1, 1 lines, 0 parameters",
+			"",
+		}, "\n")
+		if got := report.String(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("thresholds and tests", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := Metrics(dir, MetricsOptions{MinComplexity: 2, MinLines: 5, IncludeTests: true})
+		if err != nil {
+			t.Fatalf("Failed to compute metrics: %v", err)
+		}
+		var names []string
+		for _, fn := range report.Functions {
+			names = append(names, fn.Name)
+		}
+		if strings.Join(names, ",") != "Calc.Sign,Sum,TestZero" {
+			t.Errorf("Expected the functions passing the thresholds, got %v", names)
+		}
+		if report.Packages[0].Functions != 4 || report.Packages[0].Files != 2 {
+			t.Errorf("Expected the totals to include all functions of the test file too, got %+v", report.Packages[0])
+		}
+	})
+
+	t.Run("metrics tool", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      metricsToolName,
+				"arguments": map[string]any{"workspace_dir": dir, "min_complexity": 3},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "Functions by complexity (1 of 1 with complexity >= 3):") {
+			t.Errorf("Expected the functions with complexity of at least 3, got: %s", text)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	modTidyToolName        = "mod_tidy"
+	modTidyToolDescription = `Runs go mod tidy in the Go module containing a directory and reports the requirements it added, removed or changed in go.mod and whether go.sum changed. Use it after adding or removing imports to fix builds failing on missing or unused requirements.
+
+Errors of go mod tidy, e.g. imports no module provides, are returned with its output. Pass output=patch to preview the changes of go.mod and go.sum as a unified diff without writing them.`
+)
+
+func AddModTidyTool(mcpServer *server.MCPServer) {
+	handleModTidy := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := ModTidy(ctx, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error tidying module: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		modTidyToolName,
+		mcp.WithDescription(modTidyToolDescription),
+		editingToolAnnotation(true, true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to tidy"),
+			mcp.Required(),
+		),
+	), handleModTidy)
+}
+
+// ModTidyResult describes the changes go mod tidy made to a module
+type ModTidyResult struct {
+	ModuleRoot string `json:"module_root"`
+	// Added and Removed list the requirements as "path version"
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Changed lists the requirements whose version or indirect marking changed
+	Changed    []string `json:"changed,omitempty"`
+	SumChanged bool     `json:"sum_changed"`
+}
+
+// ModTidy runs go mod tidy in the module containing workspaceDir and compares the
+// requirements of go.mod before and after
+func ModTidy(ctx context.Context, workspaceDir string) (*ModTidyResult, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	root, err := findModuleRoot(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	goModPath := filepath.Join(root, "go.mod")
+	goSumPath := filepath.Join(root, "go.sum")
+	goModBefore, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, err
+	}
+	// A missing go.sum reads as empty, like one go mod tidy has no sums to write to
+	goSumBefore, _ := os.ReadFile(goSumPath)
+	before, err := modfile.Parse(goModPath, goModBefore, nil)
+	if err != nil {
+		return nil, classifyErrorf(ErrSyntaxErrors, "failed to parse %s: %w", goModPath, err)
+	}
+
+	if _, err := runGo(ctx, root, "mod", "tidy"); err != nil {
+		return nil, err
+	}
+
+	goModAfter, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, err
+	}
+	goSumAfter, _ := os.ReadFile(goSumPath)
+	after, err := modfile.Parse(goModPath, goModAfter, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tidied %s: %w", goModPath, err)
+	}
+
+	result := &ModTidyResult{ModuleRoot: root, SumChanged: !bytes.Equal(goSumBefore, goSumAfter)}
+	requirements := make(map[string]*modfile.Require)
+	for _, require := range before.Require {
+		requirements[require.Mod.Path] = require
+	}
+	for _, require := range after.Require {
+		previous, ok := requirements[require.Mod.Path]
+		delete(requirements, require.Mod.Path)
+		switch {
+		case !ok:
+			result.Added = append(result.Added, formatRequirement(require))
+		case previous.Mod.Version != require.Mod.Version || previous.Indirect != require.Indirect:
+			result.Changed = append(result.Changed, formatRequirement(previous)+" → "+formatRequirement(require))
+		}
+	}
+	// Removed requirements are listed in the order of the original go.mod
+	for _, require := range before.Require {
+		if _, ok := requirements[require.Mod.Path]; ok {
+			result.Removed = append(result.Removed, formatRequirement(require))
+		}
+	}
+	return result, nil
+}
+
+// formatRequirement formats a requirement as path version, marking indirect ones
+func formatRequirement(require *modfile.Require) string {
+	text := require.Mod.Path + " " + require.Mod.Version
+	if require.Indirect {
+		text += " // indirect"
+	}
+	return text
+}
+
+// String formats the changes as human and model readable text
+func (result *ModTidyResult) String() string {
+	if len(result.Added) == 0 && len(result.Removed) == 0 && len(result.Changed) == 0 && !result.SumChanged {
+		return fmt.Sprintf("go.mod and go.sum of %s are already tidy", result.ModuleRoot)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tidied %s\n", filepath.Join(result.ModuleRoot, "go.mod"))
+	for _, section := range []struct {
+		title        string
+		requirements []string
+	}{
+		{"Added requirements", result.Added},
+		{"Removed requirements", result.Removed},
+		{"Changed requirements", result.Changed},
+	} {
+		if len(section.requirements) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		for _, requirement := range section.requirements {
+			fmt.Fprintf(&b, "  %s\n", requirement)
+		}
+	}
+	if result.SumChanged {
+		b.WriteString("\ngo.sum was updated\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestModTidy(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module importing a replaced library it does not require,
+	// and requiring an unused one. The replacements keep go mod tidy offline.
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {
+				"module example.com/app",
+				"",
+				"go 1.22",
+				"",
+				"require example.com/unused v1.0.0",
+				"",
+				"replace example.com/lib => ./lib",
+				"",
+				"replace example.com/unused => ./lib",
+				"",
+			},
+			"main.go": {
+				"package main",
+				"",
+				"import \"example.com/lib\"",
+				"",
+				"func main() { lib.Run() }",
+				"",
+			},
+			"lib/go.mod": {"module example.com/lib", "", "go 1.22", ""},
+			"lib/lib.go": {"package lib", "", "func Run() {}", ""},
+		})
+	}
+
+	readFile := func(t testing.TB, path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	t.Run("added and removed requirements", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		result, err := ModTidy(context.Background(), workspace)
+		if err != nil {
+			t.Fatalf("Failed to tidy: %v", err)
+		}
+		if strings.Join(result.Added, ",") != "example.com/lib v0.0.0-00010101000000-000000000000" {
+			t.Errorf("Expected the imported library to be added, got %v", result.Added)
+		}
+		if strings.Join(result.Removed, ",") != "example.com/unused v1.0.0" {
+			t.Errorf("Expected the unused requirement to be removed, got %v", result.Removed)
+		}
+		if content := readFile(t, filepath.Join(workspace, "go.mod")); strings.Contains(content, "require example.com/unused") {
+			t.Errorf("Expected go.mod to be tidied, got:\n%s", content)
+		}
+
+		result, err = ModTidy(context.Background(), filepath.Join(workspace, "lib"))
+		if err != nil || result.String() != "go.mod and go.sum of "+filepath.Join(workspace, "lib")+" are already tidy" {
+			t.Errorf("Expected the library module to be tidy, got %v (%v)", result, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		before := readFile(t, filepath.Join(workspace, "go.mod"))
+		if err := os.WriteFile(filepath.Join(workspace, "missing.go"), []byte("package main\n\nimport _ \"example.com/app/missing\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := ModTidy(context.Background(), workspace)
+		if err == nil || !strings.Contains(err.Error(), "go mod tidy failed") || !strings.Contains(err.Error(), "example.com/app/missing") {
+			t.Errorf("Expected the missing package in the error, got: %v", err)
+		}
+		if readFile(t, filepath.Join(workspace, "go.mod")) != before {
+			t.Errorf("Expected the failed tidy to leave go.mod untouched")
+		}
+	})
+
+	t.Run("mod tidy tool patch", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		before := readFile(t, filepath.Join(workspace, "go.mod"))
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      modTidyToolName,
+				"arguments": map[string]any{"workspace_dir": workspace, "output": "patch"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		for _, expected := range []string{"+++ b/go.mod", "-require example.com/unused v1.0.0", "+require example.com/lib v0.0.0-00010101000000-000000000000"} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the patch, got: %s", expected, text)
+			}
+		}
+		if readFile(t, filepath.Join(workspace, "go.mod")) != before {
+			t.Errorf("Expected the patch output to leave go.mod untouched")
+		}
+	})
+}
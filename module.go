@@ -0,0 +1,223 @@
+package go_mcp_tools
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// moduleSources are the parsed Go files of a module, grouped by package directory.
+// They are parsed without type checking, so workspace wide tools stay fast.
+type moduleSources struct {
+	root string
+	path string
+	// requires are the module paths required by go.mod
+	requires []string
+	// packages are sorted by directory
+	packages []*packageSources
+}
+
+// packageSources are the parsed Go files of a package directory
+type packageSources struct {
+	dir        string
+	importPath string
+	// name is the package name of the non-test files
+	name string
+	// files are sorted by path
+	files []*sourceFile
+}
+
+// sourceFile is a parsed Go file of a module
+type sourceFile struct {
+	path string
+	ast  *ast.File
+	fset *token.FileSet
+	test bool
+}
+
+// findModuleRoot returns the directory of the go.mod file at or above dir
+func findModuleRoot(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for current := absDir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			return current, nil
+		}
+		if filepath.Dir(current) == current {
+			return "", fmt.Errorf("no go.mod found in %s or its parent directories", absDir)
+		}
+	}
+}
+
+// readModule returns the root, path and requirements of the module containing dir. A
+// GOPATH project without go.mod stands in for the module, with the import path of its
+// root as module path and no requirements.
+func readModule(dir string) (*moduleSources, error) {
+	root, err := findModuleRoot(dir)
+	if err != nil {
+		if project, ok := findGOPATHProject(dir); ok {
+			return &moduleSources{root: project.root, path: project.importPath}, nil
+		}
+		return nil, fmt.Errorf("%w, and it is not in the src directory of a GOPATH entry", err)
+	}
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	module := &moduleSources{root: root, path: modfile.ModulePath(goMod)}
+	if module.path == "" {
+		return nil, fmt.Errorf("no module path found in %s", filepath.Join(root, "go.mod"))
+	}
+	if file, err := modfile.ParseLax(filepath.Join(root, "go.mod"), goMod, nil); err == nil {
+		for _, require := range file.Require {
+			module.requires = append(module.requires, require.Mod.Path)
+		}
+	}
+	return module, nil
+}
+
+// loadModuleSources parses the Go files of the module containing workspaceDir. Hidden
+// directories, testdata, vendor and nested modules are skipped. Files with syntax errors
+// are included with their partial syntax tree.
+func loadModuleSources(workspaceDir string, includeTests bool) (*moduleSources, error) {
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	root := module.root
+
+	packages := make(map[string]*packageSources)
+	err = filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if filePath == root {
+				return nil
+			}
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(filePath, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(filePath, ".go") {
+			return nil
+		}
+		isTest := strings.HasSuffix(filePath, "_test.go")
+		if isTest && !includeTests {
+			return nil
+		}
+
+		cached, err := globalFileCache.GetOrParseFile(filePath, nil)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(filePath)
+		pkg, ok := packages[dir]
+		if !ok {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return err
+			}
+			pkg = &packageSources{dir: dir, importPath: module.path}
+			if rel != "." {
+				pkg.importPath = path.Join(module.path, filepath.ToSlash(rel))
+			}
+			packages[dir] = pkg
+		}
+		name := cached.ast.Name.Name
+		if pkg.name == "" && !strings.HasSuffix(name, "_test") {
+			pkg.name = name
+		}
+		pkg.files = append(pkg.files, &sourceFile{
+			path: filePath,
+			ast:  cached.ast,
+			fset: cached.fset,
+			test: isTest,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the module %s: %w", root, err)
+	}
+
+	for _, pkg := range packages {
+		module.packages = append(module.packages, pkg)
+	}
+	slices.SortFunc(module.packages, func(a, b *packageSources) int {
+		return strings.Compare(a.dir, b.dir)
+	})
+	return module, nil
+}
+
+// packageByImportPath returns the package of the module with the import path
+func (module *moduleSources) packageByImportPath(importPath string) *packageSources {
+	for _, pkg := range module.packages {
+		if pkg.importPath == importPath {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// fileImports maps the names under which the file refers to imported packages of the
+// module to their import paths. Blank and dot imports are skipped.
+func (module *moduleSources) fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, "\"`")
+		pkg := module.packageByImportPath(importPath)
+		if pkg == nil {
+			continue
+		}
+		name := pkg.name
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "" || name == "_" || name == "." {
+			continue
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+// requiredModule returns the required module providing the imported package, or an
+// empty string when go.mod does not require one
+func (module *moduleSources) requiredModule(importPath string) string {
+	var longest string
+	for _, require := range module.requires {
+		if (importPath == require || strings.HasPrefix(importPath, require+"/")) && len(require) > len(longest) {
+			longest = require
+		}
+	}
+	return longest
+}
+
+// relPath returns the path relative to the module root for compact output
+func (module *moduleSources) relPath(filePath string) string {
+	rel, err := filepath.Rel(module.root, filePath)
+	if err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	// Parsed files are positioned by their resolved path, which differs from the root of
+	// a module reached through a symlink
+	if rel, ok := workspaceRelPath(filePath, module.root); ok {
+		return rel
+	}
+	return filePath
+}
@@ -0,0 +1,690 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const (
+	moveSymbolToolName        = "move_symbol"
+	moveSymbolToolDescription = `Moves an exported top-level function or type from its Go package to another package of the module, updating the imports and references across the module. Types move together with their methods. The declaration is appended to the file of the same name in the destination directory, which becomes a new package when it has no Go files.
+
+Pass output=patch to preview the changes as a unified diff without writing them. Exported declarations of the source package used by the moved code are imported from it. Moves are refused when the moved code uses unexported declarations of its package, which would have to move first, or when they would create an import cycle.`
+)
+
+func AddMoveSymbolTool(mcpServer *server.MCPServer) {
+	handleMoveSymbol := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		symbolName, err := request.RequireString("symbol")
+		if err != nil {
+			return nil, err
+		}
+		destinationDir, err := request.RequireString("destination_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := MoveSymbol(filePath, symbolName, destinationDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error moving symbol: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		moveSymbolToolName,
+		mcp.WithDescription(moveSymbolToolDescription),
+		editingToolAnnotation(true, true),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the symbol"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Name of the exported function or type to move"),
+			mcp.Required(),
+		),
+		mcp.WithString("destination_dir",
+			mcp.Description("Absolute path of the destination package directory within the module"),
+			mcp.Required(),
+		),
+	), handleMoveSymbol)
+}
+
+// moveFile is a file changed by a move, parsed from its current content
+type moveFile struct {
+	path     string
+	original []byte
+	fset     *token.FileSet
+	ast      *ast.File
+	edits    []textEdit
+	// appended is source added to the end of the file
+	appended string
+	// addImports and dropImports map import paths to their explicit names. Dropped imports
+	// are only removed when the file does not use them anymore.
+	addImports  map[string]string
+	dropImports map[string]string
+}
+
+func parseMoveFile(filePath string) (*moveFile, error) {
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, original, parser.ParseComments)
+	if err != nil {
+		return nil, classifyErrorf(ErrSyntaxErrors, "failed to parse %s: %w", filePath, err)
+	}
+	return &moveFile{
+		path:        filePath,
+		original:    original,
+		fset:        fset,
+		ast:         file,
+		addImports:  make(map[string]string),
+		dropImports: make(map[string]string),
+	}, nil
+}
+
+func (file *moveFile) offset(pos token.Pos) int {
+	return file.fset.Position(pos).Offset
+}
+
+// importOf returns the path and explicit name of the import referred to by name
+func (file *moveFile) importOf(name string, packageNames map[string]string) (string, string, bool) {
+	for _, spec := range file.ast.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		explicit := ""
+		if spec.Name != nil {
+			explicit = spec.Name.Name
+		}
+		importName := explicit
+		if importName == "" {
+			importName = packageNames[importPath]
+			if importName == "" {
+				importName = path.Base(importPath)
+			}
+		}
+		if importName == name {
+			return importPath, explicit, true
+		}
+	}
+	return "", "", false
+}
+
+// qualifierOf returns the name the file refers to the imported package by, adding the
+// import when the file does not import it yet
+func (file *moveFile) qualifierOf(importPath string, name string) (string, error) {
+	for _, spec := range file.ast.Imports {
+		if existing, err := strconv.Unquote(spec.Path.Value); err == nil && existing == importPath {
+			if spec.Name == nil {
+				return name, nil
+			}
+			if spec.Name.Name != "_" && spec.Name.Name != "." {
+				return spec.Name.Name, nil
+			}
+		}
+	}
+	if _, ok := file.addImports[importPath]; !ok && fileUsesIdentifier(file.ast, name) {
+		return "", fmt.Errorf("the package name %s clashes with an identifier in %s", name, file.path)
+	}
+	file.addImports[importPath] = ""
+	return name, nil
+}
+
+// write applies the changes to the file and formats it
+func (file *moveFile) write() error {
+	src := applyTextEdits(file.original, file.edits)
+	if file.appended != "" {
+		src = append(bytes.TrimRight(src, "\n"), "\n\n"+file.appended+"\n"...)
+	}
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file.path, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse the moved code in %s: %w", file.path, err)
+	}
+	for importPath, name := range file.addImports {
+		astutil.AddNamedImport(fset, parsed, name, importPath)
+	}
+	for importPath, name := range file.dropImports {
+		if !astutil.UsesImport(parsed, importPath) {
+			astutil.DeleteNamedImport(fset, parsed, name, importPath)
+		}
+	}
+	var out bytes.Buffer
+	if err := format.Node(&out, fset, parsed); err != nil {
+		return fmt.Errorf("failed to format %s: %w", file.path, err)
+	}
+	mode := os.FileMode(0644)
+	if stat, err := os.Stat(file.path); err == nil {
+		mode = stat.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(file.path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file.path, out.Bytes(), mode); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", file.path, err)
+	}
+	return nil
+}
+
+// movedDecl is a declaration moved to the destination package with the file declaring it
+type movedDecl struct {
+	file *moveFile
+	decl ast.Decl
+}
+
+func (moved movedDecl) start() int {
+	switch decl := moved.decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Doc != nil {
+			return moved.file.offset(decl.Doc.Pos())
+		}
+	case *ast.GenDecl:
+		if decl.Doc != nil {
+			return moved.file.offset(decl.Doc.Pos())
+		}
+	}
+	return moved.file.offset(moved.decl.Pos())
+}
+
+func (moved movedDecl) end() int {
+	return moved.file.offset(moved.decl.End())
+}
+
+// MoveSymbol moves the exported top-level function or type symbolName declared in filePath,
+// together with the methods of a type, to the package in destinationDir and updates the
+// references of the module
+func MoveSymbol(filePath string, symbolName string, destinationDir string) (string, error) {
+	if !filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if !filepath.IsAbs(destinationDir) {
+		return "", fmt.Errorf("destination_dir must be an absolute path, got: %s", destinationDir)
+	}
+	if strings.HasSuffix(filePath, "_test.go") {
+		return "", fmt.Errorf("declarations of test files cannot be moved")
+	}
+	if !token.IsExported(symbolName) {
+		return "", fmt.Errorf("only exported declarations can be moved, as other packages cannot refer to %s", symbolName)
+	}
+	filePath = filepath.Clean(filePath)
+	srcDir := filepath.Dir(filePath)
+	destDir := filepath.Clean(destinationDir)
+
+	module, err := loadModuleSources(srcDir, true)
+	if err != nil {
+		return "", err
+	}
+	var src, dest *packageSources
+	for _, pkg := range module.packages {
+		switch pkg.dir {
+		case srcDir:
+			src = pkg
+		case destDir:
+			dest = pkg
+		}
+	}
+	if src == nil || src.name == "" {
+		return "", fmt.Errorf("no Go package found in %s", srcDir)
+	}
+	if destDir == srcDir {
+		return "", fmt.Errorf("%s is already declared in %s", symbolName, destDir)
+	}
+	if !isFileInWorkspace(destDir, module.root) {
+		return "", fmt.Errorf("destination_dir must be a directory within the module %s", module.root)
+	}
+
+	rel, err := filepath.Rel(module.root, destDir)
+	if err != nil {
+		return "", err
+	}
+	destImportPath := module.path
+	if rel != "." {
+		destImportPath = path.Join(module.path, filepath.ToSlash(rel))
+	}
+	destName := filepath.Base(destDir)
+	if dest != nil && dest.name != "" {
+		destName = dest.name
+	}
+	if destName == "main" || !token.IsIdentifier(destName) {
+		return "", fmt.Errorf("%s cannot be imported as a package, choose another destination", destDir)
+	}
+
+	packageNames := make(map[string]string)
+	for _, pkg := range module.packages {
+		packageNames[pkg.importPath] = pkg.name
+	}
+
+	files := make(map[string]*moveFile)
+	load := func(filePath string) (*moveFile, error) {
+		if file, ok := files[filePath]; ok {
+			return file, nil
+		}
+		file, err := parseMoveFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		files[filePath] = file
+		return file, nil
+	}
+
+	// Find the declaration, with the methods of a type, and the declarations it must not use
+	var moved []movedDecl
+	kind := ""
+	topLevel := make(map[string]bool)
+	topLevelNodes := make(map[any]bool)
+	for _, sourceFile := range src.files {
+		if sourceFile.test {
+			continue
+		}
+		file, err := load(sourceFile.path)
+		if err != nil {
+			return "", err
+		}
+		for _, decl := range file.ast.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv != nil {
+					continue
+				}
+				topLevel[decl.Name.Name] = true
+				topLevelNodes[decl] = true
+				if decl.Name.Name == symbolName && file.path == filePath {
+					moved = append(moved, movedDecl{file: file, decl: decl})
+					kind = "func"
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						topLevel[spec.Name.Name] = true
+						topLevelNodes[spec] = true
+						if spec.Name.Name == symbolName && file.path == filePath {
+							if len(decl.Specs) != 1 {
+								return "", fmt.Errorf("%s is declared in a group with other types, split the group first", symbolName)
+							}
+							moved = append(moved, movedDecl{file: file, decl: decl})
+							kind = "type"
+						}
+					case *ast.ValueSpec:
+						topLevelNodes[spec] = true
+						for _, name := range spec.Names {
+							topLevel[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(moved) == 0 {
+		return "", classifyErrorf(ErrSymbolNotFound, "no top-level function or type '%s' found in %s", symbolName, filePath)
+	}
+	if kind == "type" {
+		for _, sourceFile := range src.files {
+			for _, decl := range sourceFile.ast.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 || receiverTypeName(fn.Recv.List[0].Type) != symbolName {
+					continue
+				}
+				if sourceFile.test {
+					return "", fmt.Errorf("the test file %s declares methods of %s, which cannot move", sourceFile.path, symbolName)
+				}
+			}
+		}
+		for _, sourceFile := range src.files {
+			if sourceFile.test {
+				continue
+			}
+			file := files[sourceFile.path]
+			for _, decl := range file.ast.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if ok && fn.Recv != nil && len(fn.Recv.List) > 0 && receiverTypeName(fn.Recv.List[0].Type) == symbolName {
+					moved = append(moved, movedDecl{file: file, decl: fn})
+				}
+			}
+		}
+	}
+
+	// Check what the moved code uses, and strip qualifiers of the destination package
+	movedEdits := make(map[*moveFile][]textEdit)
+	neededImports := make(map[string]string)
+	for _, declaration := range moved {
+		file := declaration.file
+		skip := make(map[*ast.Ident]bool)
+		if fn, ok := declaration.decl.(*ast.FuncDecl); ok {
+			// Method names are not references
+			skip[fn.Name] = true
+		}
+		var usageErr error
+		ast.Inspect(declaration.decl, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.SelectorExpr:
+				skip[node.Sel] = true
+				ident, ok := node.X.(*ast.Ident)
+				if !ok || ident.Obj != nil {
+					return true
+				}
+				importPath, explicit, ok := file.importOf(ident.Name, packageNames)
+				if !ok {
+					return true
+				}
+				skip[ident] = true
+				if importPath == destImportPath {
+					movedEdits[file] = append(movedEdits[file], textEdit{
+						start: file.offset(ident.Pos()),
+						end:   file.offset(node.Sel.Pos()),
+					})
+				} else {
+					neededImports[importPath] = explicit
+				}
+			case *ast.KeyValueExpr:
+				// Keys of struct literals are field names
+				if ident, ok := node.Key.(*ast.Ident); ok {
+					skip[ident] = true
+				}
+			case *ast.Ident:
+				if skip[node] || node.Name == symbolName || !topLevel[node.Name] {
+					return true
+				}
+				if node.Obj != nil && !topLevelNodes[node.Obj.Decl] {
+					return true
+				}
+				if !node.IsExported() {
+					usageErr = fmt.Errorf(
+						"%s uses %s of package %s, move it first or make it a parameter",
+						symbolName,
+						node.Name,
+						src.importPath,
+					)
+					return false
+				}
+				// Exported declarations of the source package are imported by the destination
+				movedEdits[file] = append(movedEdits[file], textEdit{
+					start:       file.offset(node.Pos()),
+					end:         file.offset(node.Pos()),
+					replacement: src.name + ".",
+				})
+				neededImports[src.importPath] = ""
+			}
+			return usageErr == nil
+		})
+		if usageErr != nil {
+			return "", usageErr
+		}
+	}
+
+	// The source package cannot use the unexported fields and methods of a moved type anymore
+	unexported := make(map[string]bool)
+	for _, declaration := range moved {
+		switch decl := declaration.decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Name.IsExported() {
+				unexported[decl.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			if structType, ok := decl.Specs[0].(*ast.TypeSpec).Type.(*ast.StructType); ok {
+				for _, field := range structType.Fields.List {
+					for _, name := range field.Names {
+						if !name.IsExported() {
+							unexported[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Imports of the destination after the move, to check for import cycles
+	graph := make(map[string][]string)
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			if file.test {
+				continue
+			}
+			for _, spec := range file.ast.Imports {
+				if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+					graph[pkg.importPath] = append(graph[pkg.importPath], importPath)
+				}
+			}
+		}
+	}
+	for importPath := range neededImports {
+		if importsTransitively(graph, importPath, destImportPath) {
+			return "", fmt.Errorf("moving %s would create an import cycle, as %s imports %s", symbolName, importPath, destImportPath)
+		}
+		graph[destImportPath] = append(graph[destImportPath], importPath)
+	}
+
+	// Remove the moved code from its files
+	for _, declaration := range moved {
+		file := declaration.file
+		end := declaration.end()
+		for end < len(file.original) && file.original[end] == '\n' {
+			end++
+		}
+		file.edits = append(file.edits, textEdit{start: declaration.start(), end: end})
+		for importPath, explicit := range neededImports {
+			file.dropImports[importPath] = explicit
+		}
+	}
+	inMoved := func(file *moveFile, pos token.Pos) bool {
+		offset := file.offset(pos)
+		for _, declaration := range moved {
+			if declaration.file == file && offset >= declaration.start() && offset < declaration.end() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Update the references of the module
+	sourceUsesDest := false
+	for _, pkg := range module.packages {
+		for _, sourceFile := range pkg.files {
+			external := strings.HasSuffix(sourceFile.ast.Name.Name, "_test")
+			inSource := pkg == src && !external
+			if !inSource && !importsPath(sourceFile.ast, src.importPath) {
+				continue
+			}
+			file, err := load(sourceFile.path)
+			if err != nil {
+				return "", err
+			}
+			inDest := pkg.dir == destDir && !external
+
+			var refErr error
+			skip := make(map[*ast.Ident]bool)
+			ast.Inspect(file.ast, func(node ast.Node) bool {
+				if refErr != nil {
+					return false
+				}
+				switch node := node.(type) {
+				case *ast.SelectorExpr:
+					skip[node.Sel] = true
+					if inSource && unexported[node.Sel.Name] && !inMoved(file, node.Pos()) {
+						refErr = fmt.Errorf(
+							"%s uses %s, an unexported field or method of %s, which would not be accessible after the move",
+							module.relPath(file.path),
+							node.Sel.Name,
+							symbolName,
+						)
+						return false
+					}
+					ident, ok := node.X.(*ast.Ident)
+					if !ok || ident.Obj != nil || node.Sel.Name != symbolName || inSource {
+						return true
+					}
+					importPath, explicit, ok := file.importOf(ident.Name, packageNames)
+					if !ok || importPath != src.importPath {
+						return true
+					}
+					file.dropImports[importPath] = explicit
+					if inDest {
+						file.edits = append(file.edits, textEdit{
+							start: file.offset(ident.Pos()),
+							end:   file.offset(node.Sel.Pos()),
+						})
+						return true
+					}
+					qualifier, err := file.qualifierOf(destImportPath, destName)
+					if err != nil {
+						refErr = err
+						return false
+					}
+					file.edits = append(file.edits, textEdit{
+						start:       file.offset(ident.Pos()),
+						end:         file.offset(ident.End()),
+						replacement: qualifier,
+					})
+				case *ast.KeyValueExpr:
+					if ident, ok := node.Key.(*ast.Ident); ok {
+						skip[ident] = true
+					}
+				case *ast.FuncDecl:
+					skip[node.Name] = true
+				case *ast.Ident:
+					if !inSource || skip[node] || node.Name != symbolName || inMoved(file, node.Pos()) {
+						return true
+					}
+					if node.Obj != nil && !topLevelNodes[node.Obj.Decl] {
+						return true
+					}
+					qualifier, err := file.qualifierOf(destImportPath, destName)
+					if err != nil {
+						refErr = err
+						return false
+					}
+					if !sourceFile.test {
+						sourceUsesDest = true
+					}
+					file.edits = append(file.edits, textEdit{
+						start:       file.offset(node.Pos()),
+						end:         file.offset(node.Pos()),
+						replacement: qualifier + ".",
+					})
+				}
+				return true
+			})
+			if refErr != nil {
+				return "", refErr
+			}
+		}
+	}
+	if sourceUsesDest && importsTransitively(graph, destImportPath, src.importPath) {
+		return "", fmt.Errorf(
+			"moving %s would create an import cycle, as %s keeps using it and %s imports %s",
+			symbolName,
+			src.importPath,
+			destImportPath,
+			src.importPath,
+		)
+	}
+
+	// Append the moved code to the destination file
+	destPath := filepath.Join(destDir, filepath.Base(filePath))
+	destFile, err := load(destPath)
+	if os.IsNotExist(err) {
+		destFile = &moveFile{
+			path:        destPath,
+			original:    []byte("package " + destName + "\n"),
+			addImports:  make(map[string]string),
+			dropImports: make(map[string]string),
+		}
+		files[destPath] = destFile
+	} else if err != nil {
+		return "", err
+	}
+	var texts []string
+	for _, declaration := range moved {
+		file := declaration.file
+		start, end := declaration.start(), declaration.end()
+		var edits []textEdit
+		for _, edit := range movedEdits[file] {
+			if edit.start >= start && edit.end <= end {
+				edits = append(edits, textEdit{start: edit.start - start, end: edit.end - start, replacement: edit.replacement})
+			}
+		}
+		texts = append(texts, string(applyTextEdits(file.original[start:end], edits)))
+	}
+	destFile.appended = strings.Join(texts, "\n\n")
+	for importPath, explicit := range neededImports {
+		destFile.addImports[importPath] = explicit
+	}
+
+	var changed []string
+	for _, file := range files {
+		if len(file.edits) == 0 && file.appended == "" && len(file.addImports) == 0 {
+			continue
+		}
+		if err := file.write(); err != nil {
+			return "", err
+		}
+		changed = append(changed, module.relPath(file.path))
+	}
+	slices.Sort(changed)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Moved %s %s", kind, symbolName)
+	if methods := len(moved) - 1; methods > 0 {
+		fmt.Fprintf(&b, " and %d methods", methods)
+	}
+	fmt.Fprintf(&b, " from %s to %s (%s)\n", src.importPath, destImportPath, module.relPath(destPath))
+	fmt.Fprintf(&b, "Updated %d files:\n", len(changed))
+	for _, file := range changed {
+		fmt.Fprintf(&b, "- %s\n", file)
+	}
+	return b.String(), nil
+}
+
+// importsPath reports whether the file imports the package
+func importsPath(file *ast.File, importPath string) bool {
+	for _, spec := range file.Imports {
+		if existing, err := strconv.Unquote(spec.Path.Value); err == nil && existing == importPath {
+			return true
+		}
+	}
+	return false
+}
+
+// importsTransitively reports whether from imports to through the import graph
+func importsTransitively(graph map[string][]string, from string, to string) bool {
+	seen := make(map[string]bool)
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return true
+		}
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		queue = append(queue, graph[current]...)
+	}
+	return false
+}
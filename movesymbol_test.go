@@ -0,0 +1,174 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMoveSymbol(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a store package used by main
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"store/store.go": {
+				"package store",              // 1
+				"",                           // 2
+				"import (",                   // 3
+				"\t\"fmt\"",                  // 4
+				"\t\"strings\"",              // 5
+				")",                          // 6
+				"",                           // 7
+				"// Store holds values",      // 8
+				"type Store struct {",        // 9
+				"\tvalues map[string]string", // 10
+				"}",                          // 11
+				"",                           // 12
+				"// Get returns the lower cased value of key", // 13
+				"func (s *Store) Get(key string) string {",    // 14
+				"\treturn strings.ToLower(s.values[key])",     // 15
+				"}",                                      // 16
+				"",                                       // 17
+				"// Describe describes the store",        // 18
+				"func Describe(s *Store) string {",       // 19
+				"\treturn fmt.Sprint(s.Get(\"a\"))",      // 20
+				"}",                                      // 21
+				"",                                       // 22
+				"// Greet uses a helper of the package",  // 23
+				"func Greet() string {",                  // 24
+				"\treturn helper()",                      // 25
+				"}",                                      // 26
+				"",                                       // 27
+				"func helper() string { return \"hi\" }", // 28
+				"",                                       // 29
+			},
+			"main.go": {
+				"package main",                     // 1
+				"",                                 // 2
+				"import \"example.com/app/store\"", // 3
+				"",                                 // 4
+				"func main() {",                    // 5
+				"\tprintln(store.Describe(&store.Store{}))", // 6
+				"}", // 7
+				"",  // 8
+			},
+		})
+	}
+
+	readFile := func(t testing.TB, path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	t.Run("move type with methods", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		result, err := MoveSymbol(filepath.Join(workspace, "store", "store.go"), "Store", filepath.Join(workspace, "kv"))
+		if err != nil {
+			t.Fatalf("Failed to move symbol: %v", err)
+		}
+		if !strings.Contains(result, "Moved type Store and 1 methods from example.com/app/store to example.com/app/kv") {
+			t.Errorf("Expected the move to be summarized, got: %s", result)
+		}
+
+		expected := map[string]string{
+			"kv/store.go": strings.Join([]string{
+				"package kv",
+				"",
+				"import \"strings\"",
+				"",
+				"// Store holds values",
+				"type Store struct {",
+				"\tvalues map[string]string",
+				"}",
+				"",
+				"// Get returns the lower cased value of key",
+				"func (s *Store) Get(key string) string {",
+				"\treturn strings.ToLower(s.values[key])",
+				"}",
+				"",
+			}, "\n"),
+			"main.go": "\tprintln(store.Describe(&kv.Store{}))",
+		}
+		for name, text := range expected {
+			if content := readFile(t, filepath.Join(workspace, name)); !strings.Contains(content, text) {
+				t.Errorf("Expected %q in %s, got:\n%s", text, name, content)
+			}
+		}
+		store := readFile(t, filepath.Join(workspace, "store", "store.go"))
+		for _, text := range []string{"\"example.com/app/kv\"", "func Describe(s *kv.Store) string {"} {
+			if !strings.Contains(store, text) {
+				t.Errorf("Expected %q in store.go, got:\n%s", text, store)
+			}
+		}
+		if strings.Contains(store, "strings") || strings.Contains(store, "type Store") {
+			t.Errorf("Expected the type and its unused import to be removed, got:\n%s", store)
+		}
+	})
+
+	t.Run("refused moves", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "store", "store.go")
+
+		for symbol, expected := range map[string]string{
+			"Greet":   "Greet uses helper of package example.com/app/store",
+			"helper":  "only exported declarations can be moved",
+			"Missing": "no top-level function or type 'Missing' found",
+		} {
+			if _, err := MoveSymbol(file, symbol, filepath.Join(workspace, "kv")); err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected %q moving %s, got: %v", expected, symbol, err)
+			}
+		}
+		if content := readFile(t, file); !strings.Contains(content, "func Greet() string") {
+			t.Errorf("Expected refused moves to leave the files untouched, got:\n%s", content)
+		}
+	})
+
+	t.Run("move symbol tool patch", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "store", "store.go")
+		before := readFile(t, file)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name": moveSymbolToolName,
+				"arguments": map[string]any{
+					"file_path":       file,
+					"symbol":          "Describe",
+					"destination_dir": filepath.Join(workspace, "describe"),
+					"output":          "patch",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		for _, expected := range []string{"+++ b/describe/store.go", "+package describe", "+func Describe(s *store.Store) string {", "+\tprintln(describe.Describe(&store.Store{}))"} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the patch, got: %s", expected, text)
+			}
+		}
+		if readFile(t, file) != before {
+			t.Errorf("Expected the patch output to leave the files untouched")
+		}
+	})
+}
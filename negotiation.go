@@ -0,0 +1,226 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// CapabilityRoots is the client capability of listing its roots
+	CapabilityRoots = "roots"
+	// CapabilitySampling is the client capability of sampling from a language model
+	CapabilitySampling = "sampling"
+)
+
+// NegotiationOptions set the protocol versions and client capabilities the server
+// requires, and the tools it disables for clients lacking capabilities, so fleets of
+// clients of different versions behave predictably
+type NegotiationOptions struct {
+	// MinProtocolVersion rejects the initialization of clients negotiating an older MCP
+	// protocol version, e.g. "2025-03-26". Clients asking for a version the server does
+	// not know negotiate the latest version it supports.
+	MinProtocolVersion string
+	// RequiredCapabilities rejects the initialization of clients not declaring all the
+	// capabilities: CapabilityRoots, CapabilitySampling or experimental capabilities by name
+	RequiredCapabilities []string
+	// ToolCapabilities are the capabilities tools need by tool name. Tools are hidden from
+	// sessions whose client lacks any of them, and their calls are rejected.
+	ToolCapabilities map[string][]string
+	// ProgressLevel rejects calls of tools of the cost level, or a higher one, without a
+	// progress token, so long calls report their progress. Calls are not checked when it
+	// is not a CostLevel.
+	ProgressLevel CostLevel
+}
+
+// NegotiatedClient is what a client declared when initializing its session
+type NegotiatedClient struct {
+	// ProtocolVersion is the protocol version the server answered with
+	ProtocolVersion string
+	Info            mcp.Implementation
+	Capabilities    mcp.ClientCapabilities
+}
+
+// WithNegotiation enforces the minimum protocol version and client capabilities of
+// options when sessions initialize, and disables the tools of sessions whose client
+// lacks the capabilities they need. Tool handlers get the client of their session
+// from NegotiatedClientFromContext.
+func WithNegotiation(options NegotiationOptions) Option {
+	return func(o *serverOptions) {
+		o.negotiation = &options
+	}
+}
+
+// negotiatedClientKey is the context key of the client of the session calling a tool
+type negotiatedClientKey struct{}
+
+// NegotiatedClientFromContext returns the client of the session calling a tool, when the
+// server was created with WithNegotiation and the session was initialized
+func NegotiatedClientFromContext(ctx context.Context) (*NegotiatedClient, bool) {
+	client, ok := ctx.Value(negotiatedClientKey{}).(*NegotiatedClient)
+	return client, ok
+}
+
+// HasCapability reports whether the client declared a capability: CapabilityRoots,
+// CapabilitySampling or an experimental capability by name
+func (client *NegotiatedClient) HasCapability(name string) bool {
+	switch name {
+	case CapabilityRoots:
+		return client.Capabilities.Roots != nil
+	case CapabilitySampling:
+		return client.Capabilities.Sampling != nil
+	}
+	_, ok := client.Capabilities.Experimental[name]
+	return ok
+}
+
+// missingCapabilities returns the capabilities the client did not declare
+func (client *NegotiatedClient) missingCapabilities(names []string) []string {
+	var missing []string
+	for _, name := range names {
+		if !client.HasCapability(name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// negotiatedProtocolVersion is the protocol version the server answers a client asking
+// for version with, as negotiated by mcp-go
+func negotiatedProtocolVersion(version string) string {
+	if slices.Contains(mcp.ValidProtocolVersions, version) {
+		return version
+	}
+	return mcp.LATEST_PROTOCOL_VERSION
+}
+
+// negotiatedClients holds the clients of the initialized sessions by session ID. Calls
+// without a session, e.g. from the jsonl transport, share the client initialized last
+// without one.
+type negotiatedClients struct {
+	mu      sync.Mutex
+	clients map[string]*NegotiatedClient
+}
+
+func newNegotiatedClients() *negotiatedClients {
+	return &negotiatedClients{clients: make(map[string]*NegotiatedClient)}
+}
+
+// get returns the client of the session of ctx, nil before its initialization
+func (clients *negotiatedClients) get(ctx context.Context) *NegotiatedClient {
+	sessionID := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	clients.mu.Lock()
+	defer clients.mu.Unlock()
+	return clients.clients[sessionID]
+}
+
+// addHooks rejects the initialization of clients not meeting the requirements of options,
+// and keeps the clients of the sessions initialized until they end
+func (clients *negotiatedClients) addHooks(hooks *server.Hooks, options NegotiationOptions) {
+	hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+		raw, ok := message.(json.RawMessage)
+		if !ok {
+			return nil
+		}
+		var request mcp.InitializeRequest
+		if err := json.Unmarshal(raw, &request); err != nil || request.Method != string(mcp.MethodInitialize) {
+			return nil
+		}
+		client := &NegotiatedClient{
+			ProtocolVersion: negotiatedProtocolVersion(request.Params.ProtocolVersion),
+			Capabilities:    request.Params.Capabilities,
+		}
+		// Protocol versions are dates, which compare as strings
+		if options.MinProtocolVersion != "" && client.ProtocolVersion < options.MinProtocolVersion {
+			return fmt.Errorf(
+				"protocol version %s is older than %s, the minimum version of this server",
+				client.ProtocolVersion,
+				options.MinProtocolVersion,
+			)
+		}
+		if missing := client.missingCapabilities(options.RequiredCapabilities); len(missing) > 0 {
+			return fmt.Errorf("this server requires clients with the capabilities: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	})
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+		sessionID := ""
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			sessionID = session.SessionID()
+		}
+		clients.mu.Lock()
+		defer clients.mu.Unlock()
+		clients.clients[sessionID] = &NegotiatedClient{
+			ProtocolVersion: result.ProtocolVersion,
+			Info:            message.Params.ClientInfo,
+			Capabilities:    message.Params.Capabilities,
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		clients.mu.Lock()
+		defer clients.mu.Unlock()
+		delete(clients.clients, session.SessionID())
+	})
+}
+
+// negotiationToolFilter hides the tools needing capabilities the client of the session
+// lacks. All tools are listed before the session is initialized.
+func negotiationToolFilter(options NegotiationOptions, clients *negotiatedClients) server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		client := clients.get(ctx)
+		if client == nil {
+			return tools
+		}
+		return slices.DeleteFunc(tools, func(tool mcp.Tool) bool {
+			return len(client.missingCapabilities(options.ToolCapabilities[tool.Name])) > 0
+		})
+	}
+}
+
+// negotiationMiddleware rejects calls of tools needing capabilities the client of the
+// session lacks, and calls of costly tools without a progress token, and passes the
+// client of the session to the tool handlers
+func negotiationMiddleware(
+	options NegotiationOptions,
+	clients *negotiatedClients,
+	costs map[string]ToolCost,
+) server.ToolHandlerMiddleware {
+	levels := []CostLevel{CostLow, CostMedium, CostHigh}
+	progressFrom := slices.Index(levels, options.ProgressLevel)
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name := request.Params.Name
+			if client := clients.get(ctx); client != nil {
+				if missing := client.missingCapabilities(options.ToolCapabilities[name]); len(missing) > 0 {
+					return toolErrorResult(fmt.Sprintf(
+						"Tool %s requires clients with the capabilities: %s",
+						name,
+						strings.Join(missing, ", "),
+					)), nil
+				}
+				ctx = context.WithValue(ctx, negotiatedClientKey{}, client)
+			}
+
+			level := costs[name].Level
+			if progressFrom >= 0 && slices.Index(levels, level) >= progressFrom &&
+				(request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil) {
+				return toolErrorResult(fmt.Sprintf(
+					"Tool %s is of %s cost and this server requires its calls to have a progress token, "+
+						"call it with _meta.progressToken",
+					name,
+					level,
+				)), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestNegotiation(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to send a request and return the encoded response
+	send := func(t testing.TB, mcpServer *server.MCPServer, ctx context.Context, method mcp.MCPMethod, params any) string {
+		t.Helper()
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(method),
+			"params":  params,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response, err := json.Marshal(mcpServer.HandleMessage(ctx, encoded))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(response)
+	}
+
+	// Helper function to initialize a session with a protocol version and capabilities
+	initialize := func(t testing.TB, mcpServer *server.MCPServer, ctx context.Context, version string, capabilities map[string]any) string {
+		t.Helper()
+		return send(t, mcpServer, ctx, mcp.MethodInitialize, map[string]any{
+			"protocolVersion": version,
+			"capabilities":    capabilities,
+			"clientInfo":      map[string]any{"name": "test-client", "version": "0.1.0"},
+		})
+	}
+
+	clientHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client, ok := NegotiatedClientFromContext(ctx)
+		if !ok {
+			return mcp.NewToolResultText("no client"), nil
+		}
+		return mcp.NewToolResultText("client " + client.Info.Name + " " + client.ProtocolVersion), nil
+	}
+
+	newServer := func() *server.MCPServer {
+		return NewMCPServer(
+			WithTool(mcp.NewTool("plain"), clientHandler),
+			WithTool(mcp.NewTool("sampler"), clientHandler),
+			WithTool(mcp.NewTool("slow"), clientHandler),
+			WithToolCost("plain", ToolCost{Level: CostLow}),
+			WithToolCost("sampler", ToolCost{Level: CostLow}),
+			WithToolCost("slow", ToolCost{Level: CostHigh}),
+			WithNegotiation(NegotiationOptions{
+				MinProtocolVersion:   "2025-03-26",
+				RequiredCapabilities: []string{CapabilityRoots},
+				ToolCapabilities:     map[string][]string{"sampler": {CapabilitySampling}},
+				ProgressLevel:        CostHigh,
+			}),
+		)
+	}
+
+	t.Run("initialization requirements", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := newServer()
+		for _, test := range []struct {
+			name         string
+			version      string
+			capabilities map[string]any
+			rejected     string
+		}{
+			{"old protocol", "2024-11-05", map[string]any{"roots": map[string]any{}}, "protocol version 2024-11-05 is older than 2025-03-26"},
+			{"missing roots", "2025-03-26", map[string]any{}, "requires clients with the capabilities: roots"},
+			{"unknown newer protocol", "2099-01-01", map[string]any{"roots": map[string]any{}}, ""},
+			{"accepted", "2025-03-26", map[string]any{"roots": map[string]any{}}, ""},
+		} {
+			ctx := mcpServer.WithContext(context.Background(), &testSession{id: test.name})
+			response := initialize(t, mcpServer, ctx, test.version, test.capabilities)
+			if test.rejected == "" {
+				if !strings.Contains(response, `"protocolVersion":"2025-03-26"`) {
+					t.Errorf("Expected %s to be initialized, got: %s", test.name, response)
+				}
+				continue
+			}
+			if !strings.Contains(response, test.rejected) || !strings.Contains(response, `"error"`) {
+				t.Errorf("Expected %s to be rejected with %q, got: %s", test.name, test.rejected, response)
+			}
+		}
+	})
+
+	t.Run("tools disabled by capabilities", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := newServer()
+		plain := mcpServer.WithContext(context.Background(), &testSession{id: "plain"})
+		initialize(t, mcpServer, plain, "2025-03-26", map[string]any{"roots": map[string]any{}})
+		sampling := mcpServer.WithContext(context.Background(), &testSession{id: "sampling"})
+		initialize(t, mcpServer, sampling, "2025-03-26", map[string]any{"roots": map[string]any{}, "sampling": map[string]any{}})
+
+		if listing := send(t, mcpServer, plain, mcp.MethodToolsList, map[string]any{}); strings.Contains(listing, `"name":"sampler"`) ||
+			!strings.Contains(listing, `"name":"plain"`) {
+			t.Errorf("Expected sampler to be hidden from clients without sampling, got: %s", listing)
+		}
+		if listing := send(t, mcpServer, sampling, mcp.MethodToolsList, map[string]any{}); !strings.Contains(listing, `"name":"sampler"`) {
+			t.Errorf("Expected sampler to be listed for clients with sampling, got: %s", listing)
+		}
+
+		call := map[string]any{"name": "sampler", "arguments": map[string]any{}}
+		if response := send(t, mcpServer, plain, mcp.MethodToolsCall, call); !strings.Contains(response, "Tool sampler requires clients with the capabilities: sampling") {
+			t.Errorf("Expected the call to be rejected, got: %s", response)
+		}
+		if response := send(t, mcpServer, sampling, mcp.MethodToolsCall, call); !strings.Contains(response, "client test-client 2025-03-26") {
+			t.Errorf("Expected the handler to get the negotiated client, got: %s", response)
+		}
+	})
+
+	t.Run("progress required for costly tools", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := newServer()
+		ctx := mcpServer.WithContext(context.Background(), &testSession{id: "progress"})
+		initialize(t, mcpServer, ctx, "2025-03-26", map[string]any{"roots": map[string]any{}})
+
+		response := send(t, mcpServer, ctx, mcp.MethodToolsCall, map[string]any{"name": "slow", "arguments": map[string]any{}})
+		if !strings.Contains(response, "requires its calls to have a progress token") || !strings.Contains(response, `"isError":true`) {
+			t.Errorf("Expected the call without a progress token to be rejected, got: %s", response)
+		}
+		response = send(t, mcpServer, ctx, mcp.MethodToolsCall, map[string]any{
+			"name":      "slow",
+			"arguments": map[string]any{},
+			"_meta":     map[string]any{"progressToken": "slow-1"},
+		})
+		if !strings.Contains(response, "client test-client") {
+			t.Errorf("Expected the call with a progress token to run, got: %s", response)
+		}
+		response = send(t, mcpServer, ctx, mcp.MethodToolsCall, map[string]any{"name": "plain", "arguments": map[string]any{}})
+		if !strings.Contains(response, "client test-client") {
+			t.Errorf("Expected cheap tools to run without a progress token, got: %s", response)
+		}
+	})
+}
@@ -0,0 +1,721 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	ormMappingsToolName        = "orm_mappings"
+	ormMappingsToolDescription = `Reports the database models of a module with their table, the column of every field and the code using them, so all touchpoints of a table are visible before changing persistence code. Recognized models:
+
+• GORM: structs embedding gorm.Model or with gorm tags. Tables come from TableName methods or the GORM naming convention, and the uses are the *gorm.DB calls taking the model, e.g. db.First(&user).
+• ent: the generated entities, with the columns of the generated field constants, the location of the schema and the uses of the entity client and predicates.
+• sqlc: the generated models, with the generated queries returning or writing their table, the .sql file of each query and the calls of every query.`
+)
+
+// Import paths of the GORM versions
+var gormImportPaths = []string{"gorm.io/gorm", "github.com/jinzhu/gorm"}
+
+// ORMReport lists the database models of a module
+type ORMReport struct {
+	Models []ORMModel `json:"models"`
+}
+
+// ORMModel is a struct mapped to a table
+type ORMModel struct {
+	// Framework is one of gorm, ent and sqlc
+	Framework string `json:"framework"`
+	// Name is the struct qualified by its package name, e.g. models.User
+	Name    string      `json:"name"`
+	File    string      `json:"file"`
+	Line    int         `json:"line"`
+	Table   string      `json:"table"`
+	Columns []ORMColumn `json:"columns,omitempty"`
+	// Schema locates the ent schema the model is generated from, as file:line
+	Schema string `json:"schema,omitempty"`
+	// Queries are the sqlc queries of the table
+	Queries    []ORMQuery     `json:"queries,omitempty"`
+	References []ORMReference `json:"references,omitempty"`
+}
+
+// ORMColumn maps a struct field to a column
+type ORMColumn struct {
+	Field  string `json:"field"`
+	Column string `json:"column"`
+}
+
+// ORMQuery is a query generated by sqlc
+type ORMQuery struct {
+	Name string `json:"name"`
+	// Command is the sqlc query command, e.g. :one or :exec
+	Command string `json:"command"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	// Source is the .sql file of the query as named by the generated code
+	Source     string         `json:"source,omitempty"`
+	References []ORMReference `json:"references,omitempty"`
+}
+
+// ORMReference is code using a model or query
+type ORMReference struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Function is the top-level function containing the use, empty at package level
+	Function string `json:"function,omitempty"`
+	// Uses are the calls or identifiers using the model on the line, e.g. First
+	Uses []string `json:"uses"`
+}
+
+func AddORMMappingsTool(mcpServer *server.MCPServer) {
+	handleORMMappings := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		table := request.GetString("table", "")
+
+		report, err := MapORMModels(ctx, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error mapping ORM models: %v", err)), nil
+		}
+		if table != "" {
+			report.Models = slices.DeleteFunc(report.Models, func(model ORMModel) bool {
+				return !strings.EqualFold(model.Table, table) && !strings.HasSuffix(model.Name, "."+table)
+			})
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		ormMappingsToolName,
+		mcp.WithDescription(ormMappingsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+		mcp.WithString("table",
+			mcp.Description("Only report the model of this table or struct name"),
+		),
+	), handleORMMappings)
+}
+
+// ormMapper collects the models of the packages of a module
+type ormMapper struct {
+	fset   *token.FileSet
+	module *moduleSources
+	pkgs   []*packages.Package
+	report *ORMReport
+}
+
+// MapORMModels type checks the packages of the module containing workspaceDir and
+// reports its GORM, ent and sqlc models
+func MapORMModels(ctx context.Context, workspaceDir string) (*ORMReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Context: ctx,
+		Dir:     module.root,
+		Env:     packagesEnv(module.root),
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages found in %s", module.root)
+	}
+
+	mapper := &ormMapper{fset: pkgs[0].Fset, module: module, pkgs: pkgs, report: &ORMReport{}}
+	mapper.mapGORM()
+	mapper.mapEnt()
+	mapper.mapSQLC()
+	slices.SortStableFunc(mapper.report.Models, func(a, b ORMModel) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+		return a.Line - b.Line
+	})
+	return mapper.report, nil
+}
+
+// position returns the file relative to the module root and line of pos
+func (mapper *ormMapper) position(pos token.Pos) (string, int) {
+	position := mapper.fset.Position(pos)
+	return mapper.module.relPath(position.Filename), position.Line
+}
+
+// newModel returns a model of a struct type, located at its declaration
+func (mapper *ormMapper) newModel(framework string, typeName *types.TypeName, table string) ORMModel {
+	file, line := mapper.position(typeName.Pos())
+	return ORMModel{
+		Framework: framework,
+		Name:      typeName.Pkg().Name() + "." + typeName.Name(),
+		File:      file,
+		Line:      line,
+		Table:     table,
+	}
+}
+
+// addReference adds a use on a line to references, merging the uses of a line
+func (mapper *ormMapper) addReference(references []ORMReference, file *ast.File, pos token.Pos, use string) []ORMReference {
+	filename, line := mapper.position(pos)
+	for i := range references {
+		if references[i].File == filename && references[i].Line == line {
+			if !slices.Contains(references[i].Uses, use) {
+				references[i].Uses = append(references[i].Uses, use)
+			}
+			return references
+		}
+	}
+	return append(references, ORMReference{
+		File:     filename,
+		Line:     line,
+		Function: enclosingFunctionName(file, pos),
+		Uses:     []string{use},
+	})
+}
+
+// mapGORM adds the structs embedding gorm.Model or with gorm tags, with the *gorm.DB
+// calls taking them
+func (mapper *ormMapper) mapGORM() {
+	models := make(map[*types.TypeName]int)
+	for _, pkg := range mapper.pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeName, ok := pkg.TypesInfo.Defs[spec.(*ast.TypeSpec).Name].(*types.TypeName)
+					if !ok || !isGORMModel(typeName.Type()) {
+						continue
+					}
+					table := gormTableName(pkg, typeName.Name())
+					if table == "" {
+						table = pluralize(snakeCase(typeName.Name()))
+					}
+					model := mapper.newModel("gorm", typeName, table)
+					model.Columns = gormColumns(typeName.Type().Underlying().(*types.Struct))
+					models[typeName] = len(mapper.report.Models)
+					mapper.report.Models = append(mapper.report.Models, model)
+				}
+			}
+		}
+	}
+	if len(models) == 0 {
+		return
+	}
+
+	for _, pkg := range mapper.pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				method, ok := typeutil.Callee(pkg.TypesInfo, call).(*types.Func)
+				if !ok || !isGORMDB(method) {
+					return true
+				}
+				for _, arg := range call.Args {
+					if typeName := modelTypeName(pkg.TypesInfo.TypeOf(arg)); typeName != nil {
+						if index, ok := models[typeName]; ok {
+							model := &mapper.report.Models[index]
+							model.References = mapper.addReference(model.References, file, call.Lparen, method.Name())
+						}
+						continue
+					}
+					// db.Table("users") names the table of a model directly
+					value := pkg.TypesInfo.Types[arg].Value
+					if value == nil || value.Kind() != constant.String {
+						continue
+					}
+					for _, index := range models {
+						model := &mapper.report.Models[index]
+						if model.Table == constant.StringVal(value) {
+							model.References = mapper.addReference(model.References, file, call.Lparen, method.Name())
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// isGORMModel reports whether t is a struct embedding gorm.Model or with gorm tags
+func isGORMModel(t types.Type) bool {
+	structType, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		if _, ok := reflect.StructTag(structType.Tag(i)).Lookup("gorm"); ok {
+			return true
+		}
+		if named, ok := types.Unalias(field.Type()).(*types.Named); ok && field.Embedded() &&
+			named.Obj().Name() == "Model" && named.Obj().Pkg() != nil &&
+			slices.Contains(gormImportPaths, named.Obj().Pkg().Path()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGORMDB reports whether a function is a method of gorm.DB
+func isGORMDB(method *types.Func) bool {
+	recv := method.Signature().Recv()
+	if recv == nil {
+		return false
+	}
+	typeName := modelTypeName(recv.Type())
+	return typeName != nil && typeName.Name() == "DB" && typeName.Pkg() != nil &&
+		slices.Contains(gormImportPaths, typeName.Pkg().Path())
+}
+
+// modelTypeName returns the named type of models passed as T, *T, []T, []*T or *[]*T
+func modelTypeName(t types.Type) *types.TypeName {
+	for t != nil {
+		switch u := types.Unalias(t).(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Named:
+			return u.Obj()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// gormTableName returns the string literal returned by the TableName method of a
+// type in pkg, or an empty string
+func gormTableName(pkg *packages.Package, typeName string) string {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Name.Name != "TableName" || funcDecl.Recv == nil || funcDecl.Body == nil ||
+				receiverTypeName(funcDecl.Recv.List[0].Type) != typeName || len(funcDecl.Body.List) != 1 {
+				continue
+			}
+			if ret, ok := funcDecl.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+				if value := pkg.TypesInfo.Types[ret.Results[0]].Value; value != nil && value.Kind() == constant.String {
+					return constant.StringVal(value)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// gormColumns maps the fields of a GORM model to columns. Embedded structs such as
+// gorm.Model contribute their fields, associations with other structs are skipped.
+func gormColumns(structType *types.Struct) []ORMColumn {
+	var columns []ORMColumn
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		settings := make(map[string]string)
+		for setting := range strings.SplitSeq(reflect.StructTag(structType.Tag(i)).Get("gorm"), ";") {
+			key, value, _ := strings.Cut(setting, ":")
+			settings[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+		if _, ok := settings["-"]; ok || !field.Exported() {
+			continue
+		}
+		_, embedded := settings["embedded"]
+		if nested, ok := field.Type().Underlying().(*types.Struct); ok && (field.Embedded() || embedded) {
+			columns = append(columns, gormColumns(nested)...)
+			continue
+		}
+		if isAssociation(field.Type()) {
+			continue
+		}
+		column := settings["column"]
+		if column == "" {
+			column = snakeCase(field.Name())
+		}
+		columns = append(columns, ORMColumn{Field: field.Name(), Column: column})
+	}
+	return columns
+}
+
+// isAssociation reports whether a field type refers to other models: slices of structs
+// and structs other than the value types of database drivers, such as time.Time
+func isAssociation(t types.Type) bool {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	if slice, ok := t.Underlying().(*types.Slice); ok {
+		return isAssociation(slice.Elem())
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	// Structs implementing driver.Valuer or sql.Scanner are stored in a column
+	for _, name := range []string{"Value", "Scan"} {
+		if object, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), name); object != nil {
+			return false
+		}
+	}
+	return !(named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time")
+}
+
+// mapEnt adds the entities generated by ent, found by their generated package declaring
+// the Label, Table and FieldID constants
+func (mapper *ormMapper) mapEnt() {
+	byPath := make(map[string]*packages.Package)
+	packages.Visit(mapper.pkgs, nil, func(pkg *packages.Package) { byPath[pkg.PkgPath] = pkg })
+
+	for _, entity := range mapper.pkgs {
+		scope := entity.Types.Scope()
+		label, table := constantString(scope.Lookup("Label")), constantString(scope.Lookup("Table"))
+		if label == "" || table == "" || scope.Lookup("FieldID") == nil {
+			continue
+		}
+		entPath := path.Dir(entity.PkgPath)
+		ent, ok := byPath[entPath]
+		if !ok {
+			continue
+		}
+		var typeName *types.TypeName
+		for _, name := range ent.Types.Scope().Names() {
+			if object, ok := ent.Types.Scope().Lookup(name).(*types.TypeName); ok && snakeCase(name) == label {
+				typeName = object
+			}
+		}
+		if typeName == nil {
+			continue
+		}
+
+		model := mapper.newModel("ent", typeName, table)
+		var fields []*types.Const
+		for _, name := range scope.Names() {
+			if object, ok := scope.Lookup(name).(*types.Const); ok && strings.HasPrefix(name, "Field") {
+				fields = append(fields, object)
+			}
+		}
+		slices.SortFunc(fields, func(a, b *types.Const) int { return int(a.Pos() - b.Pos()) })
+		for _, field := range fields {
+			model.Columns = append(model.Columns, ORMColumn{
+				Field:  strings.TrimPrefix(field.Name(), "Field"),
+				Column: constantString(field),
+			})
+		}
+		if schema, ok := byPath[entPath+"/schema"]; ok {
+			if object := schema.Types.Scope().Lookup(typeName.Name()); object != nil {
+				file, line := mapper.position(object.Pos())
+				model.Schema = fmt.Sprintf("%s:%d", file, line)
+			}
+		}
+
+		// Uses of the entity client, the entity and its predicates outside of the generated code
+		for _, pkg := range mapper.pkgs {
+			if pkg.PkgPath == entPath || strings.HasPrefix(pkg.PkgPath, entPath+"/") {
+				continue
+			}
+			for _, file := range pkg.Syntax {
+				ast.Inspect(file, func(node ast.Node) bool {
+					selector, ok := node.(*ast.SelectorExpr)
+					if !ok {
+						return true
+					}
+					object := pkg.TypesInfo.Uses[selector.Sel]
+					if object == nil || object.Pkg() == nil {
+						return true
+					}
+					client := modelTypeName(object.Type())
+					if object.Pkg() == entity.Types || object == typeName ||
+						client != nil && client.Pkg() == ent.Types && client.Name() == typeName.Name()+"Client" {
+						model.References = mapper.addReference(model.References, file, selector.Pos(), types.ExprString(selector))
+					}
+					return true
+				})
+			}
+		}
+		mapper.report.Models = append(mapper.report.Models, model)
+	}
+}
+
+// constantString returns the value of a string constant, or an empty string
+func constantString(object types.Object) string {
+	constObject, ok := object.(*types.Const)
+	if !ok || constObject.Val().Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(constObject.Val())
+}
+
+// sqlcQueryComment matches the comment sqlc starts the SQL of a generated query with
+var sqlcQueryComment = regexp.MustCompile(`^-- name: (\w+) (:\w+)`)
+
+// sqlTable matches the first table a statement reads or writes
+var sqlTable = regexp.MustCompile(`(?i)\b(?:from|into|update)\s+([a-z_][\w.]*)`)
+
+// mapSQLC adds the models generated by sqlc, with the generated queries of their tables
+func (mapper *ormMapper) mapSQLC() {
+	for _, pkg := range mapper.pkgs {
+		queriesType, ok := pkg.Types.Scope().Lookup("Queries").(*types.TypeName)
+		if !ok {
+			continue
+		}
+		var models []ORMModel
+		modelIndex := make(map[*types.TypeName]int)
+		// queries by their method, with the table of their SQL
+		type sqlcQuery struct {
+			query ORMQuery
+			table string
+			model *types.TypeName
+		}
+		queries := make(map[*types.Func]*sqlcQuery)
+		var queryOrder []*types.Func
+		for _, file := range pkg.Syntax {
+			if !isSQLCGenerated(file) {
+				continue
+			}
+			source := ""
+			for _, group := range file.Comments {
+				for _, comment := range group.List {
+					if value, ok := strings.CutPrefix(comment.Text, "// source: "); ok && source == "" {
+						source = value
+					}
+				}
+			}
+			statements := make(map[string]string)
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						switch spec := spec.(type) {
+						case *ast.TypeSpec:
+							typeName, ok := pkg.TypesInfo.Defs[spec.Name].(*types.TypeName)
+							// Params and Row structs of queries are declared with the queries
+							if !ok || typeName == queriesType || source != "" {
+								continue
+							}
+							structType, ok := typeName.Type().Underlying().(*types.Struct)
+							if !ok {
+								continue
+							}
+							model := mapper.newModel("sqlc", typeName, "")
+							model.Columns = sqlcColumns(structType)
+							modelIndex[typeName] = len(models)
+							models = append(models, model)
+						case *ast.ValueSpec:
+							for _, value := range spec.Values {
+								if sql := pkg.TypesInfo.Types[value].Value; sql != nil && sql.Kind() == constant.String {
+									if match := sqlcQueryComment.FindStringSubmatch(constant.StringVal(sql)); match != nil {
+										statements[match[1]] = constant.StringVal(sql)
+									}
+								}
+							}
+						}
+					}
+				case *ast.FuncDecl:
+					method, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+					if !ok || decl.Recv == nil || receiverTypeName(decl.Recv.List[0].Type) != "Queries" {
+						continue
+					}
+					query := &sqlcQuery{query: ORMQuery{Name: method.Name(), Source: source}}
+					query.query.File, query.query.Line = mapper.position(method.Pos())
+					if results := method.Signature().Results(); results.Len() > 0 {
+						query.model = modelTypeName(results.At(0).Type())
+					}
+					queries[method] = query
+					queryOrder = append(queryOrder, method)
+				}
+			}
+			for _, method := range queryOrder {
+				query := queries[method]
+				statement, ok := statements[query.query.Name]
+				if !ok || query.query.Command != "" {
+					continue
+				}
+				query.query.Command = sqlcQueryComment.FindStringSubmatch(statement)[2]
+				if match := sqlTable.FindStringSubmatch(statement); match != nil {
+					query.table = match[1]
+				}
+			}
+		}
+		if len(models) == 0 {
+			continue
+		}
+
+		// The tables of models are those of the queries returning them
+		for _, method := range queryOrder {
+			query := queries[method]
+			if index, ok := modelIndex[query.model]; ok && models[index].Table == "" && query.table != "" {
+				models[index].Table = query.table
+			}
+		}
+		for i := range models {
+			if models[i].Table == "" {
+				models[i].Table = pluralize(snakeCase(strings.TrimPrefix(models[i].Name, pkg.Name+".")))
+			}
+		}
+		for _, method := range queryOrder {
+			query := queries[method]
+			index, ok := modelIndex[query.model]
+			if !ok {
+				index = slices.IndexFunc(models, func(model ORMModel) bool { return query.table != "" && model.Table == query.table })
+			}
+			if index < 0 {
+				continue
+			}
+			for _, other := range mapper.pkgs {
+				for _, file := range other.Syntax {
+					ast.Inspect(file, func(node ast.Node) bool {
+						call, ok := node.(*ast.CallExpr)
+						if ok && typeutil.Callee(other.TypesInfo, call) == method {
+							query.query.References = mapper.addReference(query.query.References, file, call.Pos(), types.ExprString(call.Fun))
+						}
+						return true
+					})
+				}
+			}
+			models[index].Queries = append(models[index].Queries, query.query)
+		}
+		mapper.report.Models = append(mapper.report.Models, models...)
+	}
+}
+
+// isSQLCGenerated reports whether a file was generated by sqlc
+func isSQLCGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		if strings.Contains(group.Text(), "Code generated by sqlc") {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlcColumns maps the fields of a sqlc model to columns by their db or json tags, or
+// the snake case field name sqlc generated the field name from
+func sqlcColumns(structType *types.Struct) []ORMColumn {
+	var columns []ORMColumn
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		tag := reflect.StructTag(structType.Tag(i))
+		column, _, _ := strings.Cut(tag.Get("db"), ",")
+		if column == "" {
+			column, _, _ = strings.Cut(tag.Get("json"), ",")
+		}
+		if column == "" {
+			column = snakeCase(field.Name())
+		}
+		columns = append(columns, ORMColumn{Field: field.Name(), Column: column})
+	}
+	return columns
+}
+
+// snakeCase converts a Go name to snake case as ORMs name columns, keeping initialisms
+// together, e.g. UserID to user_id and HTTPServer to http_server
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextIsLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// pluralize returns the English plural of a table name by the common rules
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+func (report *ORMReport) String() string {
+	if len(report.Models) == 0 {
+		return "No GORM, ent or sqlc models found"
+	}
+	var b strings.Builder
+	writeReferences := func(indent string, references []ORMReference) {
+		for _, reference := range references {
+			fmt.Fprintf(&b, "%s%s:%d", indent, reference.File, reference.Line)
+			if reference.Function != "" {
+				fmt.Fprintf(&b, " in %s", reference.Function)
+			}
+			fmt.Fprintf(&b, ": %s\n", strings.Join(reference.Uses, ", "))
+		}
+	}
+	for i, model := range report.Models {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s %s (%s:%d) -> table %s\n", model.Framework, model.Name, model.File, model.Line, model.Table)
+		if model.Schema != "" {
+			fmt.Fprintf(&b, "  schema: %s\n", model.Schema)
+		}
+		if len(model.Columns) > 0 {
+			var columns []string
+			for _, column := range model.Columns {
+				columns = append(columns, column.Field+" "+column.Column)
+			}
+			fmt.Fprintf(&b, "  columns: %s\n", strings.Join(columns, ", "))
+		}
+		for _, query := range model.Queries {
+			fmt.Fprintf(&b, "  query %s %s (%s:%d", query.Name, query.Command, query.File, query.Line)
+			if query.Source != "" {
+				fmt.Fprintf(&b, ", from %s", query.Source)
+			}
+			b.WriteString(")\n")
+			writeReferences("    ", query.References)
+		}
+		if len(model.References) > 0 {
+			b.WriteString("  used at:\n")
+			writeReferences("    ", model.References)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,290 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestORMMappings(t *testing.T) {
+	t.Parallel()
+
+	// GORM is replaced by a stub with the same API, ent and sqlc code as they generate it
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {
+			"module example.com/shop",
+			"",
+			"go 1.22",
+			"",
+			"require gorm.io/gorm v1.25.0",
+			"",
+			"replace gorm.io/gorm => ./stubs/gorm",
+			"",
+		},
+		"stubs/gorm/go.mod": {"module gorm.io/gorm", "", "go 1.22", ""},
+		"stubs/gorm/gorm.go": {
+			"package gorm",
+			"",
+			"import \"time\"",
+			"",
+			"type Model struct {",
+			"	ID        uint",
+			"	CreatedAt time.Time",
+			"	UpdatedAt time.Time",
+			"	DeletedAt *time.Time",
+			"}",
+			"",
+			"type DB struct{}",
+			"",
+			"func (db *DB) Where(query interface{}, args ...interface{}) *DB { return db }",
+			"",
+			"func (db *DB) First(dest interface{}, conds ...interface{}) *DB { return db }",
+			"",
+			"func (db *DB) Create(value interface{}) *DB { return db }",
+			"",
+			"func (db *DB) Table(name string, args ...interface{}) *DB { return db }",
+			"",
+			"func (db *DB) Count(count *int64) *DB { return db }",
+			"",
+		},
+		"models/models.go": {
+			"package models", // 1
+			"",
+			"import \"gorm.io/gorm\"", // 3
+			"",
+			"type User struct {", // 5
+			"	gorm.Model",
+			"	Name     string",
+			"	Email    string `gorm:\"column:email_address;uniqueIndex\"`",
+			"	Secret   string `gorm:\"-\"`",
+			"	Orders   []Order",
+			"	Category Category",
+			"}",
+			"",
+			"type Category struct {", // 14
+			"	ID   uint `gorm:\"primaryKey\"`",
+			"	Name string",
+			"}",
+			"",
+			"func (Category) TableName() string { return \"product_categories\" }", // 19
+			"",
+			"type Order struct {", // 21
+			"	gorm.Model",
+			"	UserID uint",
+			"}",
+			"",
+		},
+		"store/store.go": {
+			"package store", // 1
+			"",
+			"import (", // 3
+			"	\"example.com/shop/models\"",
+			"	\"gorm.io/gorm\"",
+			")",
+			"",
+			"func GetUser(db *gorm.DB, id uint) (*models.User, error) {", // 8
+			"	var user models.User",
+			"	db.Where(\"id = ?\", id).First(&user)", // 10
+			"	return &user, nil",
+			"}",
+			"",
+			"func CountCategories(db *gorm.DB) int64 {", // 14
+			"	var count int64",
+			"	db.Table(\"product_categories\").Count(&count)", // 16
+			"	return count",
+			"}",
+			"",
+			"func CreateOrders(db *gorm.DB, orders []*models.Order) {", // 20
+			"	db.Create(orders)", // 21
+			"}",
+			"",
+		},
+		"ent/user.go": {
+			"// Code generated by ent, DO NOT EDIT.",
+			"",
+			"package ent", // 3
+			"",
+			"type Account struct {", // 5
+			"	ID    int",
+			"	Login string",
+			"}",
+			"",
+			"type AccountClient struct{}", // 10
+			"",
+			"func (*AccountClient) Get(id int) *Account { return nil }", // 12
+			"",
+			"type Client struct {", // 14
+			"	Account *AccountClient",
+			"}",
+			"",
+		},
+		"ent/account/account.go": {
+			"// Code generated by ent, DO NOT EDIT.",
+			"",
+			"package account", // 3
+			"",
+			"const (", // 5
+			"	Label      = \"account\"",
+			"	FieldID    = \"id\"",
+			"	FieldLogin = \"login\"",
+			"	Table      = \"accounts\"",
+			")",
+			"",
+			"func LoginEQ(v string) func() { return nil }", // 12
+			"",
+		},
+		"ent/schema/account.go": {
+			"package schema", // 1
+			"",
+			"type Account struct{}", // 3
+			"",
+		},
+		"service/service.go": {
+			"package service", // 1
+			"",
+			"import (", // 3
+			"	\"example.com/shop/ent\"",
+			"	\"example.com/shop/ent/account\"",
+			")",
+			"",
+			"func Login(client *ent.Client) *ent.Account {", // 8
+			"	_ = account.LoginEQ(\"admin\")",               // 9
+			"	return client.Account.Get(1)",                 // 10
+			"}",
+			"",
+		},
+		"db/db.go": {
+			"// Code generated by sqlc. DO NOT EDIT.",
+			"",
+			"package db", // 3
+			"",
+			"type Queries struct{}", // 5
+			"",
+		},
+		"db/models.go": {
+			"// Code generated by sqlc. DO NOT EDIT.",
+			"",
+			"package db", // 3
+			"",
+			"type Author struct {", // 5
+			"	ID       int64  `json:\"id\"`",
+			"	FullName string `json:\"full_name\"`",
+			"}",
+			"",
+		},
+		"db/query.sql.go": {
+			"// Code generated by sqlc. DO NOT EDIT.",
+			"// source: query.sql",
+			"",
+			"package db", // 4
+			"",
+			"const getAuthor = `-- name: GetAuthor :one", // 6
+			"SELECT id, full_name FROM authors WHERE id = $1",
+			"`",
+			"",
+			"func (q *Queries) GetAuthor(id int64) (Author, error) { return Author{}, nil }", // 10
+			"",
+			"const deleteAuthor = `-- name: DeleteAuthor :exec", // 12
+			"DELETE FROM authors WHERE id = $1",
+			"`",
+			"",
+			"func (q *Queries) DeleteAuthor(id int64) error { return nil }", // 16
+			"",
+			"type GetAuthorRow struct{ ID int64 }", // 18
+			"",
+		},
+		"api/api.go": {
+			"package api", // 1
+			"",
+			"import \"example.com/shop/db\"", // 3
+			"",
+			"func Remove(q *db.Queries) error {", // 5
+			"	if _, err := q.GetAuthor(1); err != nil {", // 6
+			"		return err",
+			"	}",
+			"	return q.DeleteAuthor(1)", // 9
+			"}",
+			"",
+		},
+	})
+
+	t.Run("models", func(t *testing.T) {
+		t.Parallel()
+		report, err := MapORMModels(context.Background(), filepath.Join(workspace, "api"))
+		if err != nil {
+			t.Fatalf("Failed to map models: %v", err)
+		}
+		expected := strings.Join([]string{
+			"sqlc db.Author (db/models.go:5) -> table authors",
+			"  columns: ID id, FullName full_name",
+			"  query GetAuthor :one (db/query.sql.go:10, from query.sql)",
+			"    api/api.go:6 in Remove: q.GetAuthor",
+			"  query DeleteAuthor :exec (db/query.sql.go:16, from query.sql)",
+			"    api/api.go:9 in Remove: q.DeleteAuthor",
+			"",
+			"ent ent.Account (ent/user.go:5) -> table accounts",
+			"  schema: ent/schema/account.go:3",
+			"  columns: ID id, Login login",
+			"  used at:",
+			"    service/service.go:8 in Login: ent.Account",
+			"    service/service.go:9 in Login: account.LoginEQ",
+			"    service/service.go:10 in Login: client.Account",
+			"",
+			"gorm models.User (models/models.go:5) -> table users",
+			"  columns: ID id, CreatedAt created_at, UpdatedAt updated_at, DeletedAt deleted_at, Name name, Email email_address",
+			"  used at:",
+			"    store/store.go:10 in GetUser: First",
+			"",
+			"gorm models.Category (models/models.go:14) -> table product_categories",
+			"  columns: ID id, Name name",
+			"  used at:",
+			"    store/store.go:16 in CountCategories: Table",
+			"",
+			"gorm models.Order (models/models.go:21) -> table orders",
+			"  columns: ID id, CreatedAt created_at, UpdatedAt updated_at, DeletedAt deleted_at, UserID user_id",
+			"  used at:",
+			"    store/store.go:21 in CreateOrders: Create",
+			"",
+		}, "\n")
+		if report.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, report)
+		}
+	})
+
+	t.Run("naming", func(t *testing.T) {
+		t.Parallel()
+		for name, expected := range map[string]string{
+			"User":         "users",
+			"UserID":       "user_ids",
+			"HTTPServer":   "http_servers",
+			"Category":     "categories",
+			"Day":          "days",
+			"Address":      "addresses",
+			"OAuth2Client": "o_auth2_clients",
+		} {
+			if got := pluralize(snakeCase(name)); got != expected {
+				t.Errorf("Expected table %s for %s, got %s", expected, name, got)
+			}
+		}
+	})
+
+	t.Run("no models", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/plain\n\ngo 1.22\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "plain.go"), []byte("package plain\n\ntype User struct{ Name string }\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		report, err := MapORMModels(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("Failed to map models: %v", err)
+		}
+		if report.String() != "No GORM, ent or sqlc models found" {
+			t.Errorf("Unexpected report:\n%s", report)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// OverflowWrap breaks long lines, starting continuation lines with wrapMarker
+	OverflowWrap = "wrap"
+	// OverflowTruncate cuts long lines, ending them with truncateMarker
+	OverflowTruncate = "truncate"
+
+	wrapMarker     = "↪ "
+	truncateMarker = "…"
+)
+
+// OutputFormat normalizes the text returned by read-only tools, so code displays
+// predictably in narrow chat interfaces
+type OutputFormat struct {
+	// TabWidth expands tabs to spaces up to the next multiple of the width. Tabs are
+	// kept when zero.
+	TabWidth int
+	// MaxLineWidth limits the characters per line, unlimited when zero
+	MaxLineWidth int
+	// Overflow is how longer lines are shortened, OverflowTruncate or else OverflowWrap
+	Overflow string
+}
+
+// WithOutputFormat normalizes the tab width and line width of the text results of
+// read-only tools. Results of mutating tools are left alone, as their patches must
+// still apply, and so are JSON results.
+func WithOutputFormat(format OutputFormat) Option {
+	return func(o *serverOptions) {
+		o.outputFormat = &format
+	}
+}
+
+// outputFormatMiddleware normalizes the text content of the results of read-only tools
+func outputFormatMiddleware(format OutputFormat, costs map[string]ToolCost) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || costs[request.Params.Name].Mutating {
+				return result, err
+			}
+			for i, content := range result.Content {
+				text, ok := content.(mcp.TextContent)
+				if !ok || isJSONText(text.Text) {
+					continue
+				}
+				text.Text = format.Normalize(text.Text)
+				result.Content[i] = text
+			}
+			return result, nil
+		}
+	}
+}
+
+// isJSONText reports whether a result is a JSON object or array, whose lines are not
+// changed so it can still be parsed
+func isJSONText(text string) bool {
+	trimmed := strings.TrimSpace(text)
+	return (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed))
+}
+
+// Normalize expands the tabs of text and shortens its lines as configured
+func (format OutputFormat) Normalize(text string) string {
+	if format.TabWidth <= 0 && format.MaxLineWidth <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if format.TabWidth > 0 {
+			line = expandTabs(line, format.TabWidth)
+		}
+		if format.MaxLineWidth > 0 && utf8.RuneCountInString(line) > format.MaxLineWidth {
+			if format.Overflow == OverflowTruncate {
+				normalized = append(normalized, truncateLine(line, format.MaxLineWidth))
+				continue
+			}
+			normalized = append(normalized, wrapLine(line, format.MaxLineWidth)...)
+			continue
+		}
+		normalized = append(normalized, line)
+	}
+	return strings.Join(normalized, "\n")
+}
+
+// expandTabs replaces the tabs of a line with spaces up to the next tab stop
+func expandTabs(line string, width int) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	column := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := width - column%width
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+		b.WriteRune(r)
+		column++
+	}
+	return b.String()
+}
+
+// truncateLine cuts a line to width characters, the last being the truncation marker
+func truncateLine(line string, width int) string {
+	runes := []rune(line)
+	keep := max(width-utf8.RuneCountInString(truncateMarker), 0)
+	return string(runes[:keep]) + truncateMarker
+}
+
+// wrapLine breaks a line into lines of at most width characters, the continuation
+// lines starting with the wrap marker
+func wrapLine(line string, width int) []string {
+	runes := []rune(line)
+	// The marker takes part of the width of continuation lines, but at least one
+	// character of the line is kept per line so wrapping ends
+	continuationWidth := max(width-utf8.RuneCountInString(wrapMarker), 1)
+	lines := []string{string(runes[:width])}
+	for rest := runes[width:]; len(rest) > 0; {
+		n := min(continuationWidth, len(rest))
+		lines = append(lines, wrapMarker+string(rest[:n]))
+		rest = rest[n:]
+	}
+	return lines
+}
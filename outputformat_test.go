@@ -0,0 +1,96 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normalize", func(t *testing.T) {
+		t.Parallel()
+		for name, test := range map[string]struct {
+			format   OutputFormat
+			text     string
+			expected string
+		}{
+			"unchanged by default": {
+				format:   OutputFormat{},
+				text:     "func f() {\n\treturn\n}",
+				expected: "func f() {\n\treturn\n}",
+			},
+			"tabs to next stop": {
+				format:   OutputFormat{TabWidth: 4},
+				text:     "\tx\ty\n  \tz",
+				expected: "    x   y\n    z",
+			},
+			"wrap": {
+				format:   OutputFormat{MaxLineWidth: 8},
+				text:     "short\nabcdefghijklmnop",
+				expected: "short\nabcdefgh\n↪ ijklmn\n↪ op",
+			},
+			"truncate": {
+				format:   OutputFormat{MaxLineWidth: 8, Overflow: OverflowTruncate},
+				text:     "abcdefghijklmnop\nabcdefgh",
+				expected: "abcdefg…\nabcdefgh",
+			},
+			"width counts characters after tab expansion": {
+				format:   OutputFormat{TabWidth: 4, MaxLineWidth: 6, Overflow: OverflowTruncate},
+				text:     "\tfoo\n\tfö",
+				expected: "    f…\n    fö",
+			},
+			"narrow wrap ends": {
+				format:   OutputFormat{MaxLineWidth: 1},
+				text:     "abc",
+				expected: "a\n↪ b\n↪ c",
+			},
+		} {
+			if got := test.format.Normalize(test.text); got != test.expected {
+				t.Errorf("%s: expected %q, got %q", name, test.expected, got)
+			}
+		}
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		t.Parallel()
+		respond := func(text string) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText(text), nil
+			}
+		}
+		mcpServer := NewMCPServer(
+			WithTool(mcp.NewTool("code"), respond("func f() {\n\treturn 1234567890\n}")),
+			WithTool(mcp.NewTool("json"), respond(`{"code": "\treturn 1234567890"}`)),
+			WithOutputFormat(OutputFormat{TabWidth: 2, MaxLineWidth: 12, Overflow: OverflowTruncate}),
+		)
+
+		for tool, expected := range map[string]string{
+			"code": "func f() {\n  return 12…\n}",
+			"json": `{"code": "\treturn 1234567890"}`,
+		} {
+			encoded, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      1,
+				"method":  string(mcp.MethodToolsCall),
+				"params":  map[string]any{"name": tool, "arguments": map[string]any{}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+			text := response.Result.(mcp.CallToolResult).Content[0].(mcp.TextContent).Text
+			if text != expected {
+				t.Errorf("Expected %s result %q, got %q", tool, expected, text)
+			}
+		}
+
+		if !strings.Contains(OutputFormat{MaxLineWidth: 4}.Normalize("abcdef"), wrapMarker) {
+			t.Error("Expected long lines to wrap without an overflow mode")
+		}
+	})
+}
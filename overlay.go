@@ -0,0 +1,84 @@
+package go_mcp_tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Overlay maps absolute file paths to contents that replace the files on disk, such as
+// unsaved editor buffers. Files in the overlay do not need to exist on disk.
+type Overlay map[string][]byte
+
+// NewOverlay creates an overlay from file contents by path, resolving relative paths
+// against the workspace directory
+func NewOverlay(contents map[string]string, workspaceDir string) Overlay {
+	if len(contents) == 0 {
+		return nil
+	}
+	overlay := make(Overlay, len(contents))
+	for path, content := range contents {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workspaceDir, path)
+		}
+		overlay[filepath.Clean(path)] = []byte(content)
+	}
+	return overlay
+}
+
+// has reports whether the overlay replaces the file
+func (overlay Overlay) has(path string) bool {
+	_, ok := overlay[path]
+	return ok
+}
+
+// readFile reads a file from the overlay, falling back to the file on disk
+func (overlay Overlay) readFile(path string) ([]byte, error) {
+	if content, ok := overlay[path]; ok {
+		return content, nil
+	}
+	return os.ReadFile(path)
+}
+
+// resolveFilePath resolves a file path like the package level resolveFilePath, also
+// accepting files that only exist in the overlay
+func (overlay Overlay) resolveFilePath(filePath string, workspaceDir string) (string, error) {
+	absPath := filePath
+	if !filepath.IsAbs(absPath) {
+		absPath = filepath.Join(workspaceDir, filePath)
+	}
+	if overlay.has(filepath.Clean(absPath)) {
+		return filepath.Clean(absPath), nil
+	}
+	return resolveFilePath(filePath, workspaceDir)
+}
+
+// goplsUnavailableReason describes why gopls cannot be used for a file in the overlay, as
+// gopls only sees the files on disk. Returns an empty string for files not in the overlay.
+func (overlay Overlay) goplsUnavailableReason(path string, section string) string {
+	if !overlay.has(path) {
+		return ""
+	}
+	return fmt.Sprintf(
+		"%s are not available for %s as it has unsaved changes (overlays are not supported by gopls), save the file first",
+		section,
+		path,
+	)
+}
+
+// overlayFromRequest returns the overlay of the overlays argument of a tool call, with
+// relative paths resolved against baseDir, or nil without the argument
+func overlayFromRequest(request mcp.CallToolRequest, baseDir string) (Overlay, error) {
+	var arguments struct {
+		Overlays map[string]string `json:"overlays"`
+	}
+	if err := request.BindArguments(&arguments); err != nil {
+		return nil, fmt.Errorf("the overlays must map file paths to their content as strings: %w", err)
+	}
+	if arguments.Overlays == nil {
+		return nil, nil
+	}
+	return NewOverlay(arguments.Overlays, baseDir), nil
+}
@@ -0,0 +1,119 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestOverlay(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a test workspace with a saved main.go
+	createTestWorkspace := func(t testing.TB) string {
+		tempDir := t.TempDir()
+		lines := []string{
+			"package testpkg",       // 1
+			"",                      // 2
+			"func Saved() string {", // 3
+			"    return \"saved\"",  // 4
+			"}",                     // 5
+		}
+		err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte(strings.Join(lines, "\n")), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tempDir
+	}
+
+	unsavedLines := []string{
+		"package testpkg",         // 1
+		"",                        // 2
+		"// Unsaved is new",       // 3
+		"func Unsaved() string {", // 4
+		"    return \"unsaved\"",  // 5
+		"}",                       // 6
+	}
+
+	t.Run("overlay replaces the file on disk", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		options := DefaultInspectOptions(workspace)
+		options.SymbolName = "Unsaved"
+		options.IncludeBody = true
+		options.Overlay = NewOverlay(map[string]string{"main.go": strings.Join(unsavedLines, "\n")}, workspace)
+		result, err := InspectStructured(context.Background(), mainFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect overlaid file: %v", err)
+		}
+		symbol := result.Symbol
+		if symbol.StartLine != 4 || symbol.Doc != "Unsaved is new" || !strings.Contains(symbol.Code, `return "unsaved"`) {
+			t.Errorf("Expected the function from the overlay, got %+v", symbol)
+		}
+		if len(symbol.References) != 1 || !strings.Contains(symbol.References[0].Error, "unsaved changes") {
+			t.Errorf("Expected references to be unavailable for the overlaid file, got %+v", symbol.References)
+		}
+
+		// The cached overlay parse must not be used without the overlay
+		options.Overlay = nil
+		if _, err := InspectStructured(context.Background(), mainFile, options); err == nil {
+			t.Errorf("Expected Unsaved not to be found on disk")
+		}
+		options.SymbolName = "Saved"
+		if _, err := InspectStructured(context.Background(), mainFile, options); err != nil {
+			t.Errorf("Expected Saved to be found on disk: %v", err)
+		}
+	})
+
+	t.Run("files only in the overlay", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		newFile := filepath.Join(workspace, "new.go")
+
+		options := DefaultInspectOptions(workspace)
+		options.Overlay = Overlay{newFile: []byte(strings.Join(unsavedLines, "\n"))}
+		result, err := InspectStructured(context.Background(), newFile, options)
+		if err != nil {
+			t.Fatalf("Failed to inspect unsaved file: %v", err)
+		}
+		if len(result.File.Symbols) != 1 || result.File.Symbols[0].Code != "func Unsaved() string" {
+			t.Errorf("Expected the function of the unsaved file, got %+v", result.File.Symbols)
+		}
+	})
+
+	t.Run("inspect tool accepts overlays", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name": inspectToolName,
+				"arguments": map[string]any{
+					"path":               filepath.Join(workspace, "main.go"),
+					"workspace_dir":      workspace,
+					"include_references": false,
+					"overlays":           map[string]any{"main.go": strings.Join(unsavedLines, "\n")},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		if result.IsError || !strings.Contains(text, "func Unsaved() string") || strings.Contains(text, "Saved()") {
+			t.Errorf("Expected inspect output of the overlay, got: %s", text)
+		}
+	})
+}
@@ -0,0 +1,291 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	overviewToolName        = "package_overview"
+	overviewToolDescription = `Drafts a markdown overview of a Go package for its README or package documentation: the purpose from the package doc comment, the exported types and functions with the first sentence of their doc comments, and a usage sketch assembled from the examples or, without examples, from the tests of the package.
+
+The draft marks missing documentation with TODO notes, review and complete it before publishing.`
+)
+
+// overviewUsageLines limits the length of usage snippets taken from tests
+const overviewUsageLines = 20
+
+func AddOverviewTool(mcpServer *server.MCPServer) {
+	handleOverview := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		dir, err := request.RequireString("path")
+		if err != nil {
+			return nil, err
+		}
+
+		overview, err := PackageOverview(dir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error generating package overview: %v", err)), nil
+		}
+		return mcp.NewToolResultText(overview), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		overviewToolName,
+		mcp.WithDescription(overviewToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of the package directory"),
+			mcp.Required(),
+		),
+	), handleOverview)
+}
+
+// PackageOverview drafts a markdown overview of the package in dir from its doc
+// comments, exported API, examples and tests
+func PackageOverview(dir string) (string, error) {
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("path must be an absolute directory path, got: %s", dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read package directory: %w", err)
+	}
+
+	// go/doc needs all files in one file set, so they are parsed here instead of from the file cache
+	fset := token.NewFileSet()
+	var files, testFiles []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, parser.ParseComments)
+		if err != nil {
+			return "", classifyErrorf(ErrSyntaxErrors, "failed to parse %s: %w", entry.Name(), err)
+		}
+		if strings.HasSuffix(entry.Name(), "_test.go") {
+			testFiles = append(testFiles, file)
+		} else {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no Go files found in %s", dir)
+	}
+
+	importPath := packageImportPath(dir)
+	// The syntax trees are preserved, as the tests are read after the documentation
+	pkg, err := doc.NewFromFiles(fset, append(slices.Clone(files), testFiles...), importPath, doc.PreserveAST)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the package documentation: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", pkg.Name)
+	if importPath != "" && pkg.Name != "main" {
+		fmt.Fprintf(&b, "```go\nimport %q\n```\n\n", importPath)
+	}
+	if text := strings.TrimSpace(pkg.Doc); text != "" {
+		b.WriteString(text + "\n\n")
+	} else {
+		b.WriteString("TODO: describe the purpose of the package, it has no package doc comment.\n\n")
+	}
+
+	writeOverviewAPI(&b, fset, pkg)
+
+	b.WriteString("## Usage\n\n")
+	examples := overviewExamples(pkg)
+	if len(examples) > 0 {
+		for _, example := range examples {
+			writeOverviewSnippet(&b, fset, "Example"+example.Name, example.Code)
+		}
+	} else if tests := overviewTests(pkg, testFiles); len(tests) > 0 {
+		b.WriteString("Assembled from the tests, as the package has no examples:\n\n")
+		for _, test := range tests {
+			writeOverviewSnippet(&b, fset, test.Name.Name, test.Body)
+		}
+	} else {
+		b.WriteString("TODO: add a usage example, the package has no examples or tests using its exported API.\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n", nil
+}
+
+// packageImportPath returns the import path of the package in dir from the enclosing
+// go.mod, or an empty string outside of modules
+func packageImportPath(dir string) string {
+	root, err := findModuleRoot(dir)
+	if err != nil {
+		return ""
+	}
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	modulePath := modfile.ModulePath(content)
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || modulePath == "" {
+		return ""
+	}
+	if rel == "." {
+		return modulePath
+	}
+	return path.Join(modulePath, filepath.ToSlash(rel))
+}
+
+// writeOverviewAPI writes the exported types and functions with their synopsis
+func writeOverviewAPI(b *strings.Builder, fset *token.FileSet, pkg *doc.Package) {
+	synopsis := func(text string) string {
+		if text = pkg.Synopsis(text); text != "" {
+			return " - " + text
+		}
+		return " - TODO: document"
+	}
+	signature := func(decl *ast.FuncDecl) string {
+		// Print the declaration without its body
+		stripped := *decl
+		stripped.Body = nil
+		stripped.Doc = nil
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, &stripped); err != nil {
+			return decl.Name.Name
+		}
+		return buf.String()
+	}
+
+	if len(pkg.Types) > 0 {
+		b.WriteString("## Types\n\n")
+		for _, typ := range pkg.Types {
+			fmt.Fprintf(b, "- `%s`%s\n", typ.Name, synopsis(typ.Doc))
+			for _, fn := range typ.Funcs {
+				fmt.Fprintf(b, "  - `%s`%s\n", signature(fn.Decl), synopsis(fn.Doc))
+			}
+			for _, method := range typ.Methods {
+				fmt.Fprintf(b, "  - `%s`%s\n", signature(method.Decl), synopsis(method.Doc))
+			}
+		}
+		b.WriteString("\n")
+	}
+	if len(pkg.Funcs) > 0 {
+		b.WriteString("## Functions\n\n")
+		for _, fn := range pkg.Funcs {
+			fmt.Fprintf(b, "- `%s`%s\n", signature(fn.Decl), synopsis(fn.Doc))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// overviewExamples returns all examples of the package, the package level ones first
+func overviewExamples(pkg *doc.Package) []*doc.Example {
+	examples := slices.Clone(pkg.Examples)
+	for _, typ := range pkg.Types {
+		examples = append(examples, typ.Examples...)
+		for _, fn := range typ.Funcs {
+			examples = append(examples, fn.Examples...)
+		}
+		for _, method := range typ.Methods {
+			examples = append(examples, method.Examples...)
+		}
+	}
+	for _, fn := range pkg.Funcs {
+		examples = append(examples, fn.Examples...)
+	}
+	return examples
+}
+
+// overviewTests returns up to two tests calling the most exported functions of the package
+func overviewTests(pkg *doc.Package, testFiles []*ast.File) []*ast.FuncDecl {
+	exported := make(map[string]bool)
+	for _, fn := range pkg.Funcs {
+		exported[fn.Name] = true
+	}
+	for _, typ := range pkg.Types {
+		for _, fn := range typ.Funcs {
+			exported[fn.Name] = true
+		}
+		for _, method := range typ.Methods {
+			exported[method.Name] = true
+		}
+	}
+
+	type scoredTest struct {
+		decl  *ast.FuncDecl
+		calls int
+	}
+	var tests []scoredTest
+	for _, file := range testFiles {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+				continue
+			}
+			calls := 0
+			ast.Inspect(fn.Body, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				switch fun := call.Fun.(type) {
+				case *ast.Ident:
+					if exported[fun.Name] {
+						calls++
+					}
+				case *ast.SelectorExpr:
+					if exported[fun.Sel.Name] {
+						calls++
+					}
+				}
+				return true
+			})
+			if calls > 0 {
+				tests = append(tests, scoredTest{decl: fn, calls: calls})
+			}
+		}
+	}
+	slices.SortStableFunc(tests, func(a, b scoredTest) int {
+		return b.calls - a.calls
+	})
+
+	var result []*ast.FuncDecl
+	for _, test := range tests[:min(2, len(tests))] {
+		result = append(result, test.decl)
+	}
+	return result
+}
+
+// writeOverviewSnippet writes the statements of a code block as a titled Go snippet
+func writeOverviewSnippet(b *strings.Builder, fset *token.FileSet, title string, code ast.Node) {
+	var buf bytes.Buffer
+	if block, ok := code.(*ast.BlockStmt); ok {
+		for _, stmt := range block.List {
+			if err := format.Node(&buf, fset, stmt); err != nil {
+				continue
+			}
+			buf.WriteString("\n")
+		}
+	} else if err := format.Node(&buf, fset, code); err != nil {
+		return
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) > overviewUsageLines {
+		lines = append(lines[:overviewUsageLines], "// ...")
+	}
+	fmt.Fprintf(b, "### %s\n\n```go\n%s\n```\n\n", title, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,138 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPackageOverview(t *testing.T) {
+	t.Parallel()
+
+	libLines := []string{
+		"// Package cache stores values in memory.", // 1
+		"package cache",                  // 2
+		"",                               // 3
+		"// Cache holds values by key.",  // 4
+		"type Cache struct {",            // 5
+		"    values map[string]string",   // 6
+		"}",                              // 7
+		"",                               // 8
+		"// New creates an empty cache.", // 9
+		"func New() *Cache {",            // 10
+		"    return &Cache{values: map[string]string{}}", // 11
+		"}", // 12
+		"",  // 13
+		"// Get returns the value of key. It is empty for missing keys.", // 14
+		"func (c *Cache) Get(key string) string {",                       // 15
+		"    return c.values[key]",                                       // 16
+		"}",                                                              // 17
+		"",                                                               // 18
+		"func (c *Cache) Set(key, value string) {", // 19
+		"    c.values[key] = value",                // 20
+		"}",                                        // 21
+	}
+
+	// Helper function to create a module with the cache package and the given test file
+	createTestWorkspace := func(t testing.TB, testLines []string) string {
+		tempDir := t.TempDir()
+		files := map[string][]string{
+			"go.mod":         {"module example.com/project", "", "go 1.21", ""},
+			"cache/cache.go": libLines,
+		}
+		if testLines != nil {
+			files["cache/cache_test.go"] = testLines
+		}
+		writeTestFiles(t, tempDir, files)
+		return filepath.Join(tempDir, "cache")
+	}
+
+	t.Run("examples", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t, []string{
+			"package cache_test",                   // 1
+			"",                                     // 2
+			"import \"example.com/project/cache\"", // 3
+			"",                                     // 4
+			"func ExampleCache_Get() {",            // 5
+			"    c := cache.New()",                 // 6
+			"    c.Set(\"a\", \"b\")",              // 7
+			"    println(c.Get(\"a\"))",            // 8
+			"}",                                    // 9
+		})
+
+		overview, err := PackageOverview(dir)
+		if err != nil {
+			t.Fatalf("Failed to generate overview: %v", err)
+		}
+		for _, expected := range []string{
+			"# cache\n\n```go\nimport \"example.com/project/cache\"\n```\n\nPackage cache stores values in memory.\n",
+			"- `Cache` - Cache holds values by key.\n",
+			"  - `func New() *Cache` - New creates an empty cache.\n",
+			"  - `func (c *Cache) Get(key string) string` - Get returns the value of key.\n",
+			"  - `func (c *Cache) Set(key, value string)` - TODO: document\n",
+			"### ExampleCache_Get\n\n```go\nc := cache.New()\nc.Set(\"a\", \"b\")\nprintln(c.Get(\"a\"))\n```\n",
+		} {
+			if !strings.Contains(overview, expected) {
+				t.Errorf("Expected %q in overview, got:\n%s", expected, overview)
+			}
+		}
+	})
+
+	t.Run("usage from tests", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t, []string{
+			"package cache",                       // 1
+			"",                                    // 2
+			"import \"testing\"",                  // 3
+			"",                                    // 4
+			"func TestEmpty(t *testing.T) {",      // 5
+			"    if New().Get(\"a\") != \"\" {",   // 6
+			"        t.Fatal(\"not empty\")",      // 7
+			"    }",                               // 8
+			"}",                                   // 9
+			"",                                    // 10
+			"func TestHelperOnly(t *testing.T) {", // 11
+			"    t.Log(\"nothing\")",              // 12
+			"}",                                   // 13
+		})
+
+		overview, err := PackageOverview(dir)
+		if err != nil {
+			t.Fatalf("Failed to generate overview: %v", err)
+		}
+		if !strings.Contains(overview, "Assembled from the tests") || !strings.Contains(overview, "### TestEmpty\n") {
+			t.Errorf("Expected usage from TestEmpty, got:\n%s", overview)
+		}
+		if strings.Contains(overview, "TestHelperOnly") {
+			t.Errorf("Expected tests not using the package to be skipped, got:\n%s", overview)
+		}
+	})
+
+	t.Run("overview tool", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t, nil)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      overviewToolName,
+				"arguments": map[string]any{"path": dir},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "TODO: add a usage example") {
+			t.Errorf("Expected an overview without usage, got: %s", text)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package go_mcp_tools
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// packageMetadataCacheVersion is part of every cache key, so entries of older formats are ignored
+const packageMetadataCacheVersion = 1
+
+// WithPackageMetadataCache caches the metadata of the packages loaded by the inspect tool
+// on disk in dir, even across server restarts: their names, module and Go files. Listing
+// a cached package again only parses its files, without running go list or type checking
+// it. Types are not cached, so inspections of types needing their method sets still load
+// the package. Entries are invalidated when the files of the package, its directory
+// listing or the go.mod and go.sum of the workspace change.
+func WithPackageMetadataCache(dir string) Option {
+	return func(o *serverOptions) {
+		o.metadataCacheDir = dir
+	}
+}
+
+// packageMetadataEntry is the metadata of a loaded package stored on disk. The syntax is
+// parsed again from the files, which is cheap compared to loading and type checking, and
+// there is no export data, type information or symbol index.
+type packageMetadataEntry struct {
+	Fingerprint string   `json:"fingerprint"`
+	Name        string   `json:"name"`
+	PkgPath     string   `json:"pkg_path"`
+	ModulePath  string   `json:"module_path,omitempty"`
+	ModuleDir   string   `json:"module_dir,omitempty"`
+	GoFiles     []string `json:"go_files"`
+}
+
+// packageMetadataCache stores the metadata of loaded packages in a directory, one file per
+// package and workspace
+type packageMetadataCache struct {
+	dir string
+}
+
+// entryPath returns the file of the cache entry for the package pattern loaded from workspaceDir
+func (cache packageMetadataCache) entryPath(pkgPath string, workspaceDir string) string {
+	key := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s", packageMetadataCacheVersion, pkgPath, workspaceDir)))
+	return filepath.Join(cache.dir, fmt.Sprintf("%x.json", key))
+}
+
+// load returns the cached package with its syntax but without types, or false if it is
+// not cached or outdated
+func (cache packageMetadataCache) load(pkgPath string, workspaceDir string) (*packages.Package, bool) {
+	content, err := os.ReadFile(cache.entryPath(pkgPath, workspaceDir))
+	if err != nil {
+		return nil, false
+	}
+	var entry packageMetadataEntry
+	if err := json.Unmarshal(content, &entry); err != nil || len(entry.GoFiles) == 0 {
+		return nil, false
+	}
+	fingerprint, err := packageFingerprint(workspaceDir, entry.GoFiles)
+	if err != nil || fingerprint != entry.Fingerprint {
+		return nil, false
+	}
+
+	pkg := &packages.Package{
+		ID:              entry.PkgPath,
+		Name:            entry.Name,
+		PkgPath:         entry.PkgPath,
+		GoFiles:         entry.GoFiles,
+		CompiledGoFiles: entry.GoFiles,
+		Fset:            token.NewFileSet(),
+	}
+	if entry.ModulePath != "" {
+		pkg.Module = &packages.Module{Path: entry.ModulePath, Dir: entry.ModuleDir}
+	}
+	for _, goFile := range entry.GoFiles {
+		file, err := parser.ParseFile(pkg.Fset, goFile, nil, parser.ParseComments)
+		if err != nil {
+			return nil, false
+		}
+		pkg.Syntax = append(pkg.Syntax, file)
+	}
+	return pkg, true
+}
+
+// store writes the package to the cache
+func (cache packageMetadataCache) store(pkgPath string, workspaceDir string, pkg *packages.Package) error {
+	if len(pkg.GoFiles) == 0 {
+		return fmt.Errorf("package %s has no Go files to cache", pkg.PkgPath)
+	}
+	fingerprint, err := packageFingerprint(workspaceDir, pkg.GoFiles)
+	if err != nil {
+		return err
+	}
+	entry := packageMetadataEntry{
+		Fingerprint: fingerprint,
+		Name:        pkg.Name,
+		PkgPath:     pkg.PkgPath,
+		GoFiles:     pkg.GoFiles,
+	}
+	if pkg.Module != nil {
+		entry.ModulePath = pkg.Module.Path
+		entry.ModuleDir = pkg.Module.Dir
+	}
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Entries are written to a temporary file and renamed, so concurrent readers never see partial entries
+	if err := os.MkdirAll(cache.dir, 0755); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(cache.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), cache.entryPath(pkgPath, workspaceDir))
+}
+
+// packageFingerprint hashes everything a cached package depends on: the contents of its
+// files, the Go files in their directories, which catches added and removed files, and
+// the go.mod and go.sum of the module containing the workspace
+func packageFingerprint(workspaceDir string, goFiles []string) (string, error) {
+	hash := sha256.New()
+	dirs := make(map[string]bool)
+	for _, goFile := range goFiles {
+		content, err := os.ReadFile(goFile)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "file %s %x\n", goFile, sha256.Sum256(content))
+		dirs[filepath.Dir(goFile)] = true
+	}
+
+	sortedDirs := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sortedDirs = append(sortedDirs, dir)
+	}
+	slices.Sort(sortedDirs)
+	for _, dir := range sortedDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".go") {
+				fmt.Fprintf(hash, "listing %s %s\n", dir, entry.Name())
+			}
+		}
+	}
+
+	for dir := workspaceDir; ; dir = filepath.Dir(dir) {
+		content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			fmt.Fprintf(hash, "go.mod %s %x\n", dir, sha256.Sum256(content))
+			// go.sum is missing in modules without dependencies
+			if sum, err := os.ReadFile(filepath.Join(dir, "go.sum")); err == nil {
+				fmt.Fprintf(hash, "go.sum %x\n", sha256.Sum256(sum))
+			}
+			break
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,100 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestPackageMetadataCache(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a test module with one package file
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module testmodule", "", "go 1.21", ""},
+			"main.go": {
+				"package testpkg",       // 1
+				"",                      // 2
+				"func Hello() string {", // 3
+				"    return \"hello\"",  // 4
+				"}",                     // 5
+			},
+		})
+	}
+
+	// Helper function to store the package of the workspace like after packages.Load
+	storePackage := func(t testing.TB, cache packageMetadataCache, workspace string) {
+		pkg := &packages.Package{
+			Name:    "testpkg",
+			PkgPath: "testmodule",
+			GoFiles: []string{filepath.Join(workspace, "main.go")},
+			Module:  &packages.Module{Path: "testmodule", Dir: workspace},
+		}
+		if err := cache.store(workspace, workspace, pkg); err != nil {
+			t.Fatalf("Failed to store package: %v", err)
+		}
+	}
+
+	t.Run("cached packages are parsed from their files", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		cache := packageMetadataCache{dir: t.TempDir()}
+		storePackage(t, cache, workspace)
+
+		// A cache hit does not load the package with the go command
+		pkg, err := loadPackage(context.Background(), workspace, workspace, nil, cache.dir)
+		if err != nil {
+			t.Fatalf("Failed to load cached package: %v", err)
+		}
+		if pkg.PkgPath != "testmodule" || pkg.Module.Dir != workspace || len(pkg.Syntax) != 1 {
+			t.Fatalf("Expected the cached package with its syntax, got %+v", pkg)
+		}
+		info := newPackageInfo(pkg)
+		file := newFileInfo(context.Background(), pkg.Syntax[0], pkg.Fset, true, false, DetailSignature, workspace, nil)
+		if info.Directory != workspace || file.Symbols[0].Name != "Hello" {
+			t.Errorf("Expected package info of the cached package, got %+v and %+v", info, file)
+		}
+	})
+
+	t.Run("changes invalidate entries", func(t *testing.T) {
+		t.Parallel()
+		for name, change := range map[string]func(workspace string) error{
+			"file content": func(workspace string) error {
+				return os.WriteFile(filepath.Join(workspace, "main.go"), []byte("package testpkg\n"), 0644)
+			},
+			"added file": func(workspace string) error {
+				return os.WriteFile(filepath.Join(workspace, "other.go"), []byte("package testpkg\n"), 0644)
+			},
+			"go.mod": func(workspace string) error {
+				return os.WriteFile(filepath.Join(workspace, "go.mod"), []byte("module testmodule\n\ngo 1.22\n"), 0644)
+			},
+		} {
+			workspace := createTestWorkspace(t)
+			cache := packageMetadataCache{dir: t.TempDir()}
+			storePackage(t, cache, workspace)
+			if _, ok := cache.load(workspace, workspace); !ok {
+				t.Fatalf("%s: Expected a cache hit before the change", name)
+			}
+			if err := change(workspace); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := cache.load(workspace, workspace); ok {
+				t.Errorf("%s: Expected the change to invalidate the entry", name)
+			}
+		}
+	})
+
+	t.Run("entries are keyed by workspace", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		cache := packageMetadataCache{dir: t.TempDir()}
+		storePackage(t, cache, workspace)
+		if _, ok := cache.load(workspace, t.TempDir()); ok {
+			t.Errorf("Expected no cache hit from another workspace")
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	packageGraphToolName        = "package_graph"
+	packageGraphToolDescription = `Draws the import graph of the packages of a Go module as a Graphviz DOT or Mermaid diagram, to include architecture diagrams in answers or documentation. Nodes are the package directories relative to the module root and edges point from importing to imported packages. Packages importing each other in a cycle, through several packages or through collapsed nodes, are highlighted in red and listed in comments.
+
+Large modules can be simplified by collapsing: every package under a directory of collapse becomes a single node, and depth collapses all packages into their ancestor directory at that depth, e.g. depth 1 draws one node per top level directory.`
+)
+
+// PackageGraphOptions configures the package graph
+type PackageGraphOptions struct {
+	// Collapse lists directories relative to the module root whose packages are drawn
+	// as a single node
+	Collapse []string
+	// Depth collapses packages into their ancestor directory at this depth, none when zero
+	Depth int
+}
+
+// PackageGraph is the import graph of the packages of a module
+type PackageGraph struct {
+	Module string `json:"module"`
+	// Nodes are sorted by name
+	Nodes []PackageGraphNode `json:"nodes"`
+	// Edges are sorted by importing and imported node
+	Edges []PackageGraphEdge `json:"edges"`
+	// Cycles are the groups of nodes importing each other, each sorted by name
+	Cycles [][]string `json:"cycles,omitempty"`
+}
+
+// PackageGraphNode is a package, or the packages collapsed into a directory
+type PackageGraphNode struct {
+	// Name is the directory relative to the module root, the module path for the root
+	Name     string `json:"name"`
+	Packages int    `json:"packages"`
+	InCycle  bool   `json:"in_cycle,omitempty"`
+}
+
+// PackageGraphEdge is an import between nodes
+type PackageGraphEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	InCycle bool   `json:"in_cycle,omitempty"`
+}
+
+func AddPackageGraphTool(mcpServer *server.MCPServer) {
+	handlePackageGraph := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		format := request.GetString("format", "")
+		if format != "" && format != "dot" && format != "mermaid" {
+			return toolErrorResult(fmt.Sprintf("Error: unknown format %q, use dot or mermaid", format)), nil
+		}
+		var options PackageGraphOptions
+		options.Collapse = request.GetStringSlice("collapse", nil)
+		options.Depth = request.GetInt("depth", options.Depth)
+
+		graph, err := BuildPackageGraph(workspaceDir, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error building package graph: %v", err)), nil
+		}
+		if format == "mermaid" {
+			return mcp.NewToolResultText(graph.Mermaid()), nil
+		}
+		return mcp.NewToolResultText(graph.DOT()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		packageGraphToolName,
+		mcp.WithDescription(packageGraphToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to draw"),
+			mcp.Required(),
+		),
+		mcp.WithString("format",
+			mcp.Description("Diagram format"),
+			mcp.Enum("dot", "mermaid"),
+			mcp.DefaultString("dot"),
+		),
+		mcp.WithArray("collapse",
+			mcp.Description("Directories relative to the module root whose packages are drawn as a single node, e.g. internal/storage"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("Collapse packages into their ancestor directory at this depth, 0 to draw every package"),
+			mcp.DefaultNumber(0),
+		),
+	), handlePackageGraph)
+}
+
+// BuildPackageGraph returns the import graph of the non-test packages of the module
+// containing workspaceDir, collapsed as configured, with its import cycles
+func BuildPackageGraph(workspaceDir string, options PackageGraphOptions) (*PackageGraph, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	if options.Depth < 0 {
+		return nil, fmt.Errorf("depth must not be negative, got %d", options.Depth)
+	}
+	module, err := loadModuleSources(workspaceDir, false)
+	if err != nil {
+		return nil, err
+	}
+	var collapse []string
+	for _, dir := range options.Collapse {
+		collapse = append(collapse, strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/"))
+	}
+
+	// nodeName returns the node a package is drawn in
+	nodeName := func(pkg *packageSources) string {
+		rel := filepath.ToSlash(module.relPath(pkg.dir))
+		// The longest matching directory wins, so nested directories can be collapsed separately
+		longest := ""
+		for _, dir := range collapse {
+			if (rel == dir || strings.HasPrefix(rel, dir+"/")) && len(dir) > len(longest) {
+				longest = dir
+			}
+		}
+		if longest != "" {
+			rel = longest
+		} else if parts := strings.Split(rel, "/"); options.Depth > 0 && len(parts) > options.Depth {
+			rel = strings.Join(parts[:options.Depth], "/")
+		}
+		if rel == "." || rel == "" {
+			return module.path
+		}
+		return rel
+	}
+
+	graph := &PackageGraph{Module: module.path}
+	packageCounts := make(map[string]int)
+	imports := make(map[string]map[string]bool)
+	for _, pkg := range module.packages {
+		from := nodeName(pkg)
+		packageCounts[from]++
+		if imports[from] == nil {
+			imports[from] = make(map[string]bool)
+		}
+		for _, file := range pkg.files {
+			for _, spec := range file.ast.Imports {
+				imported := module.packageByImportPath(strings.Trim(spec.Path.Value, "\"`"))
+				// Imports within a collapsed node are not drawn
+				if imported != nil && nodeName(imported) != from {
+					imports[from][nodeName(imported)] = true
+				}
+			}
+		}
+	}
+
+	inCycle := make(map[string]bool)
+	for _, component := range stronglyConnected(imports) {
+		if len(component) < 2 {
+			continue
+		}
+		slices.Sort(component)
+		graph.Cycles = append(graph.Cycles, component)
+		for _, name := range component {
+			inCycle[name] = true
+		}
+	}
+	slices.SortFunc(graph.Cycles, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+	component := make(map[string]int)
+	for i, cycle := range graph.Cycles {
+		for _, name := range cycle {
+			component[name] = i + 1
+		}
+	}
+
+	for name, count := range packageCounts {
+		graph.Nodes = append(graph.Nodes, PackageGraphNode{Name: name, Packages: count, InCycle: inCycle[name]})
+		for to := range imports[name] {
+			graph.Edges = append(graph.Edges, PackageGraphEdge{
+				From:    name,
+				To:      to,
+				InCycle: inCycle[name] && component[name] == component[to],
+			})
+		}
+	}
+	slices.SortFunc(graph.Nodes, func(a, b PackageGraphNode) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(graph.Edges, func(a, b PackageGraphEdge) int {
+		if a.From != b.From {
+			return strings.Compare(a.From, b.From)
+		}
+		return strings.Compare(a.To, b.To)
+	})
+	return graph, nil
+}
+
+// stronglyConnected returns the strongly connected components of a directed graph
+// using Tarjan's algorithm, visiting nodes in sorted order for stable results
+func stronglyConnected(edges map[string]map[string]bool) [][]string {
+	var names []string
+	for name := range edges {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	var visit func(name string)
+	visit = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		var targets []string
+		for target := range edges[name] {
+			targets = append(targets, target)
+		}
+		slices.Sort(targets)
+		for _, target := range targets {
+			if _, visited := index[target]; !visited {
+				visit(target)
+				lowLink[name] = min(lowLink[name], lowLink[target])
+			} else if onStack[target] {
+				lowLink[name] = min(lowLink[name], index[target])
+			}
+		}
+
+		if lowLink[name] == index[name] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == name {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			visit(name)
+		}
+	}
+	return components
+}
+
+// label describes a node, with the number of packages of collapsed nodes
+func (node PackageGraphNode) label() string {
+	if node.Packages > 1 {
+		return fmt.Sprintf("%s (%d packages)", node.Name, node.Packages)
+	}
+	return node.Name
+}
+
+// DOT renders the graph in the Graphviz DOT language
+func (graph *PackageGraph) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", graph.Module)
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, cycle := range graph.Cycles {
+		fmt.Fprintf(&b, "  // Import cycle: %s\n", strings.Join(cycle, ", "))
+	}
+	for _, node := range graph.Nodes {
+		var attributes []string
+		if node.Packages > 1 {
+			attributes = append(attributes, fmt.Sprintf("label=%q", node.label()), "style=dashed")
+		}
+		if node.InCycle {
+			attributes = append(attributes, "color=red")
+		}
+		if len(attributes) > 0 {
+			fmt.Fprintf(&b, "  %q [%s];\n", node.Name, strings.Join(attributes, ", "))
+		} else {
+			fmt.Fprintf(&b, "  %q;\n", node.Name)
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.InCycle {
+			fmt.Fprintf(&b, "  %q -> %q [color=red];\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Nodes get generated identifiers, as
+// package paths are not valid Mermaid identifiers.
+func (graph *PackageGraph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, cycle := range graph.Cycles {
+		fmt.Fprintf(&b, "  %%%% Import cycle: %s\n", strings.Join(cycle, ", "))
+	}
+	ids := make(map[string]string, len(graph.Nodes))
+	var cycleNodes []string
+	for i, node := range graph.Nodes {
+		ids[node.Name] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[node.Name], strings.ReplaceAll(node.label(), `"`, "#quot;"))
+		if node.InCycle {
+			cycleNodes = append(cycleNodes, ids[node.Name])
+		}
+	}
+	var cycleEdges []string
+	for i, edge := range graph.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		if edge.InCycle {
+			cycleEdges = append(cycleEdges, fmt.Sprint(i))
+		}
+	}
+	if len(cycleNodes) > 0 {
+		b.WriteString("  classDef cycle stroke:#d00,stroke-width:2px\n")
+		fmt.Fprintf(&b, "  class %s cycle\n", strings.Join(cycleNodes, ","))
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#d00\n", strings.Join(cycleEdges, ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPackageGraph(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module where the storage packages import each other
+	// through their subpackages, a cycle only visible once they are collapsed
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",
+				"",
+				"import (",
+				"	\"example.com/app/internal/api\"",
+				"	\"example.com/app/internal/storage/sql\"",
+				")",
+				"",
+				"func main() { api.Serve(sql.Open()) }",
+				"",
+			},
+			"internal/api/api.go": {
+				"package api",
+				"",
+				"import \"example.com/app/internal/storage\"",
+				"",
+				"func Serve(store storage.Store) {}",
+				"",
+			},
+			"internal/storage/storage.go": {
+				"package storage",
+				"",
+				"import \"example.com/app/internal/model\"",
+				"",
+				"type Store interface{ Get() model.Item }",
+				"",
+			},
+			"internal/storage/sql/sql.go": {
+				"package sql",
+				"",
+				"import \"example.com/app/internal/storage\"",
+				"",
+				"func Open() storage.Store { return nil }",
+				"",
+			},
+			"internal/model/model.go": {
+				"package model",
+				"",
+				"import \"example.com/app/internal/model/validate\"",
+				"",
+				"type Item struct{}",
+				"",
+				"var _ = validate.Check",
+				"",
+			},
+			"internal/model/validate/validate.go": {
+				"package validate",
+				"",
+				"func Check() {}",
+				"",
+			},
+		})
+	}
+
+	t.Run("packages", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		graph, err := BuildPackageGraph(workspace, PackageGraphOptions{})
+		if err != nil {
+			t.Fatalf("Failed to build package graph: %v", err)
+		}
+		if len(graph.Nodes) != 6 || len(graph.Cycles) != 0 {
+			t.Errorf("Expected 6 packages without cycles, got %+v", graph)
+		}
+		dot := graph.DOT()
+		for _, expected := range []string{
+			"digraph \"example.com/app\" {",
+			"  \"example.com/app\" -> \"internal/api\";\n",
+			"  \"internal/storage/sql\" -> \"internal/storage\";\n",
+			"  \"internal/model\" -> \"internal/model/validate\";\n",
+		} {
+			if !strings.Contains(dot, expected) {
+				t.Errorf("Expected DOT to contain %q, got:\n%s", expected, dot)
+			}
+		}
+		if strings.Contains(dot, "red") {
+			t.Errorf("Expected no highlighted cycles, got:\n%s", dot)
+		}
+	})
+
+	t.Run("collapsing", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		graph, err := BuildPackageGraph(workspace, PackageGraphOptions{Collapse: []string{"internal/model"}, Depth: 2})
+		if err != nil {
+			t.Fatalf("Failed to build package graph: %v", err)
+		}
+		var names []string
+		for _, node := range graph.Nodes {
+			names = append(names, node.Name)
+		}
+		if strings.Join(names, ",") != "example.com/app,internal/api,internal/model,internal/storage" {
+			t.Errorf("Expected collapsed nodes, got %v", names)
+		}
+		dot := graph.DOT()
+		for _, expected := range []string{
+			"  \"internal/model\" [label=\"internal/model (2 packages)\", style=dashed];\n",
+			"  \"example.com/app\" -> \"internal/storage\";\n",
+		} {
+			if !strings.Contains(dot, expected) {
+				t.Errorf("Expected DOT to contain %q, got:\n%s", expected, dot)
+			}
+		}
+		// Imports within a collapsed node are not drawn
+		if strings.Contains(dot, "\"internal/storage\" -> \"internal/storage\"") {
+			t.Errorf("Expected no self imports, got:\n%s", dot)
+		}
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		// The validation package importing the storage package closes a cycle between
+		// the collapsed model and storage nodes
+		err := os.WriteFile(
+			filepath.Join(workspace, "internal", "model", "validate", "validate.go"),
+			[]byte("package validate\n\nimport _ \"example.com/app/internal/storage/sql\"\n\nfunc Check() {}\n"),
+			0644,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		graph, err := BuildPackageGraph(workspace, PackageGraphOptions{Depth: 2})
+		if err != nil {
+			t.Fatalf("Failed to build package graph: %v", err)
+		}
+		if len(graph.Cycles) != 1 || strings.Join(graph.Cycles[0], ",") != "internal/model,internal/storage" {
+			t.Fatalf("Expected a cycle between model and storage, got %v", graph.Cycles)
+		}
+		dot := graph.DOT()
+		for _, expected := range []string{
+			"  // Import cycle: internal/model, internal/storage\n",
+			"  \"internal/model\" [label=\"internal/model (2 packages)\", style=dashed, color=red];\n",
+			"  \"internal/model\" -> \"internal/storage\" [color=red];\n",
+			"  \"internal/storage\" -> \"internal/model\" [color=red];\n",
+			"  \"internal/api\" -> \"internal/storage\";\n",
+		} {
+			if !strings.Contains(dot, expected) {
+				t.Errorf("Expected DOT to contain %q, got:\n%s", expected, dot)
+			}
+		}
+
+		mermaid := graph.Mermaid()
+		for _, expected := range []string{
+			"graph LR\n",
+			"  %% Import cycle: internal/model, internal/storage\n",
+			"  n2[\"internal/model (2 packages)\"]\n",
+			"  n2 --> n3\n",
+			"  class n2,n3 cycle\n",
+			"  linkStyle 3,4 stroke:#d00\n",
+		} {
+			if !strings.Contains(mermaid, expected) {
+				t.Errorf("Expected Mermaid to contain %q, got:\n%s", expected, mermaid)
+			}
+		}
+	})
+
+	t.Run("package graph tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		call := func(arguments map[string]any) mcp.CallToolResult {
+			encoded, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      1,
+				"method":  string(mcp.MethodToolsCall),
+				"params":  map[string]any{"name": packageGraphToolName, "arguments": arguments},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+			return response.Result.(mcp.CallToolResult)
+		}
+
+		result := call(map[string]any{"workspace_dir": workspace, "format": "mermaid", "collapse": []any{"internal"}})
+		text := toolResultText(&result)
+		if result.IsError || !strings.HasPrefix(text, "graph LR\n") || !strings.Contains(text, "[\"internal (5 packages)\"]") {
+			t.Errorf("Expected a collapsed Mermaid diagram, got: %s", text)
+		}
+
+		result = call(map[string]any{"workspace_dir": workspace, "depth": -1})
+		if text := toolResultText(&result); !result.IsError || !strings.Contains(text, "depth must not be negative") {
+			t.Errorf("Expected a negative depth error, got: %s", text)
+		}
+	})
+}
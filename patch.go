@@ -0,0 +1,357 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// outputArgumentName selects whether a mutating tool writes its changes or returns them as a patch
+	outputArgumentName = "output"
+	outputWrite        = "write"
+	outputPatch        = "patch"
+
+	// patchContextLines is the number of unchanged lines around each hunk, as used by git
+	patchContextLines = 3
+)
+
+// repositoryToolNames are the mutating tools changing the git repository instead of the
+// files of the module. They return their patch output themselves and are never run on
+// copies of the module.
+var repositoryToolNames = map[string]bool{
+	commitToolName:          true,
+	worktreeDiscardToolName: true,
+}
+
+// outputArgumentFilter adds the output argument to the input schema of mutating tools
+func outputArgumentFilter(costs map[string]ToolCost) server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		for i, tool := range tools {
+			if !costs[tool.Name].Mutating {
+				continue
+			}
+			// The properties are shared with the registered tool, so they are copied before adding to them
+			properties := maps.Clone(tool.InputSchema.Properties)
+			if properties == nil {
+				properties = make(map[string]any)
+			}
+			properties[outputArgumentName] = map[string]any{
+				"type": "string",
+				"enum": []string{outputWrite, outputPatch},
+				"description": "Whether to write the changes to disk or to only return them as a unified diff. " +
+					"Patch paths are relative to the root of the Go module and can be applied there with `git apply`.",
+				"default": outputWrite,
+			}
+			tools[i].InputSchema.Properties = properties
+		}
+		return tools
+	}
+}
+
+// patchMiddleware runs mutating tools called with output "patch" on a shadow copy of the
+// module, bounded by the limits of the shadow options, and returns the changes as a unified
+// diff instead of writing them
+func patchMiddleware(costs map[string]ToolCost, shadow ShadowOptions) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !costs[request.Params.Name].Mutating {
+				return next(ctx, request)
+			}
+			switch output := request.GetString(outputArgumentName, ""); output {
+			case "", outputWrite:
+				return next(ctx, request)
+			case outputPatch:
+				if repositoryToolNames[request.Params.Name] {
+					return next(ctx, request)
+				}
+				return runForPatch(ctx, request, next, shadow)
+			default:
+				return toolErrorResult(fmt.Sprintf(
+					"Error: unknown output %q, expected %q to write the changes or %q to return them as a unified diff",
+					output,
+					outputWrite,
+					outputPatch,
+				)), nil
+			}
+		}
+	}
+}
+
+// runForPatch calls the tool on a shadow copy of the module and returns the differences
+// to the module as a patch, leaving the module untouched
+func runForPatch(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	next server.ToolHandlerFunc,
+	shadow ShadowOptions,
+) (*mcp.CallToolResult, error) {
+	request, moduleRoot, shadowRoot, err := newShadowCopy(request, shadow)
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("Error preparing copy for patch output: %v", err)), nil
+	}
+	defer os.RemoveAll(shadowRoot)
+
+	result, err := next(ctx, request)
+	if err != nil || result == nil || result.IsError {
+		return rewriteResultPaths(result, shadowRoot, moduleRoot), err
+	}
+
+	changed, removed, err := diffModules(moduleRoot, shadowRoot)
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("Error comparing copy for patch output: %v", err)), nil
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("%s made no changes, the patch is empty.", request.Params.Name)), nil
+	}
+
+	patch, err := modulePatch(moduleRoot, shadowRoot, changed, removed)
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("Error creating patch: %v", err)), nil
+	}
+	return mcp.NewToolResultText(patch), nil
+}
+
+// modulePatch creates a git compatible patch of the changed and removed files of the shadow copy
+func modulePatch(moduleRoot string, shadowRoot string, changed []string, removed []string) (string, error) {
+	var b strings.Builder
+	for _, rel := range changed {
+		newContent, err := os.ReadFile(filepath.Join(shadowRoot, rel))
+		if err != nil {
+			return "", err
+		}
+		stat, err := os.Stat(filepath.Join(shadowRoot, rel))
+		if err != nil {
+			return "", err
+		}
+		oldContent, err := os.ReadFile(filepath.Join(moduleRoot, rel))
+		if os.IsNotExist(err) {
+			writeFilePatch(&b, filepath.ToSlash(rel), nil, newContent, "new file mode "+gitFileMode(stat.Mode()))
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		writeFilePatch(&b, filepath.ToSlash(rel), oldContent, newContent, "")
+	}
+	for _, rel := range removed {
+		oldContent, err := os.ReadFile(filepath.Join(moduleRoot, rel))
+		if err != nil {
+			return "", err
+		}
+		stat, err := os.Stat(filepath.Join(moduleRoot, rel))
+		if err != nil {
+			return "", err
+		}
+		writeFilePatch(&b, filepath.ToSlash(rel), oldContent, nil, "deleted file mode "+gitFileMode(stat.Mode()))
+	}
+	return b.String(), nil
+}
+
+// gitFileMode formats the permissions of a file as stored by git
+func gitFileMode(mode os.FileMode) string {
+	if mode.Perm()&0111 != 0 {
+		return "100755"
+	}
+	return "100644"
+}
+
+// writeFilePatch writes the unified diff of a single file. A nil content means the file
+// does not exist on that side, with the mode line describing the addition or removal.
+func writeFilePatch(b *strings.Builder, path string, oldContent []byte, newContent []byte, modeLine string) {
+	fmt.Fprintf(b, "diff --git a/%s b/%s\n", path, path)
+	if modeLine != "" {
+		b.WriteString(modeLine)
+		b.WriteString("\n")
+	}
+	if bytes.IndexByte(oldContent, 0) >= 0 || bytes.IndexByte(newContent, 0) >= 0 {
+		fmt.Fprintf(b, "Binary files a/%s and b/%s differ\n", path, path)
+		return
+	}
+
+	oldName, newName := "a/"+path, "b/"+path
+	if oldContent == nil {
+		oldName = "/dev/null"
+	}
+	if newContent == nil {
+		newName = "/dev/null"
+	}
+	fmt.Fprintf(b, "--- %s\n+++ %s\n", oldName, newName)
+	writeHunks(b, diffLines(splitLines(oldContent), splitLines(newContent)))
+}
+
+// splitLines splits content into lines, each keeping its line break. Only the last line may lack one.
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffOp is a single line of a line diff: ' ' for unchanged, '-' for removed and '+' for added lines
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines computes a minimal line diff using the Myers algorithm
+func diffLines(a []string, b []string) []diffOp {
+	// Common prefixes and suffixes are trimmed first as most edits are local
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// myersDiff finds the shortest edit script between a and b, see
+// "An O(ND) Difference Algorithm and Its Variations" by Eugene W. Myers
+func myersDiff(a []string, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds the furthest reaching x of every diagonal k before step d
+	var trace [][]int
+
+	distance := 0
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				distance = d
+				break search
+			}
+		}
+	}
+
+	// Walk back from the end, collecting the operations in reverse
+	var reversed []diffOp
+	x, y := n, m
+	for d := distance; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffOp{' ', a[x]})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, diffOp{'+', b[y]})
+		} else {
+			x--
+			reversed = append(reversed, diffOp{'-', a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, diffOp{' ', a[x]})
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// writeHunks writes the changes of a line diff as unified diff hunks with context lines
+func writeHunks(b *strings.Builder, ops []diffOp) {
+	for start := 0; start < len(ops); {
+		// Find the next change and extend the hunk while changes are close enough to share context
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			return
+		}
+		last := first
+		for i := first + 1; i < len(ops) && i-last <= 2*patchContextLines+1; i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			}
+		}
+		hunkStart := max(first-patchContextLines, start)
+		hunkEnd := min(last+1+patchContextLines, len(ops))
+
+		// Line numbers are 1-based and count the lines before the hunk on each side
+		oldLine, newLine := 1, 1
+		for _, op := range ops[:hunkStart] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		// Empty ranges refer to the line before them
+		if oldCount == 0 {
+			oldLine--
+		}
+		if newCount == 0 {
+			newLine--
+		}
+
+		fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range ops[hunkStart:hunkEnd] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = hunkEnd
+	}
+}
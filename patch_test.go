@@ -0,0 +1,237 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPatchOutput(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a test module with an unsorted map and a file to remove
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module testmodule", "", "go 1.21", ""},
+			"main.go": {
+				"package testpkg",             // 1
+				"",                            // 2
+				"// Values are sorted by key", // 3
+				"var Values = map[string]int{\"b\": 2, \"a\": 1}", // 4
+				"",                // 5
+				"func Empty() {}", // 6
+			},
+			"old.go": {"package testpkg", ""},
+		})
+	}
+
+	readFileContent := func(t testing.TB, filePath string) string {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Failed to read file %s: %v", filePath, err)
+		}
+		return string(content)
+	}
+
+	// A mutating tool adding new.go and removing old.go
+	moveTool := mcp.NewTool("move", mcp.WithString("workspace_dir"))
+	moveHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		workspaceDir := request.GetArguments()["workspace_dir"].(string)
+		err := os.WriteFile(filepath.Join(workspaceDir, "new.go"), []byte("package testpkg\n\nfunc New() {}"), 0644)
+		if err != nil {
+			return toolErrorResult(err.Error()), nil
+		}
+		if err := os.Remove(filepath.Join(workspaceDir, "old.go")); err != nil {
+			return toolErrorResult(err.Error()), nil
+		}
+		return mcp.NewToolResultText("moved"), nil
+	}
+
+	t.Run("patch is returned instead of written", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		original := readFileContent(t, mainFile)
+
+		result := callTool(t, NewMCPServer(), sortToolName, map[string]any{
+			"file_path": mainFile,
+			"output":    "patch",
+		})
+		patch := toolResultText(&result)
+		if result.IsError {
+			t.Fatalf("Expected sort to succeed, got: %s", patch)
+		}
+		expected := strings.Join([]string{
+			"diff --git a/main.go b/main.go",
+			"--- a/main.go",
+			"+++ b/main.go",
+			"@@ -1,6 +1,6 @@",
+			" package testpkg",
+			" ",
+			" // Values are sorted by key",
+			"-var Values = map[string]int{\"b\": 2, \"a\": 1}",
+			"+var Values = map[string]int{\"a\": 1, \"b\": 2}",
+			" ",
+			" func Empty() {}",
+			"\\ No newline at end of file",
+			"",
+		}, "\n")
+		if patch != expected {
+			t.Errorf("Expected patch:\n%s\ngot:\n%s", expected, patch)
+		}
+		if content := readFileContent(t, mainFile); content != original {
+			t.Errorf("Expected workspace to be untouched, got:\n%s", content)
+		}
+
+		// The patch applies to the workspace and gives the same result as writing
+		cmd := exec.Command("git", "apply", "-")
+		cmd.Dir = workspace
+		cmd.Stdin = strings.NewReader(patch)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to apply patch: %v\n%s", err, output)
+		}
+		writeWorkspace := createTestWorkspace(t)
+		result = callTool(t, NewMCPServer(), sortToolName, map[string]any{
+			"file_path": filepath.Join(writeWorkspace, "main.go"),
+			"output":    "write",
+		})
+		if result.IsError {
+			t.Fatalf("Expected sort to succeed, got: %s", toolResultText(&result))
+		}
+		if readFileContent(t, mainFile) != readFileContent(t, filepath.Join(writeWorkspace, "main.go")) {
+			t.Errorf("Expected applied patch to match the written result")
+		}
+	})
+
+	t.Run("added and removed files", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mcpServer := NewMCPServer(
+			WithTool(moveTool, moveHandler),
+			WithToolCost("move", ToolCost{Level: CostLow, Mutating: true}),
+		)
+
+		result := callTool(t, mcpServer, "move", map[string]any{"workspace_dir": workspace, "output": "patch"})
+		patch := toolResultText(&result)
+		expected := strings.Join([]string{
+			"diff --git a/new.go b/new.go",
+			"new file mode 100644",
+			"--- /dev/null",
+			"+++ b/new.go",
+			"@@ -0,0 +1,3 @@",
+			"+package testpkg",
+			"+",
+			"+func New() {}",
+			"\\ No newline at end of file",
+			"diff --git a/old.go b/old.go",
+			"deleted file mode 100644",
+			"--- a/old.go",
+			"+++ /dev/null",
+			"@@ -1,1 +0,0 @@",
+			"-package testpkg",
+			"",
+		}, "\n")
+		if result.IsError || patch != expected {
+			t.Errorf("Expected patch:\n%s\ngot:\n%s", expected, patch)
+		}
+		if _, err := os.Stat(filepath.Join(workspace, "old.go")); err != nil {
+			t.Errorf("Expected old.go to be kept: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(workspace, "new.go")); !os.IsNotExist(err) {
+			t.Errorf("Expected new.go not to be written: %v", err)
+		}
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		result := callTool(t, NewMCPServer(), sortToolName, map[string]any{
+			"file_path": filepath.Join(workspace, "main.go"),
+			"output":    "diff",
+		})
+		if !result.IsError || !strings.Contains(toolResultText(&result), `must be one of "write", "patch", got "diff"`) {
+			t.Errorf("Expected unknown output error, got: %s", toolResultText(&result))
+		}
+	})
+
+	t.Run("only mutating tools have the output argument", func(t *testing.T) {
+		t.Parallel()
+		response := NewMCPServer().HandleMessage(
+			context.Background(),
+			json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`),
+		).(mcp.JSONRPCResponse)
+		for _, tool := range response.Result.(mcp.ListToolsResult).Tools {
+			_, hasOutput := tool.InputSchema.Properties[outputArgumentName]
+			if hasOutput != builtinToolCosts[tool.Name].Mutating {
+				t.Errorf("Expected output argument only for mutating tools, %s has it: %v", tool.Name, hasOutput)
+			}
+		}
+	})
+}
+
+func TestDiffLines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("distant changes get separate hunks", func(t *testing.T) {
+		t.Parallel()
+		var oldLines, newLines []string
+		for i := range 20 {
+			line := string(rune('a'+i)) + "\n"
+			oldLines = append(oldLines, line)
+			switch i {
+			case 1:
+				newLines = append(newLines, "B\n")
+			case 17:
+				// Removed
+			default:
+				newLines = append(newLines, line)
+			}
+		}
+
+		var b strings.Builder
+		writeHunks(&b, diffLines(oldLines, newLines))
+		expected := strings.Join([]string{
+			"@@ -1,5 +1,5 @@",
+			" a",
+			"-b",
+			"+B",
+			" c",
+			" d",
+			" e",
+			"@@ -15,6 +15,5 @@",
+			" o",
+			" p",
+			" q",
+			"-r",
+			" s",
+			" t",
+			"",
+		}, "\n")
+		if b.String() != expected {
+			t.Errorf("Expected hunks:\n%s\ngot:\n%s", expected, b.String())
+		}
+	})
+
+	t.Run("minimal diff", func(t *testing.T) {
+		t.Parallel()
+		ops := diffLines(
+			[]string{"a\n", "b\n", "c\n", "a\n", "b\n", "b\n", "a\n"},
+			[]string{"c\n", "b\n", "a\n", "b\n", "a\n", "c\n"},
+		)
+		changes := 0
+		for _, op := range ops {
+			if op.kind != ' ' {
+				changes++
+			}
+		}
+		if changes != 5 {
+			t.Errorf("Expected the 5 changes of the shortest edit script, got %d: %v", changes, ops)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	summarizeAPIPromptName   = "summarize_package_api"
+	reviewErrorsPromptName   = "review_error_handling"
+	planRenamePromptName     = "plan_rename"
+	promptPathDescription    = "Path in any format supported by the inspect tool, e.g. /path/to/file.go:42:symbolName or github.com/user/repo/package:symbolName"
+	promptWorkspaceDirectory = "Absolute working directory for package resolution and reference finding"
+)
+
+// AddPrompts registers parameterized prompts for common Go workflows.
+// Each prompt runs the inspect tool and embeds its output so the model starts with the relevant context.
+func AddPrompts(mcpServer *server.MCPServer) {
+	mcpServer.AddPrompt(mcp.NewPrompt(
+		summarizeAPIPromptName,
+		mcp.WithPromptDescription("Summarize the public API of a Go package"),
+		mcp.WithArgument("path",
+			mcp.ArgumentDescription("Package directory, import path or file to summarize"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("workspace_dir",
+			mcp.ArgumentDescription(promptWorkspaceDirectory),
+			mcp.RequiredArgument(),
+		),
+	), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return SummarizeAPIPrompt(
+			request.Params.Arguments["path"],
+			request.Params.Arguments["workspace_dir"],
+		)
+	})
+
+	mcpServer.AddPrompt(mcp.NewPrompt(
+		reviewErrorsPromptName,
+		mcp.WithPromptDescription("Review a Go function for error handling issues"),
+		mcp.WithArgument("path",
+			mcp.ArgumentDescription(promptPathDescription),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("workspace_dir",
+			mcp.ArgumentDescription(promptWorkspaceDirectory),
+			mcp.RequiredArgument(),
+		),
+	), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return ReviewErrorHandlingPrompt(
+			request.Params.Arguments["path"],
+			request.Params.Arguments["workspace_dir"],
+		)
+	})
+
+	mcpServer.AddPrompt(mcp.NewPrompt(
+		planRenamePromptName,
+		mcp.WithPromptDescription("Plan renaming a Go symbol, including all affected references"),
+		mcp.WithArgument("path",
+			mcp.ArgumentDescription(promptPathDescription),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("new_name",
+			mcp.ArgumentDescription("The desired new name of the symbol"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("workspace_dir",
+			mcp.ArgumentDescription(promptWorkspaceDirectory),
+			mcp.RequiredArgument(),
+		),
+	), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return PlanRenamePrompt(
+			request.Params.Arguments["path"],
+			request.Params.Arguments["new_name"],
+			request.Params.Arguments["workspace_dir"],
+		)
+	})
+}
+
+// SummarizeAPIPrompt builds a prompt asking for a summary of the exported API of a package
+func SummarizeAPIPrompt(pathStr string, workspaceDir string) (*mcp.GetPromptResult, error) {
+	if pathStr == "" {
+		return nil, fmt.Errorf("path argument is required")
+	}
+
+	path, _, _ := parseInspectPath(pathStr)
+	inspection := inspectForPrompt(path, 0, "", false, workspaceDir)
+
+	instructions := strings.Join([]string{
+		fmt.Sprintf("Summarize the public API of the Go package at %s.", path),
+		"",
+		"Cover:",
+		"1. The purpose of the package in one or two sentences.",
+		"2. The key exported types and what they model.",
+		"3. The main entry points (constructors and functions) and how they are meant to be combined.",
+		"4. Noteworthy conventions such as error handling, concurrency safety and configuration.",
+		"",
+		"Base the summary on the inspect output below. Use the inspect tool on individual symbols if more detail is needed.",
+	}, "\n")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Summarize the public API of %s", path),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions)),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(inspection)),
+		},
+	), nil
+}
+
+// ReviewErrorHandlingPrompt builds a prompt asking for an error handling review of a symbol
+func ReviewErrorHandlingPrompt(pathStr string, workspaceDir string) (*mcp.GetPromptResult, error) {
+	if pathStr == "" {
+		return nil, fmt.Errorf("path argument is required")
+	}
+
+	path, lineNumber, symbolName := parseInspectPath(pathStr)
+	if lineNumber == 0 && symbolName == "" {
+		return nil, fmt.Errorf(
+			"path must identify a function, e.g. /path/to/file.go:42:functionName, got: %s",
+			pathStr,
+		)
+	}
+	inspection := inspectForPrompt(path, lineNumber, symbolName, true, workspaceDir)
+
+	instructions := strings.Join([]string{
+		fmt.Sprintf("Review the error handling of %s.", pathStr),
+		"",
+		"Check for:",
+		"1. Errors that are ignored, discarded with _ or only logged.",
+		"2. Errors returned without context. Wrapping should use fmt.Errorf with %w.",
+		"3. Error messages that are not informative to the caller.",
+		"4. Resources that are not released on error paths (files, locks, goroutines).",
+		"5. Panics used for recoverable conditions.",
+		"",
+		"For each finding give the line, the problem and a concrete fix. The inspect output below contains the code, its references and call hierarchy.",
+	}, "\n")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Review error handling of %s", pathStr),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions)),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(inspection)),
+		},
+	), nil
+}
+
+// PlanRenamePrompt builds a prompt asking for a plan to rename a symbol
+func PlanRenamePrompt(
+	pathStr string,
+	newName string,
+	workspaceDir string,
+) (*mcp.GetPromptResult, error) {
+	if pathStr == "" {
+		return nil, fmt.Errorf("path argument is required")
+	}
+	if newName == "" {
+		return nil, fmt.Errorf("new_name argument is required")
+	}
+
+	path, lineNumber, symbolName := parseInspectPath(pathStr)
+	if symbolName == "" {
+		return nil, fmt.Errorf(
+			"path must include the symbol name, e.g. /path/to/file.go:42:symbolName, got: %s",
+			pathStr,
+		)
+	}
+	inspection := inspectForPrompt(path, lineNumber, symbolName, true, workspaceDir)
+
+	instructions := strings.Join([]string{
+		fmt.Sprintf("Plan renaming %s to %s.", symbolName, newName),
+		"",
+		"1. List the references that will change, grouped by package, using the inspect output below.",
+		"2. Point out conflicts: existing symbols named " + newName + ", interface methods that must be renamed together, exported API changes and string references such as reflection, templates or documentation that the rename tool cannot update.",
+		"3. Decide whether the new name follows Go naming conventions and the conventions of the surrounding code.",
+		"4. Finish with the exact rename tool call(s) to perform, giving file_path, line_number, old_name and new_name.",
+	}, "\n")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Plan renaming %s to %s", symbolName, newName),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions)),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(inspection)),
+		},
+	), nil
+}
+
+// inspectForPrompt runs Inspect and formats the result for embedding in a prompt.
+// Failures are embedded as instructions to call the inspect tool instead of failing the prompt.
+func inspectForPrompt(
+	path string,
+	lineNumber int,
+	symbolName string,
+	includePrivate bool,
+	workspaceDir string,
+) string {
+	summary, err := Inspect(path, lineNumber, symbolName, includePrivate, workspaceDir)
+	if err != nil {
+		return fmt.Sprintf(
+			"The inspect tool could not be run ahead of time (%v). Call the %s tool yourself before answering.",
+			err,
+			inspectToolName,
+		)
+	}
+	return "Inspect output:\n\n" + summary
+}
@@ -0,0 +1,149 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPrompts(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a test workspace with a single file
+	createTestWorkspace := func(t testing.TB) string {
+		tempDir := t.TempDir()
+
+		goModContent := "module testmodule\n\ngo 1.21\n"
+		err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte(goModContent), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mainLines := []string{
+			"package testpkg",                       // 1
+			"",                                      // 2
+			"import \"os\"",                         // 3
+			"",                                      // 4
+			"// ReadConfig reads the configuration", // 5
+			"func ReadConfig(path string) []byte {", // 6
+			"    data, _ := os.ReadFile(path)",      // 7
+			"    return data",                       // 8
+			"}",                                     // 9
+		}
+		err = os.WriteFile(
+			filepath.Join(tempDir, "main.go"),
+			[]byte(strings.Join(mainLines, "\n")),
+			0644,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tempDir
+	}
+
+	promptText := func(result *mcp.GetPromptResult) string {
+		var b strings.Builder
+		for _, message := range result.Messages {
+			if text, ok := message.Content.(mcp.TextContent); ok {
+				b.WriteString(text.Text)
+				b.WriteString("\n")
+			}
+		}
+		return b.String()
+	}
+
+	t.Run("prompts are listed", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer()
+
+		response := mcpServer.HandleMessage(
+			context.Background(),
+			json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`),
+		)
+		encoded, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{summarizeAPIPromptName, reviewErrorsPromptName, planRenamePromptName} {
+			if !strings.Contains(string(encoded), name) {
+				t.Errorf("Expected prompt %s to be listed, got: %s", name, encoded)
+			}
+		}
+	})
+
+	t.Run("review error handling embeds inspect output", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		result, err := ReviewErrorHandlingPrompt(mainFile+":6:ReadConfig", workspace)
+		if err != nil {
+			t.Fatalf("Failed to build prompt: %v", err)
+		}
+
+		text := promptText(result)
+		if !strings.Contains(text, "Inspect output:") {
+			t.Errorf("Expected embedded inspect output, got:\n%s", text)
+		}
+		if !strings.Contains(text, "func ReadConfig(path string) []byte") {
+			t.Errorf("Expected function signature in prompt, got:\n%s", text)
+		}
+	})
+
+	t.Run("plan rename", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		result, err := PlanRenamePrompt(mainFile+":6:ReadConfig", "LoadConfig", workspace)
+		if err != nil {
+			t.Fatalf("Failed to build prompt: %v", err)
+		}
+
+		text := promptText(result)
+		if !strings.Contains(text, "Plan renaming ReadConfig to LoadConfig") {
+			t.Errorf("Expected rename instructions, got:\n%s", text)
+		}
+		if !strings.Contains(text, "ReadConfig reads the configuration") {
+			t.Errorf("Expected docstring from inspect output, got:\n%s", text)
+		}
+	})
+
+	t.Run("inspect failure is embedded", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		result, err := ReviewErrorHandlingPrompt(mainFile+":0:Missing", workspace)
+		if err != nil {
+			t.Fatalf("Expected prompt to be built despite inspect failure: %v", err)
+		}
+		if !strings.Contains(promptText(result), "Call the inspect tool yourself") {
+			t.Error("Expected instructions to call the inspect tool")
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+
+		if _, err := SummarizeAPIPrompt("", workspace); err == nil {
+			t.Error("Expected error for empty path")
+		}
+		if _, err := ReviewErrorHandlingPrompt(mainFile, workspace); err == nil {
+			t.Error("Expected error when no function is identified")
+		}
+		if _, err := PlanRenamePrompt(mainFile+":6:ReadConfig", "", workspace); err == nil {
+			t.Error("Expected error for empty new name")
+		}
+		if _, err := PlanRenamePrompt(mainFile+":6", "LoadConfig", workspace); err == nil {
+			t.Error("Expected error when symbol name is missing")
+		}
+	})
+}
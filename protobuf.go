@@ -0,0 +1,787 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	protobufToolName        = "protobuf"
+	protobufToolDescription = `Navigates between protobuf definitions and the Go code generated from them, which is too verbose to inspect directly.
+
+• With package: lists every message, enum and service generated into a Go package, one line each, with the generated Go types and the .proto definition.
+• With name: looks up a message, enum or service by its full or short protobuf name (shop.v1.Order, Order.Item) or by a generated Go type (Order_Item, OrderServiceClient) in the whole module, and shows its fields with their Go names and types, or the methods of a service.
+
+Definitions are read from the descriptors embedded in *.pb.go files. The .proto files are looked up in the module by the path they were generated from.`
+)
+
+// ProtoDefinitions are protobuf definitions found in the generated Go code of a module
+type ProtoDefinitions struct {
+	Definitions []ProtoDefinition `json:"definitions"`
+	// Detailed is set when fields and methods are listed
+	Detailed bool `json:"detailed,omitempty"`
+}
+
+// ProtoDefinition is a message, enum or service with its generated Go types
+type ProtoDefinition struct {
+	// Kind is one of message, enum and service
+	Kind string `json:"kind"`
+	// Name is the full protobuf name, e.g. shop.v1.Order.Item
+	Name      string `json:"name"`
+	GoPackage string `json:"go_package"`
+	// GoTypes are the generated types, the client and server interfaces for services
+	GoTypes []ProtoGoType `json:"go_types"`
+	// ProtoFile is the path the Go code was generated from, relative to the module root
+	// when the file was found
+	ProtoFile string `json:"proto_file"`
+	// ProtoLine is the line of the definition, zero when the file was not found
+	ProtoLine int           `json:"proto_line,omitempty"`
+	Fields    []ProtoField  `json:"fields,omitempty"`
+	Methods   []ProtoMethod `json:"methods,omitempty"`
+}
+
+// ProtoGoType is a generated Go type, with its file relative to the module root
+type ProtoGoType struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// ProtoField is a field of a message. Oneofs have no number.
+type ProtoField struct {
+	Number int    `json:"number,omitempty"`
+	Name   string `json:"name"`
+	GoName string `json:"go_name"`
+	GoType string `json:"go_type"`
+}
+
+// ProtoMethod is a method of a service with its request and response message
+type ProtoMethod struct {
+	Name            string `json:"name"`
+	Input           string `json:"input"`
+	Output          string `json:"output"`
+	ClientStreaming bool   `json:"client_streaming,omitempty"`
+	ServerStreaming bool   `json:"server_streaming,omitempty"`
+	ProtoLine       int    `json:"proto_line,omitempty"`
+}
+
+func AddProtobufTool(mcpServer *server.MCPServer) {
+	handleProtobuf := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		pkg := request.GetString("package", "")
+		name := request.GetString("name", "")
+
+		definitions, err := FindProtoDefinitions(workspaceDir, pkg, name)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error reading protobuf definitions: %v", err)), nil
+		}
+		return mcp.NewToolResultText(definitions.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		protobufToolName,
+		mcp.WithDescription(protobufToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Generated Go package to list, as a directory relative to workspace_dir or an import path of the module. Limits name lookups to the package."),
+		),
+		mcp.WithString("name",
+			mcp.Description("Protobuf name of a message, enum or service, or the name of a generated Go type"),
+		),
+	), handleProtobuf)
+}
+
+// FindProtoDefinitions lists the protobuf definitions generated into the Go package pkg
+// of the module containing workspaceDir, or looks up the definitions matching name in
+// the package or whole module. At least one of pkg and name is required.
+func FindProtoDefinitions(workspaceDir string, pkg string, name string) (*ProtoDefinitions, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	if pkg == "" && name == "" {
+		return nil, fmt.Errorf("either package or name is required")
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	if pkg != "" {
+		dir, err := protoPackageDir(module, workspaceDir, pkg)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+	} else {
+		dirs, err = generatedProtoDirs(module.root)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	protoSources := make(map[string]*protoSource)
+	result := &ProtoDefinitions{Detailed: name != ""}
+	for _, dir := range dirs {
+		definitions, err := readGeneratedProtoPackage(module, dir, protoSources)
+		if err != nil {
+			return nil, err
+		}
+		if pkg != "" && len(definitions) == 0 {
+			return nil, fmt.Errorf("no generated protobuf code in %s", module.relPath(dir))
+		}
+		for _, definition := range definitions {
+			if name == "" || definition.matches(name) {
+				result.Definitions = append(result.Definitions, definition)
+			}
+		}
+	}
+	if name != "" && len(result.Definitions) == 0 {
+		return nil, classifyErrorf(ErrSymbolNotFound, "no protobuf message, enum or service matches %s", name)
+	}
+	return result, nil
+}
+
+// protoPackageDir resolves a package given as a directory or an import path of the module
+func protoPackageDir(module *moduleSources, workspaceDir string, pkg string) (string, error) {
+	var dir string
+	switch {
+	case filepath.IsAbs(pkg):
+		dir = pkg
+	case pkg == module.path:
+		dir = module.root
+	case strings.HasPrefix(pkg, module.path+"/"):
+		dir = filepath.Join(module.root, filepath.FromSlash(strings.TrimPrefix(pkg, module.path+"/")))
+	default:
+		dir = filepath.Join(workspaceDir, pkg)
+	}
+	if stat, err := os.Stat(dir); err != nil || !stat.IsDir() {
+		return "", fmt.Errorf("package %s is not a directory of module %s", pkg, module.path)
+	}
+	return dir, nil
+}
+
+// generatedProtoDirs returns the directories of the module containing *.pb.go files
+func generatedProtoDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && skipProtoSearchDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".pb.go") && !slices.Contains(dirs, filepath.Dir(path)) {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// skipProtoSearchDir reports whether a directory is skipped when searching the module
+// for generated and .proto files
+func skipProtoSearchDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+		name == "vendor" || name == "testdata" || name == "node_modules"
+}
+
+// readGeneratedProtoPackage reads the definitions of the *.pb.go files in dir. Sources
+// caches the .proto files found by the path they were generated from.
+func readGeneratedProtoPackage(module *moduleSources, dir string, sources map[string]*protoSource) ([]ProtoDefinition, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pb.go"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+	importPath := module.path
+	if rel := module.relPath(dir); rel != "." {
+		importPath += "/" + filepath.ToSlash(rel)
+	}
+
+	fset := token.NewFileSet()
+	goTypes := make(map[string]*ast.TypeSpec)
+	var descriptors [][]byte
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, classifyErrorf(ErrSyntaxErrors, "failed to parse %s: %w", module.relPath(path), err)
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					goTypes[spec.Name.Name] = spec
+				case *ast.ValueSpec:
+					for i, ident := range spec.Names {
+						if i >= len(spec.Values) || !strings.HasPrefix(ident.Name, "file_") || !strings.HasSuffix(ident.Name, "_rawDesc") {
+							continue
+						}
+						if descriptor, ok := constantBytes(spec.Values[i]); ok {
+							descriptors = append(descriptors, descriptor)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var definitions []ProtoDefinition
+	for _, descriptor := range descriptors {
+		file, err := decodeProtoFile(descriptor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the descriptor embedded in %s: %w", module.relPath(dir), err)
+		}
+		source, ok := sources[file.path]
+		if !ok {
+			source = findProtoSource(module.root, file.path)
+			sources[file.path] = source
+		}
+		protoFile := file.path
+		if source.path != "" {
+			protoFile = module.relPath(source.path)
+		}
+		goType := func(name string) (ProtoGoType, *ast.TypeSpec, bool) {
+			spec, ok := goTypes[name]
+			if !ok {
+				return ProtoGoType{}, nil, false
+			}
+			position := fset.Position(spec.Name.Pos())
+			return ProtoGoType{Name: name, File: module.relPath(position.Filename), Line: position.Line}, spec, true
+		}
+
+		for _, message := range file.messages {
+			definition := ProtoDefinition{
+				Kind:      message.kind,
+				Name:      file.qualify(message.name),
+				GoPackage: importPath,
+				ProtoFile: protoFile,
+				ProtoLine: source.lines[file.qualify(message.name)],
+			}
+			if goType, spec, ok := goType(protoGoName(message.name)); ok {
+				definition.GoTypes = append(definition.GoTypes, goType)
+				if structType, ok := spec.Type.(*ast.StructType); ok {
+					definition.Fields = protoStructFields(structType)
+				}
+			}
+			definitions = append(definitions, definition)
+		}
+		for _, service := range file.services {
+			definition := ProtoDefinition{
+				Kind:      "service",
+				Name:      file.qualify(service.name),
+				GoPackage: importPath,
+				ProtoFile: protoFile,
+				ProtoLine: source.lines[file.qualify(service.name)],
+			}
+			// protoc-gen-go-grpc generates the client and server into the same package
+			for _, suffix := range []string{"Client", "Server"} {
+				if goType, _, ok := goType(protoGoName(service.name) + suffix); ok {
+					definition.GoTypes = append(definition.GoTypes, goType)
+				}
+			}
+			for _, method := range service.methods {
+				method.ProtoLine = source.lines[file.qualify(service.name)+"."+method.Name]
+				definition.Methods = append(definition.Methods, method)
+			}
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions, nil
+}
+
+// matches reports whether name is the full or a partial protobuf name of the
+// definition, or the name of one of its Go types
+func (definition *ProtoDefinition) matches(name string) bool {
+	name = strings.TrimPrefix(name, ".")
+	if definition.Name == name || strings.HasSuffix(definition.Name, "."+name) {
+		return true
+	}
+	for _, goType := range definition.GoTypes {
+		if goType.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// protoStructFields lists the fields of a generated message struct by their tags
+func protoStructFields(structType *ast.StructType) []ProtoField {
+	var fields []ProtoField
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		goField := ProtoField{GoName: field.Names[0].Name, GoType: types.ExprString(field.Type)}
+		if oneof, ok := reflect.StructTag(tag).Lookup("protobuf_oneof"); ok {
+			goField.Name = oneof
+			fields = append(fields, goField)
+			continue
+		}
+		options, ok := reflect.StructTag(tag).Lookup("protobuf")
+		if !ok {
+			continue
+		}
+		// e.g. bytes,1,rep,name=items,proto3
+		for i, option := range strings.Split(options, ",") {
+			if i == 1 {
+				goField.Number, _ = strconv.Atoi(option)
+			}
+			if value, ok := strings.CutPrefix(option, "name="); ok {
+				goField.Name = value
+			}
+		}
+		fields = append(fields, goField)
+	}
+	return fields
+}
+
+// protoGoName returns the Go name protoc-gen-go generates for a definition, given its
+// name relative to the package, e.g. Order_Item for Order.Item
+func protoGoName(name string) string {
+	var b strings.Builder
+	isLower := func(c byte) bool { return 'a' <= c && c <= 'z' }
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '.' && i+1 < len(name) && isLower(name[i+1]):
+			// Skip the dot of .lowercase
+		case c == '.':
+			b.WriteByte('_')
+		case c == '_' && (i == 0 || name[i-1] == '.'):
+			// A leading underscore would not be exported
+			b.WriteByte('X')
+		case c == '_' && i+1 < len(name) && isLower(name[i+1]):
+			// Skip the underscore of _lowercase
+		case '0' <= c && c <= '9':
+			b.WriteByte(c)
+		default:
+			b.WriteByte(byte(unicode.ToUpper(rune(c))))
+			for ; i+1 < len(name) && isLower(name[i+1]); i++ {
+				b.WriteByte(name[i+1])
+			}
+		}
+	}
+	return b.String()
+}
+
+// constantBytes evaluates the raw descriptor of a generated file: a string constant,
+// possibly concatenated, or a byte slice literal in older generated code
+func constantBytes(expr ast.Expr) ([]byte, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind != token.STRING {
+			return nil, false
+		}
+		value, err := strconv.Unquote(expr.Value)
+		return []byte(value), err == nil
+	case *ast.BinaryExpr:
+		left, ok := constantBytes(expr.X)
+		if !ok || expr.Op != token.ADD {
+			return nil, false
+		}
+		right, ok := constantBytes(expr.Y)
+		return append(left, right...), ok
+	case *ast.ParenExpr:
+		return constantBytes(expr.X)
+	case *ast.CallExpr:
+		// string([]byte{...}) and []byte("...") conversions
+		if len(expr.Args) != 1 {
+			return nil, false
+		}
+		return constantBytes(expr.Args[0])
+	case *ast.CompositeLit:
+		var value []byte
+		for _, element := range expr.Elts {
+			literal, ok := element.(*ast.BasicLit)
+			if !ok || literal.Kind != token.INT {
+				return nil, false
+			}
+			b, err := strconv.ParseUint(literal.Value, 0, 8)
+			if err != nil {
+				return nil, false
+			}
+			value = append(value, byte(b))
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// protoFile is the part of a decoded FileDescriptorProto used for navigation
+type protoFile struct {
+	path     string
+	pkg      string
+	messages []protoMessage
+	services []protoService
+}
+
+// protoMessage is a message or enum with its name relative to the package
+type protoMessage struct {
+	kind string
+	name string
+}
+
+// protoService is a service with its name relative to the package
+type protoService struct {
+	name    string
+	methods []ProtoMethod
+}
+
+// qualify returns the full protobuf name of a name relative to the package
+func (file *protoFile) qualify(name string) string {
+	if file.pkg == "" {
+		return name
+	}
+	return file.pkg + "." + name
+}
+
+// Field numbers of descriptor.proto
+const (
+	fileDescriptorName     protowire.Number = 1
+	fileDescriptorPackage  protowire.Number = 2
+	fileDescriptorMessages protowire.Number = 4
+	fileDescriptorEnums    protowire.Number = 5
+	fileDescriptorServices protowire.Number = 6
+
+	descriptorName     protowire.Number = 1
+	descriptorNested   protowire.Number = 3
+	descriptorEnums    protowire.Number = 4
+	serviceMethods     protowire.Number = 2
+	methodInput        protowire.Number = 2
+	methodOutput       protowire.Number = 3
+	methodClientStream protowire.Number = 5
+	methodServerStream protowire.Number = 6
+)
+
+// decodeProtoFile decodes the names of the messages, enums and services of a serialized
+// FileDescriptorProto
+func decodeProtoFile(data []byte) (*protoFile, error) {
+	fields, err := decodeProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	file := &protoFile{path: fields.string(fileDescriptorName), pkg: fields.string(fileDescriptorPackage)}
+
+	var addMessage func(data []byte, parent string) error
+	addMessage = func(data []byte, parent string) error {
+		fields, err := decodeProtoFields(data)
+		if err != nil {
+			return err
+		}
+		name := parent + fields.string(descriptorName)
+		file.messages = append(file.messages, protoMessage{kind: "message", name: name})
+		if err := forEachProtoMessage(data, descriptorEnums, func(data []byte) error {
+			return addEnum(file, data, name+".")
+		}); err != nil {
+			return err
+		}
+		return forEachProtoMessage(data, descriptorNested, func(data []byte) error {
+			return addMessage(data, name+".")
+		})
+	}
+	if err := forEachProtoMessage(data, fileDescriptorMessages, func(data []byte) error {
+		return addMessage(data, "")
+	}); err != nil {
+		return nil, err
+	}
+	if err := forEachProtoMessage(data, fileDescriptorEnums, func(data []byte) error {
+		return addEnum(file, data, "")
+	}); err != nil {
+		return nil, err
+	}
+	err = forEachProtoMessage(data, fileDescriptorServices, func(data []byte) error {
+		fields, err := decodeProtoFields(data)
+		if err != nil {
+			return err
+		}
+		service := protoService{name: fields.string(descriptorName)}
+		err = forEachProtoMessage(data, serviceMethods, func(data []byte) error {
+			fields, err := decodeProtoFields(data)
+			if err != nil {
+				return err
+			}
+			service.methods = append(service.methods, ProtoMethod{
+				Name:            fields.string(descriptorName),
+				Input:           strings.TrimPrefix(fields.string(methodInput), "."),
+				Output:          strings.TrimPrefix(fields.string(methodOutput), "."),
+				ClientStreaming: fields.bool(methodClientStream),
+				ServerStreaming: fields.bool(methodServerStream),
+			})
+			return nil
+		})
+		file.services = append(file.services, service)
+		return err
+	})
+	return file, err
+}
+
+// addEnum adds an enum of a file or message to the file
+func addEnum(file *protoFile, data []byte, parent string) error {
+	fields, err := decodeProtoFields(data)
+	if err != nil {
+		return err
+	}
+	file.messages = append(file.messages, protoMessage{kind: "enum", name: parent + fields.string(descriptorName)})
+	return nil
+}
+
+// forEachProtoMessage calls f with every value of the repeated message field num,
+// which decodeProtoFields only keeps the last of
+func forEachProtoMessage(data []byte, num protowire.Number, f func(data []byte) error) error {
+	for len(data) > 0 {
+		fieldNum, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("invalid protobuf tag: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+		n = protowire.ConsumeFieldValue(fieldNum, typ, data)
+		if n < 0 {
+			return fmt.Errorf("invalid value for field %d: %w", fieldNum, protowire.ParseError(n))
+		}
+		if fieldNum == num && typ == protowire.BytesType {
+			value, _ := protowire.ConsumeBytes(data)
+			if err := f(value); err != nil {
+				return err
+			}
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// protoSource is a .proto file with the lines of its definitions by full name. The path
+// is empty when the file was not found.
+type protoSource struct {
+	path  string
+	lines map[string]int
+}
+
+// findProtoSource finds the .proto file that code was generated from in the module. The
+// path in the descriptor is relative to an include directory of protoc, so the shortest
+// file ending in it is used.
+func findProtoSource(root string, protoPath string) *protoSource {
+	source := &protoSource{lines: make(map[string]int)}
+	suffix := "/" + protoPath
+	_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if path != root && skipProtoSearchDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(filepath.ToSlash(path), suffix) && (source.path == "" || len(path) < len(source.path)) {
+			source.path = path
+		}
+		return nil
+	})
+	if source.path == "" {
+		return source
+	}
+	content, err := os.ReadFile(source.path)
+	if err != nil {
+		source.path = ""
+		return source
+	}
+	source.lines = protoDefinitionLines(string(content))
+	return source
+}
+
+// protoDefinitionLines returns the lines of the messages, enums, services and service
+// methods of a .proto file by full name. It only tokenizes the file, which is enough to
+// follow the nesting of definitions.
+func protoDefinitionLines(content string) map[string]int {
+	type protoToken struct {
+		text string
+		line int
+	}
+	var tokens []protoToken
+	line := 1
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == '/' && strings.HasPrefix(content[i:], "//"):
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(content) - i - 2
+			}
+			line += strings.Count(content[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(content) && content[j] != c && content[j] != '\n' {
+				if content[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, protoToken{text: "string", line: line})
+			i = j + 1
+		case c == '_' || c == '.' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+			j := i
+			for j < len(content) && (content[j] == '_' || content[j] == '.' ||
+				unicode.IsLetter(rune(content[j])) || unicode.IsDigit(rune(content[j]))) {
+				j++
+			}
+			tokens = append(tokens, protoToken{text: content[i:j], line: line})
+			i = j
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		default:
+			tokens = append(tokens, protoToken{text: string(c), line: line})
+			i++
+		}
+	}
+
+	lines := make(map[string]int)
+	var pkg string
+	// scopes are the names of the enclosing definitions, empty for other blocks
+	var scopes []string
+	qualify := func(name string) string {
+		parts := []string{}
+		if pkg != "" {
+			parts = append(parts, pkg)
+		}
+		for _, scope := range scopes {
+			if scope != "" {
+				parts = append(parts, scope)
+			}
+		}
+		return strings.Join(append(parts, name), ".")
+	}
+	// pending is the name of the definition whose block is opened by the next brace
+	pending := ""
+	for i, tok := range tokens {
+		next := ""
+		if i+1 < len(tokens) {
+			next = tokens[i+1].text
+		}
+		switch tok.text {
+		case "package":
+			if len(scopes) == 0 {
+				pkg = next
+			}
+		case "message", "enum", "service":
+			if next != "" && next != "{" && (i == 0 || tokens[i-1].text != ".") {
+				lines[qualify(next)] = tok.line
+				pending = next
+			}
+		case "rpc":
+			if next != "" {
+				lines[qualify(next)] = tok.line
+			}
+		case "{":
+			scopes = append(scopes, pending)
+			pending = ""
+		case "}":
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+		case ";":
+			pending = ""
+		}
+	}
+	return lines
+}
+
+func (definitions *ProtoDefinitions) String() string {
+	var b strings.Builder
+	pkg := ""
+	for _, definition := range definitions.Definitions {
+		if definition.GoPackage != pkg {
+			if pkg != "" {
+				b.WriteString("\n")
+			}
+			pkg = definition.GoPackage
+			fmt.Fprintf(&b, "Package %s:\n", pkg)
+		}
+		var goTypes []string
+		for _, goType := range definition.GoTypes {
+			if definitions.Detailed {
+				goTypes = append(goTypes, fmt.Sprintf("%s (%s:%d)", goType.Name, goType.File, goType.Line))
+			} else {
+				goTypes = append(goTypes, fmt.Sprintf("%s (%s:%d)", goType.Name, filepath.Base(goType.File), goType.Line))
+			}
+		}
+		if len(goTypes) == 0 {
+			goTypes = append(goTypes, "no Go type")
+		}
+		protoLocation := definition.ProtoFile
+		if definition.ProtoLine > 0 {
+			protoLocation += ":" + strconv.Itoa(definition.ProtoLine)
+		}
+		fmt.Fprintf(&b, "%s %s -> %s, defined in %s\n", definition.Kind, definition.Name, strings.Join(goTypes, ", "), protoLocation)
+		if !definitions.Detailed {
+			continue
+		}
+		for _, field := range definition.Fields {
+			if field.Number == 0 {
+				fmt.Fprintf(&b, "  oneof %s -> %s %s\n", field.Name, field.GoName, field.GoType)
+				continue
+			}
+			fmt.Fprintf(&b, "  %d %s -> %s %s\n", field.Number, field.Name, field.GoName, field.GoType)
+		}
+		for _, method := range definition.Methods {
+			input, output := method.Input, method.Output
+			if method.ClientStreaming {
+				input = "stream " + input
+			}
+			if method.ServerStreaming {
+				output = "stream " + output
+			}
+			fmt.Fprintf(&b, "  rpc %s(%s) returns (%s)", method.Name, input, output)
+			if method.ProtoLine > 0 {
+				fmt.Fprintf(&b, " at line %d", method.ProtoLine)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
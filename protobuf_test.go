@@ -0,0 +1,186 @@
+package go_mcp_tools
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestProtobuf(t *testing.T) {
+	t.Parallel()
+
+	// The descriptor embedded in the generated code, as protoc-gen-go serializes it
+	descriptor, err := proto.Marshal(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("shop/v1/order.proto"),
+		Package: proto.String("shop.v1"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:       proto.String("Order"),
+			NestedType: []*descriptorpb.DescriptorProto{{Name: proto.String("line_item")}},
+			EnumType:   []*descriptorpb.EnumDescriptorProto{{Name: proto.String("Status")}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("OrderService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Get"), InputType: proto.String(".shop.v1.Order"), OutputType: proto.String(".shop.v1.Order")},
+				{Name: proto.String("Watch"), InputType: proto.String(".shop.v1.Order"), OutputType: proto.String(".shop.v1.Order"), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/shop", "", "go 1.22", ""},
+		"proto/shop/v1/order.proto": {
+			`syntax = "proto3";`,                     // 1
+			``,                                       // 2
+			`package shop.v1;`,                       // 3
+			``,                                       // 4
+			`// An order { with braces in a comment`, // 5
+			`message Order {`,                        // 6
+			`  string id = 1;`,                       // 7
+			`  message line_item {`,                  // 8
+			`    string sku = 1;`,                    // 9
+			`  }`,                                    // 10
+			`  enum Status { STATUS_UNSPECIFIED = 0; }`, // 11
+			`  repeated line_item items = 2;`,           // 12
+			`  oneof payment { string card = 3; }`,      // 13
+			`}`,                                         // 14
+			``,                                          // 15
+			`service OrderService {`,                    // 16
+			`  rpc Get(Order) returns (Order);`,         // 17
+			`  rpc Watch(Order) returns (stream Order) {}`, // 18
+			`}`, // 19
+			``,
+		},
+		"gen/shop/v1/order.pb.go": {
+			"// Code generated by protoc-gen-go. DO NOT EDIT.",
+			"// source: shop/v1/order.proto",
+			"",
+			"package shopv1",
+			"",
+			"type Order_Status int32",
+			"",
+			"type Order struct {",
+			"	state         int",
+			"	Id            string              `protobuf:\"bytes,1,opt,name=id,proto3\" json:\"id,omitempty\"`",
+			"	Items         []*OrderLineItem    `protobuf:\"bytes,2,rep,name=items,proto3\" json:\"items,omitempty\"`",
+			"	Payment       isOrder_Payment     `protobuf_oneof:\"payment\"`",
+			"}",
+			"",
+			"type isOrder_Payment interface{ isOrder_Payment() }",
+			"",
+			"type OrderLineItem struct {",
+			"	Sku string `protobuf:\"bytes,1,opt,name=sku,proto3\" json:\"sku,omitempty\"`",
+			"}",
+			"",
+			"const file_shop_v1_order_proto_rawDesc = \"\" +",
+			"	" + strconv.Quote(string(descriptor[:10])) + " +",
+			"	" + strconv.Quote(string(descriptor[10:])),
+			"",
+		},
+		"gen/shop/v1/order_grpc.pb.go": {
+			"// Code generated by protoc-gen-go-grpc. DO NOT EDIT.",
+			"",
+			"package shopv1",
+			"",
+			"type OrderServiceClient interface{}",
+			"",
+			"type OrderServiceServer interface{}",
+			"",
+		},
+		"plain/plain.go": {"package plain", ""},
+	})
+
+	t.Run("package listing", func(t *testing.T) {
+		t.Parallel()
+		for _, pkg := range []string{"gen/shop/v1", "example.com/shop/gen/shop/v1"} {
+			definitions, err := FindProtoDefinitions(workspace, pkg, "")
+			if err != nil {
+				t.Fatalf("Failed to list %s: %v", pkg, err)
+			}
+			expected := strings.Join([]string{
+				"Package example.com/shop/gen/shop/v1:",
+				"message shop.v1.Order -> Order (order.pb.go:8), defined in proto/shop/v1/order.proto:6",
+				"enum shop.v1.Order.Status -> Order_Status (order.pb.go:6), defined in proto/shop/v1/order.proto:11",
+				"message shop.v1.Order.line_item -> OrderLineItem (order.pb.go:17), defined in proto/shop/v1/order.proto:8",
+				"service shop.v1.OrderService -> OrderServiceClient (order_grpc.pb.go:5), OrderServiceServer (order_grpc.pb.go:7), defined in proto/shop/v1/order.proto:16",
+				"",
+			}, "\n")
+			if definitions.String() != expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", expected, definitions)
+			}
+		}
+	})
+
+	t.Run("lookup by name", func(t *testing.T) {
+		t.Parallel()
+		for _, name := range []string{"shop.v1.Order", "Order", ".shop.v1.Order"} {
+			definitions, err := FindProtoDefinitions(filepath.Join(workspace, "plain"), "", name)
+			if err != nil {
+				t.Fatalf("Failed to look up %s: %v", name, err)
+			}
+			expected := strings.Join([]string{
+				"Package example.com/shop/gen/shop/v1:",
+				"message shop.v1.Order -> Order (gen/shop/v1/order.pb.go:8), defined in proto/shop/v1/order.proto:6",
+				"  1 id -> Id string",
+				"  2 items -> Items []*OrderLineItem",
+				"  oneof payment -> Payment isOrder_Payment",
+				"",
+			}, "\n")
+			if definitions.String() != expected {
+				t.Errorf("Expected for %s:\n%s\ngot:\n%s", name, expected, definitions)
+			}
+		}
+
+		definitions, err := FindProtoDefinitions(workspace, "", "OrderServiceServer")
+		if err != nil {
+			t.Fatalf("Failed to look up service: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Package example.com/shop/gen/shop/v1:",
+			"service shop.v1.OrderService -> OrderServiceClient (gen/shop/v1/order_grpc.pb.go:5), OrderServiceServer (gen/shop/v1/order_grpc.pb.go:7), defined in proto/shop/v1/order.proto:16",
+			"  rpc Get(shop.v1.Order) returns (shop.v1.Order) at line 17",
+			"  rpc Watch(shop.v1.Order) returns (stream shop.v1.Order) at line 18",
+			"",
+		}, "\n")
+		if definitions.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, definitions)
+		}
+	})
+
+	t.Run("go names", func(t *testing.T) {
+		t.Parallel()
+		for name, expected := range map[string]string{
+			"Order":            "Order",
+			"Order.line_item":  "OrderLineItem",
+			"http_rule":        "HttpRule",
+			"_private":         "XPrivate",
+			"Outer.Inner.Leaf": "Outer_Inner_Leaf",
+			"v2_api":           "V2Api",
+		} {
+			if got := protoGoName(name); got != expected {
+				t.Errorf("Expected Go name %s for %s, got %s", expected, name, got)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		for name, args := range map[string][2]string{
+			"no package or name": {"", ""},
+			"unknown name":       {"", "Missing"},
+			"no generated code":  {"plain", ""},
+			"missing package":    {"missing", ""},
+		} {
+			if _, err := FindProtoDefinitions(workspace, args[0], args[1]); err == nil {
+				t.Errorf("Expected an error for %s", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CostLevel is a normalized hint of how expensive a tool call is, so agent
+// frameworks can budget calls on shared servers
+type CostLevel string
+
+const (
+	// CostLow tools parse a single file or package and return in milliseconds
+	CostLow CostLevel = "low"
+	// CostMedium tools type check packages or search the workspace
+	CostMedium CostLevel = "medium"
+	// CostHigh tools run external programs such as gopls, the go command or tests
+	CostHigh CostLevel = "high"
+)
+
+// ToolCost describes the cost of a tool and which quotas its calls count against
+type ToolCost struct {
+	Level CostLevel
+	// Mutating tools modify files in the workspace
+	Mutating bool
+	// RunsTests tools execute tests or benchmarks
+	RunsTests bool
+	// TestRunArgument is a boolean argument making a call of the tool execute tests,
+	// which then counts like a call of a RunsTests tool
+	TestRunArgument string
+}
+
+// builtinToolCosts holds the costs of the tools registered by NewMCPServer
+var builtinToolCosts = map[string]ToolCost{
+	inspectToolName:             {Level: CostMedium, TestRunArgument: "run_examples"},
+	batchInspectToolName:        {Level: CostMedium, TestRunArgument: "run_examples"},
+	bodyToolName:                {Level: CostLow},
+	contextAtToolName:           {Level: CostMedium},
+	typeOfToolName:              {Level: CostMedium},
+	structLayoutToolName:        {Level: CostMedium},
+	jsonSchemaToolName:          {Level: CostMedium},
+	completionToolName:          {Level: CostMedium},
+	signatureHelpToolName:       {Level: CostMedium},
+	inlayHintsToolName:          {Level: CostMedium},
+	docToolName:                 {Level: CostHigh},
+	apiSurfaceToolName:          {Level: CostMedium},
+	apiStabilityToolName:        {Level: CostMedium},
+	changeImpactToolName:        {Level: CostHigh},
+	affectedTestsToolName:       {Level: CostHigh},
+	minimalReproToolName:        {Level: CostHigh},
+	protobufToolName:            {Level: CostMedium},
+	apiDiffToolName:             {Level: CostHigh},
+	releaseNotesToolName:        {Level: CostHigh},
+	analyzeToolName:             {Level: CostHigh},
+	codefixToolName:             {Level: CostHigh, Mutating: true},
+	deadcodeToolName:            {Level: CostHigh},
+	unusedExportedToolName:      {Level: CostMedium},
+	untestedExportedToolName:    {Level: CostHigh},
+	initOrderToolName:           {Level: CostMedium},
+	renameToolName:              {Level: CostHigh, Mutating: true},
+	renamePackageToolName:       {Level: CostMedium, Mutating: true},
+	moveSymbolToolName:          {Level: CostMedium, Mutating: true},
+	inlineToolName:              {Level: CostHigh, Mutating: true},
+	generateStubsToolName:       {Level: CostMedium, Mutating: true},
+	generateStringerToolName:    {Level: CostLow, Mutating: true},
+	generateConstructorToolName: {Level: CostLow, Mutating: true},
+	extractInterfaceToolName:    {Level: CostMedium, Mutating: true},
+	reviewFunctionToolName:      {Level: CostMedium, Mutating: true},
+	sortToolName:                {Level: CostLow, Mutating: true},
+	modTidyToolName:             {Level: CostMedium, Mutating: true},
+	hotspotsToolName:            {Level: CostMedium},
+	metricsToolName:             {Level: CostMedium},
+	overviewToolName:            {Level: CostLow},
+	architectureToolName:        {Level: CostMedium},
+	packageGraphToolName:        {Level: CostMedium},
+	diGraphToolName:             {Level: CostHigh},
+	ormMappingsToolName:         {Level: CostHigh},
+	kubernetesToolName:          {Level: CostMedium},
+	terraformToolName:           {Level: CostMedium},
+	sharedExportsToolName:       {Level: CostMedium},
+	wasmToolName:                {Level: CostHigh},
+	importRulesToolName:         {Level: CostMedium},
+	duplicatesToolName:          {Level: CostMedium},
+	depsToolName:                {Level: CostMedium},
+	importCostToolName:          {Level: CostMedium},
+	vulncheckToolName:           {Level: CostHigh},
+	listTestsToolName:           {Level: CostLow},
+	benchmarkToolName:           {Level: CostHigh, RunsTests: true},
+	raceToolName:                {Level: CostHigh, RunsTests: true},
+	racyGlobalsToolName:         {Level: CostHigh},
+	stressToolName:              {Level: CostHigh, RunsTests: true},
+	testConventionsToolName:     {Level: CostMedium},
+	conventionsToolName:         {Level: CostMedium},
+	commitToolName:              {Level: CostLow, Mutating: true},
+	worktreeDiffToolName:        {Level: CostLow},
+	worktreeDiscardToolName:     {Level: CostLow, Mutating: true},
+}
+
+// Quotas limits the number of tool calls per client session. Zero means unlimited.
+type Quotas struct {
+	MaxMutatingCalls int
+	MaxTestRuns      int
+	// MaxCalls limits calls of individual tools by name
+	MaxCalls map[string]int
+}
+
+// WithToolCost sets the cost of a tool, typically one added with WithTool.
+// The cost is appended to the tool description and decides which quotas apply.
+func WithToolCost(name string, cost ToolCost) Option {
+	return func(o *serverOptions) {
+		o.toolCosts[name] = cost
+	}
+}
+
+// WithQuotas limits how many expensive tool calls each client session may make
+func WithQuotas(quotas Quotas) Option {
+	return func(o *serverOptions) {
+		o.quotas = &quotas
+	}
+}
+
+// costHint formats the cost of a tool for its description
+func costHint(cost ToolCost) string {
+	hint := fmt.Sprintf("Cost: %s.", cost.Level)
+	if cost.Mutating {
+		hint += " Modifies files and counts against the mutating call quota."
+	}
+	if cost.RunsTests {
+		hint += " Runs tests and counts against the test run quota."
+	} else if cost.TestRunArgument != "" {
+		hint += fmt.Sprintf(" Runs tests with %s and then counts against the test run quota.", cost.TestRunArgument)
+	}
+	return hint
+}
+
+// costHintFilter appends the cost hint to the description of listed tools
+func costHintFilter(costs map[string]ToolCost) server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		for i, tool := range tools {
+			cost, ok := costs[tool.Name]
+			if !ok || cost.Level == "" {
+				continue
+			}
+			tools[i].Description = strings.TrimRight(tool.Description, "\n") + "\n\n" + costHint(cost)
+		}
+		return tools
+	}
+}
+
+// quotaUsage counts the calls made by a single session
+type quotaUsage struct {
+	mutatingCalls int
+	testRuns      int
+	calls         map[string]int
+}
+
+// quotaUsages holds the usage of the sessions by session ID
+type quotaUsages struct {
+	mu    sync.Mutex
+	usage map[string]*quotaUsage
+}
+
+func newQuotaUsages() *quotaUsages {
+	return &quotaUsages{usage: make(map[string]*quotaUsage)}
+}
+
+// removeSession forgets the usage of an ended session
+func (usages *quotaUsages) removeSession(sessionID string) {
+	usages.mu.Lock()
+	defer usages.mu.Unlock()
+	delete(usages.usage, sessionID)
+}
+
+// quotaMiddleware rejects tool calls exceeding the quotas of the calling session.
+// Calls without a session, e.g. from the jsonl transport, share a single budget.
+func quotaMiddleware(quotas Quotas, costs map[string]ToolCost, usages *quotaUsages) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			sessionID := ""
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				sessionID = session.SessionID()
+			}
+			name := request.Params.Name
+			cost := costs[name]
+			if request.GetBool(cost.TestRunArgument, false) {
+				cost.RunsTests = true
+			}
+
+			usages.mu.Lock()
+			used, ok := usages.usage[sessionID]
+			if !ok {
+				used = &quotaUsage{calls: make(map[string]int)}
+				usages.usage[sessionID] = used
+			}
+			if message := quotaExceeded(quotas, cost, name, used); message != "" {
+				usages.mu.Unlock()
+				return toolErrorResult("Error: " + message), nil
+			}
+			used.calls[name]++
+			if cost.Mutating {
+				used.mutatingCalls++
+			}
+			if cost.RunsTests {
+				used.testRuns++
+			}
+			usages.mu.Unlock()
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// quotaExceeded returns a message describing the exceeded quota, or an empty string if the call is allowed
+func quotaExceeded(quotas Quotas, cost ToolCost, name string, used *quotaUsage) string {
+	if limit := quotas.MaxCalls[name]; limit > 0 && used.calls[name] >= limit {
+		return fmt.Sprintf("quota of %d %s tool call(s) per session is used up", limit, name)
+	}
+	if cost.Mutating && quotas.MaxMutatingCalls > 0 && used.mutatingCalls >= quotas.MaxMutatingCalls {
+		return fmt.Sprintf(
+			"quota of %d mutating tool call(s) per session is used up, %s modifies files. Read-only tools can still be used.",
+			quotas.MaxMutatingCalls,
+			name,
+		)
+	}
+	if cost.RunsTests && quotas.MaxTestRuns > 0 && used.testRuns >= quotas.MaxTestRuns {
+		return fmt.Sprintf(
+			"quota of %d test run(s) per session is used up, %s runs tests",
+			quotas.MaxTestRuns,
+			name,
+		)
+	}
+	return ""
+}
@@ -0,0 +1,219 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestQuotas(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to call a tool and return the encoded response
+	callTool := func(t testing.TB, mcpServer *server.MCPServer, name string) string {
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params":  map[string]any{"name": name, "arguments": map[string]any{}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response, err := json.Marshal(mcpServer.HandleMessage(context.Background(), encoded))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(response)
+	}
+
+	okHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	}
+
+	t.Run("cost hints are listed", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(
+			WithTool(mcp.NewTool("test", mcp.WithDescription("Runs tests")), okHandler),
+			WithToolCost("test", ToolCost{Level: CostHigh, RunsTests: true}),
+		)
+		encoded, err := json.Marshal(mcpServer.HandleMessage(
+			context.Background(),
+			json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		listing := string(encoded)
+		for _, hint := range []string{
+			"Runs tests\\n\\nCost: high. Runs tests and counts against the test run quota.",
+			"Cost: low. Modifies files and counts against the mutating call quota.",
+			"Cost: medium.",
+		} {
+			if !strings.Contains(listing, hint) {
+				t.Errorf("Expected %q in tool listing, got: %s", hint, listing)
+			}
+		}
+	})
+
+	t.Run("quotas are enforced", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(
+			WithTool(mcp.NewTool("write"), okHandler),
+			WithTool(mcp.NewTool("test"), okHandler),
+			WithTool(mcp.NewTool("read"), okHandler),
+			WithToolCost("write", ToolCost{Level: CostLow, Mutating: true}),
+			WithToolCost("test", ToolCost{Level: CostHigh, RunsTests: true}),
+			WithQuotas(Quotas{
+				MaxMutatingCalls: 2,
+				MaxTestRuns:      1,
+				MaxCalls:         map[string]int{"read": 3},
+			}),
+		)
+
+		calls := []struct {
+			tool     string
+			rejected string
+		}{
+			{"write", ""},
+			{"test", ""},
+			{"write", ""},
+			{"write", "quota of 2 mutating tool call(s) per session is used up"},
+			{"test", "quota of 1 test run(s) per session is used up"},
+			{"read", ""},
+			{"read", ""},
+			{"read", ""},
+			{"read", "quota of 3 read tool call(s) per session is used up"},
+		}
+		for i, call := range calls {
+			response := callTool(t, mcpServer, call.tool)
+			if call.rejected == "" {
+				if !strings.Contains(response, "done") {
+					t.Errorf("Expected call %d to %s to succeed, got: %s", i+1, call.tool, response)
+				}
+				continue
+			}
+			if !strings.Contains(response, call.rejected) || !strings.Contains(response, `"isError":true`) {
+				t.Errorf("Expected call %d to %s to be rejected with %q, got: %s", i+1, call.tool, call.rejected, response)
+			}
+		}
+	})
+
+	t.Run("built-in test running tools count as test runs", func(t *testing.T) {
+		t.Parallel()
+		// Helper function to call a tool with valid arguments, failing fast outside a module
+		callInEmptyDir := func(t testing.TB, mcpServer *server.MCPServer, name string) string {
+			message, err := toolCallMessage(name, map[string]any{"workspace_dir": t.TempDir()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := json.Marshal(mcpServer.HandleMessage(context.Background(), message))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(response)
+		}
+
+		for _, name := range []string{benchmarkToolName, raceToolName, stressToolName} {
+			mcpServer := NewMCPServer(WithQuotas(Quotas{MaxTestRuns: 1}))
+			if response := callInEmptyDir(t, mcpServer, name); strings.Contains(response, "quota") {
+				t.Errorf("Expected the first %s call to be allowed, got: %s", name, response)
+			}
+			if response := callInEmptyDir(t, mcpServer, name); !strings.Contains(response, "quota of 1 test run(s) per session is used up") {
+				t.Errorf("Expected the second %s call to exceed the test run quota, got: %s", name, response)
+			}
+		}
+	})
+
+	t.Run("running examples counts as a test run", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(WithQuotas(Quotas{MaxTestRuns: 1}))
+		workspace := t.TempDir()
+		inspect := func(runExamples bool) string {
+			message, err := toolCallMessage(inspectToolName, map[string]any{
+				"path":          filepath.Join(workspace, "missing.go") + ":Name",
+				"workspace_dir": workspace,
+				"run_examples":  runExamples,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := json.Marshal(mcpServer.HandleMessage(context.Background(), message))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(response)
+		}
+
+		for i, test := range []struct {
+			runExamples bool
+			rejected    bool
+		}{{false, false}, {true, false}, {false, false}, {true, true}} {
+			response := inspect(test.runExamples)
+			if rejected := strings.Contains(response, "quota of 1 test run(s) per session is used up"); rejected != test.rejected {
+				t.Errorf("Expected call %d with run_examples=%t to be rejected: %t, got: %s", i+1, test.runExamples, test.rejected, response)
+			}
+		}
+	})
+	t.Run("usage of ended sessions is removed", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(
+			WithTool(mcp.NewTool("read"), okHandler),
+			WithQuotas(Quotas{MaxCalls: map[string]int{"read": 1}}),
+		)
+		session := &testSession{id: "ending"}
+		read := func() string {
+			message, err := toolCallMessage("read", map[string]any{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := mcpServer.WithContext(context.Background(), session)
+			response, err := json.Marshal(mcpServer.HandleMessage(ctx, message))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(response)
+		}
+
+		if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+			t.Fatal(err)
+		}
+		if response := read(); !strings.Contains(response, "done") {
+			t.Fatalf("Expected the first call to succeed, got: %s", response)
+		}
+		if response := read(); !strings.Contains(response, "quota of 1 read tool call(s) per session is used up") {
+			t.Fatalf("Expected the second call to exceed the quota, got: %s", response)
+		}
+		mcpServer.UnregisterSession(context.Background(), session.id)
+		if response := read(); !strings.Contains(response, "done") {
+			t.Errorf("Expected the usage to be removed with the session, got: %s", response)
+		}
+	})
+
+	t.Run("repository tools count as mutating calls", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(WithCommitTool(CommitOptions{}), WithQuotas(Quotas{MaxMutatingCalls: 1}))
+		commit := func() string {
+			message, err := toolCallMessage(commitToolName, map[string]any{"message": "Change", "workspace_dir": t.TempDir()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := json.Marshal(mcpServer.HandleMessage(context.Background(), message))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(response)
+		}
+		if response := commit(); strings.Contains(response, "quota") {
+			t.Errorf("Expected the first commit to be allowed, got: %s", response)
+		}
+		if response := commit(); !strings.Contains(response, "quota of 1 mutating tool call(s) per session is used up") {
+			t.Errorf("Expected the second commit to exceed the mutating call quota, got: %s", response)
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package go_mcp_tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	raceToolName        = "race"
+	raceToolDescription = `Runs the tests of Go packages with the race detector (go test -race) and reports the data races found. Each race lists the conflicting accesses and the creation of the goroutines involved with their stack frames, the source line of the frames in the module, and the signature of the function of the first frame in the module, formatted like the functions of inspect results.
+
+Failing tests are listed too, as the race detector fails the tests during which it finds a race. Races only show up when the racing code runs concurrently, so pass count to run the tests several times. The race detector needs cgo and a C compiler.`
+)
+
+func AddRaceTool(mcpServer *server.MCPServer) {
+	handleRace := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		options := RaceOptions{WorkspaceDir: workspaceDir}
+		options.Package = request.GetString("package", "")
+		options.Run = request.GetString("run", "")
+		options.Count = request.GetInt("count", 0)
+
+		report, err := RunRaceTests(ctx, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error running tests with the race detector: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		raceToolName,
+		mcp.WithDescription(raceToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Package pattern to test, relative to the module root"),
+			mcp.DefaultString("./..."),
+		),
+		mcp.WithString("run",
+			mcp.Description("Regular expression selecting the tests to run, as go test -run"),
+		),
+		mcp.WithNumber("count",
+			mcp.Description("Number of times to run each test"),
+			mcp.DefaultNumber(1),
+		),
+	), handleRace)
+}
+
+// RaceOptions select the tests to run with the race detector
+type RaceOptions struct {
+	WorkspaceDir string
+	// Package is the package pattern to test, ./... by default
+	Package string
+	// Run selects the tests to run, all when empty
+	Run string
+	// Count is the number of times to run each test, 1 by default
+	Count int
+}
+
+// RaceReport holds the data races found by go test -race
+type RaceReport struct {
+	// Command is the go test command that was run
+	Command string     `json:"command"`
+	Passed  bool       `json:"passed"`
+	Races   []DataRace `json:"races,omitempty"`
+	// FailedTests are the tests go test reported as failed, e.g. TestCounter/parallel
+	FailedTests []string `json:"failed_tests,omitempty"`
+}
+
+// DataRace is a data race report of the race detector
+type DataRace struct {
+	// Stacks are the conflicting accesses followed by the creation of their goroutines
+	Stacks []RaceStack `json:"stacks"`
+}
+
+// RaceStack is an access or goroutine creation of a data race with its stack
+type RaceStack struct {
+	// Title is the header of the stack, e.g. Write at 0x00c000012345 by goroutine 8
+	Title  string      `json:"title"`
+	Frames []RaceFrame `json:"frames,omitempty"`
+	// Function is the function of the first frame in the module
+	Function *SymbolInfo `json:"function,omitempty"`
+}
+
+// RaceFrame is a stack frame of a data race
+type RaceFrame struct {
+	// Function is the function as the runtime names it, e.g. example.com/app.(*Counter).Inc
+	Function string `json:"function"`
+	// File is relative to the module root for files of the module
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Source is the line of the frame, for files of the module
+	Source string `json:"source,omitempty"`
+}
+
+var (
+	// raceFrameFile matches the file line of a stack frame, e.g. /app/counter.go:7 +0x44
+	raceFrameFile = regexp.MustCompile(`^\s+(\S+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	// raceFailedTest matches the test failures of go test output, e.g. --- FAIL: TestCounter (0.00s)
+	raceFailedTest = regexp.MustCompile(`^\s*--- FAIL: (\S+)`)
+)
+
+// raceSeparator delimits the race reports in the output of a test binary
+const raceSeparator = "=================="
+
+// RunRaceTests runs go test -race in the module containing options.WorkspaceDir and
+// parses the data races of its output
+func RunRaceTests(ctx context.Context, options RaceOptions) (*RaceReport, error) {
+	if !filepath.IsAbs(options.WorkspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", options.WorkspaceDir)
+	}
+	root, err := findModuleRoot(options.WorkspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	if options.Package == "" {
+		options.Package = "./..."
+	}
+	if options.Count <= 0 {
+		options.Count = 1
+	}
+	args := []string{"test", "-race", "-count", strconv.Itoa(options.Count)}
+	if options.Run != "" {
+		args = append(args, "-run", options.Run)
+	}
+	args = append(args, options.Package)
+	return runRaceTests(ctx, root, args, nil)
+}
+
+// runRaceTests runs go with args and the extra environment variables in root and
+// parses the data races of its output
+func runRaceTests(ctx context.Context, root string, args []string, env []string) (*RaceReport, error) {
+	var output bytes.Buffer
+	runErr := runCommand(ctx, root, env, &output, &output, "go", args...)
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return nil, fmt.Errorf("go %s failed: %w", strings.Join(args, " "), runErr)
+	}
+
+	report := parseRaceOutput(root, output.String())
+	report.Command = "go " + strings.Join(args, " ")
+	report.Passed = runErr == nil
+	// Without races or failed tests, go test failed before running the tests, e.g. to build them
+	if !report.Passed && len(report.Races) == 0 && len(report.FailedTests) == 0 {
+		return nil, fmt.Errorf("%s failed: %v\n%s", report.Command, runErr, strings.TrimSpace(output.String()))
+	}
+	return report, nil
+}
+
+// parseRaceOutput parses the race reports and test failures of go test -race run in root
+func parseRaceOutput(root string, output string) *RaceReport {
+	report := &RaceReport{}
+	seenTests := make(map[string]bool)
+	var race *DataRace
+	var stack *RaceStack
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == raceSeparator:
+			if race != nil && len(race.Stacks) > 0 {
+				report.Races = append(report.Races, *race)
+			}
+			race, stack = nil, nil
+		case line == "WARNING: DATA RACE":
+			race = &DataRace{}
+		case race == nil:
+			if match := raceFailedTest.FindStringSubmatch(line); match != nil && !seenTests[match[1]] {
+				seenTests[match[1]] = true
+				report.FailedTests = append(report.FailedTests, match[1])
+			}
+		case line == "":
+			stack = nil
+		case !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ":"):
+			race.Stacks = append(race.Stacks, RaceStack{Title: strings.TrimSuffix(line, ":")})
+			stack = &race.Stacks[len(race.Stacks)-1]
+		case stack == nil:
+		case raceFrameFile.MatchString(line):
+			if len(stack.Frames) > 0 {
+				match := raceFrameFile.FindStringSubmatch(line)
+				frame := &stack.Frames[len(stack.Frames)-1]
+				frame.File = match[1]
+				frame.Line, _ = strconv.Atoi(match[2])
+			}
+		default:
+			stack.Frames = append(stack.Frames, RaceFrame{Function: strings.TrimSpace(line)})
+		}
+	}
+
+	for i := range report.Races {
+		for j := range report.Races[i].Stacks {
+			stack := &report.Races[i].Stacks[j]
+			for k := range stack.Frames {
+				frame := &stack.Frames[k]
+				if !filepath.IsAbs(frame.File) || !isFileInWorkspace(frame.File, root) {
+					continue
+				}
+				if content, err := os.ReadFile(frame.File); err == nil {
+					if lines := strings.Split(string(content), "\n"); frame.Line > 0 && frame.Line <= len(lines) {
+						frame.Source = strings.TrimSpace(lines[frame.Line-1])
+					}
+				}
+				if stack.Function == nil {
+					if funcDecl, fset := findFunctionAtLine(frame.File, frame.Line, nil); funcDecl != nil {
+						info := newFunctionInfo(context.Background(), funcDecl, fset, false, false, DetailSignature, "", nil)
+						stack.Function = &info
+					}
+				}
+				if rel, ok := workspaceRelPath(frame.File, root); ok {
+					frame.File = rel
+				}
+			}
+		}
+	}
+	return report
+}
+
+func (report *RaceReport) String() string {
+	var b strings.Builder
+	status := "ok"
+	if !report.Passed {
+		status = "FAIL"
+	}
+	if len(report.Races) == 0 {
+		fmt.Fprintf(&b, "No data races detected by %s (%s)\n", report.Command, status)
+	} else {
+		fmt.Fprintf(&b, "%d data races detected by %s (%s)\n", len(report.Races), report.Command, status)
+	}
+	if len(report.FailedTests) > 0 {
+		fmt.Fprintf(&b, "Failed tests: %s\n", strings.Join(report.FailedTests, ", "))
+	}
+	for i := range report.Races {
+		fmt.Fprintf(&b, "\nData race %d:\n", i+1)
+		writeDataRace(&b, &report.Races[i])
+	}
+	return b.String()
+}
+
+func writeDataRace(b *strings.Builder, race *DataRace) {
+	for _, stack := range race.Stacks {
+		fmt.Fprintf(b, "  %s:\n", stack.Title)
+		for _, frame := range stack.Frames {
+			fmt.Fprintf(b, "    %s", frame.Function)
+			if frame.File != "" {
+				fmt.Fprintf(b, " (%s:%d)", frame.File, frame.Line)
+			}
+			b.WriteString("\n")
+			if frame.Source != "" {
+				fmt.Fprintf(b, "      %s\n", frame.Source)
+			}
+		}
+		if stack.Function == nil {
+			continue
+		}
+		var function strings.Builder
+		writeFunction(&function, stack.Function)
+		for line := range strings.SplitSeq(function.String(), "\n") {
+			if line != "" {
+				fmt.Fprintf(b, "    %s\n", line)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRace(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/race", "", "go 1.22", ""},
+		"counter.go": {
+			"package race", // 1
+			"",
+			"type Counter struct{ n int }", // 3
+			"",
+			"// Inc increments the counter without synchronization", // 5
+			"func (c *Counter) Inc() {",
+			"	c.n++", // 7
+			"}",
+			"",
+		},
+		"counter_test.go": {
+			"package race", // 1
+			"",
+			"import \"testing\"", // 3
+			"",
+			"func TestCounter(t *testing.T) {", // 5
+			"	c := &Counter{}",
+			"	done := make(chan bool)",
+			"	go func() {", // 8
+			"		c.Inc()",
+			"		done <- true", // 10
+			"	}()",
+			"	c.Inc()", // 12
+			"	<-done",
+			"}",
+			"",
+			"func TestPass(t *testing.T) {}", // 16
+			"",
+		},
+		"broken/broken.go": {"package broken", "", "func Broken() { undefined() }", ""},
+	})
+
+	t.Run("data race", func(t *testing.T) {
+		t.Parallel()
+		report, err := RunRaceTests(context.Background(), RaceOptions{WorkspaceDir: workspace, Package: "."})
+		if err != nil {
+			t.Fatalf("Failed to run tests: %v", err)
+		}
+		if report.Passed || len(report.Races) != 1 || strings.Join(report.FailedTests, ",") != "TestCounter" {
+			t.Fatalf("Expected one race failing TestCounter, got:\n%s", report)
+		}
+		stacks := report.Races[0].Stacks
+		if len(stacks) != 4 || !strings.Contains(stacks[0].Title, " by goroutine ") ||
+			!strings.HasPrefix(stacks[1].Title, "Previous ") || !strings.HasPrefix(stacks[2].Title, "Goroutine ") {
+			t.Fatalf("Expected both accesses and goroutine creations, got:\n%s", report)
+		}
+		frame := stacks[0].Frames[0]
+		if frame.Function != "example.com/race.(*Counter).Inc()" || frame.File != "counter.go" || frame.Line != 7 ||
+			frame.Source != "c.n++" {
+			t.Errorf("Unexpected first frame %+v", frame)
+		}
+		if function := stacks[0].Function; function == nil || function.Name != "Inc" || function.Doc == "" {
+			t.Errorf("Expected the Inc method as the function of the access, got %+v", function)
+		}
+		text := report.String()
+		for _, expected := range []string{
+			"1 data races detected by go test -race -count 1 . (FAIL)\nFailed tests: TestCounter\n",
+			"    example.com/race.(*Counter).Inc() (counter.go:7)\n      c.n++\n",
+			"    example.com/race.TestCounter.func1() (counter_test.go:9)\n      c.Inc()\n",
+			"    Code:\n    func (c *Counter) Inc()",
+		} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Expected the report to contain %q, got:\n%s", expected, text)
+			}
+		}
+	})
+
+	t.Run("no race", func(t *testing.T) {
+		t.Parallel()
+		report, err := RunRaceTests(context.Background(), RaceOptions{WorkspaceDir: workspace, Package: ".", Run: "TestPass", Count: 2})
+		if err != nil {
+			t.Fatalf("Failed to run tests: %v", err)
+		}
+		if text := report.String(); text != "No data races detected by go test -race -count 2 -run TestPass . (ok)\n" {
+			t.Errorf("Unexpected report:\n%s", text)
+		}
+	})
+
+	t.Run("build failure", func(t *testing.T) {
+		t.Parallel()
+		if _, err := RunRaceTests(context.Background(), RaceOptions{WorkspaceDir: workspace, Package: "./broken"}); err == nil ||
+			!strings.Contains(err.Error(), "undefined") {
+			t.Errorf("Expected the build error, got: %v", err)
+		}
+	})
+}
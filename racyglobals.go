@@ -0,0 +1,539 @@
+package go_mcp_tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+const (
+	racyGlobalsToolName        = "racy_globals"
+	racyGlobalsToolDescription = `Finds the package level variables of a Go module that may be accessed by several goroutines without synchronization, candidates for data races, with their read and write sites and the goroutines reaching them. Unlike the race tool, nothing runs: the code is analyzed statically.
+
+The goroutines are the main goroutine, running main, the exported API of library packages and everything they call, and those started by go statements and net/http handlers, each with everything it calls through a static call graph by Class Hierarchy Analysis. A variable is reported when it is written outside init and accessed from several goroutines, or written from goroutines that run concurrently with themselves: HTTP handlers and goroutines started in loops.
+
+Accesses in functions that lock a sync.Mutex or sync.RWMutex, use sync/atomic or sync.Once, or in closures of such functions, are considered synchronized, as are variables of the types of sync and sync/atomic and channels. This is a heuristic: calls through interfaces reach every implementation, and locks are not matched to the variables they guard.`
+)
+
+func AddRacyGlobalsTool(mcpServer *server.MCPServer) {
+	handleRacyGlobals := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := RacyGlobals(ctx, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding racy globals: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		racyGlobalsToolName,
+		mcp.WithDescription(racyGlobalsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to analyze"),
+			mcp.Required(),
+		),
+	), handleRacyGlobals)
+}
+
+// RacyGlobalsReport lists the package level variables of a module that may be accessed
+// concurrently without synchronization
+type RacyGlobalsReport struct {
+	Module  string       `json:"module"`
+	Globals []RacyGlobal `json:"globals,omitempty"`
+}
+
+// RacyGlobal is a package level variable accessed by several goroutines
+type RacyGlobal struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	Type    string `json:"type"`
+	// File is relative to the module root
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Goroutines are the goroutines accessing the variable without synchronization
+	Goroutines []string       `json:"goroutines"`
+	Accesses   []GlobalAccess `json:"accesses"`
+}
+
+// GlobalAccess is a read or write of a package level variable without synchronization
+type GlobalAccess struct {
+	Write bool `json:"write"`
+	// File is relative to the module root
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+	// Goroutines are the goroutines the function runs in
+	Goroutines []string `json:"goroutines"`
+}
+
+// goroutineRoot is a function starting a goroutine, or the functions of the main goroutine
+type goroutineRoot struct {
+	label     string
+	functions []*ssa.Function
+	// concurrent is set when several instances of the goroutine may run at once
+	concurrent bool
+}
+
+// RacyGlobals finds the package level variables of the module containing workspaceDir
+// accessed by several goroutines without synchronization cues
+func RacyGlobals(ctx context.Context, workspaceDir string) (*RacyGlobalsReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	initial, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  module.root,
+		Env:  packagesEnv(module.root),
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	for _, pkg := range initial {
+		if len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors))
+		}
+	}
+	prog, ssaPkgs := ssautil.AllPackages(initial, ssa.InstantiateGenerics)
+	buildProgram(prog)
+	graph := cha.CallGraph(prog)
+
+	modulePackage := func(pkg *ssa.Package) bool {
+		return pkg != nil && (pkg.Pkg.Path() == module.path || strings.HasPrefix(pkg.Pkg.Path(), module.path+"/"))
+	}
+	inModule := func(fn *ssa.Function) bool {
+		for fn.Parent() != nil {
+			fn = fn.Parent()
+		}
+		if origin := fn.Origin(); origin != nil {
+			fn = origin
+		}
+		return modulePackage(fn.Pkg)
+	}
+	position := func(pos token.Pos) token.Position { return prog.Fset.Position(pos) }
+	location := func(pos token.Pos) string {
+		return fmt.Sprintf("%s:%d", module.relPath(position(pos).Filename), position(pos).Line)
+	}
+
+	var functions []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		if inModule(fn) {
+			functions = append(functions, fn)
+		}
+	}
+	slices.SortFunc(functions, func(a, b *ssa.Function) int { return cmp.Compare(a.Pos(), b.Pos()) })
+
+	mainRoot := &goroutineRoot{label: "main goroutine"}
+	var roots []*goroutineRoot
+	for _, pkg := range ssaPkgs {
+		if pkg == nil {
+			continue
+		}
+		if main := pkg.Func("main"); pkg.Pkg.Name() == "main" && main != nil {
+			mainRoot.functions = append(mainRoot.functions, main)
+			continue
+		}
+		// The callers of a library run its exported API in their goroutines
+		for _, member := range pkg.Members {
+			if fn, ok := member.(*ssa.Function); ok && token.IsExported(fn.Name()) {
+				mainRoot.functions = append(mainRoot.functions, fn)
+			}
+			if t, ok := member.(*ssa.Type); ok && t.Object().Exported() {
+				methods := prog.MethodSets.MethodSet(types.NewPointer(t.Type()))
+				for selection := range methods.Methods() {
+					if fn := prog.MethodValue(selection); fn != nil && selection.Obj().Exported() {
+						mainRoot.functions = append(mainRoot.functions, fn)
+					}
+				}
+			}
+		}
+	}
+	roots = append(roots, mainRoot)
+
+	for _, fn := range functions {
+		if isHTTPHandler(fn) {
+			roots = append(roots, &goroutineRoot{
+				label:      fmt.Sprintf("HTTP handler %s", racyFunctionName(fn)),
+				functions:  []*ssa.Function{fn},
+				concurrent: true,
+			})
+		}
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				goStmt, ok := instr.(*ssa.Go)
+				if !ok {
+					continue
+				}
+				root := &goroutineRoot{label: "goroutine started at " + location(goStmt.Pos()), concurrent: inLoop(block)}
+				if root.concurrent {
+					root.label += " in a loop"
+				}
+				if callee := goStmt.Call.StaticCallee(); callee != nil {
+					root.functions = append(root.functions, callee)
+				} else if node := graph.Nodes[fn]; node != nil {
+					for _, edge := range node.Out {
+						if edge.Site == goStmt {
+							root.functions = append(root.functions, edge.Callee.Func)
+						}
+					}
+				}
+				roots = append(roots, root)
+			}
+		}
+	}
+
+	// goroutines maps the functions of the module to the goroutines they run in
+	goroutines := make(map[*ssa.Function][]*goroutineRoot)
+	valueTaken := functionValues(functions)
+	for _, root := range roots {
+		for fn := range goroutineFunctions(graph, root.functions, inModule, valueTaken) {
+			goroutines[fn] = append(goroutines[fn], root)
+		}
+	}
+
+	report := &RacyGlobalsReport{Module: module.path}
+	type access struct {
+		GlobalAccess
+		roots []*goroutineRoot
+	}
+	accesses := make(map[*ssa.Global][]access)
+	for _, fn := range functions {
+		if isPackageInit(fn) || len(goroutines[fn]) == 0 || synchronized(fn) {
+			continue
+		}
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				global, write := globalAccess(instr)
+				if global == nil || !modulePackage(global.Pkg) || !racyGlobalType(global) {
+					continue
+				}
+				pos := instr.Pos()
+				if !pos.IsValid() {
+					pos = fn.Pos()
+				}
+				var labels []string
+				for _, root := range goroutines[fn] {
+					labels = append(labels, root.label)
+				}
+				accesses[global] = append(accesses[global], access{
+					GlobalAccess: GlobalAccess{
+						Write:      write,
+						File:       module.relPath(position(pos).Filename),
+						Line:       position(pos).Line,
+						Function:   racyFunctionName(fn),
+						Goroutines: labels,
+					},
+					roots: goroutines[fn],
+				})
+			}
+		}
+	}
+
+	for global, globalAccesses := range accesses {
+		accessing := make(map[*goroutineRoot]bool)
+		writing := make(map[*goroutineRoot]bool)
+		for _, access := range globalAccesses {
+			for _, root := range access.roots {
+				accessing[root] = true
+				if access.Write {
+					writing[root] = true
+				}
+			}
+		}
+		racy := len(writing) > 0 && len(accessing) > 1
+		for root := range writing {
+			racy = racy || root.concurrent
+		}
+		if !racy {
+			continue
+		}
+
+		object := global.Object()
+		racyGlobal := RacyGlobal{
+			Name:    global.Name(),
+			Package: global.Pkg.Pkg.Path(),
+			Type:    types.TypeString(object.Type(), types.RelativeTo(object.Pkg())),
+			File:    module.relPath(position(object.Pos()).Filename),
+			Line:    position(object.Pos()).Line,
+		}
+		for _, root := range roots {
+			if accessing[root] {
+				racyGlobal.Goroutines = append(racyGlobal.Goroutines, root.label)
+			}
+		}
+		for _, access := range globalAccesses {
+			if !slices.ContainsFunc(racyGlobal.Accesses, func(existing GlobalAccess) bool {
+				return existing.File == access.File && existing.Line == access.Line && existing.Write == access.Write
+			}) {
+				racyGlobal.Accesses = append(racyGlobal.Accesses, access.GlobalAccess)
+			}
+		}
+		slices.SortFunc(racyGlobal.Accesses, func(a, b GlobalAccess) int {
+			return cmp.Or(strings.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line), cmp.Compare(btoi(a.Write), btoi(b.Write)))
+		})
+		report.Globals = append(report.Globals, racyGlobal)
+	}
+	slices.SortFunc(report.Globals, func(a, b RacyGlobal) int {
+		return cmp.Or(strings.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return report, nil
+}
+
+// goroutineFunctions returns the functions of the module reachable from the roots through
+// calls, not following go statements, which start other goroutines. Calls of function values
+// only reach the functions whose value is taken, which CHA does not distinguish.
+func goroutineFunctions(
+	graph *callgraph.Graph,
+	roots []*ssa.Function,
+	inModule func(*ssa.Function) bool,
+	valueTaken map[*ssa.Function]bool,
+) map[*ssa.Function]bool {
+	reached := make(map[*ssa.Function]bool)
+	for queue := slices.Clone(roots); len(queue) > 0; queue = queue[1:] {
+		fn := queue[0]
+		if reached[fn] || !inModule(fn) {
+			continue
+		}
+		reached[fn] = true
+		node := graph.Nodes[fn]
+		if node == nil {
+			continue
+		}
+		for _, edge := range node.Out {
+			if _, ok := edge.Site.(*ssa.Go); ok {
+				continue
+			}
+			call := edge.Site.Common()
+			if call.StaticCallee() == nil && !call.IsInvoke() && !valueTaken[edge.Callee.Func] {
+				continue
+			}
+			queue = append(queue, edge.Callee.Func)
+		}
+	}
+	return reached
+}
+
+// functionValues returns the functions used as values rather than called directly
+func functionValues(functions []*ssa.Function) map[*ssa.Function]bool {
+	values := make(map[*ssa.Function]bool)
+	for _, fn := range functions {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				var callee ssa.Value
+				if call, ok := instr.(ssa.CallInstruction); ok {
+					callee = call.Common().Value
+				}
+				for _, operand := range instr.Operands(nil) {
+					if value, ok := (*operand).(*ssa.Function); ok && *operand != callee {
+						values[value] = true
+					}
+				}
+			}
+		}
+	}
+	return values
+}
+
+// globalAccess returns the package level variable the instruction reads or writes, through
+// fields and elements of it, and whether it writes it
+func globalAccess(instr ssa.Instruction) (*ssa.Global, bool) {
+	// root follows addresses and loaded values to the variable they derive from
+	var root func(value ssa.Value) *ssa.Global
+	root = func(value ssa.Value) *ssa.Global {
+		switch value := value.(type) {
+		case *ssa.Global:
+			return value
+		case *ssa.FieldAddr:
+			return root(value.X)
+		case *ssa.IndexAddr:
+			return root(value.X)
+		case *ssa.UnOp:
+			if value.Op == token.MUL {
+				return root(value.X)
+			}
+		}
+		return nil
+	}
+	switch instr := instr.(type) {
+	case *ssa.Store:
+		return root(instr.Addr), true
+	case *ssa.MapUpdate:
+		return root(instr.Map), true
+	case *ssa.UnOp:
+		// Loads of the variable itself, or of its fields, are reads. Loads of its elements
+		// are counted through the load of the variable.
+		if instr.Op != token.MUL {
+			return nil, false
+		}
+		switch x := instr.X.(type) {
+		case *ssa.Global:
+			return x, false
+		case *ssa.FieldAddr:
+			return root(x), false
+		}
+	case *ssa.Call:
+		if builtin, ok := instr.Call.Value.(*ssa.Builtin); ok && builtin.Name() == "delete" {
+			return root(instr.Call.Args[0]), true
+		}
+	}
+	return nil, false
+}
+
+// racyGlobalType reports whether accesses to the variable may race, which is not the case
+// for variables synchronizing themselves and blank variables
+func racyGlobalType(global *ssa.Global) bool {
+	object := global.Object()
+	if object == nil || object.Name() == "_" {
+		return false
+	}
+	t := object.Type()
+	if _, ok := t.Underlying().(*types.Chan); ok {
+		return false
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() != nil {
+		if pkgPath := named.Obj().Pkg().Path(); pkgPath == "sync" || pkgPath == "sync/atomic" {
+			return false
+		}
+	}
+	return true
+}
+
+// synchronized reports whether the function, or a function it is a closure of, locks a
+// mutex, uses sync/atomic or runs a sync.Once
+func synchronized(fn *ssa.Function) bool {
+	for ; fn != nil; fn = fn.Parent() {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok {
+					continue
+				}
+				callee := call.Common().StaticCallee()
+				if callee == nil || callee.Pkg == nil {
+					if method := call.Common().Method; method != nil && method.Pkg() != nil && method.Pkg().Path() == "sync" && method.Name() == "Lock" {
+						return true
+					}
+					continue
+				}
+				switch pkgPath := callee.Pkg.Pkg.Path(); {
+				case pkgPath == "sync/atomic":
+					return true
+				case pkgPath == "sync" && slices.Contains([]string{"Lock", "RLock", "Do"}, callee.Name()):
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isHTTPHandler reports whether the function is a ServeHTTP method or a function with the
+// signature of a net/http handler function, which net/http runs in a goroutine per request
+func isHTTPHandler(fn *ssa.Function) bool {
+	params := fn.Signature.Params()
+	if params.Len() != 2 || fn.Signature.Results().Len() != 0 {
+		return false
+	}
+	isHTTP := func(t types.Type, name string) bool {
+		if pointer, ok := t.(*types.Pointer); ok {
+			t = pointer.Elem()
+		}
+		named, ok := types.Unalias(t).(*types.Named)
+		return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == name
+	}
+	if !isHTTP(params.At(0).Type(), "ResponseWriter") || !isHTTP(params.At(1).Type(), "Request") {
+		return false
+	}
+	return fn.Signature.Recv() == nil || fn.Name() == "ServeHTTP"
+}
+
+// inLoop reports whether the block is part of a loop of its function
+func inLoop(block *ssa.BasicBlock) bool {
+	visited := make(map[*ssa.BasicBlock]bool)
+	for queue := slices.Clone(block.Succs); len(queue) > 0; queue = queue[1:] {
+		next := queue[0]
+		if next == block {
+			return true
+		}
+		if !visited[next] {
+			visited[next] = true
+			queue = append(queue, next.Succs...)
+		}
+	}
+	return false
+}
+
+// isPackageInit reports whether the function is an init function or the initializer of a
+// package, which run before other goroutines start
+func isPackageInit(fn *ssa.Function) bool {
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	return fn.Signature.Recv() == nil && (fn.Name() == "init" || strings.HasPrefix(fn.Name(), "init#"))
+}
+
+// racyFunctionName names a function, or the function a closure is declared in
+func racyFunctionName(fn *ssa.Function) string {
+	closure := fn.Parent() != nil
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	name := fn.Name()
+	if object, ok := fn.Object().(*types.Func); ok {
+		name = deadCodeName(object)
+	}
+	if fn.Pkg != nil {
+		name = fn.Pkg.Pkg.Name() + "." + name
+	}
+	if closure {
+		return "func literal in " + name
+	}
+	return name
+}
+
+func (report *RacyGlobalsReport) String() string {
+	if len(report.Globals) == 0 {
+		return fmt.Sprintf("No package level variables of %s are accessed by several goroutines without synchronization", report.Module)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d package level variables of %s may be accessed by several goroutines without synchronization:\n", len(report.Globals), report.Module)
+	for _, global := range report.Globals {
+		fmt.Fprintf(
+			&b,
+			"\n%s %s of %s (%s:%d), accessed by %s:\n",
+			global.Name, global.Type, global.Package, global.File, global.Line, strings.Join(global.Goroutines, ", "),
+		)
+		for _, access := range global.Accesses {
+			kind := "read "
+			if access.Write {
+				kind = "write"
+			}
+			fmt.Fprintf(&b, "  %s %s:%d in %s (%s)\n", kind, access.File, access.Line, access.Function, strings.Join(access.Goroutines, ", "))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRacyGlobals(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/app", "", "go 1.22", ""},
+		"main.go": {
+			"package main", // 1
+			"",
+			"import (",
+			"\t\"fmt\"",
+			"\t\"sync\"",
+			")",
+			"",
+			"var count int", // 8
+			"",
+			"var (",
+			"\tmu    sync.Mutex",
+			"\ttotal int", // 12
+			")",
+			"",
+			"var names []string", // 15
+			"",
+			"var done = make(chan bool)",
+			"",
+			"func init() { names = append(names, \"init\") }", // 19
+			"",
+			"func add() {",
+			"\tmu.Lock()",
+			"\tdefer mu.Unlock()",
+			"\ttotal++",
+			"}",
+			"",
+			"func worker() {",
+			"\tcount++", // 28
+			"\tadd()",
+			"\tdone <- true",
+			"}",
+			"",
+			"func main() {",
+			"\tfor i := 0; i < 3; i++ {",
+			"\t\tgo worker()", // 35
+			"\t}",
+			"\tfor i := 0; i < 3; i++ {",
+			"\t\t<-done",
+			"\t}",
+			"\tfmt.Println(count, total, names)", // 40
+			"}",
+			"",
+		},
+		"server/server.go": {
+			"package server", // 1
+			"",
+			"import \"net/http\"",
+			"",
+			"var hits int", // 5
+			"",
+			"var greeting string",
+			"",
+			"func init() { greeting = \"hello\" }",
+			"",
+			"func handle(w http.ResponseWriter, r *http.Request) {",
+			"\thits++", // 12
+			"\tw.Write([]byte(greeting))",
+			"}",
+			"",
+			"func Register() { http.HandleFunc(\"/\", handle) }",
+			"",
+		},
+	})
+
+	t.Run("racy", func(t *testing.T) {
+		t.Parallel()
+		report, err := RacyGlobals(context.Background(), workspace)
+		if err != nil {
+			t.Fatalf("Failed to find racy globals: %v", err)
+		}
+		expected := strings.Join([]string{
+			"2 package level variables of example.com/app may be accessed by several goroutines without synchronization:",
+			"",
+			"count int of example.com/app (main.go:8), accessed by main goroutine, goroutine started at main.go:35 in a loop:",
+			"  read  main.go:28 in main.worker (goroutine started at main.go:35 in a loop)",
+			"  write main.go:28 in main.worker (goroutine started at main.go:35 in a loop)",
+			"  read  main.go:40 in main.main (main goroutine)",
+			"",
+			"hits int of example.com/app/server (server/server.go:5), accessed by HTTP handler server.handle:",
+			"  read  server/server.go:12 in server.handle (HTTP handler server.handle)",
+			"  write server/server.go:12 in server.handle (HTTP handler server.handle)",
+			"",
+		}, "\n")
+		if output := report.String(); output != expected {
+			t.Errorf("Unexpected report:\n%s\nexpected:\n%s", output, expected)
+		}
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		t.Parallel()
+		if _, err := RacyGlobals(context.Background(), "app"); err == nil {
+			t.Error("Expected an error for a relative workspace_dir")
+		}
+	})
+}
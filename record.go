@@ -0,0 +1,215 @@
+package go_mcp_tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RecordedCall is a single tool call and its result, stored as one JSON line in a session recording
+type RecordedCall struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Text      string         `json:"text,omitempty"`
+	IsError   bool           `json:"is_error,omitempty"`
+	// Error is set when the tool handler failed instead of returning a result
+	Error string `json:"error,omitempty"`
+}
+
+// ReplayMode controls how replayed results are compared to the recording
+type ReplayMode string
+
+const (
+	// ReplayExact requires the replayed text to be identical to the recorded text
+	ReplayExact ReplayMode = "exact"
+	// ReplayCompatible only requires calls to succeed or fail the same way as recorded
+	ReplayCompatible ReplayMode = "compatible"
+)
+
+// ReplayOptions configures Replay
+type ReplayOptions struct {
+	Mode ReplayMode
+	// PathRewrites maps recorded paths to the paths to use when replaying, e.g. the
+	// workspace directory of the recording to a fresh checkout. Rewrites are applied to
+	// string arguments and to the recorded results before comparing.
+	PathRewrites map[string]string
+}
+
+// ReplayMismatch describes a replayed call whose result differs from the recording
+type ReplayMismatch struct {
+	// Index is the 1-based position of the call in the recording
+	Index    int
+	Expected RecordedCall
+	Actual   RecordedCall
+}
+
+// String formats the mismatch for bug reports and test failures
+func (m ReplayMismatch) String() string {
+	return fmt.Sprintf(
+		"call %d (%s):\nexpected (is_error=%t, error=%q):\n%s\nactual (is_error=%t, error=%q):\n%s",
+		m.Index,
+		m.Expected.Tool,
+		m.Expected.IsError,
+		m.Expected.Error,
+		m.Expected.Text,
+		m.Actual.IsError,
+		m.Actual.Error,
+		m.Actual.Text,
+	)
+}
+
+// WithRecorder writes every tool call and its result to w as one RecordedCall per line.
+// The recording can be replayed with Replay.
+func WithRecorder(w io.Writer) Option {
+	return func(o *serverOptions) {
+		o.recorder = w
+	}
+}
+
+// recordingMiddleware writes tool calls and their results to w
+func recordingMiddleware(w io.Writer) server.ToolHandlerMiddleware {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+
+			call := RecordedCall{
+				Tool:      request.Params.Name,
+				Arguments: request.GetArguments(),
+			}
+			if err != nil {
+				call.Error = err.Error()
+			} else if result != nil {
+				call.Text = toolResultText(result)
+				call.IsError = result.IsError
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if encodeErr := encoder.Encode(call); encodeErr != nil && err == nil {
+				// A broken recording should not go unnoticed, but the call itself succeeded
+				return result, fmt.Errorf("failed to record %s tool call: %w", call.Tool, encodeErr)
+			}
+			return result, err
+		}
+	}
+}
+
+// Replay executes the tool calls recorded with WithRecorder against the MCP server and
+// compares the results to the recording. Returns the calls whose results differ.
+func Replay(
+	mcpServer *server.MCPServer,
+	recording io.Reader,
+	options ReplayOptions,
+) ([]ReplayMismatch, error) {
+	if options.Mode == "" {
+		options.Mode = ReplayExact
+	}
+	if options.Mode != ReplayExact && options.Mode != ReplayCompatible {
+		return nil, fmt.Errorf(
+			"unknown replay mode %q, expected %q or %q",
+			options.Mode,
+			ReplayExact,
+			ReplayCompatible,
+		)
+	}
+	rewriter := newPathRewriter(options.PathRewrites)
+
+	scanner := bufio.NewScanner(recording)
+	scanner.Buffer(make([]byte, 0, 64*1024), jsonlMaxLineSize)
+
+	var mismatches []ReplayMismatch
+	index := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		index++
+
+		var expected RecordedCall
+		if err := json.Unmarshal([]byte(line), &expected); err != nil {
+			return mismatches, fmt.Errorf("invalid recorded call %d: %w", index, err)
+		}
+		expected.Arguments = rewriteArguments(rewriter, expected.Arguments)
+		expected.Text = rewriter.Replace(expected.Text)
+		expected.Error = rewriter.Replace(expected.Error)
+
+		response := executeJSONLRequest(mcpServer, JSONLRequest{
+			Tool:      expected.Tool,
+			Arguments: expected.Arguments,
+		})
+		actual := RecordedCall{
+			Tool:      expected.Tool,
+			Arguments: expected.Arguments,
+			Text:      response.Text,
+			IsError:   response.IsError,
+			Error:     response.Error,
+		}
+
+		if !replayResultsMatch(expected, actual, options.Mode) {
+			mismatches = append(mismatches, ReplayMismatch{
+				Index:    index,
+				Expected: expected,
+				Actual:   actual,
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return mismatches, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return mismatches, nil
+}
+
+// replayResultsMatch compares a replayed call to its recording
+func replayResultsMatch(expected RecordedCall, actual RecordedCall, mode ReplayMode) bool {
+	if expected.IsError != actual.IsError || (expected.Error == "") != (actual.Error == "") {
+		return false
+	}
+	if mode == ReplayCompatible {
+		return true
+	}
+	return expected.Text == actual.Text && expected.Error == actual.Error
+}
+
+// newPathRewriter creates a replacer applying the longest paths first so nested paths rewrite correctly
+func newPathRewriter(rewrites map[string]string) *strings.Replacer {
+	from := make([]string, 0, len(rewrites))
+	for path := range rewrites {
+		if path != "" {
+			from = append(from, path)
+		}
+	}
+	sort.Slice(from, func(i, j int) bool {
+		return len(from[i]) > len(from[j])
+	})
+
+	pairs := make([]string, 0, 2*len(from))
+	for _, path := range from {
+		pairs = append(pairs, path, rewrites[path])
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// rewriteArguments applies path rewrites to all string arguments
+func rewriteArguments(rewriter *strings.Replacer, arguments map[string]any) map[string]any {
+	rewritten := make(map[string]any, len(arguments))
+	for name, value := range arguments {
+		if s, ok := value.(string); ok {
+			value = rewriter.Replace(s)
+		}
+		rewritten[name] = value
+	}
+	return rewritten
+}
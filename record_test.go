@@ -0,0 +1,122 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a workspace with an unsorted map literal
+	createTestWorkspace := func(t testing.TB, mapLiteral string) string {
+		tempDir := t.TempDir()
+		lines := []string{
+			"package testpkg", // 1
+			"",                // 2
+			mapLiteral,        // 3
+			"",                // 4
+		}
+		err := os.WriteFile(
+			filepath.Join(tempDir, "main.go"),
+			[]byte(strings.Join(lines, "\n")),
+			0644,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tempDir
+	}
+
+	// Helper function to record a sort session in the workspace
+	recordSession := func(t testing.TB, workspace string) *bytes.Buffer {
+		var recording bytes.Buffer
+		input := strings.Join([]string{
+			`{"tool": "sort", "arguments": {"file_path": "` + filepath.Join(workspace, "main.go") + `"}}`, // 1
+			`{"tool": "sort", "arguments": {"file_path": "` + filepath.Join(workspace, "main.go") + `"}}`, // 2
+			`{"tool": "sort", "arguments": {}}`, // 3 - missing file_path
+		}, "\n")
+		err := ServeJSONL(NewMCPServer(WithRecorder(&recording)), strings.NewReader(input), &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("Failed to serve jsonl: %v", err)
+		}
+		return &recording
+	}
+
+	t.Run("recording contains calls and results", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t, `var M = map[string]int{"b": 2, "a": 1}`)
+		recording := recordSession(t, workspace).String()
+
+		lines := strings.Split(strings.TrimSpace(recording), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 recorded calls, got %d:\n%s", len(lines), recording)
+		}
+		if !strings.Contains(lines[0], `"tool":"sort"`) || !strings.Contains(lines[0], "1 map literal(s)") {
+			t.Errorf("Expected first call with its result, got: %s", lines[0])
+		}
+		if !strings.Contains(lines[2], `argument \"file_path\" is required`) {
+			t.Errorf("Expected argument error to be recorded, got: %s", lines[2])
+		}
+	})
+
+	t.Run("replay in another workspace", func(t *testing.T) {
+		t.Parallel()
+		recorded := createTestWorkspace(t, `var M = map[string]int{"b": 2, "a": 1}`)
+		recording := recordSession(t, recorded)
+
+		replayed := createTestWorkspace(t, `var M = map[string]int{"b": 2, "a": 1}`)
+		mismatches, err := Replay(NewMCPServer(), recording, ReplayOptions{
+			PathRewrites: map[string]string{recorded: replayed},
+		})
+		if err != nil {
+			t.Fatalf("Failed to replay: %v", err)
+		}
+		for _, mismatch := range mismatches {
+			t.Errorf("Unexpected mismatch: %s", mismatch)
+		}
+	})
+
+	t.Run("changed results are reported", func(t *testing.T) {
+		t.Parallel()
+		recorded := createTestWorkspace(t, `var M = map[string]int{"b": 2, "a": 1}`)
+		recording := recordSession(t, recorded).String()
+
+		// A file that is already sorted changes the result of the first call
+		sortedWorkspace := createTestWorkspace(t, `var M = map[string]int{"a": 1, "b": 2}`)
+		mismatches, err := Replay(NewMCPServer(), strings.NewReader(recording), ReplayOptions{
+			Mode:         ReplayExact,
+			PathRewrites: map[string]string{recorded: sortedWorkspace},
+		})
+		if err != nil {
+			t.Fatalf("Failed to replay: %v", err)
+		}
+		if len(mismatches) != 1 || mismatches[0].Index != 1 {
+			t.Fatalf("Expected the first call to mismatch, got %v", mismatches)
+		}
+
+		mismatches, err = Replay(NewMCPServer(), strings.NewReader(recording), ReplayOptions{
+			Mode:         ReplayCompatible,
+			PathRewrites: map[string]string{recorded: sortedWorkspace},
+		})
+		if err != nil {
+			t.Fatalf("Failed to replay: %v", err)
+		}
+		if len(mismatches) != 0 {
+			t.Errorf("Expected compatible replay to match, got %v", mismatches)
+		}
+	})
+
+	t.Run("invalid recordings", func(t *testing.T) {
+		t.Parallel()
+		if _, err := Replay(NewMCPServer(), strings.NewReader("not json"), ReplayOptions{}); err == nil {
+			t.Error("Expected error for malformed recording")
+		}
+		if _, err := Replay(NewMCPServer(), strings.NewReader(""), ReplayOptions{Mode: "fuzzy"}); err == nil {
+			t.Error("Expected error for unknown mode")
+		}
+	})
+}
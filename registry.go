@@ -0,0 +1,121 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolRegistry collects third-party tools to serve alongside the built-in tools.
+// Handlers can use RunGopls, GoplsPosition, ParseFile and the path helpers of this
+// package to share the gopls setup, file cache and workspace resolution of the built-in tools.
+//
+//	registry := go_mcp_tools.NewToolRegistry()
+//	registry.Register(myTool, myHandler, go_mcp_tools.ToolCost{Level: go_mcp_tools.CostLow})
+//	mcpServer := go_mcp_tools.NewMCPServer(go_mcp_tools.WithRegistry(registry))
+type ToolRegistry struct {
+	mu    sync.Mutex
+	tools []registeredTool
+}
+
+// registeredTool is a tool with its cost as registered in a ToolRegistry
+type registeredTool struct {
+	tool server.ServerTool
+	cost ToolCost
+}
+
+// NewToolRegistry creates an empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{}
+}
+
+// Register adds a tool to the registry. A tool with the same name as a built-in
+// or previously registered tool replaces it. The cost is appended to the tool
+// description and decides which quotas apply, see ToolCost.
+func (registry *ToolRegistry) Register(tool mcp.Tool, handler server.ToolHandlerFunc, cost ToolCost) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.tools = append(registry.tools, registeredTool{
+		tool: server.ServerTool{Tool: tool, Handler: handler},
+		cost: cost,
+	})
+}
+
+// Tools returns the registered tools in registration order
+func (registry *ToolRegistry) Tools() []mcp.Tool {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	tools := make([]mcp.Tool, 0, len(registry.tools))
+	for _, registered := range registry.tools {
+		tools = append(tools, registered.tool.Tool)
+	}
+	return tools
+}
+
+// WithRegistry serves the tools of the registry next to the built-in tools.
+// Tools registered after the server is created are not served.
+func WithRegistry(registry *ToolRegistry) Option {
+	return func(o *serverOptions) {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		for _, registered := range registry.tools {
+			o.tools = append(o.tools, registered.tool)
+			o.toolCosts[registered.tool.Tool.Name] = registered.cost
+		}
+	}
+}
+
+// RunGopls runs gopls with the given arguments the same way the built-in tools do
+// and returns its trimmed output. Pass the context of the tool call, so gopls is
+// killed when the call is cancelled or its session ends.
+func RunGopls(ctx context.Context, args ...string) (string, error) {
+	return executeGoplsCommand(ctx, args...)
+}
+
+// GoplsPosition creates a file:line:column position for gopls commands by
+// locating symbolName on the given line, so tools never need a column index
+func GoplsPosition(filePath string, lineNumber int, symbolName string) (string, error) {
+	return createGoplsPosition(filePath, lineNumber, symbolName)
+}
+
+// ParseFile returns the parsed file from the file cache shared with the built-in tools,
+// parsing it if it is not cached or has changed on disk. Files with syntax errors
+// return a partial AST. The returned AST must not be modified.
+func ParseFile(filePath string) (*ast.File, *token.FileSet, error) {
+	cached, err := globalFileCache.GetOrParseFile(filePath, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cached.ast, cached.fset, nil
+}
+
+// ResolveFilePath resolves an absolute or workspace relative file path to an existing file
+func ResolveFilePath(filePath string, workspaceDir string) (string, error) {
+	return resolveFilePath(filePath, workspaceDir)
+}
+
+// ResolvePackagePath makes package paths starting with ./ or ../ absolute and
+// returns import paths unchanged
+func ResolvePackagePath(pkgPath string, workspaceDir string) (string, error) {
+	return resolvePackagePath(pkgPath, workspaceDir)
+}
+
+// IsFileInWorkspace reports whether filePath is inside workspaceDir
+func IsFileInWorkspace(filePath string, workspaceDir string) bool {
+	return isFileInWorkspace(filePath, workspaceDir)
+}
+
+// CheckWorkspacePath returns an error matching ErrOutsideWorkspace unless path is
+// inside one of the workspace directories
+func CheckWorkspacePath(path string, workspaceDirs ...string) error {
+	for _, dir := range workspaceDirs {
+		if isFileInWorkspace(path, dir) {
+			return nil
+		}
+	}
+	return classifyErrorf(ErrOutsideWorkspace, "%s is outside the workspaces %v", path, workspaceDirs)
+}
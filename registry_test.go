@@ -0,0 +1,112 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestToolRegistry(t *testing.T) {
+	t.Parallel()
+
+	// A third-party tool listing the functions of a file using the shared helpers
+	functionsTool := mcp.NewTool("functions",
+		mcp.WithDescription("Lists the functions of a Go file"),
+		mcp.WithString("file_path", mcp.Required()),
+		mcp.WithString("workspace_dir", mcp.Required()),
+	)
+	functionsHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments := request.GetArguments()
+		filePath, _ := arguments["file_path"].(string)
+		workspaceDir, _ := arguments["workspace_dir"].(string)
+
+		absPath, err := ResolveFilePath(filePath, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+		file, fset, err := ParseFile(absPath)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error: %v", err)), nil
+		}
+
+		var functions []string
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				functions = append(functions, fmt.Sprintf(
+					"%s:%d",
+					funcDecl.Name.Name,
+					fset.Position(funcDecl.Pos()).Line,
+				))
+			}
+		}
+		return mcp.NewToolResultText(strings.Join(functions, "\n")), nil
+	}
+
+	t.Run("registered tools are served", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		lines := []string{
+			"package testpkg",  // 1
+			"",                 // 2
+			"func First() {}",  // 3
+			"",                 // 4
+			"func Second() {}", // 5
+		}
+		err := os.WriteFile(
+			filepath.Join(workspace, "main.go"),
+			[]byte(strings.Join(lines, "\n")),
+			0644,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		registry := NewToolRegistry()
+		registry.Register(functionsTool, functionsHandler, ToolCost{Level: CostLow})
+		if tools := registry.Tools(); len(tools) != 1 || tools[0].Name != "functions" {
+			t.Fatalf("Expected the functions tool to be registered, got %v", tools)
+		}
+
+		var out strings.Builder
+		input := `{"tool": "functions", "arguments": {"file_path": "main.go", "workspace_dir": "` + workspace + `"}}`
+		err = ServeJSONL(NewMCPServer(WithRegistry(registry)), strings.NewReader(input), &out)
+		if err != nil {
+			t.Fatalf("Failed to serve jsonl: %v", err)
+		}
+
+		var response JSONLResponse
+		if err := json.Unmarshal([]byte(out.String()), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Text != "First:3\nSecond:5" {
+			t.Errorf("Expected functions with line numbers, got %+v", response)
+		}
+	})
+
+	t.Run("registered tools have cost hints", func(t *testing.T) {
+		t.Parallel()
+		registry := NewToolRegistry()
+		registry.Register(functionsTool, functionsHandler, ToolCost{Level: CostLow})
+
+		encoded, err := json.Marshal(NewMCPServer(WithRegistry(registry)).HandleMessage(
+			context.Background(),
+			json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`),
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(encoded), "Lists the functions of a Go file\\n\\nCost: low.") {
+			t.Errorf("Expected cost hint in description, got: %s", encoded)
+		}
+		if !strings.Contains(string(encoded), `"name":"`+inspectToolName+`"`) {
+			t.Errorf("Expected built-in tools to be served as well, got: %s", encoded)
+		}
+	})
+}
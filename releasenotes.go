@@ -0,0 +1,379 @@
+package go_mcp_tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	releaseNotesToolName        = "release_notes"
+	releaseNotesToolDescription = `Drafts the release notes of Go packages since a git ref, usually the tag of the previous release, by combining the changes of their exported API, the deprecations added, and the commits changing exported symbols.
+
+The exported API of the working tree is compared with the ref as by the apidiff tool and listed as breaking changes and additions, and symbols whose doc comment gained a "Deprecated: " paragraph are listed with it. Each entry names the commits since the ref that changed the declaration of its symbol, doc comment included, and the commits changing exported declarations are listed with their subject and the symbols they changed. Commits only changing unexported code, tests or other files are left out, as are merges.
+
+The result is a draft to edit: uncommitted changes appear in the API changes but not in the commits.`
+)
+
+func AddReleaseNotesTool(mcpServer *server.MCPServer) {
+	handleReleaseNotes := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		ref, err := request.RequireString("ref")
+		if err != nil {
+			return nil, err
+		}
+		pattern := request.GetString("package", "")
+		if pattern == "" {
+			pattern = "./..."
+		}
+
+		notes, err := DraftReleaseNotes(ctx, workspaceDir, pattern, ref)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error drafting release notes: %v", err)), nil
+		}
+		return mcp.NewToolResultText(notes.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		releaseNotesToolName,
+		mcp.WithDescription(releaseNotesToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of the directory the packages are resolved from, in a git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString("ref",
+			mcp.Description("Git ref of the previous release, e.g. v1.4.0"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Import path, relative directory or pattern of the released packages (default: ./...)"),
+		),
+	), handleReleaseNotes)
+}
+
+// ReleaseNotes is a draft of the release notes of packages since a git ref
+type ReleaseNotes struct {
+	Since      string        `json:"since"`
+	Breaking   []ReleaseNote `json:"breaking,omitempty"`
+	Deprecated []ReleaseNote `json:"deprecated,omitempty"`
+	Added      []ReleaseNote `json:"added,omitempty"`
+	// Commits are the commits since the ref changing exported declarations, oldest first
+	Commits []ReleaseCommit `json:"commits,omitempty"`
+}
+
+// ReleaseNote is a change of the exported API, or a deprecation, with the commits changing
+// the declaration of its symbol
+type ReleaseNote struct {
+	Package string `json:"package"`
+	// Symbol is the name of the symbol, prefixed by the type name for methods and fields
+	Symbol string `json:"symbol"`
+	// Old and New are the features of an API change as listed by ExportedAPI
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+	// Note is the deprecation paragraph of a deprecation
+	Note    string   `json:"note,omitempty"`
+	Commits []string `json:"commits,omitempty"`
+}
+
+// ReleaseCommit is a commit changing exported declarations
+type ReleaseCommit struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	// Symbols are the exported symbols whose declaration the commit changed, qualified by
+	// the last element of their import path
+	Symbols []string `json:"symbols"`
+}
+
+// releaseSymbol is an exported symbol of a package
+type releaseSymbol struct {
+	pkgPath string
+	name    string
+}
+
+// DraftReleaseNotes drafts the release notes of the packages matching pattern, resolved
+// from workspaceDir, since ref
+func DraftReleaseNotes(ctx context.Context, workspaceDir string, pattern string, ref string) (*ReleaseNotes, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffAPIVersions(ctx, APIDiffOptions{WorkspaceDir: workspaceDir, Package: pattern, Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	stability, err := APIStability(ctx, workspaceDir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	var oldStability *StabilityReport
+	if err := atGitRef(ctx, workspaceDir, ref, func(dir string) error {
+		oldStability, err = APIStability(ctx, dir, pattern)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	commits, symbolCommits, err := exportedSymbolCommits(ctx, workspaceDir, module, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := &ReleaseNotes{Since: ref, Commits: commits}
+	apiNote := func(change APIChange) ReleaseNote {
+		feature, _, _ := strings.Cut(cmp.Or(change.New, change.Old), "\n")
+		symbol := apiFeatureSymbol(feature)
+		return ReleaseNote{
+			Package: symbol.pkgPath,
+			Symbol:  symbol.name,
+			Old:     change.Old,
+			New:     change.New,
+			Commits: symbolCommits[symbol],
+		}
+	}
+	for _, change := range diff.Breaking {
+		notes.Breaking = append(notes.Breaking, apiNote(change))
+	}
+	for _, change := range diff.Compatible {
+		notes.Added = append(notes.Added, apiNote(change))
+	}
+
+	deprecated := make(map[releaseSymbol]bool)
+	for _, symbol := range oldStability.Symbols {
+		if symbol.Level == StabilityDeprecated {
+			deprecated[releaseSymbol{symbol.Package, symbol.Name}] = true
+		}
+	}
+	for _, symbol := range stability.Symbols {
+		key := releaseSymbol{symbol.Package, symbol.Name}
+		// Members of deprecated types and packages are covered by their deprecation
+		if symbol.Level != StabilityDeprecated || symbol.Inherited || deprecated[key] {
+			continue
+		}
+		notes.Deprecated = append(notes.Deprecated, ReleaseNote{
+			Package: symbol.Package,
+			Symbol:  symbol.Name,
+			Note:    symbol.Note,
+			Commits: symbolCommits[key],
+		})
+	}
+	slices.SortFunc(notes.Deprecated, func(a, b ReleaseNote) int {
+		return cmp.Or(strings.Compare(a.Package, b.Package), strings.Compare(a.Symbol, b.Symbol))
+	})
+	return notes, nil
+}
+
+// exportedSymbolCommits lists the commits since ref changing exported declarations of the
+// module below workspaceDir, and the short hashes of the commits changing each symbol
+func exportedSymbolCommits(
+	ctx context.Context,
+	workspaceDir string,
+	module *moduleSources,
+	ref string,
+) ([]ReleaseCommit, map[releaseSymbol][]string, error) {
+	repoRoot, err := gitRepoRoot(ctx, workspaceDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	moduleDir, err := filepath.Rel(resolveSymlinks(repoRoot), resolveSymlinks(module.root))
+	if err != nil {
+		return nil, nil, err
+	}
+	moduleDir = filepath.ToSlash(moduleDir)
+
+	log, err := runGit(ctx, workspaceDir, "log", "--no-merges", "--reverse", "--format=%h%x00%s", ref+"..HEAD", "--", ".")
+	if err != nil {
+		return nil, nil, err
+	}
+	var commits []ReleaseCommit
+	symbolCommits := make(map[releaseSymbol][]string)
+	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+		hash, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		output, err := runGit(
+			ctx, workspaceDir,
+			"show", "--format=", "--unified=0", "--no-renames", "--no-color", "--no-ext-diff", hash, "--", ".",
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		changed := make(map[releaseSymbol]bool)
+		for file, lines := range parseUnifiedDiff(output) {
+			rel := file
+			if moduleDir != "." {
+				if rel, ok = strings.CutPrefix(file, moduleDir+"/"); !ok {
+					continue
+				}
+			}
+			if strings.HasSuffix(rel, "_test.go") {
+				continue
+			}
+			pkgPath := path.Join(module.path, path.Dir(rel))
+			// Declarations are looked up in the file after the commit, and in the file
+			// before it for removed lines. Missing files were added or removed.
+			for _, version := range []struct {
+				rev      string
+				overlaps func(start, end int) bool
+			}{
+				{hash, lines.overlapsCurrent},
+				{hash + "^", lines.overlapsOld},
+			} {
+				src, err := runGit(ctx, repoRoot, "show", version.rev+":"+file)
+				if err != nil {
+					continue
+				}
+				for _, name := range changedExportedDeclarations(src, version.overlaps) {
+					changed[releaseSymbol{pkgPath, name}] = true
+				}
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		commit := ReleaseCommit{Hash: hash, Subject: subject}
+		for symbol := range changed {
+			commit.Symbols = append(commit.Symbols, path.Base(symbol.pkgPath)+"."+symbol.name)
+			symbolCommits[symbol] = append(symbolCommits[symbol], hash)
+		}
+		slices.Sort(commit.Symbols)
+		commits = append(commits, commit)
+	}
+	return commits, symbolCommits, nil
+}
+
+// changedExportedDeclarations returns the names of the exported top-level declarations of
+// a library source file whose lines, doc comment included, overlap changed lines
+func changedExportedDeclarations(src string, overlaps func(start, end int) bool) []string {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil || file.Name.Name == "main" {
+		return nil
+	}
+	var names []string
+	for _, decl := range file.Decls {
+		for _, ident := range declarationIdents(decl) {
+			name, kind := topLevelName(decl, ident)
+			receiver, _, _ := strings.Cut(name, ".")
+			if !ident.IsExported() || kind == "method" && !token.IsExported(receiver) {
+				continue
+			}
+			node := declarationNode(decl, ident)
+			start := node.Pos()
+			var doc *ast.CommentGroup
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				doc = node.Doc
+			case *ast.GenDecl:
+				doc = node.Doc
+			case *ast.TypeSpec:
+				doc = node.Doc
+			case *ast.ValueSpec:
+				doc = node.Doc
+			}
+			if doc != nil {
+				start = doc.Pos()
+			}
+			if overlaps(fset.Position(start).Line, fset.Position(node.End()).Line) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// apiFeatureSymbol returns the package and symbol of a feature listed by ExportedAPI,
+// Type.Method for methods and the type for fields and interface methods
+func apiFeatureSymbol(feature string) releaseSymbol {
+	prefix := apiFeaturePackage(feature)
+	symbol := releaseSymbol{pkgPath: strings.TrimSuffix(strings.TrimPrefix(prefix, "pkg "), ", ")}
+	kind, rest, _ := strings.Cut(strings.TrimPrefix(apiFeatureKey(feature), prefix), " ")
+	switch kind {
+	case "method":
+		receiver, method, _ := strings.Cut(rest, ") ")
+		receiver = strings.TrimLeft(receiver, "(*")
+		receiver, _, _ = strings.Cut(receiver, "[")
+		symbol.name = receiver + "." + method
+	default:
+		symbol.name, _, _ = strings.Cut(rest, " ")
+	}
+	return symbol
+}
+
+func (notes *ReleaseNotes) String() string {
+	if len(notes.Breaking)+len(notes.Deprecated)+len(notes.Added)+len(notes.Commits) == 0 {
+		return fmt.Sprintf("No changes of the exported API since %s", notes.Since)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Release notes since %s (draft)\n", notes.Since)
+	feature := func(features string) string {
+		var listed []string
+		for _, feature := range strings.Split(features, "\n") {
+			listed = append(listed, "`"+strings.TrimPrefix(feature, apiFeaturePackage(feature))+"`")
+		}
+		return strings.Join(listed, ", ")
+	}
+	entry := func(note ReleaseNote, text string) {
+		fmt.Fprintf(&b, "- %s: %s", path.Base(note.Package), text)
+		if len(note.Commits) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(note.Commits, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(notes.Breaking) > 0 {
+		b.WriteString("\n## Breaking changes\n")
+		for _, note := range notes.Breaking {
+			switch {
+			case note.Old == "":
+				entry(note, "added "+feature(note.New))
+			case note.New == "":
+				entry(note, "removed "+feature(note.Old))
+			default:
+				entry(note, "changed "+feature(note.Old)+" to "+feature(note.New))
+			}
+		}
+	}
+	if len(notes.Deprecated) > 0 {
+		b.WriteString("\n## Deprecations\n")
+		for _, note := range notes.Deprecated {
+			entry(note, fmt.Sprintf("`%s` is deprecated: %s", note.Symbol, note.Note))
+		}
+	}
+	if len(notes.Added) > 0 {
+		b.WriteString("\n## Additions\n")
+		for _, note := range notes.Added {
+			if note.Old == "" {
+				entry(note, "added "+feature(note.New))
+			} else {
+				entry(note, "removed "+feature(note.Old))
+			}
+		}
+	}
+	if len(notes.Commits) > 0 {
+		b.WriteString("\n## Commits changing the exported API\n")
+		for _, commit := range notes.Commits {
+			fmt.Fprintf(&b, "- %s %s (%s)\n", commit.Hash, commit.Subject, strings.Join(commit.Symbols, ", "))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+
+	// Helper function to write the files of the module and commit them
+	commit := func(t testing.TB, message string, files map[string][]string) string {
+		t.Helper()
+		writeTestFiles(t, workspace, files)
+		runTestGit(t, workspace, "add", "-A")
+		runTestGit(t, workspace, "commit", "-q", "-m", message)
+		return strings.TrimSpace(runTestGit(t, workspace, "rev-parse", "--short", "HEAD"))
+	}
+
+	runTestGit(t, workspace, "init", "-q")
+	commit(t, "Initial release", map[string][]string{
+		"go.mod": {"module example.com/lib", "", "go 1.22", ""},
+		"store/store.go": {
+			"package store",
+			"",
+			"type DB struct{}",
+			"",
+			"func Open(path string) *DB { return &DB{} }",
+			"",
+			"func (db *DB) Get(key string) string { return key }",
+			"",
+			"func Close(db *DB) {}",
+			"",
+		},
+	})
+	runTestGit(t, workspace, "tag", "v1.0.0")
+	put := commit(t, "Add DB.Put", map[string][]string{
+		"store/store.go": {
+			"package store",
+			"",
+			"type DB struct{}",
+			"",
+			"func Open(path string) *DB { return &DB{} }",
+			"",
+			"func (db *DB) Get(key string) string { return key }",
+			"",
+			"func (db *DB) Put(key, value string) {}",
+			"",
+			"func Close(db *DB) {}",
+			"",
+		},
+	})
+	deprecate := commit(t, "Deprecate Get and remove Close", map[string][]string{
+		"store/store.go": {
+			"package store",
+			"",
+			"type DB struct{}",
+			"",
+			"func Open(path string) *DB { return &DB{} }",
+			"",
+			"// Get returns the value of a key.",
+			"//",
+			"// Deprecated: use Lookup instead.",
+			"func (db *DB) Get(key string) string { return key }",
+			"",
+			"func (db *DB) Put(key, value string) {}",
+			"",
+		},
+	})
+	commit(t, "Document the store", map[string][]string{
+		"README.md": {"# store", ""},
+		"store/internal.go": {
+			"package store",
+			"",
+			"func lookup(key string) string { return key }",
+			"",
+		},
+	})
+
+	t.Run("notes", func(t *testing.T) {
+		t.Parallel()
+		notes, err := DraftReleaseNotes(context.Background(), workspace, "./...", "v1.0.0")
+		if err != nil {
+			t.Fatalf("Failed to draft release notes: %v", err)
+		}
+		expected := strings.Join([]string{
+			"# Release notes since v1.0.0 (draft)",
+			"",
+			"## Breaking changes",
+			"- store: removed `func Close(*DB)` (" + deprecate + ")",
+			"",
+			"## Deprecations",
+			"- store: `DB.Get` is deprecated: use Lookup instead. (" + deprecate + ")",
+			"",
+			"## Additions",
+			"- store: added `method (*DB) Put(string, string)` (" + put + ")",
+			"",
+			"## Commits changing the exported API",
+			"- " + put + " Add DB.Put (store.DB.Put)",
+			"- " + deprecate + " Deprecate Get and remove Close (store.Close, store.DB.Get)",
+			"",
+		}, "\n")
+		if output := notes.String(); output != expected {
+			t.Errorf("Unexpected release notes:\n%s\nexpected:\n%s", output, expected)
+		}
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		t.Parallel()
+		if _, err := DraftReleaseNotes(context.Background(), workspace, "./...", "v9.0.0"); err == nil {
+			t.Error("Expected an error for an unknown ref")
+		}
+	})
+}
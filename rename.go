@@ -8,39 +8,36 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+const renameToolName = "rename"
+
 func AddRenameTool(mcpServer *server.MCPServer) {
 	// handleRenameSymbolTool handles the rename symbol tool requests
 	handleRename := func(
 		ctx context.Context,
 		request mcp.CallToolRequest,
 	) (*mcp.CallToolResult, error) {
-		arguments := request.GetArguments()
-
-		filePath, ok := arguments["file_path"].(string)
-		if !ok || filePath == "" {
-			return nil, fmt.Errorf("file_path argument is required and must be a string")
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
 		}
 
-		lineNumberFloat, ok := arguments["line_number"].(float64)
-		if !ok {
-			return nil, fmt.Errorf(
-				"line_number argument is required and must be a number",
-			)
+		lineNumber, err := request.RequireInt("line_number")
+		if err != nil {
+			return nil, err
 		}
-		lineNumber := int(lineNumberFloat)
 
-		oldName, ok := arguments["old_name"].(string)
-		if !ok || oldName == "" {
-			return nil, fmt.Errorf("old_name argument is required and must be a string")
+		oldName, err := request.RequireString("old_name")
+		if err != nil {
+			return nil, err
 		}
 
-		newName, ok := arguments["new_name"].(string)
-		if !ok || newName == "" {
-			return nil, fmt.Errorf("new_name argument is required and must be a string")
+		newName, err := request.RequireString("new_name")
+		if err != nil {
+			return nil, err
 		}
 
 		// Call the rename function
-		result, err := Rename(filePath, lineNumber, oldName, newName)
+		result, err := Rename(ctx, filePath, lineNumber, oldName, newName)
 		if err != nil {
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
@@ -64,8 +61,9 @@ func AddRenameTool(mcpServer *server.MCPServer) {
 		}, nil
 	}
 
-	mcpServer.AddTool(mcp.NewTool("rename",
+	mcpServer.AddTool(mcp.NewTool(renameToolName,
 		mcp.WithDescription("Renames a Go symbol throughout a file"),
+		editingToolAnnotation(true, true),
 		mcp.WithString("file_path",
 			mcp.Description("Path to the Go file containing the symbol to rename"),
 			mcp.Required(),
@@ -85,7 +83,10 @@ func AddRenameTool(mcpServer *server.MCPServer) {
 	), handleRename)
 }
 
+// Rename renames the symbol named symbolName at lineNumber of filePath and its references
+// to newName, killing gopls when ctx is done
 func Rename(
+	ctx context.Context,
 	filePath string,
 	lineNumber int,
 	symbolName string,
@@ -117,7 +118,7 @@ func Rename(
 		return "", err
 	}
 
-	output, err := executeGoplsCommand("rename", "-w", position, newName)
+	output, err := executeGoplsCommand(ctx, "rename", "-w", position, newName)
 	if err != nil {
 		return "", fmt.Errorf(
 			"failed to rename symbol '%s' at %s: %w",
@@ -1,6 +1,7 @@
 package go_mcp_tools
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -126,7 +127,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Rename GlobalCounter to GlobalCount on line 43
-		result, err := Rename(mainFile, 43, "GlobalCounter", "GlobalCount")
+		result, err := Rename(context.Background(), mainFile, 43, "GlobalCounter", "GlobalCount")
 		if err != nil {
 			t.Fatalf("Failed to rename variable: %v", err)
 		}
@@ -151,7 +152,7 @@ func TestRename(t *testing.T) {
 		helperFile := filepath.Join(workspace, "helper.go")
 
 		// Rename NewPerson to CreatePerson on line 28
-		result, err := Rename(mainFile, 28, "NewPerson", "CreatePerson")
+		result, err := Rename(context.Background(), mainFile, 28, "NewPerson", "CreatePerson")
 		if err != nil {
 			t.Fatalf("Failed to rename function: %v", err)
 		}
@@ -187,7 +188,7 @@ func TestRename(t *testing.T) {
 		helperFile := filepath.Join(workspace, "helper.go")
 
 		// Rename Person to Individual on line 12
-		result, err := Rename(mainFile, 12, "Person", "Individual")
+		result, err := Rename(context.Background(), mainFile, 12, "Person", "Individual")
 		if err != nil {
 			t.Fatalf("Failed to rename type: %v", err)
 		}
@@ -222,7 +223,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Rename DefaultName to StandardName on line 37
-		result, err := Rename(mainFile, 37, "DefaultName", "StandardName")
+		result, err := Rename(context.Background(), mainFile, 37, "DefaultName", "StandardName")
 		if err != nil {
 			t.Fatalf("Failed to rename constant: %v", err)
 		}
@@ -254,7 +255,7 @@ func TestRename(t *testing.T) {
 		helperFile := filepath.Join(workspace, "helper.go")
 
 		// Rename GetName to GetFullName on line 18
-		result, err := Rename(mainFile, 18, "GetName", "GetFullName")
+		result, err := Rename(context.Background(), mainFile, 18, "GetName", "GetFullName")
 		if err != nil {
 			t.Fatalf("Failed to rename method: %v", err)
 		}
@@ -289,7 +290,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Try to rename Person to Person (same name)
-		result, err := Rename(mainFile, 12, "Person", "Person")
+		result, err := Rename(context.Background(), mainFile, 12, "Person", "Person")
 		if err != nil {
 			t.Fatalf("Unexpected error for same name rename: %v", err)
 		}
@@ -304,7 +305,7 @@ func TestRename(t *testing.T) {
 	t.Run("empty file path", func(t *testing.T) {
 		t.Parallel()
 
-		_, err := Rename("", 12, "Person", "Individual")
+		_, err := Rename(context.Background(), "", 12, "Person", "Individual")
 		if err == nil {
 			t.Fatal("Expected error for empty file path")
 		}
@@ -318,7 +319,7 @@ func TestRename(t *testing.T) {
 		workspace := createTestWorkspace(t)
 		mainFile := filepath.Join(workspace, "main.go")
 
-		_, err := Rename(mainFile, 0, "Person", "Individual")
+		_, err := Rename(context.Background(), mainFile, 0, "Person", "Individual")
 		if err == nil {
 			t.Fatal("Expected error for line number 0")
 		}
@@ -332,7 +333,7 @@ func TestRename(t *testing.T) {
 		workspace := createTestWorkspace(t)
 		mainFile := filepath.Join(workspace, "main.go")
 
-		_, err := Rename(mainFile, -5, "Person", "Individual")
+		_, err := Rename(context.Background(), mainFile, -5, "Person", "Individual")
 		if err == nil {
 			t.Fatal("Expected error for negative line number")
 		}
@@ -346,7 +347,7 @@ func TestRename(t *testing.T) {
 		workspace := createTestWorkspace(t)
 		mainFile := filepath.Join(workspace, "main.go")
 
-		_, err := Rename(mainFile, 12, "", "Individual")
+		_, err := Rename(context.Background(), mainFile, 12, "", "Individual")
 		if err == nil {
 			t.Fatal("Expected error for empty symbol name")
 		}
@@ -360,7 +361,7 @@ func TestRename(t *testing.T) {
 		workspace := createTestWorkspace(t)
 		mainFile := filepath.Join(workspace, "main.go")
 
-		_, err := Rename(mainFile, 12, "Person", "")
+		_, err := Rename(context.Background(), mainFile, 12, "Person", "")
 		if err == nil {
 			t.Fatal("Expected error for empty new name")
 		}
@@ -373,7 +374,7 @@ func TestRename(t *testing.T) {
 		t.Parallel()
 
 		nonExistentFile := "/tmp/non_existent_file.go"
-		_, err := Rename(nonExistentFile, 12, "Person", "Individual")
+		_, err := Rename(context.Background(), nonExistentFile, 12, "Person", "Individual")
 		if err == nil {
 			t.Fatal("Expected error for non-existent file")
 		}
@@ -389,7 +390,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Try line 1000 when file has much fewer lines
-		_, err := Rename(mainFile, 1000, "Person", "Individual")
+		_, err := Rename(context.Background(), mainFile, 1000, "Person", "Individual")
 		if err == nil {
 			t.Fatal("Expected error for line number exceeding file length")
 		}
@@ -404,7 +405,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Try to find "NonExistentSymbol" at line 12 (where Person struct is)
-		_, err := Rename(mainFile, 12, "NonExistentSymbol", "NewName")
+		_, err := Rename(context.Background(), mainFile, 12, "NonExistentSymbol", "NewName")
 		if err == nil {
 			t.Fatal("Expected error for symbol not found")
 		}
@@ -419,7 +420,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Try to find "Person" at line 2 (empty line) instead of line 12
-		_, err := Rename(mainFile, 2, "Person", "Individual")
+		_, err := Rename(context.Background(), mainFile, 2, "Person", "Individual")
 		if err == nil {
 			t.Fatal("Expected error for symbol at wrong line")
 		}
@@ -434,7 +435,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Try to rename "Person" as "Per" - should not match partial
-		_, err := Rename(mainFile, 12, "Per", "Ind")
+		_, err := Rename(context.Background(), mainFile, 12, "Per", "Ind")
 		if err == nil {
 			t.Fatal("Expected error for partial symbol match")
 		}
@@ -449,7 +450,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Rename GetName method in interface on line 7
-		result, err := Rename(mainFile, 7, "GetName", "GetFullName")
+		result, err := Rename(context.Background(), mainFile, 7, "GetName", "GetFullName")
 		if err != nil {
 			t.Fatalf("Failed to rename interface method: %v", err)
 		}
@@ -483,7 +484,7 @@ func TestRename(t *testing.T) {
 		mainFile := filepath.Join(workspace, "main.go")
 
 		// Rename GetName implementation method on line 18
-		result, err := Rename(mainFile, 18, "GetName", "GetFullName")
+		result, err := Rename(context.Background(), mainFile, 18, "GetName", "GetFullName")
 		if err != nil {
 			t.Fatalf("Failed to rename implementation method: %v", err)
 		}
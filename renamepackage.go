@@ -0,0 +1,342 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	renamePackageToolName        = "rename_package"
+	renamePackageToolDescription = `Renames a Go package and/or moves its directory within the module, rewriting the package clauses, all import paths and all references qualified with the package name across the module. Moving a directory moves its subpackages along with it.
+
+Files where the new package name would clash with another identifier keep referring to the package by its old name through an import alias. Import paths in non-Go files are not rewritten.`
+)
+
+func AddRenamePackageTool(mcpServer *server.MCPServer) {
+	handleRenamePackage := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		dir, err := request.RequireString("path")
+		if err != nil {
+			return nil, err
+		}
+		newName := request.GetString("new_name", "")
+		newDir := request.GetString("new_path", "")
+		if newName == "" && newDir == "" {
+			return toolErrorResult("Error: new_name or new_path is required"), nil
+		}
+
+		result, err := RenamePackage(dir, newName, newDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error renaming package: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		renamePackageToolName,
+		mcp.WithDescription(renamePackageToolDescription),
+		editingToolAnnotation(true, true),
+		mcp.WithString("path",
+			mcp.Description("Absolute path of the package directory"),
+			mcp.Required(),
+		),
+		mcp.WithString("new_name",
+			mcp.Description("New package name, the name is kept when omitted"),
+		),
+		mcp.WithString("new_path",
+			mcp.Description("Absolute path of the new package directory within the module, the directory is kept when omitted"),
+		),
+	), handleRenamePackage)
+}
+
+// RenamePackage renames the package in dir to newName and moves it to newDir, rewriting
+// the imports and qualified references of the module. An empty newName keeps the name
+// and an empty newDir keeps the directory.
+func RenamePackage(dir string, newName string, newDir string) (string, error) {
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("path must be an absolute directory path, got: %s", dir)
+	}
+	dir = filepath.Clean(dir)
+	module, err := loadModuleSources(dir, true)
+	if err != nil {
+		return "", err
+	}
+	var pkg *packageSources
+	for _, candidate := range module.packages {
+		if candidate.dir == dir {
+			pkg = candidate
+		}
+	}
+	if pkg == nil || pkg.name == "" {
+		return "", fmt.Errorf("no Go package found in %s", dir)
+	}
+
+	oldName := pkg.name
+	if newName == "" {
+		newName = oldName
+	}
+	if !token.IsIdentifier(newName) || newName == "_" || strings.HasSuffix(newName, "_test") {
+		return "", fmt.Errorf("%q is not a valid package name", newName)
+	}
+	if oldName == "main" && newName != oldName {
+		return "", fmt.Errorf("main packages cannot be renamed, as the package name makes them commands")
+	}
+
+	oldImportPath := pkg.importPath
+	newImportPath := oldImportPath
+	if newDir != "" && filepath.Clean(newDir) != dir {
+		if !filepath.IsAbs(newDir) {
+			return "", fmt.Errorf("new_path must be an absolute directory path, got: %s", newDir)
+		}
+		newDir = filepath.Clean(newDir)
+		if !isFileInWorkspace(newDir, module.root) || newDir == module.root {
+			return "", fmt.Errorf("new_path must be a directory within the module %s", module.root)
+		}
+		if isFileInWorkspace(newDir, dir) {
+			return "", fmt.Errorf("a package cannot be moved into its own directory")
+		}
+		if _, err := os.Stat(newDir); err == nil {
+			return "", fmt.Errorf("%s already exists", newDir)
+		}
+		rel, err := filepath.Rel(module.root, newDir)
+		if err != nil {
+			return "", err
+		}
+		newImportPath = path.Join(module.path, filepath.ToSlash(rel))
+	} else {
+		newDir = dir
+	}
+	if newName == oldName && newImportPath == oldImportPath {
+		return fmt.Sprintf("Package '%s' already has the desired name and directory", oldImportPath), nil
+	}
+
+	rename := packageRename{
+		dir:           dir,
+		oldName:       oldName,
+		newName:       newName,
+		oldImportPath: oldImportPath,
+		newImportPath: newImportPath,
+	}
+	var changed []string
+	for _, candidate := range module.packages {
+		for _, file := range candidate.files {
+			if candidate != pkg && !rename.importsPackage(file.ast) {
+				continue
+			}
+			ok, err := rename.rewriteFile(file.path)
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				changed = append(changed, module.relPath(file.path))
+			}
+		}
+	}
+
+	if newDir != dir {
+		if err := os.MkdirAll(filepath.Dir(newDir), 0755); err != nil {
+			return "", fmt.Errorf("failed to create the parent directory of %s: %w", newDir, err)
+		}
+		if err := os.Rename(dir, newDir); err != nil {
+			return "", fmt.Errorf("failed to move %s to %s: %w", dir, newDir, err)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Renamed package %s (%s) to %s (%s)", oldName, oldImportPath, newName, newImportPath)
+	if newDir != dir {
+		fmt.Fprintf(&b, ", moved %s to %s", module.relPath(dir), module.relPath(newDir))
+	}
+	fmt.Fprintf(&b, "\nUpdated %d files:\n", len(changed))
+	for _, file := range changed {
+		fmt.Fprintf(&b, "- %s\n", file)
+	}
+	return b.String(), nil
+}
+
+// packageRename rewrites the files of a module for a renamed or moved package
+type packageRename struct {
+	dir           string
+	oldName       string
+	newName       string
+	oldImportPath string
+	newImportPath string
+}
+
+// newImportPathOf returns the import path after the rename, or an empty string when the
+// imported package is not affected. Subpackages move along with the package.
+func (rename *packageRename) newImportPathOf(importPath string) string {
+	if importPath == rename.oldImportPath {
+		return rename.newImportPath
+	}
+	if rename.oldImportPath != rename.newImportPath && strings.HasPrefix(importPath, rename.oldImportPath+"/") {
+		return rename.newImportPath + strings.TrimPrefix(importPath, rename.oldImportPath)
+	}
+	return ""
+}
+
+func (rename *packageRename) importsPackage(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err == nil && rename.newImportPathOf(importPath) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteFile rewrites the package clause, imports and qualified references of the file,
+// reporting whether it changed
+func (rename *packageRename) rewriteFile(filePath string) (bool, error) {
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return false, err
+	}
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		return false, err
+	}
+	// The file is parsed again, as the edits need positions matching its current content
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, original, parser.ParseComments)
+	if err != nil {
+		return false, classifyErrorf(ErrSyntaxErrors, "failed to parse %s: %w", filePath, err)
+	}
+
+	var edits []textEdit
+	replace := func(node ast.Node, replacement string) {
+		edits = append(edits, textEdit{
+			start:       fset.Position(node.Pos()).Offset,
+			end:         fset.Position(node.End()).Offset,
+			replacement: replacement,
+		})
+	}
+
+	nameChanged := rename.newName != rename.oldName
+	if filepath.Dir(filePath) == rename.dir && nameChanged {
+		switch file.Name.Name {
+		case rename.oldName:
+			replace(file.Name, rename.newName)
+		case rename.oldName + "_test":
+			replace(file.Name, rename.newName+"_test")
+		}
+		if file.Doc != nil {
+			// Keep the conventional "Package name ..." doc comment in sync
+			first := file.Doc.List[0]
+			prefix := "// Package " + rename.oldName
+			if rest, ok := strings.CutPrefix(first.Text, prefix); ok && (rest == "" || rest[0] == ' ') {
+				replace(first, "// Package "+rename.newName+rest)
+			}
+		}
+	}
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		newImportPath := rename.newImportPathOf(importPath)
+		if newImportPath == "" {
+			continue
+		}
+		if newImportPath != importPath {
+			replace(spec.Path, strconv.Quote(newImportPath))
+		}
+		if importPath != rename.oldImportPath || spec.Name != nil || !nameChanged {
+			continue
+		}
+		if fileUsesIdentifier(file, rename.newName) {
+			// The new name would clash, so the file keeps the old one as import alias
+			edits = append(edits, textEdit{
+				start:       fset.Position(spec.Path.Pos()).Offset,
+				end:         fset.Position(spec.Path.Pos()).Offset,
+				replacement: rename.oldName + " ",
+			})
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			selector, ok := node.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			// Package qualifiers are not resolved to local objects by the parser
+			if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == rename.oldName && ident.Obj == nil {
+				replace(ident, rename.newName)
+			}
+			return true
+		})
+	}
+	if len(edits) == 0 {
+		return false, nil
+	}
+
+	src := applyTextEdits(original, edits)
+
+	// Keep gofmt-clean files gofmt-clean, e.g. by sorting the changed imports
+	if formattedOriginal, err := format.Source(original); err == nil &&
+		bytes.Equal(formattedOriginal, original) {
+		if formatted, err := format.Source(src); err == nil {
+			src = formatted
+		}
+	}
+	if err := os.WriteFile(filePath, src, stat.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+	return true, nil
+}
+
+// textEdit replaces the bytes from start to end of a file, inserting when they are equal
+type textEdit struct {
+	start, end  int
+	replacement string
+}
+
+// applyTextEdits returns src with the non-overlapping edits applied
+func applyTextEdits(src []byte, edits []textEdit) []byte {
+	edits = slices.Clone(edits)
+	// Insertions go before the replacement starting at the same offset
+	slices.SortFunc(edits, func(a, b textEdit) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		return a.end - b.end
+	})
+	var out bytes.Buffer
+	offset := 0
+	for _, edit := range edits {
+		out.Write(src[offset:edit.start])
+		out.WriteString(edit.replacement)
+		offset = edit.end
+	}
+	out.Write(src[offset:])
+	return out.Bytes()
+}
+
+// fileUsesIdentifier reports whether name is used as any identifier in the file other
+// than its package clause
+func fileUsesIdentifier(file *ast.File, name string) bool {
+	found := false
+	ast.Inspect(file, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && ident != file.Name && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,167 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRenamePackage(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a util package used by main and a clashing file
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"util/util.go": {
+				"// Package util has helpers.",          // 1
+				"package util",                          // 2
+				"",                                      // 3
+				"import \"example.com/app/util/inner\"", // 4
+				"",                                      // 5
+				"func Double(n int) int { return inner.Twice(n) }", // 6
+			},
+			"util/util_test.go": {
+				"package util_test",          // 1
+				"",                           // 2
+				"import (",                   // 3
+				"\t\"testing\"",              // 4
+				"",                           // 5
+				"\t\"example.com/app/util\"", // 6
+				")",                          // 7
+				"",                           // 8
+				"func TestDouble(t *testing.T) { _ = util.Double(1) }", // 9
+			},
+			"util/inner/inner.go": {
+				"package inner",                          // 1
+				"",                                       // 2
+				"func Twice(n int) int { return n * 2 }", // 3
+			},
+			"main.go": {
+				"package main",                  // 1
+				"",                              // 2
+				"import (",                      // 3
+				"\t\"fmt\"",                     // 4
+				"",                              // 5
+				"\t\"example.com/app/util\"",    // 6
+				")",                             // 7
+				"",                              // 8
+				"func main() {",                 // 9
+				"\tfmt.Println(util.Double(2))", // 10
+				"}",                             // 11
+				"",                              // 12
+			},
+			"clash/clash.go": {
+				"package clash",                   // 1
+				"",                                // 2
+				"import \"example.com/app/util\"", // 3
+				"",                                // 4
+				"var mathutil = util.Double(3)",   // 5
+				"",                                // 6
+			},
+		})
+	}
+
+	readFile := func(t testing.TB, path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	t.Run("rename and move", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		result, err := RenamePackage(filepath.Join(workspace, "util"), "mathutil", filepath.Join(workspace, "pkg", "mathutil"))
+		if err != nil {
+			t.Fatalf("Failed to rename package: %v", err)
+		}
+		if !strings.Contains(result, "Updated 4 files") {
+			t.Errorf("Expected four updated files, got: %s", result)
+		}
+		if _, err := os.Stat(filepath.Join(workspace, "util")); !os.IsNotExist(err) {
+			t.Errorf("Expected the old directory to be moved, got: %v", err)
+		}
+
+		for name, expected := range map[string][]string{
+			"pkg/mathutil/util.go": {
+				"// Package mathutil has helpers.\npackage mathutil\n",
+				"import \"example.com/app/pkg/mathutil/inner\"",
+			},
+			"pkg/mathutil/util_test.go": {
+				"package mathutil_test\n",
+				"\"example.com/app/pkg/mathutil\"",
+				"_ = mathutil.Double(1)",
+			},
+			"pkg/mathutil/inner/inner.go": {"package inner\n"},
+			"main.go": {
+				"\t\"example.com/app/pkg/mathutil\"",
+				"fmt.Println(mathutil.Double(2))",
+			},
+			"clash/clash.go": {
+				"import util \"example.com/app/pkg/mathutil\"",
+				"var mathutil = util.Double(3)",
+			},
+		} {
+			content := readFile(t, filepath.Join(workspace, name))
+			for _, text := range expected {
+				if !strings.Contains(content, text) {
+					t.Errorf("Expected %q in %s, got:\n%s", text, name, content)
+				}
+			}
+		}
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		for _, name := range []string{"func", "a-b", "util_test"} {
+			if _, err := RenamePackage(filepath.Join(workspace, "util"), name, ""); err == nil {
+				t.Errorf("Expected %q to be rejected", name)
+			}
+		}
+		if _, err := RenamePackage(workspace, "app", ""); err == nil {
+			t.Errorf("Expected renaming a main package to fail")
+		}
+		if _, err := RenamePackage(filepath.Join(workspace, "util"), "", filepath.Join(workspace, "clash")); err == nil {
+			t.Errorf("Expected moving onto an existing directory to fail")
+		}
+	})
+
+	t.Run("rename package tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name": renamePackageToolName,
+				"arguments": map[string]any{
+					"path":     filepath.Join(workspace, "util"),
+					"new_name": "helpers",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "Renamed package util (example.com/app/util) to helpers (example.com/app/util)") {
+			t.Errorf("Expected the package to be renamed, got: %s", text)
+		}
+		if content := readFile(t, filepath.Join(workspace, "main.go")); !strings.Contains(content, "helpers.Double(2)") {
+			t.Errorf("Expected main.go to use the new name, got:\n%s", content)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package go_mcp_tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// REPLOptions configures an interactive session of RunREPL
+type REPLOptions struct {
+	// WorkspaceDir is the directory commands resolve relative paths and packages in
+	WorkspaceDir string
+	// MetadataCacheDir is the directory of a disk cache of the metadata of loaded packages,
+	// shared with the inspect tool. Packages are only kept in memory when empty.
+	MetadataCacheDir string
+	// Prompt is written before reading every command, "> " when empty
+	Prompt string
+}
+
+// replHelp lists the commands of the REPL
+const replHelp = `Commands:
+  inspect <path>     Summarize a package, file or symbol, e.g. ./pkg, main.go:42 or ./pkg:Name
+  refs <symbol>      List the references of a symbol, e.g. main.go:42:Name or ./pkg:Name
+  impl <symbol>      List the types implementing an interface
+  callers <symbol>   Show the incoming and outgoing calls of a function
+  workspace [dir]    Show or change the workspace directory
+  reload             Forget the loaded packages, e.g. after editing files
+  history            List the commands entered so far
+  !!, !<n>           Repeat the last or the nth command
+  help               Show this help
+  quit, exit         Leave the REPL (or end the input, e.g. with Ctrl-D)
+`
+
+// repl is the state of an interactive session kept between commands
+type repl struct {
+	options REPLOptions
+	history []string
+	// loadedPackages keeps the packages loaded by earlier commands warm
+	loadedPackages *packageMemo
+}
+
+// RunREPL reads commands from in and writes their results to out until the input ends
+// or the quit command, inspecting code with the same engine as the MCP tools. Loaded
+// packages and parsed files stay cached between commands, so exploring a package after
+// its first command is fast. Errors of commands are written to out and do not end the
+// session.
+func RunREPL(ctx context.Context, in io.Reader, out io.Writer, options REPLOptions) error {
+	if options.Prompt == "" {
+		options.Prompt = "> "
+	}
+	workspaceDir, err := filepath.Abs(options.WorkspaceDir)
+	if err != nil {
+		return err
+	}
+	options.WorkspaceDir = workspaceDir
+	session := &repl{options: options, loadedPackages: &packageMemo{}}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), jsonlMaxLineSize)
+	for {
+		fmt.Fprint(out, options.Prompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// History expansion repeats an earlier command, which is echoed like a shell does
+		if strings.HasPrefix(line, "!") {
+			expanded, err := session.expand(line)
+			if err != nil {
+				fmt.Fprintf(out, "Error: %v\n", err)
+				continue
+			}
+			line = expanded
+			fmt.Fprintln(out, line)
+		}
+		session.history = append(session.history, line)
+
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		text, err := session.execute(ctx, line)
+		if err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+			continue
+		}
+		fmt.Fprint(out, text)
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			fmt.Fprintln(out)
+		}
+	}
+}
+
+// expand returns the command of the history referred to by !! or !n, counting from 1
+func (session *repl) expand(line string) (string, error) {
+	if len(session.history) == 0 {
+		return "", fmt.Errorf("no commands in the history yet")
+	}
+	if line == "!!" {
+		return session.history[len(session.history)-1], nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(session.history) {
+		return "", fmt.Errorf("%s is not in the history, use !! or !1 to !%d", line, len(session.history))
+	}
+	return session.history[n-1], nil
+}
+
+// execute runs a command and returns its output
+func (session *repl) execute(ctx context.Context, line string) (string, error) {
+	fields := strings.Fields(line)
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "help":
+		return replHelp, nil
+	case "history":
+		var b strings.Builder
+		for i, entry := range session.history {
+			fmt.Fprintf(&b, "%4d  %s\n", i+1, entry)
+		}
+		return b.String(), nil
+	case "workspace":
+		if len(args) == 0 {
+			return session.options.WorkspaceDir, nil
+		}
+		dir := args[0]
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(session.options.WorkspaceDir, dir)
+		}
+		if _, err := findModuleRoot(dir); err != nil {
+			return "", err
+		}
+		session.options.WorkspaceDir = filepath.Clean(dir)
+		session.loadedPackages = &packageMemo{}
+		return "Workspace: " + session.options.WorkspaceDir, nil
+	case "reload":
+		session.loadedPackages = &packageMemo{}
+		globalFileCache.ClearCache()
+		return "Forgot the loaded packages and parsed files", nil
+	case "inspect", "refs", "impl", "callers":
+		if len(args) != 1 {
+			return "", fmt.Errorf("%s takes one path, see help", command)
+		}
+		return session.inspect(ctx, command, args[0])
+	}
+	return "", fmt.Errorf("unknown command %q, see help", command)
+}
+
+// inspect runs an inspection with only the section of the command enabled and returns
+// the whole result for inspect, or the section for the other commands
+func (session *repl) inspect(ctx context.Context, command string, pathStr string) (string, error) {
+	path, lineNumber, symbolName := parseInspectPath(pathStr)
+	options := DefaultInspectOptions(session.options.WorkspaceDir)
+	options.LineNumber = lineNumber
+	options.SymbolName = symbolName
+	options.MetadataCacheDir = session.options.MetadataCacheDir
+	options.loadedPackages = session.loadedPackages
+	// The gopls backed sections have their own commands, leaving inspect fast
+	options.IncludeReferences = command == "refs"
+	options.IncludeImplementers = command == "impl"
+	options.IncludeCallHierarchy = command == "callers"
+	if command != "inspect" && lineNumber == 0 && symbolName == "" {
+		return "", fmt.Errorf("%s needs a symbol, e.g. main.go:42:Name or ./pkg:Name", command)
+	}
+
+	result, err := InspectStructured(ctx, path, options)
+	if err != nil {
+		return "", err
+	}
+	if command == "inspect" {
+		return result.String(), nil
+	}
+
+	symbol := result.Symbol
+	if symbol == nil {
+		return "", fmt.Errorf("%s needs a symbol, e.g. main.go:42:Name or ./pkg:Name", command)
+	}
+	var b strings.Builder
+	switch command {
+	case "refs":
+		if len(symbol.References) == 0 {
+			return "", fmt.Errorf("%s has no references to list", symbol.Name)
+		}
+		for i := range symbol.References {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			writeReferences(&b, &symbol.References[i])
+		}
+	case "impl":
+		if symbol.Implementers == nil {
+			return "", fmt.Errorf("%s is not an interface or type, impl needs one", symbol.Name)
+		}
+		writeImplementers(&b, symbol.Implementers)
+	case "callers":
+		if symbol.CallHierarchy == nil {
+			return "", fmt.Errorf("%s is not a function, callers needs one", symbol.Name)
+		}
+		writeCallHierarchy(&b, symbol.CallHierarchy)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,107 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestREPL(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/shapes", "", "go 1.22", ""},
+		"shapes.go": {
+			"package shapes", // 1
+			"",
+			"// Shape has an area", // 3
+			"type Shape interface {",
+			"	Area() float64", // 5
+			"}",
+			"",
+			"// Square is a shape", // 8
+			"type Square struct{ Side float64 }",
+			"", // 10
+			"// Area returns the area of the square",
+			"func (s Square) Area() float64 { return s.Side * s.Side }",
+			"",
+		},
+		"util/util.go": {
+			"package util", // 1
+			"",
+			"// Double doubles x", // 3
+			"func Double(x int) int { return 2 * x }",
+			"",
+		},
+	})
+
+	run := func(t *testing.T, commands ...string) string {
+		t.Helper()
+		var out strings.Builder
+		input := strings.NewReader(strings.Join(commands, "\n") + "\n")
+		if err := RunREPL(context.Background(), input, &out, REPLOptions{WorkspaceDir: workspace, Prompt: "$ "}); err != nil {
+			t.Fatalf("Failed to run the REPL: %v", err)
+		}
+		return out.String()
+	}
+
+	t.Run("inspect", func(t *testing.T) {
+		t.Parallel()
+		output := run(t, "inspect shapes.go:12", "inspect ./util:Double", "!!", "history", "quit", "inspect ./util")
+		for _, expected := range []string{
+			"$ Lines: 12\nDocstring: Area returns the area of the square\nCode:\nfunc (s Square) Area() float64 { return s.Side * s.Side }\n",
+			"$ Lines: 4\nDocstring: Double doubles x\nCode:\nfunc Double(x int) int { return 2 * x }\n",
+			// The repeated command is echoed before its output
+			"$ inspect ./util:Double\nLines: 4\n",
+			"$    1  inspect shapes.go:12\n   2  inspect ./util:Double\n   3  inspect ./util:Double\n   4  history\n$ ",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected the output to contain %q, got:\n%s", expected, output)
+			}
+		}
+		if strings.Contains(output, "References") || strings.Contains(output, "Call Hierarchy") {
+			t.Errorf("Expected inspect to leave out the gopls backed sections, got:\n%s", output)
+		}
+		if !strings.HasSuffix(output, "4  history\n$ ") {
+			t.Errorf("Expected quit to end the session, got:\n%s", output)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		output := run(t, "!1", "frobnicate", "refs ./util", "callers shapes.go:4:Shape", "inspect ./util:Missing", "!7", "help")
+		for _, expected := range []string{
+			"$ Error: no commands in the history yet\n",
+			"$ Error: unknown command \"frobnicate\", see help\n",
+			"$ Error: refs needs a symbol, e.g. main.go:42:Name or ./pkg:Name\n",
+			"$ Error: Shape is not a function, callers needs one\n",
+			"$ Error: symbol 'Missing' not found in package\n",
+			"$ Error: !7 is not in the history, use !! or !1 to !4\n",
+			"$ Commands:\n  inspect <path>",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected the output to contain %q, got:\n%s", expected, output)
+			}
+		}
+		// The session ends with the input
+		if !strings.HasSuffix(output, "$ \n") {
+			t.Errorf("Expected the session to end with the input, got:\n%s", output)
+		}
+	})
+
+	t.Run("workspace", func(t *testing.T) {
+		t.Parallel()
+		output := run(t, "workspace", "workspace util", "inspect util.go", "workspace /nonexistent")
+		for _, expected := range []string{
+			"$ " + workspace + "\n",
+			"$ Workspace: " + filepath.Join(workspace, "util") + "\n",
+			"func Double(x int) int",
+			"$ Error: ",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected the output to contain %q, got:\n%s", expected, output)
+			}
+		}
+	})
+}
@@ -0,0 +1,599 @@
+package go_mcp_tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	minimalReproToolName        = "minimal_repro"
+	minimalReproToolDescription = `Extracts a minimal self-contained reproduction of a failing test or a panic into a single runnable Go file, to file upstream bugs or to isolate misbehavior.
+
+Given a test, benchmark, fuzz test or example declared in the file, the file is repro_test.go, run with go test repro_test.go. Given a line, e.g. of a panic, the function enclosing it is reproduced in repro.go, whose main function calls it with zero values for its arguments and receiver, to be replaced by those of the failing call, run with go run repro.go.
+
+The function is copied with the declarations of the module it needs transitively: functions, types, variables, constants, and the methods called or implementing the interfaces used, such as Error and String. Declarations of other packages of the module are copied into the file, renamed when their names conflict, while the standard library and other modules stay imported and need a go.mod requiring them. init functions and TestMain are not copied. Nothing is written.`
+)
+
+func AddMinimalReproTool(mcpServer *server.MCPServer) {
+	handleMinimalRepro := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		var options ReproOptions
+		options.Test = request.GetString("test", "")
+		options.Line = request.GetInt("line", 0)
+
+		repro, err := ExtractRepro(ctx, filePath, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error extracting reproduction: %v", err)), nil
+		}
+		return mcp.NewToolResultText(repro.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		minimalReproToolName,
+		mcp.WithDescription(minimalReproToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the test or containing the line"),
+			mcp.Required(),
+		),
+		mcp.WithString("test",
+			mcp.Description("Name of the failing test, benchmark, fuzz test or example declared in the file"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("Line number (1-based) in the file, e.g. of a panic, whose enclosing function is reproduced. Used when no test is given."),
+		),
+	), handleMinimalRepro)
+}
+
+// ReproOptions selects the function ExtractRepro reproduces, by Test or by Line
+type ReproOptions struct {
+	// Test is the name of a test, benchmark, fuzz test or example declared in the file
+	Test string
+	// Line is a line of the file, e.g. of a panic, whose enclosing function is reproduced
+	Line int
+}
+
+// Repro is a single file reproducing a test or a call of a function
+type Repro struct {
+	// FileName is repro_test.go for tests, and repro.go with a main function otherwise
+	FileName string `json:"file_name"`
+	Code     string `json:"code"`
+	// Declarations are the copied declarations, qualified by their package name
+	Declarations []string `json:"declarations"`
+	// Imports are the imported packages, outside the module
+	Imports []string `json:"imports,omitempty"`
+}
+
+// reproDecl is a declaration of the module that can be copied into a reproduction
+type reproDecl struct {
+	pkg *packages.Package
+	// node is a *ast.FuncDecl, a *ast.TypeSpec or *ast.ValueSpec with the keyword tok, or
+	// the *ast.GenDecl of a const group, copied whole as its specs may depend on iota
+	node ast.Node
+	tok  token.Token
+	doc  *ast.CommentGroup
+	objs []types.Object
+}
+
+// reproExtraction collects the declarations of a reproduction and rewrites their source
+type reproExtraction struct {
+	module  *moduleSources
+	decls   map[types.Object]*reproDecl
+	names   map[types.Object]string
+	imports map[string]string
+	sources map[string][]byte
+}
+
+// ExtractRepro copies the test or the function enclosing the line of filePath, with the
+// declarations of its module it needs, into a single runnable file
+func ExtractRepro(ctx context.Context, filePath string, options ReproOptions) (*Repro, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if (options.Test == "") == (options.Line <= 0) {
+		return nil, fmt.Errorf("either a test or a line is required")
+	}
+	module, err := readModule(filepath.Dir(filePath))
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Dir:   filepath.Dir(filePath),
+		Env:   packagesEnv(filepath.Dir(filePath)),
+		Tests: true,
+	}, "file="+filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the package of %s: %w", filePath, err)
+	}
+	// Non-test files belong to the package and its test variant, the package is reproduced
+	var pkg *packages.Package
+	for _, candidate := range pkgs {
+		if slices.Contains(candidate.GoFiles, filePath) && (pkg == nil || !strings.Contains(candidate.ID, " [")) {
+			pkg = candidate
+		}
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("no package found for %s", filePath)
+	}
+	if len(pkg.Errors) > 0 {
+		return nil, classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+	var file *ast.File
+	for _, syntax := range pkg.Syntax {
+		if pkg.Fset.Position(syntax.Pos()).Filename == filePath {
+			file = syntax
+		}
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s was not type checked", filePath)
+	}
+
+	var root *ast.FuncDecl
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if options.Test != "" && decl.Name.Name == options.Test && goTestKind(decl) != "" ||
+			options.Test == "" && pkg.Fset.Position(decl.Pos()).Line <= options.Line && options.Line <= pkg.Fset.Position(decl.End()).Line {
+			root = decl
+		}
+	}
+	if root == nil && options.Test != "" {
+		return nil, classifyErrorf(ErrSymbolNotFound, "no test, benchmark, fuzz test or example '%s' declared in %s", options.Test, filePath)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("line %d of %s is not in a function", options.Line, filePath)
+	}
+	if options.Test == "" && (root.Type.TypeParams != nil || root.Recv != nil && !isPlainReceiver(root.Recv.List[0].Type)) {
+		return nil, fmt.Errorf("%s is generic, its type arguments cannot be chosen", root.Name.Name)
+	}
+
+	extraction := &reproExtraction{
+		module:  module,
+		decls:   make(map[types.Object]*reproDecl),
+		names:   make(map[types.Object]string),
+		imports: make(map[string]string),
+		sources: make(map[string][]byte),
+	}
+	packages.Visit(pkgs, nil, extraction.indexPackage)
+	rootObj := pkg.TypesInfo.Defs[root.Name]
+	if rootObj == nil || extraction.decls[rootObj] == nil {
+		return nil, fmt.Errorf("%s was not type checked", root.Name.Name)
+	}
+	included := extraction.collect(extraction.decls[rootObj])
+	extraction.rename(included, pkg, options.Test == "")
+
+	repro := &Repro{FileName: "repro_test.go"}
+	var body strings.Builder
+	for _, decl := range included {
+		text, err := extraction.declSource(decl)
+		if err != nil {
+			return nil, err
+		}
+		body.WriteString("\n" + text + "\n")
+		for _, obj := range decl.objs {
+			repro.Declarations = append(repro.Declarations, reproName(obj))
+		}
+	}
+	packageName := "repro"
+	if options.Test == "" {
+		repro.FileName = "repro.go"
+		packageName = "main"
+		text, err := extraction.mainSource(extraction.decls[rootObj])
+		if err != nil {
+			return nil, err
+		}
+		body.WriteString("\n" + text)
+	}
+
+	var b strings.Builder
+	run := "go test " + repro.FileName
+	if options.Test == "" {
+		run = "go run " + repro.FileName
+	}
+	fmt.Fprintf(
+		&b,
+		"// Minimal reproduction of %s of %s, extracted with the declarations it needs.\n// Run it with: %s\npackage %s\n",
+		strings.SplitN(reproName(rootObj), ".", 2)[1], strings.TrimSuffix(pkg.PkgPath, "_test"), run, packageName,
+	)
+	if len(extraction.imports) > 0 {
+		b.WriteString("\nimport (\n")
+		for _, name := range slices.Sorted(maps.Keys(extraction.imports)) {
+			importPath := extraction.imports[name]
+			repro.Imports = append(repro.Imports, importPath)
+			if name == path.Base(importPath) {
+				fmt.Fprintf(&b, "\t%q\n", importPath)
+			} else {
+				fmt.Fprintf(&b, "\t%s %q\n", name, importPath)
+			}
+		}
+		b.WriteString(")\n")
+	}
+	slices.Sort(repro.Imports)
+	b.WriteString(body.String())
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the reproduction: %w", err)
+	}
+	repro.Code = string(formatted)
+	return repro, nil
+}
+
+// isPlainReceiver reports whether a receiver type has no type parameters
+func isPlainReceiver(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	_, ok := expr.(*ast.Ident)
+	return ok
+}
+
+// inModule reports whether the package, or the package an external test package tests,
+// is part of the module
+func (extraction *reproExtraction) inModule(pkgPath string) bool {
+	pkgPath = strings.TrimSuffix(pkgPath, "_test")
+	return pkgPath == extraction.module.path || strings.HasPrefix(pkgPath, extraction.module.path+"/")
+}
+
+// indexPackage records the declarations of the package when it is part of the module
+func (extraction *reproExtraction) indexPackage(pkg *packages.Package) {
+	if !extraction.inModule(pkg.PkgPath) || pkg.TypesInfo == nil {
+		return
+	}
+	add := func(decl *reproDecl, idents ...*ast.Ident) {
+		for _, ident := range idents {
+			if obj := pkg.TypesInfo.Defs[ident]; obj != nil && ident.Name != "_" {
+				decl.objs = append(decl.objs, obj)
+				extraction.decls[obj] = decl
+			}
+		}
+	}
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				add(&reproDecl{pkg: pkg, node: decl, doc: decl.Doc}, decl.Name)
+			case *ast.GenDecl:
+				if decl.Tok == token.CONST {
+					group := &reproDecl{pkg: pkg, node: decl, doc: decl.Doc}
+					for _, spec := range decl.Specs {
+						add(group, spec.(*ast.ValueSpec).Names...)
+					}
+					continue
+				}
+				for _, spec := range decl.Specs {
+					doc := decl.Doc
+					if decl.Lparen.IsValid() {
+						doc = nil
+					}
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						add(&reproDecl{pkg: pkg, node: spec, tok: decl.Tok, doc: cmp.Or(spec.Doc, doc)}, spec.Name)
+					case *ast.ValueSpec:
+						add(&reproDecl{pkg: pkg, node: spec, tok: decl.Tok, doc: cmp.Or(spec.Doc, doc)}, spec.Names...)
+					}
+				}
+			}
+		}
+	}
+}
+
+// collect returns the root declaration followed by the declarations it needs
+// transitively, in the order of their packages and positions
+func (extraction *reproExtraction) collect(root *reproDecl) []*reproDecl {
+	included := map[*reproDecl]bool{root: true}
+	queue := []*reproDecl{root}
+	include := func(obj types.Object) {
+		if fn, ok := obj.(*types.Func); ok {
+			obj = fn.Origin()
+		}
+		if decl := extraction.decls[obj]; decl != nil && !included[decl] {
+			included[decl] = true
+			queue = append(queue, decl)
+		}
+	}
+
+	// Methods called implicitly through interfaces are copied by name, those of the
+	// interfaces used and the methods fmt and errors call
+	interfaceMethods := map[string]bool{"Error": true, "String": true}
+	var namedTypes []*types.Named
+	for len(queue) > 0 {
+		decl := queue[0]
+		queue = queue[1:]
+		ast.Inspect(decl.node, func(node ast.Node) bool {
+			ident, ok := node.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			obj := decl.pkg.TypesInfo.Uses[ident]
+			if obj == nil {
+				return true
+			}
+			if typeName, ok := obj.(*types.TypeName); ok {
+				if iface, ok := typeName.Type().Underlying().(*types.Interface); ok {
+					for method := range iface.Methods() {
+						interfaceMethods[method.Name()] = true
+					}
+				}
+			}
+			include(obj)
+			return true
+		})
+		for _, obj := range decl.objs {
+			if named, ok := obj.Type().(*types.Named); ok {
+				if _, isTypeName := obj.(*types.TypeName); isTypeName {
+					namedTypes = append(namedTypes, named)
+				}
+			}
+		}
+		if len(queue) == 0 {
+			for _, named := range namedTypes {
+				for method := range named.Methods() {
+					if interfaceMethods[method.Name()] {
+						include(method)
+					}
+				}
+			}
+		}
+	}
+
+	var decls []*reproDecl
+	for decl := range included {
+		if decl != root {
+			decls = append(decls, decl)
+		}
+	}
+	slices.SortFunc(decls, func(a, b *reproDecl) int {
+		// The package of the root comes first
+		return cmp.Or(
+			cmp.Compare(btoi(a.pkg != root.pkg), btoi(b.pkg != root.pkg)),
+			strings.Compare(a.pkg.PkgPath, b.pkg.PkgPath),
+			cmp.Compare(a.pkg.Fset.Position(a.node.Pos()).Filename, b.pkg.Fset.Position(b.node.Pos()).Filename),
+			cmp.Compare(a.node.Pos(), b.node.Pos()),
+		)
+	})
+	return append([]*reproDecl{root}, decls...)
+}
+
+// btoi converts a bool to 1 or 0 for comparisons
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// rename names the package level objects of the declarations, keeping the names of the
+// package of the root and prefixing conflicting names of other packages by their package
+// name. A main function is renamed when the reproduction declares its own.
+func (extraction *reproExtraction) rename(decls []*reproDecl, rootPkg *packages.Package, declaresMain bool) {
+	taken := make(map[string]bool)
+	if declaresMain {
+		taken["main"] = true
+	}
+	for _, decl := range decls {
+		for _, obj := range decl.objs {
+			if fn, ok := obj.(*types.Func); ok && fn.Type().(*types.Signature).Recv() != nil {
+				continue
+			}
+			name := obj.Name()
+			for taken[name] {
+				first, size := utf8.DecodeRuneInString(name)
+				name = strings.TrimSuffix(decl.pkg.Name, "_test") + string(unicode.ToUpper(first)) + name[size:]
+				if decl.pkg == rootPkg && taken[name] {
+					name += "_"
+				}
+			}
+			taken[name] = true
+			extraction.names[obj] = name
+		}
+	}
+}
+
+// declSource returns the rewritten source of the declaration with its keyword and doc
+func (extraction *reproExtraction) declSource(decl *reproDecl) (string, error) {
+	text, err := extraction.source(decl.pkg, decl.node)
+	if err != nil {
+		return "", err
+	}
+	if decl.tok != token.ILLEGAL {
+		text = decl.tok.String() + " " + text
+	}
+	if decl.doc != nil {
+		var doc strings.Builder
+		for _, comment := range decl.doc.List {
+			doc.WriteString(comment.Text + "\n")
+		}
+		text = doc.String() + text
+	}
+	return text, nil
+}
+
+// source returns the source of the node with references to copied declarations of
+// other packages unqualified and renamed, recording the imports of other packages
+func (extraction *reproExtraction) source(pkg *packages.Package, node ast.Node) (string, error) {
+	filename := pkg.Fset.Position(node.Pos()).Filename
+	src, ok := extraction.sources[filename]
+	if !ok {
+		var err error
+		if src, err = os.ReadFile(filename); err != nil {
+			return "", err
+		}
+		extraction.sources[filename] = src
+	}
+	offset := func(pos token.Pos) int { return pkg.Fset.Position(pos).Offset }
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	var importErr error
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			x, ok := node.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			pkgName, ok := pkg.TypesInfo.Uses[x].(*types.PkgName)
+			if !ok {
+				return true
+			}
+			importPath := pkgName.Imported().Path()
+			if extraction.inModule(importPath) {
+				edits = append(edits, edit{offset(node.Pos()), offset(node.End()), extraction.name(pkg.TypesInfo.Uses[node.Sel])})
+				return false
+			}
+			if existing, ok := extraction.imports[x.Name]; ok && existing != importPath && importErr == nil {
+				importErr = fmt.Errorf("the name %s refers to both %s and %s, rename one of the imports", x.Name, existing, importPath)
+			}
+			extraction.imports[x.Name] = importPath
+			return false
+		case *ast.Ident:
+			obj := pkg.TypesInfo.Uses[node]
+			if obj == nil {
+				obj = pkg.TypesInfo.Defs[node]
+			}
+			if name := extraction.name(obj); obj != nil && name != node.Name {
+				edits = append(edits, edit{offset(node.Pos()), offset(node.End()), name})
+			}
+		}
+		return true
+	})
+	if importErr != nil {
+		return "", importErr
+	}
+
+	start, end := offset(node.Pos()), offset(node.End())
+	var b strings.Builder
+	for _, edit := range edits {
+		b.Write(src[start:edit.start])
+		b.WriteString(edit.text)
+		start = edit.end
+	}
+	b.Write(src[start:end])
+	return b.String(), nil
+}
+
+// name returns the name of the object in the reproduction
+func (extraction *reproExtraction) name(obj types.Object) string {
+	if fn, ok := obj.(*types.Func); ok {
+		obj = fn.Origin()
+	}
+	if name, ok := extraction.names[obj]; ok {
+		return name
+	}
+	if obj == nil {
+		return ""
+	}
+	return obj.Name()
+}
+
+// mainSource returns a main function calling the function of the declaration with zero
+// values for its receiver and parameters
+func (extraction *reproExtraction) mainSource(decl *reproDecl) (string, error) {
+	fn := decl.node.(*ast.FuncDecl)
+	var b strings.Builder
+	b.WriteString("func main() {\n\t// The arguments are zero values, set them to those of the failing call\n")
+	call := extraction.name(decl.objs[0])
+	if fn.Recv != nil {
+		receiverType := fn.Recv.List[0].Type
+		if star, ok := receiverType.(*ast.StarExpr); ok {
+			text, err := extraction.source(decl.pkg, star.X)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "\treceiver := new(%s)\n", text)
+		} else {
+			text, err := extraction.source(decl.pkg, receiverType)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "\tvar receiver %s\n", text)
+		}
+		call = "receiver." + fn.Name.Name
+	}
+	var arguments []string
+	for _, field := range fn.Type.Params.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{Name: "_"}}
+		}
+		for _, name := range names {
+			argument := name.Name
+			if argument == "_" {
+				argument = fmt.Sprintf("p%d", len(arguments))
+			}
+			paramType := field.Type
+			if ellipsis, ok := paramType.(*ast.Ellipsis); ok {
+				paramType = ellipsis.Elt
+			}
+			text, err := extraction.source(decl.pkg, paramType)
+			if err != nil {
+				return "", err
+			}
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				fmt.Fprintf(&b, "\tvar %s []%s\n", argument, text)
+				argument += "..."
+			} else {
+				fmt.Fprintf(&b, "\tvar %s %s\n", argument, text)
+			}
+			arguments = append(arguments, argument)
+		}
+	}
+	fmt.Fprintf(&b, "\t%s(%s)\n}\n", call, strings.Join(arguments, ", "))
+	return b.String(), nil
+}
+
+// reproName qualifies the name of an object by its package name, and the type name for methods
+func reproName(obj types.Object) string {
+	name := obj.Name()
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			t := recv.Type()
+			if pointer, ok := t.(*types.Pointer); ok {
+				t = pointer.Elem()
+			}
+			if named, ok := t.(*types.Named); ok {
+				name = named.Obj().Name() + "." + name
+			}
+		}
+	}
+	return strings.TrimSuffix(obj.Pkg().Name(), "_test") + "." + name
+}
+
+func (repro *Repro) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Copied %d declarations: %s\n", len(repro.Declarations), strings.Join(repro.Declarations, ", "))
+	if len(repro.Imports) > 0 {
+		fmt.Fprintf(&b, "Imports: %s\n", strings.Join(repro.Imports, ", "))
+	}
+	fmt.Fprintf(&b, "\n// %s\n%s", repro.FileName, repro.Code)
+	return b.String()
+}
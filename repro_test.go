@@ -0,0 +1,191 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractRepro(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/app", "", "go 1.22", ""},
+		"parser/parser.go": {
+			"package parser", // 1
+			"",
+			"import (",
+			"\t\"errors\"",
+			"",
+			"\t\"example.com/app/util\"",
+			")",
+			"",
+			"// ErrEmpty is returned for empty input", // 9
+			"var ErrEmpty = errors.New(\"empty input\")",
+			"",
+			"type Kind int", // 12
+			"",
+			"const (", // 14
+			"\tWord Kind = iota",
+			"\tNumber",
+			")",
+			"",
+			"// Token is a parsed token", // 19
+			"type Token struct {",
+			"\tKind  Kind",
+			"\tValue string",
+			"}",
+			"",
+			"func (t Token) String() string { return t.Value }", // 25
+			"",
+			"func (t Token) unused() {}", // 27
+			"",
+			"// Parse splits the input into tokens", // 29
+			"func Parse(input string) ([]Token, error) {",
+			"\tif input == \"\" {",
+			"\t\treturn nil, ErrEmpty",
+			"\t}",
+			"\tvar tokens []Token",
+			"\tfor _, field := range util.Fields(input) {",
+			"\t\tif valid(field) {",
+			"\t\t\ttokens = append(tokens, Token{Kind: kindOf(field), Value: field})",
+			"\t\t}",
+			"\t}",
+			"\treturn tokens, nil",
+			"}",
+			"",
+			"func valid(field string) bool { return field != \"-\" }", // 43
+			"",
+			"func kindOf(field string) Kind {", // 45
+			"\tif util.IsDigit(field[0]) {",
+			"\t\treturn Number",
+			"\t}",
+			"\treturn Word",
+			"}",
+			"",
+			"func Unrelated() {}", // 52
+			"",
+		},
+		"parser/parser_test.go": {
+			"package parser_test",
+			"",
+			"import (",
+			"\t\"testing\"",
+			"",
+			"\t\"example.com/app/parser\"",
+			")",
+			"",
+			"func TestParse(t *testing.T) {",
+			"\ttokens, err := parser.Parse(\"a 1 -\")",
+			"\tif err != nil || len(tokens) != 2 || tokens[1].Kind != parser.Number {",
+			"\t\tt.Fatalf(\"unexpected %v %v\", tokens, err)",
+			"\t}",
+			"}",
+			"",
+		},
+		"util/util.go": {
+			"package util",
+			"",
+			"// Fields splits s at spaces",
+			"func Fields(s string) []string {",
+			"\tvar fields []string",
+			"\tstart := 0",
+			"\tfor i := 0; i <= len(s); i++ {",
+			"\t\tif i == len(s) || s[i] == ' ' {",
+			"\t\t\tif i > start {",
+			"\t\t\t\tfields = append(fields, s[start:i])",
+			"\t\t\t}",
+			"\t\t\tstart = i + 1",
+			"\t\t}",
+			"\t}",
+			"\treturn fields",
+			"}",
+			"",
+			"func IsDigit(b byte) bool { return valid(b) && '0' <= b && b <= '9' }",
+			"",
+			"func valid(b byte) bool { return b != 0 }",
+			"",
+		},
+	})
+
+	run := func(t testing.TB, repro *Repro, args ...string) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module repro\n\ngo 1.22\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, repro.FileName), []byte(repro.Code), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("go", append(args, repro.FileName)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Expected the reproduction to run, got: %v\n%s\n%s", err, output, repro.Code)
+		}
+	}
+
+	t.Run("test", func(t *testing.T) {
+		t.Parallel()
+		repro, err := ExtractRepro(context.Background(), filepath.Join(workspace, "parser", "parser_test.go"), ReproOptions{Test: "TestParse"})
+		if err != nil {
+			t.Fatalf("Failed to extract the reproduction: %v", err)
+		}
+		expected := "Copied 13 declarations: parser.TestParse, parser.ErrEmpty, parser.Kind, parser.Word, parser.Number, parser.Token, parser.Token.String, parser.Parse, parser.valid, parser.kindOf, util.Fields, util.IsDigit, util.valid\n"
+		if !strings.HasPrefix(repro.String(), expected) {
+			t.Errorf("Expected the result to start with %q, got:\n%s", expected, repro)
+		}
+		for _, expected := range []string{
+			"// Minimal reproduction of TestParse of example.com/app/parser, extracted with the declarations it needs.\n// Run it with: go test repro_test.go\npackage repro\n",
+			"import (\n\t\"errors\"\n\t\"testing\"\n)\n",
+			"\ttokens, err := Parse(\"a 1 -\")\n\tif err != nil || len(tokens) != 2 || tokens[1].Kind != Number {",
+			"\tfor _, field := range Fields(input) {",
+			"\tif IsDigit(field[0]) {",
+			"func IsDigit(b byte) bool { return utilValid(b) && '0' <= b && b <= '9' }",
+			"func utilValid(b byte) bool { return b != 0 }",
+			"const (\n\tWord Kind = iota\n\tNumber\n)",
+		} {
+			if !strings.Contains(repro.Code, expected) {
+				t.Errorf("Expected the reproduction to contain %q, got:\n%s", expected, repro.Code)
+			}
+		}
+		if strings.Contains(repro.Code, "Unrelated") || strings.Contains(repro.Code, "unused") {
+			t.Errorf("Expected unneeded declarations to be left out, got:\n%s", repro.Code)
+		}
+		run(t, repro, "test")
+	})
+
+	t.Run("line", func(t *testing.T) {
+		t.Parallel()
+		repro, err := ExtractRepro(context.Background(), filepath.Join(workspace, "parser", "parser.go"), ReproOptions{Line: 46})
+		if err != nil {
+			t.Fatalf("Failed to extract the reproduction: %v", err)
+		}
+		expected := "func main() {\n\t// The arguments are zero values, set them to those of the failing call\n\tvar field string\n\tkindOf(field)\n}\n"
+		if repro.FileName != "repro.go" || !strings.HasSuffix(repro.Code, expected) {
+			t.Errorf("Expected a main function calling kindOf, got:\n%s", repro.Code)
+		}
+		if strings.Contains(repro.Code, "Parse") {
+			t.Errorf("Expected only the declarations kindOf needs, got:\n%s", repro.Code)
+		}
+		run(t, repro, "vet")
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(workspace, "parser", "parser.go")
+		for _, test := range []struct {
+			options  ReproOptions
+			expected string
+		}{
+			{ReproOptions{}, "either a test or a line is required"},
+			{ReproOptions{Test: "TestMissing"}, "no test, benchmark, fuzz test or example 'TestMissing'"},
+			{ReproOptions{Line: 12}, "line 12 of " + file + " is not in a function"},
+		} {
+			if _, err := ExtractRepro(context.Background(), file, test.options); err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected %q for %+v, got: %v", test.expected, test.options, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,375 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	reviewFunctionToolName        = "review_function"
+	reviewFunctionToolDescription = `Reviews a single Go function before editing it, reporting:
+- unused_parameter: parameters never used in the body, fixed by renaming them to _
+- ignored_result: calls whose results are discarded, fixed by returning a discarded error when the function itself returns an error
+- unreachable: statements after a return, panic, break, continue or goto, fixed by removing them
+
+Pass apply=true to apply the fixes, and output=patch to preview them as a unified diff. Methods are named Type.Method.`
+)
+
+func AddReviewFunctionTool(mcpServer *server.MCPServer) {
+	handleReviewFunction := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		symbol, err := request.RequireString("symbol")
+		if err != nil {
+			return nil, err
+		}
+		apply := request.GetBool("apply", false)
+
+		review, err := ReviewFunction(ctx, filePath, symbol, apply)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error reviewing function: %v", err)), nil
+		}
+		return mcp.NewToolResultText(review.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		reviewFunctionToolName,
+		mcp.WithDescription(reviewFunctionToolDescription),
+		editingToolAnnotation(true, true),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the function"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol",
+			mcp.Description("Name of the function, or Type.Method for a method"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("apply",
+			mcp.Description("Whether to apply the fixes of the findings"),
+			mcp.DefaultBool(false),
+		),
+	), handleReviewFunction)
+}
+
+// FunctionReview lists the findings of reviewing a function
+type FunctionReview struct {
+	Function string          `json:"function"`
+	File     string          `json:"file"`
+	Findings []ReviewFinding `json:"findings"`
+	// Applied is the number of fixes written to the file
+	Applied int `json:"applied,omitempty"`
+}
+
+// ReviewFinding is a problem found in a function
+type ReviewFinding struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Kind is unused_parameter, ignored_result or unreachable
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// Fix describes the edit fixing the finding, empty when there is none
+	Fix  string `json:"fix,omitempty"`
+	edit textEdit
+}
+
+// ignoredResultExceptions are the functions and methods whose results are conventionally
+// discarded, as errcheck excludes them
+var ignoredResultExceptions = []string{
+	"fmt.Print",
+	"fmt.Fprint",
+	"(*bytes.Buffer).Write",
+	"(*strings.Builder).Write",
+}
+
+// ReviewFunction type checks the package of filePath and reports the unused parameters,
+// ignored call results and unreachable code of the function or method named symbol, as Name
+// or Type.Method. The fixes of the findings are written to the file when apply is set.
+func ReviewFunction(ctx context.Context, filePath string, symbol string, apply bool) (*FunctionReview, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	pkg, file, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.Errors) > 0 {
+		return nil, classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+	fn := findFuncDecl(file, symbol)
+	if fn == nil {
+		return nil, classifyErrorf(ErrSymbolNotFound, "no function or method '%s' found in %s", symbol, filePath)
+	}
+	target, err := parseMoveFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info := pkg.TypesInfo
+	review := &FunctionReview{Function: symbol, File: filePath}
+	offset := func(pos token.Pos) int { return pkg.Fset.Position(pos).Offset }
+	add := func(node ast.Node, kind string, message string, fix string, edit textEdit) {
+		position := pkg.Fset.Position(node.Pos())
+		review.Findings = append(review.Findings, ReviewFinding{
+			Line:    position.Line,
+			Column:  position.Column,
+			Kind:    kind,
+			Message: message,
+			Fix:     fix,
+			edit:    edit,
+		})
+	}
+
+	used := make(map[types.Object]bool)
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			used[info.Uses[ident]] = true
+		}
+		return true
+	})
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			if name.Name != "_" && !used[info.Defs[name]] {
+				add(name, "unused_parameter", fmt.Sprintf("parameter %s is never used", name.Name), "rename it to _",
+					textEdit{start: offset(name.Pos()), end: offset(name.End()), replacement: "_"})
+			}
+		}
+	}
+
+	qualifier := func(other *types.Package) string {
+		if other == pkg.Types {
+			return ""
+		}
+		name, _ := target.qualifierOf(other.Path(), other.Name())
+		return name
+	}
+	// Returned errors are only added in the function itself, function literals have their own results
+	signature := info.Defs[fn.Name].Type().(*types.Signature)
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ExprStmt:
+			call, ok := node.X.(*ast.CallExpr)
+			if !ok {
+				break
+			}
+			callee := calleeSignature(info, call)
+			if callee == nil || callee.Results().Len() == 0 || ignoredResultException(info, call) {
+				break
+			}
+			message := fmt.Sprintf("the results of %s are discarded", types.ExprString(call.Fun))
+			results := callee.Results()
+			if !types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type()) {
+				add(node, "ignored_result", message, "", textEdit{})
+				break
+			}
+			message = fmt.Sprintf("the error of %s is discarded", types.ExprString(call.Fun))
+			own := signature.Results()
+			if own.Len() == 0 || !types.Identical(own.At(own.Len()-1).Type(), types.Universe.Lookup("error").Type()) || insideFuncLit(fn, node) {
+				add(node, "ignored_result", message, "", textEdit{})
+				break
+			}
+			var returned []string
+			for i := 0; i < own.Len()-1; i++ {
+				returned = append(returned, zeroValue(own.At(i).Type(), qualifier))
+			}
+			lhs := strings.Repeat("_, ", results.Len()-1) + "err"
+			replacement := fmt.Sprintf(
+				"if %s := %s; err != nil {\nreturn %s\n}",
+				lhs,
+				target.original[offset(call.Pos()):offset(call.End())],
+				strings.Join(append(returned, "err"), ", "),
+			)
+			add(node, "ignored_result", message, "return the error",
+				textEdit{start: offset(node.Pos()), end: offset(node.End()), replacement: replacement})
+		case *ast.BlockStmt:
+			unreachableStatements(info, node.List, add, offset)
+		case *ast.CaseClause:
+			unreachableStatements(info, node.Body, add, offset)
+		case *ast.CommClause:
+			unreachableStatements(info, node.Body, add, offset)
+		}
+		return true
+	})
+
+	sort.SliceStable(review.Findings, func(i, j int) bool {
+		if review.Findings[i].Line != review.Findings[j].Line {
+			return review.Findings[i].Line < review.Findings[j].Line
+		}
+		return review.Findings[i].Column < review.Findings[j].Column
+	})
+	if !apply {
+		return review, nil
+	}
+	// Fixes overlapping an earlier one, e.g. inside removed unreachable code, are skipped
+	var fixes []textEdit
+	for _, finding := range review.Findings {
+		if finding.Fix != "" {
+			fixes = append(fixes, finding.edit)
+		}
+	}
+	sort.SliceStable(fixes, func(i, j int) bool { return fixes[i].start < fixes[j].start })
+	end := -1
+	for _, fix := range fixes {
+		if fix.start >= end {
+			target.edits = append(target.edits, fix)
+			end = fix.end
+			review.Applied++
+		}
+	}
+	if review.Applied > 0 {
+		if err := target.write(); err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
+}
+
+// calleeSignature returns the signature of the called function, nil for conversions and builtins
+func calleeSignature(info *types.Info, call *ast.CallExpr) *types.Signature {
+	fun, ok := info.Types[call.Fun]
+	if !ok || fun.IsType() || fun.IsBuiltin() {
+		return nil
+	}
+	signature, _ := fun.Type.Underlying().(*types.Signature)
+	return signature
+}
+
+// ignoredResultException reports whether the call is of a function whose results are
+// conventionally discarded
+func ignoredResultException(info *types.Info, call *ast.CallExpr) bool {
+	fn := calledFunc(info, call)
+	if fn == nil {
+		return false
+	}
+	for _, exception := range ignoredResultExceptions {
+		if strings.HasPrefix(fn.FullName(), exception) {
+			return true
+		}
+	}
+	return false
+}
+
+// insideFuncLit reports whether the node is in a function literal of the function
+func insideFuncLit(fn *ast.FuncDecl, node ast.Node) bool {
+	inside := false
+	ast.Inspect(fn.Body, func(current ast.Node) bool {
+		if literal, ok := current.(*ast.FuncLit); ok && literal.Pos() <= node.Pos() && node.End() <= literal.End() {
+			inside = true
+		}
+		return !inside
+	})
+	return inside
+}
+
+// unreachableStatements reports the statements of a list following a statement that never
+// continues to the next one. Labeled statements may be jumped to, so they end the report.
+func unreachableStatements(
+	info *types.Info,
+	list []ast.Stmt,
+	add func(ast.Node, string, string, string, textEdit),
+	offset func(token.Pos) int,
+) {
+	for i, stmt := range list[:max(len(list)-1, 0)] {
+		if !terminates(info, stmt) {
+			continue
+		}
+		last := i + 1
+		for last < len(list) {
+			if _, labeled := list[last].(*ast.LabeledStmt); labeled {
+				break
+			}
+			last++
+		}
+		if last == i+1 {
+			return
+		}
+		unreachable := list[i+1 : last]
+		message := "the statement is unreachable"
+		if len(unreachable) > 1 {
+			message = fmt.Sprintf("%d statements are unreachable", len(unreachable))
+		}
+		// The removal starts at the end of the terminating statement to drop the lines too
+		add(unreachable[0], "unreachable", message, "remove them", textEdit{
+			start: offset(stmt.End()),
+			end:   offset(unreachable[len(unreachable)-1].End()),
+		})
+		return
+	}
+}
+
+// terminates reports whether control never flows past the statement to the next one
+func terminates(info *types.Info, stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return stmt.Tok != token.FALLTHROUGH
+	case *ast.ExprStmt:
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		builtin, ok := info.Uses[ident].(*types.Builtin)
+		return ok && builtin.Name() == "panic"
+	}
+	return false
+}
+
+// zeroValue returns the expression of the zero value of the type
+func zeroValue(t types.Type, qualifier types.Qualifier) string {
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case underlying.Info()&types.IsBoolean != 0:
+			return "false"
+		case underlying.Info()&types.IsString != 0:
+			return `""`
+		case underlying.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Struct, *types.Array:
+		return types.TypeString(t, qualifier) + "{}"
+	case *types.Interface:
+		if _, isParam := t.(*types.TypeParam); isParam {
+			return "*new(" + types.TypeString(t, qualifier) + ")"
+		}
+	}
+	return "nil"
+}
+
+// String formats the review as human and model readable text
+func (review *FunctionReview) String() string {
+	if len(review.Findings) == 0 {
+		return fmt.Sprintf("No findings in %s", review.Function)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Findings in %s (%s):\n", review.Function, review.File)
+	for _, finding := range review.Findings {
+		fmt.Fprintf(&b, "  %d:%d %s: %s", finding.Line, finding.Column, finding.Kind, finding.Message)
+		if finding.Fix != "" {
+			fmt.Fprintf(&b, " (fix: %s)", finding.Fix)
+		}
+		b.WriteString("\n")
+	}
+	if review.Applied > 0 {
+		fmt.Fprintf(&b, "\nApplied %d fixes\n", review.Applied)
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestReviewFunction(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a function discarding errors and results
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",         // 1
+				"",                     // 2
+				"import (",             // 3
+				"\t\"fmt\"",            // 4
+				"\t\"os\"",             // 5
+				"\t\"time\"",           // 6
+				")",                    // 7
+				"",                     // 8
+				"type Config struct{}", // 9
+				"",                     // 10
+				"func load(path string) (int, error) { return 0, nil }", // 11
+				"", // 12
+				"func Open(path string, timeout time.Duration) (Config, string, error) {", // 13
+				"\tload(path)",                   // 14
+				"\tos.Remove(path)",              // 15
+				"\tfmt.Println(path)",            // 16
+				"\tif path == \"\" {",            // 17
+				"\t\treturn Config{}, \"\", nil", // 18
+				"\t\tpanic(\"unreachable\")",     // 19
+				"\t}",                            // 20
+				"\tfunc() { os.Remove(path) }()", // 21
+				"\treturn Config{}, path, nil",   // 22
+				"}",                              // 23
+				"",                               // 24
+			},
+		})
+	}
+
+	t.Run("findings", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		review, err := ReviewFunction(context.Background(), file, "Open", false)
+		if err != nil {
+			t.Fatalf("Failed to review function: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Findings in Open (" + file + "):",
+			"  13:24 unused_parameter: parameter timeout is never used (fix: rename it to _)",
+			"  14:2 ignored_result: the error of load is discarded (fix: return the error)",
+			"  15:2 ignored_result: the error of os.Remove is discarded (fix: return the error)",
+			"  19:3 unreachable: the statement is unreachable (fix: remove them)",
+			"  21:11 ignored_result: the error of os.Remove is discarded",
+			"",
+		}, "\n")
+		if got := review.String(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("apply fixes", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		review, err := ReviewFunction(context.Background(), file, "Open", true)
+		if err != nil {
+			t.Fatalf("Failed to review function: %v", err)
+		}
+		if review.Applied != 4 {
+			t.Errorf("Expected 4 applied fixes, got %d", review.Applied)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := strings.Join([]string{
+			"func Open(path string, _ time.Duration) (Config, string, error) {",
+			"\tif _, err := load(path); err != nil {",
+			"\t\treturn Config{}, \"\", err",
+			"\t}",
+			"\tif err := os.Remove(path); err != nil {",
+			"\t\treturn Config{}, \"\", err",
+			"\t}",
+			"\tfmt.Println(path)",
+			"\tif path == \"\" {",
+			"\t\treturn Config{}, \"\", nil",
+			"\t}",
+		}, "\n")
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected the fixes in:\n%s\ngot:\n%s", expected, content)
+		}
+	})
+
+	t.Run("review function tool", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      reviewFunctionToolName,
+				"arguments": map[string]any{"file_path": file, "symbol": "load"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "parameter path is never used") {
+			t.Errorf("Expected the unused parameter of load, got: %s", text)
+		}
+	})
+}
@@ -1,6 +1,18 @@
 package go_mcp_tools
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -16,6 +28,8 @@ type Transport string
 const (
 	StdioTransport Transport = "stdio"
 	HTTPTransport  Transport = "http"
+	GRPCTransport  Transport = "grpc"
+	JSONLTransport Transport = "jsonl"
 )
 
 // DefaultServerConfig returns a default server configuration
@@ -26,30 +40,538 @@ func DefaultServerConfig() *ServerConfig {
 	}
 }
 
-// NewMCPServer creates a new MCP server with the specified configuration
-func NewMCPServer(config *ServerConfig) *server.MCPServer {
-	if config == nil {
-		config = DefaultServerConfig()
+// Option configures the MCP server created by NewMCPServer
+type Option func(*serverOptions)
+
+// serverOptions holds the settings collected from the options passed to NewMCPServer
+type serverOptions struct {
+	config             *ServerConfig
+	tools              []server.ServerTool
+	disabledTools      map[string]bool
+	logger             *slog.Logger
+	workspaceAllowlist []string
+	timeout            time.Duration
+	recorder           io.Writer
+	toolCosts          map[string]ToolCost
+	quotas             *Quotas
+	shadow             *ShadowOptions
+	commit             *CommitOptions
+	worktrees          *WorktreeOptions
+	conflicts          *ConflictOptions
+	diagnostics        *DiagnosticsOptions
+	symbolResourcesDir string
+	metadataCacheDir   string
+	fileCacheLimits    *FileCacheLimits
+	goplsConcurrency   *int
+	sandboxPolicy      *SandboxPolicy
+	outputFormat       *OutputFormat
+	negotiation        *NegotiationOptions
+	mcpOptions         []server.ServerOption
+}
+
+// pathArgumentNames are the tool arguments checked against the workspace allowlist. Tests
+// fail when a tool declares a path argument missing from them or pathListArgumentNames.
+var pathArgumentNames = []string{"workspace_dir", "file_path", "path", "new_path", "destination_dir"}
+
+// pathListArgumentNames are the array tool arguments of paths checked against the workspace allowlist
+var pathListArgumentNames = []string{"paths", "call_sites", "importers"}
+
+// pathArgument is a path given to a tool call
+type pathArgument struct {
+	name  string
+	value string
+}
+
+// pathArguments returns the non-empty paths of the path and path list arguments
+func pathArguments(arguments map[string]any) []pathArgument {
+	var paths []pathArgument
+	for _, name := range pathArgumentNames {
+		if value, ok := arguments[name].(string); ok && value != "" {
+			paths = append(paths, pathArgument{name: name, value: value})
+		}
+	}
+	for _, name := range pathListArgumentNames {
+		values, _ := arguments[name].([]any)
+		for _, value := range values {
+			if value, ok := value.(string); ok && value != "" {
+				paths = append(paths, pathArgument{name: name, value: value})
+			}
+		}
 	}
+	return paths
+}
 
-	mcpServer := server.NewMCPServer(
-		config.Name,
-		config.Version,
+// resolvePathArgument returns the absolute path of a path argument, stripping the
+// :line:symbol suffixes of the inspect path format. Relative paths are resolved like the
+// tools do, against the workspace_dir argument when the call has one and against the
+// working directory otherwise.
+func resolvePathArgument(argument pathArgument, arguments map[string]any) (string, error) {
+	path, _, _ := parseInspectPath(argument.value)
+	workspaceDir, _ := arguments["workspace_dir"].(string)
+	if !filepath.IsAbs(path) && workspaceDir != "" && argument.name != "workspace_dir" {
+		path = filepath.Join(workspaceDir, path)
+	}
+	return filepath.Abs(path)
+}
+
+// WithConfig sets the name and version reported by the server
+func WithConfig(config *ServerConfig) Option {
+	return func(o *serverOptions) {
+		if config != nil {
+			o.config = config
+		}
+	}
+}
+
+// WithTool registers an additional tool next to the built-in ones.
+// A tool with the same name as a built-in tool replaces it.
+func WithTool(tool mcp.Tool, handler server.ToolHandlerFunc) Option {
+	return func(o *serverOptions) {
+		o.tools = append(o.tools, server.ServerTool{Tool: tool, Handler: handler})
+	}
+}
+
+// WithoutTools removes tools (built-in or added with WithTool) by name
+func WithoutTools(names ...string) Option {
+	return func(o *serverOptions) {
+		for _, name := range names {
+			o.disabledTools[name] = true
+		}
+	}
+}
+
+// WithLogger logs every tool call with its duration and outcome
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *serverOptions) {
+		o.logger = logger
+	}
+}
+
+// WithWorkspaceAllowlist rejects tool calls with path arguments (workspace_dir, file_path,
+// path and the like) outside the given directories. Relative paths are resolved against
+// the workspace_dir argument of the call, or the working directory of the server without one.
+func WithWorkspaceAllowlist(dirs ...string) Option {
+	return func(o *serverOptions) {
+		o.workspaceAllowlist = append(o.workspaceAllowlist, dirs...)
+	}
+}
+
+// WithTimeout limits how long a tool call may take. Calls exceeding the timeout
+// return an error result to the client and their subprocesses are killed; other
+// work of the call is not interrupted.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *serverOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithServerOptions passes options through to the underlying mcp-go server
+func WithServerOptions(opts ...server.ServerOption) Option {
+	return func(o *serverOptions) {
+		o.mcpOptions = append(o.mcpOptions, opts...)
+	}
+}
+
+// NewMCPServer creates a new MCP server with all built-in tools and prompts, configured by opts
+func NewMCPServer(opts ...Option) *server.MCPServer {
+	options := &serverOptions{
+		config:        DefaultServerConfig(),
+		disabledTools: make(map[string]bool),
+		toolCosts:     make(map[string]ToolCost),
+	}
+	for name, cost := range builtinToolCosts {
+		options.toolCosts[name] = cost
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.fileCacheLimits != nil {
+		SetFileCacheLimits(*options.fileCacheLimits)
+	}
+	if options.goplsConcurrency != nil {
+		SetGoplsConcurrency(*options.goplsConcurrency)
+	}
+	if options.sandboxPolicy != nil {
+		policy := *options.sandboxPolicy
+		if len(policy.Roots) == 0 {
+			policy.Roots = options.workspaceAllowlist
+		}
+		SetSandboxPolicy(policy)
+	}
+
+	mcpOptions := []server.ServerOption{
 		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(true),
+	}
+	// The tool filters are run by schemas, which applies them to the called tool alone to
+	// validate the arguments the filters add
+	schemas := &toolSchemas{
+		filters: []server.ToolFilterFunc{
+			costHintFilter(options.toolCosts),
+			outputArgumentFilter(options.toolCosts),
+		},
+	}
+	mcpOptions = append(mcpOptions, server.WithToolFilter(schemas.filter))
+	// Middlewares run in the order they are added, the first one being the outermost
+	if options.recorder != nil {
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(recordingMiddleware(options.recorder)),
+		)
+	}
+	mcpOptions = append(
+		mcpOptions,
+		server.WithToolHandlerMiddleware(argumentValidationMiddleware(schemas)),
 	)
-	AddInspectTool(mcpServer)
+	if options.outputFormat != nil {
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(outputFormatMiddleware(*options.outputFormat, options.toolCosts)),
+		)
+	}
+	if len(options.workspaceAllowlist) > 0 {
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(workspaceAllowlistMiddleware(options.workspaceAllowlist)),
+		)
+	}
+	var clients *negotiatedClients
+	if options.negotiation != nil {
+		// Calls of disabled tools are rejected before they count against the quotas
+		clients = newNegotiatedClients()
+		schemas.filters = append(schemas.filters, negotiationToolFilter(*options.negotiation, clients))
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(negotiationMiddleware(*options.negotiation, clients, options.toolCosts)),
+		)
+	}
+	var usages *quotaUsages
+	if options.quotas != nil {
+		usages = newQuotaUsages()
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(quotaMiddleware(*options.quotas, options.toolCosts, usages)),
+		)
+	}
+	if options.logger != nil {
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(loggingMiddleware(options.logger)),
+		)
+	}
+	if options.timeout > 0 {
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(timeoutMiddleware(options.timeout)),
+		)
+	}
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		// Tool calls of the ending session still running would otherwise leave their
+		// test runs and builds behind
+		KillSessionSubprocesses(session.SessionID())
+	})
+	if clients != nil {
+		clients.addHooks(hooks, *options.negotiation)
+	}
+	if usages != nil {
+		hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+			usages.removeSession(session.SessionID())
+		})
+	}
+	var worktrees *worktreeManager
+	if options.worktrees != nil {
+		worktrees = newWorktreeManager(*options.worktrees)
+		hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+			// The context of the ending session may already be cancelled
+			worktrees.removeSession(context.Background(), session.SessionID())
+		})
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(worktreeMiddleware(worktrees, options.toolCosts)),
+		)
+	}
+	if options.conflicts != nil {
+		versions := newFileVersions()
+		hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+			versions.removeSession(session.SessionID())
+		})
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(conflictMiddleware(versions, *options.conflicts, options.toolCosts)),
+		)
+	}
+	mcpOptions = append(mcpOptions, server.WithHooks(hooks))
+	tracker := newChangeTracker()
+	if options.commit != nil {
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(changeTrackingMiddleware(tracker, options.toolCosts)),
+		)
+	}
+	// Patch output runs before the shadow copy, so patches are validated as well
+	var shadowOptions ShadowOptions
+	if options.shadow != nil {
+		shadowOptions = *options.shadow
+	}
+	mcpOptions = append(
+		mcpOptions,
+		server.WithToolHandlerMiddleware(patchMiddleware(options.toolCosts, shadowOptions)),
+	)
+	if options.shadow != nil {
+		mcpOptions = append(
+			mcpOptions,
+			server.WithToolHandlerMiddleware(shadowMiddleware(*options.shadow, options.toolCosts)),
+		)
+	}
+	if options.diagnostics != nil || options.symbolResourcesDir != "" {
+		// Only the diagnostics resource can be subscribed to
+		mcpOptions = append(mcpOptions, server.WithResourceCapabilities(options.diagnostics != nil, false))
+	}
+	mcpOptions = append(mcpOptions, options.mcpOptions...)
+
+	mcpServer := server.NewMCPServer(
+		options.config.Name,
+		options.config.Version,
+		mcpOptions...,
+	)
+	addInspectTool(mcpServer, options.metadataCacheDir)
+	addBatchInspectTool(mcpServer, options.metadataCacheDir)
+	AddBodyTool(mcpServer)
+	AddContextAtTool(mcpServer)
+	AddTypeOfTool(mcpServer)
+	AddStructLayoutTool(mcpServer)
+	AddJSONSchemaTool(mcpServer)
+	AddCompletionTool(mcpServer)
+	AddSignatureHelpTool(mcpServer)
+	AddInlayHintsTool(mcpServer)
+	AddDocTool(mcpServer)
+	AddAPISurfaceTool(mcpServer)
+	AddAPIStabilityTool(mcpServer)
+	AddAPIDiffTool(mcpServer)
+	AddReleaseNotesTool(mcpServer)
+	AddProtobufTool(mcpServer)
+	AddInitOrderTool(mcpServer)
+	AddAnalyzeTool(mcpServer)
+	AddCodefixTool(mcpServer)
+	AddDeadcodeTool(mcpServer)
+	AddUnusedExportedTool(mcpServer)
+	AddUntestedExportedTool(mcpServer)
 	AddRenameTool(mcpServer)
+	AddRenamePackageTool(mcpServer)
+	AddMoveSymbolTool(mcpServer)
+	AddInlineTool(mcpServer)
+	AddGenerateStubsTool(mcpServer)
+	AddGenerateStringerTool(mcpServer)
+	AddGenerateConstructorTool(mcpServer)
+	AddExtractInterfaceTool(mcpServer)
+	AddReviewFunctionTool(mcpServer)
+	AddChangeImpactTool(mcpServer)
+	AddAffectedTestsTool(mcpServer)
+	AddMinimalReproTool(mcpServer)
+	AddSortTool(mcpServer)
+	AddModTidyTool(mcpServer)
+	AddHotspotsTool(mcpServer)
+	AddMetricsTool(mcpServer)
+	AddOverviewTool(mcpServer)
+	AddArchitectureTool(mcpServer)
+	AddPackageGraphTool(mcpServer)
+	AddImportRulesTool(mcpServer)
+	AddDIGraphTool(mcpServer)
+	AddORMMappingsTool(mcpServer)
+	AddKubernetesTool(mcpServer)
+	AddTerraformTool(mcpServer)
+	AddSharedExportsTool(mcpServer)
+	AddWasmTool(mcpServer)
+	AddDuplicatesTool(mcpServer)
+	AddDepsTool(mcpServer)
+	AddImportCostTool(mcpServer)
+	AddVulncheckTool(mcpServer)
+	AddListTestsTool(mcpServer)
+	AddBenchmarkTool(mcpServer)
+	AddRaceTool(mcpServer)
+	AddRacyGlobalsTool(mcpServer)
+	AddStressTool(mcpServer)
+	AddTestConventionsTool(mcpServer)
+	AddConventionsTool(mcpServer)
+	if options.commit != nil {
+		addCommitTool(mcpServer, tracker, *options.commit)
+	}
+	if worktrees != nil {
+		addWorktreeTools(mcpServer, worktrees)
+	}
+	AddPrompts(mcpServer)
+	if options.diagnostics != nil {
+		addDiagnosticsResource(mcpServer, hooks, *options.diagnostics)
+	}
+	if options.symbolResourcesDir != "" {
+		addSymbolResources(mcpServer, options.symbolResourcesDir, options.metadataCacheDir)
+	}
+	mcpServer.AddTools(options.tools...)
+
+	disabled := make([]string, 0, len(options.disabledTools))
+	for name := range options.disabledTools {
+		disabled = append(disabled, name)
+	}
+	if len(disabled) > 0 {
+		mcpServer.DeleteTools(disabled...)
+	}
+	// Calls of tools whose schemas fail to list are passed on unvalidated
+	schemas.mcpServer = mcpServer
 	return mcpServer
 }
 
+// workspaceAllowlistMiddleware rejects tool calls referring to paths outside the allowed directories
+func workspaceAllowlistMiddleware(allowlist []string) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			arguments := request.GetArguments()
+			for _, argument := range pathArguments(arguments) {
+				path, err := resolvePathArgument(argument, arguments)
+				if err != nil {
+					return toolErrorResult(fmt.Sprintf("Error: invalid %s: %v", argument.name, err)), nil
+				}
+
+				if err := CheckWorkspacePath(path, allowlist...); err != nil {
+					return toolErrorResult(fmt.Sprintf(
+						"Error: %s %v, this server may only access them",
+						argument.name,
+						err,
+					)), nil
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// loggingMiddleware logs tool calls with their duration and outcome
+func loggingMiddleware(logger *slog.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+			attrs := []any{
+				slog.String("tool", request.Params.Name),
+				slog.Duration("duration", time.Since(start)),
+			}
+			switch {
+			case err != nil:
+				logger.ErrorContext(ctx, "tool call failed", append(attrs, slog.Any("error", err))...)
+			case result != nil && result.IsError:
+				logger.WarnContext(ctx, "tool call returned an error result", attrs...)
+			default:
+				logger.InfoContext(ctx, "tool call completed", attrs...)
+			}
+			return result, err
+		}
+	}
+}
+
+// timeoutMiddleware returns an error result when a tool call exceeds the timeout
+func timeoutMiddleware(timeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			type outcome struct {
+				result *mcp.CallToolResult
+				err    error
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				result, err := next(ctx, request)
+				done <- outcome{result, err}
+			}()
+
+			select {
+			case o := <-done:
+				return o.result, o.err
+			case <-ctx.Done():
+				return toolErrorResult(fmt.Sprintf(
+					"Error: %s tool call timed out after %s",
+					request.Params.Name,
+					timeout,
+				)), nil
+			}
+		}
+	}
+}
+
+// readOnlyToolAnnotation marks a tool that only reads the workspace, so clients can call it
+// without asking for confirmation
+func readOnlyToolAnnotation() mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(false),
+	})
+}
+
+// editingToolAnnotation marks a tool that writes files of the workspace. Destructive tools
+// rewrite or remove existing code instead of only adding to it, and calling idempotent tools
+// again with the same arguments has no further effect.
+func editingToolAnnotation(destructive bool, idempotent bool) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(destructive),
+		IdempotentHint:  mcp.ToBoolPtr(idempotent),
+		OpenWorldHint:   mcp.ToBoolPtr(false),
+	})
+}
+
+// toolErrorResult wraps an error message in a tool result reported as failed
+func toolErrorResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+		IsError: true,
+	}
+}
+
 // ServeStdio starts the MCP server on stdio transport
 func ServeStdio(mcpServer *server.MCPServer) error {
-	return server.ServeStdio(mcpServer)
+	defer KillSubprocesses()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	return serveStdio(ctx, mcpServer, os.Stdin, os.Stdout)
+}
+
+// ServeJSONLStdio serves the jsonl scripting transport on stdin and stdout
+func ServeJSONLStdio(mcpServer *server.MCPServer) error {
+	defer KillSubprocesses()
+	return ServeJSONL(mcpServer, os.Stdin, os.Stdout)
 }
 
 // ServeHTTP starts the MCP server on HTTP transport at the specified address
 func ServeHTTP(mcpServer *server.MCPServer, host string, port string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", resourceSubscriptionHandler(mcpServer, server.NewStreamableHTTPServer(mcpServer)))
+	httpServer := &http.Server{
+		Addr:    host + ":" + port,
+		Handler: mux,
+	}
+	defer KillSubprocesses()
+	return httpServer.ListenAndServe()
+}
+
+// ServeGRPC starts the gRPC service defined in proto/tools.proto on plaintext HTTP/2 at the
+// specified address, calling the tools of the MCP server
+func ServeGRPC(mcpServer *server.MCPServer, host string, port string) error {
 	addr := host + ":" + port
-	httpServer := server.NewStreamableHTTPServer(mcpServer)
-	return httpServer.Start(addr)
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	httpServer := &http.Server{
+		Addr:      addr,
+		Handler:   NewGRPCServer(mcpServer),
+		Protocols: protocols,
+	}
+	defer KillSubprocesses()
+	return httpServer.ListenAndServe()
 }
@@ -0,0 +1,276 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestNewMCPServer(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to send a JSON-RPC message and return the encoded response
+	handle := func(t testing.TB, mcpServer *server.MCPServer, message map[string]any) string {
+		message["jsonrpc"] = mcp.JSONRPC_VERSION
+		message["id"] = 1
+		encoded, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response, err := json.Marshal(mcpServer.HandleMessage(context.Background(), encoded))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(response)
+	}
+
+	callTool := func(t testing.TB, mcpServer *server.MCPServer, name string, arguments map[string]any) string {
+		return handle(t, mcpServer, map[string]any{
+			"method": string(mcp.MethodToolsCall),
+			"params": map[string]any{"name": name, "arguments": arguments},
+		})
+	}
+
+	echoTool := mcp.NewTool("echo", mcp.WithString("file_path"))
+	echoHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("echoed"), nil
+	}
+
+	t.Run("default tools are registered", func(t *testing.T) {
+		t.Parallel()
+		tools := handle(t, NewMCPServer(), map[string]any{"method": string(mcp.MethodToolsList)})
+		for _, name := range []string{inspectToolName, batchInspectToolName, bodyToolName, contextAtToolName, typeOfToolName, structLayoutToolName, jsonSchemaToolName, completionToolName, signatureHelpToolName, inlayHintsToolName, docToolName, apiSurfaceToolName, apiStabilityToolName, apiDiffToolName, releaseNotesToolName, protobufToolName, initOrderToolName, analyzeToolName, codefixToolName, deadcodeToolName, unusedExportedToolName, untestedExportedToolName, renameToolName, renamePackageToolName, moveSymbolToolName, inlineToolName, generateStubsToolName, generateStringerToolName, generateConstructorToolName, extractInterfaceToolName, reviewFunctionToolName, changeImpactToolName, affectedTestsToolName, minimalReproToolName, sortToolName, modTidyToolName, hotspotsToolName, metricsToolName, overviewToolName, architectureToolName, packageGraphToolName, importRulesToolName, diGraphToolName, ormMappingsToolName, kubernetesToolName, terraformToolName, sharedExportsToolName, wasmToolName, duplicatesToolName, depsToolName, importCostToolName, vulncheckToolName, listTestsToolName, benchmarkToolName, raceToolName, racyGlobalsToolName, stressToolName, testConventionsToolName, conventionsToolName} {
+			if !strings.Contains(tools, `"name":"`+name+`"`) {
+				t.Errorf("Expected tool %s to be listed, got: %s", name, tools)
+			}
+		}
+	})
+
+	t.Run("default tools are annotated", func(t *testing.T) {
+		t.Parallel()
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsList),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		for _, tool := range response.Result.(mcp.ListToolsResult).Tools {
+			annotations := tool.Annotations
+			if annotations.ReadOnlyHint == nil || annotations.DestructiveHint == nil || annotations.IdempotentHint == nil {
+				t.Errorf("Expected tool %s to be annotated, got %+v", tool.Name, annotations)
+				continue
+			}
+			if mutating := builtinToolCosts[tool.Name].Mutating; *annotations.ReadOnlyHint == mutating {
+				t.Errorf("Expected tool %s to have readOnlyHint %v", tool.Name, !mutating)
+			}
+			if *annotations.ReadOnlyHint && *annotations.DestructiveHint {
+				t.Errorf("Expected read-only tool %s not to be destructive", tool.Name)
+			}
+			switch tool.Name {
+			case docToolName, apiDiffToolName, depsToolName, importCostToolName, modTidyToolName, vulncheckToolName:
+				if annotations.OpenWorldHint == nil || !*annotations.OpenWorldHint {
+					t.Errorf("Expected tool %s reaching the network to have openWorldHint", tool.Name)
+				}
+			case raceToolName, stressToolName, benchmarkToolName:
+				if *annotations.IdempotentHint {
+					t.Errorf("Expected tool %s with varying results not to be idempotent", tool.Name)
+				}
+			}
+		}
+	})
+
+	t.Run("tools can be added and removed", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(
+			WithTool(echoTool, echoHandler),
+			WithoutTools(renameToolName),
+		)
+		tools := handle(t, mcpServer, map[string]any{"method": string(mcp.MethodToolsList)})
+		if !strings.Contains(tools, `"name":"echo"`) {
+			t.Errorf("Expected custom tool to be listed, got: %s", tools)
+		}
+		if strings.Contains(tools, `"name":"rename"`) {
+			t.Errorf("Expected rename tool to be removed, got: %s", tools)
+		}
+	})
+
+	t.Run("config sets server info", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(WithConfig(&ServerConfig{Name: "custom", Version: "9.9.9"}))
+		response := handle(t, mcpServer, map[string]any{
+			"method": string(mcp.MethodInitialize),
+			"params": map[string]any{"protocolVersion": mcp.LATEST_PROTOCOL_VERSION},
+		})
+		if !strings.Contains(response, `"name":"custom"`) || !strings.Contains(response, `"version":"9.9.9"`) {
+			t.Errorf("Expected custom server info, got: %s", response)
+		}
+	})
+
+	t.Run("workspace allowlist rejects outside paths", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		mcpServer := NewMCPServer(
+			WithTool(echoTool, echoHandler),
+			WithWorkspaceAllowlist(workspace),
+		)
+
+		response := callTool(t, mcpServer, "echo", map[string]any{
+			"file_path": filepath.Join(workspace, "main.go"),
+		})
+		if !strings.Contains(response, "echoed") {
+			t.Errorf("Expected call inside workspace to succeed, got: %s", response)
+		}
+
+		response = callTool(t, mcpServer, "echo", map[string]any{
+			"file_path": filepath.Join(t.TempDir(), "main.go") + ":3:main",
+		})
+		if !strings.Contains(response, "is outside the workspaces") || !strings.Contains(response, `"isError":true`) {
+			t.Errorf("Expected call outside workspace to be rejected, got: %s", response)
+		}
+	})
+
+	t.Run("workspace allowlist resolves symlinks", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		outside := t.TempDir()
+		// The link stands in for a workspace reached through a symlink, like /tmp on macOS
+		link := filepath.Join(t.TempDir(), "link")
+		if err := os.Symlink(workspace, link); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(outside, filepath.Join(workspace, "escape")); err != nil {
+			t.Fatal(err)
+		}
+
+		for name, allowed := range map[string]string{"resolved": workspace, "symlink": link} {
+			mcpServer := NewMCPServer(
+				WithTool(echoTool, echoHandler),
+				WithWorkspaceAllowlist(allowed),
+			)
+			for _, path := range []string{filepath.Join(workspace, "main.go"), filepath.Join(link, "main.go"), filepath.Join(link, "new", "file.go")} {
+				response := callTool(t, mcpServer, "echo", map[string]any{"file_path": path})
+				if !strings.Contains(response, "echoed") {
+					t.Errorf("Expected %s inside the %s workspace to be allowed, got: %s", path, name, response)
+				}
+			}
+			// Symlinks inside the workspace cannot be used to reach files outside of it
+			response := callTool(t, mcpServer, "echo", map[string]any{"file_path": filepath.Join(workspace, "escape", "main.go")})
+			if !strings.Contains(response, "is outside the workspaces") {
+				t.Errorf("Expected the symlink out of the %s workspace to be rejected, got: %s", name, response)
+			}
+		}
+	})
+
+	t.Run("workspace allowlist resolves relative paths", func(t *testing.T) {
+		t.Parallel()
+		workingDir, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mcpServer := NewMCPServer(
+			WithTool(echoTool, echoHandler),
+			WithWorkspaceAllowlist(workingDir),
+		)
+
+		response := callTool(t, mcpServer, "echo", map[string]any{"file_path": "server.go"})
+		if !strings.Contains(response, "echoed") {
+			t.Errorf("Expected relative path inside the workspace to be allowed, got: %s", response)
+		}
+		response = callTool(t, mcpServer, "echo", map[string]any{"file_path": filepath.Join("..", "outside", "main.go")})
+		if !strings.Contains(response, "is outside the workspaces") {
+			t.Errorf("Expected relative path outside the workspace to be rejected, got: %s", response)
+		}
+
+		// Relative paths of calls with a workspace_dir are resolved against it
+		workspace := t.TempDir()
+		mcpServer = NewMCPServer(
+			WithTool(mcp.NewTool("echo", mcp.WithString("workspace_dir"), mcp.WithString("path")), echoHandler),
+			WithWorkspaceAllowlist(workspace),
+		)
+		response = callTool(t, mcpServer, "echo", map[string]any{"workspace_dir": workspace, "path": "main.go:3"})
+		if !strings.Contains(response, "echoed") {
+			t.Errorf("Expected path relative to the workspace_dir to be allowed, got: %s", response)
+		}
+		response = callTool(t, mcpServer, "echo", map[string]any{"workspace_dir": workspace, "path": filepath.Join("..", "main.go")})
+		if !strings.Contains(response, "is outside the workspaces") {
+			t.Errorf("Expected path relative to the workspace_dir leaving it to be rejected, got: %s", response)
+		}
+	})
+
+	t.Run("workspace allowlist covers the path arguments of all tools", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(
+			WithCommitTool(CommitOptions{AuthorName: "Agent", AuthorEmail: "agent@example.com"}),
+			WithWorktrees(WorktreeOptions{Dir: t.TempDir()}),
+		)
+		var listing struct {
+			Result mcp.ListToolsResult `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(handle(t, mcpServer, map[string]any{"method": string(mcp.MethodToolsList)})), &listing); err != nil {
+			t.Fatal(err)
+		}
+
+		isPathName := regexp.MustCompile(`(^|_)(path|dir)s?$`)
+		for _, tool := range listing.Result.Tools {
+			for name, property := range tool.InputSchema.Properties {
+				schema, _ := property.(map[string]any)
+				description, _ := schema["description"].(string)
+				if !isPathName.MatchString(name) && !strings.Contains(strings.ToLower(description), "absolute path") {
+					continue
+				}
+				names := pathArgumentNames
+				if schema["type"] == "array" {
+					names = pathListArgumentNames
+				}
+				if !slices.Contains(names, name) {
+					t.Errorf("Expected path argument %s of %s to be checked against the workspace allowlist", name, tool.Name)
+				}
+			}
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		slowHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			<-ctx.Done()
+			return mcp.NewToolResultText("finished"), nil
+		}
+		mcpServer := NewMCPServer(
+			WithTool(mcp.NewTool("slow"), slowHandler),
+			WithTimeout(10*time.Millisecond),
+		)
+
+		response := callTool(t, mcpServer, "slow", map[string]any{})
+		if !strings.Contains(response, "timed out after 10ms") {
+			t.Errorf("Expected timeout error, got: %s", response)
+		}
+	})
+
+	t.Run("logger", func(t *testing.T) {
+		t.Parallel()
+		var logs bytes.Buffer
+		mcpServer := NewMCPServer(
+			WithTool(echoTool, echoHandler),
+			WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		)
+
+		callTool(t, mcpServer, "echo", map[string]any{})
+		if !strings.Contains(logs.String(), "tool call completed") || !strings.Contains(logs.String(), "tool=echo") {
+			t.Errorf("Expected tool call to be logged, got: %s", logs.String())
+		}
+	})
+}
@@ -0,0 +1,419 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultShadowMaxFiles and DefaultShadowMaxBytes bound the size of the modules copied for
+// shadow copies unless set with ShadowOptions.MaxFiles and ShadowOptions.MaxBytes
+const (
+	DefaultShadowMaxFiles = 20000
+	DefaultShadowMaxBytes = 512 << 20
+)
+
+// ShadowOptions configures how mutating tools are isolated on a shadow copy of the module
+type ShadowOptions struct {
+	// SkipTests only validates that the module builds, without running its tests
+	SkipTests bool
+	// MaxFiles and MaxBytes refuse to copy modules with more files or bytes, vendor and
+	// testdata directories included. Zero means DefaultShadowMaxFiles and
+	// DefaultShadowMaxBytes, a negative value no limit.
+	MaxFiles int
+	MaxBytes int64
+}
+
+// limits returns the file and byte limits of the copies, zero meaning no limit
+func (options ShadowOptions) limits() (maxFiles int, maxBytes int64) {
+	maxFiles, maxBytes = options.MaxFiles, options.MaxBytes
+	if maxFiles == 0 {
+		maxFiles = DefaultShadowMaxFiles
+	}
+	if maxBytes == 0 {
+		maxBytes = DefaultShadowMaxBytes
+	}
+	return max(maxFiles, 0), max(maxBytes, 0)
+}
+
+// WithShadowCopy runs mutating tools on a temporary copy of the Go module they operate on.
+// Changes are only synced back when the copy still builds and its tests pass, otherwise
+// the tool call fails with the build or test output and the workspace is left untouched.
+// Modules with relative replace directives pointing outside the module cannot be validated.
+// Modules larger than the limits of the options or with symlinks pointing outside of them
+// are not copied, and calls with path arguments outside the module are refused.
+func WithShadowCopy(options ShadowOptions) Option {
+	return func(o *serverOptions) {
+		o.shadow = &options
+	}
+}
+
+// shadowMiddleware runs mutating tools on a shadow copy of the module
+func shadowMiddleware(options ShadowOptions, costs map[string]ToolCost) server.ToolHandlerMiddleware {
+	// Mutating calls are serialized so they never sync over each other
+	var mu sync.Mutex
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !costs[request.Params.Name].Mutating || repositoryToolNames[request.Params.Name] {
+				return next(ctx, request)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			return runOnShadowCopy(ctx, request, next, options)
+		}
+	}
+}
+
+// runOnShadowCopy copies the module, calls the tool on the copy, validates and syncs the changes back
+func runOnShadowCopy(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	next server.ToolHandlerFunc,
+	options ShadowOptions,
+) (*mcp.CallToolResult, error) {
+	request, moduleRoot, shadowRoot, err := newShadowCopy(request, options)
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("Error preparing shadow copy: %v", err)), nil
+	}
+	defer os.RemoveAll(shadowRoot)
+
+	result, err := next(ctx, request)
+	if err != nil || result == nil || result.IsError {
+		return rewriteResultPaths(result, shadowRoot, moduleRoot), err
+	}
+
+	changed, removed, err := diffModules(moduleRoot, shadowRoot)
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("Error comparing shadow copy: %v", err)), nil
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return rewriteResultPaths(result, shadowRoot, moduleRoot), nil
+	}
+
+	validation := [][]string{{"go", "build", "./..."}}
+	if !options.SkipTests {
+		validation = append(validation, []string{"go", "test", "./..."})
+	}
+	for _, args := range validation {
+		if output, err := commandOutput(ctx, shadowRoot, nil, args[0], args[1:]...); err != nil {
+			return toolErrorResult(fmt.Sprintf(
+				"Error: the changes of %s were discarded because `%s` failed on a shadow copy of the module (%v). No files were modified.\n\n%s\n\nChanges that were discarded: %s",
+				request.Params.Name,
+				strings.Join(args, " "),
+				err,
+				strings.ReplaceAll(strings.TrimSpace(string(output)), shadowRoot, moduleRoot),
+				strings.Join(append(changed, removed...), ", "),
+			)), nil
+		}
+	}
+
+	if err := syncModule(shadowRoot, moduleRoot, changed, removed); err != nil {
+		return toolErrorResult(fmt.Sprintf("Error syncing shadow copy back to %s: %v", moduleRoot, err)), nil
+	}
+
+	result = rewriteResultPaths(result, shadowRoot, moduleRoot)
+	checks := "go build ./..."
+	if !options.SkipTests {
+		checks += " and go test ./..."
+	}
+	result.Content = append(result.Content, mcp.TextContent{
+		Type: "text",
+		Text: fmt.Sprintf(
+			"Validated with %s on a shadow copy and synced %d changed and %d removed file(s) back.",
+			checks,
+			len(changed),
+			len(removed),
+		),
+	})
+	return result, nil
+}
+
+// newShadowCopy copies the module referred to by the path arguments of the request to a
+// temporary directory and returns the request with its path arguments pointing into the copy.
+// The caller must remove shadowRoot.
+func newShadowCopy(
+	request mcp.CallToolRequest,
+	options ShadowOptions,
+) (shadowRequest mcp.CallToolRequest, moduleRoot string, shadowRoot string, err error) {
+	arguments := request.GetArguments()
+	moduleRoot, err = shadowModuleRoot(arguments)
+	if err != nil {
+		return request, "", "", err
+	}
+	// The tool would change files outside the module directly instead of on the copy
+	for _, argument := range pathArguments(arguments) {
+		path, err := resolvePathArgument(argument, arguments)
+		if err != nil {
+			return request, "", "", fmt.Errorf("invalid %s: %w", argument.name, err)
+		}
+		if _, ok := workspaceRelPath(path, moduleRoot); !ok {
+			return request, "", "", fmt.Errorf(
+				"%s %s is outside the module %s, only the module is copied",
+				argument.name,
+				argument.value,
+				moduleRoot,
+			)
+		}
+	}
+
+	shadowRoot, err = os.MkdirTemp("", "go-mcp-tools-shadow-")
+	if err != nil {
+		return request, "", "", err
+	}
+	maxFiles, maxBytes := options.limits()
+	if err := copyModule(moduleRoot, shadowRoot, maxFiles, maxBytes); err != nil {
+		os.RemoveAll(shadowRoot)
+		return request, "", "", err
+	}
+
+	// Point all path arguments into the shadow copy
+	request.Params.Arguments = rewritePathArguments(arguments, moduleRoot, shadowRoot)
+	return request, moduleRoot, shadowRoot, nil
+}
+
+// rewritePathArguments returns a copy of the arguments with the path arguments inside
+// the from directory pointing to the same relative path inside the to directory
+func rewritePathArguments(arguments map[string]any, from string, to string) map[string]any {
+	rewritten := make(map[string]any, len(arguments))
+	for name, value := range arguments {
+		rewritten[name] = value
+	}
+	_, hasWorkspaceDir := arguments["workspace_dir"].(string)
+	rewrite := func(name string, value string) string {
+		if !filepath.IsAbs(value) {
+			// Relative paths are resolved against workspace_dir by the tools, so they follow
+			// it, and against the working directory without one
+			if hasWorkspaceDir && name != "workspace_dir" {
+				return value
+			}
+			absValue, err := filepath.Abs(value)
+			if err != nil {
+				return value
+			}
+			value = absValue
+		}
+		if rel, ok := workspaceRelPath(value, from); ok {
+			return filepath.Join(to, rel)
+		}
+		return value
+	}
+	for _, name := range pathArgumentNames {
+		if value, ok := rewritten[name].(string); ok && value != "" {
+			rewritten[name] = rewrite(name, value)
+		}
+	}
+	for _, name := range pathListArgumentNames {
+		values, ok := rewritten[name].([]any)
+		if !ok {
+			continue
+		}
+		rewrittenValues := make([]any, len(values))
+		for i, value := range values {
+			if text, ok := value.(string); ok && text != "" {
+				value = rewrite(name, text)
+			}
+			rewrittenValues[i] = value
+		}
+		rewritten[name] = rewrittenValues
+	}
+	return rewritten
+}
+
+// shadowModuleRoot finds the module containing the first absolute path argument
+func shadowModuleRoot(arguments map[string]any) (string, error) {
+	for _, name := range pathArgumentNames {
+		value, ok := arguments[name].(string)
+		if !ok || value == "" {
+			continue
+		}
+		path, _, _ := parseInspectPath(value)
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		if stat, err := os.Stat(absPath); err != nil {
+			continue
+		} else if !stat.IsDir() {
+			absPath = filepath.Dir(absPath)
+		}
+
+		for dir := absPath; ; dir = filepath.Dir(dir) {
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+				return dir, nil
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+		return "", fmt.Errorf("no go.mod found above %s, shadow copies require a Go module", absPath)
+	}
+	return "", fmt.Errorf("the tool call has no existing file_path, path or workspace_dir argument to locate the module")
+}
+
+// copyModule copies all files of the module except version control directories. Modules
+// with more files or bytes than the limits, zero meaning none, such as those of large vendor
+// or testdata directories, are refused before the limits are exceeded. Symlinks into the
+// module point into the copy, symlinks leading out of it are refused.
+func copyModule(src string, dst string, maxFiles int, maxBytes int64) error {
+	var files int
+	var size int64
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case entry.IsDir():
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			linked := link
+			if !filepath.IsAbs(linked) {
+				linked = filepath.Join(filepath.Dir(path), linked)
+			}
+			linkedRel, ok := workspaceRelPath(linked, src)
+			if !ok {
+				return fmt.Errorf("the symlink %s points outside the module to %s, which a shadow copy cannot isolate", rel, link)
+			}
+			link, err = filepath.Rel(filepath.Dir(target), filepath.Join(dst, linkedRel))
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			files++
+			size += info.Size()
+			if maxFiles > 0 && files > maxFiles {
+				return fmt.Errorf("the module has more than %d files and is too large for a shadow copy", maxFiles)
+			}
+			if maxBytes > 0 && size > maxBytes {
+				return fmt.Errorf("the module has more than %d bytes and is too large for a shadow copy", maxBytes)
+			}
+			return copyFile(path, target)
+		}
+		return nil
+	})
+}
+
+// copyFile copies a regular file including its permissions
+func copyFile(src string, dst string) error {
+	stat, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, content, stat.Mode().Perm())
+}
+
+// diffModules returns the module relative paths of files changed or added in the shadow
+// copy and of files removed from it
+func diffModules(moduleRoot string, shadowRoot string) (changed []string, removed []string, err error) {
+	err = filepath.WalkDir(shadowRoot, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(shadowRoot, path)
+		if err != nil {
+			return err
+		}
+		shadowContent, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		originalContent, err := os.ReadFile(filepath.Join(moduleRoot, rel))
+		if err != nil || !bytes.Equal(shadowContent, originalContent) {
+			changed = append(changed, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = filepath.WalkDir(moduleRoot, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(moduleRoot, path)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(filepath.Join(shadowRoot, rel)); os.IsNotExist(err) {
+			removed = append(removed, rel)
+		}
+		return nil
+	})
+	return changed, removed, err
+}
+
+// syncModule applies the changed and removed files of the shadow copy to the module
+func syncModule(shadowRoot string, moduleRoot string, changed []string, removed []string) error {
+	for _, rel := range changed {
+		target := filepath.Join(moduleRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := copyFile(filepath.Join(shadowRoot, rel), target); err != nil {
+			return err
+		}
+		globalFileCache.RemoveFile(target)
+	}
+	for _, rel := range removed {
+		target := filepath.Join(moduleRoot, rel)
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+		globalFileCache.RemoveFile(target)
+	}
+	return nil
+}
+
+// rewriteResultPaths replaces shadow copy paths in the text of a tool result with workspace paths
+func rewriteResultPaths(result *mcp.CallToolResult, shadowRoot string, moduleRoot string) *mcp.CallToolResult {
+	if result == nil {
+		return nil
+	}
+	for i, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			result.Content[i] = mcp.TextContent{
+				Annotated: text.Annotated,
+				Type:      text.Type,
+				Text:      strings.ReplaceAll(text.Text, shadowRoot, moduleRoot),
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,237 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestShadowCopy(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a test module with a passing test
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module testmodule", "", "go 1.21", ""},
+			"main.go": {
+				"package testpkg", // 1
+				"",                // 2
+				"var Values = map[string]int{\"b\": 2, \"a\": 1}", // 3
+				"", // 4
+			},
+			"main_test.go": {
+				"package testpkg",             // 1
+				"",                            // 2
+				"import \"testing\"",          // 3
+				"",                            // 4
+				"func TestA(t *testing.T) {",  // 5
+				"    if Values[\"a\"] != 1 {", // 6
+				"        t.Fatal(\"a\")",      // 7
+				"    }",                       // 8
+				"}",                           // 9
+				"",                            // 10
+			},
+		})
+	}
+
+	// A mutating tool replacing the content of a file
+	writeTool := mcp.NewTool("write", mcp.WithString("file_path"), mcp.WithString("content"))
+	writeHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := request.GetArguments()["file_path"].(string)
+		content := request.GetArguments()["content"].(string)
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			return toolErrorResult(err.Error()), nil
+		}
+		return mcp.NewToolResultText("wrote " + filePath), nil
+	}
+
+	t.Run("valid changes are synced back", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		mcpServer := NewMCPServer(WithShadowCopy(ShadowOptions{}))
+
+		result := callTool(t, mcpServer, sortToolName, map[string]any{"file_path": mainFile})
+		text := toolResultText(&result)
+		if result.IsError {
+			t.Fatalf("Expected sort to succeed, got: %s", text)
+		}
+		if !strings.Contains(text, mainFile) {
+			t.Errorf("Expected result to refer to the workspace file, got: %s", text)
+		}
+		if !strings.Contains(text, "synced 1 changed and 0 removed file(s) back") {
+			t.Errorf("Expected validation summary, got: %s", text)
+		}
+
+		content, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), `{"a": 1, "b": 2}`) {
+			t.Errorf("Expected sorted map in workspace, got:\n%s", content)
+		}
+	})
+
+	t.Run("broken changes are discarded", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		mcpServer := NewMCPServer(
+			WithTool(writeTool, writeHandler),
+			WithToolCost("write", ToolCost{Level: CostLow, Mutating: true}),
+			WithShadowCopy(ShadowOptions{SkipTests: true}),
+		)
+
+		result := callTool(t, mcpServer, "write", map[string]any{
+			"file_path": mainFile,
+			"content":   "package testpkg\n\nvar Values = undefined\n",
+		})
+		text := toolResultText(&result)
+		if !result.IsError || !strings.Contains(text, "`go build ./...` failed") {
+			t.Fatalf("Expected build failure, got: %s", text)
+		}
+		if !strings.Contains(text, "undefined") || strings.Contains(text, "go-mcp-tools-shadow-") {
+			t.Errorf("Expected build output with workspace paths, got: %s", text)
+		}
+
+		content, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), `{"b": 2, "a": 1}`) {
+			t.Errorf("Expected workspace to be untouched, got:\n%s", content)
+		}
+	})
+
+	t.Run("failing tests discard changes", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		mcpServer := NewMCPServer(
+			WithTool(writeTool, writeHandler),
+			WithToolCost("write", ToolCost{Level: CostLow, Mutating: true}),
+			WithShadowCopy(ShadowOptions{}),
+		)
+
+		result := callTool(t, mcpServer, "write", map[string]any{
+			"file_path": mainFile,
+			"content":   "package testpkg\n\nvar Values = map[string]int{\"a\": 2}\n",
+		})
+		if !result.IsError || !strings.Contains(toolResultText(&result), "`go test ./...` failed") {
+			t.Fatalf("Expected test failure, got: %s", toolResultText(&result))
+		}
+	})
+
+	t.Run("read-only tools are not copied", func(t *testing.T) {
+		t.Parallel()
+		mcpServer := NewMCPServer(
+			WithTool(writeTool, writeHandler),
+			WithShadowCopy(ShadowOptions{}),
+		)
+
+		// Without a mutating cost the tool writes directly, even outside a module
+		filePath := filepath.Join(t.TempDir(), "notes.txt")
+		result := callTool(t, mcpServer, "write", map[string]any{"file_path": filePath, "content": "x"})
+		if result.IsError {
+			t.Fatalf("Expected direct write to succeed, got: %s", toolResultText(&result))
+		}
+		if _, err := os.Stat(filePath); err != nil {
+			t.Errorf("Expected file to be written: %v", err)
+		}
+	})
+
+	t.Run("large modules are not copied", func(t *testing.T) {
+		t.Parallel()
+		moduleRoot := writeTestModule(t, map[string][]string{
+			"go.mod":            {"module example.com/large", "", "go 1.22", ""},
+			"large.go":          {"package large", ""},
+			"testdata/data.txt": {strings.Repeat("data ", 100)},
+		})
+		if err := copyModule(moduleRoot, t.TempDir(), 3, 1024); err != nil {
+			t.Fatalf("Expected the module to be copied within the limits: %v", err)
+		}
+		err := copyModule(moduleRoot, t.TempDir(), 0, 100)
+		if err == nil || !strings.Contains(err.Error(), "more than 100 bytes") {
+			t.Errorf("Expected the module to exceed the byte limit, got %v", err)
+		}
+		err = copyModule(moduleRoot, t.TempDir(), 2, 0)
+		if err == nil || !strings.Contains(err.Error(), "more than 2 files") {
+			t.Errorf("Expected the module to exceed the file limit, got %v", err)
+		}
+
+		maxFiles, maxBytes := ShadowOptions{MaxFiles: 2, MaxBytes: -1}.limits()
+		if maxFiles != 2 || maxBytes != 0 {
+			t.Errorf("Expected the set file limit and no byte limit, got %d and %d", maxFiles, maxBytes)
+		}
+		maxFiles, maxBytes = ShadowOptions{}.limits()
+		if maxFiles != DefaultShadowMaxFiles || maxBytes != DefaultShadowMaxBytes {
+			t.Errorf("Expected the default limits, got %d and %d", maxFiles, maxBytes)
+		}
+	})
+
+	t.Run("symlinks point into the copy", func(t *testing.T) {
+		t.Parallel()
+		moduleRoot := writeTestModule(t, map[string][]string{
+			"go.mod":        {"module example.com/links", "", "go 1.22", ""},
+			"data/file.txt": {"data"},
+		})
+		for name, link := range map[string]string{
+			"relative": filepath.Join("data", "file.txt"),
+			"absolute": filepath.Join(moduleRoot, "data", "file.txt"),
+		} {
+			if err := os.Symlink(link, filepath.Join(moduleRoot, name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		shadowRoot := t.TempDir()
+		if err := copyModule(moduleRoot, shadowRoot, 0, 0); err != nil {
+			t.Fatalf("Failed to copy the module: %v", err)
+		}
+		for _, name := range []string{"relative", "absolute"} {
+			resolved, err := filepath.EvalSymlinks(filepath.Join(shadowRoot, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rel, ok := workspaceRelPath(resolved, shadowRoot); !ok || rel != filepath.Join("data", "file.txt") {
+				t.Errorf("Expected the %s symlink to point into the copy, got %s", name, resolved)
+			}
+		}
+
+		outside := filepath.Join(t.TempDir(), "outside.txt")
+		if err := os.Symlink(outside, filepath.Join(moduleRoot, "escape")); err != nil {
+			t.Fatal(err)
+		}
+		err := copyModule(moduleRoot, t.TempDir(), 0, 0)
+		if err == nil || !strings.Contains(err.Error(), "points outside the module") {
+			t.Errorf("Expected the symlink out of the module to be refused, got %v", err)
+		}
+	})
+
+	t.Run("paths outside the module are refused", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		outside := filepath.Join(t.TempDir(), "main.go")
+		mcpServer := NewMCPServer(
+			WithTool(mcp.NewTool("write", mcp.WithString("file_path"), mcp.WithString("content"), mcp.WithString("workspace_dir")), writeHandler),
+			WithToolCost("write", ToolCost{Level: CostLow, Mutating: true}),
+			WithShadowCopy(ShadowOptions{SkipTests: true}),
+		)
+
+		result := callTool(t, mcpServer, "write", map[string]any{
+			"workspace_dir": workspace,
+			"file_path":     outside,
+			"content":       "package outside\n",
+		})
+		if !result.IsError || !strings.Contains(toolResultText(&result), "is outside the module") {
+			t.Fatalf("Expected the path outside the module to be refused, got: %s", toolResultText(&result))
+		}
+		if _, err := os.Stat(outside); !os.IsNotExist(err) {
+			t.Errorf("Expected the file outside the module not to be written, got %v", err)
+		}
+	})
+}
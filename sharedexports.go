@@ -0,0 +1,477 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	sharedExportsToolName        = "shared_exports"
+	sharedExportsToolDescription = `Reports the symbols a Go module exports to other languages and programs when built with -buildmode=c-shared, c-archive or plugin, the ABI embedders see:
+
+• c-shared: functions marked with //export, with their Go signature and the C prototype cgo writes to the generated header, e.g. extern GoInt Add(GoInt a, GoInt b); for func Add(a, b int) int. Functions returning several values return a struct Name_return. Exports cgo rejects are flagged: a name differing from the function, methods, files without import "C" and types without a C equivalent.
+• plugin: the exported functions and variables of main packages without a main function, which plugin.Lookup finds by name.
+• plugin.Lookup calls in the module with the symbol they look up and the plugin packages exporting it.
+
+The source is parsed without type checking, so types are resolved through the type declarations of their package only.`
+)
+
+func AddSharedExportsTool(mcpServer *server.MCPServer) {
+	handleSharedExports := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		exports, err := FindSharedExports(workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding shared library exports: %v", err)), nil
+		}
+		return mcp.NewToolResultText(exports.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		sharedExportsToolName,
+		mcp.WithDescription(sharedExportsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+	), handleSharedExports)
+}
+
+// SharedExports lists the symbols a module exports from shared libraries and plugins
+type SharedExports struct {
+	Module    string          `json:"module"`
+	Libraries []SharedLibrary `json:"libraries,omitempty"`
+	Lookups   []PluginLookup  `json:"lookups,omitempty"`
+}
+
+// SharedLibrary is a package exporting symbols to a shared library or plugin
+type SharedLibrary struct {
+	ImportPath string `json:"import_path"`
+	// BuildMode is c-shared for packages with //export functions and plugin otherwise
+	BuildMode string         `json:"build_mode"`
+	Symbols   []SharedSymbol `json:"symbols"`
+	// Warnings are problems of the package as a whole, e.g. a missing main function
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SharedSymbol is a function or variable exported from a shared library or plugin
+type SharedSymbol struct {
+	Name string `json:"name"`
+	// Kind is func or var
+	Kind string `json:"kind"`
+	// Signature is the Go declaration, e.g. func Add(a, b int) int
+	Signature string `json:"signature"`
+	// CPrototype is the declaration of the generated C header of //export functions
+	CPrototype string `json:"c_prototype,omitempty"`
+	// CReturn is the struct holding the results of functions returning several values
+	CReturn  string   `json:"c_return,omitempty"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PluginLookup is a plugin.Lookup call looking up a symbol by name
+type PluginLookup struct {
+	Symbol   string `json:"symbol"`
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	// Plugins are the import paths of the plugin packages exporting the symbol
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+// cgoGoTypes are the C types cgo gives the predeclared Go types in exported functions
+var cgoGoTypes = map[string]string{
+	"bool":       "GoUint8",
+	"byte":       "GoUint8",
+	"rune":       "GoInt32",
+	"int":        "GoInt",
+	"int8":       "GoInt8",
+	"int16":      "GoInt16",
+	"int32":      "GoInt32",
+	"int64":      "GoInt64",
+	"uint":       "GoUint",
+	"uint8":      "GoUint8",
+	"uint16":     "GoUint16",
+	"uint32":     "GoUint32",
+	"uint64":     "GoUint64",
+	"uintptr":    "GoUintptr",
+	"float32":    "GoFloat32",
+	"float64":    "GoFloat64",
+	"complex64":  "GoComplex64",
+	"complex128": "GoComplex128",
+	"string":     "GoString",
+	"error":      "GoInterface",
+	"any":        "GoInterface",
+}
+
+// cgoCTypes are the C types of the C.name types of cgo abbreviating them
+var cgoCTypes = map[string]string{
+	"schar":     "signed char",
+	"uchar":     "unsigned char",
+	"ushort":    "unsigned short",
+	"uint":      "unsigned int",
+	"ulong":     "unsigned long",
+	"longlong":  "long long",
+	"ulonglong": "unsigned long long",
+}
+
+// FindSharedExports lists the //export functions, plugin symbols and plugin.Lookup calls
+// of the module containing workspaceDir
+func FindSharedExports(workspaceDir string) (*SharedExports, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := loadModuleSources(workspaceDir, false)
+	if err != nil {
+		return nil, err
+	}
+	exports := &SharedExports{Module: module.path}
+	plugins := make(map[string][]string)
+	for _, pkg := range module.packages {
+		typeSpecs := make(map[string]*ast.TypeSpec)
+		hasMain := false
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						if spec, ok := spec.(*ast.TypeSpec); ok {
+							typeSpecs[spec.Name.Name] = spec
+						}
+					}
+				case *ast.FuncDecl:
+					hasMain = hasMain || decl.Recv == nil && decl.Name.Name == "main"
+				}
+			}
+		}
+
+		library := SharedLibrary{ImportPath: pkg.importPath, BuildMode: "c-shared"}
+		for _, file := range pkg.files {
+			importsC := false
+			for _, spec := range file.ast.Imports {
+				importsC = importsC || spec.Path.Value == `"C"`
+			}
+			for _, decl := range file.ast.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Doc == nil {
+					continue
+				}
+				name, ok := exportDirective(funcDecl.Doc)
+				if !ok {
+					continue
+				}
+				symbol := SharedSymbol{
+					Name:      name,
+					Kind:      "func",
+					Signature: "func " + funcDeclName(funcDecl) + strings.TrimPrefix(types.ExprString(funcDecl.Type), "func"),
+					File:      module.relPath(file.path),
+					Line:      file.fset.Position(funcDecl.Pos()).Line,
+				}
+				if funcDecl.Recv != nil {
+					symbol.Warnings = append(symbol.Warnings, "methods cannot be exported")
+				}
+				if name != funcDecl.Name.Name {
+					symbol.Warnings = append(symbol.Warnings, fmt.Sprintf("the //export name differs from the function name %s", funcDecl.Name.Name))
+				}
+				if !importsC {
+					symbol.Warnings = append(symbol.Warnings, `the file does not import "C", so cgo ignores the //export comment`)
+				}
+				var unsupported []string
+				symbol.CPrototype, symbol.CReturn = cPrototype(name, funcDecl.Type, func(expr ast.Expr) string {
+					cType, ok := cgoType(expr, typeSpecs, nil)
+					if !ok {
+						unsupported = append(unsupported, types.ExprString(expr))
+					}
+					return cType
+				})
+				for _, goType := range unsupported {
+					symbol.Warnings = append(symbol.Warnings, fmt.Sprintf("cgo has no C type for %s", goType))
+				}
+				library.Symbols = append(library.Symbols, symbol)
+			}
+		}
+		if len(library.Symbols) > 0 {
+			if pkg.name != "main" {
+				library.Warnings = append(library.Warnings, "exported from the c-shared libraries of the main packages importing it")
+			} else if !hasMain {
+				library.Warnings = append(library.Warnings, "c-shared and c-archive builds need a main function, e.g. func main() {}")
+			}
+			exports.Libraries = append(exports.Libraries, library)
+			continue
+		}
+
+		// Plugins are main packages without a main function, as they are never run
+		if pkg.name != "main" || hasMain {
+			continue
+		}
+		library = SharedLibrary{ImportPath: pkg.importPath, BuildMode: "plugin"}
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					if decl.Recv != nil || !decl.Name.IsExported() {
+						continue
+					}
+					library.Symbols = append(library.Symbols, SharedSymbol{
+						Name:      decl.Name.Name,
+						Kind:      "func",
+						Signature: "func " + decl.Name.Name + strings.TrimPrefix(types.ExprString(decl.Type), "func"),
+						File:      module.relPath(file.path),
+						Line:      file.fset.Position(decl.Pos()).Line,
+					})
+				case *ast.GenDecl:
+					if decl.Tok != token.VAR {
+						continue
+					}
+					for _, spec := range decl.Specs {
+						spec := spec.(*ast.ValueSpec)
+						for _, name := range spec.Names {
+							if !name.IsExported() {
+								continue
+							}
+							signature := "var " + name.Name
+							if spec.Type != nil {
+								signature += " " + types.ExprString(spec.Type)
+							}
+							library.Symbols = append(library.Symbols, SharedSymbol{
+								Name:      name.Name,
+								Kind:      "var",
+								Signature: signature,
+								File:      module.relPath(file.path),
+								Line:      file.fset.Position(name.Pos()).Line,
+							})
+						}
+					}
+				}
+			}
+		}
+		if len(library.Symbols) == 0 {
+			continue
+		}
+		for _, symbol := range library.Symbols {
+			plugins[symbol.Name] = append(plugins[symbol.Name], pkg.importPath)
+		}
+		exports.Libraries = append(exports.Libraries, library)
+	}
+
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			pluginName := ""
+			for _, spec := range file.ast.Imports {
+				if spec.Path.Value == `"plugin"` {
+					pluginName = "plugin"
+					if spec.Name != nil {
+						pluginName = spec.Name.Name
+					}
+				}
+			}
+			if pluginName == "" {
+				continue
+			}
+			ast.Inspect(file.ast, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || len(call.Args) != 1 {
+					return true
+				}
+				selector, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || selector.Sel.Name != "Lookup" {
+					return true
+				}
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				name, _ := strconv.Unquote(lit.Value)
+				exports.Lookups = append(exports.Lookups, PluginLookup{
+					Symbol:   name,
+					Function: enclosingFunctionName(file.ast, call.Pos()),
+					File:     module.relPath(file.path),
+					Line:     file.fset.Position(call.Pos()).Line,
+					Plugins:  plugins[name],
+				})
+				return true
+			})
+		}
+	}
+	return exports, nil
+}
+
+// exportDirective returns the name of the //export directive of a doc comment
+func exportDirective(doc *ast.CommentGroup) (string, bool) {
+	for _, comment := range doc.List {
+		if name, ok := strings.CutPrefix(comment.Text, "//export "); ok {
+			return strings.TrimSpace(name), true
+		}
+	}
+	return "", false
+}
+
+// cPrototype returns the C declaration cgo writes to the header of a c-shared library
+// for an exported function, and the struct of its results when it returns several
+func cPrototype(name string, funcType *ast.FuncType, cType func(ast.Expr) string) (string, string) {
+	var params []string
+	for i, field := range cgoFields(funcType.Params) {
+		paramName := field.name
+		if paramName == "" {
+			paramName = fmt.Sprintf("p%d", i)
+		}
+		params = append(params, cType(field.typ)+" "+paramName)
+	}
+	if len(params) == 0 {
+		params = []string{"void"}
+	}
+	results := cgoFields(funcType.Results)
+	switch len(results) {
+	case 0:
+		return fmt.Sprintf("extern void %s(%s);", name, strings.Join(params, ", ")), ""
+	case 1:
+		return fmt.Sprintf("extern %s %s(%s);", cType(results[0].typ), name, strings.Join(params, ", ")), ""
+	}
+	// The fields are numbered, with the names of named results in comments
+	var fields []string
+	for i, result := range results {
+		field := fmt.Sprintf("%s r%d;", cType(result.typ), i)
+		if result.name != "" {
+			field += fmt.Sprintf(" /* %s */", result.name)
+		}
+		fields = append(fields, field)
+	}
+	returnStruct := fmt.Sprintf("struct %s_return { %s };", name, strings.Join(fields, " "))
+	return fmt.Sprintf("extern struct %s_return %s(%s);", name, name, strings.Join(params, ", ")), returnStruct
+}
+
+// cgoField is a parameter or result with its name, empty when unnamed
+type cgoField struct {
+	name string
+	typ  ast.Expr
+}
+
+// cgoFields returns the parameters or results of a field list one by one
+func cgoFields(list *ast.FieldList) []cgoField {
+	var fields []cgoField
+	if list == nil {
+		return nil
+	}
+	for _, field := range list.List {
+		if len(field.Names) == 0 {
+			fields = append(fields, cgoField{typ: field.Type})
+		}
+		for _, name := range field.Names {
+			fields = append(fields, cgoField{name: name.Name, typ: field.Type})
+		}
+	}
+	return fields
+}
+
+// cgoType returns the C type cgo uses for a Go type in an exported function, resolving
+// the types declared in the package, and false for types cgo rejects
+func cgoType(expr ast.Expr, typeSpecs map[string]*ast.TypeSpec, resolving map[string]bool) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if spec, ok := typeSpecs[expr.Name]; ok && !resolving[expr.Name] {
+			if resolving == nil {
+				resolving = make(map[string]bool)
+			}
+			resolving[expr.Name] = true
+			if cType, ok := cgoType(spec.Type, typeSpecs, resolving); ok {
+				return cType, true
+			}
+			return expr.Name, false
+		}
+		cType, ok := cgoGoTypes[expr.Name]
+		if !ok {
+			return expr.Name, false
+		}
+		return cType, true
+	case *ast.SelectorExpr:
+		pkg, _ := expr.X.(*ast.Ident)
+		switch {
+		case pkg != nil && pkg.Name == "C":
+			if cType, ok := cgoCTypes[expr.Sel.Name]; ok {
+				return cType, true
+			}
+			for _, prefix := range []string{"struct_", "union_", "enum_"} {
+				if name, ok := strings.CutPrefix(expr.Sel.Name, prefix); ok {
+					return strings.TrimSuffix(prefix, "_") + " " + name, true
+				}
+			}
+			return expr.Sel.Name, true
+		case pkg != nil && pkg.Name == "unsafe" && expr.Sel.Name == "Pointer":
+			return "void*", true
+		}
+		return types.ExprString(expr), false
+	case *ast.StarExpr:
+		cType, ok := cgoType(expr.X, typeSpecs, resolving)
+		return cType + "*", ok
+	case *ast.ArrayType:
+		if expr.Len == nil {
+			return "GoSlice", true
+		}
+	case *ast.MapType:
+		return "GoMap", true
+	case *ast.ChanType:
+		return "GoChan", true
+	case *ast.InterfaceType:
+		return "GoInterface", true
+	case *ast.FuncType:
+		return "void*", true
+	case *ast.ParenExpr:
+		return cgoType(expr.X, typeSpecs, resolving)
+	}
+	return types.ExprString(expr), false
+}
+
+func (exports *SharedExports) String() string {
+	if len(exports.Libraries) == 0 && len(exports.Lookups) == 0 {
+		return fmt.Sprintf("No //export functions, plugin packages or plugin.Lookup calls found in %s", exports.Module)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Shared library exports of %s:\n", exports.Module)
+	for _, library := range exports.Libraries {
+		fmt.Fprintf(&b, "\n%s %s:\n", library.BuildMode, library.ImportPath)
+		for _, warning := range library.Warnings {
+			fmt.Fprintf(&b, "  note: %s\n", warning)
+		}
+		for _, symbol := range library.Symbols {
+			fmt.Fprintf(&b, "  %s (%s:%d)\n", symbol.Signature, symbol.File, symbol.Line)
+			if symbol.CReturn != "" {
+				fmt.Fprintf(&b, "    C: %s\n", symbol.CReturn)
+			}
+			if symbol.CPrototype != "" {
+				fmt.Fprintf(&b, "    C: %s\n", symbol.CPrototype)
+			}
+			for _, warning := range symbol.Warnings {
+				fmt.Fprintf(&b, "    warning: %s\n", warning)
+			}
+		}
+	}
+	if len(exports.Lookups) > 0 {
+		b.WriteString("\nplugin.Lookup calls:\n")
+		for _, lookup := range exports.Lookups {
+			fmt.Fprintf(&b, "  %q in %s (%s:%d)", lookup.Symbol, lookup.Function, lookup.File, lookup.Line)
+			if len(lookup.Plugins) > 0 {
+				fmt.Fprintf(&b, ", exported by %s\n", strings.Join(lookup.Plugins, ", "))
+			} else {
+				b.WriteString(", not exported by a plugin package of the module\n")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package go_mcp_tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSharedExports(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/embed", "", "go 1.22", ""},
+		"lib/main.go": {
+			"package main", // 1
+			"",
+			"import \"C\"", // 3
+			"",
+			"import \"unsafe\"", // 5
+			"",
+			"type Handle int64", // 7
+			"",
+			"type Point struct{ X int }", // 9
+			"",
+			"//export Add", // 11
+			"func Add(a, b int) int { return a + b }",
+			"",
+			"//export Divide", // 14
+			"func Divide(a, b float64) (quotient float64, err error) { return }",
+			"",
+			"//export Open", // 17
+			"func Open(h Handle, data unsafe.Pointer, name *C.char, flags C.uint, buf []byte) bool { return true }",
+			"",
+			"//export Anon", // 20
+			"func Anon(int, string) {}",
+			"",
+			"//export Move", // 23
+			"func Move(p *Point) {}",
+			"",
+			"//export Renamed", // 26
+			"func renamed() {}",
+			"",
+			"func main() {}", // 29
+			"",
+		},
+		"lib/nocgo.go": {
+			"package main", // 1
+			"",
+			"//export Ignored", // 3
+			"func Ignored() {}",
+			"",
+		},
+		"plugins/greeter/greeter.go": {
+			"package main", // 1
+			"",
+			"var Version = \"1.0\"", // 3
+			"",
+			"var count int", // 5
+			"",
+			"func Greet(name string) string { return \"hello \" + name }", // 7
+			"",
+			"func helper() {}", // 9
+			"",
+		},
+		"cmd/app/main.go": {
+			"package main", // 1
+			"",
+			"import \"plugin\"", // 3
+			"",
+			"func Exported() {}", // 5
+			"",
+			"func main() {", // 7
+			"	p, _ := plugin.Open(\"greeter.so\")",
+			"	p.Lookup(\"Greet\")", // 9
+			"	p.Lookup(\"Missing\")",
+			"}",
+			"",
+		},
+	})
+
+	t.Run("exports", func(t *testing.T) {
+		t.Parallel()
+		exports, err := FindSharedExports(workspace)
+		if err != nil {
+			t.Fatalf("Failed to find exports: %v", err)
+		}
+		// The prototypes match the headers of go build -buildmode=c-shared
+		expected := strings.Join([]string{
+			"Shared library exports of example.com/embed:",
+			"",
+			"c-shared example.com/embed/lib:",
+			"  func Add(a, b int) int (lib/main.go:12)",
+			"    C: extern GoInt Add(GoInt a, GoInt b);",
+			"  func Divide(a, b float64) (quotient float64, err error) (lib/main.go:15)",
+			"    C: struct Divide_return { GoFloat64 r0; /* quotient */ GoInterface r1; /* err */ };",
+			"    C: extern struct Divide_return Divide(GoFloat64 a, GoFloat64 b);",
+			"  func Open(h Handle, data unsafe.Pointer, name *C.char, flags C.uint, buf []byte) bool (lib/main.go:18)",
+			"    C: extern GoUint8 Open(GoInt64 h, void* data, char* name, unsigned int flags, GoSlice buf);",
+			"  func Anon(int, string) (lib/main.go:21)",
+			"    C: extern void Anon(GoInt p0, GoString p1);",
+			"  func Move(p *Point) (lib/main.go:24)",
+			"    C: extern void Move(Point* p);",
+			"    warning: cgo has no C type for *Point",
+			"  func renamed() (lib/main.go:27)",
+			"    C: extern void Renamed(void);",
+			"    warning: the //export name differs from the function name renamed",
+			"  func Ignored() (lib/nocgo.go:4)",
+			"    C: extern void Ignored(void);",
+			"    warning: the file does not import \"C\", so cgo ignores the //export comment",
+			"",
+			"plugin example.com/embed/plugins/greeter:",
+			"  var Version (plugins/greeter/greeter.go:3)",
+			"  func Greet(name string) string (plugins/greeter/greeter.go:7)",
+			"",
+			"plugin.Lookup calls:",
+			"  \"Greet\" in main (cmd/app/main.go:9), exported by example.com/embed/plugins/greeter",
+			"  \"Missing\" in main (cmd/app/main.go:10), not exported by a plugin package of the module",
+			"",
+		}, "\n")
+		if exports.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, exports)
+		}
+	})
+
+	t.Run("no exports", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/none\n\ngo 1.22\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc Run() {}\n\nfunc main() {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		exports, err := FindSharedExports(dir)
+		if err != nil {
+			t.Fatalf("Failed to find exports: %v", err)
+		}
+		if exports.String() != "No //export functions, plugin packages or plugin.Lookup calls found in example.com/none" {
+			t.Errorf("Unexpected exports:\n%s", exports)
+		}
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		t.Parallel()
+		if _, err := FindSharedExports("lib"); err == nil {
+			t.Error("Expected an error for a relative path")
+		}
+	})
+}
@@ -0,0 +1,316 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	signatureHelpToolName        = "signature_help"
+	signatureHelpToolDescription = `Returns the signature of a function called on a line of a Go file, like the signature help of an editor: the function, its documentation, each parameter with its name, type and comment, and the parameter an argument is passed to. Useful when filling in the arguments of a call to get the parameter names and types instead of guessing them.
+
+The call is found by its callee as written on the line, e.g. "Connect", "c.Send" or "strings.TrimSpace", the first call of it on the line is used.`
+)
+
+func AddSignatureHelpTool(mcpServer *server.MCPServer) {
+	handleSignatureHelp := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		lineNumber, err := request.RequireInt("line_number")
+		if err != nil {
+			return nil, err
+		}
+		callee, err := request.RequireString("callee")
+		if err != nil {
+			return nil, err
+		}
+		argument := request.GetString("argument", "")
+
+		help, err := SignatureHelp(ctx, filePath, lineNumber, callee, argument)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error getting signature help: %v", err)), nil
+		}
+		return mcp.NewToolResultText(help.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		signatureHelpToolName,
+		mcp.WithDescription(signatureHelpToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("line_number",
+			mcp.Description("Line the call starts on"),
+			mcp.Required(),
+		),
+		mcp.WithString("callee",
+			mcp.Description("Called function as written in the call, e.g. \"c.Send\""),
+			mcp.Required(),
+		),
+		mcp.WithString("argument",
+			mcp.Description("An argument of the call as written, whose parameter is the active one. By default the parameter of the last argument written, or of the next one after a trailing comma."),
+		),
+	), handleSignatureHelp)
+}
+
+// CallSignature describes the function called at a position and the parameter it is at
+type CallSignature struct {
+	// Function is the called function, qualified by its package or receiver type
+	Function  string `json:"function"`
+	Signature string `json:"signature"`
+	Doc       string `json:"doc,omitempty"`
+	// Parameters lists the parameters of the function. The last parameter of a variadic
+	// function has a ...T type.
+	Parameters []SignatureParameter `json:"parameters"`
+	// ActiveParameter is the index of the parameter the position is at, -1 when the
+	// position is past the parameters
+	ActiveParameter int `json:"active_parameter"`
+}
+
+// SignatureParameter is a parameter of a called function
+type SignatureParameter struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+	// Doc is the comment of the parameter in the declaration of the function
+	Doc string `json:"doc,omitempty"`
+}
+
+// SignatureHelp describes the signature of the first call of callee, the called function as
+// written, starting on lineNumber of filePath. The active parameter is the one of argument
+// when it is not empty, otherwise the one of the last argument written, or of the next one
+// after a trailing comma.
+func SignatureHelp(ctx context.Context, filePath string, lineNumber int, callee string, argument string) (*CallSignature, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if lineNumber <= 0 {
+		return nil, fmt.Errorf("line number must be positive, got %d", lineNumber)
+	}
+	pkg, file, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	tokenFile := pkg.Fset.File(file.Pos())
+	if lineNumber > tokenFile.LineCount() {
+		return nil, fmt.Errorf("line number %d exceeds the length of %s", lineNumber, filePath)
+	}
+
+	// Callees are compared as printed, so spacing in the file does not matter
+	var call *ast.CallExpr
+	ast.Inspect(file, func(node ast.Node) bool {
+		if candidate, ok := node.(*ast.CallExpr); ok && call == nil && tokenFile.Line(candidate.Pos()) == lineNumber &&
+			types.ExprString(candidate.Fun) == callee {
+			call = candidate
+		}
+		return call == nil
+	})
+	if call == nil {
+		return nil, classifyErrorf(ErrSymbolNotFound, "no call of %s starting on line %d of %s", callee, lineNumber, filePath)
+	}
+	pos := call.Rparen
+	if !pos.IsValid() {
+		pos = call.End()
+	}
+	if argument != "" {
+		index := slices.IndexFunc(call.Args, func(arg ast.Expr) bool { return types.ExprString(arg) == argument })
+		if index < 0 {
+			return nil, classifyErrorf(ErrSymbolNotFound, "no argument %s in the call of %s on line %d", argument, callee, lineNumber)
+		}
+		pos = call.Args[index].Pos()
+	}
+
+	funType, ok := pkg.TypesInfo.Types[call.Fun]
+	if !ok {
+		return nil, fmt.Errorf("the type of %s is unknown", types.ExprString(call.Fun))
+	}
+	if funType.IsType() {
+		return nil, fmt.Errorf("%s is a conversion to %s, not a call", types.ExprString(call), types.ExprString(call.Fun))
+	}
+	signature, ok := funType.Type.Underlying().(*types.Signature)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a function", types.ExprString(call.Fun))
+	}
+
+	qualifier := types.RelativeTo(pkg.Types)
+	result := &CallSignature{
+		Function:        types.ExprString(call.Fun),
+		Signature:       types.TypeString(signature, qualifier),
+		ActiveParameter: activeParameter(pkg, call, pos, signature),
+	}
+	var fieldDocs []string
+	if fn := calledFunc(pkg.TypesInfo, call); fn != nil {
+		result.Function = fn.Name()
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			result.Function = types.TypeString(recv.Type(), qualifier) + "." + fn.Name()
+		} else if fn.Pkg() != nil && fn.Pkg() != pkg.Types {
+			result.Function = fn.Pkg().Name() + "." + fn.Name()
+		}
+		result.Doc, fieldDocs = funcDocs(pkg.Fset, fn)
+	}
+	for i := 0; i < signature.Params().Len(); i++ {
+		param := signature.Params().At(i)
+		parameter := SignatureParameter{Name: param.Name(), Type: types.TypeString(param.Type(), qualifier)}
+		if signature.Variadic() && i == signature.Params().Len()-1 {
+			parameter.Type = "..." + types.TypeString(param.Type().(*types.Slice).Elem(), qualifier)
+		}
+		if i < len(fieldDocs) {
+			parameter.Doc = fieldDocs[i]
+		}
+		result.Parameters = append(result.Parameters, parameter)
+	}
+	return result, nil
+}
+
+// calledFunc returns the function or method called, nil for calls of function values
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	if index, ok := fun.(*ast.IndexExpr); ok {
+		fun = index.X
+	} else if index, ok := fun.(*ast.IndexListExpr); ok {
+		fun = index.X
+	}
+	var ident *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[ident].(*types.Func)
+	return fn
+}
+
+// activeParameter returns the index of the parameter the position is at, counting the
+// commas after the arguments before it
+func activeParameter(pkg *packages.Package, call *ast.CallExpr, pos token.Pos, signature *types.Signature) int {
+	src, _ := os.ReadFile(pkg.Fset.Position(call.Pos()).Filename)
+	base := pkg.Fset.File(call.Pos()).Base()
+	index := 0
+	for i, arg := range call.Args {
+		if arg.End() >= pos {
+			break
+		}
+		end := pos
+		if i+1 < len(call.Args) && call.Args[i+1].Pos() < end {
+			end = call.Args[i+1].Pos()
+		}
+		if start, stop := int(arg.End())-base, int(end)-base; stop <= len(src) && bytes.Contains(src[start:stop], []byte(",")) {
+			index = i + 1
+		}
+	}
+	if params := signature.Params().Len(); index >= params {
+		if signature.Variadic() {
+			return params - 1
+		}
+		return -1
+	}
+	return index
+}
+
+// funcDocs returns the doc comment of the declaration of the function and the comments of
+// its parameters by parameter index
+func funcDocs(fset *token.FileSet, fn *types.Func) (string, []string) {
+	position := fset.Position(fn.Pos())
+	if !position.IsValid() {
+		return "", nil
+	}
+	cached, err := globalFileCache.GetOrParseFile(position.Filename, nil)
+	if err != nil {
+		return "", nil
+	}
+	tokenFile := cached.fset.File(cached.ast.Pos())
+	if position.Offset >= tokenFile.Size() {
+		return "", nil
+	}
+	namePos := tokenFile.Pos(position.Offset)
+	path, _ := astutil.PathEnclosingInterval(cached.ast, namePos, namePos)
+	var doc *ast.CommentGroup
+	var funcType *ast.FuncType
+	for _, node := range path {
+		if decl, ok := node.(*ast.FuncDecl); ok {
+			doc, funcType = decl.Doc, decl.Type
+			break
+		}
+		// Interface methods are fields of the interface type
+		if field, ok := node.(*ast.Field); ok {
+			if fieldType, ok := field.Type.(*ast.FuncType); ok {
+				doc, funcType = field.Doc, fieldType
+				break
+			}
+		}
+	}
+	if funcType == nil {
+		return "", nil
+	}
+	// The parser only attaches comments to struct fields and interface methods, so the
+	// comments of a parameter are the ones on the lines above it or after it on its line
+	line := func(pos token.Pos) int { return cached.fset.Position(pos).Line }
+	var paramDocs []string
+	for i, field := range funcType.Params.List {
+		after := funcType.Params.Opening
+		if i > 0 {
+			after = funcType.Params.List[i-1].End()
+		}
+		var comments []string
+		for _, group := range cached.ast.Comments {
+			above := group.Pos() > after && line(group.End()) == line(field.Pos())-1
+			trailing := group.Pos() > field.End() && line(group.Pos()) == line(field.End()) && group.Pos() < funcType.Params.Closing
+			if above || trailing {
+				comments = append(comments, strings.TrimSpace(group.Text()))
+			}
+		}
+		for range max(len(field.Names), 1) {
+			paramDocs = append(paramDocs, strings.Join(comments, " "))
+		}
+	}
+	return strings.TrimSpace(doc.Text()), paramDocs
+}
+
+// String formats the signature as human and model readable text, marking the active
+// parameter with an arrow
+func (help *CallSignature) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Function: %s\nSignature: %s\n", help.Function, help.Signature)
+	if help.Doc != "" {
+		fmt.Fprintf(&b, "\n%s\n", help.Doc)
+	}
+	if len(help.Parameters) == 0 {
+		b.WriteString("\nNo parameters\n")
+		return b.String()
+	}
+	b.WriteString("\nParameters:\n")
+	for i, parameter := range help.Parameters {
+		marker := "  "
+		if i == help.ActiveParameter {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%s", marker, strings.TrimSpace(parameter.Name+" "+parameter.Type))
+		if parameter.Doc != "" {
+			fmt.Fprintf(&b, " // %s", parameter.Doc)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
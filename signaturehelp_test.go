@@ -0,0 +1,142 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSignatureHelp(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with documented functions and calls of them
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",                   // 1
+				"",                               // 2
+				"import \"strings\"",             // 3
+				"",                               // 4
+				"// Connect opens a connection",  // 5
+				"func Connect(",                  // 6
+				"\thost string, // host to dial", // 7
+				"\t// port defaults to 80",       // 8
+				"\tport int,",                    // 9
+				"\toptions ...string,",           // 10
+				") error {",                      // 11
+				"\treturn nil",                   // 12
+				"}",                              // 13
+				"",                               // 14
+				"type Client struct{}",           // 15
+				"",                               // 16
+				"// Send sends the payload",      // 17
+				"func (c *Client) Send(payload []byte) {}", // 18
+				"",              // 19
+				"func main() {", // 20
+				"\tConnect(\"localhost\", 8080, \"a\", \"b\")", // 21
+				"\tc := &Client{}",                           // 22
+				"\tc.Send([]byte(strings.TrimSpace(\" \")))", // 23
+				"}", // 24
+				"",  // 25
+			},
+		})
+	}
+
+	t.Run("active parameter", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		// Without an argument, the last argument written is at the variadic parameter
+		for argument, expected := range map[string]int{`"localhost"`: 0, "8080": 1, `"a"`: 2, `"b"`: 2, "": 2} {
+			help, err := SignatureHelp(context.Background(), file, 21, "Connect", argument)
+			if err != nil {
+				t.Fatalf("Failed to get signature help: %v", err)
+			}
+			if help.ActiveParameter != expected {
+				t.Errorf("Expected parameter %d for argument %q, got %d", expected, argument, help.ActiveParameter)
+			}
+		}
+		if _, err := SignatureHelp(context.Background(), file, 21, "Connect", "80"); err == nil || !strings.Contains(err.Error(), "no argument 80") {
+			t.Errorf("Expected an unknown argument to be refused, got: %v", err)
+		}
+
+		help, err := SignatureHelp(context.Background(), file, 21, "Connect", "")
+		if err != nil {
+			t.Fatalf("Failed to get signature help: %v", err)
+		}
+		if help.Function != "Connect" || help.Doc != "Connect opens a connection" {
+			t.Errorf("Expected the documented Connect function, got %+v", help)
+		}
+		expected := []SignatureParameter{
+			{Name: "host", Type: "string", Doc: "host to dial"},
+			{Name: "port", Type: "int", Doc: "port defaults to 80"},
+			{Name: "options", Type: "...string"},
+		}
+		if len(help.Parameters) != len(expected) {
+			t.Fatalf("Expected %d parameters, got %+v", len(expected), help.Parameters)
+		}
+		for i, parameter := range help.Parameters {
+			if parameter != expected[i] {
+				t.Errorf("Expected parameter %+v, got %+v", expected[i], parameter)
+			}
+		}
+	})
+
+	t.Run("innermost call", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		help, err := SignatureHelp(context.Background(), file, 23, "strings.TrimSpace", "")
+		if err != nil {
+			t.Fatalf("Failed to get signature help: %v", err)
+		}
+		if help.Function != "strings.TrimSpace" || help.Signature != "func(s string) string" || !strings.HasPrefix(help.Doc, "TrimSpace returns") {
+			t.Errorf("Expected strings.TrimSpace, got %+v", help)
+		}
+
+		help, err = SignatureHelp(context.Background(), file, 23, "c.Send", "")
+		if err != nil {
+			t.Fatalf("Failed to get signature help: %v", err)
+		}
+		if help.Function != "*Client.Send" || help.Doc != "Send sends the payload" {
+			t.Errorf("Expected the Send method, got %+v", help)
+		}
+
+		if _, err := SignatureHelp(context.Background(), file, 23, "[]byte", ""); err == nil || !strings.Contains(err.Error(), "is a conversion to []byte") {
+			t.Errorf("Expected the conversion to be refused, got: %v", err)
+		}
+		if _, err := SignatureHelp(context.Background(), file, 22, "Client", ""); err == nil || !strings.Contains(err.Error(), "no call of Client starting on line 22") {
+			t.Errorf("Expected no call of a composite literal type, got: %v", err)
+		}
+	})
+
+	t.Run("signature help tool", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      signatureHelpToolName,
+				"arguments": map[string]any{"file_path": file, "line_number": 21, "callee": "Connect", "argument": "8080"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		expected := "Function: Connect\nSignature: func(host string, port int, options ...string) error\n\nConnect opens a connection\n\nParameters:\n  host string // host to dial\n> port int // port defaults to 80\n  options ...string\n"
+		if result.IsError || text != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, text)
+		}
+	})
+}
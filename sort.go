@@ -0,0 +1,711 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	sortToolName        = "sort"
+	sortToolDescription = `Deterministically sorts switch case clauses, map literal keys and import groups in a Go file and writes the result back to disk. Useful for making codemod diffs reviewable.
+
+Only constructs where reordering cannot change behavior are sorted:
+• Switch statements with a tag expression whose case values are all constants and which contain no fallthrough. The default clause is placed last.
+• Map composite literals with an explicit map type whose keys are all constants and whose values have no function calls.
+Constants are determined by type checking the package, so the file's package must load. Literal keys are ordered by their value, other constants by their name.
+• Import specs within each blank-line separated import group.
+
+Comments directly above a case clause, map entry or import move together with it.`
+)
+
+// maxSortPasses bounds how many times nested constructs are re-sorted
+const maxSortPasses = 100
+
+// SortOptions selects which constructs Sort reorders
+type SortOptions struct {
+	SwitchCases bool
+	MapKeys     bool
+	Imports     bool
+}
+
+func AddSortTool(mcpServer *server.MCPServer) {
+	handleSort := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+
+		symbolName := request.GetString("symbol_name", "")
+
+		options := SortOptions{
+			SwitchCases: true,
+			MapKeys:     true,
+			Imports:     true,
+		}
+		options.SwitchCases = request.GetBool("switch_cases", options.SwitchCases)
+		options.MapKeys = request.GetBool("map_keys", options.MapKeys)
+		options.Imports = request.GetBool("imports", options.Imports)
+
+		result, err := Sort(ctx, filePath, symbolName, options)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: fmt.Sprintf("Error sorting file: %v", err),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: result,
+				},
+			},
+		}, nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		sortToolName,
+		mcp.WithDescription(sortToolDescription),
+		editingToolAnnotation(true, true),
+		mcp.WithString("file_path",
+			mcp.Description("Path to the Go file to sort"),
+			mcp.Required(),
+		),
+		mcp.WithString("symbol_name",
+			mcp.Description(
+				"Optional name of a top-level declaration to restrict switch and map sorting to. Imports are file-level and unaffected by this.",
+			),
+		),
+		mcp.WithBoolean("switch_cases",
+			mcp.Description("Whether to sort switch case clauses"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("map_keys",
+			mcp.Description("Whether to sort map literal keys"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("imports",
+			mcp.Description("Whether to sort imports within each import group"),
+			mcp.DefaultBool(true),
+		),
+	), handleSort)
+}
+
+// Sort reorders switch case clauses, map literal keys and import groups
+// of the given file as selected by options and writes the file back.
+// symbolName optionally restricts switch and map sorting to a single top-level declaration.
+// Switches and maps are only sorted when the type checked package proves their case values
+// and keys constant. Returns a summary of what was sorted.
+func Sort(ctx context.Context, filePath string, symbolName string, options SortOptions) (string, error) {
+	if filePath == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+
+	if !options.SwitchCases && !options.MapKeys && !options.Imports {
+		return "", fmt.Errorf(
+			"nothing to sort: enable at least one of switch_cases, map_keys or imports",
+		)
+	}
+
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file %s: %w", filePath, err)
+	}
+
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	src := original
+	counts := make(map[string]int)
+	symbolFound := symbolName == ""
+
+	for pass := 0; ; pass++ {
+		if pass >= maxSortPasses {
+			return "", fmt.Errorf(
+				"sorting %s did not converge after %d passes",
+				filePath,
+				maxSortPasses,
+			)
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+		if err != nil {
+			return "", classifyErrorf(
+				ErrSyntaxErrors,
+				"failed to parse file %s (sorting requires a file without syntax errors): %w",
+				filePath,
+				err,
+			)
+		}
+
+		// The type checked syntax of the current source replaces the parsed one, so the
+		// expressions can be looked up in the type information
+		var info *types.Info
+		if options.SwitchCases || options.MapKeys {
+			absPath, err := filepath.Abs(filePath)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve %s: %w", filePath, err)
+			}
+			pkg, pkgFile, _, err := loadOverlaidFilePackage(ctx, absPath, Overlay{absPath: src})
+			if err != nil {
+				return "", err
+			}
+			fset, file, info = pkg.Fset, pkgFile, pkg.TypesInfo
+		}
+
+		var candidates []sortCandidate
+
+		// Imports are file-level and are therefore not restricted by symbolName
+		if options.Imports {
+			for _, decl := range file.Decls {
+				if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+					candidates = append(candidates, importSortCandidates(fset, src, genDecl)...)
+				}
+			}
+		}
+
+		for _, decl := range file.Decls {
+			if symbolName != "" && !declHasName(decl, symbolName) {
+				continue
+			}
+			symbolFound = true
+
+			ast.Inspect(decl, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.SwitchStmt:
+					if options.SwitchCases {
+						if candidate, ok := switchSortCandidate(fset, src, info, node); ok {
+							candidates = append(candidates, candidate)
+						}
+					}
+				case *ast.CompositeLit:
+					if options.MapKeys {
+						if candidate, ok := mapSortCandidate(fset, src, info, node); ok {
+							candidates = append(candidates, candidate)
+						}
+					}
+				}
+				return true
+			})
+		}
+
+		if !symbolFound {
+			return "", classifyErrorf(
+				ErrSymbolNotFound,
+				"symbol '%s' not found among the top-level declarations of %s",
+				symbolName,
+				filePath,
+			)
+		}
+
+		// Apply every non-overlapping edit of this pass. Nested constructs that
+		// overlap an already sorted outer construct are handled in the next pass.
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].start < candidates[j].start
+		})
+		var applied []sortCandidate
+		lastEnd := -1
+		for _, candidate := range candidates {
+			if candidate.start < lastEnd {
+				continue
+			}
+			applied = append(applied, candidate)
+			lastEnd = candidate.end
+		}
+
+		if len(applied) == 0 {
+			break
+		}
+
+		var out bytes.Buffer
+		offset := 0
+		for _, candidate := range applied {
+			out.Write(src[offset:candidate.start])
+			out.WriteString(candidate.replacement)
+			offset = candidate.end
+			counts[candidate.kind]++
+		}
+		out.Write(src[offset:])
+		src = out.Bytes()
+	}
+
+	if bytes.Equal(src, original) {
+		return fmt.Sprintf("%s is already sorted", filePath), nil
+	}
+
+	// Keep gofmt-clean files gofmt-clean. Files that were not formatted to begin
+	// with are left alone so the diff only contains the reordering.
+	if formattedOriginal, err := format.Source(original); err == nil &&
+		bytes.Equal(formattedOriginal, original) {
+		if formatted, err := format.Source(src); err == nil {
+			src = formatted
+		}
+	}
+
+	if err := os.WriteFile(filePath, src, stat.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	var parts []string
+	for _, kind := range []string{"switch statement", "map literal", "import group"} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s(s)", counts[kind], kind))
+		}
+	}
+	return fmt.Sprintf("Sorted %s in %s", strings.Join(parts, ", "), filePath), nil
+}
+
+// sortCandidate is a text replacement that reorders one construct
+type sortCandidate struct {
+	kind        string
+	start       int
+	end         int
+	replacement string
+}
+
+// sortElement is a single reorderable element (case clause, map entry, import spec)
+type sortElement struct {
+	node ast.Node
+	key  sortKey
+	// firstLine is the first line belonging to the element, including its doc comment
+	firstLine int
+}
+
+// sortKey orders elements numerically when both keys are numeric literals, by value when
+// both are string literals and by source text otherwise
+type sortKey struct {
+	// value is the constant value of a literal key, nil for other keys
+	value constant.Value
+	text  string
+	last  bool
+}
+
+func (a sortKey) less(b sortKey) bool {
+	if a.last != b.last {
+		return b.last
+	}
+	aNumber, bNumber := isOrderedNumber(a.value), isOrderedNumber(b.value)
+	if aNumber && bNumber && !constant.Compare(a.value, token.EQL, b.value) {
+		return constant.Compare(a.value, token.LSS, b.value)
+	}
+	if aNumber != bNumber {
+		return aNumber
+	}
+	return a.valueText() < b.valueText()
+}
+
+// valueText returns the value of a string literal key and the source text of other keys
+func (key sortKey) valueText() string {
+	if key.value != nil && key.value.Kind() == constant.String {
+		return constant.StringVal(key.value)
+	}
+	return key.text
+}
+
+// isOrderedNumber reports whether a constant is an integer or a floating-point number,
+// which unlike complex numbers can be compared with constant.Compare
+func isOrderedNumber(value constant.Value) bool {
+	return value != nil && (value.Kind() == constant.Int || value.Kind() == constant.Float)
+}
+
+// newSortKey creates a sort key from a case value or map key with its type checked constant
+// value. Only literals, possibly negated or parenthesized, are ordered by value.
+func newSortKey(src []byte, fset *token.FileSet, info *types.Info, expr ast.Expr) sortKey {
+	key := sortKey{text: nodeSource(src, fset, expr)}
+	if isLiteral(expr) {
+		key.value = info.Types[expr].Value
+	}
+	return key
+}
+
+// isLiteral reports whether an expression is a basic literal, possibly signed or parenthesized
+func isLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.UnaryExpr:
+		return (e.Op == token.SUB || e.Op == token.ADD) && isLiteral(e.X)
+	case *ast.ParenExpr:
+		return isLiteral(e.X)
+	}
+	return false
+}
+
+// nodeSource returns the source text of a node
+func nodeSource(src []byte, fset *token.FileSet, node ast.Node) string {
+	start := fset.Position(node.Pos()).Offset
+	end := fset.Position(node.End()).Offset
+	if start < 0 || end > len(src) || start > end {
+		return ""
+	}
+	return string(src[start:end])
+}
+
+// isConstant reports whether the type checker evaluated an expression to a constant.
+// Evaluating constants has no effects, and the compiler rejects duplicate constant case
+// values and map keys, so the relative order of such cases and entries does not matter.
+func isConstant(info *types.Info, expr ast.Expr) bool {
+	return info.Types[expr].Value != nil
+}
+
+// hasSideEffects reports whether evaluating an expression may call functions or receive from channels.
+// Function literal bodies are not evaluated and are therefore ignored.
+func hasSideEffects(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			found = true
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// declHasName reports whether a top-level declaration declares the given name
+func declHasName(decl ast.Decl, name string) bool {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return d.Name.Name == name
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.Name == name {
+					return true
+				}
+			case *ast.ValueSpec:
+				for _, ident := range s.Names {
+					if ident.Name == name {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// switchSortCandidate creates a candidate for sorting the case clauses of a switch statement
+func switchSortCandidate(
+	fset *token.FileSet,
+	src []byte,
+	info *types.Info,
+	switchStmt *ast.SwitchStmt,
+) (sortCandidate, bool) {
+	if switchStmt.Tag == nil || switchStmt.Body == nil || len(switchStmt.Body.List) < 2 {
+		return sortCandidate{}, false
+	}
+
+	var elements []sortElement
+	for _, stmt := range switchStmt.Body.List {
+		clause, ok := stmt.(*ast.CaseClause)
+		if !ok {
+			return sortCandidate{}, false
+		}
+		for _, bodyStmt := range clause.Body {
+			if branch, ok := bodyStmt.(*ast.BranchStmt); ok && branch.Tok == token.FALLTHROUGH {
+				return sortCandidate{}, false
+			}
+		}
+
+		element := sortElement{node: clause}
+		if clause.List == nil {
+			element.key = sortKey{last: true}
+		} else {
+			for _, expr := range clause.List {
+				if !isConstant(info, expr) {
+					return sortCandidate{}, false
+				}
+			}
+			element.key = newSortKey(src, fset, info, clause.List[0])
+		}
+		elements = append(elements, element)
+	}
+
+	openLine := fset.Position(switchStmt.Body.Lbrace).Line
+	for i := range elements {
+		elements[i].firstLine = openLine + 1
+		if i > 0 {
+			elements[i].firstLine = fset.Position(elements[i-1].node.End()).Line + 1
+		}
+	}
+
+	return lineSortCandidate(
+		"switch statement",
+		fset,
+		src,
+		elements,
+		fset.Position(switchStmt.Body.Rbrace).Line,
+	)
+}
+
+// mapSortCandidate creates a candidate for sorting the keys of a map composite literal
+func mapSortCandidate(
+	fset *token.FileSet,
+	src []byte,
+	info *types.Info,
+	lit *ast.CompositeLit,
+) (sortCandidate, bool) {
+	if _, ok := lit.Type.(*ast.MapType); !ok || len(lit.Elts) < 2 {
+		return sortCandidate{}, false
+	}
+
+	var elements []sortElement
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok || !isConstant(info, kv.Key) || hasSideEffects(kv.Value) {
+			return sortCandidate{}, false
+		}
+		elements = append(elements, sortElement{
+			node: kv,
+			key:  newSortKey(src, fset, info, kv.Key),
+		})
+	}
+
+	openLine := fset.Position(lit.Lbrace).Line
+	closeLine := fset.Position(lit.Rbrace).Line
+	onePerLine := fset.Position(elements[0].node.Pos()).Line > openLine
+	for i := range elements {
+		elements[i].firstLine = openLine + 1
+		if i > 0 {
+			prevEndLine := fset.Position(elements[i-1].node.End()).Line
+			elements[i].firstLine = prevEndLine + 1
+			if fset.Position(elements[i].node.Pos()).Line <= prevEndLine {
+				onePerLine = false
+			}
+		}
+	}
+
+	if onePerLine {
+		return lineSortCandidate("map literal", fset, src, elements, closeLine)
+	}
+	return inlineSortCandidate("map literal", fset, src, elements, lit.Lbrace, lit.Rbrace)
+}
+
+// importSortCandidates creates candidates for sorting each blank-line separated group of an import declaration
+func importSortCandidates(
+	fset *token.FileSet,
+	src []byte,
+	genDecl *ast.GenDecl,
+) []sortCandidate {
+	if !genDecl.Lparen.IsValid() || len(genDecl.Specs) < 2 {
+		return nil
+	}
+
+	lines := strings.Split(string(src), "\n")
+	isBlank := func(line int) bool {
+		return line >= 1 && line <= len(lines) && strings.TrimSpace(lines[line-1]) == ""
+	}
+
+	var groups [][]sortElement
+	var current []sortElement
+	prevEndLine := 0
+	for _, spec := range genDecl.Specs {
+		importSpec := spec.(*ast.ImportSpec)
+		startLine := fset.Position(importSpec.Pos()).Line
+		if importSpec.Doc != nil {
+			startLine = fset.Position(importSpec.Doc.Pos()).Line
+		}
+
+		if prevEndLine > 0 {
+			if startLine <= prevEndLine {
+				// Several imports on one line, leave the declaration as is
+				return nil
+			}
+			for line := prevEndLine + 1; line < startLine; line++ {
+				if isBlank(line) {
+					groups = append(groups, current)
+					current = nil
+					break
+				}
+			}
+		}
+
+		element := sortElement{
+			node:      importSpec,
+			key:       sortKey{value: constant.MakeFromLiteral(importSpec.Path.Value, token.STRING, 0)},
+			firstLine: startLine,
+		}
+		if len(current) > 0 {
+			element.firstLine = prevEndLine + 1
+		}
+		current = append(current, element)
+		prevEndLine = fset.Position(importSpec.End()).Line
+	}
+	groups = append(groups, current)
+
+	var candidates []sortCandidate
+	closeLine := fset.Position(genDecl.Rparen).Line
+	for i, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		limitLine := closeLine
+		if i+1 < len(groups) {
+			limitLine = groups[i+1][0].firstLine
+		}
+		if candidate, ok := lineSortCandidate("import group", fset, src, group, limitLine); ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// lineSortCandidate reorders elements that each occupy their own lines.
+// Each element owns the lines from its firstLine through the line it ends on.
+// limitLine is the first line after the elements that must not be moved.
+func lineSortCandidate(
+	kind string,
+	fset *token.FileSet,
+	src []byte,
+	elements []sortElement,
+	limitLine int,
+) (sortCandidate, bool) {
+	lastEndLine := fset.Position(elements[len(elements)-1].node.End()).Line
+	if lastEndLine >= limitLine {
+		return sortCandidate{}, false
+	}
+
+	tokenFile := fset.File(elements[0].node.Pos())
+	if tokenFile == nil {
+		return sortCandidate{}, false
+	}
+	lineStart := func(line int) int {
+		return tokenFile.Offset(tokenFile.LineStart(line))
+	}
+
+	chunks := make([]string, len(elements))
+	for i, element := range elements {
+		endLine := fset.Position(element.node.End()).Line
+		if element.firstLine > fset.Position(element.node.Pos()).Line {
+			return sortCandidate{}, false
+		}
+		chunks[i] = string(src[lineStart(element.firstLine):lineStart(endLine+1)])
+	}
+
+	return reorderedCandidate(
+		kind,
+		elements,
+		chunks,
+		lineStart(elements[0].firstLine),
+		lineStart(lastEndLine+1),
+		func(ordered []string) string { return strings.Join(ordered, "") },
+	)
+}
+
+// inlineSortCandidate reorders elements that share lines, keeping the separators between them.
+// Literals containing comments are skipped as the comments cannot be attributed reliably.
+func inlineSortCandidate(
+	kind string,
+	fset *token.FileSet,
+	src []byte,
+	elements []sortElement,
+	lbrace token.Pos,
+	rbrace token.Pos,
+) (sortCandidate, bool) {
+	start := fset.Position(lbrace).Offset + 1
+	end := fset.Position(rbrace).Offset
+	if bytes.Contains(src[start:end], []byte("//")) || bytes.Contains(src[start:end], []byte("/*")) {
+		return sortCandidate{}, false
+	}
+
+	chunks := make([]string, len(elements))
+	separators := make([]string, len(elements)-1)
+	for i, element := range elements {
+		chunks[i] = nodeSource(src, fset, element.node)
+		if i > 0 {
+			separators[i-1] = string(
+				src[fset.Position(elements[i-1].node.End()).Offset:fset.Position(element.node.Pos()).Offset],
+			)
+		}
+	}
+
+	return reorderedCandidate(
+		kind,
+		elements,
+		chunks,
+		fset.Position(elements[0].node.Pos()).Offset,
+		fset.Position(elements[len(elements)-1].node.End()).Offset,
+		func(ordered []string) string {
+			var b strings.Builder
+			for i, chunk := range ordered {
+				if i > 0 {
+					b.WriteString(separators[i-1])
+				}
+				b.WriteString(chunk)
+			}
+			return b.String()
+		},
+	)
+}
+
+// reorderedCandidate sorts the chunks by their element keys and returns a candidate
+// if the order changed
+func reorderedCandidate(
+	kind string,
+	elements []sortElement,
+	chunks []string,
+	start int,
+	end int,
+	join func([]string) string,
+) (sortCandidate, bool) {
+	order := make([]int, len(elements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return elements[order[i]].key.less(elements[order[j]].key)
+	})
+
+	changed := false
+	ordered := make([]string, len(order))
+	for i, index := range order {
+		if index != i {
+			changed = true
+		}
+		ordered[i] = chunks[index]
+	}
+	if !changed {
+		return sortCandidate{}, false
+	}
+
+	return sortCandidate{
+		kind:        kind,
+		start:       start,
+		end:         end,
+		replacement: join(ordered),
+	}, true
+}
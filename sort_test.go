@@ -0,0 +1,324 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	allOptions := SortOptions{SwitchCases: true, MapKeys: true, Imports: true}
+
+	// Helper function to write a Go file into a fresh temp directory
+	writeTestFile := func(t testing.TB, lines []string) string {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "main.go")
+		err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filePath
+	}
+
+	readFileContent := func(t testing.TB, filePath string) string {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Failed to read file %s: %v", filePath, err)
+		}
+		return string(content)
+	}
+
+	// assertOrder checks that the given snippets appear in the given order
+	assertOrder := func(t testing.TB, content string, snippets ...string) {
+		t.Helper()
+		last := -1
+		for _, snippet := range snippets {
+			index := strings.Index(content, snippet)
+			if index == -1 {
+				t.Fatalf("Expected %q in content:\n%s", snippet, content)
+			}
+			if index < last {
+				t.Fatalf("Expected %q after previous snippets in content:\n%s", snippet, content)
+			}
+			last = index
+		}
+	}
+
+	t.Run("sort switch cases", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{
+			"package testpkg",           // 1
+			"",                          // 2
+			"func Name(v int) string {", // 3
+			"\tswitch v {",              // 4
+			"\tdefault:",                // 5
+			"\t\treturn \"other\"",      // 6
+			"\t// three is the largest", // 7
+			"\tcase 3:",                 // 8
+			"\t\treturn \"three\"",      // 9
+			"\tcase 1:",                 // 10
+			"\t\treturn \"one\"",        // 11
+			"\tcase 10, 2:",             // 12
+			"\t\treturn \"ten or two\"", // 13
+			"\t}",                       // 14
+			"}",                         // 15
+			"",                          // 16
+		})
+
+		result, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Failed to sort switch: %v", err)
+		}
+		if !strings.Contains(result, "1 switch statement(s)") {
+			t.Errorf("Expected switch statement count in result, got: %s", result)
+		}
+
+		content := readFileContent(t, filePath)
+		assertOrder(t, content, "case 1:", "// three is the largest", "case 3:", "case 10, 2:", "default:")
+	})
+
+	t.Run("switch with fallthrough is untouched", func(t *testing.T) {
+		t.Parallel()
+		lines := []string{
+			"package testpkg",           // 1
+			"",                          // 2
+			"func Name(v int) string {", // 3
+			"\tswitch v {",              // 4
+			"\tcase 2:",                 // 5
+			"\t\tfallthrough",           // 6
+			"\tcase 1:",                 // 7
+			"\t\treturn \"small\"",      // 8
+			"\t}",                       // 9
+			"\treturn \"\"",             // 10
+			"}",                         // 11
+			"",                          // 12
+		}
+		filePath := writeTestFile(t, lines)
+
+		result, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(result, "already sorted") {
+			t.Errorf("Expected file to be reported as already sorted, got: %s", result)
+		}
+		if readFileContent(t, filePath) != strings.Join(lines, "\n") {
+			t.Error("Expected file content to be unchanged")
+		}
+	})
+
+	t.Run("sort map literal keys", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{
+			"package testpkg",            // 1
+			"",                           // 2
+			"var Ages = map[string]int{", // 3
+			"\t\"carol\": 3,",            // 4
+			"\t// alice is first",        // 5
+			"\t\"alice\": 1,",            // 6
+			"\t\"bob\":   2,",            // 7
+			"}",                          // 8
+			"",                           // 9
+			"var Small = map[int]string{3: \"c\", 1: \"a\", 2: \"b\"}", // 10
+			"", // 11
+		})
+
+		_, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Failed to sort map literals: %v", err)
+		}
+
+		content := readFileContent(t, filePath)
+		assertOrder(t, content, "// alice is first", "\"alice\": 1", "\"bob\":", "\"carol\": 3")
+		if !strings.Contains(content, "map[int]string{1: \"a\", 2: \"b\", 3: \"c\"}") {
+			t.Errorf("Expected inline map literal to be sorted, got:\n%s", content)
+		}
+	})
+
+	t.Run("map with side effects is untouched", func(t *testing.T) {
+		t.Parallel()
+		lines := []string{
+			"package testpkg",              // 1
+			"",                             // 2
+			"func next() int { return 1 }", // 3
+			"",                             // 4
+			"var Values = map[string]int{", // 5
+			"\t\"b\": next(),",             // 6
+			"\t\"a\": next(),",             // 7
+			"}",                            // 8
+			"",                             // 9
+		}
+		filePath := writeTestFile(t, lines)
+
+		_, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if readFileContent(t, filePath) != strings.Join(lines, "\n") {
+			t.Error("Expected map literal with function calls to be unchanged")
+		}
+	})
+
+	t.Run("literals are ordered by their constant value", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{
+			"package testpkg", // 1
+			"",                // 2
+			"var Modes = map[int]string{0x10: \"hex\", 010: \"octal\", 1_000: \"separated\", 0b11: \"binary\", 9: \"nine\"}", // 3
+			"", // 4
+		})
+
+		_, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Failed to sort map literal: %v", err)
+		}
+
+		content := readFileContent(t, filePath)
+		assertOrder(t, content, "0b11:", "010:", "9:", "0x10:", "1_000:")
+	})
+
+	t.Run("switch on variables is untouched", func(t *testing.T) {
+		t.Parallel()
+		lines := []string{
+			"package testpkg",           // 1
+			"",                          // 2
+			"var b, a = 1, 1",           // 3
+			"",                          // 4
+			"func Name(v int) string {", // 5
+			"\tswitch v {",              // 6
+			"\tcase b:",                 // 7
+			"\t\treturn \"b\"",          // 8
+			"\tcase a:",                 // 9
+			"\t\treturn \"a\"",          // 10
+			"\t}",                       // 11
+			"\treturn \"\"",             // 12
+			"}",                         // 13
+			"",                          // 14
+			"var Names = map[int]string{b: \"b\", a: \"a\"}", // 15
+			"", // 16
+		}
+		filePath := writeTestFile(t, lines)
+
+		result, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(result, "already sorted") {
+			t.Errorf("Expected file to be reported as already sorted, got: %s", result)
+		}
+		if readFileContent(t, filePath) != strings.Join(lines, "\n") {
+			t.Error("Expected the switch and map on variables to be unchanged")
+		}
+	})
+
+	t.Run("sort import groups separately", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{
+			"package testpkg",       // 1
+			"",                      // 2
+			"import (",              // 3
+			"\t\"strings\"",         // 4
+			"\t\"fmt\"",             // 5
+			"",                      // 6
+			"\t\"os/exec\"",         // 7
+			"\t\"errors\"",          // 8
+			")",                     // 9
+			"",                      // 10
+			"var _ = fmt.Sprint",    // 11
+			"var _ = strings.Split", // 12
+			"var _ = exec.Command",  // 13
+			"var _ = errors.New",    // 14
+			"",                      // 15
+		})
+
+		result, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Failed to sort imports: %v", err)
+		}
+		if !strings.Contains(result, "2 import group(s)") {
+			t.Errorf("Expected two import groups in result, got: %s", result)
+		}
+
+		content := readFileContent(t, filePath)
+		// The groups keep their relative order, only the specs within them are sorted
+		assertOrder(t, content, "\"fmt\"", "\"strings\"", "\"errors\"", "\"os/exec\"")
+	})
+
+	t.Run("nested constructs are sorted", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{
+			"package testpkg",              // 1
+			"",                             // 2
+			"var Nested = map[string]any{", // 3
+			"\t\"z\": map[string]int{",     // 4
+			"\t\t\"y\": 2,",                // 5
+			"\t\t\"x\": 1,",                // 6
+			"\t},",                         // 7
+			"\t\"a\": 0,",                  // 8
+			"}",                            // 9
+			"",                             // 10
+		})
+
+		_, err := Sort(context.Background(), filePath, "", allOptions)
+		if err != nil {
+			t.Fatalf("Failed to sort nested literals: %v", err)
+		}
+
+		content := readFileContent(t, filePath)
+		assertOrder(t, content, "\"a\": 0", "\"z\":", "\"x\": 1", "\"y\": 2")
+	})
+
+	t.Run("restrict to symbol", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{
+			"package testpkg", // 1
+			"",                // 2
+			"var First = map[string]int{\"b\": 2, \"a\": 1}", // 3
+			"", // 4
+			"var Second = map[string]int{\"b\": 2, \"a\": 1}", // 5
+			"", // 6
+		})
+
+		_, err := Sort(context.Background(), filePath, "Second", allOptions)
+		if err != nil {
+			t.Fatalf("Failed to sort symbol: %v", err)
+		}
+
+		content := readFileContent(t, filePath)
+		if !strings.Contains(content, "First = map[string]int{\"b\": 2, \"a\": 1}") {
+			t.Error("Expected First to be unchanged")
+		}
+		if !strings.Contains(content, "Second = map[string]int{\"a\": 1, \"b\": 2}") {
+			t.Error("Expected Second to be sorted")
+		}
+
+		_, err = Sort(context.Background(), filePath, "Missing", allOptions)
+		if err == nil {
+			t.Error("Expected error for missing symbol")
+		}
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+		filePath := writeTestFile(t, []string{"package testpkg", ""})
+
+		_, err := Sort(context.Background(), "", "", allOptions)
+		if err == nil || !strings.Contains(err.Error(), "file path cannot be empty") {
+			t.Errorf("Expected empty file path error, got: %v", err)
+		}
+
+		_, err = Sort(context.Background(), filePath, "", SortOptions{})
+		if err == nil || !strings.Contains(err.Error(), "nothing to sort") {
+			t.Errorf("Expected nothing to sort error, got: %v", err)
+		}
+
+		_, err = Sort(context.Background(), filepath.Join(filepath.Dir(filePath), "missing.go"), "", allOptions)
+		if err == nil {
+			t.Error("Expected error for non-existent file")
+		}
+	})
+}
@@ -0,0 +1,465 @@
+package go_mcp_tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	apiStabilityToolName        = "api_stability"
+	apiStabilityToolDescription = `Reports the stability of the exported API of Go packages from the markers in doc comments, and the stable API that depends on experimental API.
+
+Markers, in order of precedence:
+- a paragraph starting with "Deprecated: " marks a symbol deprecated, the convention of go doc and staticcheck
+- a line starting with "Experimental" or "EXPERIMENTAL" marks it experimental
+- "Stable since v1.2.0" marks it stable since that version
+
+Methods without markers take the stability of their type, and symbols of a const, var or type group that of the group. A marker in the package doc comment applies to every symbol of the package without one.
+
+A violation is a stable symbol whose declaration or body refers to an experimental symbol of the listed packages, e.g. a stable function calling an experimental one or a stable struct with a field of an experimental type. Pass ./... to check references across the packages of a module.`
+)
+
+// StabilityLevel is the stability of a symbol according to the markers in its doc comment
+type StabilityLevel string
+
+const (
+	StabilityStable       StabilityLevel = "stable"
+	StabilityExperimental StabilityLevel = "experimental"
+	StabilityDeprecated   StabilityLevel = "deprecated"
+	// StabilityUnmarked is the level of symbols without markers
+	StabilityUnmarked StabilityLevel = "unmarked"
+)
+
+var (
+	deprecatedMarker   = regexp.MustCompile(`(?m)^Deprecated: `)
+	experimentalMarker = regexp.MustCompile(`(?m)^(Experimental|EXPERIMENTAL)\b`)
+	stableMarker       = regexp.MustCompile(`\bStable since:? (v?[0-9]+(?:\.[0-9]+)*)`)
+)
+
+// StabilityReport is the stability surface of packages and the stable API that depends
+// on experimental API
+type StabilityReport struct {
+	Packages   []string             `json:"packages"`
+	Symbols    []StabilitySymbol    `json:"symbols"`
+	Violations []StabilityViolation `json:"violations,omitempty"`
+}
+
+// StabilitySymbol is an exported symbol with the stability of its doc comment
+type StabilitySymbol struct {
+	// Name is the name of the symbol, prefixed by the type name for methods
+	Name    string         `json:"name"`
+	Package string         `json:"package"`
+	Level   StabilityLevel `json:"level"`
+	// Since is the version of "Stable since" markers
+	Since string `json:"since,omitempty"`
+	// Note is the deprecation paragraph of deprecated symbols
+	Note string `json:"note,omitempty"`
+	// Inherited is set when the level is that of the type, group or package of the symbol
+	Inherited bool `json:"inherited,omitempty"`
+	// File is relative to the module root
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// StabilityViolation is a reference of stable API to experimental API
+type StabilityViolation struct {
+	Symbol string `json:"symbol"`
+	Uses   string `json:"uses"`
+	// File and Line locate the reference, File is relative to the module root
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// stabilityMarker is the stability parsed from a doc comment
+type stabilityMarker struct {
+	level StabilityLevel
+	since string
+	note  string
+}
+
+func AddAPIStabilityTool(mcpServer *server.MCPServer) {
+	handleAPIStability := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := request.RequireString("package")
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := APIStability(ctx, workspaceDir, pattern)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error reporting API stability: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		apiStabilityToolName,
+		mcp.WithDescription(apiStabilityToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module the package is resolved from"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Import path or relative directory of the package, e.g. ./internal/store. Patterns like ./... report several packages."),
+			mcp.Required(),
+		),
+	), handleAPIStability)
+}
+
+// APIStability reports the stability markers of the exported symbols of the packages
+// matching pattern, resolved from workspaceDir, and the references of stable symbols to
+// experimental ones
+func APIStability(ctx context.Context, workspaceDir string, pattern string) (*StabilityReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir: workspaceDir,
+		Env: packagesEnv(workspaceDir),
+	}, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pattern, err)
+	}
+
+	report := &StabilityReport{}
+	// Symbols are keyed by stabilityKey, as the objects of a package differ between the
+	// packages importing it
+	symbols := make(map[string]*StabilitySymbol)
+	var checked []*packages.Package
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors))
+		}
+		if pkg.Name == "main" {
+			continue
+		}
+		report.Packages = append(report.Packages, pkg.PkgPath)
+		checked = append(checked, pkg)
+		for _, symbol := range packageStability(pkg, module) {
+			symbols[symbol.key] = &symbol.StabilitySymbol
+			report.Symbols = append(report.Symbols, symbol.StabilitySymbol)
+		}
+	}
+	if len(report.Packages) == 0 {
+		return nil, fmt.Errorf("no library packages match %s", pattern)
+	}
+
+	seen := make(map[[2]string]bool)
+	for _, pkg := range checked {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				for node, obj := range stabilityDecls(pkg, decl) {
+					key := stabilityKey(obj)
+					symbol := symbols[key]
+					if symbol == nil || symbol.Level != StabilityStable {
+						continue
+					}
+					ast.Inspect(node, func(node ast.Node) bool {
+						ident, ok := node.(*ast.Ident)
+						if !ok {
+							return true
+						}
+						usedKey := stabilityKey(pkg.TypesInfo.Uses[ident])
+						used := symbols[usedKey]
+						if used == nil || used.Level != StabilityExperimental || seen[[2]string{key, usedKey}] {
+							return true
+						}
+						seen[[2]string{key, usedKey}] = true
+						position := pkg.Fset.Position(ident.Pos())
+						report.Violations = append(report.Violations, StabilityViolation{
+							Symbol: stabilityName(symbol.Package, symbol.Name, pkg.PkgPath),
+							Uses:   stabilityName(used.Package, used.Name, pkg.PkgPath),
+							File:   module.relPath(position.Filename),
+							Line:   position.Line,
+						})
+						return true
+					})
+				}
+			}
+		}
+	}
+
+	slices.Sort(report.Packages)
+	slices.SortFunc(report.Symbols, func(a, b StabilitySymbol) int {
+		return cmp.Or(strings.Compare(a.Package, b.Package), strings.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(report.Violations, func(a, b StabilityViolation) int {
+		return cmp.Or(strings.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return report, nil
+}
+
+// keyedStabilitySymbol is a symbol with the key references to it are matched by
+type keyedStabilitySymbol struct {
+	StabilitySymbol
+	key string
+}
+
+// packageStability returns the exported symbols of the package with their stability
+func packageStability(pkg *packages.Package, module *moduleSources) []keyedStabilitySymbol {
+	var packageMarker stabilityMarker
+	for _, file := range pkg.Syntax {
+		if file.Doc != nil {
+			if marker := parseStabilityMarker(file.Doc.Text()); marker.level != StabilityUnmarked {
+				packageMarker = marker
+			}
+		}
+	}
+
+	// Types are marked before their methods, which may be declared before them
+	typeMarkers := make(map[string]stabilityMarker)
+	var methods []*ast.FuncDecl
+	var symbols []keyedStabilitySymbol
+	add := func(obj types.Object, name string, marker stabilityMarker, inherited ...stabilityMarker) {
+		if !obj.Exported() {
+			return
+		}
+		isInherited := false
+		for _, outer := range append(inherited, packageMarker) {
+			if marker.level == StabilityUnmarked && outer.level != "" && outer.level != StabilityUnmarked {
+				marker = outer
+				isInherited = true
+			}
+		}
+		position := pkg.Fset.Position(obj.Pos())
+		symbols = append(symbols, keyedStabilitySymbol{
+			StabilitySymbol: StabilitySymbol{
+				Name:      name,
+				Package:   pkg.PkgPath,
+				Level:     marker.level,
+				Since:     marker.since,
+				Note:      marker.note,
+				Inherited: isInherited,
+				File:      module.relPath(position.Filename),
+				Line:      position.Line,
+			},
+			key: stabilityKey(obj),
+		})
+	}
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv != nil {
+					methods = append(methods, decl)
+					continue
+				}
+				if obj := pkg.TypesInfo.Defs[decl.Name]; obj != nil {
+					add(obj, decl.Name.Name, parseStabilityMarker(decl.Doc.Text()))
+				}
+			case *ast.GenDecl:
+				group := parseStabilityMarker(decl.Doc.Text())
+				if !decl.Lparen.IsValid() {
+					// The doc comment of a single spec is that of the declaration
+					group = stabilityMarker{level: StabilityUnmarked}
+				}
+				specDoc := func(doc *ast.CommentGroup) string {
+					if !decl.Lparen.IsValid() {
+						return decl.Doc.Text()
+					}
+					return doc.Text()
+				}
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						marker := parseStabilityMarker(specDoc(spec.Doc))
+						if marker.level == StabilityUnmarked {
+							marker = group
+						}
+						if marker.level == StabilityUnmarked {
+							marker = packageMarker
+						}
+						typeMarkers[spec.Name.Name] = marker
+						if obj := pkg.TypesInfo.Defs[spec.Name]; obj != nil {
+							add(obj, spec.Name.Name, parseStabilityMarker(specDoc(spec.Doc)), group)
+						}
+					case *ast.ValueSpec:
+						marker := parseStabilityMarker(specDoc(spec.Doc))
+						for _, name := range spec.Names {
+							if obj := pkg.TypesInfo.Defs[name]; obj != nil {
+								add(obj, name.Name, marker, group)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, decl := range methods {
+		obj := pkg.TypesInfo.Defs[decl.Name]
+		typeName := receiverTypeName(decl.Recv.List[0].Type)
+		if obj == nil || !token.IsExported(typeName) {
+			continue
+		}
+		add(obj, typeName+"."+decl.Name.Name, parseStabilityMarker(decl.Doc.Text()), typeMarkers[typeName])
+	}
+	return symbols
+}
+
+// stabilityDecls returns the nodes of the declaration whose references are checked with
+// the objects they declare: a function with its signature and body, or the specs of a
+// type, const or var declaration
+func stabilityDecls(pkg *packages.Package, decl ast.Decl) map[ast.Node]types.Object {
+	nodes := make(map[ast.Node]types.Object)
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if obj := pkg.TypesInfo.Defs[decl.Name]; obj != nil {
+			nodes[decl] = obj
+		}
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if obj := pkg.TypesInfo.Defs[spec.Name]; obj != nil {
+					nodes[spec] = obj
+				}
+			case *ast.ValueSpec:
+				// The specs of several names are attributed to the first one
+				if len(spec.Names) > 0 {
+					if obj := pkg.TypesInfo.Defs[spec.Names[0]]; obj != nil {
+						nodes[spec] = obj
+					}
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+// parseStabilityMarker returns the stability of a doc comment, StabilityUnmarked when it
+// has no markers
+func parseStabilityMarker(doc string) stabilityMarker {
+	if loc := deprecatedMarker.FindStringIndex(doc); loc != nil {
+		note, _, _ := strings.Cut(doc[loc[1]:], "\n\n")
+		return stabilityMarker{level: StabilityDeprecated, note: strings.Join(strings.Fields(note), " ")}
+	}
+	if experimentalMarker.MatchString(doc) {
+		return stabilityMarker{level: StabilityExperimental}
+	}
+	if match := stableMarker.FindStringSubmatch(doc); match != nil {
+		since := match[1]
+		if !strings.HasPrefix(since, "v") {
+			since = "v" + since
+		}
+		return stabilityMarker{level: StabilityStable, since: since}
+	}
+	return stabilityMarker{level: StabilityUnmarked}
+}
+
+// stabilityKey identifies a package level object or method across the packages of a
+// load, e.g. example.com/store.DB.Get. Other objects have no key.
+func stabilityKey(obj types.Object) string {
+	if obj == nil || obj.Pkg() == nil {
+		return ""
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		fn = fn.Origin()
+		recv := fn.Type().(*types.Signature).Recv()
+		if recv == nil {
+			return fn.Pkg().Path() + "." + fn.Name()
+		}
+		t := recv.Type()
+		if pointer, ok := t.(*types.Pointer); ok {
+			t = pointer.Elem()
+		}
+		named, ok := t.(*types.Named)
+		if !ok {
+			return ""
+		}
+		return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+	}
+	if obj.Parent() != obj.Pkg().Scope() {
+		return ""
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// stabilityName qualifies the name of a symbol of another package than from
+func stabilityName(pkgPath string, name string, from string) string {
+	if pkgPath == from {
+		return name
+	}
+	return path.Base(pkgPath) + "." + name
+}
+
+func (report *StabilityReport) String() string {
+	var b strings.Builder
+	levels := []StabilityLevel{StabilityStable, StabilityExperimental, StabilityDeprecated, StabilityUnmarked}
+	for _, pkgPath := range report.Packages {
+		counts := make(map[StabilityLevel]int)
+		for _, symbol := range report.Symbols {
+			if symbol.Package == pkgPath {
+				counts[symbol.Level]++
+			}
+		}
+		fmt.Fprintf(
+			&b,
+			"%s: %d stable, %d experimental, %d deprecated, %d unmarked\n",
+			pkgPath, counts[StabilityStable], counts[StabilityExperimental], counts[StabilityDeprecated], counts[StabilityUnmarked],
+		)
+		for _, level := range levels[:3] {
+			for _, symbol := range report.Symbols {
+				if symbol.Package != pkgPath || symbol.Level != level {
+					continue
+				}
+				fmt.Fprintf(&b, "  %s %s (%s:%d)", level, symbol.Name, symbol.File, symbol.Line)
+				if symbol.Since != "" {
+					fmt.Fprintf(&b, " since %s", symbol.Since)
+				}
+				if symbol.Inherited {
+					b.WriteString(", inherited")
+				}
+				if symbol.Note != "" {
+					fmt.Fprintf(&b, ": %s", symbol.Note)
+				}
+				b.WriteString("\n")
+			}
+		}
+		var unmarked []string
+		for _, symbol := range report.Symbols {
+			if symbol.Package == pkgPath && symbol.Level == StabilityUnmarked {
+				unmarked = append(unmarked, symbol.Name)
+			}
+		}
+		if len(unmarked) > 0 {
+			fmt.Fprintf(&b, "  unmarked: %s\n", strings.Join(unmarked, ", "))
+		}
+	}
+
+	if len(report.Violations) == 0 {
+		b.WriteString("\nNo stable API refers to experimental API\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n%d violations, stable API referring to experimental API:\n", len(report.Violations))
+	for _, violation := range report.Violations {
+		fmt.Fprintf(&b, "  %s:%d: stable %s uses experimental %s\n", violation.File, violation.Line, violation.Symbol, violation.Uses)
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAPIStability(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/app", "", "go 1.22", ""},
+		"store/store.go": {
+			"// Package store stores values", // 1
+			"package store",
+			"",
+			"import \"example.com/app/beta\"", // 4
+			"",
+			"// DB is a database", // 6
+			"//",
+			"// Stable since v1.2.0",
+			"type DB struct {",
+			"\tCache *Cache", // 10
+			"}",
+			"",
+			"// Experimental: the cache may change", // 13
+			"type Cache struct{}",
+			"",
+			"// Open opens a database. Stable since 1.0", // 16
+			"func Open() *DB {",
+			"\tbeta.New()",
+			"\treturn &DB{}",
+			"}",
+			"",
+			"// Compact compacts the database", // 22
+			"//",
+			"// EXPERIMENTAL",
+			"func (db *DB) Compact() {}",
+			"",
+			"// Get gets a value", // 27
+			"func (db *DB) Get() { db.Compact() }",
+			"",
+			"// OpenFile opens a database in a file", // 30
+			"//",
+			"// Deprecated: Use Open instead,",
+			"// it takes options.",
+			"func OpenFile() *DB { return Open() }",
+			"",
+			"func Close() {}", // 36
+			"",
+			"// Experimental limits", // 38
+			"const (",
+			"\tMaxKeys = 1",
+			"\tMaxValues = 2",
+			")",
+			"",
+		},
+		"beta/beta.go": {
+			"// Package beta has new APIs.", // 1
+			"//",
+			"// Experimental: they may change.",
+			"package beta",
+			"",
+			"func New() {}", // 6
+			"",
+			"// Old is replaced by New",
+			"//",
+			"// Deprecated: Use New.",
+			"func Old() {}", // 11
+			"",
+		},
+	})
+
+	t.Run("report", func(t *testing.T) {
+		t.Parallel()
+		report, err := APIStability(context.Background(), workspace, "./...")
+		if err != nil {
+			t.Fatalf("Failed to report the stability: %v", err)
+		}
+		expected := strings.Join([]string{
+			"example.com/app/beta: 0 stable, 1 experimental, 1 deprecated, 0 unmarked",
+			"  experimental New (beta/beta.go:6), inherited",
+			"  deprecated Old (beta/beta.go:11): Use New.",
+			"example.com/app/store: 3 stable, 4 experimental, 1 deprecated, 1 unmarked",
+			"  stable DB (store/store.go:9) since v1.2.0",
+			"  stable DB.Get (store/store.go:28) since v1.2.0, inherited",
+			"  stable Open (store/store.go:17) since v1.0",
+			"  experimental Cache (store/store.go:14)",
+			"  experimental DB.Compact (store/store.go:25)",
+			"  experimental MaxKeys (store/store.go:40), inherited",
+			"  experimental MaxValues (store/store.go:41), inherited",
+			"  deprecated OpenFile (store/store.go:34): Use Open instead, it takes options.",
+			"  unmarked: Close",
+			"",
+			"3 violations, stable API referring to experimental API:",
+			"  store/store.go:10: stable DB uses experimental Cache",
+			"  store/store.go:18: stable Open uses experimental beta.New",
+			"  store/store.go:28: stable DB.Get uses experimental DB.Compact",
+			"",
+		}, "\n")
+		if report.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, report.String())
+		}
+	})
+
+	t.Run("markers", func(t *testing.T) {
+		t.Parallel()
+		for doc, expected := range map[string]stabilityMarker{
+			"Get gets a value\n":                                 {level: StabilityUnmarked},
+			"Get is not experimental\n":                          {level: StabilityUnmarked},
+			"Get gets a value.\n\nStable since: v2.1.0\n":        {level: StabilityStable, since: "v2.1.0"},
+			"Get gets a value.\nExperimental. Stable since v1\n": {level: StabilityExperimental},
+			"Get gets.\n\nDeprecated: Use Fetch.\n\nMore.\n":     {level: StabilityDeprecated, note: "Use Fetch."},
+		} {
+			if marker := parseStabilityMarker(doc); marker != expected {
+				t.Errorf("Expected %+v for %q, got %+v", expected, doc, marker)
+			}
+		}
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		t.Parallel()
+		if _, err := APIStability(context.Background(), "store", "./..."); err == nil || !strings.Contains(err.Error(), "must be an absolute path") {
+			t.Errorf("Expected a relative path to be refused, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,246 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	stressToolName        = "stress"
+	stressToolDescription = `Hunts data races in the tests of a Go package by running them repeatedly with the race detector, each iteration with a random GOMAXPROCS and test order (go test -race -shuffle), so races depending on scheduling and on the order tests share state in show up.
+
+The races of all iterations are aggregated: each unique race, identified by the source positions of its conflicting accesses, is reported once with the number of iterations it occurred in, the command reproducing its first occurrence, and its accesses and goroutine creations mapped to source like the race tool reports them. Pass seed to repeat the same sequence of GOMAXPROCS values and test orders.`
+
+	// defaultStressIterations is the number of test runs unless set
+	defaultStressIterations = 10
+)
+
+func AddStressTool(mcpServer *server.MCPServer) {
+	handleStress := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		options := StressOptions{WorkspaceDir: workspaceDir}
+		options.Package = request.GetString("package", "")
+		options.Run = request.GetString("run", "")
+		options.Iterations = request.GetInt("iterations", 0)
+		options.Seed = uint64(request.GetInt("seed", 0))
+
+		report, err := StressTests(ctx, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error stress testing: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		stressToolName,
+		mcp.WithDescription(stressToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+		mcp.WithString("package",
+			mcp.Description("Package whose tests to run, relative to the module root"),
+			mcp.DefaultString("."),
+		),
+		mcp.WithString("run",
+			mcp.Description("Regular expression selecting the tests to run, as go test -run"),
+		),
+		mcp.WithNumber("iterations",
+			mcp.Description("Number of times to run the tests"),
+			mcp.DefaultNumber(defaultStressIterations),
+		),
+		mcp.WithNumber("seed",
+			mcp.Description("Seed of the random GOMAXPROCS values and test orders, random when 0"),
+		),
+	), handleStress)
+}
+
+// StressOptions select the tests to stress and how often
+type StressOptions struct {
+	WorkspaceDir string
+	// Package is the package whose tests to run, . by default
+	Package string
+	// Run selects the tests to run, all when empty
+	Run string
+	// Iterations is the number of test runs, defaultStressIterations by default
+	Iterations int
+	// Seed makes the GOMAXPROCS values and test orders reproducible, random when 0
+	Seed uint64
+}
+
+// StressReport aggregates the data races of repeated race detector runs
+type StressReport struct {
+	Package string `json:"package"`
+	Seed    uint64 `json:"seed"`
+	// Iterations are the runs in order
+	Iterations []StressIteration `json:"iterations"`
+	Races      []StressRace      `json:"races,omitempty"`
+	// FailedTests are the tests failing in any iteration
+	FailedTests []string `json:"failed_tests,omitempty"`
+}
+
+// StressIteration is a run of the tests with the race detector
+type StressIteration struct {
+	GOMAXPROCS int   `json:"gomaxprocs"`
+	Shuffle    int64 `json:"shuffle"`
+	// Command reproduces the iteration
+	Command string `json:"command"`
+	Races   int    `json:"races"`
+	Passed  bool   `json:"passed"`
+}
+
+// StressRace is a unique data race with the iterations it occurred in
+type StressRace struct {
+	DataRace
+	// Occurrences is the number of iterations the race occurred in
+	Occurrences int `json:"occurrences"`
+	// First is the index of the first iteration the race occurred in
+	First int `json:"first"`
+}
+
+// StressTests runs the tests of options.Package repeatedly with the race detector, a
+// random GOMAXPROCS and a random test order, and aggregates the data races found
+func StressTests(ctx context.Context, options StressOptions) (*StressReport, error) {
+	if !filepath.IsAbs(options.WorkspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", options.WorkspaceDir)
+	}
+	root, err := findModuleRoot(options.WorkspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	if options.Package == "" {
+		options.Package = "."
+	}
+	if options.Iterations <= 0 {
+		options.Iterations = defaultStressIterations
+	}
+	if options.Seed == 0 {
+		options.Seed = rand.Uint64()
+	}
+	random := rand.New(rand.NewPCG(options.Seed, options.Seed))
+	maxProcs := max(2*runtime.NumCPU(), 4)
+
+	report := &StressReport{Package: options.Package, Seed: options.Seed}
+	indexes := make(map[string]int)
+	seenTests := make(map[string]bool)
+	for i := range options.Iterations {
+		iteration := StressIteration{
+			GOMAXPROCS: 1 + random.IntN(maxProcs),
+			Shuffle:    random.Int64N(1 << 31),
+		}
+		args := []string{"test", "-race", "-count", "1", "-shuffle", strconv.FormatInt(iteration.Shuffle, 10)}
+		if options.Run != "" {
+			args = append(args, "-run", options.Run)
+		}
+		args = append(args, options.Package)
+		env := []string{"GOMAXPROCS=" + strconv.Itoa(iteration.GOMAXPROCS)}
+
+		run, err := runRaceTests(ctx, root, args, env)
+		if err != nil {
+			return nil, err
+		}
+		iteration.Command = env[0] + " " + run.Command
+		iteration.Races = len(run.Races)
+		iteration.Passed = run.Passed
+		report.Iterations = append(report.Iterations, iteration)
+		for _, test := range run.FailedTests {
+			if !seenTests[test] {
+				seenTests[test] = true
+				report.FailedTests = append(report.FailedTests, test)
+			}
+		}
+		// A race reported twice in one run, e.g. by several tests, occurs once in the iteration
+		counted := make(map[string]bool)
+		for _, race := range run.Races {
+			key := raceKey(race)
+			index, ok := indexes[key]
+			if !ok {
+				index = len(report.Races)
+				indexes[key] = index
+				report.Races = append(report.Races, StressRace{DataRace: race, First: i})
+			}
+			if !counted[key] {
+				counted[key] = true
+				report.Races[index].Occurrences++
+			}
+		}
+	}
+	slices.SortStableFunc(report.Races, func(a, b StressRace) int {
+		return b.Occurrences - a.Occurrences
+	})
+	return report, nil
+}
+
+// raceKey identifies a data race by the positions of its conflicting accesses, which
+// are the same whichever goroutine the race detector sees access first
+func raceKey(race DataRace) string {
+	var positions []string
+	for _, stack := range race.Stacks {
+		if strings.HasPrefix(stack.Title, "Goroutine ") {
+			continue
+		}
+		position := ""
+		if len(stack.Frames) > 0 {
+			frame := stack.Frames[0]
+			position = fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)
+		}
+		positions = append(positions, position)
+	}
+	slices.Sort(positions)
+	return strings.Join(positions, "\n")
+}
+
+func (report *StressReport) String() string {
+	var b strings.Builder
+	racy := 0
+	for _, iteration := range report.Iterations {
+		if iteration.Races > 0 {
+			racy++
+		}
+	}
+	if len(report.Races) == 0 {
+		fmt.Fprintf(&b, "No data races in %d iterations of go test -race -shuffle %s (seed %d)\n",
+			len(report.Iterations), report.Package, report.Seed)
+	} else {
+		fmt.Fprintf(&b, "%d unique data races in %d of %d iterations of go test -race -shuffle %s (seed %d)\n",
+			len(report.Races), racy, len(report.Iterations), report.Package, report.Seed)
+	}
+	if len(report.FailedTests) > 0 {
+		fmt.Fprintf(&b, "Failed tests: %s\n", strings.Join(report.FailedTests, ", "))
+	}
+	b.WriteString("\nIterations:\n")
+	for i, iteration := range report.Iterations {
+		status := "ok"
+		switch {
+		case iteration.Races > 0:
+			status = fmt.Sprintf("%d races", iteration.Races)
+		case !iteration.Passed:
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "  %d. GOMAXPROCS=%d -shuffle %d: %s\n", i+1, iteration.GOMAXPROCS, iteration.Shuffle, status)
+	}
+	for i := range report.Races {
+		race := &report.Races[i]
+		fmt.Fprintf(&b, "\nData race %d, in %d of %d iterations, first reproduced by:\n  %s\n",
+			i+1, race.Occurrences, len(report.Iterations), report.Iterations[race.First].Command)
+		writeDataRace(&b, &race.DataRace)
+	}
+	return b.String()
+}
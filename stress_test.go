@@ -0,0 +1,103 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStress(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/stress", "", "go 1.22", ""},
+		"cache.go": {
+			"package stress", // 1
+			"",
+			"var hits int", // 3
+			"",
+			"func Hit() {", // 5
+			"	hits++",
+			"}",
+			"",
+		},
+		"cache_test.go": {
+			"package stress", // 1
+			"",
+			"import \"testing\"", // 3
+			"",
+			"func TestHit(t *testing.T) {", // 5
+			"	done := make(chan bool)",
+			"	go func() {",
+			"		Hit()",
+			"		done <- true",
+			"	}()", // 10
+			"	Hit()",
+			"	<-done",
+			"}",
+			"",
+			"func TestPass(t *testing.T) {}", // 15
+			"",
+		},
+	})
+
+	t.Run("aggregates races", func(t *testing.T) {
+		t.Parallel()
+		report, err := StressTests(context.Background(), StressOptions{WorkspaceDir: workspace, Iterations: 3})
+		if err != nil {
+			t.Fatalf("Failed to stress tests: %v", err)
+		}
+		if len(report.Iterations) != 3 || len(report.Races) != 1 || report.Races[0].Occurrences == 0 {
+			t.Fatalf("Expected one unique race over 3 iterations, got:\n%s", report)
+		}
+		if strings.Join(report.FailedTests, ",") != "TestHit" {
+			t.Errorf("Expected TestHit to fail, got %v", report.FailedTests)
+		}
+		text := report.String()
+		first := report.Iterations[report.Races[0].First]
+		for _, expected := range []string{
+			"1 unique data races in ",
+			"\nIterations:\n  1. GOMAXPROCS=",
+			"first reproduced by:\n  GOMAXPROCS=",
+			first.Command,
+			"    example.com/stress.Hit() (cache.go:6)\n      hits++\n",
+		} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Expected the report to contain %q, got:\n%s", expected, text)
+			}
+		}
+		if !strings.Contains(first.Command, "go test -race -count 1 -shuffle ") || !strings.HasSuffix(first.Command, " .") {
+			t.Errorf("Unexpected command %q", first.Command)
+		}
+	})
+
+	t.Run("seed", func(t *testing.T) {
+		t.Parallel()
+		options := StressOptions{WorkspaceDir: workspace, Run: "TestPass", Iterations: 2, Seed: 42}
+		first, err := StressTests(context.Background(), options)
+		if err != nil {
+			t.Fatalf("Failed to stress tests: %v", err)
+		}
+		second, err := StressTests(context.Background(), options)
+		if err != nil {
+			t.Fatalf("Failed to stress tests: %v", err)
+		}
+		if first.String() != second.String() || !strings.HasPrefix(first.String(), "No data races in 2 iterations of go test -race -shuffle . (seed 42)\n") {
+			t.Errorf("Expected the same iterations for the same seed, got:\n%s\nand:\n%s", first, second)
+		}
+	})
+
+	t.Run("race key", func(t *testing.T) {
+		t.Parallel()
+		write := RaceStack{Title: "Write at 0x1 by goroutine 8", Frames: []RaceFrame{{Function: "a.Hit()", File: "cache.go", Line: 6}}}
+		read := RaceStack{Title: "Previous read at 0x1 by goroutine 7", Frames: []RaceFrame{{Function: "a.Get()", File: "cache.go", Line: 10}}}
+		created := RaceStack{Title: "Goroutine 8 (running) created at", Frames: []RaceFrame{{Function: "a.TestHit()", File: "cache_test.go", Line: 7}}}
+		if raceKey(DataRace{Stacks: []RaceStack{write, read, created}}) != raceKey(DataRace{Stacks: []RaceStack{read, write}}) {
+			t.Error("Expected the same key whichever access is reported first")
+		}
+		other := RaceStack{Title: "Write at 0x2 by goroutine 9", Frames: []RaceFrame{{Function: "a.Hit()", File: "cache.go", Line: 7}}}
+		if raceKey(DataRace{Stacks: []RaceStack{write, read}}) == raceKey(DataRace{Stacks: []RaceStack{other, read}}) {
+			t.Error("Expected different keys for different accesses")
+		}
+	})
+}
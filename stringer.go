@@ -0,0 +1,305 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	generateStringerToolName        = "generate_stringer"
+	generateStringerToolDescription = `Generates a String() method for a named integer type whose constants are declared in an iota based const group, like the stringer command. String returns the name of the constant of a value, the first one for duplicate values, and the type name with the number for other values, e.g. Color(7). Pass marshal_text=true to also generate a MarshalText method for encoding/json and other text encodings.
+
+Without a type, the methods are generated for every type of the iota const groups declared in the file. The code of each type is written to <type>_string.go next to the file, replacing an earlier generated file, or only returned with return_code=true.`
+
+	// stringerGeneratedHeader marks the files written by the tool, which it may replace
+	stringerGeneratedHeader = "// Code generated by go-mcp-tools generate_stringer; DO NOT EDIT."
+)
+
+func AddGenerateStringerTool(mcpServer *server.MCPServer) {
+	handleGenerateStringer := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		var options StringerOptions
+		options.TypeName = request.GetString("type", "")
+		options.MarshalText = request.GetBool("marshal_text", false)
+		returnCode := request.GetBool("return_code", false)
+		options.Write = !returnCode
+
+		result, err := GenerateStringer(ctx, filePath, options)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error generating String methods: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		generateStringerToolName,
+		mcp.WithDescription(generateStringerToolDescription),
+		editingToolAnnotation(false, true),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the constants"),
+			mcp.Required(),
+		),
+		mcp.WithString("type",
+			mcp.Description("Name of the integer type to generate String for (default: all types of the iota const groups in the file)"),
+		),
+		mcp.WithBoolean("marshal_text",
+			mcp.Description("Whether to also generate a MarshalText method encoding the value as its name"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("return_code",
+			mcp.Description("Whether to only return the generated code instead of writing the _string.go files"),
+			mcp.DefaultBool(false),
+		),
+	), handleGenerateStringer)
+}
+
+// StringerOptions configures GenerateStringer
+type StringerOptions struct {
+	// TypeName is the type to generate the methods of, all types of the iota const groups
+	// declared in the file when empty
+	TypeName string
+	// MarshalText also generates a MarshalText method returning the name of the value
+	MarshalText bool
+	// Write writes the code of every type to <type>_string.go in the directory of the
+	// file instead of returning it
+	Write bool
+}
+
+// stringerConstant is a constant named in the String method of its type
+type stringerConstant struct {
+	name  string
+	value constant.Value
+	pos   token.Pos
+}
+
+// GenerateStringer generates the String method of integer types with iota based constants
+// declared in the package of filePath
+func GenerateStringer(ctx context.Context, filePath string, options StringerOptions) (string, error) {
+	if !filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if strings.HasSuffix(filePath, "_test.go") {
+		return "", fmt.Errorf("%s is a test file, String methods are generated for constants of the package", filePath)
+	}
+	pkg, file, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return "", err
+	}
+	if len(pkg.Errors) > 0 {
+		return "", classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+
+	var named []*types.Named
+	if options.TypeName != "" {
+		obj, ok := pkg.Types.Scope().Lookup(options.TypeName).(*types.TypeName)
+		if !ok {
+			return "", classifyErrorf(ErrSymbolNotFound, "no type '%s' declared in package %s", options.TypeName, pkg.PkgPath)
+		}
+		t, ok := obj.Type().(*types.Named)
+		if !ok || !isStringerType(t) {
+			return "", fmt.Errorf("'%s' is not a named integer type", options.TypeName)
+		}
+		named = append(named, t)
+	} else {
+		named = iotaTypes(pkg, file)
+		if len(named) == 0 {
+			return "", fmt.Errorf("no iota based const groups of named integer types declared in %s", filePath)
+		}
+	}
+
+	var b strings.Builder
+	for i, t := range named {
+		name := t.Obj().Name()
+		outputPath := filepath.Join(filepath.Dir(filePath), strings.ToLower(name)+"_string.go")
+		constants := stringerConstants(pkg, t, outputPath)
+		if len(constants) == 0 {
+			return "", fmt.Errorf("no constants of type %s declared in package %s", name, pkg.PkgPath)
+		}
+		if err := checkStringerMethods(pkg, t, outputPath, options.MarshalText); err != nil {
+			return "", err
+		}
+		receiverName, _ := stubReceiver(pkg, file, t)
+		code, err := stringerCode(pkg.Name, t, receiverName, constants, options.MarshalText)
+		if err != nil {
+			return "", err
+		}
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if !options.Write {
+			fmt.Fprintf(&b, "// %s\n%s", outputPath, code)
+			continue
+		}
+		if err := writeStringerFile(outputPath, code); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "Generated String for %s with %d constants in %s\n", name, len(constants), outputPath)
+	}
+	return b.String(), nil
+}
+
+// isStringerType reports whether String can be generated for the type, a non generic type
+// with an integer underlying type
+func isStringerType(t *types.Named) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0 && t.TypeParams().Len() == 0
+}
+
+// iotaTypes returns the types of the constants of the iota based const groups in the file,
+// in the order of their first constant
+func iotaTypes(pkg *packages.Package, file *ast.File) []*types.Named {
+	iota := types.Universe.Lookup("iota")
+	var named []*types.Named
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST {
+			continue
+		}
+		usesIota := false
+		ast.Inspect(decl, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && pkg.TypesInfo.Uses[ident] == iota {
+				usesIota = true
+			}
+			return !usesIota
+		})
+		if !usesIota {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			for _, ident := range spec.(*ast.ValueSpec).Names {
+				obj, ok := pkg.TypesInfo.Defs[ident].(*types.Const)
+				if !ok {
+					continue
+				}
+				t, ok := obj.Type().(*types.Named)
+				if ok && t.Obj().Pkg() == pkg.Types && isStringerType(t) && !slices.Contains(named, t) {
+					named = append(named, t)
+				}
+			}
+		}
+	}
+	return named
+}
+
+// stringerConstants returns the constants of the type in the package ordered by value,
+// keeping the first declared of constants with the same value. Constants of the generated
+// file are left out.
+func stringerConstants(pkg *packages.Package, t *types.Named, outputPath string) []stringerConstant {
+	var constants []stringerConstant
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.Const)
+		if !ok || obj.Name() == "_" || !types.Identical(obj.Type(), t) {
+			continue
+		}
+		if pkg.Fset.Position(obj.Pos()).Filename == outputPath {
+			continue
+		}
+		constants = append(constants, stringerConstant{name: obj.Name(), value: obj.Val(), pos: obj.Pos()})
+	}
+	slices.SortFunc(constants, func(a, b stringerConstant) int {
+		return int(a.pos - b.pos)
+	})
+	var unique []stringerConstant
+	for _, c := range constants {
+		if !slices.ContainsFunc(unique, func(u stringerConstant) bool { return constant.Compare(u.value, token.EQL, c.value) }) {
+			unique = append(unique, c)
+		}
+	}
+	slices.SortStableFunc(unique, func(a, b stringerConstant) int {
+		if constant.Compare(a.value, token.LSS, b.value) {
+			return -1
+		}
+		if constant.Compare(a.value, token.GTR, b.value) {
+			return 1
+		}
+		return 0
+	})
+	return unique
+}
+
+// checkStringerMethods refuses types that already have the methods to generate, unless
+// they are declared in the generated file that is replaced
+func checkStringerMethods(pkg *packages.Package, t *types.Named, outputPath string, marshalText bool) error {
+	methods := []string{"String"}
+	if marshalText {
+		methods = append(methods, "MarshalText")
+	}
+	for _, method := range methods {
+		existing, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, pkg.Types, method)
+		if existing == nil || pkg.Fset.Position(existing.Pos()).Filename == outputPath {
+			continue
+		}
+		position := pkg.Fset.Position(existing.Pos())
+		return fmt.Errorf("%s already has %s at %s:%d", t.Obj().Name(), method, position.Filename, position.Line)
+	}
+	return nil
+}
+
+// stringerCode returns the formatted source of the file declaring the methods of the type
+func stringerCode(
+	packageName string,
+	t *types.Named,
+	receiverName string,
+	constants []stringerConstant,
+	marshalText bool,
+) (string, error) {
+	name := t.Obj().Name()
+	number := "strconv.FormatInt(int64(%s), 10)"
+	if t.Underlying().(*types.Basic).Info()&types.IsUnsigned != 0 {
+		number = "strconv.FormatUint(uint64(%s), 10)"
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\nimport \"strconv\"\n\n", stringerGeneratedHeader, packageName)
+	fmt.Fprintf(&b, "// String returns the name of the constant with the value of %s\n", receiverName)
+	fmt.Fprintf(&b, "func (%s %s) String() string {\n\tswitch %s {\n", receiverName, name, receiverName)
+	for _, c := range constants {
+		fmt.Fprintf(&b, "\tcase %s:\n\t\treturn %q\n", c.name, c.name)
+	}
+	fmt.Fprintf(&b, "\t}\n\treturn %q + "+number+" + \")\"\n}\n", name+"(", receiverName)
+	if marshalText {
+		b.WriteString("\n// MarshalText implements encoding.TextMarshaler, encoding the value as its name\n")
+		fmt.Fprintf(&b, "func (%s %s) MarshalText() ([]byte, error) {\n\treturn []byte(%s.String()), nil\n}\n", receiverName, name, receiverName)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return string(src), nil
+}
+
+// writeStringerFile writes the generated code, refusing to replace a file that was not
+// generated by the tool
+func writeStringerFile(path string, code string) error {
+	existing, err := os.ReadFile(path)
+	if err == nil && !bytes.HasPrefix(existing, []byte(stringerGeneratedHeader)) {
+		return fmt.Errorf("%s already exists and was not generated by %s", path, generateStringerToolName)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(path, []byte(code), 0644)
+}
@@ -0,0 +1,155 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateStringer(t *testing.T) {
+	t.Parallel()
+
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"color/color.go": {
+				"package color", // 1
+				"",
+				"type Color int", // 3
+				"",
+				"const (", // 5
+				"\tRed Color = iota",
+				"\tGreen",
+				"\tBlue",
+				"\t_", // 9
+				"\tViolet",
+				"\tPrimary = Red",
+				")",
+				"",
+				"type Size uint8", // 14
+				"",
+				"const (", // 16
+				"\tSmall Size = iota + 1",
+				"\tLarge",
+				")",
+				"",
+				"// Custom already has a String method", // 21
+				"type Custom int",
+				"",
+				"const (",
+				"\tFirst Custom = iota",
+				")",
+				"",
+				"func (c Custom) String() string { return \"\" }", // 28
+				"",
+			},
+			"color/color_test.go": {
+				"package color",
+				"",
+				"import \"testing\"",
+				"",
+				"func TestString(t *testing.T) {",
+				"\tif Green.String() != \"Green\" || Primary.String() != \"Red\" || Color(42).String() != \"Color(42)\" {",
+				"\t\tt.Error(Green, Primary, Color(42))",
+				"\t}",
+				"\tif Large.String() != \"Large\" || Size(0).String() != \"Size(0)\" {",
+				"\t\tt.Error(Large, Size(0))",
+				"\t}",
+				"}",
+				"",
+			},
+		})
+	}
+
+	t.Run("writes files", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "color", "color.go")
+
+		_, err := GenerateStringer(context.Background(), file, StringerOptions{Write: true})
+		if err == nil || !strings.Contains(err.Error(), "Custom already has String at "+file+":28") {
+			t.Fatalf("Expected the existing String method of Custom to be refused, got: %v", err)
+		}
+		for _, name := range []string{"Color", "Size"} {
+			result, err := GenerateStringer(context.Background(), file, StringerOptions{TypeName: name, Write: true, MarshalText: name == "Color"})
+			if err != nil {
+				t.Fatalf("Failed to generate String for %s: %v", name, err)
+			}
+			outputPath := filepath.Join(workspace, "color", strings.ToLower(name)+"_string.go")
+			if !strings.HasPrefix(result, "Generated String for "+name+" with ") || !strings.HasSuffix(result, " in "+outputPath+"\n") {
+				t.Errorf("Unexpected result: %s", result)
+			}
+		}
+
+		content, err := os.ReadFile(filepath.Join(workspace, "color", "color_string.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range []string{
+			stringerGeneratedHeader + "\n\npackage color\n",
+			"func (c Color) String() string {\n\tswitch c {\n\tcase Red:\n\t\treturn \"Red\"\n\tcase Green:",
+			"\tcase Violet:\n\t\treturn \"Violet\"\n\t}\n\treturn \"Color(\" + strconv.FormatInt(int64(c), 10) + \")\"\n}",
+			"func (c Color) MarshalText() ([]byte, error) {",
+		} {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("Expected the generated file to contain %q, got:\n%s", expected, content)
+			}
+		}
+		if strings.Contains(string(content), "Primary") {
+			t.Errorf("Expected the duplicate value of Primary to be left out, got:\n%s", content)
+		}
+
+		// Regenerating replaces the earlier file instead of conflicting with its methods
+		if _, err := GenerateStringer(context.Background(), file, StringerOptions{TypeName: "Color", Write: true}); err != nil {
+			t.Fatalf("Failed to regenerate String for Color: %v", err)
+		}
+		cmd := exec.Command("go", "test", "./...")
+		cmd.Dir = workspace
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Expected the generated methods to build and work, got: %v\n%s", err, output)
+		}
+	})
+
+	t.Run("return code", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "color", "color.go")
+
+		result, err := GenerateStringer(context.Background(), file, StringerOptions{TypeName: "Size"})
+		if err != nil {
+			t.Fatalf("Failed to generate String for Size: %v", err)
+		}
+		outputPath := filepath.Join(workspace, "color", "size_string.go")
+		if !strings.HasPrefix(result, "// "+outputPath+"\n"+stringerGeneratedHeader) {
+			t.Errorf("Expected the code of the file, got:\n%s", result)
+		}
+		if !strings.Contains(result, "\treturn \"Size(\" + strconv.FormatUint(uint64(s), 10) + \")\"") {
+			t.Errorf("Expected unsigned values to be formatted as such, got:\n%s", result)
+		}
+		if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+			t.Errorf("Expected no file to be written, got: %v", err)
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "color", "color.go")
+
+		if err := os.WriteFile(filepath.Join(workspace, "color", "size_string.go"), []byte("package color\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for options, expected := range map[StringerOptions]string{
+			{TypeName: "Size", Write: true}: "was not generated by generate_stringer",
+			{TypeName: "Missing"}:           "no type 'Missing' declared",
+			{TypeName: "Custom"}:            "Custom already has String",
+		} {
+			if _, err := GenerateStringer(context.Background(), file, options); err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected an error containing %q for %+v, got: %v", expected, options, err)
+			}
+		}
+	})
+}
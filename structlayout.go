@@ -0,0 +1,303 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	structLayoutToolName        = "struct_layout"
+	structLayoutToolDescription = `Reports the memory layout of a Go struct as laid out by the gc compiler for a target GOARCH: its size and alignment, and the offset, size, alignment and padding after each field.
+
+When reordering the fields reduces the padding, the declaration with the fields ordered by decreasing alignment is returned, keeping their docs, tags and comments, with the size it would have. Zero sized fields are placed first, as a zero sized last field is padded. Nothing is written, replace the declaration to apply the order.`
+)
+
+func AddStructLayoutTool(mcpServer *server.MCPServer) {
+	handleStructLayout := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := request.RequireString("type")
+		if err != nil {
+			return nil, err
+		}
+		goarch := request.GetString("goarch", "")
+
+		layout, err := StructLayout(ctx, filePath, typeName, goarch)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error computing struct layout: %v", err)), nil
+		}
+		return mcp.NewToolResultText(layout.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		structLayoutToolName,
+		mcp.WithDescription(structLayoutToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the struct"),
+			mcp.Required(),
+		),
+		mcp.WithString("type",
+			mcp.Description("Name of the struct type"),
+			mcp.Required(),
+		),
+		mcp.WithString("goarch",
+			mcp.Description("Target architecture whose sizes and alignments are used, e.g. amd64, arm64, 386 or wasm (default: the architecture of the server)"),
+		),
+	), handleStructLayout)
+}
+
+// StructLayoutReport is the memory layout of a struct for a target architecture
+type StructLayoutReport struct {
+	Type    string              `json:"type"`
+	GOARCH  string              `json:"goarch"`
+	Size    int64               `json:"size"`
+	Align   int64               `json:"align"`
+	Padding int64               `json:"padding"`
+	Fields  []StructLayoutField `json:"fields"`
+	// Reordered is the declaration with the fields ordered to minimize padding, empty when
+	// the declared order already does
+	Reordered     string `json:"reordered,omitempty"`
+	ReorderedSize int64  `json:"reordered_size,omitempty"`
+}
+
+// StructLayoutField is the placement of a field in a struct
+type StructLayoutField struct {
+	// Name is empty for embedded fields
+	Name   string `json:"name,omitempty"`
+	Type   string `json:"type"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	Align  int64  `json:"align"`
+	// Padding is the number of bytes between the field and the next one, or the end of
+	// the struct for the last field
+	Padding int64 `json:"padding"`
+}
+
+// layoutField is a field with the source it is declared by in the reordered declaration
+type layoutField struct {
+	field  *types.Var
+	size   int64
+	align  int64
+	source string
+}
+
+// StructLayout computes the layout of the struct typeName declared in filePath with the
+// sizes of the gc compiler for goarch, the architecture of the running program when empty
+func StructLayout(ctx context.Context, filePath string, typeName string, goarch string) (*StructLayoutReport, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	sizes := types.SizesFor("gc", goarch)
+	if sizes == nil {
+		return nil, fmt.Errorf("unknown goarch %q", goarch)
+	}
+	pkg, file, _, err := loadFilePackage(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.Errors) > 0 {
+		return nil, classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok || pkg.Fset.Position(obj.Pos()).Filename != filePath {
+		return nil, classifyErrorf(ErrSymbolNotFound, "no type '%s' declared in %s", typeName, filePath)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is an alias, compute the layout of the aliased type", typeName)
+	}
+	structType, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a struct", typeName)
+	}
+	if named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("'%s' is generic, its layout depends on the type arguments", typeName)
+	}
+
+	layout := &StructLayoutReport{
+		Type:   typeName,
+		GOARCH: goarch,
+		Size:   sizes.Sizeof(structType),
+		Align:  sizes.Alignof(structType),
+	}
+	qualifier := types.RelativeTo(pkg.Types)
+	var fields []*types.Var
+	for field := range structType.Fields() {
+		fields = append(fields, field)
+	}
+	offsets := sizes.Offsetsof(fields)
+	for i, field := range fields {
+		end := layout.Size
+		if i+1 < len(fields) {
+			end = offsets[i+1]
+		}
+		placed := StructLayoutField{
+			Type:   types.TypeString(field.Type(), qualifier),
+			Offset: offsets[i],
+			Size:   sizes.Sizeof(field.Type()),
+			Align:  sizes.Alignof(field.Type()),
+		}
+		if !field.Embedded() {
+			placed.Name = field.Name()
+		}
+		placed.Padding = end - placed.Offset - placed.Size
+		layout.Padding += placed.Padding
+		layout.Fields = append(layout.Fields, placed)
+	}
+	if len(fields) == 0 {
+		layout.Padding = layout.Size
+	}
+
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	spec := layoutTypeSpec(file, typeName)
+	if spec == nil {
+		return nil, classifyErrorf(ErrSymbolNotFound, "declaration of '%s' not found in %s", typeName, filePath)
+	}
+	source := func(node ast.Node) string {
+		return string(src[pkg.Fset.Position(node.Pos()).Offset:pkg.Fset.Position(node.End()).Offset])
+	}
+	var fieldSources []layoutField
+	i := 0
+	for _, field := range spec.Type.(*ast.StructType).Fields.List {
+		names := []string{""}
+		if len(field.Names) > 0 {
+			names = nil
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+		for j, name := range names {
+			var b strings.Builder
+			if field.Doc != nil && j == 0 {
+				for _, comment := range field.Doc.List {
+					b.WriteString(comment.Text + "\n")
+				}
+			}
+			if name != "" {
+				b.WriteString(name + " ")
+			}
+			b.WriteString(source(field.Type))
+			if field.Tag != nil {
+				b.WriteString(" " + field.Tag.Value)
+			}
+			if field.Comment != nil {
+				for _, comment := range field.Comment.List {
+					b.WriteString(" " + comment.Text)
+				}
+			}
+			fieldSources = append(fieldSources, layoutField{
+				field:  fields[i],
+				size:   layout.Fields[i].Size,
+				align:  layout.Fields[i].Align,
+				source: b.String(),
+			})
+			i++
+		}
+	}
+	order := slices.Clone(fieldSources)
+	slices.SortStableFunc(order, func(a, b layoutField) int {
+		if (a.size == 0) != (b.size == 0) {
+			if a.size == 0 {
+				return -1
+			}
+			return 1
+		}
+		return int(b.align - a.align)
+	})
+	var orderedFields []*types.Var
+	for _, field := range order {
+		orderedFields = append(orderedFields, field.field)
+	}
+	reorderedSize := sizes.Sizeof(types.NewStruct(orderedFields, nil))
+	if reorderedSize >= layout.Size {
+		return layout, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s struct {\n", typeName)
+	for _, field := range order {
+		b.WriteString(field.source + "\n")
+	}
+	b.WriteString("}\n")
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the reordered declaration: %w", err)
+	}
+	layout.Reordered = string(formatted)
+	layout.ReorderedSize = reorderedSize
+	return layout, nil
+}
+
+// layoutTypeSpec returns the spec declaring the struct type in the file
+func layoutTypeSpec(file *ast.File, typeName string) *ast.TypeSpec {
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			spec, ok := spec.(*ast.TypeSpec)
+			if !ok || spec.Name.Name != typeName {
+				continue
+			}
+			if _, ok := spec.Type.(*ast.StructType); ok {
+				return spec
+			}
+		}
+	}
+	return nil
+}
+
+func (layout *StructLayoutReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		"%s on %s: size %d, align %d, %d bytes of padding\n",
+		layout.Type, layout.GOARCH, layout.Size, layout.Align, layout.Padding,
+	)
+	b.WriteString("offset size align field\n")
+	for _, field := range layout.Fields {
+		declared := field.Type
+		if field.Name != "" {
+			declared = field.Name + " " + field.Type
+		}
+		fmt.Fprintf(&b, "%6d %4d %5d %s\n", field.Offset, field.Size, field.Align, declared)
+		if field.Padding > 0 {
+			fmt.Fprintf(&b, "%6d %4d       (padding)\n", field.Offset+field.Size, field.Padding)
+		}
+	}
+	if layout.Reordered == "" {
+		b.WriteString("\nThe declared order has the minimal padding")
+		return b.String()
+	}
+	fmt.Fprintf(
+		&b,
+		"\nReordered by alignment, size %d, saving %d bytes:\n%s",
+		layout.ReorderedSize, layout.Size-layout.ReorderedSize, layout.Reordered,
+	)
+	return b.String()
+}
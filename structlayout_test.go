@@ -0,0 +1,113 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStructLayout(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/app", "", "go 1.22", ""},
+		"shape/shape.go": {
+			"package shape",
+			"",
+			"// Point is a point",
+			"type Point struct {",
+			"\t// Visible reports whether the point is drawn",
+			"\tVisible bool",
+			"\tX, Y    int64 `json:\"x\"`",
+			"\tTag     byte // the tag",
+			"\tCount   int32",
+			"\tEmpty   struct{}",
+			"}",
+			"",
+			"type Compact struct {",
+			"\tA int64",
+			"\tB, C int32",
+			"}",
+			"",
+			"type Size int",
+			"",
+		},
+	})
+	file := filepath.Join(workspace, "shape", "shape.go")
+
+	t.Run("reordered", func(t *testing.T) {
+		t.Parallel()
+		layout, err := StructLayout(context.Background(), file, "Point", "amd64")
+		if err != nil {
+			t.Fatalf("Failed to compute the layout: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Point on amd64: size 40, align 8, 18 bytes of padding",
+			"offset size align field",
+			"     0    1     1 Visible bool",
+			"     1    7       (padding)",
+			"     8    8     8 X int64",
+			"    16    8     8 Y int64",
+			"    24    1     1 Tag byte",
+			"    25    3       (padding)",
+			"    28    4     4 Count int32",
+			"    32    0     1 Empty struct{}",
+			"    32    8       (padding)",
+			"",
+			"Reordered by alignment, size 24, saving 16 bytes:",
+			"type Point struct {",
+			"\tEmpty struct{}",
+			"\tX     int64 `json:\"x\"`",
+			"\tY     int64 `json:\"x\"`",
+			"\tCount int32",
+			"\t// Visible reports whether the point is drawn",
+			"\tVisible bool",
+			"\tTag     byte // the tag",
+			"}",
+			"",
+		}, "\n")
+		if layout.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, layout.String())
+		}
+
+		layout, err = StructLayout(context.Background(), file, "Point", "386")
+		if err != nil {
+			t.Fatalf("Failed to compute the layout on 386: %v", err)
+		}
+		if layout.Size != 32 || layout.Fields[1].Offset != 4 || layout.ReorderedSize != 24 {
+			t.Errorf("Expected the 4 byte alignment of int64 on 386, got:\n%s", layout)
+		}
+	})
+
+	t.Run("minimal", func(t *testing.T) {
+		t.Parallel()
+		layout, err := StructLayout(context.Background(), file, "Compact", "arm64")
+		if err != nil {
+			t.Fatalf("Failed to compute the layout: %v", err)
+		}
+		if layout.Size != 16 || layout.Padding != 0 || layout.Reordered != "" {
+			t.Errorf("Expected no padding and no reordering, got:\n%s", layout)
+		}
+		if !strings.HasSuffix(layout.String(), "\nThe declared order has the minimal padding") {
+			t.Errorf("Unexpected result:\n%s", layout)
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		for _, test := range []struct {
+			typeName string
+			goarch   string
+			expected string
+		}{
+			{"Size", "", "'Size' is not a struct"},
+			{"Missing", "", "no type 'Missing' declared"},
+			{"Point", "vax", "unknown goarch \"vax\""},
+		} {
+			if _, err := StructLayout(context.Background(), file, test.typeName, test.goarch); err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected %q for %s, got: %v", test.expected, test.typeName, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	generateStubsToolName        = "generate_stubs"
+	generateStubsToolDescription = `Generates the methods a concrete Go type is missing to implement an interface, with the signatures of the interface and a receiver following the existing methods of the type or the conventions of the module. The stubs panic with "unimplemented" and are inserted after the last method of the type in the file declaring it, adding the imports their signatures need.
+
+The interface is named Name for an interface of the same package, or qualified with the import path or package name of its package, e.g. io.Reader or example.com/app/store.Store. Pass output=patch to preview the stubs as a unified diff without writing them.`
+)
+
+func AddGenerateStubsTool(mcpServer *server.MCPServer) {
+	handleGenerateStubs := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := request.RequireString("type")
+		if err != nil {
+			return nil, err
+		}
+		interfaceName, err := request.RequireString("interface")
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := GenerateStubs(ctx, filePath, typeName, interfaceName)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error generating stubs: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		generateStubsToolName,
+		mcp.WithDescription(generateStubsToolDescription),
+		editingToolAnnotation(false, true),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file declaring the concrete type"),
+			mcp.Required(),
+		),
+		mcp.WithString("type",
+			mcp.Description("Name of the concrete type to add the methods to"),
+			mcp.Required(),
+		),
+		mcp.WithString("interface",
+			mcp.Description("Interface to implement, e.g. Store, io.Reader or example.com/app/store.Store"),
+			mcp.Required(),
+		),
+	), handleGenerateStubs)
+}
+
+// GenerateStubs adds the methods the type named typeName declared in filePath is missing
+// to implement the interface named interfaceName, writing the changed file
+func GenerateStubs(ctx context.Context, filePath string, typeName string, interfaceName string) (string, error) {
+	if !filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	qualifier, name := "", interfaceName
+	if i := strings.LastIndex(interfaceName, "."); i >= 0 {
+		qualifier, name = interfaceName[:i], interfaceName[i+1:]
+	}
+
+	// Loading the interface package in the same call type checks both in one universe,
+	// which the identity of the types in the signatures depends on
+	var extraPatterns []string
+	if qualifier != "" {
+		extraPatterns = append(extraPatterns, qualifier)
+	}
+	pkg, _, pkgs, err := loadFilePackage(ctx, filePath, extraPatterns...)
+	if err != nil {
+		return "", err
+	}
+	if len(pkg.Errors) > 0 {
+		return "", classifyPackageErrors(pkg, fmt.Errorf("package has errors: %v", pkg.Errors))
+	}
+
+	concrete, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok || pkg.Fset.Position(concrete.Pos()).Filename != filePath {
+		return "", classifyErrorf(ErrSymbolNotFound, "no type '%s' declared in %s", typeName, filePath)
+	}
+	named, ok := concrete.Type().(*types.Named)
+	if !ok {
+		return "", fmt.Errorf("'%s' is an alias, stubs are generated for the aliased type", typeName)
+	}
+	if _, ok := named.Underlying().(*types.Interface); ok {
+		return "", fmt.Errorf("'%s' is an interface, stubs are generated for concrete types", typeName)
+	}
+	ifaceObj, err := lookupInterface(pkg, pkgs, qualifier, name)
+	if err != nil {
+		return "", err
+	}
+	iface := ifaceObj.Type().Underlying().(*types.Interface)
+	ifaceName := ifaceObj.Name()
+	if ifaceObj.Pkg() != pkg.Types {
+		ifaceName = ifaceObj.Pkg().Name() + "." + ifaceName
+	}
+
+	// Methods of both the value and the pointer receiver count as existing
+	pointer := types.NewPointer(named)
+	var missing []*types.Func
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		existing, _, _ := types.LookupFieldOrMethod(pointer, false, method.Pkg(), method.Name())
+		if existing == nil {
+			if !method.Exported() && method.Pkg() != pkg.Types {
+				return "", fmt.Errorf("%s has the unexported method %s, only types of %s can implement it", interfaceName, method.Name(), method.Pkg().Path())
+			}
+			missing = append(missing, method)
+			continue
+		}
+		fn, ok := existing.(*types.Func)
+		if !ok || !types.Identical(fn.Type().(*types.Signature), method.Type().(*types.Signature)) {
+			return "", fmt.Errorf(
+				"%s.%s conflicts with the method of %s: %s",
+				typeName, method.Name(), interfaceName, types.ObjectString(method, nil),
+			)
+		}
+	}
+	if len(missing) == 0 {
+		return fmt.Sprintf("%s already implements %s", typeName, interfaceName), nil
+	}
+
+	file, err := parseMoveFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	receiverName, receiverPointer := stubReceiver(pkg, file.ast, named)
+
+	var qualifyErr error
+	qualify := func(other *types.Package) string {
+		if other == pkg.Types {
+			return ""
+		}
+		name, err := file.qualifierOf(other.Path(), other.Name())
+		if err != nil && qualifyErr == nil {
+			qualifyErr = err
+		}
+		return name
+	}
+
+	receiver := typeName
+	if params := named.TypeParams(); params.Len() > 0 {
+		var names []string
+		for i := 0; i < params.Len(); i++ {
+			names = append(names, params.At(i).Obj().Name())
+		}
+		receiver += "[" + strings.Join(names, ", ") + "]"
+	}
+	if receiverPointer {
+		receiver = "*" + receiver
+	}
+
+	var stubs bytes.Buffer
+	var names []string
+	for _, method := range missing {
+		names = append(names, method.Name())
+		signature := stubSignature(method.Type().(*types.Signature), receiverName)
+		fmt.Fprintf(&stubs, "\n\n// %s implements %s\n", method.Name(), ifaceName)
+		fmt.Fprintf(&stubs, "func (%s %s) %s", receiverName, receiver, method.Name())
+		types.WriteSignature(&stubs, signature, qualify)
+		stubs.WriteString(" {\n\tpanic(\"unimplemented\")\n}")
+	}
+	if qualifyErr != nil {
+		return "", qualifyErr
+	}
+
+	insertAt := stubInsertionPoint(file, typeName)
+	file.edits = append(file.edits, textEdit{start: insertAt, end: insertAt, replacement: stubs.String()})
+	if err := file.write(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"Added %d method stubs to %s in %s: %s",
+		len(missing), typeName, filePath, strings.Join(names, ", "),
+	), nil
+}
+
+// lookupInterface finds the interface name in the package qualified by qualifier, an import
+// path or package name among the loaded packages and their dependencies, or in pkg when
+// there is no qualifier
+func lookupInterface(pkg *packages.Package, pkgs []*packages.Package, qualifier string, name string) (*types.TypeName, error) {
+	var candidates []*types.Package
+	if qualifier == "" {
+		candidates = []*types.Package{pkg.Types}
+	} else {
+		// Import paths win over package names, and direct imports over transitive ones
+		var byName []*types.Package
+		packages.Visit(pkgs, nil, func(loaded *packages.Package) {
+			if loaded.Types == nil {
+				return
+			}
+			if loaded.PkgPath == qualifier {
+				candidates = append(candidates, loaded.Types)
+			} else if loaded.Name == qualifier {
+				byName = append(byName, loaded.Types)
+			}
+		})
+		for _, imported := range pkg.Types.Imports() {
+			if imported.Name() == qualifier {
+				byName = append([]*types.Package{imported}, byName...)
+			}
+		}
+		candidates = append(candidates, byName...)
+	}
+
+	for _, candidate := range candidates {
+		if obj, ok := candidate.Scope().Lookup(name).(*types.TypeName); ok {
+			if _, ok := obj.Type().Underlying().(*types.Interface); !ok {
+				return nil, fmt.Errorf("%s.%s is not an interface", candidate.Path(), name)
+			}
+			return obj, nil
+		}
+	}
+	if qualifier == "" {
+		return nil, classifyErrorf(ErrSymbolNotFound, "no interface '%s' found in package %s", name, pkg.PkgPath)
+	}
+	return nil, classifyErrorf(ErrSymbolNotFound, "no interface '%s' found in package %s", name, qualifier)
+}
+
+// stubReceiver returns the receiver name and kind of new methods of the type, following its
+// existing methods or else the conventions of the module
+func stubReceiver(pkg *packages.Package, file *ast.File, named *types.Named) (string, bool) {
+	name := ""
+	pointers, values := 0, 0
+	for i := 0; i < named.NumMethods(); i++ {
+		method := named.Method(i)
+		signature := method.Type().(*types.Signature)
+		if _, ok := signature.Recv().Type().(*types.Pointer); ok {
+			pointers++
+		} else {
+			values++
+		}
+		if name == "" && signature.Recv().Name() != "" && signature.Recv().Name() != "_" {
+			name = signature.Recv().Name()
+		}
+	}
+	if name != "" {
+		return name, pointers > 0 || values == 0
+	}
+
+	profile, err := Conventions(filepath.Dir(pkg.Fset.Position(file.Pos()).Filename))
+	if err != nil {
+		// Without a module the defaults of the profile apply
+		profile = &ConventionProfile{}
+	}
+	return profile.ReceiverName(pkg.PkgPath, named.Obj().Name()), profile.ReceiverKind.Value != "value"
+}
+
+// stubSignature returns the signature with the parameters named like the receiver renamed
+// to _, as the stubs do not use them
+func stubSignature(signature *types.Signature, receiverName string) *types.Signature {
+	rename := func(tuple *types.Tuple) *types.Tuple {
+		vars := make([]*types.Var, tuple.Len())
+		for i := range vars {
+			v := tuple.At(i)
+			if v.Name() == receiverName {
+				v = types.NewParam(v.Pos(), v.Pkg(), "_", v.Type())
+			}
+			vars[i] = v
+		}
+		return types.NewTuple(vars...)
+	}
+	return types.NewSignatureType(
+		nil, nil, nil,
+		rename(signature.Params()),
+		rename(signature.Results()),
+		signature.Variadic(),
+	)
+}
+
+// stubInsertionPoint returns the offset after the last method of the type in the file, or
+// after the type declaration when the file declares no methods of the type
+func stubInsertionPoint(file *moveFile, typeName string) int {
+	end := token.NoPos
+	for _, decl := range file.ast.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv != nil && receiverTypeName(decl.Recv.List[0].Type) == typeName {
+				end = decl.End()
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.Name == typeName && end == token.NoPos {
+					end = decl.End()
+				}
+			}
+		}
+	}
+	return file.offset(end)
+}
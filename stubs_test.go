@@ -0,0 +1,183 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestGenerateStubs(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a store interface and partial implementations
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"store/store.go": {
+				"package store",                     // 1
+				"",                                  // 2
+				"import \"time\"",                   // 3
+				"",                                  // 4
+				"// Store stores values",            // 5
+				"type Store interface {",            // 6
+				"\tGet(key string) (string, error)", // 7
+				"\tSet(key string, value string, ttl time.Duration) error", // 8
+				"\tLen() int", // 9
+				"}",           // 10
+				"",            // 11
+			},
+			"memory/memory.go": {
+				"package memory",                         // 1
+				"",                                       // 2
+				"// Memory stores values in memory",      // 3
+				"type Memory struct {",                   // 4
+				"\tvalues map[string]string",             // 5
+				"}",                                      // 6
+				"",                                       // 7
+				"func (m *Memory) Len() int {",           // 8
+				"\treturn len(m.values)",                 // 9
+				"}",                                      // 10
+				"",                                       // 11
+				"// Broken has a conflicting Len method", // 12
+				"type Broken struct{}",                   // 13
+				"",                                       // 14
+				"func (Broken) Len() string { return \"\" }", // 15
+				"",                    // 16
+				"type Empty struct{}", // 17
+				"",                    // 18
+			},
+		})
+	}
+
+	readFile := func(t testing.TB, path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	t.Run("missing methods", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "memory", "memory.go")
+
+		result, err := GenerateStubs(context.Background(), file, "Memory", "example.com/app/store.Store")
+		if err != nil {
+			t.Fatalf("Failed to generate stubs: %v", err)
+		}
+		if result != "Added 2 method stubs to Memory in "+file+": Get, Set" {
+			t.Errorf("Expected the stubs to be summarized, got: %s", result)
+		}
+		expected := strings.Join([]string{
+			"package memory",
+			"",
+			"import \"time\"",
+			"",
+			"// Memory stores values in memory",
+			"type Memory struct {",
+			"\tvalues map[string]string",
+			"}",
+			"",
+			"func (m *Memory) Len() int {",
+			"\treturn len(m.values)",
+			"}",
+			"",
+			"// Get implements store.Store",
+			"func (m *Memory) Get(key string) (string, error) {",
+			"\tpanic(\"unimplemented\")",
+			"}",
+			"",
+			"// Set implements store.Store",
+			"func (m *Memory) Set(key string, value string, ttl time.Duration) error {",
+			"\tpanic(\"unimplemented\")",
+			"}",
+			"",
+		}, "\n")
+		if content := readFile(t, file); !strings.HasPrefix(content, expected) {
+			t.Errorf("Expected the stubs after the existing methods:\n%s\ngot:\n%s", expected, content)
+		}
+
+		result, err = GenerateStubs(context.Background(), file, "Memory", "example.com/app/store.Store")
+		if err != nil || result != "Memory already implements example.com/app/store.Store" {
+			t.Errorf("Expected Memory to implement the store, got %s (%v)", result, err)
+		}
+	})
+
+	t.Run("receiver from conventions", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "memory", "memory.go")
+
+		if _, err := GenerateStubs(context.Background(), file, "Empty", "fmt.Stringer"); err != nil {
+			t.Fatalf("Failed to generate stubs: %v", err)
+		}
+		if content := readFile(t, file); !strings.Contains(content, "type Empty struct{}\n\n// String implements fmt.Stringer\nfunc (e *Empty) String() string {") {
+			t.Errorf("Expected a short pointer receiver following the module, got:\n%s", content)
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "memory", "memory.go")
+		before := readFile(t, file)
+
+		for _, test := range []struct {
+			typeName, interfaceName string
+			expected                string
+		}{
+			{"Broken", "example.com/app/store.Store", "Broken.Len conflicts with the method of example.com/app/store.Store"},
+			{"Missing", "fmt.Stringer", "no type 'Missing' declared"},
+			{"Memory", "fmt.Missing", "no interface 'Missing' found in package fmt"},
+		} {
+			if _, err := GenerateStubs(context.Background(), file, test.typeName, test.interfaceName); err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected %q for %s, got: %v", test.expected, test.typeName, err)
+			}
+		}
+		if readFile(t, file) != before {
+			t.Errorf("Expected refused stubs to leave the file untouched")
+		}
+	})
+
+	t.Run("generate stubs tool patch", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		file := filepath.Join(workspace, "memory", "memory.go")
+		before := readFile(t, file)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name": generateStubsToolName,
+				"arguments": map[string]any{
+					"file_path": file,
+					"type":      "Memory",
+					"interface": "example.com/app/store.Store",
+					"output":    "patch",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		for _, expected := range []string{"+++ b/memory/memory.go", "+import \"time\"", "+func (m *Memory) Get(key string) (string, error) {"} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the patch, got: %s", expected, text)
+			}
+		}
+		if readFile(t, file) != before {
+			t.Errorf("Expected the patch output to leave the files untouched")
+		}
+	})
+}
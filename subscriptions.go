@@ -0,0 +1,229 @@
+package go_mcp_tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+
+	// stdioSessionID is the ID of the single session of the stdio transport of mcp-go
+	stdioSessionID = "stdio"
+	// sessionIDHeader carries the session ID of the streamable HTTP transport
+	sessionIDHeader = "Mcp-Session-Id"
+)
+
+// subscriptionRegistry holds the resource subscriptions of the sessions of each server.
+// mcp-go does not route resources/subscribe and resources/unsubscribe requests, so the
+// stdio and HTTP transports answer them before passing the other messages on to mcp-go.
+type subscriptionRegistry struct {
+	mu sync.Mutex
+	// subscribable holds the URIs of the resources that can be subscribed to per server
+	subscribable map[*server.MCPServer]map[string]bool
+	// sessions maps servers to session IDs to the subscribed URIs
+	sessions map[*server.MCPServer]map[string]map[string]bool
+}
+
+var resourceSubscriptions = &subscriptionRegistry{
+	subscribable: make(map[*server.MCPServer]map[string]bool),
+	sessions:     make(map[*server.MCPServer]map[string]map[string]bool),
+}
+
+// allow makes the resource of the server subscribable
+func (registry *subscriptionRegistry) allow(mcpServer *server.MCPServer, uri string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.subscribable[mcpServer] == nil {
+		registry.subscribable[mcpServer] = make(map[string]bool)
+	}
+	registry.subscribable[mcpServer][uri] = true
+}
+
+func (registry *subscriptionRegistry) subscribe(mcpServer *server.MCPServer, sessionID string, uri string) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if !registry.subscribable[mcpServer][uri] {
+		return fmt.Errorf("resource %s cannot be subscribed to", uri)
+	}
+	sessions, ok := registry.sessions[mcpServer]
+	if !ok {
+		sessions = make(map[string]map[string]bool)
+		registry.sessions[mcpServer] = sessions
+	}
+	if sessions[sessionID] == nil {
+		sessions[sessionID] = make(map[string]bool)
+	}
+	sessions[sessionID][uri] = true
+	return nil
+}
+
+func (registry *subscriptionRegistry) unsubscribe(mcpServer *server.MCPServer, sessionID string, uri string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.sessions[mcpServer][sessionID], uri)
+	if len(registry.sessions[mcpServer][sessionID]) == 0 {
+		registry.removeSessionLocked(mcpServer, sessionID)
+	}
+}
+
+// removeSession ends the subscriptions of an ended session
+func (registry *subscriptionRegistry) removeSession(mcpServer *server.MCPServer, sessionID string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.removeSessionLocked(mcpServer, sessionID)
+}
+
+func (registry *subscriptionRegistry) removeSessionLocked(mcpServer *server.MCPServer, sessionID string) {
+	delete(registry.sessions[mcpServer], sessionID)
+	if len(registry.sessions[mcpServer]) == 0 {
+		delete(registry.sessions, mcpServer)
+	}
+}
+
+// subscribers returns the sorted IDs of the sessions subscribed to the resource
+func (registry *subscriptionRegistry) subscribers(mcpServer *server.MCPServer, uri string) []string {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	var sessionIDs []string
+	for sessionID, uris := range registry.sessions[mcpServer] {
+		if uris[uri] {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+	slices.Sort(sessionIDs)
+	return sessionIDs
+}
+
+// handle answers the resources/subscribe and resources/unsubscribe requests of the session,
+// reporting false for all other messages
+func (registry *subscriptionRegistry) handle(
+	mcpServer *server.MCPServer,
+	sessionID string,
+	message []byte,
+) (mcp.JSONRPCMessage, bool) {
+	var request struct {
+		ID     any    `json:"id"`
+		Method string `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || request.ID == nil {
+		return nil, false
+	}
+	id := mcp.NewRequestId(request.ID)
+	switch request.Method {
+	case methodResourcesSubscribe:
+		if err := registry.subscribe(mcpServer, sessionID, request.Params.URI); err != nil {
+			return mcp.NewJSONRPCError(id, mcp.INVALID_PARAMS, err.Error(), nil), true
+		}
+	case methodResourcesUnsubscribe:
+		registry.unsubscribe(mcpServer, sessionID, request.Params.URI)
+	default:
+		return nil, false
+	}
+	return mcp.NewJSONRPCResponse(id, mcp.Result{}), true
+}
+
+// notifyResourceUpdated notifies the sessions subscribed to the resource that it changed
+func notifyResourceUpdated(mcpServer *server.MCPServer, uri string) {
+	for _, sessionID := range resourceSubscriptions.subscribers(mcpServer, uri) {
+		// Sessions without a notification stream, e.g. HTTP clients between their
+		// requests, miss the update and read the resource again on their next request
+		_ = mcpServer.SendNotificationToSpecificClient(
+			sessionID,
+			mcp.MethodNotificationResourceUpdated,
+			map[string]any{"uri": uri},
+		)
+	}
+}
+
+// lockedWriter serializes the writes of the responses and notifications of a transport
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (writer *lockedWriter) Write(p []byte) (int, error) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	return writer.w.Write(p)
+}
+
+// serveStdio serves the stdio transport of mcp-go on in and out, answering the resource
+// subscription requests itself
+func serveStdio(ctx context.Context, mcpServer *server.MCPServer, in io.Reader, out io.Writer) error {
+	// mcp-go writes every message with a single write, so the answers are never interleaved with them
+	writer := &lockedWriter{w: out}
+	reader, pipe := io.Pipe()
+	go func() {
+		pipe.CloseWithError(forwardStdio(mcpServer, in, pipe, writer))
+	}()
+	return server.NewStdioServer(mcpServer).Listen(ctx, reader, writer)
+}
+
+// forwardStdio answers the resource subscription requests read from in on out and
+// forwards all other lines to the stdio transport of mcp-go
+func forwardStdio(mcpServer *server.MCPServer, in io.Reader, forward io.Writer, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if response, ok := resourceSubscriptions.handle(mcpServer, stdioSessionID, line); ok {
+				encoded, err := json.Marshal(response)
+				if err != nil {
+					return err
+				}
+				if _, err := out.Write(append(encoded, '\n')); err != nil {
+					return err
+				}
+			} else if _, err := forward.Write(line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// resourceSubscriptionHandler answers the resource subscription requests posted to the
+// streamable HTTP transport of mcp-go and ends the subscriptions of deleted sessions,
+// passing all requests on to next otherwise
+func resourceSubscriptionHandler(mcpServer *server.MCPServer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(sessionIDHeader)
+		switch {
+		case sessionID == "":
+		case r.Method == http.MethodDelete:
+			resourceSubscriptions.removeSession(mcpServer, sessionID)
+		case r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
+				return
+			}
+			if response, ok := resourceSubscriptions.handle(mcpServer, sessionID, body); ok {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(response)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// symbolResourceTemplate resolves to the inspect output of a package level symbol
+	symbolResourceTemplate = "gosym://{+package}/{symbol}"
+	// packageResourceTemplate resolves to the inspect output of a package
+	packageResourceTemplate = "gopkg://{+package}"
+)
+
+// WithSymbolResources registers the gosym://{package}/{symbol} and gopkg://{package}
+// resource templates, which resolve to the inspect output of a symbol or a package. Import
+// paths are resolved in workspaceDir, so clients can deep-link to symbols of its module and
+// dependencies without constructing tool calls.
+func WithSymbolResources(workspaceDir string) Option {
+	return func(o *serverOptions) {
+		o.symbolResourcesDir = workspaceDir
+	}
+}
+
+// addSymbolResources registers the resource templates inspecting symbols and packages
+func addSymbolResources(mcpServer *server.MCPServer, workspaceDir string, cacheDir string) {
+	inspect := func(ctx context.Context, request mcp.ReadResourceRequest, symbol bool) ([]mcp.ResourceContents, error) {
+		pkgPath := templateArgument(request, "package")
+		if pkgPath == "" {
+			return nil, fmt.Errorf("no package in %s", request.Params.URI)
+		}
+		options := DefaultInspectOptions(workspaceDir)
+		options.MetadataCacheDir = cacheDir
+		if symbol {
+			options.SymbolName = templateArgument(request, "symbol")
+		}
+		result, err := InspectStructured(ctx, pkgPath, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", request.Params.URI, err)
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "text/plain",
+				Text:     result.String(),
+			},
+		}, nil
+	}
+
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(
+		symbolResourceTemplate,
+		"Go symbol",
+		mcp.WithTemplateDescription("Inspect output of a package level function, type, variable or constant, e.g. gosym://example.com/app/pkg/Name"),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return inspect(ctx, request, true)
+	})
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(
+		packageResourceTemplate,
+		"Go package",
+		mcp.WithTemplateDescription("Inspect output of a package, e.g. gopkg://example.com/app/pkg"),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return inspect(ctx, request, false)
+	})
+}
+
+// templateArgument returns the value of a variable of the resource template matched by the request
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		return strings.Join(value, ",")
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSymbolResources(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a nested package
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"store/store.go": {
+				"package store",                   // 1
+				"",                                // 2
+				"// Open opens the store at path", // 3
+				"func Open(path string) error {",  // 4
+				"\treturn nil",                    // 5
+				"}",                               // 6
+				"",                                // 7
+				"// Close closes the store",       // 8
+				"func Close() {}",                 // 9
+				"",                                // 10
+			},
+		})
+	}
+
+	readResource := func(t *testing.T, dir string, uri string) (string, *mcp.JSONRPCError) {
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodResourcesRead),
+			"params":  map[string]any{"uri": uri},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch response := NewMCPServer(WithSymbolResources(dir)).HandleMessage(context.Background(), encoded).(type) {
+		case mcp.JSONRPCResponse:
+			result := response.Result.(mcp.ReadResourceResult)
+			if len(result.Contents) != 1 {
+				t.Fatalf("Expected 1 content, got %d", len(result.Contents))
+			}
+			return result.Contents[0].(mcp.TextResourceContents).Text, nil
+		case mcp.JSONRPCError:
+			return "", &response
+		default:
+			t.Fatalf("Unexpected response %T", response)
+		}
+		return "", nil
+	}
+
+	t.Run("symbol resource", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		text, rpcErr := readResource(t, dir, "gosym://example.com/app/store/Open")
+		if rpcErr != nil {
+			t.Fatalf("Failed to read symbol resource: %+v", rpcErr.Error)
+		}
+		if !strings.Contains(text, "func Open(path string) error") {
+			t.Errorf("Expected the Open function, got: %s", text)
+		}
+		if strings.Contains(text, "func Close()") {
+			t.Errorf("Expected only the Open function, got: %s", text)
+		}
+	})
+
+	t.Run("package resource", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		text, rpcErr := readResource(t, dir, "gopkg://example.com/app/store")
+		if rpcErr != nil {
+			t.Fatalf("Failed to read package resource: %+v", rpcErr.Error)
+		}
+		for _, expected := range []string{"func Open(path string) error", "func Close()"} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the package, got: %s", expected, text)
+			}
+		}
+	})
+
+	t.Run("missing package", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		if _, rpcErr := readResource(t, dir, "gopkg://example.com/app/missing"); rpcErr == nil {
+			t.Error("Expected an error reading a missing package")
+		}
+	})
+}
@@ -0,0 +1,654 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	terraformToolName        = "terraform_schema"
+	terraformToolDescription = `Lists the resources and data sources of a Terraform provider written with terraform-plugin-sdk/v2 or terraform-plugin-framework, with their attribute schemas and the functions implementing them:
+
+• SDKv2: functions returning a schema.Resource literal, named by the ResourcesMap and DataSourcesMap of the provider, with the Create, Read, Update and Delete functions of the resource.
+• Framework: types with a Schema method of resource.Resource or datasource.DataSource, named by their Metadata method, with their Create, Read, Update, Delete and ImportState methods.
+
+Attributes are listed with their type, whether they are required, optional, computed or sensitive, whether changing them forces a new resource (ForceNew or a RequiresReplace plan modifier), their description and line, and nested attributes and blocks below them.
+
+The source is parsed without type checking, so only schemas written as literals in the function or method are found. Pass resource to only list the resources and data sources whose name contains it.`
+)
+
+func AddTerraformTool(mcpServer *server.MCPServer) {
+	handleTerraform := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		resource := request.GetString("resource", "")
+
+		schema, err := ExtractTerraformSchema(workspaceDir, resource)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error extracting Terraform schemas: %v", err)), nil
+		}
+		return mcp.NewToolResultText(schema.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		terraformToolName,
+		mcp.WithDescription(terraformToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module of the provider"),
+			mcp.Required(),
+		),
+		mcp.WithString("resource",
+			mcp.Description("Only list resources and data sources whose name contains this, e.g. example_server"),
+		),
+	), handleTerraform)
+}
+
+// TerraformSchema lists the resources and data sources of a Terraform provider
+type TerraformSchema struct {
+	Module string `json:"module"`
+	// Provider is the type name of a framework provider, which prefixes its resource names
+	Provider  string              `json:"provider,omitempty"`
+	Filter    string              `json:"filter,omitempty"`
+	Resources []TerraformResource `json:"resources,omitempty"`
+}
+
+// TerraformResource is a resource or data source of a provider
+type TerraformResource struct {
+	// Name is the type name used in configurations, e.g. example_server
+	Name string `json:"name"`
+	// Kind is resource, data source or ephemeral resource
+	Kind string `json:"kind"`
+	// SDK is sdkv2 or framework
+	SDK string `json:"sdk"`
+	// Implementation is the function returning the schema.Resource or the type implementing it
+	Implementation string               `json:"implementation"`
+	File           string               `json:"file"`
+	Line           int                  `json:"line"`
+	Operations     []TerraformOperation `json:"operations,omitempty"`
+	Attributes     []TerraformAttribute `json:"attributes,omitempty"`
+}
+
+// TerraformOperation is the function or method implementing an operation of a resource
+type TerraformOperation struct {
+	// Name is create, read, update, delete or import
+	Name     string `json:"name"`
+	Function string `json:"function"`
+	// File and Line are empty when the function is declared outside of the module
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// TerraformAttribute is an attribute or block of a resource schema
+type TerraformAttribute struct {
+	Name string `json:"name"`
+	// Type is the attribute type, e.g. string, list of string or list nested block
+	Type        string               `json:"type"`
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Optional    bool                 `json:"optional,omitempty"`
+	Computed    bool                 `json:"computed,omitempty"`
+	Sensitive   bool                 `json:"sensitive,omitempty"`
+	ForceNew    bool                 `json:"force_new,omitempty"`
+	Line        int                  `json:"line"`
+	Attributes  []TerraformAttribute `json:"attributes,omitempty"`
+}
+
+// terraformOperations are the operations of a resource, with the fields of an SDKv2
+// schema.Resource and the methods of a framework resource implementing them
+var terraformOperations = []struct {
+	name   string
+	fields []string
+	method string
+}{
+	{"create", []string{"CreateContext", "CreateWithoutTimeout", "Create"}, "Create"},
+	{"read", []string{"ReadContext", "ReadWithoutTimeout", "Read"}, "Read"},
+	{"update", []string{"UpdateContext", "UpdateWithoutTimeout", "Update"}, "Update"},
+	{"delete", []string{"DeleteContext", "DeleteWithoutTimeout", "Delete"}, "Delete"},
+	{"import", nil, "ImportState"},
+}
+
+// terraformKinds are the kinds of resources in the order they are listed
+var terraformKinds = []string{"resource", "data source", "ephemeral resource"}
+
+// sdkv2Types are the attribute types of SDKv2 schemas
+var sdkv2Types = map[string]string{
+	"TypeBool":   "bool",
+	"TypeInt":    "int",
+	"TypeFloat":  "float",
+	"TypeString": "string",
+	"TypeList":   "list",
+	"TypeMap":    "map",
+	"TypeSet":    "set",
+}
+
+// terraformRegistration is a resource function registered in the ResourcesMap or
+// DataSourcesMap of an SDKv2 provider
+type terraformRegistration struct {
+	name string
+	kind string
+}
+
+// ExtractTerraformSchema lists the resources and data sources of the provider in the
+// module containing workspaceDir whose name contains filter, or all when filter is empty
+func ExtractTerraformSchema(workspaceDir string, filter string) (*TerraformSchema, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := loadModuleSources(workspaceDir, false)
+	if err != nil {
+		return nil, err
+	}
+	schema := &TerraformSchema{Module: module.path, Filter: filter}
+
+	// Providers are found first, as they name the resources of both SDKs
+	registrations := make(map[string]terraformRegistration)
+	for _, pkg := range module.packages {
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Body == nil {
+					continue
+				}
+				if frameworkRequest(funcDecl, "Metadata") == "provider" {
+					if name, ok := assignedString(funcDecl.Body, "TypeName", ""); ok {
+						schema.Provider = name
+					}
+				}
+				ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+					kv, ok := node.(*ast.KeyValueExpr)
+					if !ok {
+						return true
+					}
+					kind := ""
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "ResourcesMap" {
+						kind = "resource"
+					} else if ok && key.Name == "DataSourcesMap" {
+						kind = "data source"
+					}
+					lit, ok := kv.Value.(*ast.CompositeLit)
+					if kind == "" || !ok {
+						return true
+					}
+					for _, elt := range lit.Elts {
+						entry, ok := elt.(*ast.KeyValueExpr)
+						if !ok {
+							continue
+						}
+						name, ok := stringLiteral(entry.Key)
+						call, isCall := entry.Value.(*ast.CallExpr)
+						if !ok || !isCall {
+							continue
+						}
+						registrations[calledName(call.Fun)] = terraformRegistration{name: name, kind: kind}
+					}
+					return false
+				})
+			}
+		}
+	}
+
+	for _, pkg := range module.packages {
+		functions := make(map[string]*ast.FuncDecl)
+		files := make(map[*ast.FuncDecl]*sourceFile)
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+					functions[funcDeclName(funcDecl)] = funcDecl
+					files[funcDecl] = file
+				}
+			}
+		}
+		location := func(name string) (string, int) {
+			funcDecl, ok := functions[name]
+			if !ok {
+				return "", 0
+			}
+			file := files[funcDecl]
+			return module.relPath(file.path), file.fset.Position(funcDecl.Pos()).Line
+		}
+
+		for _, file := range pkg.files {
+			for _, decl := range file.ast.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Body == nil {
+					continue
+				}
+				resource := TerraformResource{
+					Implementation: funcDeclName(funcDecl),
+					File:           module.relPath(file.path),
+					Line:           file.fset.Position(funcDecl.Pos()).Line,
+				}
+				if lit := returnedResource(funcDecl.Body); lit != nil {
+					resource.SDK = "sdkv2"
+					registration, ok := registrations[funcDecl.Name.Name]
+					if !ok {
+						// Unregistered resources are named by their function
+						registration = terraformRegistration{name: funcDecl.Name.Name, kind: "resource"}
+						if strings.HasPrefix(funcDecl.Name.Name, "dataSource") {
+							registration.kind = "data source"
+						}
+					}
+					resource.Name, resource.Kind = registration.name, registration.kind
+					fields := compositeFields(lit)
+					for _, operation := range terraformOperations {
+						for _, field := range operation.fields {
+							if value, ok := fields[field]; ok {
+								function := types.ExprString(value)
+								file, line := location(function)
+								resource.Operations = append(resource.Operations, TerraformOperation{
+									Name:     operation.name,
+									Function: function,
+									File:     file,
+									Line:     line,
+								})
+								break
+							}
+						}
+					}
+					resource.Attributes = sdkv2Attributes(file.fset, fields["Schema"])
+				} else if kind := frameworkRequest(funcDecl, "Schema"); funcDecl.Recv != nil && kind != "" && kind != "provider" {
+					lit := assignedLiteral(funcDecl.Body, "Schema")
+					if lit == nil {
+						continue
+					}
+					resource.SDK = "framework"
+					resource.Kind = map[string]string{
+						"resource":   "resource",
+						"datasource": "data source",
+						"ephemeral":  "ephemeral resource",
+					}[kind]
+					if resource.Kind == "" {
+						resource.Kind = kind
+					}
+					receiver := receiverTypeName(funcDecl.Recv.List[0].Type)
+					resource.Implementation = receiver
+					resource.Name = receiver
+					if metadata, ok := functions[receiver+".Metadata"]; ok && metadata.Body != nil {
+						if name, ok := assignedString(metadata.Body, "TypeName", schema.Provider); ok {
+							resource.Name = name
+						}
+					}
+					for _, operation := range terraformOperations {
+						function := receiver + "." + operation.method
+						if file, line := location(function); file != "" {
+							resource.Operations = append(resource.Operations, TerraformOperation{
+								Name:     operation.name,
+								Function: function,
+								File:     file,
+								Line:     line,
+							})
+						}
+					}
+					resource.Attributes = frameworkAttributes(file.fset, compositeFields(lit))
+				} else {
+					continue
+				}
+				if filter != "" && !strings.Contains(resource.Name, filter) {
+					continue
+				}
+				schema.Resources = append(schema.Resources, resource)
+			}
+		}
+	}
+	slices.SortStableFunc(schema.Resources, func(a, b TerraformResource) int {
+		if a.Kind != b.Kind {
+			return slices.Index(terraformKinds, a.Kind) - slices.Index(terraformKinds, b.Kind)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return schema, nil
+}
+
+// frameworkRequest returns the package of the request type of a framework method with
+// the given name, e.g. resource for Schema(ctx, resource.SchemaRequest, *resource.SchemaResponse)
+func frameworkRequest(decl *ast.FuncDecl, method string) string {
+	if decl.Recv == nil || decl.Name.Name != method || decl.Type.Params.NumFields() != 3 {
+		return ""
+	}
+	// The request is the second parameter, after the context
+	var params []ast.Expr
+	for _, field := range decl.Type.Params.List {
+		for range max(len(field.Names), 1) {
+			params = append(params, field.Type)
+		}
+	}
+	request := params[1]
+	selector, ok := request.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != method+"Request" {
+		return ""
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return pkg.Name
+}
+
+// returnedResource returns the schema.Resource literal with a Schema field returned by
+// a function body, outside of function literals
+func returnedResource(body *ast.BlockStmt) *ast.CompositeLit {
+	var resource *ast.CompositeLit
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != 1 {
+				return false
+			}
+			lit := compositeLiteral(node.Results[0])
+			if lit == nil || lit.Type == nil || !strings.HasSuffix(types.ExprString(lit.Type), "Resource") {
+				return false
+			}
+			if _, ok := compositeFields(lit)["Schema"]; ok {
+				resource = lit
+			}
+			return false
+		}
+		return resource == nil
+	})
+	return resource
+}
+
+// assignedLiteral returns the composite literal assigned to the named field in a body,
+// e.g. resp.Schema = schema.Schema{...}
+func assignedLiteral(body *ast.BlockStmt, field string) *ast.CompositeLit {
+	var lit *ast.CompositeLit
+	ast.Inspect(body, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return lit == nil
+		}
+		if selector, ok := assign.Lhs[0].(*ast.SelectorExpr); ok && selector.Sel.Name == field {
+			lit = compositeLiteral(assign.Rhs[0])
+		}
+		return lit == nil
+	})
+	return lit
+}
+
+// assignedString evaluates the string assigned to the named field in a body, e.g.
+// resp.TypeName = req.ProviderTypeName + "_server", with provider as the value of
+// ProviderTypeName, or <provider> when it is unknown
+func assignedString(body *ast.BlockStmt, field string, provider string) (string, bool) {
+	if provider == "" {
+		provider = "<provider>"
+	}
+	var value string
+	var found bool
+	var evaluate func(expr ast.Expr) string
+	evaluate = func(expr ast.Expr) string {
+		switch expr := expr.(type) {
+		case *ast.BinaryExpr:
+			if expr.Op == token.ADD {
+				return evaluate(expr.X) + evaluate(expr.Y)
+			}
+		case *ast.ParenExpr:
+			return evaluate(expr.X)
+		case *ast.SelectorExpr:
+			if expr.Sel.Name == "ProviderTypeName" {
+				return provider
+			}
+		}
+		if s, ok := stringLiteral(expr); ok {
+			return s
+		}
+		return types.ExprString(expr)
+	}
+	ast.Inspect(body, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || found || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return !found
+		}
+		if selector, ok := assign.Lhs[0].(*ast.SelectorExpr); ok && selector.Sel.Name == field {
+			value, found = evaluate(assign.Rhs[0]), true
+		}
+		return !found
+	})
+	return value, found
+}
+
+// sdkv2Attributes returns the attributes of an SDKv2 map[string]*schema.Schema literal
+func sdkv2Attributes(fset *token.FileSet, expr ast.Expr) []TerraformAttribute {
+	lit := compositeLiteral(expr)
+	if lit == nil {
+		return nil
+	}
+	var attributes []TerraformAttribute
+	for _, elt := range lit.Elts {
+		entry, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		name, ok := stringLiteral(entry.Key)
+		value := compositeLiteral(entry.Value)
+		if !ok || value == nil {
+			continue
+		}
+		fields := compositeFields(value)
+		attribute := TerraformAttribute{
+			Name:      name,
+			Required:  isTrue(fields["Required"]),
+			Optional:  isTrue(fields["Optional"]),
+			Computed:  isTrue(fields["Computed"]),
+			Sensitive: isTrue(fields["Sensitive"]),
+			ForceNew:  isTrue(fields["ForceNew"]),
+			Line:      fset.Position(entry.Pos()).Line,
+		}
+		attribute.Description, _ = stringLiteral(fields["Description"])
+		if selector, ok := fields["Type"].(*ast.SelectorExpr); ok {
+			attribute.Type = sdkv2Types[selector.Sel.Name]
+		}
+		if attribute.Type == "" {
+			attribute.Type = types.ExprString(fields["Type"])
+		}
+		// Elem is the element schema of lists, sets and maps, or a nested block
+		if elem := compositeLiteral(fields["Elem"]); elem != nil {
+			elemFields := compositeFields(elem)
+			if nested, ok := elemFields["Schema"]; ok && strings.HasSuffix(types.ExprString(elem.Type), "Resource") {
+				attribute.Type += " nested block"
+				attribute.Attributes = sdkv2Attributes(fset, nested)
+			} else if selector, ok := elemFields["Type"].(*ast.SelectorExpr); ok && sdkv2Types[selector.Sel.Name] != "" {
+				attribute.Type += " of " + sdkv2Types[selector.Sel.Name]
+			}
+		}
+		attributes = append(attributes, attribute)
+	}
+	return attributes
+}
+
+// frameworkAttributes returns the attributes and blocks of the fields of a framework
+// schema, nested attribute object or block literal
+func frameworkAttributes(fset *token.FileSet, fields map[string]ast.Expr) []TerraformAttribute {
+	var attributes []TerraformAttribute
+	for _, key := range []string{"Attributes", "Blocks"} {
+		lit := compositeLiteral(fields[key])
+		if lit == nil {
+			continue
+		}
+		for _, elt := range lit.Elts {
+			entry, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			name, ok := stringLiteral(entry.Key)
+			value := compositeLiteral(entry.Value)
+			if !ok || value == nil || value.Type == nil {
+				continue
+			}
+			valueFields := compositeFields(value)
+			attribute := TerraformAttribute{
+				Name:      name,
+				Type:      frameworkType(value.Type),
+				Required:  isTrue(valueFields["Required"]),
+				Optional:  isTrue(valueFields["Optional"]),
+				Computed:  isTrue(valueFields["Computed"]),
+				Sensitive: isTrue(valueFields["Sensitive"]),
+				Line:      fset.Position(entry.Pos()).Line,
+			}
+			attribute.Description, _ = stringLiteral(valueFields["Description"])
+			if attribute.Description == "" {
+				attribute.Description, _ = stringLiteral(valueFields["MarkdownDescription"])
+			}
+			if element, ok := valueFields["ElementType"].(*ast.SelectorExpr); ok {
+				attribute.Type += " of " + strings.ToLower(strings.TrimSuffix(element.Sel.Name, "Type"))
+			}
+			if modifiers, ok := valueFields["PlanModifiers"]; ok {
+				ast.Inspect(modifiers, func(node ast.Node) bool {
+					if call, ok := node.(*ast.CallExpr); ok && strings.HasPrefix(calledName(call.Fun), "RequiresReplace") {
+						attribute.ForceNew = true
+					}
+					return !attribute.ForceNew
+				})
+			}
+			// Nested attributes hold their attributes in a NestedObject, except single ones
+			if nested := compositeLiteral(valueFields["NestedObject"]); nested != nil {
+				attribute.Attributes = frameworkAttributes(fset, compositeFields(nested))
+			} else {
+				attribute.Attributes = frameworkAttributes(fset, valueFields)
+			}
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes
+}
+
+// frameworkType returns the type of a framework attribute or block type, e.g. list
+// nested block for schema.ListNestedBlock and string for schema.StringAttribute
+func frameworkType(expr ast.Expr) string {
+	name := types.ExprString(expr)
+	if selector, ok := expr.(*ast.SelectorExpr); ok {
+		name = selector.Sel.Name
+	}
+	name = strings.TrimSuffix(name, "Attribute")
+	var words []string
+	start := 0
+	for i, r := range name {
+		// Words start at upper case letters following lower case ones, e.g. ListNested
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(rune(name[i-1])) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+	return strings.ToLower(strings.Join(words, " "))
+}
+
+// compositeLiteral returns the composite literal of an expression like &T{...}
+func compositeLiteral(expr ast.Expr) *ast.CompositeLit {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	lit, _ := expr.(*ast.CompositeLit)
+	return lit
+}
+
+// compositeFields returns the values of the keyed fields of a struct literal
+func compositeFields(lit *ast.CompositeLit) map[string]ast.Expr {
+	fields := make(map[string]ast.Expr)
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				fields[key.Name] = kv.Value
+			}
+		}
+	}
+	return fields
+}
+
+// stringLiteral returns the value of a string literal
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// isTrue reports whether an expression is the identifier true
+func isTrue(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+// calledName returns the name of a called function without its package or receiver
+func calledName(fun ast.Expr) string {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return types.ExprString(fun)
+}
+
+func (schema *TerraformSchema) String() string {
+	if len(schema.Resources) == 0 {
+		if schema.Filter != "" {
+			return fmt.Sprintf("No Terraform resources or data sources matching '%s' found in %s", schema.Filter, schema.Module)
+		}
+		return fmt.Sprintf("No Terraform resources or data sources found in %s", schema.Module)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Terraform resources and data sources of %s:\n", schema.Module)
+	for _, resource := range schema.Resources {
+		fmt.Fprintf(&b, "\n%s %s, %s %s (%s:%d)\n", resource.Kind, resource.Name, resource.SDK,
+			resource.Implementation, resource.File, resource.Line)
+		for _, operation := range resource.Operations {
+			fmt.Fprintf(&b, "  %s: %s", operation.Name, operation.Function)
+			if operation.File != "" {
+				fmt.Fprintf(&b, " (%s:%d)", operation.File, operation.Line)
+			}
+			b.WriteString("\n")
+		}
+		if len(resource.Attributes) > 0 {
+			b.WriteString("  attributes:\n")
+			writeTerraformAttributes(&b, resource.Attributes, "    ")
+		}
+	}
+	return b.String()
+}
+
+func writeTerraformAttributes(b *strings.Builder, attributes []TerraformAttribute, indent string) {
+	for _, attribute := range attributes {
+		fmt.Fprintf(b, "%s%s %s", indent, attribute.Name, attribute.Type)
+		for _, flag := range []struct {
+			set  bool
+			name string
+		}{
+			{attribute.Required, "required"},
+			{attribute.Optional, "optional"},
+			{attribute.Computed, "computed"},
+			{attribute.Sensitive, "sensitive"},
+			{attribute.ForceNew, "forces new resource"},
+		} {
+			if flag.set {
+				fmt.Fprintf(b, ", %s", flag.name)
+			}
+		}
+		fmt.Fprintf(b, " (line %d)", attribute.Line)
+		if attribute.Description != "" {
+			fmt.Fprintf(b, ": %s", attribute.Description)
+		}
+		b.WriteString("\n")
+		writeTerraformAttributes(b, attribute.Attributes, indent+"  ")
+	}
+}
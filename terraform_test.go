@@ -0,0 +1,197 @@
+package go_mcp_tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerraformSchema(t *testing.T) {
+	t.Parallel()
+
+	// Parsed without type checking, so the terraform-plugin imports need not resolve
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/terraform-provider-example", "", "go 1.22", ""},
+		"sdk/provider.go": {
+			"package sdk", // 1
+			"",
+			"import \"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema\"", // 3
+			"",
+			"func Provider() *schema.Provider {", // 5
+			"	return &schema.Provider{",
+			"		ResourcesMap:   map[string]*schema.Resource{\"example_server\": resourceServer()},",
+			"		DataSourcesMap: map[string]*schema.Resource{\"example_image\": dataSourceImage()},",
+			"	}",
+			"}",
+			"",
+		},
+		"sdk/resource_server.go": {
+			"package sdk", // 1
+			"",
+			"import \"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema\"", // 3
+			"",
+			"func resourceServer() *schema.Resource {", // 5
+			"	return &schema.Resource{",
+			"		CreateContext: resourceServerCreate,",
+			"		ReadContext:   resourceServerRead,",
+			"		DeleteContext: schema.NoopContext,",
+			"		Schema: map[string]*schema.Schema{", // 10
+			"			\"name\": {Type: schema.TypeString, Required: true, ForceNew: true, Description: \"Name of the server\"},",
+			"			\"tags\": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},",
+			"			\"disk\": {",
+			"				Type:     schema.TypeList,",
+			"				Optional: true,", // 15
+			"				Elem: &schema.Resource{",
+			"					Schema: map[string]*schema.Schema{",
+			"						\"size\": {Type: schema.TypeInt, Required: true},",
+			"					},",
+			"				},", // 20
+			"			},",
+			"		},",
+			"	}",
+			"}",
+			"", // 25
+			"func resourceServerCreate() {}",
+			"",
+			"func resourceServerRead() {}", // 28
+			"",
+			"func dataSourceImage() *schema.Resource {", // 30
+			"	return &schema.Resource{",
+			"		ReadContext: resourceServerRead,",
+			"		Schema:      map[string]*schema.Schema{\"id\": {Type: schema.TypeString, Computed: true}},",
+			"	}",
+			"}",
+			"",
+		},
+		"framework/provider.go": {
+			"package framework", // 1
+			"",
+			"import (", // 3
+			"	\"context\"",
+			"",
+			"	\"github.com/hashicorp/terraform-plugin-framework/provider\"",
+			")",
+			"",
+			"type exampleProvider struct{}", // 9
+			"",
+			"func (p *exampleProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {",
+			"	resp.TypeName = \"example\"",
+			"}",
+			"",
+		},
+		"framework/network.go": {
+			"package framework", // 1
+			"",
+			"import (", // 3
+			"	\"context\"",
+			"",
+			"	\"github.com/hashicorp/terraform-plugin-framework/resource\"",
+			"	\"github.com/hashicorp/terraform-plugin-framework/resource/schema\"",
+			"	\"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier\"",
+			"	\"github.com/hashicorp/terraform-plugin-framework/types\"",
+			")", // 10
+			"",
+			"type networkResource struct{}", // 12
+			"",
+			"func (r *networkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {",
+			"	resp.TypeName = req.ProviderTypeName + \"_network\"", // 15
+			"}",
+			"",
+			"func (r *networkResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {", // 18
+			"	resp.Schema = schema.Schema{",
+			"		Attributes: map[string]schema.Attribute{", // 20
+			"			\"cidr\": schema.StringAttribute{",
+			"				Required:      true,",
+			"				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},",
+			"			},",
+			"			\"token\": schema.StringAttribute{Computed: true, Sensitive: true, MarkdownDescription: \"API token\"},", // 25
+			"			\"dns\":   schema.ListAttribute{Optional: true, ElementType: types.StringType},",
+			"			\"peer\": schema.SingleNestedAttribute{",
+			"				Optional:   true,",
+			"				Attributes: map[string]schema.Attribute{\"id\": schema.Int64Attribute{Required: true}},",
+			"			},", // 30
+			"		},",
+			"		Blocks: map[string]schema.Block{",
+			"			\"route\": schema.ListNestedBlock{",
+			"				NestedObject: schema.NestedBlockObject{",
+			"					Attributes: map[string]schema.Attribute{\"via\": schema.StringAttribute{Required: true}},", // 35
+			"				},",
+			"			},",
+			"		},",
+			"	}",
+			"}", // 40
+			"",
+			"func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {}", // 42
+			"",
+			"func (r *networkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {}", // 44
+			"",
+		},
+	})
+
+	t.Run("providers", func(t *testing.T) {
+		t.Parallel()
+		schema, err := ExtractTerraformSchema(workspace, "")
+		if err != nil {
+			t.Fatalf("Failed to extract schemas: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Terraform resources and data sources of example.com/terraform-provider-example:",
+			"",
+			"resource example_network, framework networkResource (framework/network.go:18)",
+			"  create: networkResource.Create (framework/network.go:42)",
+			"  import: networkResource.ImportState (framework/network.go:44)",
+			"  attributes:",
+			"    cidr string, required, forces new resource (line 21)",
+			"    token string, computed, sensitive (line 25): API token",
+			"    dns list of string, optional (line 26)",
+			"    peer single nested, optional (line 27)",
+			"      id int64, required (line 29)",
+			"    route list nested block (line 33)",
+			"      via string, required (line 35)",
+			"",
+			"resource example_server, sdkv2 resourceServer (sdk/resource_server.go:5)",
+			"  create: resourceServerCreate (sdk/resource_server.go:26)",
+			"  read: resourceServerRead (sdk/resource_server.go:28)",
+			"  delete: schema.NoopContext",
+			"  attributes:",
+			"    name string, required, forces new resource (line 11): Name of the server",
+			"    tags set of string, optional (line 12)",
+			"    disk list nested block, optional (line 13)",
+			"      size int, required (line 18)",
+			"",
+			"data source example_image, sdkv2 dataSourceImage (sdk/resource_server.go:30)",
+			"  read: resourceServerRead (sdk/resource_server.go:28)",
+			"  attributes:",
+			"    id string, computed (line 33)",
+			"",
+		}, "\n")
+		if schema.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, schema)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+		schema, err := ExtractTerraformSchema(workspace, "image")
+		if err != nil {
+			t.Fatalf("Failed to extract schemas: %v", err)
+		}
+		if len(schema.Resources) != 1 || schema.Resources[0].Name != "example_image" {
+			t.Errorf("Expected only example_image, got:\n%s", schema)
+		}
+
+		schema, err = ExtractTerraformSchema(workspace, "missing")
+		if err != nil {
+			t.Fatalf("Failed to extract schemas: %v", err)
+		}
+		if schema.String() != "No Terraform resources or data sources matching 'missing' found in example.com/terraform-provider-example" {
+			t.Errorf("Unexpected schema:\n%s", schema)
+		}
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ExtractTerraformSchema("provider", ""); err == nil {
+			t.Error("Expected an error for a relative path")
+		}
+	})
+}
@@ -0,0 +1,392 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	testConventionsToolName        = "test_conventions"
+	testConventionsToolDescription = `Lists the test frameworks and helpers used by each package of a Go module (testify, go-cmp, gomega, quicktest, bare testing, ...) with the style of its tests: internal or external test packages, subtests, table-driven tests, parallel tests and test helpers. Flags inconsistencies such as packages mixing assertion libraries or deviating from the framework most packages use.
+
+Use it before writing or changing tests to match the conventions of the package. Returns markdown, or JSON with format=json.`
+)
+
+// bareTesting is the framework of test files using only the standard library
+const bareTesting = "bare testing"
+
+// testFrameworks maps import path prefixes of test libraries to their name and kind.
+// Packages using several libraries of the assertion kind are flagged as inconsistent.
+var testFrameworks = []struct {
+	prefix string
+	name   string
+	kind   string
+}{
+	{"github.com/stretchr/testify/suite", "testify/suite", "suite"},
+	{"github.com/stretchr/testify/mock", "testify/mock", "mock"},
+	{"github.com/stretchr/testify", "testify", "assertion"},
+	{"github.com/google/go-cmp", "go-cmp", "comparison"},
+	{"github.com/onsi/gomega", "gomega", "assertion"},
+	{"github.com/onsi/ginkgo", "ginkgo", "suite"},
+	{"github.com/frankban/quicktest", "quicktest", "assertion"},
+	{"github.com/matryer/is", "is", "assertion"},
+	{"gotest.tools", "gotest.tools", "assertion"},
+	{"github.com/alecthomas/assert", "alecthomas/assert", "assertion"},
+	{"github.com/smartystreets/goconvey", "goconvey", "assertion"},
+	{"github.com/golang/mock", "gomock", "mock"},
+	{"go.uber.org/mock", "gomock", "mock"},
+	{"github.com/google/gofuzz", "gofuzz", "fuzzing"},
+}
+
+// TestConventionReport describes how the packages of a module write their tests
+type TestConventionReport struct {
+	ModulePath string                   `json:"module_path"`
+	Packages   []PackageTestConventions `json:"packages"`
+	// Dominant is the framework used by most packages, bare testing when none is used
+	Dominant        string   `json:"dominant,omitempty"`
+	Inconsistencies []string `json:"inconsistencies,omitempty"`
+}
+
+// PackageTestConventions describes the tests of a package
+type PackageTestConventions struct {
+	ImportPath string           `json:"import_path"`
+	Files      int              `json:"files"`
+	Frameworks []FrameworkUsage `json:"frameworks"`
+	// Internal and External report test files in the package itself and in its _test package
+	Internal bool `json:"internal"`
+	External bool `json:"external"`
+
+	Tests       int `json:"tests"`
+	Subtests    int `json:"subtests"`
+	TableDriven int `json:"table_driven"`
+	Parallel    int `json:"parallel"`
+	// Helpers are the functions of the test files calling t.Helper
+	Helpers []string `json:"helpers,omitempty"`
+}
+
+// FrameworkUsage is a test framework and the number of test files of a package using it
+type FrameworkUsage struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Files int    `json:"files"`
+}
+
+func AddTestConventionsTool(mcpServer *server.MCPServer) {
+	handleTestConventions := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		format := request.GetString("format", "")
+		if format != "" && format != "markdown" && format != "json" {
+			return toolErrorResult(fmt.Sprintf("Error: unknown format %q, use markdown or json", format)), nil
+		}
+
+		report, err := TestConventions(workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error listing test conventions: %v", err)), nil
+		}
+		if format == "json" {
+			encoded, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return toolErrorResult(fmt.Sprintf("Error encoding test conventions: %v", err)), nil
+			}
+			return mcp.NewToolResultText(string(encoded)), nil
+		}
+		return mcp.NewToolResultText(report.Markdown()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		testConventionsToolName,
+		mcp.WithDescription(testConventionsToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to describe"),
+			mcp.Required(),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format"),
+			mcp.Enum("markdown", "json"),
+			mcp.DefaultString("markdown"),
+		),
+	), handleTestConventions)
+}
+
+// TestConventions describes the tests of the packages of the module containing workspaceDir
+func TestConventions(workspaceDir string) (*TestConventionReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := loadModuleSources(workspaceDir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TestConventionReport{ModulePath: module.path}
+	for _, pkg := range module.packages {
+		conventions := PackageTestConventions{ImportPath: pkg.importPath}
+		frameworks := make(map[string]*FrameworkUsage)
+		for _, file := range pkg.files {
+			if !file.test {
+				continue
+			}
+			conventions.Files++
+			if strings.HasSuffix(file.ast.Name.Name, "_test") {
+				conventions.External = true
+			} else {
+				conventions.Internal = true
+			}
+
+			used := make(map[string]string)
+			for _, spec := range file.ast.Imports {
+				importPath, err := strconv.Unquote(spec.Path.Value)
+				if err != nil {
+					continue
+				}
+				for _, framework := range testFrameworks {
+					if importPath == framework.prefix || strings.HasPrefix(importPath, framework.prefix+"/") {
+						used[framework.name] = framework.kind
+						break
+					}
+				}
+			}
+			if len(used) == 0 {
+				used[bareTesting] = "assertion"
+			}
+			for name, kind := range used {
+				if frameworks[name] == nil {
+					frameworks[name] = &FrameworkUsage{Name: name, Kind: kind}
+				}
+				frameworks[name].Files++
+			}
+
+			describeTestFunctions(file.ast, &conventions)
+		}
+		if conventions.Files == 0 {
+			continue
+		}
+		for _, framework := range frameworks {
+			conventions.Frameworks = append(conventions.Frameworks, *framework)
+		}
+		slices.SortFunc(conventions.Frameworks, func(a, b FrameworkUsage) int {
+			if a.Files != b.Files {
+				return b.Files - a.Files
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		slices.Sort(conventions.Helpers)
+		report.Packages = append(report.Packages, conventions)
+	}
+
+	report.Dominant, report.Inconsistencies = testInconsistencies(report.Packages)
+	return report, nil
+}
+
+// describeTestFunctions counts the tests of the file by style and collects its helpers
+func describeTestFunctions(file *ast.File, conventions *PackageTestConventions) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Recv != nil {
+			continue
+		}
+		if !isTestFunction(fn) {
+			if callsMethod(fn.Body, "", "Helper") {
+				conventions.Helpers = append(conventions.Helpers, fn.Name.Name)
+			}
+			continue
+		}
+
+		conventions.Tests++
+		t := fn.Type.Params.List[0].Names[0].Name
+		if callsMethod(fn.Body, t, "Run") {
+			conventions.Subtests++
+		}
+		if callsMethod(fn.Body, t, "Parallel") {
+			conventions.Parallel++
+		}
+		if isTableDriven(fn.Body) {
+			conventions.TableDriven++
+		}
+	}
+}
+
+// isTestFunction reports whether fn is a test function with a named *testing.T parameter
+func isTestFunction(fn *ast.FuncDecl) bool {
+	if !strings.HasPrefix(fn.Name.Name, "Test") || fn.Name.Name == "TestMain" {
+		return false
+	}
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 {
+		return false
+	}
+	star, ok := params[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := star.X.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == "T"
+}
+
+// callsMethod reports whether the body calls the method on the named receiver, or on any
+// receiver when receiver is empty
+func callsMethod(body *ast.BlockStmt, receiver string, method string) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != method {
+			return !found
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && (receiver == "" || ident.Name == receiver) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// isTableDriven reports whether the body ranges over a table of test cases, a slice or map
+// literal of anonymous structs
+func isTableDriven(body *ast.BlockStmt) bool {
+	hasTable, hasRange := false, false
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.CompositeLit:
+			var element ast.Expr
+			switch typ := n.Type.(type) {
+			case *ast.ArrayType:
+				element = typ.Elt
+			case *ast.MapType:
+				element = typ.Value
+			}
+			if _, ok := element.(*ast.StructType); ok {
+				hasTable = true
+			}
+		case *ast.RangeStmt:
+			hasRange = true
+		}
+		return true
+	})
+	return hasTable && hasRange
+}
+
+// testInconsistencies returns the framework used by most packages and the packages
+// deviating from it or from their own conventions
+func testInconsistencies(packages []PackageTestConventions) (string, []string) {
+	counts := make(map[string]int)
+	for _, pkg := range packages {
+		for _, framework := range pkg.Frameworks {
+			if framework.Kind == "assertion" {
+				counts[framework.Name]++
+			}
+		}
+	}
+	dominant := ""
+	for name, count := range counts {
+		if count > counts[dominant] || (count == counts[dominant] && name < dominant) {
+			dominant = name
+		}
+	}
+
+	var inconsistencies []string
+	for _, pkg := range packages {
+		var assertions []string
+		for _, framework := range pkg.Frameworks {
+			if framework.Kind == "assertion" {
+				assertions = append(assertions, framework.Name)
+			}
+		}
+		slices.Sort(assertions)
+		if len(assertions) > 1 {
+			inconsistencies = append(inconsistencies, fmt.Sprintf(
+				"%s mixes %s for assertions",
+				pkg.ImportPath,
+				strings.Join(assertions, " and "),
+			))
+		} else if counts[dominant] > 1 && len(assertions) == 1 && assertions[0] != dominant {
+			inconsistencies = append(inconsistencies, fmt.Sprintf(
+				"%s uses %s while %d packages use %s",
+				pkg.ImportPath,
+				assertions[0],
+				counts[dominant],
+				dominant,
+			))
+		}
+		if pkg.Parallel > 0 && pkg.Parallel < pkg.Tests {
+			inconsistencies = append(inconsistencies, fmt.Sprintf(
+				"%s has %d of %d tests calling t.Parallel",
+				pkg.ImportPath,
+				pkg.Parallel,
+				pkg.Tests,
+			))
+		}
+	}
+	return dominant, inconsistencies
+}
+
+// Markdown renders the report for reading
+func (report *TestConventionReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Test conventions of %s\n\n", report.ModulePath)
+	if len(report.Packages) == 0 {
+		b.WriteString("No test files found.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d packages with tests", len(report.Packages))
+	if report.Dominant != "" {
+		fmt.Fprintf(&b, ", most use %s", report.Dominant)
+	}
+	b.WriteString(".\n")
+
+	if len(report.Inconsistencies) > 0 {
+		b.WriteString("\n## Inconsistencies\n\n")
+		for _, inconsistency := range report.Inconsistencies {
+			fmt.Fprintf(&b, "- %s\n", inconsistency)
+		}
+	}
+
+	b.WriteString("\n## Packages\n")
+	for _, pkg := range report.Packages {
+		fmt.Fprintf(&b, "\n### %s\n\n", pkg.ImportPath)
+		var frameworks []string
+		for _, framework := range pkg.Frameworks {
+			frameworks = append(frameworks, fmt.Sprintf("%s (%d files)", framework.Name, framework.Files))
+		}
+		fmt.Fprintf(&b, "- Frameworks: %s\n", strings.Join(frameworks, ", "))
+		switch {
+		case pkg.Internal && pkg.External:
+			b.WriteString("- Test package: internal and external (_test)\n")
+		case pkg.External:
+			b.WriteString("- Test package: external (_test)\n")
+		default:
+			b.WriteString("- Test package: internal\n")
+		}
+		fmt.Fprintf(
+			&b,
+			"- Tests: %d (%d with subtests, %d table-driven, %d parallel)\n",
+			pkg.Tests,
+			pkg.Subtests,
+			pkg.TableDriven,
+			pkg.Parallel,
+		)
+		if len(pkg.Helpers) > 0 {
+			fmt.Fprintf(&b, "- Helpers: %s\n", strings.Join(pkg.Helpers, ", "))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTestConventions(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with packages testing in different styles
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"a/a.go": {"package a"},
+			"b/b.go": {"package b"},
+			"c/c.go": {"package c"},
+			"a/a_test.go": {
+				"package a_test",  // 1
+				"",                // 2
+				"import (",        // 3
+				"    \"testing\"", // 4
+				"    \"github.com/stretchr/testify/require\"", // 5
+				")",                            // 6
+				"",                             // 7
+				"func TestAdd(t *testing.T) {", // 8
+				"    t.Parallel()",             // 9
+				"    tests := []struct{ a, b int }{{1, 2}}",                                    // 10
+				"    for _, test := range tests {",                                             // 11
+				"        t.Run(\"\", func(t *testing.T) { require.Equal(t, test.a, test.b) })", // 12
+				"    }", // 13
+				"}",     // 14
+			},
+			"b/b_test.go": {
+				"package b",       // 1
+				"",                // 2
+				"import (",        // 3
+				"    \"testing\"", // 4
+				"    \"github.com/stretchr/testify/assert\"", // 5
+				"    \"github.com/matryer/is\"",              // 6
+				")",                                          // 7
+				"",                                           // 8
+				"func TestB(t *testing.T) { assert.True(t, true); is.New(t) }", // 9
+			},
+			"c/c_test.go": {
+				"package c",                    // 1
+				"",                             // 2
+				"import \"testing\"",           // 3
+				"",                             // 4
+				"func check(t *testing.T) {",   // 5
+				"    t.Helper()",               // 6
+				"}",                            // 7
+				"",                             // 8
+				"func TestOne(t *testing.T) {", // 9
+				"    t.Parallel()",             // 10
+				"    check(t)",                 // 11
+				"}",                            // 12
+				"",                             // 13
+				"func TestTwo(t *testing.T) { check(t) }", // 14
+			},
+		})
+	}
+
+	t.Run("conventions", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		report, err := TestConventions(workspace)
+		if err != nil {
+			t.Fatalf("Failed to list test conventions: %v", err)
+		}
+		if len(report.Packages) != 3 || report.Dominant != "testify" {
+			t.Fatalf("Expected three packages mostly using testify, got %+v", report)
+		}
+
+		a := report.Packages[0]
+		if !a.External || a.Internal || a.Tests != 1 || a.Subtests != 1 || a.TableDriven != 1 || a.Parallel != 1 {
+			t.Errorf("Expected an external, table-driven and parallel test in a, got %+v", a)
+		}
+		c := report.Packages[2]
+		if len(c.Frameworks) != 1 || c.Frameworks[0].Name != bareTesting || c.Tests != 2 || strings.Join(c.Helpers, ",") != "check" {
+			t.Errorf("Expected bare tests with the check helper in c, got %+v", c)
+		}
+
+		expected := []string{
+			"example.com/app/b mixes is and testify for assertions",
+			"example.com/app/c uses bare testing while 2 packages use testify",
+			"example.com/app/c has 1 of 2 tests calling t.Parallel",
+		}
+		if strings.Join(report.Inconsistencies, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected inconsistencies %v, got %v", expected, report.Inconsistencies)
+		}
+	})
+
+	t.Run("test conventions tool", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      testConventionsToolName,
+				"arguments": map[string]any{"workspace_dir": workspace},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		for _, expected := range []string{
+			"3 packages with tests, most use testify.\n",
+			"### example.com/app/a\n\n- Frameworks: testify (1 files)\n- Test package: external (_test)\n- Tests: 1 (1 with subtests, 1 table-driven, 1 parallel)\n",
+			"- Helpers: check\n",
+		} {
+			if result.IsError || !strings.Contains(text, expected) {
+				t.Errorf("Expected %q in the result, got: %s", expected, text)
+			}
+		}
+	})
+}
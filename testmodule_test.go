@@ -0,0 +1,62 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// writeTestModule writes the files to a new temporary directory and returns it. Files map
+// slash separated paths relative to the directory to their lines.
+func writeTestModule(t testing.TB, files map[string][]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFiles(t, dir, files)
+	return dir
+}
+
+// writeTestFiles writes the files to dir, creating their parent directories
+func writeTestFiles(t testing.TB, dir string, files map[string][]string) {
+	t.Helper()
+	for name, lines := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// runTestGit runs git in dir as a test user and returns its output
+func runTestGit(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+// callTool calls a tool of the server without a session and returns its result
+func callTool(t testing.TB, mcpServer *server.MCPServer, name string, arguments map[string]any) mcp.CallToolResult {
+	t.Helper()
+	encoded, err := toolCallMessage(name, arguments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, ok := mcpServer.HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected a tool result for %s", name)
+	}
+	return response.Result.(mcp.CallToolResult)
+}
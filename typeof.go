@@ -0,0 +1,278 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	typeOfToolName        = "type_of"
+	typeOfToolDescription = `Evaluates the static type of a Go expression on a line of a file with go/types, e.g. "s.handlers[0]" or "len(values) > 0". An expression written on the line is evaluated where it is written, which includes variables declared on the line, any other expression in the scope of the line. Returns the type, whether the expression is a type, constant, variable or value, the constant value and the method set of the type.
+
+Pass implements with an interface (Name, io.Reader or example.com/app/store.Store) to check whether the type implements it and is assignable to it, with the first missing method otherwise.`
+)
+
+func AddTypeOfTool(mcpServer *server.MCPServer) {
+	handleTypeOf := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		filePath, err := request.RequireString("file_path")
+		if err != nil {
+			return nil, err
+		}
+		lineNumber, err := request.RequireInt("line_number")
+		if err != nil {
+			return nil, err
+		}
+		expression, err := request.RequireString("expression")
+		if err != nil {
+			return nil, err
+		}
+		interfaceName := request.GetString("implements", "")
+
+		result, err := TypeOf(ctx, filePath, lineNumber, expression, interfaceName)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error evaluating type: %v", err)), nil
+		}
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		typeOfToolName,
+		mcp.WithDescription(typeOfToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("file_path",
+			mcp.Description("Absolute path of the Go file"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("line_number",
+			mcp.Description("Line of the expression, or of the scope to evaluate it in"),
+			mcp.Required(),
+		),
+		mcp.WithString("expression",
+			mcp.Description("Expression to evaluate, as written on the line or any expression valid at the start of the line"),
+			mcp.Required(),
+		),
+		mcp.WithString("implements",
+			mcp.Description("Interface to check the type against"),
+		),
+	), handleTypeOf)
+}
+
+// ExpressionType describes the static type of an expression
+type ExpressionType struct {
+	Expression string `json:"expression"`
+	Type       string `json:"type"`
+	// Mode is type, constant, variable (addressable), value, builtin or void
+	Mode string `json:"mode"`
+	// Value is the value of a constant expression
+	Value string `json:"value,omitempty"`
+	// Methods is the method set of the type. PointerMethods lists the methods only in the
+	// method set of the pointer to the type.
+	Methods        []string `json:"methods,omitempty"`
+	PointerMethods []string `json:"pointer_methods,omitempty"`
+	// Interface is set when the type was checked against an interface
+	Interface *InterfaceCheck `json:"interface,omitempty"`
+}
+
+// InterfaceCheck is the result of checking a type against an interface
+type InterfaceCheck struct {
+	Name       string `json:"name"`
+	Implements bool   `json:"implements"`
+	Assignable bool   `json:"assignable"`
+	// PointerImplements is set when only the pointer to the type implements the interface
+	PointerImplements bool `json:"pointer_implements,omitempty"`
+	// Missing describes the first missing or mismatched method when the type does not implement the interface
+	Missing string `json:"missing,omitempty"`
+}
+
+// TypeOf evaluates the static type of expression on lineNumber of filePath: where it is
+// written when the line contains it, otherwise in the scope at the start of the line. The
+// type is checked against the interface named interfaceName when it is not empty.
+func TypeOf(ctx context.Context, filePath string, lineNumber int, expression string, interfaceName string) (*ExpressionType, error) {
+	if !filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file_path must be an absolute path, got: %s", filePath)
+	}
+	if lineNumber <= 0 {
+		return nil, fmt.Errorf("line number must be positive, got %d", lineNumber)
+	}
+	if expression == "" {
+		return nil, fmt.Errorf("expression is required")
+	}
+	qualifier, name := "", interfaceName
+	if i := strings.LastIndex(interfaceName, "."); i >= 0 {
+		qualifier, name = interfaceName[:i], interfaceName[i+1:]
+	}
+	var extraPatterns []string
+	if qualifier != "" {
+		extraPatterns = append(extraPatterns, qualifier)
+	}
+	pkg, file, pkgs, err := loadFilePackage(ctx, filePath, extraPatterns...)
+	if err != nil {
+		return nil, err
+	}
+	if lineNumber > pkg.Fset.File(file.Pos()).LineCount() {
+		return nil, fmt.Errorf("line number %d exceeds the length of %s", lineNumber, filePath)
+	}
+
+	typeAndValue, found := writtenExpressionType(pkg, file, lineNumber, expression)
+	if !found {
+		typeAndValue, err = types.Eval(pkg.Fset, pkg.Types, lineScopePos(pkg, file, lineNumber), expression)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate %s: %w", expression, err)
+		}
+	}
+	if typeAndValue.Type == nil {
+		return nil, fmt.Errorf("%s has no type", expression)
+	}
+
+	typeQualifier := types.RelativeTo(pkg.Types)
+	result := &ExpressionType{
+		Expression: expression,
+		Type:       types.TypeString(typeAndValue.Type, typeQualifier),
+		Mode:       expressionMode(typeAndValue),
+	}
+	if typeAndValue.Value != nil {
+		result.Value = typeAndValue.Value.ExactString()
+	}
+
+	t := typeAndValue.Type
+	methods := make(map[string]bool)
+	methodSet := types.NewMethodSet(t)
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj()
+		methods[method.Name()] = true
+		result.Methods = append(result.Methods, method.Name()+strings.TrimPrefix(types.TypeString(method.Type(), typeQualifier), "func"))
+	}
+	_, isPointer := t.Underlying().(*types.Pointer)
+	if !isPointer && !types.IsInterface(t) {
+		pointerMethodSet := types.NewMethodSet(types.NewPointer(t))
+		for i := 0; i < pointerMethodSet.Len(); i++ {
+			method := pointerMethodSet.At(i).Obj()
+			if !methods[method.Name()] {
+				result.PointerMethods = append(result.PointerMethods, method.Name()+strings.TrimPrefix(types.TypeString(method.Type(), typeQualifier), "func"))
+			}
+		}
+	}
+
+	if interfaceName != "" {
+		obj, err := lookupInterface(pkg, pkgs, qualifier, name)
+		if err != nil {
+			return nil, err
+		}
+		iface := obj.Type().Underlying().(*types.Interface)
+		check := &InterfaceCheck{
+			Name:       types.TypeString(obj.Type(), typeQualifier),
+			Implements: types.Implements(t, iface),
+			Assignable: types.AssignableTo(t, obj.Type()),
+		}
+		if !check.Implements {
+			check.PointerImplements = !isPointer && types.Implements(types.NewPointer(t), iface)
+			if method, wrongType := types.MissingMethod(t, iface, true); method != nil {
+				switch {
+				case check.PointerImplements:
+					check.Missing = "method " + method.Name() + " has a pointer receiver"
+				case wrongType:
+					check.Missing = "wrong signature of method " + types.ObjectString(method, typeQualifier)
+				default:
+					check.Missing = "missing method " + types.ObjectString(method, typeQualifier)
+				}
+			}
+		}
+		result.Interface = check
+	}
+	return result, nil
+}
+
+// writtenExpressionType returns the type of the first occurrence of expression written on
+// lineNumber of the file, or false if the line does not contain it as a whole expression
+func writtenExpressionType(pkg *packages.Package, file *ast.File, lineNumber int, expression string) (types.TypeAndValue, bool) {
+	tokenFile := pkg.Fset.File(file.Pos())
+	line, err := readSourceLines(tokenFile.Name(), lineNumber, lineNumber, nil)
+	if err != nil {
+		return types.TypeAndValue{}, false
+	}
+	for offset := 0; ; {
+		index := strings.Index(line[offset:], expression)
+		if index < 0 {
+			return types.TypeAndValue{}, false
+		}
+		start := tokenFile.LineStart(lineNumber) + token.Pos(offset+index)
+		end := start + token.Pos(len(expression))
+		offset += index + 1
+		path, _ := astutil.PathEnclosingInterval(file, start, end)
+		for _, node := range path {
+			expr, ok := node.(ast.Expr)
+			if !ok || expr.Pos() != start || expr.End() != end {
+				continue
+			}
+			if typeAndValue, ok := pkg.TypesInfo.Types[expr]; ok {
+				return typeAndValue, true
+			}
+			// Declared identifiers are only recorded as definitions
+			if ident, ok := expr.(*ast.Ident); ok && pkg.TypesInfo.Defs[ident] != nil {
+				return types.TypeAndValue{Type: pkg.TypesInfo.Defs[ident].Type()}, true
+			}
+		}
+	}
+}
+
+// expressionMode describes what kind of expression the type and value belong to
+func expressionMode(typeAndValue types.TypeAndValue) string {
+	switch {
+	case typeAndValue.IsVoid():
+		return "void"
+	case typeAndValue.IsType():
+		return "type"
+	case typeAndValue.IsBuiltin():
+		return "builtin"
+	case typeAndValue.Value != nil:
+		return "constant"
+	case typeAndValue.Addressable():
+		return "variable"
+	}
+	return "value"
+}
+
+// String formats the type as human and model readable text
+func (result *ExpressionType) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Expression: %s\nType: %s\nMode: %s\n", result.Expression, result.Type, result.Mode)
+	if result.Value != "" {
+		fmt.Fprintf(&b, "Value: %s\n", result.Value)
+	}
+	if len(result.Methods) > 0 {
+		b.WriteString("\nMethods:\n")
+		for _, method := range result.Methods {
+			fmt.Fprintf(&b, "  %s\n", method)
+		}
+	}
+	if len(result.PointerMethods) > 0 {
+		b.WriteString("\nPointer Methods:\n")
+		for _, method := range result.PointerMethods {
+			fmt.Fprintf(&b, "  %s\n", method)
+		}
+	}
+	if check := result.Interface; check != nil {
+		fmt.Fprintf(&b, "\nInterface %s:\n", check.Name)
+		fmt.Fprintf(&b, "  Implements: %t\n  Assignable: %t\n", check.Implements, check.Assignable)
+		if check.PointerImplements {
+			fmt.Fprintf(&b, "  The pointer *%s implements it\n", result.Type)
+		}
+		if check.Missing != "" {
+			fmt.Fprintf(&b, "  %s\n", check.Missing)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTypeOf(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with a type implementing fmt.Stringer through its pointer
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",         // 1
+				"",                     // 2
+				"const limit = 2 * 21", // 3
+				"",                     // 4
+				"type Counter struct{ counts map[string]int }", // 5
+				"", // 6
+				"func (c Counter) Len() int { return len(c.counts) }", // 7
+				"",                                    // 8
+				"func (c *Counter) String() string {", // 9
+				"\treturn \"counter\"",                // 10
+				"}",                                   // 11
+				"",                                    // 12
+				"func main() {",                       // 13
+				"\tcounter := Counter{}",              // 14
+				"\tprintln(counter.counts[\"a\"] < limit)", // 15
+				"}", // 16
+				"",  // 17
+			},
+		})
+	}
+
+	t.Run("expression on the line", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		result, err := TypeOf(context.Background(), file, 15, "counter", "fmt.Stringer")
+		if err != nil {
+			t.Fatalf("Failed to evaluate type: %v", err)
+		}
+		if result.Expression != "counter" || result.Type != "Counter" || result.Mode != "variable" {
+			t.Errorf("Expected the Counter variable, got %+v", result)
+		}
+		if strings.Join(result.Methods, ",") != "Len() int" || strings.Join(result.PointerMethods, ",") != "String() string" {
+			t.Errorf("Expected the value and pointer method sets, got %v and %v", result.Methods, result.PointerMethods)
+		}
+		check := result.Interface
+		if check == nil || check.Implements || check.Assignable || !check.PointerImplements || check.Missing != "method String has a pointer receiver" {
+			t.Errorf("Expected only the pointer to implement fmt.Stringer, got %+v", check)
+		}
+
+		// Written on the line, the variable is evaluated where it is declared
+		result, err = TypeOf(context.Background(), file, 14, "counter", "")
+		if err != nil {
+			t.Fatalf("Failed to evaluate type: %v", err)
+		}
+		if result.Type != "Counter" {
+			t.Errorf("Expected the declared Counter variable, got %+v", result)
+		}
+	})
+
+	t.Run("expression string", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		for expression, expected := range map[string]string{
+			"counter.counts":     "map[string]int variable",
+			"&counter":           "*Counter value",
+			"limit + 1":          "untyped int constant 43",
+			"counter.Len() == 0": "untyped bool value",
+			"Counter":            "Counter type",
+		} {
+			result, err := TypeOf(context.Background(), file, 15, expression, "")
+			if err != nil {
+				t.Errorf("Failed to evaluate %s: %v", expression, err)
+				continue
+			}
+			got := strings.TrimSpace(result.Type + " " + result.Mode + " " + result.Value)
+			if got != expected {
+				t.Errorf("Expected %s for %s, got %s", expected, expression, got)
+			}
+		}
+		if _, err := TypeOf(context.Background(), file, 13, "counter", ""); err == nil || !strings.Contains(err.Error(), "undefined: counter") {
+			t.Errorf("Expected counter to be out of scope before its declaration, got: %v", err)
+		}
+	})
+
+	t.Run("type of tool", func(t *testing.T) {
+		t.Parallel()
+		file := filepath.Join(createTestWorkspace(t), "main.go")
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name": typeOfToolName,
+				"arguments": map[string]any{
+					"file_path":   file,
+					"line_number": 15,
+					"expression":  "&counter",
+					"implements":  "fmt.Stringer",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		text := toolResultText(&result)
+		expected := "Expression: &counter\nType: *Counter\nMode: value\n\nMethods:\n  Len() int\n  String() string\n\nInterface fmt.Stringer:\n  Implements: true\n  Assignable: true\n"
+		if result.IsError || text != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, text)
+		}
+	})
+}
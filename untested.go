@@ -0,0 +1,260 @@
+package go_mcp_tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+const (
+	untestedExportedToolName        = "untested_exported"
+	untestedExportedToolDescription = `Lists the exported functions and methods of a Go module that no test exercises, neither directly nor through other functions of the module, most referenced first, so the public APIs most worth testing come first. Main packages are skipped.
+
+The functions tests reach are found with a static call graph by Class Hierarchy Analysis, following calls, closures such as subtests and functions passed as values. Calls through interfaces reach every implementation, so a function may be counted as tested without running at run time. References are counted in the non-test code of the module.`
+)
+
+func AddUntestedExportedTool(mcpServer *server.MCPServer) {
+	handleUntestedExported := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := UntestedExported(ctx, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding untested exported symbols: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		untestedExportedToolName,
+		mcp.WithDescription(untestedExportedToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to check"),
+			mcp.Required(),
+		),
+	), handleUntestedExported)
+}
+
+// UntestedReport lists the exported functions and methods of a module no test exercises
+type UntestedReport struct {
+	Module string `json:"module"`
+	// Exported is the number of exported functions and methods checked
+	Exported int              `json:"exported"`
+	Symbols  []UntestedSymbol `json:"symbols,omitempty"`
+}
+
+// UntestedSymbol is an exported function or method not reached from any test
+type UntestedSymbol struct {
+	// Name is the name of the function, prefixed by its receiver type name for methods
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	// File is relative to the module root
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// References is the number of references in the non-test code of the module
+	References int `json:"references"`
+}
+
+// UntestedExported finds the exported functions and methods of the module containing
+// workspaceDir that are not reached from its tests, ordered by their references
+func UntestedExported(ctx context.Context, workspaceDir string) (*UntestedReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	module, err := readModule(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	initial, err := loadPackages(ctx, &packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Dir:   module.root,
+		Env:   packagesEnv(module.root),
+		Tests: true,
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	for _, pkg := range initial {
+		if len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors))
+		}
+	}
+	report := &UntestedReport{Module: module.path}
+
+	// Functions are tracked by position, as test variants of a package declare them again
+	declared := make(map[token.Position]*UntestedSymbol)
+	for _, pkg := range initial {
+		if pkg.Name == "main" || strings.HasSuffix(pkg.PkgPath, "_test") {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			if strings.HasSuffix(pkg.Fset.Position(file.Package).Filename, "_test.go") {
+				continue
+			}
+			for _, decl := range file.Decls {
+				decl, ok := decl.(*ast.FuncDecl)
+				if !ok || !decl.Name.IsExported() || decl.Recv != nil && !token.IsExported(receiverTypeName(decl.Recv.List[0].Type)) {
+					continue
+				}
+				obj, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+				if !ok {
+					continue
+				}
+				position := pkg.Fset.Position(obj.Pos())
+				if _, ok := declared[position]; ok {
+					continue
+				}
+				declared[position] = &UntestedSymbol{
+					Name:    deadCodeName(obj),
+					Package: pkg.PkgPath,
+					File:    module.relPath(position.Filename),
+					Line:    position.Line,
+				}
+			}
+		}
+	}
+	report.Exported = len(declared)
+
+	// References are deduplicated by position for the same reason
+	references := make(map[token.Position]map[token.Position]bool)
+	for _, pkg := range initial {
+		for ident, obj := range pkg.TypesInfo.Uses {
+			fn, ok := obj.(*types.Func)
+			if !ok {
+				continue
+			}
+			use := pkg.Fset.Position(ident.Pos())
+			if strings.HasSuffix(use.Filename, "_test.go") {
+				continue
+			}
+			position := pkg.Fset.Position(fn.Origin().Pos())
+			if _, ok := declared[position]; !ok {
+				continue
+			}
+			if references[position] == nil {
+				references[position] = make(map[token.Position]bool)
+			}
+			references[position][use] = true
+		}
+	}
+
+	prog, _ := ssautil.AllPackages(initial, ssa.InstantiateGenerics)
+	buildProgram(prog)
+	reached := testedFunctions(prog, module)
+
+	for position, symbol := range declared {
+		if reached[position] {
+			continue
+		}
+		symbol.References = len(references[position])
+		report.Symbols = append(report.Symbols, *symbol)
+	}
+	slices.SortFunc(report.Symbols, func(a, b UntestedSymbol) int {
+		return cmp.Or(
+			cmp.Compare(b.References, a.References),
+			strings.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+		)
+	})
+	return report, nil
+}
+
+// testedFunctions returns the positions of the functions of the module reached from its
+// tests through calls, closures and function values, and calls through interfaces to
+// every implementation
+func testedFunctions(prog *ssa.Program, module *moduleSources) map[token.Position]bool {
+	graph := cha.CallGraph(prog)
+	inModule := func(fn *ssa.Function) bool {
+		if origin := fn.Origin(); origin != nil {
+			fn = origin
+		}
+		if fn.Pkg == nil {
+			// Wrappers the compiler generates for methods, e.g. of embedded fields
+			return fn.Synthetic != ""
+		}
+		pkgPath := fn.Pkg.Pkg.Path()
+		return pkgPath == module.path || strings.HasPrefix(pkgPath, module.path+"/")
+	}
+
+	var queue []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		if runByGoTest(fn, prog.Fset.Position(fn.Pos())) {
+			queue = append(queue, fn)
+		}
+	}
+	visited := make(map[*ssa.Function]bool)
+	reached := make(map[token.Position]bool)
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		if visited[fn] || !inModule(fn) {
+			continue
+		}
+		visited[fn] = true
+		origin := fn
+		if fn.Origin() != nil {
+			origin = fn.Origin()
+		}
+		if origin.Pos().IsValid() {
+			reached[prog.Fset.Position(origin.Pos())] = true
+		}
+
+		// Closures run with their function, e.g. subtests passed to t.Run
+		queue = append(queue, fn.AnonFuncs...)
+		// Functions used as values may be called by the code they are passed to
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				for _, operand := range instr.Operands(nil) {
+					if operand == nil {
+						continue
+					}
+					if value, ok := (*operand).(*ssa.Function); ok {
+						queue = append(queue, value)
+					}
+				}
+			}
+		}
+		if node := graph.Nodes[fn]; node != nil {
+			for _, edge := range node.Out {
+				queue = append(queue, edge.Callee.Func)
+			}
+		}
+	}
+	return reached
+}
+
+func (report *UntestedReport) String() string {
+	if len(report.Symbols) == 0 {
+		return fmt.Sprintf("All %d exported functions and methods of %s are exercised by tests", report.Exported, report.Module)
+	}
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		"%d of %d exported functions and methods of %s are not exercised by any test, most referenced first:\n",
+		len(report.Symbols), report.Exported, report.Module,
+	)
+	for _, symbol := range report.Symbols {
+		fmt.Fprintf(&b, "  %s.%s (%s:%d), %d references\n", path.Base(symbol.Package), symbol.Name, symbol.File, symbol.Line, symbol.References)
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUntestedExported(t *testing.T) {
+	t.Parallel()
+
+	workspace := writeTestModule(t, map[string][]string{
+		"go.mod": {"module example.com/app", "", "go 1.22", ""},
+		"store/store.go": {
+			"package store", // 1
+			"",
+			"type Store struct{}", // 3
+			"",
+			"func Open() *Store { return &Store{} }", // 5
+			"",
+			"func (s *Store) Get() int { return s.count() }", // 7
+			"",
+			"func (s *Store) count() int { return 1 }", // 9
+			"",
+			"func (s *Store) Put() { Flush() }", // 11
+			"",
+			"func Flush() {}", // 13
+			"",
+			"func Sync() { Flush() }", // 15
+			"",
+			"func Callback() {}", // 17
+			"",
+			"func Register() func() { return Callback }", // 19
+			"",
+		},
+		"store/store_test.go": {
+			"package store",
+			"",
+			"import \"testing\"",
+			"",
+			"func TestOpen(t *testing.T) {",
+			"\ts := Open()",
+			"\tt.Run(\"get\", func(t *testing.T) { s.Get() })",
+			"\tRegister()",
+			"}",
+			"",
+		},
+		"cmd/main.go": {
+			"package main",
+			"",
+			"import \"example.com/app/store\"",
+			"",
+			"func main() { store.Sync() }",
+			"",
+		},
+	})
+
+	t.Run("untested", func(t *testing.T) {
+		t.Parallel()
+		report, err := UntestedExported(context.Background(), filepath.Join(workspace, "store"))
+		if err != nil {
+			t.Fatalf("Failed to find untested symbols: %v", err)
+		}
+		expected := strings.Join([]string{
+			"3 of 7 exported functions and methods of example.com/app are not exercised by any test, most referenced first:",
+			"  store.Flush (store/store.go:13), 2 references",
+			"  store.Sync (store/store.go:15), 1 references",
+			"  store.Store.Put (store/store.go:11), 0 references",
+			"",
+		}, "\n")
+		if report.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, report.String())
+		}
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		t.Parallel()
+		if _, err := UntestedExported(context.Background(), "store"); err == nil || !strings.Contains(err.Error(), "must be an absolute path") {
+			t.Errorf("Expected a relative path to be refused, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	unusedExportedToolName        = "unused_exported"
+	unusedExportedToolDescription = `Finds the exported package level functions, types, variables and constants of a Go module that are not referenced outside their own package, grouped by package, to help prune the public API. Each symbol is marked as unused or as only used within its package, where it could be unexported instead.
+
+References are resolved with type checking and include the tests of other packages, but not the tests of the declaring package. Main packages are skipped. Pass importers, the directories of other modules importing this one, to also count their references.`
+)
+
+func AddUnusedExportedTool(mcpServer *server.MCPServer) {
+	handleUnusedExported := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		importers := request.GetStringSlice("importers", nil)
+
+		report, err := UnusedExported(ctx, workspaceDir, importers)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error finding unused exported symbols: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		unusedExportedToolName,
+		mcp.WithDescription(unusedExportedToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to check"),
+			mcp.Required(),
+		),
+		mcp.WithArray("importers",
+			mcp.Description("Absolute paths of directories of other Go modules importing this module, whose references also count"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	), handleUnusedExported)
+}
+
+// UnusedExportedReport lists the exported symbols of a module not referenced outside their package
+type UnusedExportedReport struct {
+	Module   string                  `json:"module"`
+	Packages []UnusedExportedPackage `json:"packages,omitempty"`
+}
+
+// UnusedExportedPackage holds the exported symbols of a package without outside references
+type UnusedExportedPackage struct {
+	Path    string                 `json:"path"`
+	Symbols []UnusedExportedSymbol `json:"symbols"`
+}
+
+// UnusedExportedSymbol is an exported symbol without references outside its package
+type UnusedExportedSymbol struct {
+	Name string `json:"name"`
+	// Kind is func, type, var or const
+	Kind string `json:"kind"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// UsedInPackage is set when the symbol is referenced within its package, so it could
+	// be unexported rather than removed
+	UsedInPackage bool `json:"used_in_package"`
+}
+
+// UnusedExported reports the exported package level symbols of the module containing
+// workspaceDir that are not referenced by other packages of the module, their tests or the
+// packages of the modules in the importers directories
+func UnusedExported(ctx context.Context, workspaceDir string, importers []string) (*UnusedExportedReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	root, err := findModuleRoot(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	const mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule
+	pkgs, err := loadPackages(ctx, &packages.Config{Mode: mode, Dir: root, Tests: true}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages found in %s", root)
+	}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, classifyPackageErrors(pkg, fmt.Errorf("package %s has errors: %v", pkg.PkgPath, pkg.Errors))
+		}
+	}
+	report := &UnusedExportedReport{}
+	if pkgs[0].Module != nil {
+		report.Module = pkgs[0].Module.Path
+	}
+
+	// Test variants type check the packages again, so symbols are identified by package
+	// path and name instead of by object
+	type declared struct {
+		pkgPath string
+		symbol  UnusedExportedSymbol
+	}
+	declarations := make(map[string]*declared)
+	for _, pkg := range pkgs {
+		if pkg.Name == "main" || strings.Contains(pkg.ID, " [") || strings.HasSuffix(pkg.ID, ".test") || strings.HasSuffix(pkg.PkgPath, "_test") {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			if !obj.Exported() {
+				continue
+			}
+			position := pkg.Fset.Position(obj.Pos())
+			declarations[symbolKey(obj)] = &declared{
+				pkgPath: pkg.PkgPath,
+				symbol: UnusedExportedSymbol{
+					Name: name,
+					Kind: objectKind(obj),
+					File: position.Filename,
+					Line: position.Line,
+				},
+			}
+		}
+	}
+
+	outside := make(map[string]bool)
+	countReferences := func(pkgs []*packages.Package) {
+		for _, pkg := range pkgs {
+			// External test packages belong to the package they test
+			user := strings.TrimSuffix(pkg.PkgPath, "_test")
+			for ident, obj := range pkg.TypesInfo.Uses {
+				decl, ok := declarations[symbolKey(obj)]
+				if !ok {
+					continue
+				}
+				if user != decl.pkgPath {
+					outside[symbolKey(obj)] = true
+					continue
+				}
+				// Only the package itself, not its tests, makes a symbol worth keeping unexported
+				if !strings.HasSuffix(pkg.Fset.Position(ident.Pos()).Filename, "_test.go") {
+					decl.symbol.UsedInPackage = true
+				}
+			}
+			// Types of values used through their fields and methods are used without naming them
+			for _, selection := range pkg.TypesInfo.Selections {
+				typ := selection.Recv()
+				if pointer, ok := typ.(*types.Pointer); ok {
+					typ = pointer.Elem()
+				}
+				named, ok := typ.(*types.Named)
+				if !ok {
+					continue
+				}
+				key := symbolKey(named.Origin().Obj())
+				if decl, ok := declarations[key]; ok && user != decl.pkgPath {
+					outside[key] = true
+				}
+			}
+		}
+	}
+	countReferences(pkgs)
+	for _, dir := range importers {
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("importers must be absolute paths, got: %s", dir)
+		}
+		importerPkgs, err := loadPackages(ctx, &packages.Config{Mode: mode, Dir: dir, Tests: true}, "./...")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load packages of %s: %w", dir, err)
+		}
+		countReferences(importerPkgs)
+	}
+
+	byPackage := make(map[string][]UnusedExportedSymbol)
+	for key, decl := range declarations {
+		if !outside[key] {
+			byPackage[decl.pkgPath] = append(byPackage[decl.pkgPath], decl.symbol)
+		}
+	}
+	for path, symbols := range byPackage {
+		sort.Slice(symbols, func(i, j int) bool {
+			if symbols[i].File != symbols[j].File {
+				return symbols[i].File < symbols[j].File
+			}
+			return symbols[i].Line < symbols[j].Line
+		})
+		report.Packages = append(report.Packages, UnusedExportedPackage{Path: path, Symbols: symbols})
+	}
+	sort.Slice(report.Packages, func(i, j int) bool { return report.Packages[i].Path < report.Packages[j].Path })
+	return report, nil
+}
+
+// symbolKey identifies a package level object across the type checks of test variants,
+// returning an empty string for other objects
+func symbolKey(obj types.Object) string {
+	if obj == nil || obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+		return ""
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// objectKind returns the keyword declaring a package level object
+func objectKind(obj types.Object) string {
+	switch obj.(type) {
+	case *types.Func:
+		return "func"
+	case *types.TypeName:
+		return "type"
+	case *types.Const:
+		return "const"
+	}
+	return "var"
+}
+
+func (report *UnusedExportedReport) String() string {
+	if len(report.Packages) == 0 {
+		return fmt.Sprintf("All exported symbols of %s are referenced outside their package", report.Module)
+	}
+	count := 0
+	for _, pkg := range report.Packages {
+		count += len(pkg.Symbols)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Exported symbols of %s not referenced outside their package (%d):\n", report.Module, count)
+	for _, pkg := range report.Packages {
+		fmt.Fprintf(&b, "\n%s:\n", pkg.Path)
+		for _, symbol := range pkg.Symbols {
+			usage := "unused"
+			if symbol.UsedInPackage {
+				usage = "only used in its package"
+			}
+			fmt.Fprintf(&b, "  %s %s %s:%d, %s\n", symbol.Kind, symbol.Name, symbol.File, symbol.Line, usage)
+		}
+	}
+	return b.String()
+}
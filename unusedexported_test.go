@@ -0,0 +1,125 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestUnusedExported(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module with exported symbols used to varying degrees
+	// and a second module importing it
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"app/go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"app/main.go": {
+				"package main",                         // 1
+				"",                                     // 2
+				"import \"example.com/app/store\"",     // 3
+				"",                                     // 4
+				"func main() { store.Open().Close() }", // 5
+				"",                                     // 6
+				"func Unused() {}",                     // 7
+				"",                                     // 8
+			},
+			"app/store/store.go": {
+				"package store",                          // 1
+				"",                                       // 2
+				"type Store struct{}",                    // 3
+				"",                                       // 4
+				"func Open() *Store { return &Store{} }", // 5
+				"",                                       // 6
+				"func (s *Store) Close() { Reset() }",    // 7
+				"",                                       // 8
+				"func Reset() {}",                        // 9
+				"",                                       // 10
+				"const Version = \"1\"",                  // 11
+				"",                                       // 12
+				"var Debug bool",                         // 13
+				"",                                       // 14
+			},
+			"app/store/store_test.go": {
+				"package store",      // 1
+				"",                   // 2
+				"import \"testing\"", // 3
+				"",                   // 4
+				"func TestDebug(t *testing.T) { _ = Debug }", // 5
+				"", // 6
+			},
+			"client/go.mod": {"module example.com/client", "", "go 1.22", "", "require example.com/app v0.0.0", "", "replace example.com/app => ../app", ""},
+			"client/client.go": {
+				"package client",                   // 1
+				"",                                 // 2
+				"import \"example.com/app/store\"", // 3
+				"",                                 // 4
+				"var V = store.Version",            // 5
+				"",                                 // 6
+			},
+		})
+	}
+
+	t.Run("unused exported symbols", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+		file := filepath.Join(dir, "app", "store", "store.go")
+
+		report, err := UnusedExported(context.Background(), filepath.Join(dir, "app"), nil)
+		if err != nil {
+			t.Fatalf("Failed to find unused exported symbols: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Exported symbols of example.com/app not referenced outside their package (3):",
+			"",
+			"example.com/app/store:",
+			"  func Reset " + file + ":9, only used in its package",
+			"  const Version " + file + ":11, unused",
+			"  var Debug " + file + ":13, unused",
+			"",
+		}, "\n")
+		if got := report.String(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+	})
+
+	t.Run("importers", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		report, err := UnusedExported(context.Background(), filepath.Join(dir, "app"), []string{filepath.Join(dir, "client")})
+		if err != nil {
+			t.Fatalf("Failed to find unused exported symbols: %v", err)
+		}
+		if got := report.String(); strings.Contains(got, "Version") || !strings.Contains(got, "Reset") {
+			t.Errorf("Expected Version to be used by the client, got:\n%s", got)
+		}
+	})
+
+	t.Run("unused exported tool", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      unusedExportedToolName,
+				"arguments": map[string]any{"workspace_dir": filepath.Join(dir, "app")},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "func Reset") {
+			t.Errorf("Expected the unused exported symbols, got: %s", text)
+		}
+	})
+}
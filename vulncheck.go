@@ -0,0 +1,301 @@
+package go_mcp_tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	vulncheckToolName        = "vulncheck"
+	vulncheckToolDescription = `Runs govulncheck on the Go module containing a directory and reports the known vulnerabilities of its dependencies and the standard library. Vulnerabilities whose functions are called are listed with the call sites in the module reaching them, each shown with the signature of the calling function, followed by vulnerable packages that are imported but whose vulnerable functions are not called and vulnerable modules that are only required.
+
+Requires govulncheck in PATH (go install golang.org/x/vuln/cmd/govulncheck@latest) and access to the vulnerability database.`
+)
+
+func AddVulncheckTool(mcpServer *server.MCPServer) {
+	handleVulncheck := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := Vulncheck(ctx, workspaceDir)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error checking vulnerabilities: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		vulncheckToolName,
+		mcp.WithDescription(vulncheckToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module to check"),
+			mcp.Required(),
+		),
+	), handleVulncheck)
+}
+
+// VulnReport lists the vulnerabilities govulncheck found in a module
+type VulnReport struct {
+	ModuleRoot string `json:"module_root"`
+	// Called lists the vulnerabilities whose vulnerable functions are reached by the module
+	Called []Vulnerability `json:"called,omitempty"`
+	// Imported lists the vulnerabilities of imported packages whose vulnerable functions are not called
+	Imported []Vulnerability `json:"imported,omitempty"`
+	// Required lists the vulnerabilities of required modules whose vulnerable packages are not imported
+	Required []Vulnerability `json:"required,omitempty"`
+}
+
+// Vulnerability is a vulnerability of a module the checked module depends on
+type Vulnerability struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	Module  string   `json:"module"`
+	Version string   `json:"version,omitempty"`
+	// FixedVersion is empty when no fixed version is known
+	FixedVersion string `json:"fixed_version,omitempty"`
+	// Symbols lists the called vulnerable functions, e.g. html.Parse
+	Symbols   []string       `json:"symbols,omitempty"`
+	CallSites []VulnCallSite `json:"call_sites,omitempty"`
+}
+
+// VulnCallSite is a call in the checked module leading to a vulnerable function
+type VulnCallSite struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	// Symbol is the vulnerable function the call leads to
+	Symbol string `json:"symbol"`
+	// Function is the function containing the call
+	Function *SymbolInfo `json:"function,omitempty"`
+}
+
+// govulncheckMessage is a message of the JSON stream written by govulncheck -json
+type govulncheckMessage struct {
+	OSV *struct {
+		ID      string   `json:"id"`
+		Aliases []string `json:"aliases"`
+		Summary string   `json:"summary"`
+	} `json:"osv"`
+	Finding *struct {
+		OSV          string `json:"osv"`
+		FixedVersion string `json:"fixed_version"`
+		Trace        []struct {
+			Module   string `json:"module"`
+			Version  string `json:"version"`
+			Package  string `json:"package"`
+			Function string `json:"function"`
+			Receiver string `json:"receiver"`
+			Position *struct {
+				Filename string `json:"filename"`
+				Line     int    `json:"line"`
+				Column   int    `json:"column"`
+			} `json:"position"`
+		} `json:"trace"`
+	} `json:"finding"`
+}
+
+// Vulncheck runs govulncheck on the packages of the module containing workspaceDir
+func Vulncheck(ctx context.Context, workspaceDir string) (*VulnReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	root, err := findModuleRoot(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	if err := runCommand(ctx, root, nil, &stdout, &stderr, "govulncheck", "-json", "./..."); errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf(
+			"govulncheck is not installed or not in PATH, install it with `go install golang.org/x/vuln/cmd/govulncheck@latest`: %w",
+			err,
+		)
+	} else if err != nil {
+		return nil, fmt.Errorf("govulncheck failed: %v\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseGovulncheckOutput(root, &stdout)
+}
+
+// parseGovulncheckOutput builds the report from the JSON stream of govulncheck -json run in root
+func parseGovulncheckOutput(root string, output io.Reader) (*VulnReport, error) {
+	report := &VulnReport{ModuleRoot: root}
+	vulnerabilities := make(map[string]*Vulnerability)
+	var order []string
+	// Each vulnerability is reported at the most precise level found: called, imported or required
+	levels := make(map[string]int)
+	seen := make(map[string]bool)
+
+	decoder := json.NewDecoder(output)
+	for {
+		var message govulncheckMessage
+		if err := decoder.Decode(&message); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse govulncheck output: %w", err)
+		}
+		if message.OSV != nil {
+			vulnerability := vulnerabilityEntry(vulnerabilities, &order, message.OSV.ID)
+			vulnerability.Aliases = message.OSV.Aliases
+			vulnerability.Summary = message.OSV.Summary
+		}
+		finding := message.Finding
+		if finding == nil || len(finding.Trace) == 0 {
+			continue
+		}
+		vulnerability := vulnerabilityEntry(vulnerabilities, &order, finding.OSV)
+		vulnerable := finding.Trace[0]
+		vulnerability.Module = vulnerable.Module
+		vulnerability.Version = vulnerable.Version
+		vulnerability.FixedVersion = finding.FixedVersion
+		level := 0
+		switch {
+		case vulnerable.Function != "":
+			level = 2
+		case vulnerable.Package != "":
+			level = 1
+		}
+		levels[finding.OSV] = max(levels[finding.OSV], level)
+		if level < 2 {
+			continue
+		}
+
+		symbol := filepath.Base(vulnerable.Package) + "." + vulnerable.Function
+		if vulnerable.Receiver != "" {
+			symbol = filepath.Base(vulnerable.Package) + "." + strings.TrimPrefix(vulnerable.Receiver, "*") + "." + vulnerable.Function
+		}
+		if !seen[finding.OSV+" "+symbol] {
+			seen[finding.OSV+" "+symbol] = true
+			vulnerability.Symbols = append(vulnerability.Symbols, symbol)
+		}
+		// The trace goes from the vulnerable function to the entry point, the call site is the
+		// position of the first frame in the module
+		for _, frame := range finding.Trace[1:] {
+			if frame.Position == nil || frame.Position.Filename == "" {
+				continue
+			}
+			file := frame.Position.Filename
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(root, file)
+			}
+			if !isFileInWorkspace(file, root) {
+				continue
+			}
+			key := fmt.Sprintf("%s %s:%d:%d", finding.OSV, file, frame.Position.Line, frame.Position.Column)
+			if !seen[key] {
+				seen[key] = true
+				callSite := VulnCallSite{File: file, Line: frame.Position.Line, Column: frame.Position.Column, Symbol: symbol}
+				if funcDecl, fset := findFunctionAtLine(file, callSite.Line, nil); funcDecl != nil {
+					info := newFunctionInfo(context.Background(), funcDecl, fset, false, false, DetailSignature, "", nil)
+					callSite.Function = &info
+				}
+				vulnerability.CallSites = append(vulnerability.CallSites, callSite)
+			}
+			break
+		}
+	}
+
+	for _, id := range order {
+		level, found := levels[id]
+		if !found {
+			// Vulnerabilities of modules the module does not use only appear as osv messages
+			continue
+		}
+		switch level {
+		case 2:
+			report.Called = append(report.Called, *vulnerabilities[id])
+		case 1:
+			report.Imported = append(report.Imported, *vulnerabilities[id])
+		default:
+			report.Required = append(report.Required, *vulnerabilities[id])
+		}
+	}
+	return report, nil
+}
+
+// vulnerabilityEntry returns the vulnerability with the id, adding it in order of first appearance
+func vulnerabilityEntry(vulnerabilities map[string]*Vulnerability, order *[]string, id string) *Vulnerability {
+	vulnerability, ok := vulnerabilities[id]
+	if !ok {
+		vulnerability = &Vulnerability{ID: id}
+		vulnerabilities[id] = vulnerability
+		*order = append(*order, id)
+	}
+	return vulnerability
+}
+
+// String formats the report as human and model readable text
+func (report *VulnReport) String() string {
+	if len(report.Called) == 0 && len(report.Imported) == 0 && len(report.Required) == 0 {
+		return fmt.Sprintf("No known vulnerabilities affect %s", report.ModuleRoot)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Vulnerabilities of %s\n", report.ModuleRoot)
+	for _, section := range []struct {
+		title           string
+		vulnerabilities []Vulnerability
+	}{
+		{"Called vulnerable functions", report.Called},
+		{"Imported vulnerable packages, vulnerable functions not called", report.Imported},
+		{"Required vulnerable modules, vulnerable packages not imported", report.Required},
+	} {
+		if len(section.vulnerabilities) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", section.title, len(section.vulnerabilities))
+		for _, vulnerability := range section.vulnerabilities {
+			writeVulnerability(&b, &vulnerability)
+		}
+	}
+	return b.String()
+}
+
+func writeVulnerability(b *strings.Builder, vulnerability *Vulnerability) {
+	b.WriteString("\n")
+	b.WriteString(vulnerability.ID)
+	if len(vulnerability.Aliases) > 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(vulnerability.Aliases, ", "))
+	}
+	if vulnerability.Summary != "" {
+		fmt.Fprintf(b, ": %s", vulnerability.Summary)
+	}
+	fmt.Fprintf(b, "\n  Module: %s %s", vulnerability.Module, vulnerability.Version)
+	if vulnerability.FixedVersion != "" {
+		fmt.Fprintf(b, ", fixed in %s", vulnerability.FixedVersion)
+	} else {
+		b.WriteString(", no fixed version")
+	}
+	b.WriteString("\n")
+	if len(vulnerability.Symbols) > 0 {
+		fmt.Fprintf(b, "  Vulnerable functions: %s\n", strings.Join(vulnerability.Symbols, ", "))
+	}
+	for _, callSite := range vulnerability.CallSites {
+		fmt.Fprintf(b, "  %s:%d:%d reaches %s\n", callSite.File, callSite.Line, callSite.Column, callSite.Symbol)
+		if callSite.Function == nil {
+			continue
+		}
+		var function strings.Builder
+		writeFunction(&function, callSite.Function)
+		for line := range strings.SplitSeq(function.String(), "\n") {
+			if line != "" {
+				fmt.Fprintf(b, "    %s\n", line)
+			}
+		}
+	}
+}
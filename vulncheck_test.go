@@ -0,0 +1,111 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestVulncheck(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a module calling a vulnerable function
+	createTestWorkspace := func(t testing.TB) string {
+		return writeTestModule(t, map[string][]string{
+			"go.mod": {"module example.com/app", "", "go 1.22", ""},
+			"main.go": {
+				"package main",                     // 1
+				"",                                 // 2
+				"import \"golang.org/x/net/html\"", // 3
+				"",                                 // 4
+				"// render parses the page",        // 5
+				"func render(page string) error {", // 6
+				"\t_, err := html.Parse(strings.NewReader(page))", // 7
+				"\treturn err", // 8
+				"}",            // 9
+				"",             // 10
+			},
+		})
+	}
+
+	t.Run("parse output", func(t *testing.T) {
+		t.Parallel()
+		dir := createTestWorkspace(t)
+
+		output := strings.Join([]string{
+			`{"config": {"protocol_version": "v1.0.0", "scanner_name": "govulncheck"}}`,
+			`{"progress": {"message": "Scanning your code and 3 packages across 1 dependent module for known vulnerabilities..."}}`,
+			`{"osv": {"id": "GO-2023-0001", "aliases": ["CVE-2023-0001"], "summary": "Quadratic parsing in golang.org/x/net/html"}}`,
+			`{"osv": {"id": "GO-2023-0002", "summary": "Panic in golang.org/x/net/http2"}}`,
+			`{"osv": {"id": "GO-2023-0003", "summary": "Unrelated vulnerability"}}`,
+			`{"finding": {"osv": "GO-2023-0001", "fixed_version": "v0.7.0", "trace": [{"module": "golang.org/x/net", "version": "v0.1.0"}]}}`,
+			`{"finding": {"osv": "GO-2023-0001", "fixed_version": "v0.7.0", "trace": [{"module": "golang.org/x/net", "version": "v0.1.0", "package": "golang.org/x/net/html"}]}}`,
+			`{"finding": {"osv": "GO-2023-0001", "fixed_version": "v0.7.0", "trace": [` +
+				`{"module": "golang.org/x/net", "version": "v0.1.0", "package": "golang.org/x/net/html", "function": "Parse", "position": {"filename": "/mod/golang.org/x/net@v0.1.0/html/parse.go", "line": 2300, "column": 6}},` +
+				`{"module": "example.com/app", "package": "example.com/app", "function": "render", "position": {"filename": "main.go", "line": 7, "column": 21}},` +
+				`{"module": "example.com/app", "package": "example.com/app", "function": "main"}]}}`,
+			`{"finding": {"osv": "GO-2023-0002", "trace": [{"module": "golang.org/x/net", "version": "v0.1.0", "package": "golang.org/x/net/http2"}]}}`,
+		}, "\n")
+		report, err := parseGovulncheckOutput(dir, strings.NewReader(output))
+		if err != nil {
+			t.Fatalf("Failed to parse govulncheck output: %v", err)
+		}
+		expected := strings.Join([]string{
+			"Vulnerabilities of " + dir,
+			"",
+			"Called vulnerable functions (1):",
+			"",
+			"GO-2023-0001 (CVE-2023-0001): Quadratic parsing in golang.org/x/net/html",
+			"  Module: golang.org/x/net v0.1.0, fixed in v0.7.0",
+			"  Vulnerable functions: html.Parse",
+			"  " + filepath.Join(dir, "main.go") + ":7:21 reaches html.Parse",
+			"    Lines: 6-9",
+			"    Docstring: render parses the page",
+			"    Code:",
+			"    func render(page string) error",
+			"",
+			"Imported vulnerable packages, vulnerable functions not called (1):",
+			"",
+			"GO-2023-0002: Panic in golang.org/x/net/http2",
+			"  Module: golang.org/x/net v0.1.0, no fixed version",
+			"",
+		}, "\n")
+		if got := report.String(); got != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+		}
+
+		empty, err := parseGovulncheckOutput(dir, strings.NewReader(`{"config": {}}`))
+		if err != nil {
+			t.Fatalf("Failed to parse govulncheck output: %v", err)
+		}
+		if got := empty.String(); got != "No known vulnerabilities affect "+dir {
+			t.Errorf("Expected no vulnerabilities, got: %s", got)
+		}
+	})
+
+	t.Run("vulncheck tool", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params": map[string]any{
+				"name":      vulncheckToolName,
+				"arguments": map[string]any{"workspace_dir": "relative/dir"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := NewMCPServer().HandleMessage(context.Background(), encoded).(mcp.JSONRPCResponse)
+		result := response.Result.(mcp.CallToolResult)
+		if text := toolResultText(&result); !result.IsError || !strings.Contains(text, "must be an absolute path") {
+			t.Errorf("Expected an error for a relative directory, got: %s", text)
+		}
+	})
+}
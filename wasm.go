@@ -0,0 +1,394 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	wasmToolName        = "wasm"
+	wasmToolDescription = `Checks the WebAssembly code of a Go module, which type checking on other platforms skips: files constrained to js or wasm by //go:build lines or _js.go and _wasm.go file names, and files importing syscall/js.
+
+The packages containing such files are type checked with GOOS=js GOARCH=wasm, or GOOS=wasip1 with goos set to wasip1, and their build errors are reported. Their use of syscall/js is listed: the values exported to JavaScript with js.Global().Set, the JavaScript globals read, the methods called and properties accessed on js.Value, and the js.FuncOf callbacks with whether they are ever released.`
+)
+
+func AddWasmTool(mcpServer *server.MCPServer) {
+	handleWasm := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, err
+		}
+		goos := request.GetString("goos", "")
+
+		report, err := CheckWasm(ctx, workspaceDir, goos)
+		if err != nil {
+			return toolErrorResult(fmt.Sprintf("Error checking WebAssembly code: %v", err)), nil
+		}
+		return mcp.NewToolResultText(report.String()), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(
+		wasmToolName,
+		mcp.WithDescription(wasmToolDescription),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Absolute path of a directory in the Go module"),
+			mcp.Required(),
+		),
+		mcp.WithString("goos",
+			mcp.Description("Operating system of the WebAssembly target, built with GOARCH=wasm"),
+			mcp.Enum("js", "wasip1"),
+			mcp.DefaultString("js"),
+		),
+	), handleWasm)
+}
+
+// WasmReport holds the WebAssembly files of a module, their build errors and their use of syscall/js
+type WasmReport struct {
+	Module string `json:"module"`
+	// GOOS is js or wasip1, checked with GOARCH=wasm
+	GOOS string `json:"goos"`
+	// Packages are the import paths of the packages checked
+	Packages []string     `json:"packages,omitempty"`
+	Files    []WasmFile   `json:"files,omitempty"`
+	Errors   []Diagnostic `json:"errors,omitempty"`
+	Uses     []JSUse      `json:"uses,omitempty"`
+}
+
+// WasmFile is a file only built for WebAssembly or importing syscall/js
+type WasmFile struct {
+	File string `json:"file"`
+	// Reason is the //go:build constraint, the file name suffix or the syscall/js import
+	Reason string `json:"reason"`
+}
+
+// JSUse is a use of syscall/js
+type JSUse struct {
+	// Kind is export, global, call, property, new, invoke or callback
+	Kind string `json:"kind"`
+	// Name is the JavaScript name, or the Go function of callbacks
+	Name     string `json:"name"`
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	// Released is set for callbacks whose js.Func is released
+	Released bool `json:"released,omitempty"`
+}
+
+// jsUseKinds are the kinds of uses of syscall/js with their titles in the order they are listed
+var jsUseKinds = []struct{ kind, title string }{
+	{"export", "Exported to JavaScript"},
+	{"global", "JavaScript globals"},
+	{"call", "Methods called"},
+	{"property", "Properties accessed"},
+	{"new", "Constructors called"},
+	{"invoke", "Functions invoked"},
+	{"callback", "Callbacks"},
+}
+
+// CheckWasm type checks the packages of the module containing workspaceDir that contain
+// WebAssembly files for GOOS (js by default) and GOARCH=wasm, and lists their syscall/js uses
+func CheckWasm(ctx context.Context, workspaceDir string, goos string) (*WasmReport, error) {
+	if !filepath.IsAbs(workspaceDir) {
+		return nil, fmt.Errorf("workspace_dir must be an absolute path, got: %s", workspaceDir)
+	}
+	if goos == "" {
+		goos = "js"
+	}
+	if goos != "js" && goos != "wasip1" {
+		return nil, fmt.Errorf("goos must be js or wasip1, got: %s", goos)
+	}
+	module, err := loadModuleSources(workspaceDir, true)
+	if err != nil {
+		return nil, err
+	}
+	report := &WasmReport{Module: module.path, GOOS: goos}
+	for _, pkg := range module.packages {
+		found := false
+		for _, file := range pkg.files {
+			if reason := wasmFileReason(file); reason != "" {
+				report.Files = append(report.Files, WasmFile{File: module.relPath(file.path), Reason: reason})
+				found = true
+			}
+		}
+		if found {
+			report.Packages = append(report.Packages, pkg.importPath)
+		}
+	}
+	if len(report.Packages) == 0 {
+		return report, nil
+	}
+
+	pkgs, err := loadPackages(ctx, &packages.Config{
+		Context: ctx,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:   module.root,
+		Env:   []string{"GOOS=" + goos, "GOARCH=wasm"},
+		Tests: true,
+	}, report.Packages...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the packages of %s: %w", module.root, err)
+	}
+
+	// Test variants of a package report the errors and contain the files of it again
+	seen := make(map[string]bool)
+	seenFiles := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, pkgError := range pkg.Errors {
+			diagnostic := Diagnostic{Package: pkg.PkgPath, Message: pkgError.Msg}
+			if pkgError.Pos != "" && pkgError.Pos != "-" {
+				diagnostic.File, diagnostic.Line, diagnostic.Column = parseErrorPosition(pkgError.Pos)
+				diagnostic.File = module.relPath(diagnostic.File)
+			}
+			key := fmt.Sprintf("%s:%d:%d %s", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Message)
+			if diagnostic.File == "" {
+				key = diagnostic.Package + " " + key
+			}
+			if !seen[key] {
+				seen[key] = true
+				report.Errors = append(report.Errors, diagnostic)
+			}
+		}
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			path := pkg.Fset.Position(file.Package).Filename
+			if seenFiles[path] {
+				continue
+			}
+			seenFiles[path] = true
+			report.Uses = append(report.Uses, jsUses(module, pkg, file)...)
+		}
+	}
+	slices.SortStableFunc(report.Errors, func(a, b Diagnostic) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+		return a.Line - b.Line
+	})
+	return report, nil
+}
+
+// wasmFileReason returns why a file is only built for WebAssembly or needs it, or an
+// empty string for other files
+func wasmFileReason(file *sourceFile) string {
+	for _, group := range file.ast.Comments {
+		if group.Pos() > file.ast.Package {
+			break
+		}
+		for _, comment := range group.List {
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				continue
+			}
+			if requiresWasmTag(expr) {
+				return comment.Text
+			}
+		}
+	}
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file.path), ".go"), "_test")
+	for _, suffix := range []string{"_js_wasm", "_wasip1_wasm", "_js", "_wasip1", "_wasm"} {
+		if strings.HasSuffix(name, suffix) {
+			return suffix[1:] + " file name"
+		}
+	}
+	for _, spec := range file.ast.Imports {
+		if spec.Path.Value == `"syscall/js"` {
+			return "imports syscall/js"
+		}
+	}
+	return ""
+}
+
+// requiresWasmTag reports whether a build constraint mentions js, wasm or wasip1 other
+// than negated, as in //go:build js && wasm or //go:build linux || wasip1
+func requiresWasmTag(expr constraint.Expr) bool {
+	switch expr := expr.(type) {
+	case *constraint.TagExpr:
+		return expr.Tag == "js" || expr.Tag == "wasm" || expr.Tag == "wasip1"
+	case *constraint.AndExpr:
+		return requiresWasmTag(expr.X) || requiresWasmTag(expr.Y)
+	case *constraint.OrExpr:
+		return requiresWasmTag(expr.X) || requiresWasmTag(expr.Y)
+	}
+	return false
+}
+
+// jsUses returns the uses of syscall/js in a type checked file
+func jsUses(module *moduleSources, pkg *packages.Package, file *ast.File) []JSUse {
+	isJSValue := func(expr ast.Expr) bool {
+		named, ok := types.Unalias(pkg.TypesInfo.TypeOf(expr)).(*types.Named)
+		return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "syscall/js" && named.Obj().Name() == "Value"
+	}
+	isJSFunc := func(expr ast.Expr, name string) bool {
+		selector, ok := expr.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		fn, ok := pkg.TypesInfo.Uses[selector.Sel].(*types.Func)
+		return ok && fn.Pkg() != nil && fn.Pkg().Path() == "syscall/js" && fn.Name() == name
+	}
+	isGlobal := func(expr ast.Expr) bool {
+		call, ok := expr.(*ast.CallExpr)
+		return ok && isJSFunc(call.Fun, "Global")
+	}
+
+	// Callbacks are released when Release is called on the variable holding them
+	released := make(map[types.Object]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Release" {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				if obj := pkg.TypesInfo.Uses[ident]; obj != nil {
+					released[obj] = true
+				}
+			}
+		}
+		return true
+	})
+	callbackVars := make(map[*ast.CallExpr]types.Object)
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				if call, ok := rhs.(*ast.CallExpr); ok && i < len(node.Lhs) {
+					if ident, ok := node.Lhs[i].(*ast.Ident); ok {
+						callbackVars[call] = pkg.TypesInfo.ObjectOf(ident)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range node.Values {
+				if call, ok := value.(*ast.CallExpr); ok && i < len(node.Names) {
+					callbackVars[call] = pkg.TypesInfo.ObjectOf(node.Names[i])
+				}
+			}
+		}
+		return true
+	})
+
+	var uses []JSUse
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		position := pkg.Fset.Position(call.Pos())
+		use := JSUse{
+			Function: enclosingFunctionName(file, call.Pos()),
+			File:     module.relPath(position.Filename),
+			Line:     position.Line,
+		}
+		if isJSFunc(call.Fun, "FuncOf") && len(call.Args) == 1 {
+			use.Kind, use.Name = "callback", types.ExprString(call.Args[0])
+			if _, ok := call.Args[0].(*ast.FuncLit); ok {
+				use.Name = "func literal"
+			}
+			if obj := callbackVars[call]; obj != nil {
+				use.Released = released[obj]
+			}
+			uses = append(uses, use)
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !isJSValue(selector.X) {
+			return true
+		}
+		name := ""
+		if len(call.Args) > 0 {
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+				name, _ = strconv.Unquote(lit.Value)
+			} else {
+				name = types.ExprString(call.Args[0])
+			}
+		}
+		switch selector.Sel.Name {
+		case "Get":
+			use.Kind = "property"
+			if isGlobal(selector.X) {
+				use.Kind = "global"
+			}
+		case "Set", "Delete":
+			use.Kind = "property"
+			if isGlobal(selector.X) && selector.Sel.Name == "Set" {
+				use.Kind = "export"
+			}
+		case "Call":
+			use.Kind = "call"
+		case "New":
+			use.Kind, name = "new", types.ExprString(selector.X)
+		case "Invoke":
+			use.Kind, name = "invoke", types.ExprString(selector.X)
+		default:
+			return true
+		}
+		use.Name = name
+		uses = append(uses, use)
+		return true
+	})
+	return uses
+}
+
+func (report *WasmReport) String() string {
+	if len(report.Files) == 0 {
+		return fmt.Sprintf("No WebAssembly files found in %s", report.Module)
+	}
+	target := fmt.Sprintf("GOOS=%s GOARCH=wasm", report.GOOS)
+	var b strings.Builder
+	if len(report.Errors) == 0 {
+		fmt.Fprintf(&b, "Build of %d packages with %s is green\n", len(report.Packages), target)
+	} else {
+		fmt.Fprintf(&b, "Build of %d packages with %s is red: %d errors\n", len(report.Packages), target, len(report.Errors))
+		for _, diagnostic := range report.Errors {
+			switch {
+			case diagnostic.File == "":
+				fmt.Fprintf(&b, "  %s: %s\n", diagnostic.Package, diagnostic.Message)
+			case diagnostic.Column > 0:
+				fmt.Fprintf(&b, "  %s:%d:%d: %s\n", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Message)
+			default:
+				fmt.Fprintf(&b, "  %s:%d: %s\n", diagnostic.File, diagnostic.Line, diagnostic.Message)
+			}
+		}
+	}
+
+	b.WriteString("\nWebAssembly files:\n")
+	for _, file := range report.Files {
+		fmt.Fprintf(&b, "  %s (%s)\n", file.File, file.Reason)
+	}
+	for _, kind := range jsUseKinds {
+		var lines []string
+		for _, use := range report.Uses {
+			if use.Kind != kind.kind {
+				continue
+			}
+			line := fmt.Sprintf("  %s in %s (%s:%d)", use.Name, use.Function, use.File, use.Line)
+			if use.Kind == "callback" && !use.Released {
+				line += ", never released"
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n%s:\n%s\n", kind.title, strings.Join(lines, "\n"))
+		}
+	}
+	return b.String()
+}
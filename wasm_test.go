@@ -0,0 +1,137 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWasm(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	files := map[string][]string{
+		"go.mod": {"module example.com/web", "", "go 1.22", ""},
+		"calc/calc.go": {
+			"package calc", // 1
+			"",
+			"func Add(a, b int) int { return a + b }", // 3
+			"",
+		},
+		"calc/calc_other.go": {
+			"//go:build !js", // 1
+			"",
+			"package calc", // 3
+			"",
+		},
+		"app/main.go": {
+			"//go:build js && wasm", // 1
+			"",
+			"package main", // 3
+			"",
+			"import (", // 5
+			"	\"syscall/js\"",
+			"",
+			"	\"example.com/web/calc\"",
+			")",
+			"", // 10
+			"func add(this js.Value, args []js.Value) any {",
+			"	return calc.Add(args[0].Int(), args[1].Int())", // 12
+			"}",
+			"",
+			"func main() {", // 15
+			"	js.Global().Set(\"add\", js.FuncOf(add))",
+			"	document := js.Global().Get(\"document\")",
+			"	button := document.Call(\"getElementById\", \"run\")",
+			"	onClick := js.FuncOf(func(js.Value, []js.Value) any { return nil })",
+			"	defer onClick.Release()", // 20
+			"	button.Set(\"onclick\", onClick)",
+			"	js.Global().Get(\"Date\").New()",
+			"	select {}",
+			"}",
+			"", // 25
+		},
+		"app/dom_js.go": {
+			"package main", // 1
+			"",
+			"func broken() int { return \"text\" }", // 3
+			"",
+		},
+	}
+	writeTestFiles(t, workspace, files)
+
+	t.Run("js", func(t *testing.T) {
+		t.Parallel()
+		report, err := CheckWasm(context.Background(), workspace, "")
+		if err != nil {
+			t.Fatalf("Failed to check WebAssembly code: %v", err)
+		}
+		if len(report.Errors) != 1 || report.Errors[0].File != "app/dom_js.go" || report.Errors[0].Line != 3 {
+			t.Errorf("Expected the error of dom_js.go, got %+v", report.Errors)
+		}
+		report.Errors = nil
+		expected := strings.Join([]string{
+			"Build of 1 packages with GOOS=js GOARCH=wasm is green",
+			"",
+			"WebAssembly files:",
+			"  app/dom_js.go (js file name)",
+			"  app/main.go (//go:build js && wasm)",
+			"",
+			"Exported to JavaScript:",
+			"  add in main (app/main.go:16)",
+			"",
+			"JavaScript globals:",
+			"  document in main (app/main.go:17)",
+			"  Date in main (app/main.go:22)",
+			"",
+			"Methods called:",
+			"  getElementById in main (app/main.go:18)",
+			"",
+			"Properties accessed:",
+			"  onclick in main (app/main.go:21)",
+			"",
+			"Constructors called:",
+			"  js.Global().Get(\"Date\") in main (app/main.go:22)",
+			"",
+			"Callbacks:",
+			"  add in main (app/main.go:16), never released",
+			"  func literal in main (app/main.go:19)",
+			"",
+		}, "\n")
+		if report.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, report)
+		}
+	})
+
+	t.Run("wasip1", func(t *testing.T) {
+		t.Parallel()
+		report, err := CheckWasm(context.Background(), workspace, "wasip1")
+		if err != nil {
+			t.Fatalf("Failed to check WebAssembly code: %v", err)
+		}
+		// Neither file of the app package is built for wasip1
+		if !strings.HasPrefix(report.String(), "Build of 1 packages with GOOS=wasip1 GOARCH=wasm is red") {
+			t.Errorf("Unexpected report:\n%s", report)
+		}
+		if _, err := CheckWasm(context.Background(), workspace, "linux"); err == nil {
+			t.Error("Expected an error for a GOOS other than js and wasip1")
+		}
+	})
+
+	t.Run("no wasm files", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/none\n\ngo 1.22\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		report, err := CheckWasm(context.Background(), dir, "js")
+		if err != nil {
+			t.Fatalf("Failed to check WebAssembly code: %v", err)
+		}
+		if report.String() != "No WebAssembly files found in example.com/none" {
+			t.Errorf("Unexpected report:\n%s", report)
+		}
+	})
+}
@@ -0,0 +1,277 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	worktreeDiffToolName    = "worktree_diff"
+	worktreeDiscardToolName = "worktree_discard"
+)
+
+// WorktreeOptions configures the git worktrees created per session
+type WorktreeOptions struct {
+	// BaseRef is the branch or commit the worktrees are created from, HEAD of the repository by default
+	BaseRef string
+	// Dir is the directory the worktrees are created in, the system temp directory by default
+	Dir string
+}
+
+// WithWorktrees gives every session a dedicated git worktree per repository. The first
+// mutating tool call of a session creates the worktree, and from then on all tool calls
+// of the session referring to the repository operate on the worktree instead, with paths
+// in results referring to the repository. The worktree_diff and worktree_discard tools
+// show the changes of the session against the base and remove the worktree.
+// Worktrees are removed when their session ends.
+func WithWorktrees(options WorktreeOptions) Option {
+	return func(o *serverOptions) {
+		o.worktrees = &options
+	}
+}
+
+// sessionWorktree is a git worktree of a repository owned by a session
+type sessionWorktree struct {
+	dir        string
+	repoRoot   string
+	baseRef    string
+	baseCommit string
+}
+
+// worktreeManager creates and removes the worktrees of sessions
+type worktreeManager struct {
+	mu      sync.Mutex
+	options WorktreeOptions
+	// worktrees maps session IDs to repository roots to worktrees
+	worktrees map[string]map[string]*sessionWorktree
+}
+
+func newWorktreeManager(options WorktreeOptions) *worktreeManager {
+	return &worktreeManager{
+		options:   options,
+		worktrees: make(map[string]map[string]*sessionWorktree),
+	}
+}
+
+func (manager *worktreeManager) get(sessionID string, repoRoot string) (*sessionWorktree, bool) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	worktree, ok := manager.worktrees[sessionID][repoRoot]
+	return worktree, ok
+}
+
+// getOrCreate returns the worktree of the session for the repository, creating it if needed
+func (manager *worktreeManager) getOrCreate(
+	ctx context.Context,
+	sessionID string,
+	repoRoot string,
+) (*sessionWorktree, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if worktree, ok := manager.worktrees[sessionID][repoRoot]; ok {
+		return worktree, nil
+	}
+
+	baseRef := manager.options.BaseRef
+	if baseRef == "" {
+		baseRef = "HEAD"
+	}
+	baseCommit, err := runGit(ctx, repoRoot, "rev-parse", "--verify", baseRef+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("base %s of the worktree not found in %s: %w", baseRef, repoRoot, err)
+	}
+	baseCommit = strings.TrimSpace(baseCommit)
+
+	dir, err := os.MkdirTemp(manager.options.Dir, "go-mcp-tools-worktree-")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := runGit(ctx, repoRoot, "worktree", "add", "--detach", dir, baseCommit); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	worktree := &sessionWorktree{dir: dir, repoRoot: repoRoot, baseRef: baseRef, baseCommit: baseCommit}
+	if manager.worktrees[sessionID] == nil {
+		manager.worktrees[sessionID] = make(map[string]*sessionWorktree)
+	}
+	manager.worktrees[sessionID][repoRoot] = worktree
+	return worktree, nil
+}
+
+// remove removes the worktree of the session for the repository
+func (manager *worktreeManager) remove(ctx context.Context, sessionID string, repoRoot string) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	worktree, ok := manager.worktrees[sessionID][repoRoot]
+	if !ok {
+		return nil
+	}
+	if _, err := runGit(ctx, repoRoot, "worktree", "remove", "--force", worktree.dir); err != nil {
+		return err
+	}
+	delete(manager.worktrees[sessionID], repoRoot)
+	return nil
+}
+
+// removeSession removes all worktrees of the session
+func (manager *worktreeManager) removeSession(ctx context.Context, sessionID string) {
+	manager.mu.Lock()
+	repoRoots := make([]string, 0, len(manager.worktrees[sessionID]))
+	for repoRoot := range manager.worktrees[sessionID] {
+		repoRoots = append(repoRoots, repoRoot)
+	}
+	manager.mu.Unlock()
+
+	for _, repoRoot := range repoRoots {
+		// Worktrees that cannot be removed are left for `git worktree prune`
+		_ = manager.remove(ctx, sessionID, repoRoot)
+	}
+	manager.mu.Lock()
+	delete(manager.worktrees, sessionID)
+	manager.mu.Unlock()
+}
+
+// worktreeMiddleware redirects tool calls of sessions into their worktrees
+func worktreeMiddleware(manager *worktreeManager, costs map[string]ToolCost) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name := request.Params.Name
+			if name == worktreeDiffToolName || name == worktreeDiscardToolName {
+				return next(ctx, request)
+			}
+			arguments := request.GetArguments()
+			repoRoot, err := gitRepoRootOfArguments(ctx, arguments)
+			if err != nil {
+				return next(ctx, request)
+			}
+
+			sessionID := sessionIDFromContext(ctx)
+			worktree, ok := manager.get(sessionID, repoRoot)
+			if !ok {
+				// Read-only calls see the repository until the session modifies it
+				if !costs[name].Mutating || repositoryToolNames[name] {
+					return next(ctx, request)
+				}
+				worktree, err = manager.getOrCreate(ctx, sessionID, repoRoot)
+				if err != nil {
+					return toolErrorResult(fmt.Sprintf("Error creating the worktree of this session: %v", err)), nil
+				}
+			}
+
+			request.Params.Arguments = rewritePathArguments(arguments, repoRoot, worktree.dir)
+			result, err := next(ctx, request)
+			return rewriteResultPaths(result, worktree.dir, repoRoot), err
+		}
+	}
+}
+
+// addWorktreeTools adds the tools showing and discarding the changes of session worktrees
+func addWorktreeTools(mcpServer *server.MCPServer, manager *worktreeManager) {
+	// sessionWorktreeOf finds the worktree of the calling session for the workspace_dir argument
+	sessionWorktreeOf := func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*sessionWorktree, *mcp.CallToolResult, error) {
+		workspaceDir, err := request.RequireString("workspace_dir")
+		if err != nil {
+			return nil, nil, err
+		}
+		repoRoot, err := gitRepoRoot(ctx, workspaceDir)
+		if err != nil {
+			return nil, toolErrorResult(fmt.Sprintf("Error: %s is not inside a git repository: %v", workspaceDir, err)), nil
+		}
+		worktree, ok := manager.get(sessionIDFromContext(ctx), repoRoot)
+		if !ok {
+			return nil, toolErrorResult(fmt.Sprintf(
+				"Error: this session has no worktree for %s, it is created by the first tool call modifying files",
+				repoRoot,
+			)), nil
+		}
+		return worktree, nil, nil
+	}
+
+	// worktreeDiff returns the changes of the worktree against its base as a unified diff
+	worktreeDiff := func(ctx context.Context, worktree *sessionWorktree) (string, *mcp.CallToolResult) {
+		// Staging includes new files in the diff, the index of the worktree belongs to the session
+		if _, err := runGit(ctx, worktree.dir, "add", "-A"); err != nil {
+			return "", toolErrorResult(fmt.Sprintf("Error staging the worktree changes: %v", err))
+		}
+		diff, err := runGit(ctx, worktree.dir, "diff", "--cached", "--binary", worktree.baseCommit)
+		if err != nil {
+			return "", toolErrorResult(fmt.Sprintf("Error diffing the worktree: %v", err))
+		}
+		return diff, nil
+	}
+
+	handleDiff := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		worktree, errorResult, err := sessionWorktreeOf(ctx, request)
+		if worktree == nil {
+			return errorResult, err
+		}
+		diff, errorResult := worktreeDiff(ctx, worktree)
+		if errorResult != nil {
+			return errorResult, nil
+		}
+		if diff == "" {
+			return mcp.NewToolResultText(fmt.Sprintf(
+				"No changes in the worktree of this session against %s (%s).",
+				worktree.baseRef,
+				worktree.baseCommit,
+			)), nil
+		}
+		return mcp.NewToolResultText(diff), nil
+	}
+
+	handleDiscard := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		worktree, errorResult, err := sessionWorktreeOf(ctx, request)
+		if worktree == nil {
+			return errorResult, err
+		}
+		if request.GetString(outputArgumentName, "") == outputPatch {
+			// The worktree is kept, the patch holds the changes that would be discarded
+			diff, errorResult := worktreeDiff(ctx, worktree)
+			if errorResult != nil {
+				return errorResult, nil
+			}
+			if diff == "" {
+				return mcp.NewToolResultText(fmt.Sprintf("%s made no changes, the patch is empty.", worktreeDiscardToolName)), nil
+			}
+			return mcp.NewToolResultText(diff), nil
+		}
+		if err := manager.remove(ctx, sessionIDFromContext(ctx), worktree.repoRoot); err != nil {
+			return toolErrorResult(fmt.Sprintf("Error removing the worktree: %v", err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"Discarded the worktree of this session for %s. The next tool call modifying files starts a new worktree from %s.",
+			worktree.repoRoot,
+			worktree.baseRef,
+		)), nil
+	}
+
+	mcpServer.AddTool(mcp.NewTool(worktreeDiffToolName,
+		mcp.WithDescription(
+			"Shows the changes made in the git worktree of this session as a unified diff against the base the worktree was created from. "+
+				"The paths are relative to the repository root and the diff can be applied to the repository with `git apply`.",
+		),
+		readOnlyToolAnnotation(),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Directory inside the git repository"),
+			mcp.Required(),
+		),
+	), handleDiff)
+	mcpServer.AddTool(mcp.NewTool(worktreeDiscardToolName,
+		mcp.WithDescription("Removes the git worktree of this session including all of its changes"),
+		editingToolAnnotation(true, true),
+		mcp.WithString("workspace_dir",
+			mcp.Description("Directory inside the git repository"),
+			mcp.Required(),
+		),
+	), handleDiscard)
+}
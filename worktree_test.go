@@ -0,0 +1,168 @@
+package go_mcp_tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testSession is a client session for calling tools as different sessions
+type testSession struct {
+	id string
+}
+
+func (session *testSession) Initialize()       {}
+func (session *testSession) Initialized() bool { return true }
+func (session *testSession) SessionID() string { return session.id }
+func (session *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return make(chan mcp.JSONRPCNotification, 1)
+}
+
+func TestWorktrees(t *testing.T) {
+	t.Parallel()
+
+	// Helper function to create a git repository with an unsorted map
+	createTestWorkspace := func(t testing.TB) string {
+		tempDir := writeTestModule(t, map[string][]string{
+			"go.mod": {"module testmodule", "", "go 1.21", ""},
+			"main.go": {
+				"package testpkg", // 1
+				"",                // 2
+				"var Values = map[string]int{\"b\": 2, \"a\": 1}", // 3
+				"", // 4
+			},
+		})
+		runTestGit(t, tempDir, "init", "--quiet")
+		runTestGit(t, tempDir, "add", "-A")
+		runTestGit(t, tempDir, "commit", "--quiet", "-m", "Initial commit")
+		return tempDir
+	}
+
+	// Helper function to call a tool as a session and return its result
+	callTool := func(
+		t testing.TB,
+		mcpServer *server.MCPServer,
+		session server.ClientSession,
+		name string,
+		arguments map[string]any,
+	) mcp.CallToolResult {
+		encoded, err := json.Marshal(map[string]any{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      1,
+			"method":  string(mcp.MethodToolsCall),
+			"params":  map[string]any{"name": name, "arguments": arguments},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := mcpServer.WithContext(context.Background(), session)
+		response, ok := mcpServer.HandleMessage(ctx, encoded).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("Expected a tool result for %s", name)
+		}
+		return response.Result.(mcp.CallToolResult)
+	}
+
+	t.Run("sessions change their own worktree", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mainFile := filepath.Join(workspace, "main.go")
+		mcpServer := NewMCPServer(WithWorktrees(WorktreeOptions{Dir: t.TempDir()}))
+		first := &testSession{id: "first"}
+		second := &testSession{id: "second"}
+		if err := mcpServer.RegisterSession(context.Background(), first); err != nil {
+			t.Fatal(err)
+		}
+
+		result := callTool(t, mcpServer, first, sortToolName, map[string]any{"file_path": mainFile})
+		text := toolResultText(&result)
+		if result.IsError || !strings.Contains(text, mainFile) {
+			t.Fatalf("Expected sort to succeed with repository paths, got: %s", text)
+		}
+		content, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), `{"b": 2, "a": 1}`) {
+			t.Errorf("Expected the repository to be untouched, got:\n%s", content)
+		}
+
+		// Later calls of the session see its worktree, other sessions the repository
+		inspectArguments := map[string]any{
+			"path":               mainFile + ":Values",
+			"workspace_dir":      workspace,
+			"include_references": false,
+			"include_scope":      false,
+		}
+		result = callTool(t, mcpServer, first, inspectToolName, inspectArguments)
+		if text := toolResultText(&result); !strings.Contains(text, `{"a": 1, "b": 2}`) {
+			t.Errorf("Expected the session to see its sorted worktree, got: %s", text)
+		}
+		result = callTool(t, mcpServer, second, inspectToolName, inspectArguments)
+		if text := toolResultText(&result); !strings.Contains(text, `{"b": 2, "a": 1}`) {
+			t.Errorf("Expected other sessions to see the repository, got: %s", text)
+		}
+
+		result = callTool(t, mcpServer, first, worktreeDiffToolName, map[string]any{"workspace_dir": workspace})
+		diff := toolResultText(&result)
+		if result.IsError || !strings.Contains(diff, "+var Values = map[string]int{\"a\": 1, \"b\": 2}") {
+			t.Fatalf("Expected the sort in the diff, got: %s", diff)
+		}
+		cmd := exec.Command("git", "apply", "--check", "-")
+		cmd.Dir = workspace
+		cmd.Stdin = strings.NewReader(diff)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Expected the diff to apply to the repository: %v\n%s", err, output)
+		}
+
+		// Ending the session removes its worktree
+		mcpServer.UnregisterSession(context.Background(), first.id)
+		if list := runTestGit(t, workspace, "worktree", "list"); strings.Count(list, "\n") != 1 {
+			t.Errorf("Expected only the main worktree after the session ended, got:\n%s", list)
+		}
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		t.Parallel()
+		workspace := createTestWorkspace(t)
+		mcpServer := NewMCPServer(WithWorktrees(WorktreeOptions{Dir: t.TempDir()}))
+		session := &testSession{id: "session"}
+
+		result := callTool(t, mcpServer, session, sortToolName, map[string]any{
+			"file_path": filepath.Join(workspace, "main.go"),
+		})
+		if result.IsError {
+			t.Fatalf("Expected sort to succeed, got: %s", toolResultText(&result))
+		}
+		result = callTool(t, mcpServer, session, worktreeDiscardToolName, map[string]any{
+			"workspace_dir": workspace,
+			"output":        "patch",
+		})
+		if text := toolResultText(&result); result.IsError || !strings.Contains(text, "+var Values = map[string]int{\"a\": 1, \"b\": 2}") {
+			t.Fatalf("Expected the changes to discard in the patch, got: %s", text)
+		}
+		if list := runTestGit(t, workspace, "worktree", "list"); strings.Count(list, "\n") != 2 {
+			t.Errorf("Expected the worktree to be kept with patch output, got:\n%s", list)
+		}
+
+		result = callTool(t, mcpServer, session, worktreeDiscardToolName, map[string]any{"workspace_dir": workspace})
+		if result.IsError || !strings.Contains(toolResultText(&result), "Discarded the worktree") {
+			t.Fatalf("Expected the worktree to be discarded, got: %s", toolResultText(&result))
+		}
+		if list := runTestGit(t, workspace, "worktree", "list"); strings.Count(list, "\n") != 1 {
+			t.Errorf("Expected only the main worktree, got:\n%s", list)
+		}
+
+		result = callTool(t, mcpServer, session, worktreeDiffToolName, map[string]any{"workspace_dir": workspace})
+		if !result.IsError || !strings.Contains(toolResultText(&result), "has no worktree") {
+			t.Errorf("Expected no worktree error, got: %s", toolResultText(&result))
+		}
+	})
+}